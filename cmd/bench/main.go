@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+)
+
+// bench reads the output of go test -bench -benchmem and compares it with a stored baseline, a benchmark slower or
+// allocating more than the threshold exits with status 1 so it can gate a release. With -count the median of the runs
+// is compared.
+// Run with: go test -run '^$' -bench . -benchmem ./internal/... > new.txt && go run ./cmd/bench [-baseline file] [-out file] [-threshold 15] new.txt
+func main() {
+	baselinePath := flag.String("baseline", "", "compare the results with this baseline")
+	out := flag.String("out", "", "write the results to this file, e.g. to record a new baseline")
+	threshold := flag.Float64("threshold", 15, "percent slower, or more allocations, than the baseline that counts as a regression")
+	flag.Parse()
+
+	var input io.Reader = os.Stdin
+	if flag.NArg() > 0 {
+		file, err := os.Open(flag.Arg(0))
+		if err != nil {
+			log.Fatalf("Failed to open benchmark output: %v", err)
+		}
+		defer file.Close()
+		input = file
+	}
+
+	results, err := ParseResults(input)
+	if err != nil {
+		log.Fatalf("Failed to read benchmark output: %v", err)
+	}
+	if len(results.Benchmarks) == 0 {
+		log.Fatal("No benchmark results in the output")
+	}
+
+	if *out != "" {
+		if err := results.Save(*out); err != nil {
+			log.Fatalf("Failed to save results: %v", err)
+		}
+		fmt.Printf("%d benchmarks written to %s\n", len(results.Benchmarks), *out)
+	}
+
+	if *baselinePath != "" {
+		baseline, err := LoadResults(*baselinePath)
+		if err != nil {
+			log.Fatalf("Failed to load baseline, record one with make bench/baseline: %v", err)
+		}
+		if regressions := Compare(os.Stdout, baseline, results, *threshold); regressions > 0 {
+			fmt.Printf("%d benchmarks regressed more than %.0f%%\n", regressions, *threshold)
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Result struct {
+	NsPerOp     float64 `json:"ns_per_op"`
+	BytesPerOp  float64 `json:"bytes_per_op"`
+	AllocsPerOp float64 `json:"allocs_per_op"`
+}
+
+// Results is the file a baseline is stored in, numbers only compare on the same machine and Go version
+type Results struct {
+	RecordedAt string            `json:"recorded_at"`
+	GoVersion  string            `json:"go_version"`
+	Platform   string            `json:"platform"`
+	Benchmarks map[string]Result `json:"benchmarks"`
+}
+
+// procsSuffix is the GOMAXPROCS go test appends to a benchmark name, e.g. -8
+var procsSuffix = regexp.MustCompile(`-\d+$`)
+
+// ParseResults reads the output of go test -bench -benchmem. A benchmark is named after its package without the
+// module, e.g. internal/core/usecase/swipes.BenchmarkExecuteSwipes, and keeps the median of its runs
+func ParseResults(r io.Reader) (*Results, error) {
+	runs := map[string][]Result{}
+	pkg := ""
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "pkg:" {
+			_, pkg, _ = strings.Cut(fields[1], "/")
+			continue
+		}
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+
+		var result Result
+		// The name and the iterations come first, then pairs of value and unit
+		for i := 2; i+1 < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("could not read %q: %v", scanner.Text(), err)
+			}
+			switch fields[i+1] {
+			case "ns/op":
+				result.NsPerOp = value
+			case "B/op":
+				result.BytesPerOp = value
+			case "allocs/op":
+				result.AllocsPerOp = value
+			}
+		}
+		name := procsSuffix.ReplaceAllString(fields[0], "")
+		if pkg != "" {
+			name = pkg + "." + name
+		}
+		runs[name] = append(runs[name], result)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	results := &Results{
+		RecordedAt: time.Now().Format(time.RFC3339),
+		GoVersion:  runtime.Version(),
+		Platform:   runtime.GOOS + "/" + runtime.GOARCH,
+		Benchmarks: map[string]Result{},
+	}
+	for name, samples := range runs {
+		results.Benchmarks[name] = Result{
+			NsPerOp:     median(samples, func(r Result) float64 { return r.NsPerOp }),
+			BytesPerOp:  median(samples, func(r Result) float64 { return r.BytesPerOp }),
+			AllocsPerOp: median(samples, func(r Result) float64 { return r.AllocsPerOp }),
+		}
+	}
+	return results, nil
+}
+
+func (r *Results) Save(path string) error {
+	content, err := json.MarshalIndent(r, "", "    ")
+	if err != nil {
+		return fmt.Errorf("could not encode results: %v", err)
+	}
+	return os.WriteFile(path, append(content, '\n'), 0o644)
+}
+
+func LoadResults(path string) (*Results, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var results Results
+	if err := json.Unmarshal(content, &results); err != nil {
+		return nil, fmt.Errorf("could not decode %s: %v", path, err)
+	}
+	return &results, nil
+}
+
+// Compare writes a line per benchmark and returns how many are slower or allocate more than threshold percent over
+// the baseline. A benchmark missing from the baseline is new and never a regression
+func Compare(w io.Writer, baseline *Results, current *Results, threshold float64) int {
+	if baseline.GoVersion != current.GoVersion || baseline.Platform != current.Platform {
+		fmt.Fprintf(w, "Baseline was recorded with %s on %s, now %s on %s\n",
+			baseline.GoVersion, baseline.Platform, current.GoVersion, current.Platform)
+	}
+
+	names := make([]string, 0, len(current.Benchmarks))
+	width := 0
+	for name := range current.Benchmarks {
+		names = append(names, name)
+		width = max(width, len(name))
+	}
+	sort.Strings(names)
+
+	regressions := 0
+	for _, name := range names {
+		result := current.Benchmarks[name]
+		base, ok := baseline.Benchmarks[name]
+		if !ok {
+			fmt.Fprintf(w, "%-*s %14.0f ns/op %10.0f allocs/op  new\n", width, name, result.NsPerOp, result.AllocsPerOp)
+			continue
+		}
+
+		timeDelta := percentChange(base.NsPerOp, result.NsPerOp)
+		allocsDelta := percentChange(base.AllocsPerOp, result.AllocsPerOp)
+		status := "ok"
+		if timeDelta > threshold || allocsDelta > threshold {
+			status = "REGRESSION"
+			regressions++
+		}
+		fmt.Fprintf(w, "%-*s %14.0f ns/op %+7.1f%% %10.0f allocs/op %+7.1f%%  %s\n",
+			width, name, result.NsPerOp, timeDelta, result.AllocsPerOp, allocsDelta, status)
+	}
+	return regressions
+}
+
+func percentChange(base float64, current float64) float64 {
+	if base == 0 {
+		if current == 0 {
+			return 0
+		}
+		return 100
+	}
+	return (current - base) * 100 / base
+}
+
+func median(samples []Result, value func(Result) float64) float64 {
+	values := make([]float64, 0, len(samples))
+	for _, sample := range samples {
+		values = append(values, value(sample))
+	}
+	sort.Float64s(values)
+	middle := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[middle-1] + values[middle]) / 2
+	}
+	return values[middle]
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/config"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/accounts"
+	usersentity "godating-dealls/internal/core/entities/users"
+	dailyquotausecase "godating-dealls/internal/core/usecase/daily_quotas"
+	"godating-dealls/internal/domain"
+	"log"
+	"os"
+	"strings"
+)
+
+// devCommand runs the server for local development, see InitializeDevEnvironment
+const devCommand = "dev"
+
+// devPassword is the password of every demo account
+const devPassword = "Demo1234"
+
+// devEnvironment is what the dev command runs with unless the shell sets it, the .env file does not override it.
+// The database file and the JWT secret of .env outlive a restart, so a file watcher rebuilding the server keeps
+// the data and the tokens already issued
+var devEnvironment = []struct {
+	key   string
+	value string
+}{
+	{"ENV", "development"},
+	{"DB_DRIVER", "sqlite"},
+	{"DB_SQLITE_PATH", "godating-dev.db"},
+	{"REDIS_DRIVER", "memory"},
+	{"MIGRATE_ON_STARTUP", "true"},
+	{"ADMIN_API_KEY", "dev-admin-key"},
+	{"ANTI_ENUMERATION", "false"},
+	{"PII_SCRUBBING", "false"},
+	{"AUTH_RATE_LIMIT_PER_MINUTE", "1000"},
+	{"SWIPE_RATE_LIMIT_PER_MINUTE", "1000"},
+	// The quotas of the day are created at midnight UTC instead of a day after startup
+	{"CRON_JOB_DAILY_QUOTA", "5 0 * * *"},
+	// A restart does not wait for the long polls and websockets, nor downloads the email domain list again
+	{"SHUTDOWN_TIMEOUT_SECONDS", "1"},
+	{"EMAIL_DOMAIN_LIST_URL", ""},
+}
+
+// devUsers are the demo accounts, close to each other so they show up in each other's discovery
+var devUsers = []struct {
+	username    string
+	fullName    string
+	gender      string
+	dateOfBirth string
+	bio         string
+	latitude    float64
+	longitude   float64
+}{
+	{"alice", "Alice Demo", "P", "1996-04-12", "Coffee, climbing and long walks", -6.2000, 106.8167},
+	{"bob", "Bob Demo", "L", "1994-09-03", "Weekend cook and bad guitarist", -6.2088, 106.8456},
+	{"carol", "Carol Demo", "P", "1998-01-25", "Museums and street food", -6.1751, 106.8650},
+	{"dave", "Dave Demo", "L", "1992-11-30", "Runner, reader, dog person", -6.2297, 106.8295},
+}
+
+// IsDevCommand tells whether the server was started with `go run ./cmd dev`, any other argument is refused
+func IsDevCommand() bool {
+	if len(os.Args) < 2 {
+		return false
+	}
+	if os.Args[1] != devCommand {
+		log.Fatalf("Unknown command %q, run without arguments or with %q", os.Args[1], devCommand)
+	}
+	return true
+}
+
+// InitializeDevEnvironment selects the sqlite database and the in-process redis before the config is loaded
+func InitializeDevEnvironment() {
+	for _, variable := range devEnvironment {
+		if _, ok := os.LookupEnv(variable.key); ok {
+			continue
+		}
+		if err := os.Setenv(variable.key, variable.value); err != nil {
+			log.Fatalf("Failed to set %s: %v", variable.key, err)
+		}
+	}
+}
+
+// InitializeDevData creates the demo accounts missing from the database, the existing ones keep their data
+func InitializeDevData(ctx context.Context, DB *sql.DB, accountEntity accounts.AccountEntity, userEntity usersentity.UserEntity, dailyQuotas dailyquotausecase.InputDailyQuotaBoundary) {
+	created := 0
+	fn := func(tx *sql.Tx) error {
+		for _, demo := range devUsers {
+			email := demo.username + "@demo.godating.local"
+			err := accountEntity.CheckAccountAvailableEntities(ctx, tx, email, demo.username)
+			if errors.Is(err, accounts.ErrAccountExists) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			username, fullName := demo.username, demo.fullName
+			account, err := accountEntity.SaveAccountEntities(ctx, tx, domain.SignUpAccountDto{Username: username, Password: devPassword, Email: email})
+			if err != nil {
+				return fmt.Errorf("could not create demo account %s: %v", demo.username, err)
+			}
+			if err := userEntity.SaveUserEntities(ctx, tx, domain.UserDto{AccountID: account.AccountId, FullName: &fullName}); err != nil {
+				return fmt.Errorf("could not create demo user %s: %v", demo.username, err)
+			}
+			user, err := userEntity.FindUserDetailEntity(ctx, tx, account.AccountId)
+			if err != nil {
+				return err
+			}
+
+			gender, address, bio, dateOfBirth := demo.gender, "Jakarta", demo.bio, demo.dateOfBirth
+			_, err = userEntity.UpdateUserEntities(ctx, tx, domain.PatchUser{UserID: user.UserID, FullName: &fullName, Gender: &gender, Address: &address, Bio: &bio, DateOfBirth: &dateOfBirth})
+			if err != nil {
+				return fmt.Errorf("could not fill demo profile %s: %v", demo.username, err)
+			}
+			latitude, longitude := demo.latitude, demo.longitude
+			if err := userEntity.UpdateUserLocationEntity(ctx, tx, account.AccountId, domain.UserLocationDto{Latitude: &latitude, Longitude: &longitude}); err != nil {
+				return fmt.Errorf("could not set demo location %s: %v", demo.username, err)
+			}
+			log.Printf("Created demo account %s", demo.username)
+			created++
+		}
+		return nil
+	}
+	if err := common.WithExecuteTransactionalManager(ctx, DB, fn); err != nil {
+		log.Fatalf("Failed to seed demo accounts: %v", err)
+	}
+
+	// The daily quota job adds a row on every run, it only runs here for the new accounts so a restart does not add
+	// another quota of today. The next days come from the job at midnight or its admin trigger
+	if created == 0 {
+		return
+	}
+	if err := dailyQuotas.ExecuteAutoUpdateDailyQuotaUsecase(ctx); err != nil {
+		log.Fatalf("Failed to reset daily quotas: %v", err)
+	}
+}
+
+// PrintDevCredentials prints where the server listens and how to log in as the demo accounts
+func PrintDevCredentials(cfg config.Config) {
+	usernames := make([]string, 0, len(devUsers))
+	for _, demo := range devUsers {
+		usernames = append(usernames, demo.username)
+	}
+	port := cfg.ServerPort
+
+	fmt.Printf(`
+GoDating development server on http://localhost:%d/godating-dealls/api/v1
+  database   %s (delete it to start over)
+  accounts   %s, password %s
+  login      curl -X POST localhost:%d/godating-dealls/api/v1/authenticate/login -d '{"username":"%s","password":"%s"}'
+  admin key  X-Admin-Key: %s
+  quotas     curl -X POST localhost:%d/godating-dealls/api/v1/admin/jobs/daily_quota_reset/trigger -H 'X-Admin-Key: %s'
+
+`, port, cfg.DB.SQLitePath, strings.Join(usernames, ", "), devPassword, port, usernames[0], devPassword,
+		cfg.Security.AdminAPIKey, port, cfg.Security.AdminAPIKey)
+}
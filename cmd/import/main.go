@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"godating-dealls/config"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/accounts"
+	"godating-dealls/internal/core/entities/outbox"
+	"godating-dealls/internal/core/entities/users"
+	"godating-dealls/internal/core/usecase/account_imports"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/repo"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// import creates the accounts of a dump from another platform in the configured database, like
+// POST /admin/accounts/import without its size limit. It exits 1 when rows were invalid or failed.
+// Run with: go run ./cmd/import -file accounts.csv [-format csv|json] [-dry-run]
+func main() {
+	file := flag.String("file", "", "the dump to import")
+	format := flag.String("format", "", "csv or json, by default the extension of the file")
+	dryRun := flag.Bool("dry-run", false, "check the rows against the database without creating any account")
+	flag.Parse()
+
+	if *file == "" {
+		log.Fatal("Missing -file")
+	}
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*file)), ".")
+	}
+
+	dump, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("Failed to open the dump: %v", err)
+	}
+	rows, err := account_imports.ReadAccountImport(dump, *format)
+	_ = dump.Close()
+	if err != nil {
+		log.Fatalf("Failed to read the dump: %v", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	ctx := context.Background()
+	db := config.CreateDBConnection(ctx, cfg.DB)
+	defer config.CloseDBConnection()
+
+	val := common.NewValidator()
+	importUsecase := account_imports.NewAccountImportUsecase(db,
+		accounts.NewAccountsEntityImpl(repo.NewAccountsRepositoryImpl(), val, common.NewEmailDomainPolicy(cfg.Policies.EmailDomains)),
+		users.NewUserEntityImpl(repo.NewUsersRepositoryImpl(), val),
+		outbox.NewOutboxEntityImpl(repo.NewOutboxEventsRepositoryImpl()))
+
+	progress := func(response domain.AccountImportResponse) {
+		fmt.Fprintf(os.Stderr, "  %d/%d rows, %d created\n", response.Processed, response.Total, response.Created)
+	}
+	report := &importReport{}
+	if err := importUsecase.ExecuteImportAccounts(ctx, domain.AccountImportRequest{Rows: rows, DryRun: *dryRun}, progress, report); err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+
+	res := report.response
+	for _, rejected := range res.Rejected {
+		fmt.Printf("  row %-6d %-9s %-30s %s\n", rejected.Row, rejected.Status, rejected.Email, rejected.Reason)
+	}
+	verb := "Created"
+	if res.DryRun {
+		verb = "Would create"
+	}
+	fmt.Printf("%s %d of %d accounts (%d must reset their password), %d existing, %d duplicates, %d invalid, %d failed\n",
+		verb, res.Created, res.Total, res.ResetRequired, res.Existing, res.Duplicates, res.Invalid, res.Failed)
+	if res.Invalid > 0 || res.Failed > 0 {
+		config.CloseDBConnection()
+		os.Exit(1)
+	}
+}
+
+// importReport stands in for the presenter, the import reports once at the end
+type importReport struct {
+	response domain.AccountImportResponse
+}
+
+func (i *importReport) AccountImportResponse(response domain.AccountImportResponse, err error) {
+	i.response = response
+}
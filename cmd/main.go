@@ -3,51 +3,150 @@ package main
 import (
 	"context"
 	"database/sql"
-	"github.com/go-playground/validator/v10"
-	"github.com/robfig/cron/v3"
+	"errors"
 	"godating-dealls/config"
 	"godating-dealls/internal/common"
+	accountexportsentity "godating-dealls/internal/core/entities/account_exports"
 	"godating-dealls/internal/core/entities/accounts"
+	adminentity "godating-dealls/internal/core/entities/admin"
+	analyticsentity "godating-dealls/internal/core/entities/analytics"
+	blocksentity "godating-dealls/internal/core/entities/blocks"
+	candidatesentity "godating-dealls/internal/core/entities/candidates"
+	clientconfigsentity "godating-dealls/internal/core/entities/client_configs"
+	contactsentity "godating-dealls/internal/core/entities/contacts"
 	dailyquotaentity "godating-dealls/internal/core/entities/daily_quotas"
+	dormancyentity "godating-dealls/internal/core/entities/dormancy"
+	duosentity "godating-dealls/internal/core/entities/duos"
+	entitlementsentity "godating-dealls/internal/core/entities/entitlements"
+	eventsentity "godating-dealls/internal/core/entities/events"
+	hiddenaccountsentity "godating-dealls/internal/core/entities/hidden_accounts"
+	integrationsentity "godating-dealls/internal/core/entities/integrations"
 	loginhistoryentity "godating-dealls/internal/core/entities/login_histories"
+	matchfeaturesentity "godating-dealls/internal/core/entities/match_features"
+	matchesentity "godating-dealls/internal/core/entities/matches"
+	messagesentity "godating-dealls/internal/core/entities/messages"
+	networkrulesentity "godating-dealls/internal/core/entities/network_rules"
+	notesentity "godating-dealls/internal/core/entities/notes"
+	notificationsentity "godating-dealls/internal/core/entities/notifications"
+	outboxentity "godating-dealls/internal/core/entities/outbox"
 	"godating-dealls/internal/core/entities/packages"
+	passkeysentity "godating-dealls/internal/core/entities/passkeys"
+	photosentity "godating-dealls/internal/core/entities/photos"
+	profilechangesentity "godating-dealls/internal/core/entities/profile_changes"
+	profilestrengthentity "godating-dealls/internal/core/entities/profile_strength"
+	recoveryentity "godating-dealls/internal/core/entities/recovery"
+	rewardsentity "godating-dealls/internal/core/entities/rewards"
+	safetyentity "godating-dealls/internal/core/entities/safety"
 	"godating-dealls/internal/core/entities/selection_histories"
+	sharelinksentity "godating-dealls/internal/core/entities/share_links"
+	statusmessagesentity "godating-dealls/internal/core/entities/status_messages"
 	"godating-dealls/internal/core/entities/swipes"
 	"godating-dealls/internal/core/entities/task_history"
 	usersentity "godating-dealls/internal/core/entities/users"
 	"godating-dealls/internal/core/entities/views"
+	accountimportsusecase "godating-dealls/internal/core/usecase/account_imports"
 	accountsusecase "godating-dealls/internal/core/usecase/accounts"
+	adminusecase "godating-dealls/internal/core/usecase/admin"
+	analyticsusecase "godating-dealls/internal/core/usecase/analytics"
 	accountusecase "godating-dealls/internal/core/usecase/auths"
+	backupsusecase "godating-dealls/internal/core/usecase/backups"
+	blocksusecase "godating-dealls/internal/core/usecase/blocks"
+	candidatesusecase "godating-dealls/internal/core/usecase/candidates"
+	clientconfigsusecase "godating-dealls/internal/core/usecase/client_configs"
+	contactsusecase "godating-dealls/internal/core/usecase/contacts"
 	dailyquotausecase "godating-dealls/internal/core/usecase/daily_quotas"
+	dormancyusecase "godating-dealls/internal/core/usecase/dormancy"
+	duosusecase "godating-dealls/internal/core/usecase/duos"
+	emaildomainsusecase "godating-dealls/internal/core/usecase/email_domains"
+	eventsusecase "godating-dealls/internal/core/usecase/events"
+	healthusecase "godating-dealls/internal/core/usecase/health"
+	hiddenaccountsusecase "godating-dealls/internal/core/usecase/hidden_accounts"
+	integrationsusecase "godating-dealls/internal/core/usecase/integrations"
+	likesusecase "godating-dealls/internal/core/usecase/likes"
+	loginhistoryusecase "godating-dealls/internal/core/usecase/login_histories"
+	matchfeaturesusecase "godating-dealls/internal/core/usecase/match_features"
+	matchesusecase "godating-dealls/internal/core/usecase/matches"
+	messagesusecase "godating-dealls/internal/core/usecase/messages"
+	networkrulesusecase "godating-dealls/internal/core/usecase/network_rules"
+	notesusecase "godating-dealls/internal/core/usecase/notes"
+	notificationsusecase "godating-dealls/internal/core/usecase/notifications"
+	outboxusecase "godating-dealls/internal/core/usecase/outbox"
 	packageusecase "godating-dealls/internal/core/usecase/packages"
+	photosusecase "godating-dealls/internal/core/usecase/photos"
+	profilechangesusecase "godating-dealls/internal/core/usecase/profile_changes"
+	profilestrengthusecase "godating-dealls/internal/core/usecase/profile_strength"
+	recoveryusecase "godating-dealls/internal/core/usecase/recovery"
+	rewardsusecase "godating-dealls/internal/core/usecase/rewards"
+	safetyusecase "godating-dealls/internal/core/usecase/safety"
+	sharelinksusecase "godating-dealls/internal/core/usecase/share_links"
+	statusmessagesusecase "godating-dealls/internal/core/usecase/status_messages"
 	swipeusecase "godating-dealls/internal/core/usecase/swipes"
 	"godating-dealls/internal/core/usecase/users"
 	"godating-dealls/internal/delivery/handler"
+	"godating-dealls/internal/infra/alerts"
+	analyticsevents "godating-dealls/internal/infra/analytics"
+	"godating-dealls/internal/infra/backups"
+	"godating-dealls/internal/infra/emaildomains"
+	"godating-dealls/internal/infra/eventbus"
+	"godating-dealls/internal/infra/geoip"
+	"godating-dealls/internal/infra/integrations"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"godating-dealls/internal/infra/mysql/migrations"
 	"godating-dealls/internal/infra/mysql/repo"
+	"godating-dealls/internal/infra/notifier"
+	"godating-dealls/internal/infra/oauth"
+	"godating-dealls/internal/infra/push"
+	"godating-dealls/internal/infra/realtime"
 	"godating-dealls/internal/infra/redisclient"
+	"godating-dealls/internal/infra/regions"
+	"godating-dealls/internal/infra/scheduler"
+	"godating-dealls/internal/infra/storage"
+	"godating-dealls/internal/infra/uptime"
+	"godating-dealls/internal/infra/watchdog"
 	"godating-dealls/router"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
+	"time"
 )
 
 func main() {
 	// Init context before run application
 	ctx := context.Background()
 
+	devMode := IsDevCommand()
+	if devMode {
+		InitializeDevEnvironment()
+	}
+
 	// Set up logging
 	// logs := InitializeLogger()
 	// defer logs.Close()
 
-	DB := InitializeDB(ctx)
-	defer config.CloseDBConnection()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	common.SetSecurityConfig(cfg.Security)
+	InitializePIIScrubbing()
+	common.SetFaultInjectionConfig(cfg.FaultInjection)
+	jsonwebtoken.SetSecret(cfg.JWTSecret)
+	if err := common.SetLogLevels(cfg.LogLevel, cfg.ModuleLogLevels); err != nil {
+		log.Fatalf("Failed to set log levels: %v", err)
+	}
+
+	DB := InitializeDB(ctx, cfg.DB)
+	InitializeMigrations(ctx, DB, cfg.MigrateOnStartup)
+	schemaGuard := InitializeSchemaGuard(ctx, DB)
 
-	RS := InitializeRedis(ctx)
+	RS := InitializeRedis(ctx, cfg.Redis)
 
-	// Initiate validator
-	val := validator.New()
+	// Initiate validator with the domain rule tags
+	val := common.NewValidator()
 
 	// Initiate repo
 	accountRepository := repo.NewAccountsRepositoryImpl()
@@ -60,9 +159,65 @@ func main() {
 	packageRepository := repo.NewPackagesRepositoryImpl()
 	purchaseRepository := repo.NewPurchasePackagesRepositoryImpl()
 	viewRepository := repo.NewViewAccountsRepositoryImpl()
+	noteRepository := repo.NewNotesRepositoryImpl()
+	contactExclusionRepository := repo.NewContactExclusionsRepositoryImpl()
+	hiddenAccountsRepository := repo.NewHiddenAccountsRepositoryImpl()
+	blocksRepository := repo.NewBlocksRepositoryImpl()
+	profileIntegrationRepository := repo.NewProfileIntegrationsRepositoryImpl()
+	profileImportRepository := repo.NewProfileImportsRepositoryImpl()
+	profileShareLinkRepository := repo.NewProfileShareLinksRepositoryImpl()
+	loginStreakRepository := repo.NewLoginStreaksRepositoryImpl()
+	adminRepository := repo.NewAdminRepositoryImpl()
+	quotaUsageRollupsRepository := repo.NewQuotaUsageRollupsRepositoryImpl()
+	accountDormancyRepository := repo.NewAccountDormancyRepositoryImpl()
+	passkeyCredentialsRepository := repo.NewPasskeyCredentialsRepositoryImpl()
+	recoveryRepository := repo.NewRecoveryRepositoryImpl()
+	matchFeaturesRepository := repo.NewMatchFeaturesRepositoryImpl()
+	clientConfigsRepository := repo.NewClientConfigsRepositoryImpl()
+	statusMessagesRepository := repo.NewStatusMessagesRepositoryImpl()
+	profileChangesRepository := repo.NewProfileChangesRepositoryImpl()
+	matchesRepository := repo.NewMatchesRepositoryImpl()
+	duosRepository := repo.NewDuosRepositoryImpl()
+	messagesRepository := repo.NewMessagesRepositoryImpl()
+	profilePhotosRepository := repo.NewProfilePhotosRepositoryImpl()
+	smartPhotosRepository := repo.NewSmartPhotosRepositoryImpl()
+	networkRulesRepository := repo.NewNetworkRulesRepositoryImpl()
+	eventsRepository := repo.NewEventsRepositoryImpl()
+	candidatesRepository := repo.NewCandidatesRepositoryImpl()
+	pushNotificationsRepository := repo.NewPushNotificationsRepositoryImpl()
+	notificationsRepository := repo.NewNotificationsRepositoryImpl()
+	accountExportsRepository := repo.NewAccountExportsRepositoryImpl()
+	outboxEventsRepository := repo.NewOutboxEventsRepositoryImpl()
+	safetySettingsRepository := repo.NewSafetySettingsRepositoryImpl()
+
+	// Third party providers for profile imports, a provider without credentials is left out
+	integrationProviders := integrations.NewRegistry(
+		integrations.NewSpotifyProvider(cfg.Services.Integrations.Spotify),
+		integrations.NewInstagramProvider(cfg.Services.Integrations.Instagram),
+	)
+
+	// Uploaded media goes to local disk, S3, MinIO or GCS, chosen by STORAGE_DRIVER
+	mediaStorage := storage.NewStorage(cfg.Services.Storage)
+	InitializeStorageCheck(ctx, mediaStorage)
+
+	// The status monitor probes the dependencies for the status page, mail and push are known by the outcome of the sends
+	statusMonitor := InitializeStatusMonitor(DB, mediaStorage, cfg.Services.Status)
+
+	// The watchdog samples goroutines, heap and the connection pools, it runs until the server shuts down
+	runtimeWatchdog := watchdog.New(cfg.Services.Watchdog, DB, config.RedisClient)
+
+	// Requests from listed countries and networks are blocked or flagged, off until GEOIP_PROVIDER is set
+	geoProvider := geoip.NewProvider(cfg.Services.GeoIP)
+	geoGuard := geoip.NewGuard(geoProvider, geoip.NewPolicy(cfg.Services.GeoIP))
+
+	// Accounts are homed in the region they first log in to, off until REGION is set
+	regionRegistry := regions.NewRegistry(cfg.Services.Regions, geoProvider)
+
+	// Sign up email domains, the remote list is refreshed by a background job
+	emailDomainPolicy := common.NewEmailDomainPolicy(cfg.Policies.EmailDomains)
 
 	// Entities represented of enterprise business rules for that self of entity
-	accountEntity := accounts.NewAccountsEntityImpl(accountRepository, val)
+	accountEntity := accounts.NewAccountsEntityImpl(accountRepository, val, emailDomainPolicy)
 	userEntity := usersentity.NewUserEntityImpl(userRepository, val)
 	loginHistoryEntity := loginhistoryentity.NewLoginHistoriesEntityImpl(val, loginHistoryRepository)
 	dailyQuotasEntity := dailyquotaentity.NewDailyQuotasEntityImpl(val, dailyQuotaRepository)
@@ -70,49 +225,244 @@ func main() {
 	taskHistoryEntity := task_history.NewTaskHistoryEntityImpl(taskHistoryRepository)
 	swipeEntity := swipes.NewSwipeEntityImpl(swipeRepository)
 	packageEntity := packages.NewPackageEntityImpl(packageRepository, purchaseRepository)
+	entitlementEntity := entitlementsentity.NewEntitlementEntityImpl(purchaseRepository, accountRepository, dailyQuotaRepository)
 	viewEntity := views.NewViewEntityImpl(viewRepository)
+	noteEntity := notesentity.NewNoteEntityImpl(noteRepository, val)
+	contactEntity := contactsentity.NewContactEntityImpl(contactExclusionRepository, val)
+	hiddenAccountEntity := hiddenaccountsentity.NewHiddenAccountEntityImpl(hiddenAccountsRepository, accountRepository)
+	blockEntity := blocksentity.NewBlockEntityImpl(blocksRepository, accountRepository)
+	duoEntity := duosentity.NewDuoEntityImpl(duosRepository, accountRepository)
+	integrationEntity := integrationsentity.NewIntegrationEntityImpl(profileIntegrationRepository, profileImportRepository)
+	shareLinkEntity := sharelinksentity.NewShareLinkEntityImpl(profileShareLinkRepository, val)
+	rewardEntity := rewardsentity.NewRewardEntityImpl(loginStreakRepository)
+	profileStrengthEntity := profilestrengthentity.NewProfileStrengthEntityImpl()
+	adminEntity := adminentity.NewAdminEntityImpl(adminRepository, val)
+	analyticsEntity := analyticsentity.NewAnalyticsEntityImpl(quotaUsageRollupsRepository)
+	dormancyEntity := dormancyentity.NewDormancyEntityImpl(accountDormancyRepository)
+	passkeyEntity := passkeysentity.NewPasskeyEntityImpl(passkeyCredentialsRepository, val)
+	recoveryEntity := recoveryentity.NewRecoveryEntityImpl(recoveryRepository)
+	matchFeatureEntity := matchfeaturesentity.NewMatchFeatureEntityImpl(matchFeaturesRepository, val)
+	clientConfigEntity := clientconfigsentity.NewClientConfigEntityImpl(clientConfigsRepository, val)
+	statusMessageEntity := statusmessagesentity.NewStatusMessageEntityImpl(statusMessagesRepository, val)
+	profileChangeEntity := profilechangesentity.NewProfileChangeEntityImpl(profileChangesRepository, val)
+	matchEntity := matchesentity.NewMatchEntityImpl(matchesRepository)
+	messageEntity := messagesentity.NewMessageEntityImpl(messagesRepository, val)
+	photoEntity := photosentity.NewPhotoEntityImpl(profilePhotosRepository, smartPhotosRepository)
+	networkRuleEntity := networkrulesentity.NewNetworkRuleEntityImpl(networkRulesRepository, val)
+	eventEntity := eventsentity.NewEventEntityImpl(eventsRepository, val)
+	candidateEntity := candidatesentity.NewCandidateEntityImpl(candidatesRepository, val)
+	notificationEntity := notificationsentity.NewNotificationEntityImpl(pushNotificationsRepository, notificationsRepository)
+	accountExportEntity := accountexportsentity.NewAccountExportEntityImpl(accountExportsRepository)
+	outboxEntity := outboxentity.NewOutboxEntityImpl(outboxEventsRepository)
+	safetyEntity := safetyentity.NewSafetyEntityImpl(safetySettingsRepository, cfg.Policies.Safety)
+
+	// Background jobs share one scheduler so they can be inspected from the admin api
+	jobScheduler := scheduler.New(ctx)
 
 	// Usecase
-	authenticateUsecase := accountusecase.NewAuthUsecase(DB, accountEntity, userEntity, RS, loginHistoryEntity)
-	dailyQuotasUsecase := dailyquotausecase.NewDailyQuotasUsecase(DB, dailyQuotasEntity, userEntity, accountEntity, packageEntity)
-	InitializeCronJobDailyQuota(ctx, dailyQuotasUsecase)
-	usersUsecase := users.NewUserUsecase(DB, userEntity, accountEntity, selectionHistoryEntity, taskHistoryEntity)
-	swipeUsecase := swipeusecase.NewSwipeUsecase(DB, swipeEntity, dailyQuotasEntity, accountEntity)
-	packageUsecase := packageusecase.NewPackageUsecase(DB, packageEntity, accountEntity, dailyQuotasEntity)
-	accountUsecase := accountsusecase.NewAccountsUsecase(DB, accountEntity, swipeEntity, userEntity, viewEntity)
+	accountNotifier := statusMonitor.TrackNotifier(notifier.NewNotifier(cfg.Services.Notifier))
+	analyticsPublisher := analyticsevents.NewPublisher(config.RedisClient, cfg.Services.Analytics)
+	authenticateUsecase := accountusecase.NewAuthUsecase(DB, accountEntity, userEntity, RS, loginHistoryEntity, rewardEntity, dormancyEntity, passkeyEntity, accountNotifier, regionRegistry, outboxEntity, analyticsPublisher,
+		cfg.Services.WebAuthn, oauth.NewRegistryFromConfig(cfg.Services.OAuth), cfg.Policies.Auth)
+	dailyQuotasUsecase := dailyquotausecase.NewDailyQuotasUsecase(DB, dailyQuotasEntity, userEntity, accountEntity, entitlementEntity, analyticsPublisher)
+	if devMode {
+		InitializeDevData(ctx, DB, accountEntity, userEntity, dailyQuotasUsecase)
+	}
+	InitializeCronJobDailyQuota(jobScheduler, cfg.Cron.DailyQuota, dailyQuotasUsecase)
+	emailDomainUsecase := emaildomainsusecase.NewEmailDomainUsecase(emaildomains.NewSource(cfg.Services.EmailDomainListURL), emailDomainPolicy)
+	InitializeEmailDomains(ctx, emailDomainUsecase)
+	InitializeCronJobEmailDomainRefresh(jobScheduler, cfg.Cron.EmailDomainRefresh, emailDomainUsecase)
+	usersUsecase := users.NewUserUsecase(DB, userEntity, accountEntity, selectionHistoryEntity, taskHistoryEntity, photoEntity, mediaStorage, cfg.Policies.Discovery, RS, analyticsPublisher)
+	InitializeCronJobSeenTodayCleanup(jobScheduler, cfg.Cron.SeenTodayCleanup, usersUsecase)
+	candidateUsecase := candidatesusecase.NewCandidateUsecase(DB, candidateEntity, photoEntity, mediaStorage, cfg.Policies.Candidates)
+	swipeUsecase := swipeusecase.NewSwipeUsecase(DB, swipeEntity, dailyQuotasEntity, accountEntity, matchEntity, entitlementEntity, photoEntity, blockEntity, notificationEntity, accountNotifier, cfg.Policies.SuperLike, outboxEntity, analyticsPublisher)
+	// Domain events leave the outbox for the bus chosen by OUTBOX_BUS, the relayer starts with the server
+	outboxUsecase := outboxusecase.NewOutboxUsecase(DB, outboxEntity, eventbus.NewBus(config.RedisClient, cfg.Services.EventBus), cfg.Policies.OutboxRelayInterval)
+	likeUsecase := likesusecase.NewLikeUsecase(DB, swipeEntity, userEntity, entitlementEntity, photoEntity, mediaStorage)
+	packageUsecase := packageusecase.NewPackageUsecase(DB, packageEntity, accountEntity, dailyQuotasEntity, entitlementEntity)
+	InitializeCronJobPremiumExpiry(jobScheduler, cfg.Cron.PremiumExpiry, packageUsecase)
+	accountUsecase := accountsusecase.NewAccountsUsecase(DB, accountEntity, swipeEntity, userEntity, viewEntity, photoEntity, mediaStorage, dormancyEntity, accountExportEntity, RS, cfg.Policies.Dormancy.DeletionGrace)
+	noteUsecase := notesusecase.NewNoteUsecase(DB, noteEntity, accountEntity)
+	contactUsecase := contactsusecase.NewContactUsecase(DB, contactEntity)
+	hiddenAccountUsecase := hiddenaccountsusecase.NewHiddenAccountUsecase(DB, hiddenAccountEntity)
+	blockUsecase := blocksusecase.NewBlockUsecase(DB, blockEntity)
+	integrationUsecase := integrationsusecase.NewIntegrationUsecase(DB, integrationEntity, RS, integrationProviders)
+	InitializeCronJobIntegrationRefresh(jobScheduler, cfg.Cron.IntegrationRefresh, integrationUsecase)
+	shareLinkUsecase := sharelinksusecase.NewShareLinkUsecase(DB, shareLinkEntity, userEntity, integrationEntity, photoEntity, mediaStorage, cfg.Policies.PublicProfileBaseURL)
+	rewardUsecase := rewardsusecase.NewRewardUsecase(DB, rewardEntity, dailyQuotasEntity, accountEntity)
+	clientConfigUsecase := clientconfigsusecase.NewClientConfigUsecase(DB, clientConfigEntity)
+	statusMessageUsecase := statusmessagesusecase.NewStatusMessageUsecase(DB, statusMessageEntity)
+	recoveryUsecase := recoveryusecase.NewRecoveryUsecase(DB, recoveryEntity, accountEntity, RS, cfg.Policies.Recovery)
+	profileStrengthUsecase := profilestrengthusecase.NewProfileStrengthUsecase(DB, profileStrengthEntity, userEntity, integrationEntity, photoEntity)
+	backupUsecase := backupsusecase.NewBackupUsecase(backups.NewVerifier(cfg.Services.Backups, DB), alerts.NewAlerter(cfg.Services.AlertWebhookURL))
+	InitializeCronJobBackupVerification(jobScheduler, cfg.Cron.BackupVerification, backupUsecase)
+	analyticsUsecase := analyticsusecase.NewAnalyticsUsecase(DB, analyticsEntity)
+	InitializeCronJobQuotaUsageRollup(jobScheduler, cfg.Cron.QuotaUsageRollup, analyticsUsecase)
+	dormancyUsecase := dormancyusecase.NewDormancyUsecase(DB, dormancyEntity, cfg.Policies.Dormancy, photoEntity, mediaStorage)
+	InitializeCronJobDormancy(jobScheduler, cfg.Cron.Dormancy, dormancyUsecase)
+	InitializeCronJobAccountDeletionPurge(jobScheduler, cfg.Cron.AccountDeletionPurge, dormancyUsecase)
+	loginHistoryUsecase := loginhistoryusecase.NewLoginHistoriesUsecase(DB, loginHistoryEntity, cfg.Policies.LoginHistoryRetentionDays)
+	InitializeCronJobLoginHistoryRetention(jobScheduler, cfg.Cron.LoginHistoryRetention, loginHistoryUsecase)
+	matchFeatureUsecase := matchfeaturesusecase.NewMatchFeatureUsecase(DB, matchFeatureEntity)
+	InitializeCronJobMatchFeatures(jobScheduler, cfg.Cron.MatchFeatures, matchFeatureUsecase)
+	adminUsecase := adminusecase.NewAdminUsecase(DB, adminEntity, accountEntity, userEntity, analyticsEntity, jobScheduler, geoGuard, runtimeWatchdog)
+	profileChangeUsecase := profilechangesusecase.NewProfileChangeUsecase(DB, profileChangeEntity, userEntity, accountEntity, adminEntity)
+	matchUsecase := matchesusecase.NewMatchUsecase(DB, matchEntity)
+	duoUsecase := duosusecase.NewDuoUsecase(DB, duoEntity, matchEntity)
+	messageUsecase := messagesusecase.NewMessageUsecase(DB, messageEntity, matchEntity, blockEntity, notificationEntity, safetyEntity, realtime.NewHub(), regions.NewReplicator(cfg.Services.Regions, regionRegistry))
+	eventUsecase := eventsusecase.NewEventUsecase(DB, eventEntity, matchEntity, blockEntity)
+	InitializeCronJobEventRoomCleanup(jobScheduler, cfg.Cron.EventRoomCleanup, eventUsecase)
+	photoUsecase := photosusecase.NewPhotoUsecase(DB, photoEntity, mediaStorage)
+	InitializeCronJobPhotoTrashPurge(jobScheduler, cfg.Cron.PhotoTrashPurge, photoUsecase)
+	networkACL := common.NewNetworkACL()
+	networkRuleUsecase := networkrulesusecase.NewNetworkRuleUsecase(DB, networkRuleEntity, networkACL)
+	InitializeNetworkRules(ctx, networkRuleUsecase)
+	InitializeCronJobNetworkRuleRefresh(jobScheduler, cfg.Cron.NetworkRuleRefresh, networkRuleUsecase)
+	notificationUsecase := notificationsusecase.NewNotificationUsecase(DB, notificationEntity, statusMonitor.TrackPushProviders(push.NewProviders(cfg.Services.Push)))
+	InitializeCronJobPushDelivery(jobScheduler, cfg.Cron.PushDelivery, notificationUsecase)
+	healthUsecase := healthusecase.NewHealthUsecase(schemaGuard, statusMonitor)
+	accountImportUsecase := accountimportsusecase.NewAccountImportUsecase(DB, accountEntity, userEntity, outboxEntity)
+	safetyUsecase := safetyusecase.NewSafetyUsecase(DB, safetyEntity)
 
 	// Create the handler with the use case
 	authenticateHandler := handler.NewAuthHandler(authenticateUsecase)
 	usersHandler := handler.NewUsersHandler(usersUsecase)
+	candidateHandler := handler.NewCandidateHandler(candidateUsecase)
 	swipeHandler := handler.NewSwipeHandler(swipeUsecase)
 	packageHandler := handler.NewPackageHandler(packageUsecase)
 	quotaHandler := handler.NewQuotaHandler(dailyQuotasUsecase)
 	accountHandler := handler.NewAccountHandler(accountUsecase)
+	noteHandler := handler.NewNoteHandler(noteUsecase)
+	contactHandler := handler.NewContactHandler(contactUsecase)
+	hiddenAccountHandler := handler.NewHiddenAccountHandler(hiddenAccountUsecase)
+	blockHandler := handler.NewBlockHandler(blockUsecase)
+	eventHandler := handler.NewEventHandler(eventUsecase)
+	integrationHandler := handler.NewIntegrationHandler(integrationUsecase)
+	shareLinkHandler := handler.NewShareLinkHandler(shareLinkUsecase)
+	rewardHandler := handler.NewRewardHandler(rewardUsecase)
+	profileStrengthHandler := handler.NewProfileStrengthHandler(profileStrengthUsecase)
+	adminHandler := handler.NewAdminHandler(adminUsecase)
+	recoveryHandler := handler.NewRecoveryHandler(recoveryUsecase)
+	matchFeatureHandler := handler.NewMatchFeatureHandler(matchFeatureUsecase)
+	clientConfigHandler := handler.NewClientConfigHandler(clientConfigUsecase)
+	statusMessageHandler := handler.NewStatusMessageHandler(statusMessageUsecase)
+	profileChangeHandler := handler.NewProfileChangeHandler(profileChangeUsecase)
+	matchHandler := handler.NewMatchHandler(matchUsecase)
+	duoHandler := handler.NewDuoHandler(duoUsecase)
+	messageHandler := handler.NewMessageHandler(messageUsecase)
+	photoHandler := handler.NewPhotoHandler(photoUsecase)
+	networkRuleHandler := handler.NewNetworkRuleHandler(networkRuleUsecase)
+	likesHandler := handler.NewLikesHandler(likeUsecase)
+	notificationHandler := handler.NewNotificationHandler(notificationUsecase)
+	healthHandler := handler.NewHealthHandler(healthUsecase)
+	accountImportHandler := handler.NewAccountImportHandler(accountImportUsecase)
+	safetyHandler := handler.NewSafetyHandler(safetyUsecase)
 
 	// Set up the router
 	r := router.InitializeRouter(
 		authenticateHandler,
 		usersHandler,
+		candidateHandler,
 		swipeHandler,
 		packageHandler,
 		quotaHandler,
 		accountHandler,
+		noteHandler,
+		contactHandler,
+		hiddenAccountHandler,
+		duoHandler,
+		blockHandler,
+		eventHandler,
+		integrationHandler,
+		shareLinkHandler,
+		rewardHandler,
+		profileStrengthHandler,
+		adminHandler,
+		recoveryHandler,
+		matchFeatureHandler,
+		clientConfigHandler,
+		statusMessageHandler,
+		profileChangeHandler,
+		matchHandler,
+		messageHandler,
+		photoHandler,
+		networkRuleHandler,
+		likesHandler,
+		notificationHandler,
+		healthHandler,
+		accountImportHandler,
+		safetyHandler,
+		RS,
+		storage.MediaHandler(mediaStorage),
+		regionRegistry.HintMiddleware,
+		cfg.Server.Router,
 	)
 
+	jobScheduler.Start()
+
 	// Create a channel to listen for OS signals
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
 
-	// Start the server in a goroutine
+	// Every request context derives from serverCtx, cancelling it ends the chat websockets
+	// because Shutdown does not wait for hijacked connections
+	serverCtx, cancelServerCtx := context.WithCancel(ctx)
+	tlsConfig, err := config.NewServerTLSConfig(cfg.Server.TLS)
+	if err != nil {
+		log.Fatalf("Invalid TLS configuration: %v", err)
+	}
+	server := &http.Server{
+		Addr:        ":" + strconv.Itoa(cfg.ServerPort),
+		Handler:     common.RequestIDMiddleware(networkACL.Middleware(geoGuard.Middleware(r))),
+		BaseContext: func(net.Listener) context.Context { return serverCtx },
+		TLSConfig:   tlsConfig,
+	}
+	server.RegisterOnShutdown(cancelServerCtx)
+	go runtimeWatchdog.Run(serverCtx)
+	go statusMonitor.Run(serverCtx)
+	go outboxUsecase.ExecuteRelayLoop(serverCtx)
+
+	// Start the server in a goroutine, the certificate is already in the TLS config
 	go func() {
-		err := http.ListenAndServe(":8000", r)
-		common.HandleErrorReturn(err)
+		var err error
+		if tlsConfig != nil {
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Server failed: %v", err)
+		}
 	}()
 
+	if devMode {
+		PrintDevCredentials(cfg)
+	}
+
 	// Block until a signal is received
 	<-stop
 
 	log.Println("Shutting down the server...")
+	Shutdown(server, jobScheduler, cfg.Server.ShutdownTimeout)
+}
+
+// Shutdown lets the in-flight requests and the running jobs finish before the connections they use are closed,
+// all within timeout
+func Shutdown(server *http.Server, jobScheduler *scheduler.Scheduler, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Stop accepting requests first, a request may still trigger a job
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("Server shutdown did not complete: %v", err)
+	}
+	if err := jobScheduler.Stop(ctx); err != nil {
+		log.Printf("Cron scheduler stop did not complete: %v", err)
+	}
+
+	config.CloseRedisClient()
+	config.CloseDBConnection()
+	log.Println("Server stopped")
 }
 
 func InitializeLogger() *os.File {
@@ -122,35 +472,138 @@ func InitializeLogger() *os.File {
 	return setupLogger
 }
 
-func InitializeDB(ctx context.Context) *sql.DB {
+func InitializePIIScrubbing() {
+	// Scrub the default log output too when the file logger is not used
+	if common.PIIScrubbingEnabled() {
+		log.SetOutput(common.NewPIIScrubWriter(log.Writer()))
+		log.Println("PII scrubbing enabled for logs")
+	}
+}
+
+func InitializeDB(ctx context.Context, cfg config.DBConfig) *sql.DB {
 	// Create of the database connection
-	DB := config.CreateDBConnection(ctx)
+	DB := config.CreateDBConnection(ctx, cfg)
 	return DB
 }
 
-func InitializeRedis(ctx context.Context) redisclient.RedisInterface {
+func InitializeMigrations(ctx context.Context, DB *sql.DB, migrateOnStartup bool) {
+	// Off by default so a deploy with several instances can migrate once with cmd/migrate before starting them
+	if !migrateOnStartup {
+		return
+	}
+	if _, err := migrations.NewMigrator(DB).Up(ctx); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+}
+
+// InitializeSchemaGuard compares the schema with this build once at startup. A drifted schema does not stop the
+// server, the readiness probe reports it so the instance gets no traffic and the log says what is wrong
+func InitializeSchemaGuard(ctx context.Context, DB *sql.DB) *migrations.SchemaGuard {
+	schemaGuard := migrations.NewSchemaGuard(DB)
+	_, _ = schemaGuard.Check(ctx)
+	return schemaGuard
+}
+
+func InitializeRedis(ctx context.Context, cfg config.RedisConfig) redisclient.RedisInterface {
 	// Create redis client connection
-	rdsClient := config.InitializeRedisClient(ctx)
+	rdsClient := config.InitializeRedisClient(ctx, cfg)
 	rds := redisclient.NewRedisService(rdsClient)
-	return rds
+	// Only wraps the client when fault injection is enabled for resilience testing
+	return redisclient.NewFaultInjectingRedis(rds)
 }
 
-func InitializeCronJobDailyQuota(ctx context.Context, boundary dailyquotausecase.InputDailyQuotaBoundary) {
-	cronRunning := os.Getenv("CRON_JOB_DAILY_QUOTA")
-	c := cron.New()
-	// Run every 24 hours
-	_, err := c.AddFunc(cronRunning, func() { // Changed to run every minute for testing
-		log.Println("Executing daily quota update usecase")
-		err := boundary.ExecuteAutoUpdateDailyQuotaUsecase(ctx)
-		if err != nil {
-			log.Printf("Error executing daily quota update usecase: %v", err)
-		} else {
-			log.Println("Successfully executed daily quota update usecase")
-		}
+func InitializeStorageCheck(ctx context.Context, mediaStorage storage.Storage) {
+	// The service still starts, only uploads and deletes fail until the storage is reachable
+	checkCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := mediaStorage.Check(checkCtx); err != nil {
+		log.Printf("Media storage check failed: %v", err)
+	}
+}
+
+func InitializeStatusMonitor(DB *sql.DB, mediaStorage storage.Storage, cfg uptime.Config) *uptime.Monitor {
+	statusMonitor := uptime.New(cfg)
+	statusMonitor.AddProbe(uptime.ComponentDatabase, true, DB.PingContext)
+	statusMonitor.AddProbe(uptime.ComponentRedis, true, func(ctx context.Context) error {
+		return config.RedisClient.Ping(ctx).Err()
 	})
-	if err != nil {
-		log.Printf("Error adding cron job: %v", err)
+	statusMonitor.AddProbe(uptime.ComponentStorage, false, mediaStorage.Check)
+	return statusMonitor
+}
+
+func InitializeCronJobDailyQuota(jobScheduler *scheduler.Scheduler, spec string, boundary dailyquotausecase.InputDailyQuotaBoundary) {
+	// Run every 24 hours
+	jobScheduler.Register("daily_quota_reset", spec, boundary.ExecuteAutoUpdateDailyQuotaUsecase)
+}
+
+func InitializeCronJobSeenTodayCleanup(jobScheduler *scheduler.Scheduler, spec string, boundary users.InputUserBoundary) {
+	jobScheduler.Register("seen_today_cleanup", spec, boundary.ExecuteClearSeenToday)
+}
+
+func InitializeCronJobPremiumExpiry(jobScheduler *scheduler.Scheduler, spec string, boundary packageusecase.InputPackageBoundary) {
+	jobScheduler.Register("premium_expiry", spec, boundary.ExecuteExpirePremiums)
+}
+
+func InitializeEmailDomains(ctx context.Context, boundary emaildomainsusecase.InputEmailDomainBoundary) {
+	// Sign up is still served without the remote list, with the configured domains only
+	if err := boundary.ExecuteRefreshEmailDomains(ctx); err != nil {
+		log.Printf("Failed to load the email domain list: %v", err)
+	}
+}
+
+func InitializeCronJobEmailDomainRefresh(jobScheduler *scheduler.Scheduler, spec string, boundary emaildomainsusecase.InputEmailDomainBoundary) {
+	jobScheduler.Register("email_domain_refresh", spec, boundary.ExecuteRefreshEmailDomains)
+}
+
+func InitializeCronJobIntegrationRefresh(jobScheduler *scheduler.Scheduler, spec string, boundary integrationsusecase.InputIntegrationBoundary) {
+	jobScheduler.Register("integration_refresh", spec, boundary.ExecuteRefreshIntegrations)
+}
+
+func InitializeCronJobBackupVerification(jobScheduler *scheduler.Scheduler, spec string, boundary backupsusecase.InputBackupBoundary) {
+	jobScheduler.Register("backup_verification", spec, boundary.ExecuteVerifyBackups)
+}
+
+func InitializeCronJobQuotaUsageRollup(jobScheduler *scheduler.Scheduler, spec string, boundary analyticsusecase.InputAnalyticsBoundary) {
+	jobScheduler.Register("quota_usage_rollup", spec, boundary.ExecuteQuotaUsageRollup)
+}
+
+func InitializeCronJobDormancy(jobScheduler *scheduler.Scheduler, spec string, boundary dormancyusecase.InputDormancyBoundary) {
+	jobScheduler.Register("account_dormancy", spec, boundary.ExecuteDormancyPipeline)
+}
+
+func InitializeCronJobAccountDeletionPurge(jobScheduler *scheduler.Scheduler, spec string, boundary dormancyusecase.InputDormancyBoundary) {
+	jobScheduler.Register("account_deletion_purge", spec, boundary.ExecuteDeletionPurge)
+}
+
+func InitializeCronJobLoginHistoryRetention(jobScheduler *scheduler.Scheduler, spec string, boundary loginhistoryusecase.InputLoginHistoriesBoundary) {
+	jobScheduler.Register("login_history_retention", spec, boundary.ExecuteLoginHistoryRetention)
+}
+
+func InitializeCronJobMatchFeatures(jobScheduler *scheduler.Scheduler, spec string, boundary matchfeaturesusecase.InputMatchFeatureBoundary) {
+	jobScheduler.Register("match_features", spec, boundary.ExecuteComputeMatchFeatures)
+}
+
+func InitializeCronJobEventRoomCleanup(jobScheduler *scheduler.Scheduler, spec string, boundary eventsusecase.InputEventBoundary) {
+	jobScheduler.Register("event_room_cleanup", spec, boundary.ExecuteCleanupEventRooms)
+}
+
+func InitializeCronJobPhotoTrashPurge(jobScheduler *scheduler.Scheduler, spec string, boundary photosusecase.InputPhotoBoundary) {
+	jobScheduler.Register("photo_trash_purge", spec, boundary.ExecutePurgeTrashedPhotos)
+}
+
+func InitializeCronJobNetworkRuleRefresh(jobScheduler *scheduler.Scheduler, spec string, boundary networkrulesusecase.InputNetworkRuleBoundary) {
+	// Picks up network rules changed on another instance
+	jobScheduler.Register("network_rule_refresh", spec, boundary.ExecuteLoadNetworkRules)
+}
+
+func InitializeCronJobPushDelivery(jobScheduler *scheduler.Scheduler, spec string, boundary notificationsusecase.InputNotificationBoundary) {
+	// Sends the queued pushes, a few seconds apart so a match is pushed right away
+	jobScheduler.Register("push_delivery", spec, boundary.ExecuteDeliverPushes)
+}
+
+func InitializeNetworkRules(ctx context.Context, boundary networkrulesusecase.InputNetworkRuleBoundary) {
+	// Deny and allow lists are in force before the server accepts a request
+	if err := boundary.ExecuteLoadNetworkRules(ctx); err != nil {
+		log.Fatalf("Failed to load network rules: %v", err)
 	}
-	c.Start()
-	log.Println("Cron job started")
 }
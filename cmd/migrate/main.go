@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"godating-dealls/config"
+	"godating-dealls/internal/infra/mysql/migrations"
+	"log"
+	"os"
+)
+
+// migrate applies the pending schema migrations, the server does the same at startup when MIGRATE_ON_STARTUP is true.
+// Run with: go run ./cmd/migrate [-status] [-drift] [-baseline version]
+func main() {
+	status := flag.Bool("status", false, "list the migrations and when they were applied, without applying any")
+	drift := flag.Bool("drift", false, "compare the schema with the migrations and critical columns of this build, exits 1 on drift")
+	baseline := flag.Int("baseline", 0, "record the migrations up to this version as applied without running them, for a database created before the migrations")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	ctx := context.Background()
+	db := config.CreateDBConnection(ctx, cfg.DB)
+	defer config.CloseDBConnection()
+
+	migrator := migrations.NewMigrator(db)
+	switch {
+	case *status:
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatalf("Migration status failed: %v", err)
+		}
+		for _, migration := range statuses {
+			appliedAt := "pending"
+			if migration.AppliedAt != nil {
+				appliedAt = migration.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("  %04d %-40s %s\n", migration.Version, migration.Name, appliedAt)
+		}
+	case *drift:
+		report, err := migrator.CheckDrift(ctx)
+		if err != nil {
+			log.Fatalf("Schema check failed: %v", err)
+		}
+		fmt.Printf("Database at migration %d, this build expects %d\n", report.AppliedVersion, report.ExpectedVersion)
+		for _, version := range report.UnknownMigrations {
+			fmt.Printf("  migration %d is applied but not known to this build\n", version)
+		}
+		if !report.Drifted() {
+			fmt.Println("No schema drift")
+			return
+		}
+		for _, problem := range report.Problems() {
+			fmt.Printf("  %s\n", problem)
+		}
+		config.CloseDBConnection()
+		os.Exit(1)
+	case *baseline > 0:
+		baselined, err := migrator.Baseline(ctx, *baseline)
+		if err != nil {
+			log.Fatalf("Migration baseline failed: %v", err)
+		}
+		fmt.Printf("Baselined %d migrations\n", len(baselined))
+	default:
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		fmt.Printf("Applied %d migrations\n", len(applied))
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"godating-dealls/config"
+	"godating-dealls/internal/infra/redisclient"
+	"log"
+	"os"
+)
+
+// redis-audit reports redis keys that break the key registry, it exits non zero when a violation is found.
+// Run with: go run ./cmd/redis-audit [-fix]
+func main() {
+	fix := flag.Bool("fix", false, "delete keys in an unregistered namespace and expire registered keys without a ttl")
+	limit := flag.Int("limit", 50, "maximum violations to print")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	ctx := context.Background()
+	client := config.InitializeRedisClient(ctx, cfg.Redis)
+	defer client.Close()
+
+	report, err := redisclient.AuditKeys(ctx, client)
+	if err != nil {
+		log.Fatalf("Redis audit failed: %v", err)
+	}
+
+	fmt.Printf("Scanned %d keys\n", report.Scanned)
+	for prefix, count := range report.ByPrefix {
+		fmt.Printf("  %-24s %d\n", prefix, count)
+	}
+	fmt.Printf("Violations: %d\n", len(report.Violations))
+	for i, violation := range report.Violations {
+		if i == *limit {
+			fmt.Printf("  ... %d more\n", len(report.Violations)-*limit)
+			break
+		}
+		fmt.Printf("  %s: %s (ttl %s)\n", violation.Key, violation.Reason, violation.TTL)
+	}
+
+	if *fix {
+		fixed := 0
+		for _, violation := range report.Violations {
+			if policy, ok := redisclient.FindKeyPolicy(violation.Key); ok {
+				err = client.Expire(ctx, violation.Key, policy.TTL).Err()
+			} else {
+				err = client.Del(ctx, violation.Key).Err()
+			}
+			if err != nil {
+				log.Printf("Failed to fix %s: %v", violation.Key, err)
+				continue
+			}
+			fixed++
+		}
+		fmt.Printf("Fixed %d keys\n", fixed)
+		return
+	}
+
+	if len(report.Violations) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"godating-dealls/config"
+	"godating-dealls/internal/infra/storage"
+	"log"
+	"time"
+)
+
+// storage checks the configured media storage and sets up the lifecycle rules of its bucket.
+// Run with: go run ./cmd/storage [-lifecycle] [-rules "trash/=30"]
+func main() {
+	lifecycle := flag.Bool("lifecycle", false, "replace the lifecycle rules of the bucket with -rules")
+	rules := flag.String("rules", "", "lifecycle rules as prefix=days pairs, comma separated, empty removes them (default STORAGE_LIFECYCLE_RULES)")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	lifecycleRules := cfg.Services.Storage.LifecycleRules
+	if flagSet("rules") {
+		if lifecycleRules, err = storage.ParseLifecycleRules(*rules); err != nil {
+			log.Fatalf("Invalid lifecycle rules: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	mediaStorage := storage.NewStorage(cfg.Services.Storage)
+	if err := mediaStorage.Check(ctx); err != nil {
+		log.Fatalf("Storage check failed: %v", err)
+	}
+	fmt.Printf("Storage %T is reachable\n", mediaStorage)
+
+	if !*lifecycle {
+		return
+	}
+	if err := storage.SetLifecycle(ctx, mediaStorage, lifecycleRules); err != nil {
+		log.Fatalf("Setting lifecycle rules failed: %v", err)
+	}
+	for _, rule := range lifecycleRules {
+		fmt.Printf("  %-24s expires after %d days\n", rule.Prefix, rule.ExpireAfterDays)
+	}
+	fmt.Printf("Lifecycle rules set: %d\n", len(lifecycleRules))
+}
+
+// flagSet tells an empty -rules, which removes the rules, from no -rules at all
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
@@ -0,0 +1,204 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/joho/godotenv"
+	"github.com/robfig/cron/v3"
+	"godating-dealls/internal/common"
+	"os"
+	"strings"
+)
+
+// Config is read once at startup, a missing or malformed setting stops the service before it serves anything
+type Config struct {
+	Env              string `validate:"required"`
+	ServerPort       int    `validate:"min=1,max=65535"`
+	JWTSecret        string `validate:"required,min=32"`
+	MigrateOnStartup bool
+	LogLevel         string            `validate:"oneof=debug info warn error"`
+	ModuleLogLevels  map[string]string `validate:"dive,keys,log-module,endkeys,oneof=debug info warn error"`
+	DB               DBConfig
+	Redis            RedisConfig
+	Cron             CronConfig
+	Server           ServerConfig
+	Security         common.SecurityConfig
+	FaultInjection   common.FaultInjectionConfig
+	Policies         PolicyConfig
+	Services         ServiceConfig
+}
+
+// DBConfig is MySQL unless Driver is sqlite, the development mode keeping the database in the SQLitePath file
+type DBConfig struct {
+	Driver     string `validate:"oneof=mysql sqlite"`
+	User       string `validate:"required_if=Driver mysql"`
+	Password   string
+	Name       string `validate:"required_if=Driver mysql"`
+	Host       string `validate:"required_if=Driver mysql"`
+	Port       int    `validate:"min=1,max=65535"`
+	SQLitePath string `validate:"required_if=Driver sqlite"`
+}
+
+// DSN parses times so DATETIME and TIMESTAMP columns scan into time.Time
+func (d DBConfig) DSN() string {
+	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true", d.User, d.Password, d.Host, d.Port, d.Name)
+}
+
+// RedisConfig is a redis server unless Driver is memory, the development mode running one in the process
+type RedisConfig struct {
+	Driver   string `validate:"oneof=redis memory"`
+	Host     string `validate:"required_if=Driver redis"`
+	Port     int    `validate:"min=1,max=65535"`
+	User     string
+	Password string
+	// TLS is on outside development, the hosted redis only accepts TLS connections
+	TLS bool
+}
+
+func (r RedisConfig) Addr() string {
+	return fmt.Sprintf("%s:%d", r.Host, r.Port)
+}
+
+// CronConfig has the schedule of every background job, an empty schedule pauses the job
+// so it only runs when triggered from the admin api
+type CronConfig struct {
+	DailyQuota            string `validate:"omitempty,cron"`
+	PremiumExpiry         string `validate:"omitempty,cron"`
+	EmailDomainRefresh    string `validate:"omitempty,cron"`
+	IntegrationRefresh    string `validate:"omitempty,cron"`
+	BackupVerification    string `validate:"omitempty,cron"`
+	QuotaUsageRollup      string `validate:"omitempty,cron"`
+	Dormancy              string `validate:"omitempty,cron"`
+	LoginHistoryRetention string `validate:"omitempty,cron"`
+	MatchFeatures         string `validate:"omitempty,cron"`
+	EventRoomCleanup      string `validate:"omitempty,cron"`
+	PhotoTrashPurge       string `validate:"omitempty,cron"`
+	NetworkRuleRefresh    string `validate:"omitempty,cron"`
+	SeenTodayCleanup      string `validate:"omitempty,cron"`
+	PushDelivery          string `validate:"omitempty,cron"`
+	AccountDeletionPurge  string `validate:"omitempty,cron"`
+}
+
+// Load reads the config from the environment, in development the .env file is loaded first.
+// Every invalid setting is reported at once
+func Load() (Config, error) {
+	env := os.Getenv("ENV")
+	if env == "development" {
+		if err := godotenv.Load(); err != nil {
+			return Config{}, fmt.Errorf("could not load .env file: %v", err)
+		}
+	}
+
+	read := &envReader{}
+	server := loadServerConfig(read)
+	cfg := Config{
+		Env:              env,
+		ServerPort:       read.Int("SERVER_PORT", 8000),
+		JWTSecret:        os.Getenv("JWT_SECRET"),
+		MigrateOnStartup: strings.EqualFold(os.Getenv("MIGRATE_ON_STARTUP"), "true"),
+		LogLevel:         common.LogLevelInfo,
+		ModuleLogLevels:  map[string]string{},
+		DB: DBConfig{
+			Driver:     strings.ToLower(read.String("DB_DRIVER", "mysql")),
+			SQLitePath: read.String("DB_SQLITE_PATH", "godating-dev.db"),
+			User:       os.Getenv("DB_USER"),
+			Password:   os.Getenv("DB_PASSWORD"),
+			Name:       os.Getenv("DB_NAME"),
+			Host:       os.Getenv("DB_HOST"),
+			Port:       read.Int("DB_PORT", 3306),
+		},
+		Redis: RedisConfig{
+			Driver:   strings.ToLower(read.String("REDIS_DRIVER", "redis")),
+			Host:     os.Getenv("REDIS_HOST"),
+			Port:     read.Int("REDIS_PORT", 6379),
+			User:     os.Getenv("REDIS_USER"),
+			Password: os.Getenv("REDIS_PASSWORD"),
+			TLS:      env != "development",
+		},
+		Cron: CronConfig{
+			DailyQuota:            os.Getenv("CRON_JOB_DAILY_QUOTA"),
+			PremiumExpiry:         os.Getenv("CRON_JOB_PREMIUM_EXPIRY"),
+			EmailDomainRefresh:    os.Getenv("CRON_JOB_EMAIL_DOMAIN_REFRESH"),
+			IntegrationRefresh:    os.Getenv("CRON_JOB_INTEGRATION_REFRESH"),
+			BackupVerification:    os.Getenv("CRON_JOB_BACKUP_VERIFICATION"),
+			QuotaUsageRollup:      os.Getenv("CRON_JOB_QUOTA_USAGE_ROLLUP"),
+			Dormancy:              os.Getenv("CRON_JOB_DORMANCY"),
+			LoginHistoryRetention: os.Getenv("CRON_JOB_LOGIN_HISTORY_RETENTION"),
+			MatchFeatures:         os.Getenv("CRON_JOB_MATCH_FEATURES"),
+			EventRoomCleanup:      os.Getenv("CRON_JOB_EVENT_ROOM_CLEANUP"),
+			PhotoTrashPurge:       os.Getenv("CRON_JOB_PHOTO_TRASH_PURGE"),
+			NetworkRuleRefresh:    os.Getenv("CRON_JOB_NETWORK_RULE_REFRESH"),
+			SeenTodayCleanup:      os.Getenv("CRON_JOB_SEEN_TODAY_CLEANUP"),
+			PushDelivery:          os.Getenv("CRON_JOB_PUSH_DELIVERY"),
+			AccountDeletionPurge:  os.Getenv("CRON_JOB_ACCOUNT_DELETION_PURGE"),
+		},
+		Server:         server,
+		Security:       loadSecurityConfig(read, server.TLS),
+		FaultInjection: loadFaultInjectionConfig(read, env),
+		Policies:       loadPolicyConfig(read),
+		Services:       loadServiceConfig(read),
+	}
+
+	invalid := read.invalid
+	if level := strings.TrimSpace(os.Getenv("LOG_LEVEL")); level != "" {
+		cfg.LogLevel = strings.ToLower(level)
+	}
+	// LOG_LEVELS overrides the level per module, e.g. "repo=debug,auth=warn"
+	for _, entry := range strings.Split(os.Getenv("LOG_LEVELS"), ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		module, level, ok := strings.Cut(entry, "=")
+		if !ok {
+			invalid = append(invalid, fmt.Sprintf("LOG_LEVELS entry %q must be module=level", entry))
+			continue
+		}
+		cfg.ModuleLogLevels[strings.TrimSpace(module)] = strings.ToLower(strings.TrimSpace(level))
+	}
+
+	// The sqlite and in-memory drivers are for running the service without external services on a laptop
+	if env != "development" && (cfg.DB.Driver == "sqlite" || cfg.Redis.Driver == "memory") {
+		invalid = append(invalid, "DB_DRIVER=sqlite and REDIS_DRIVER=memory are only allowed with ENV=development")
+	}
+	// A new sqlite file has no tables, the migrations are the only way to create them
+	if cfg.DB.Driver == "sqlite" {
+		cfg.MigrateOnStartup = true
+	}
+
+	validate := validator.New()
+	rules := map[string]validator.Func{
+		"cron":       validateCronSpec,
+		"log-module": validateLogModule,
+	}
+	for tag, rule := range rules {
+		if err := validate.RegisterValidation(tag, rule); err != nil {
+			return Config{}, fmt.Errorf("could not register the %s validation: %v", tag, err)
+		}
+	}
+	var fieldErrors validator.ValidationErrors
+	if err := validate.Struct(cfg); errors.As(err, &fieldErrors) {
+		for _, fieldError := range fieldErrors {
+			invalid = append(invalid, fmt.Sprintf("%s failed %s", fieldError.Namespace(), fieldError.Tag()))
+		}
+	}
+	if len(invalid) > 0 {
+		return Config{}, fmt.Errorf("invalid config: %s", strings.Join(invalid, ", "))
+	}
+	return cfg, nil
+}
+
+func validateLogModule(fl validator.FieldLevel) bool {
+	for _, module := range common.LogModules() {
+		if module == fl.Field().String() {
+			return true
+		}
+	}
+	return false
+}
+
+// validateCronSpec accepts what the scheduler accepts, standard five field specs and descriptors like @hourly
+func validateCronSpec(fl validator.FieldLevel) bool {
+	_, err := cron.ParseStandard(fl.Field().String())
+	return err == nil
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// envReader reads typed settings from the environment. An unset or blank variable keeps the fallback, a malformed
+// one is added to invalid so Load reports every problem at once
+type envReader struct {
+	invalid []string
+}
+
+func (e *envReader) lookup(key string) (string, bool) {
+	value := strings.TrimSpace(os.Getenv(key))
+	return value, value != ""
+}
+
+func (e *envReader) String(key string, fallback string) string {
+	if value, ok := e.lookup(key); ok {
+		return value
+	}
+	return fallback
+}
+
+func (e *envReader) Int(key string, fallback int) int {
+	value, ok := e.lookup(key)
+	if !ok {
+		return fallback
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		e.invalid = append(e.invalid, key+" must be a number")
+		return fallback
+	}
+	return parsed
+}
+
+func (e *envReader) Int64(key string, fallback int64) int64 {
+	value, ok := e.lookup(key)
+	if !ok {
+		return fallback
+	}
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		e.invalid = append(e.invalid, key+" must be a number")
+		return fallback
+	}
+	return parsed
+}
+
+func (e *envReader) Float(key string, fallback float64) float64 {
+	value, ok := e.lookup(key)
+	if !ok {
+		return fallback
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		e.invalid = append(e.invalid, key+" must be a number")
+		return fallback
+	}
+	return parsed
+}
+
+func (e *envReader) Bool(key string, fallback bool) bool {
+	value, ok := e.lookup(key)
+	if !ok {
+		return fallback
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		e.invalid = append(e.invalid, key+" must be true or false")
+		return fallback
+	}
+	return parsed
+}
+
+// Duration reads a whole number of unit, e.g. DORMANCY_GRACE_DAYS in days, the fallback is in unit too
+func (e *envReader) Duration(key string, fallback int, unit time.Duration) time.Duration {
+	return time.Duration(e.Int(key, fallback)) * unit
+}
+
+// ParseDuration reads a Go duration such as 500ms or 2s
+func (e *envReader) ParseDuration(key string, fallback time.Duration) time.Duration {
+	value, ok := e.lookup(key)
+	if !ok {
+		return fallback
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		e.invalid = append(e.invalid, key+" must be a duration such as 1s")
+		return fallback
+	}
+	return parsed
+}
+
+// List reads a comma separated list, the entries are trimmed and the empty ones left out. An empty list is the
+// fallback
+func (e *envReader) List(key string, fallback ...string) []string {
+	var entries []string
+	for _, entry := range strings.Split(os.Getenv(key), ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == 0 {
+		return fallback
+	}
+	return entries
+}
+
+// Pairs reads a comma separated list of key and value joined by separator, e.g. "name:secret,other:secret"
+func (e *envReader) Pairs(key string, separator string) map[string]string {
+	pairs := map[string]string{}
+	for _, entry := range e.List(key) {
+		name, value, found := strings.Cut(entry, separator)
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !found || name == "" || value == "" {
+			e.invalid = append(e.invalid, key+" entries must be name"+separator+"value")
+			continue
+		}
+		pairs[name] = value
+	}
+	return pairs
+}
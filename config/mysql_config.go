@@ -3,11 +3,9 @@ package config
 import (
 	"context"
 	"database/sql"
-	"fmt"
-	"github.com/joho/godotenv"
 	"godating-dealls/internal/common"
+	"godating-dealls/internal/infra/sqlite"
 	"log"
-	"os"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
@@ -16,23 +14,8 @@ import (
 var db *sql.DB
 
 // initMySQLDB initializes the database connection
-func initMySQLDB(ctx context.Context) *sql.DB {
-	// Load .env file
-	env := os.Getenv("ENV")
-	if env == "development" {
-		err := godotenv.Load()
-		if err != nil {
-			log.Fatalf("Error loading .env file: %v", err)
-		}
-	}
-	dbUser := os.Getenv("DB_USER")
-	dbPassword := os.Getenv("DB_PASSWORD")
-	dbName := os.Getenv("DB_NAME")
-	dbHost := os.Getenv("DB_HOST")
-	dbPort := os.Getenv("DB_PORT")
-
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true", dbUser, dbPassword, dbHost, dbPort, dbName)
-	db, err := sql.Open("mysql", dsn)
+func initMySQLDB(ctx context.Context, cfg DBConfig) *sql.DB {
+	db, err := sql.Open("mysql", cfg.DSN())
 	common.HandleErrorWithParam(err, "Could not opn DB connection, DB connection is failed")
 
 	db.SetMaxOpenConns(25)
@@ -49,10 +32,26 @@ func initMySQLDB(ctx context.Context) *sql.DB {
 	return db
 }
 
+// initSQLiteDB opens the database file of the development mode, the transactions writing to it take turns
+func initSQLiteDB(ctx context.Context, cfg DBConfig) *sql.DB {
+	db, err := sqlite.Open(ctx, cfg.SQLitePath)
+	common.HandleErrorWithParam(err, "Could not open DB SQLite")
+
+	db.SetMaxOpenConns(25)
+	db.SetMaxIdleConns(25)
+	log.Printf("Connected to DB SQLite %s, for development only", cfg.SQLitePath)
+
+	return db
+}
+
 // CreateDBConnection returns the singleton database instance
-func CreateDBConnection(ctx context.Context) *sql.DB {
+func CreateDBConnection(ctx context.Context, cfg DBConfig) *sql.DB {
 	if db == nil {
-		db = initMySQLDB(ctx)
+		if cfg.Driver == "sqlite" {
+			db = initSQLiteDB(ctx, cfg)
+		} else {
+			db = initMySQLDB(ctx, cfg)
+		}
 	}
 	return db
 }
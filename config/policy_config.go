@@ -0,0 +1,83 @@
+package config
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/candidates"
+	"godating-dealls/internal/core/entities/safety"
+	"godating-dealls/internal/core/usecase/auths"
+	candidatesusecase "godating-dealls/internal/core/usecase/candidates"
+	"godating-dealls/internal/core/usecase/dormancy"
+	"godating-dealls/internal/core/usecase/login_histories"
+	"godating-dealls/internal/core/usecase/outbox"
+	"godating-dealls/internal/core/usecase/recovery"
+	"godating-dealls/internal/core/usecase/share_links"
+	"godating-dealls/internal/core/usecase/swipes"
+	"godating-dealls/internal/core/usecase/users"
+	"strings"
+	"time"
+)
+
+// PolicyConfig has the limits and windows of the usecases, each one keeps its default when the variable is unset
+type PolicyConfig struct {
+	Auth                      auths.Policy
+	SuperLike                 swipes.SuperLikePolicy
+	Discovery                 users.DiscoveryPolicy
+	Candidates                candidatesusecase.CandidatePolicy
+	Dormancy                  dormancy.Policy
+	Recovery                  recovery.Policy
+	Safety                    safety.Policy
+	EmailDomains              common.EmailDomainRules
+	LoginHistoryRetentionDays int    `validate:"gt=0"`
+	PublicProfileBaseURL      string `validate:"url"`
+	// OutboxRelayInterval is a Go duration such as 500ms
+	OutboxRelayInterval time.Duration `validate:"gt=0"`
+}
+
+func loadPolicyConfig(read *envReader) PolicyConfig {
+	const day = 24 * time.Hour
+	discoveryRadiusKm := read.Int("DISCOVERY_RADIUS_KM", users.DefaultDiscoveryRadiusKm)
+	return PolicyConfig{
+		Auth: auths.Policy{
+			LoginThrottle: auths.LoginThrottlePolicy{
+				CredentialLimit: read.Int64("LOGIN_THROTTLE_CREDENTIAL_LIMIT", auths.DefaultCredentialFailureLimit),
+				AddressLimit:    read.Int64("LOGIN_THROTTLE_ADDRESS_LIMIT", auths.DefaultAddressFailureLimit),
+			},
+			PasswordResetURL: read.String("PASSWORD_RESET_URL", ""),
+		},
+		SuperLike: swipes.SuperLikePolicy{
+			DailyLimit:        read.Int("SUPER_LIKE_DAILY_LIMIT", swipes.DefaultSuperLikeDailyLimit),
+			PremiumDailyLimit: read.Int("SUPER_LIKE_PREMIUM_DAILY_LIMIT", swipes.DefaultSuperLikePremiumDailyLimit),
+		},
+		Discovery: users.DiscoveryPolicy{RadiusKm: discoveryRadiusKm},
+		Candidates: candidatesusecase.CandidatePolicy{
+			RadiusKm: discoveryRadiusKm,
+			Weights: candidates.Weights{
+				Activity:     read.Float("CANDIDATE_ACTIVITY_WEIGHT", candidatesusecase.DefaultCandidateActivityWeight),
+				Completeness: read.Float("CANDIDATE_COMPLETENESS_WEIGHT", candidatesusecase.DefaultCandidateCompletenessWeight),
+			},
+		},
+		Dormancy: dormancy.Policy{
+			WarnAfterMonths:  read.Int("DORMANCY_WARN_AFTER_MONTHS", dormancy.DefaultWarnAfterMonths),
+			HideAfterMonths:  read.Int("DORMANCY_HIDE_AFTER_MONTHS", dormancy.DefaultHideAfterMonths),
+			PurgeAfterMonths: read.Int("DORMANCY_PURGE_AFTER_MONTHS", dormancy.DefaultPurgeAfterMonths),
+			Grace:            read.Duration("DORMANCY_GRACE_DAYS", dormancy.DefaultGraceDays, day),
+			DeletionGrace:    read.Duration("ACCOUNT_DELETION_GRACE_DAYS", dormancy.DefaultDeletionDays, day),
+			BatchSize:        read.Int("DORMANCY_BATCH_SIZE", dormancy.DefaultBatchSize),
+		},
+		Recovery: recovery.Policy{
+			WaitingPeriod: read.Duration("RECOVERY_WAITING_HOURS", recovery.DefaultWaitingHours, time.Hour),
+			ExpiresAfter:  read.Duration("RECOVERY_EXPIRES_HOURS", recovery.DefaultExpiresHours, time.Hour),
+		},
+		Safety: safety.NewPolicy(
+			read.Duration("SAFETY_FIRST_CONVERSATION_HOURS", safety.DefaultFirstConversationHours, time.Hour),
+			read.List("SAFETY_BLOCKED_TERMS")),
+		EmailDomains: common.EmailDomainRules{
+			Mode:    strings.ToLower(read.String("EMAIL_DOMAIN_MODE", common.EmailDomainModeDeny)),
+			Allowed: read.List("EMAIL_ALLOW_DOMAINS"),
+			Denied:  read.List("EMAIL_DENY_DOMAINS"),
+		},
+		LoginHistoryRetentionDays: read.Int("LOGIN_HISTORY_RETENTION_DAYS", login_histories.DefaultRetentionDays),
+		PublicProfileBaseURL:      read.String("PUBLIC_PROFILE_BASE_URL", share_links.DefaultPublicProfileBaseURL),
+		OutboxRelayInterval:       read.ParseDuration("OUTBOX_RELAY_INTERVAL", outbox.DefaultRelayInterval),
+	}
+}
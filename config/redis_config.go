@@ -3,40 +3,41 @@ package config
 import (
 	"context"
 	"crypto/tls"
-	"fmt"
-	"github.com/joho/godotenv"
+	"github.com/alicebob/miniredis/v2"
 	"github.com/redis/go-redis/v9"
+	"godating-dealls/internal/common"
 	"log"
-	"os"
 )
 
 // RedisClient is a global variable to hold the Redis client
 var RedisClient *redis.Client
 
-// InitializeRedisClient initializes the Redis client
-func InitializeRedisClient(ctx context.Context) *redis.Client {
-	// Load environment variables from .env file only in development
-	env := os.Getenv("ENV")
-	if env == "development" {
-		err := godotenv.Load()
-		if err != nil {
-			log.Fatalf("Error loading .env file: %v", err)
-		}
-	}
-
-	rdsHost := os.Getenv("REDIS_HOST")
-	rdsPort := os.Getenv("REDIS_PORT")
-	uri := fmt.Sprintf("%s:%s", rdsHost, rdsPort)
+// memoryRedis is the in-process redis of the development mode, its keys are gone when the service stops
+var memoryRedis *miniredis.Miniredis
 
+// InitializeRedisClient initializes the Redis client
+func InitializeRedisClient(ctx context.Context, cfg RedisConfig) *redis.Client {
 	options := &redis.Options{
-		Addr:     uri,
-		Password: os.Getenv("REDIS_PASSWORD"),
+		Addr:     cfg.Addr(),
+		Password: cfg.Password,
 		DB:       0,
-		Username: os.Getenv("REDIS_USER"),
+		Username: cfg.User,
+	}
+
+	if cfg.Driver == "memory" {
+		memoryRedis = miniredis.NewMiniRedis()
+		if err := memoryRedis.Start(); err != nil {
+			log.Fatalf("Error starting in-memory redis: %v", err)
+		}
+		options.Addr = memoryRedis.Addr()
+		options.Username, options.Password = "", ""
+		// It does not know CLIENT SETINFO
+		options.DisableIndentity = true
+		log.Println("Started in-memory Redis, for development only")
 	}
 
 	// Enable TLS for non-local environments
-	if env != "development" {
+	if cfg.TLS && cfg.Driver != "memory" {
 		options.TLSConfig = &tls.Config{
 			InsecureSkipVerify: true,
 		}
@@ -53,3 +54,14 @@ func InitializeRedisClient(ctx context.Context) *redis.Client {
 
 	return RedisClient
 }
+
+// CloseRedisClient closes the Redis client connection
+func CloseRedisClient() {
+	if RedisClient != nil {
+		err := RedisClient.Close()
+		common.HandleErrorReturn(err)
+	}
+	if memoryRedis != nil {
+		memoryRedis.Close()
+	}
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"fmt"
+	"godating-dealls/internal/common"
+	"godating-dealls/router"
+	"net/netip"
+	"strings"
+	"time"
+)
+
+// ServerConfig has the settings of the HTTP server itself
+type ServerConfig struct {
+	// ShutdownTimeout bounds how long the in-flight requests and the running jobs get to finish
+	ShutdownTimeout time.Duration `validate:"gt=0"`
+	TLS             TLSConfig
+	Router          router.Config
+}
+
+// TLSConfig is empty when the server serves plain HTTP behind its proxy. ClientCAFile turns on mTLS, client
+// certificates only exist on TLS connections
+type TLSConfig struct {
+	CertFile     string `validate:"required_with=ClientCAFile"`
+	KeyFile      string `validate:"required_with=CertFile"`
+	ClientCAFile string
+}
+
+func loadServerConfig(read *envReader) ServerConfig {
+	return ServerConfig{
+		ShutdownTimeout: read.Duration("SHUTDOWN_TIMEOUT_SECONDS", 30, time.Second),
+		TLS: TLSConfig{
+			CertFile:     read.String("TLS_CERT_FILE", ""),
+			KeyFile:      read.String("TLS_KEY_FILE", ""),
+			ClientCAFile: read.String("MTLS_CLIENT_CA_FILE", ""),
+		},
+		Router: router.Config{
+			PprofEnabled:            read.Bool("PPROF_ENABLED", false),
+			AuthRateLimitPerMinute:  read.Int("AUTH_RATE_LIMIT_PER_MINUTE", router.DefaultAuthRateLimitPerMinute),
+			SwipeRateLimitPerMinute: read.Int("SWIPE_RATE_LIMIT_PER_MINUTE", router.DefaultSwipeRateLimitPerMinute),
+		},
+	}
+}
+
+func loadSecurityConfig(read *envReader, tlsConfig TLSConfig) common.SecurityConfig {
+	return common.SecurityConfig{
+		AdminAPIKey:        read.String("ADMIN_API_KEY", ""),
+		ServiceClients:     read.Pairs("SERVICE_CLIENTS", ":"),
+		ClientCertRequired: tlsConfig.ClientCAFile != "",
+		ClientCertRoles:    read.Pairs("MTLS_IDENTITIES", "="),
+		TrustedProxies:     readPrefixes(read, "TRUSTED_PROXY_CIDRS"),
+		AntiEnumeration:    read.Bool("ANTI_ENUMERATION", true),
+		PIIScrubbing:       read.Bool("PII_SCRUBBING", false),
+		PIIHashSalt:        read.String("PII_HASH_SALT", ""),
+	}
+}
+
+// loadFaultInjectionConfig leaves fault injection off in production whatever FAULT_INJECTION_ENABLED says
+func loadFaultInjectionConfig(read *envReader, env string) common.FaultInjectionConfig {
+	config := common.FaultInjectionConfig{
+		Enabled:   read.Bool("FAULT_INJECTION_ENABLED", false) && env != "production",
+		Latency:   read.Duration("FAULT_INJECTION_LATENCY_MS", 0, time.Millisecond),
+		ErrorRate: read.Float("FAULT_INJECTION_ERROR_RATE", 0),
+		Targets:   map[string]bool{},
+	}
+	for _, target := range read.List("FAULT_INJECTION_TARGETS", common.FaultTargetDB, common.FaultTargetRedis) {
+		config.Targets[strings.ToLower(target)] = true
+	}
+	return config
+}
+
+func readPrefixes(read *envReader, key string) []netip.Prefix {
+	var prefixes []netip.Prefix
+	for _, value := range read.List(key) {
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			read.invalid = append(read.invalid, fmt.Sprintf("%s entry %q must be a CIDR such as 10.0.0.0/8", key, value))
+			continue
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes
+}
@@ -0,0 +1,210 @@
+package config
+
+import (
+	"fmt"
+	"godating-dealls/internal/infra/analytics"
+	"godating-dealls/internal/infra/backups"
+	"godating-dealls/internal/infra/eventbus"
+	"godating-dealls/internal/infra/geoip"
+	"godating-dealls/internal/infra/integrations"
+	"godating-dealls/internal/infra/notifier"
+	"godating-dealls/internal/infra/oauth"
+	"godating-dealls/internal/infra/push"
+	"godating-dealls/internal/infra/regions"
+	"godating-dealls/internal/infra/storage"
+	"godating-dealls/internal/infra/uptime"
+	"godating-dealls/internal/infra/watchdog"
+	"godating-dealls/internal/infra/webauthn"
+	"strings"
+	"time"
+)
+
+// ServiceConfig has the settings of the third parties the service talks to, one left unconfigured is turned off
+// or replaced by its local stand in
+type ServiceConfig struct {
+	WebAuthn           webauthn.Config
+	OAuth              oauth.Config
+	Integrations       integrations.Config
+	Storage            storage.Config
+	Notifier           notifier.Config
+	Push               push.Config
+	EventBus           eventbus.Config
+	Analytics          analytics.Config
+	GeoIP              geoip.Config
+	Regions            regions.Config
+	Backups            backups.Config
+	Status             uptime.Config
+	Watchdog           watchdog.Config
+	AlertWebhookURL    string `validate:"omitempty,url"`
+	EmailDomainListURL string `validate:"omitempty,url"`
+}
+
+func loadServiceConfig(read *envReader) ServiceConfig {
+	replicationName, replicationSecret, _ := strings.Cut(read.String("REGION_REPLICATION_CLIENT", ""), ":")
+	return ServiceConfig{
+		WebAuthn: webauthn.Config{
+			RPID:    read.String("WEBAUTHN_RP_ID", webauthn.DefaultRPID),
+			RPName:  read.String("WEBAUTHN_RP_NAME", webauthn.DefaultRPName),
+			Origins: read.List("WEBAUTHN_ORIGINS", webauthn.DefaultOrigin),
+		},
+		OAuth: oauth.Config{
+			GoogleClientIds: read.List("GOOGLE_CLIENT_IDS"),
+			FacebookAppIds:  read.List("FACEBOOK_APP_IDS"),
+		},
+		Integrations: integrations.Config{
+			Spotify:   readCredentials(read, "SPOTIFY"),
+			Instagram: readCredentials(read, "INSTAGRAM"),
+		},
+		Storage: storage.Config{
+			Driver: strings.ToLower(read.String("STORAGE_DRIVER", storage.DefaultDriver)),
+			Local: storage.LocalConfig{
+				Dir:       read.String("STORAGE_LOCAL_DIR", storage.DefaultLocalDir),
+				PublicURL: read.String("STORAGE_PUBLIC_URL", storage.DefaultLocalPublicURL),
+			},
+			S3: storage.S3Config{
+				Bucket:          read.String("S3_BUCKET", ""),
+				Region:          read.String("S3_REGION", storage.DefaultRegion),
+				Endpoint:        read.String("S3_ENDPOINT", ""),
+				AccessKeyID:     read.String("S3_ACCESS_KEY_ID", ""),
+				SecretAccessKey: read.String("S3_SECRET_ACCESS_KEY", ""),
+				PublicURL:       read.String("S3_PUBLIC_URL", ""),
+			},
+			MinIO: storage.S3Config{
+				Bucket:          read.String("MINIO_BUCKET", ""),
+				Region:          read.String("MINIO_REGION", storage.DefaultRegion),
+				Endpoint:        read.String("MINIO_ENDPOINT", storage.DefaultMinIOEndpoint),
+				AccessKeyID:     read.String("MINIO_ACCESS_KEY", ""),
+				SecretAccessKey: read.String("MINIO_SECRET_KEY", ""),
+				PublicURL:       read.String("MINIO_PUBLIC_URL", ""),
+			},
+			GCS: storage.GCSConfig{
+				Bucket:          read.String("GCS_BUCKET", ""),
+				CredentialsFile: read.String("GCS_CREDENTIALS_FILE", ""),
+				PublicURL:       read.String("GCS_PUBLIC_URL", ""),
+			},
+			LifecycleRules: readLifecycleRules(read, "STORAGE_LIFECYCLE_RULES"),
+		},
+		Notifier: notifier.Config{
+			SMTPHost:     read.String("SMTP_HOST", ""),
+			SMTPPort:     read.Int("SMTP_PORT", notifier.DefaultSMTPPort),
+			SMTPUsername: read.String("SMTP_USERNAME", ""),
+			SMTPPassword: read.String("SMTP_PASSWORD", ""),
+			SMTPFrom:     read.String("SMTP_FROM", ""),
+			WebhookURL:   read.String("NOTIFIER_WEBHOOK_URL", ""),
+		},
+		Push: push.Config{
+			FCMProjectID:       read.String("FCM_PROJECT_ID", ""),
+			FCMCredentialsFile: read.String("FCM_CREDENTIALS_FILE", ""),
+			APNsKeyFile:        read.String("APNS_KEY_FILE", ""),
+			APNsKeyID:          read.String("APNS_KEY_ID", ""),
+			APNsTeamID:         read.String("APNS_TEAM_ID", ""),
+			APNsBundleID:       read.String("APNS_BUNDLE_ID", ""),
+			APNsSandbox:        read.Bool("APNS_SANDBOX", false),
+		},
+		EventBus: eventbus.Config{
+			Bus:               strings.ToLower(read.String("OUTBOX_BUS", eventbus.DefaultBus)),
+			Stream:            read.String("OUTBOX_REDIS_STREAM", eventbus.DefaultStream),
+			StreamMaxLen:      read.Int64("OUTBOX_REDIS_STREAM_MAXLEN", eventbus.DefaultStreamMaxLen),
+			KafkaRESTURL:      read.String("OUTBOX_KAFKA_REST_URL", ""),
+			KafkaTopic:        read.String("OUTBOX_KAFKA_TOPIC", eventbus.DefaultKafkaTopic),
+			KafkaRESTUsername: read.String("OUTBOX_KAFKA_REST_USERNAME", ""),
+			KafkaRESTPassword: read.String("OUTBOX_KAFKA_REST_PASSWORD", ""),
+		},
+		Analytics: analytics.Config{
+			Publisher:    strings.ToLower(read.String("ANALYTICS_PUBLISHER", "")),
+			Stream:       read.String("ANALYTICS_REDIS_STREAM", analytics.DefaultStream),
+			StreamMaxLen: read.Int64("ANALYTICS_REDIS_STREAM_MAXLEN", analytics.DefaultStreamMaxLen),
+		},
+		GeoIP: geoip.Config{
+			Provider:       strings.ToLower(read.String("GEOIP_PROVIDER", "")),
+			IPInfoToken:    read.String("IPINFO_TOKEN", ""),
+			BlockCountries: upper(read.List("GEO_BLOCK_COUNTRIES")),
+			FlagCountries:  upper(read.List("GEO_FLAG_COUNTRIES")),
+			BlockASNs:      readASNs(read, "GEO_BLOCK_ASNS"),
+			FlagASNs:       readASNs(read, "GEO_FLAG_ASNS"),
+			RouteOverrides: lowerValues(read.Pairs("GEO_ROUTE_OVERRIDES", "=")),
+		},
+		Regions: regions.Config{
+			Current:           read.String("REGION", ""),
+			Regions:           read.Pairs("REGIONS", "="),
+			Countries:         upperKeys(read.Pairs("REGION_COUNTRIES", "=")),
+			ReplicationName:   strings.TrimSpace(replicationName),
+			ReplicationSecret: strings.TrimSpace(replicationSecret),
+		},
+		Backups: backups.Config{
+			Dir:            read.String("BACKUP_DIR", ""),
+			MaxAge:         read.Duration("BACKUP_MAX_AGE_HOURS", backups.DefaultMaxAgeHours, time.Hour),
+			RestoreCommand: read.String("BACKUP_RESTORE_COMMAND", ""),
+			ScratchSchema:  read.String("BACKUP_SCRATCH_SCHEMA", ""),
+		},
+		Status: uptime.Config{
+			Interval:          read.Duration("STATUS_CHECK_INTERVAL_SECONDS", uptime.DefaultIntervalSeconds, time.Second),
+			Window:            read.Duration("STATUS_WINDOW_MINUTES", uptime.DefaultWindowMinutes, time.Minute),
+			DegradedErrorRate: read.Float("STATUS_DEGRADED_ERROR_RATE", uptime.DefaultDegradedErrorRate),
+		},
+		Watchdog: watchdog.Config{
+			Interval:            read.Duration("WATCHDOG_INTERVAL_SECONDS", watchdog.DefaultIntervalSeconds, time.Second),
+			MaxGoroutines:       read.Int("WATCHDOG_MAX_GOROUTINES", watchdog.DefaultMaxGoroutines),
+			MaxHeapBytes:        uint64(read.Int("WATCHDOG_MAX_HEAP_MB", watchdog.DefaultMaxHeapMB)) << 20,
+			MaxDBConnections:    read.Int("WATCHDOG_MAX_DB_CONNECTIONS", watchdog.DefaultMaxDBConnections),
+			MaxRedisConnections: read.Int("WATCHDOG_MAX_REDIS_CONNECTIONS", watchdog.DefaultMaxRedisConnections),
+			GrowthSamples:       read.Int("WATCHDOG_GROWTH_SAMPLES", watchdog.DefaultGrowthSamples),
+			PprofDir:            read.String("WATCHDOG_PPROF_DIR", ""),
+			DumpCooldown:        read.Duration("WATCHDOG_DUMP_COOLDOWN_MINUTES", watchdog.DefaultDumpCooldownMinutes, time.Minute),
+		},
+		AlertWebhookURL:    read.String("ALERT_WEBHOOK_URL", ""),
+		EmailDomainListURL: read.String("EMAIL_DOMAIN_LIST_URL", ""),
+	}
+}
+
+// readCredentials reads <PREFIX>_CLIENT_ID, <PREFIX>_CLIENT_SECRET and <PREFIX>_REDIRECT_URL
+func readCredentials(read *envReader, prefix string) integrations.Credentials {
+	return integrations.Credentials{
+		ClientID:     read.String(prefix+"_CLIENT_ID", ""),
+		ClientSecret: read.String(prefix+"_CLIENT_SECRET", ""),
+		RedirectURL:  read.String(prefix+"_REDIRECT_URL", ""),
+	}
+}
+
+func readASNs(read *envReader, key string) []uint32 {
+	var asns []uint32
+	for _, value := range read.List(key) {
+		asn, ok := geoip.ParseASN(value)
+		if !ok {
+			read.invalid = append(read.invalid, fmt.Sprintf("%s entry %q must be an ASN such as AS13335", key, value))
+			continue
+		}
+		asns = append(asns, asn)
+	}
+	return asns
+}
+
+func readLifecycleRules(read *envReader, key string) []storage.LifecycleRule {
+	rules, err := storage.ParseLifecycleRules(read.String(key, ""))
+	if err != nil {
+		read.invalid = append(read.invalid, fmt.Sprintf("%s %v", key, err))
+	}
+	return rules
+}
+
+func upper(values []string) []string {
+	for i, value := range values {
+		values[i] = strings.ToUpper(value)
+	}
+	return values
+}
+
+func upperKeys(pairs map[string]string) map[string]string {
+	upper := make(map[string]string, len(pairs))
+	for key, value := range pairs {
+		upper[strings.ToUpper(key)] = value
+	}
+	return upper
+}
+
+func lowerValues(pairs map[string]string) map[string]string {
+	for key, value := range pairs {
+		pairs[key] = strings.ToLower(value)
+	}
+	return pairs
+}
@@ -0,0 +1,150 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Golden stores one file per step of a journey, the status and the body with the values that change on every run
+// replaced by placeholders
+type Golden struct {
+	Dir    string
+	Update bool
+}
+
+// Check compares the response with the golden file of the step, or writes it with -update
+func (g *Golden) Check(journey string, step string, content []byte) error {
+	path := filepath.Join(g.Dir, journey, step+".golden.json")
+	if g.Update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(path, content, 0o644)
+	}
+
+	expected, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("%s: no golden file, record it with -update", path)
+	}
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(expected, content) {
+		return nil
+	}
+	return fmt.Errorf("%s: %s", path, firstDifference(string(expected), string(content)))
+}
+
+// Reset removes the golden files of the journey before -update writes them again, so the files of steps that were
+// renamed or removed do not stay behind
+func (g *Golden) Reset(journey string) error {
+	if !g.Update {
+		return nil
+	}
+	return os.RemoveAll(filepath.Join(g.Dir, journey))
+}
+
+// normalizer replaces what differs from run to run: request ids, times, tokens, the suffix of the usernames and
+// the ids, the ids the journey named are replaced by their name so a response pointing at the wrong account fails
+type normalizer struct {
+	suffix string
+	// names of the ids by the JSON key they are found under, e.g. account_id 12 is {bob.account_id}
+	names map[string]map[int64]string
+}
+
+func (n *normalizer) name(key string, id int64, name string) {
+	if n.names[key] == nil {
+		n.names[key] = map[int64]string{}
+	}
+	n.names[key][id] = "{" + name + "." + key + "}"
+}
+
+// Snapshot is the content of a golden file, 4 spaces indented like the other JSON files of the repository
+func (n *normalizer) Snapshot(status int, body []byte) []byte {
+	var decoded interface{}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		decoded = strings.TrimSpace(string(body))
+	}
+	content, _ := json.MarshalIndent(map[string]interface{}{
+		"status": status,
+		"body":   n.normalize("", decoded),
+	}, "", "    ")
+	return append(content, '\n')
+}
+
+func (n *normalizer) normalize(key string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			v[k] = n.normalize(k, item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = n.normalize(key, item)
+		}
+		return v
+	case string:
+		switch {
+		case v == "":
+			return v
+		case key == "request_id":
+			return "{request_id}"
+		case isIdKey(key):
+			// The data of a notification carries its ids as strings
+			if id, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return n.id(key, id)
+			}
+		case key == "password" || strings.HasSuffix(key, "token"):
+			return "{secret}"
+		case strings.HasSuffix(key, "_at") || strings.HasSuffix(key, "_on"):
+			return "{time}"
+		}
+		return strings.ReplaceAll(v, n.suffix, "{run}")
+	case float64:
+		if isIdKey(key) {
+			return n.id(key, int64(v))
+		}
+		return v
+	}
+	return value
+}
+
+// id is the name the journey gave the id under the key, e.g. sender_account_id 12 is {bob.account_id}
+func (n *normalizer) id(key string, id int64) string {
+	for named, ids := range n.names {
+		if name, ok := ids[id]; ok && strings.Contains(key, named) {
+			return name
+		}
+	}
+	return "{id}"
+}
+
+func isIdKey(key string) bool {
+	return key == "id" || strings.HasSuffix(key, "_id") || strings.HasPrefix(key, "account_id")
+}
+
+func firstDifference(expected string, actual string) string {
+	expectedLines := strings.Split(expected, "\n")
+	actualLines := strings.Split(actual, "\n")
+	for i := 0; i < len(expectedLines) || i < len(actualLines); i++ {
+		var want, got string
+		if i < len(expectedLines) {
+			want = expectedLines[i]
+		}
+		if i < len(actualLines) {
+			got = actualLines[i]
+		}
+		if want != got {
+			return fmt.Sprintf("line %d\n      want %s\n      got  %s", i+1, strings.TrimSpace(want), strings.TrimSpace(got))
+		}
+	}
+	return "differs"
+}
@@ -0,0 +1,237 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const apiPrefix = "/godating-dealls/api/v1"
+
+// Journey is a sequence of requests one or more users make, each step depends on the ones before
+type Journey struct {
+	Name  string
+	Steps func(r *Run)
+}
+
+// Run is one journey against the server, a step answering another status than expected ends it
+type Run struct {
+	t          *testing.T
+	journey    string
+	baseURL    string
+	adminKey   string
+	client     *http.Client
+	golden     *Golden
+	normalizer *normalizer
+	step       int
+}
+
+// Response is the decoded answer of a step, the accessors return the zero value for a missing field
+type Response struct {
+	Status int
+	Body   map[string]interface{}
+}
+
+// RunJourney runs the steps as t. The usernames get a suffix of the run so the journeys can run against a server
+// that already has data, e.g. with -base-url
+func RunJourney(t *testing.T, baseURL string, adminKey string, golden *Golden, journey Journey) {
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	r := &Run{
+		t:          t,
+		journey:    journey.Name,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		adminKey:   adminKey,
+		client:     &http.Client{Timeout: 30 * time.Second},
+		golden:     golden,
+		normalizer: &normalizer{suffix: hex.EncodeToString(suffix), names: map[string]map[int64]string{}},
+	}
+
+	if err := golden.Reset(journey.Name); err != nil {
+		t.Fatal(err)
+	}
+	journey.Steps(r)
+}
+
+// Unique returns the name with the suffix of the run, short enough for a username
+func (r *Run) Unique(name string) string {
+	return name + r.normalizer.suffix
+}
+
+// Name makes the id show up as {name.key} in the golden files instead of a number
+func (r *Run) Name(key string, id int64, name string) {
+	r.normalizer.name(key, id, name)
+}
+
+// Call sends the request and checks the status and the golden file of the step, token is empty for the public
+// routes. The steps are numbered so the golden files list in the order they ran
+func (r *Run) Call(step string, method string, path string, token string, body interface{}, status int) Response {
+	r.step++
+	name := fmt.Sprintf("%02d_%s", r.step, step)
+
+	header := http.Header{}
+	if token != "" {
+		header.Set("Authorization", "Bearer "+token)
+	}
+	statusCode, content, err := r.send(method, path, header, body)
+	if err != nil {
+		r.fail(name, "%v", err)
+	}
+
+	res := Response{Status: statusCode}
+	_ = json.Unmarshal(content, &res.Body)
+	if statusCode != status {
+		r.fail(name, "%s %s answered %d, want %d: %s", method, path, statusCode, status, strings.TrimSpace(string(content)))
+	}
+	if err := r.golden.Check(r.journey, name, r.normalizer.Snapshot(statusCode, content)); err != nil {
+		r.t.Error(err)
+	}
+	return res
+}
+
+// RunJob triggers a scheduled job through the admin api and waits until it finished, for the state the journeys
+// need that only a job creates, e.g. the daily swipe quota. The job has no golden file, its answer is not part of
+// what a user sees
+func (r *Run) RunJob(job string) {
+	r.step++
+	name := fmt.Sprintf("%02d_%s", r.step, job)
+	if r.adminKey == "" {
+		r.fail(name, "the journey runs the %s job, set -admin-key to the ADMIN_API_KEY of the server", job)
+	}
+	header := http.Header{}
+	header.Set("X-Admin-Key", r.adminKey)
+
+	// The job runs in the background of the trigger, it finished once its last run is another than before
+	before, err := r.jobStatus(header, job)
+	if err != nil {
+		r.fail(name, "%v", err)
+	}
+	statusCode, content, err := r.send(http.MethodPost, "/admin/jobs/"+job+"/trigger", header, nil)
+	if err != nil {
+		r.fail(name, "%v", err)
+	}
+	if statusCode != http.StatusOK {
+		r.fail(name, "triggering %s answered %d: %s", job, statusCode, strings.TrimSpace(string(content)))
+	}
+
+	deadline := time.Now().Add(30 * time.Second)
+	for time.Now().Before(deadline) {
+		status, err := r.jobStatus(header, job)
+		if err != nil {
+			r.fail(name, "%v", err)
+		}
+		if !status.Running && status.LastRunAt != "" && status.LastRunAt != before.LastRunAt {
+			if status.LastError != "" {
+				r.fail(name, "%s failed: %s", job, status.LastError)
+			}
+			return
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	r.fail(name, "%s did not finish within 30s", job)
+}
+
+type jobStatus struct {
+	Name      string `json:"name"`
+	Running   bool   `json:"running"`
+	LastRunAt string `json:"last_run_at"`
+	LastError string `json:"last_error"`
+}
+
+func (r *Run) jobStatus(header http.Header, job string) (jobStatus, error) {
+	statusCode, content, err := r.send(http.MethodGet, "/admin/jobs", header, nil)
+	if err != nil {
+		return jobStatus{}, err
+	}
+	if statusCode != http.StatusOK {
+		return jobStatus{}, fmt.Errorf("listing the jobs answered %d: %s", statusCode, strings.TrimSpace(string(content)))
+	}
+	var jobs struct {
+		Data []jobStatus `json:"data"`
+	}
+	if err := json.Unmarshal(content, &jobs); err != nil {
+		return jobStatus{}, fmt.Errorf("could not decode the jobs: %v", err)
+	}
+	for _, status := range jobs.Data {
+		if status.Name == job {
+			return status, nil
+		}
+	}
+	return jobStatus{}, fmt.Errorf("the server has no %s job", job)
+}
+
+// send makes one request to the api and returns the status and the body
+func (r *Run) send(method string, path string, header http.Header, body interface{}) (int, []byte, error) {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return 0, nil, fmt.Errorf("could not encode request: %v", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	req, err := http.NewRequest(method, r.baseURL+apiPrefix+path, reader)
+	if err != nil {
+		return 0, nil, fmt.Errorf("could not create request: %v", err)
+	}
+	req.Header = header
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("could not read response: %v", err)
+	}
+	return resp.StatusCode, content, nil
+}
+
+// fail ends the journey, the later steps would only fail because of this one
+func (r *Run) fail(step string, format string, args ...interface{}) {
+	r.t.Helper()
+	r.t.Fatalf("%s: %s", step, fmt.Sprintf(format, args...))
+}
+
+// Int returns the number at the path of the body, e.g. Int("data", "account_id"). A number in the path indexes an
+// array, e.g. Int("data", "0", "package_id")
+func (res Response) Int(path ...string) int64 {
+	number, _ := res.value(path).(float64)
+	return int64(number)
+}
+
+func (res Response) String(path ...string) string {
+	text, _ := res.value(path).(string)
+	return text
+}
+
+func (res Response) value(path []string) interface{} {
+	var current interface{} = res.Body
+	for _, key := range path {
+		switch container := current.(type) {
+		case map[string]interface{}:
+			current = container[key]
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(container) {
+				return nil
+			}
+			current = container[index]
+		default:
+			return nil
+		}
+	}
+	return current
+}
@@ -0,0 +1,134 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"testing"
+)
+
+const e2ePassword = "Journey1234"
+
+var (
+	backend  = flag.String("backend", backendSQLite, "what the booted server runs on, sqlite or containers (MySQL and Redis in docker)")
+	update   = flag.Bool("update", false, "write the responses to the golden files in testdata instead of comparing them")
+	baseURL  = flag.String("base-url", "", "test this running server instead of booting one, e.g. http://localhost:8000")
+	adminKey = flag.String("admin-key", os.Getenv("ADMIN_API_KEY"), "ADMIN_API_KEY of the -base-url server, the journeys trigger jobs with it")
+	keep     = flag.Bool("keep", false, "leave the booted server, its database and the containers running afterwards")
+)
+
+// TestMain boots the service once for all the journeys, unless -base-url points at one that is already running, that
+// server needs the packages of docs/sql/dml_records.sql to buy.
+// Run with: go test -tags e2e ./e2e [-run TestJourneys/session] [-update] [-backend sqlite|containers] [-base-url url -admin-key key] [-keep]
+func TestMain(m *testing.M) {
+	flag.Parse()
+	if *baseURL != "" {
+		os.Exit(m.Run())
+	}
+
+	server, err := StartServer(*backend)
+	if err != nil {
+		log.Fatalf("Failed to start the server: %v", err)
+	}
+	log.Printf("Server on %s (%s), log in %s", server.BaseURL, *backend, server.LogFile)
+	*baseURL, *adminKey = server.BaseURL, server.AdminKey
+
+	code := m.Run()
+	if !*keep {
+		server.Stop()
+	}
+	os.Exit(code)
+}
+
+// TestJourneys drives complete user journeys through the HTTP api the way a client would, and compares every
+// response with its golden file so a change in the answers shows up as a diff
+func TestJourneys(t *testing.T) {
+	golden := &Golden{Dir: "testdata", Update: *update}
+	for _, journey := range journeys() {
+		t.Run(journey.Name, func(t *testing.T) {
+			RunJourney(t, *baseURL, *adminKey, golden, journey)
+		})
+	}
+}
+
+func journeys() []Journey {
+	return []Journey{
+		{Name: "signup_match_chat", Steps: signupMatchChat},
+		{Name: "session", Steps: session},
+	}
+}
+
+// signupMatchChat two new users sign up, one of them gets verified, they like each other, chat and one of them
+// unmatches, the chat goes with the match
+func signupMatchChat(r *Run) {
+	alice := register(r, "alice")
+	bob := register(r, "bob")
+	aliceToken := login(r, "alice")
+	bobToken := login(r, "bob")
+	verify(r, "alice", aliceToken)
+	// A new account has no swipes until the daily reset gave it its quota
+	r.RunJob("daily_quota_reset")
+
+	r.Call("alice_likes_bob", http.MethodPost, "/swipes", aliceToken, map[string]interface{}{"action_type": "right", "account_id_swipe": bob}, http.StatusOK)
+	match := r.Call("bob_likes_alice", http.MethodPost, "/swipes", bobToken, map[string]interface{}{"action_type": "right", "account_id_swipe": alice}, http.StatusOK)
+	matchId := match.Int("data", "match_id")
+	r.Name("match_id", matchId, "alice_bob")
+
+	r.Call("alice_notifications", http.MethodGet, "/notifications", aliceToken, nil, http.StatusOK)
+	r.Call("alice_reads_notifications", http.MethodPut, "/notifications/read", aliceToken, map[string]interface{}{}, http.StatusOK)
+	r.Call("alice_matches", http.MethodGet, "/matches", aliceToken, nil, http.StatusOK)
+	sent := r.Call("alice_says_hi", http.MethodPost, fmt.Sprintf("/matches/%d/messages", matchId), aliceToken, map[string]string{"body": "Hi Bob!"}, http.StatusCreated)
+	r.Name("message_id", sent.Int("data", "message_id"), "hi")
+	r.Call("bob_reads", http.MethodGet, fmt.Sprintf("/matches/%d/messages", matchId), bobToken, nil, http.StatusOK)
+
+	r.Call("bob_unmatches", http.MethodDelete, fmt.Sprintf("/matches/%d", matchId), bobToken, nil, http.StatusOK)
+	r.Call("alice_cannot_send", http.MethodPost, fmt.Sprintf("/matches/%d/messages", matchId), aliceToken, map[string]string{"body": "Still there?"}, http.StatusForbidden)
+	r.Call("alice_matches_after_unmatch", http.MethodGet, "/matches", aliceToken, nil, http.StatusOK)
+}
+
+// session the errors of sign up and login, and a session that ends with the logout
+func session(r *Run) {
+	register(r, "carol")
+	// The server runs with ANTI_ENUMERATION=false, so the taken account is named instead of the generic error
+	r.Call("register_taken", http.MethodPost, "/authenticate/register", "", map[string]string{
+		"email": r.Unique("carol") + "@example.com", "username": r.Unique("carol"), "password": e2ePassword, "full_name": "Carol Journey",
+	}, http.StatusConflict)
+	r.Call("login_wrong_password", http.MethodPost, "/authenticate/login", "", map[string]string{"username": r.Unique("carol"), "password": "Wrong1234"}, http.StatusUnauthorized)
+
+	res := r.Call("login_carol", http.MethodPost, "/authenticate/login", "", map[string]string{"username": r.Unique("carol"), "password": e2ePassword}, http.StatusOK)
+	refreshed := r.Call("refresh", http.MethodPost, "/authenticate/refresh", "", map[string]string{"refresh_token": res.String("data", "refresh_token")}, http.StatusOK)
+	r.Call("logout", http.MethodPost, "/authenticate/logout", refreshed.String("data", "access_token"), nil, http.StatusOK)
+	// The access token stays valid until it expires, the logout revokes the session behind the refresh token
+	r.Call("refresh_after_logout", http.MethodPost, "/authenticate/refresh", "", map[string]string{"refresh_token": refreshed.String("data", "refresh_token")}, http.StatusUnauthorized)
+}
+
+// register signs up the user and names its account id after it
+func register(r *Run, name string) int64 {
+	res := r.Call("register_"+name, http.MethodPost, "/authenticate/register", "", map[string]string{
+		"email":     r.Unique(name) + "@example.com",
+		"username":  r.Unique(name),
+		"password":  e2ePassword,
+		"full_name": name + " Journey",
+	}, http.StatusCreated)
+	accountId := res.Int("data", "account_id")
+	r.Name("account_id", accountId, name)
+	return accountId
+}
+
+// verify buys the user the first package on offer, a premium account carries the verified badge
+func verify(r *Run, name string, token string) {
+	packages := r.Call(name+"_packages", http.MethodGet, "/packages", token, nil, http.StatusOK)
+	packageId := packages.Int("data", "0", "package_id")
+	r.Name("package_id", packageId, "first")
+	r.Call(name+"_buys_premium", http.MethodPost, "/premium/purchase", token, map[string]interface{}{"package_id": packageId}, http.StatusCreated)
+	r.Call(name+"_is_verified", http.MethodGet, "/premium/status", token, nil, http.StatusOK)
+}
+
+func login(r *Run, name string) string {
+	res := r.Call("login_"+name, http.MethodPost, "/authenticate/login", "", map[string]string{"username": r.Unique(name), "password": e2ePassword}, http.StatusOK)
+	return res.String("data", "access_token")
+}
@@ -0,0 +1,298 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"godating-dealls/internal/infra/sqlite"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	backendSQLite     = "sqlite"
+	backendContainers = "containers"
+
+	mysqlImage = "mysql:8.0"
+	redisImage = "redis:7-alpine"
+	// e2eSecret signs the tokens of the booted server, it never signs a real one
+	e2eSecret   = "end-to-end-only-secret-0123456789abcdef"
+	e2eAdminKey = "end-to-end-only-admin-key-0123456789"
+	e2eDBSecret = "e2e-password"
+	e2eDBName   = "godating_e2e"
+	// repositoryRoot is where the service is built and run from, go test runs in the directory of the package
+	repositoryRoot = ".."
+)
+
+// seedPackages are the packages of docs/sql/dml_records.sql, a migrated database has none to buy
+const seedPackages = `INSERT INTO packages
+(package_name, description, package_duration_in_monthly, price, unlimited_swipes, status)
+VALUES ('Basic Package', 'Access to basic features for one month', 1, 99999, 1, 1),
+       ('Standard Package', 'Access to standard features for three months', 3, 249999, 1, 1),
+       ('Premium Package', 'Access to all features including unlimited swipes for six months', 6, 499999, 1, 1)`
+
+// Server is the service booted for the journeys, with the containers it runs against
+type Server struct {
+	BaseURL    string
+	AdminKey   string
+	LogFile    string
+	backend    string
+	dir        string
+	cmd        *exec.Cmd
+	containers []string
+	mysql      string
+}
+
+// StartServer builds the service and runs it on a free port with a fresh database, it returns once the service
+// answers and the packages are seeded. The sqlite backend is a fresh sqlite file with the in-process redis, the
+// containers backend runs MySQL and Redis in docker. The server runs from the repository root since the development
+// mode reads the .env file there, the variables set here take precedence over it
+func StartServer(backend string) (*Server, error) {
+	dir, err := os.MkdirTemp("", "godating-e2e-")
+	if err != nil {
+		return nil, err
+	}
+	server := &Server{AdminKey: e2eAdminKey, backend: backend, dir: dir}
+
+	env := map[string]string{
+		"ENV":                         "development",
+		"JWT_SECRET":                  e2eSecret,
+		"ADMIN_API_KEY":               e2eAdminKey,
+		"MIGRATE_ON_STARTUP":          "true",
+		"ANTI_ENUMERATION":            "false",
+		"PII_SCRUBBING":               "false",
+		"AUTH_RATE_LIMIT_PER_MINUTE":  "10000",
+		"SWIPE_RATE_LIMIT_PER_MINUTE": "10000",
+		"EMAIL_DOMAIN_LIST_URL":       "",
+		"SHUTDOWN_TIMEOUT_SECONDS":    "1",
+		"TLS_CERT_FILE":               "",
+		"STORAGE_DRIVER":              "local",
+		"STORAGE_LOCAL_DIR":           filepath.Join(dir, "uploads"),
+	}
+	switch backend {
+	case backendSQLite:
+		env["DB_DRIVER"] = "sqlite"
+		env["DB_SQLITE_PATH"] = server.sqlitePath()
+		env["REDIS_DRIVER"] = "memory"
+	case backendContainers:
+		if err := server.startContainers(env); err != nil {
+			server.Stop()
+			return nil, err
+		}
+	default:
+		server.Stop()
+		return nil, fmt.Errorf("unknown backend %q, use %s or %s", backend, backendSQLite, backendContainers)
+	}
+
+	port, err := freePort()
+	if err != nil {
+		server.Stop()
+		return nil, err
+	}
+	env["SERVER_PORT"] = fmt.Sprint(port)
+	server.BaseURL = fmt.Sprintf("http://127.0.0.1:%d", port)
+
+	binary := filepath.Join(dir, "godating-e2e")
+	build := exec.Command("go", "build", "-o", binary, "./cmd")
+	build.Dir = repositoryRoot
+	build.Stdout, build.Stderr = os.Stdout, os.Stderr
+	if err := build.Run(); err != nil {
+		server.Stop()
+		return nil, fmt.Errorf("could not build the service: %v", err)
+	}
+
+	logFile, err := os.Create(filepath.Join(dir, "server.log"))
+	if err != nil {
+		server.Stop()
+		return nil, err
+	}
+	server.LogFile = logFile.Name()
+	server.cmd = exec.Command(binary)
+	server.cmd.Dir = repositoryRoot
+	server.cmd.Env = os.Environ()
+	for key, value := range env {
+		server.cmd.Env = append(server.cmd.Env, key+"="+value)
+	}
+	server.cmd.Stdout, server.cmd.Stderr = logFile, logFile
+	if err := server.cmd.Start(); err != nil {
+		server.Stop()
+		return nil, fmt.Errorf("could not start the service: %v", err)
+	}
+
+	if err := waitUntil(60*time.Second, server.ready); err != nil {
+		server.Stop()
+		return nil, fmt.Errorf("the service did not answer, see %s: %v", logFile.Name(), err)
+	}
+	// The migrations ran before the service answered
+	if err := server.exec(seedPackages); err != nil {
+		server.Stop()
+		return nil, fmt.Errorf("could not seed the packages: %v", err)
+	}
+	return server, nil
+}
+
+// Stop ends the service and removes the containers and the database
+func (s *Server) Stop() {
+	if s.cmd != nil && s.cmd.Process != nil {
+		_ = s.cmd.Process.Signal(os.Interrupt)
+		done := make(chan struct{})
+		go func() {
+			_ = s.cmd.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			_ = s.cmd.Process.Kill()
+		}
+	}
+	if len(s.containers) > 0 {
+		_ = exec.Command("docker", append([]string{"rm", "-f"}, s.containers...)...).Run()
+	}
+	_ = os.RemoveAll(s.dir)
+}
+
+// exec runs the statement on the database of the service, for the data no api creates
+func (s *Server) exec(statement string) error {
+	if s.backend == backendContainers {
+		out, err := exec.Command("docker", "exec", s.mysql, "mysql", "-uroot", "-p"+e2eDBSecret, e2eDBName, "-e", statement).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+
+	ctx := context.Background()
+	db, err := sqlite.Open(ctx, s.sqlitePath())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	_, err = db.ExecContext(ctx, statement)
+	return err
+}
+
+func (s *Server) sqlitePath() string {
+	return filepath.Join(s.dir, "godating-e2e.db")
+}
+
+// ready waits for the readiness probe, a schema the migrations left drifted fails the run with the report
+func (s *Server) ready() error {
+	resp, err := http.Get(s.BaseURL + apiPrefix + "/ready")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("readiness answered %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// startContainers runs MySQL and Redis on random host ports, the service migrates the empty database at startup
+func (s *Server) startContainers(env map[string]string) error {
+	mysql, err := s.runContainer(mysqlImage, "-e", "MYSQL_ROOT_PASSWORD="+e2eDBSecret, "-e", "MYSQL_DATABASE="+e2eDBName)
+	if err != nil {
+		return err
+	}
+	s.mysql = mysql
+	redis, err := s.runContainer(redisImage)
+	if err != nil {
+		return err
+	}
+
+	mysqlPort, err := containerPort(mysql, "3306/tcp")
+	if err != nil {
+		return err
+	}
+	redisPort, err := containerPort(redis, "6379/tcp")
+	if err != nil {
+		return err
+	}
+
+	// The image starts a server without networking to initialise the database first, TCP only answers afterwards
+	err = waitUntil(120*time.Second, func() error {
+		return exec.Command("docker", "exec", mysql, "mysqladmin", "ping", "--protocol=tcp", "-h127.0.0.1", "-uroot", "-p"+e2eDBSecret, "--silent").Run()
+	})
+	if err != nil {
+		return fmt.Errorf("mysql did not start: %v", err)
+	}
+	err = waitUntil(30*time.Second, func() error {
+		return exec.Command("docker", "exec", redis, "redis-cli", "ping").Run()
+	})
+	if err != nil {
+		return fmt.Errorf("redis did not start: %v", err)
+	}
+
+	env["DB_DRIVER"] = "mysql"
+	env["DB_HOST"] = "127.0.0.1"
+	env["DB_PORT"] = mysqlPort
+	env["DB_USER"] = "root"
+	env["DB_PASSWORD"] = e2eDBSecret
+	env["DB_NAME"] = e2eDBName
+	env["REDIS_DRIVER"] = "redis"
+	env["REDIS_HOST"] = "127.0.0.1"
+	env["REDIS_PORT"] = redisPort
+	env["REDIS_USER"] = ""
+	env["REDIS_PASSWORD"] = ""
+	return nil
+}
+
+func (s *Server) runContainer(image string, args ...string) (string, error) {
+	args = append(append([]string{"run", "-d", "-P"}, args...), image)
+	out, err := exec.Command("docker", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("could not run %s: %v", image, err)
+	}
+	id := strings.TrimSpace(string(out))
+	s.containers = append(s.containers, id)
+	return id, nil
+}
+
+// containerPort returns the host port docker published the container port on
+func containerPort(container string, port string) (string, error) {
+	out, err := exec.Command("docker", "port", container, port).Output()
+	if err != nil {
+		return "", fmt.Errorf("could not find the port of %s: %v", port, err)
+	}
+	// One line per address family, e.g. 0.0.0.0:49153
+	first := strings.SplitN(strings.TrimSpace(string(out)), "\n", 2)[0]
+	_, hostPort, err := net.SplitHostPort(first)
+	if err != nil {
+		return "", fmt.Errorf("unexpected docker port output %q", first)
+	}
+	return hostPort, nil
+}
+
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+func waitUntil(timeout time.Duration, check func() error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	for {
+		err := check()
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
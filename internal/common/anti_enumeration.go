@@ -0,0 +1,23 @@
+package common
+
+import (
+	"context"
+	"log"
+)
+
+// AntiEnumerationEnabled hides from clients whether an email or username exists, the detailed reason is only logged.
+// It is on unless ANTI_ENUMERATION is set to false
+func AntiEnumerationEnabled() bool {
+	return security.AntiEnumeration
+}
+
+// ConcealReason returns the generic error in anti-enumeration mode and logs the real reason with the request id,
+// with the mode off the real reason is returned as before
+func ConcealReason(ctx context.Context, generic error, reason error) error {
+	if !AntiEnumerationEnabled() {
+		return reason
+	}
+	requestID, _ := ctx.Value("request_id").(string)
+	log.Printf("Concealed %q (request %s): %v", generic.Error(), requestID, reason)
+	return generic
+}
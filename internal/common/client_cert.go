@@ -0,0 +1,45 @@
+package common
+
+import "net/http"
+
+// Roles a client certificate can be mapped to with MTLS_IDENTITIES
+const (
+	ClientCertRoleAdmin   = "admin"   // every admin endpoint
+	ClientCertRoleViewer  = "viewer"  // admin endpoints that only read
+	ClientCertRoleService = "service" // internal endpoints for sibling services
+)
+
+// clientCertEnforced is true once a client CA is configured, from then on the admin and internal routes need
+// a verified client certificate on top of their key or credentials
+func clientCertEnforced() bool {
+	return security.ClientCertRequired
+}
+
+// clientCertIdentity finds the first SAN of the verified client certificate listed in MTLS_IDENTITIES, a comma
+// separated list of san=role. A SAN is a DNS name, a URI such as spiffe://godating/ops/alice or an email address
+func clientCertIdentity(r *http.Request) (string, string, bool) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return "", "", false
+	}
+
+	leaf := r.TLS.VerifiedChains[0][0]
+	sans := append([]string{}, leaf.DNSNames...)
+	for _, uri := range leaf.URIs {
+		sans = append(sans, uri.String())
+	}
+	sans = append(sans, leaf.EmailAddresses...)
+	for _, san := range sans {
+		if role, ok := security.ClientCertRoles[san]; ok {
+			return san, role, true
+		}
+	}
+	return "", "", false
+}
+
+// clientCertRoleAllows tells whether the role may send the request to a route group guarded for wanted
+func clientCertRoleAllows(role string, wanted string, method string) bool {
+	if wanted == ClientCertRoleAdmin && role == ClientCertRoleViewer {
+		return method == http.MethodGet || method == http.MethodHead
+	}
+	return role == wanted
+}
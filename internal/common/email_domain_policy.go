@@ -0,0 +1,101 @@
+package common
+
+import (
+	"strings"
+	"sync"
+)
+
+// Email domain policy modes, deny refuses the listed domains (disposable providers), allow only accepts
+// the listed domains, e.g. during a soft launch
+const (
+	EmailDomainModeDeny  = "deny"
+	EmailDomainModeAllow = "allow"
+)
+
+// EmailDomainPolicy decides which email domains can sign up. The domains of the active mode come from
+// the configured rules and from a remote list swapped in whole by a background job
+type EmailDomainPolicy struct {
+	mu      sync.RWMutex
+	mode    string
+	allowed map[string]bool
+	denied  map[string]bool
+	remote  map[string]bool
+}
+
+// EmailDomainRules are the configured part of the policy, read by config.Load from EMAIL_DOMAIN_MODE,
+// EMAIL_ALLOW_DOMAINS and EMAIL_DENY_DOMAINS
+type EmailDomainRules struct {
+	Mode    string `validate:"oneof=deny allow"`
+	Allowed []string
+	Denied  []string
+}
+
+func NewEmailDomainPolicy(rules EmailDomainRules) *EmailDomainPolicy {
+	return &EmailDomainPolicy{
+		mode:    rules.Mode,
+		allowed: emailDomainSet(rules.Allowed),
+		denied:  emailDomainSet(rules.Denied),
+		remote:  map[string]bool{},
+	}
+}
+
+func (p *EmailDomainPolicy) Mode() string {
+	return p.mode
+}
+
+// ReplaceRemote swaps the remote list, it extends the allow list in allow mode and the deny list in deny mode
+func (p *EmailDomainPolicy) ReplaceRemote(domains []string) {
+	remote := emailDomainSet(domains)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.remote = remote
+}
+
+// Allows checks the domain of the email and its parent domains, so listing example.com covers mail.example.com.
+// In deny mode a domain on EMAIL_ALLOW_DOMAINS passes even when a remote list denies it
+func (p *EmailDomainPolicy) Allows(email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := normalizeEmailDomain(email[at+1:])
+	if domain == "" {
+		return false
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	listed := func(sets ...map[string]bool) bool {
+		for candidate := domain; ; {
+			for _, set := range sets {
+				if set[candidate] {
+					return true
+				}
+			}
+			dot := strings.Index(candidate, ".")
+			if dot < 0 {
+				return false
+			}
+			candidate = candidate[dot+1:]
+		}
+	}
+
+	if p.mode == EmailDomainModeAllow {
+		return listed(p.allowed, p.remote)
+	}
+	return listed(p.allowed) || !listed(p.denied, p.remote)
+}
+
+func emailDomainSet(domains []string) map[string]bool {
+	set := map[string]bool{}
+	for _, domain := range domains {
+		if domain = normalizeEmailDomain(domain); domain != "" {
+			set[domain] = true
+		}
+	}
+	return set
+}
+
+func normalizeEmailDomain(domain string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+}
@@ -3,6 +3,8 @@ package common
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"strings"
+	"unicode"
 )
 
 func StringEncoder(input string) string {
@@ -11,3 +13,16 @@ func StringEncoder(input string) string {
 	hashedBytes := hash.Sum(nil)
 	return hex.EncodeToString(hashedBytes)
 }
+
+// PhoneNumberEncoder hashes a phone number after stripping everything except digits and a leading plus,
+// clients must apply the same normalization before hashing their contacts
+func PhoneNumberEncoder(phone string) string {
+	phone = strings.TrimSpace(phone)
+	var normalized strings.Builder
+	for i, r := range phone {
+		if unicode.IsDigit(r) || (i == 0 && r == '+') {
+			normalized.WriteRune(r)
+		}
+	}
+	return StringEncoder(normalized.String())
+}
@@ -0,0 +1,129 @@
+package common
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const (
+	ExportFormatNDJSON = "ndjson"
+	ExportFormatCSV    = "csv"
+)
+
+// exportFlushRows is how many rows are buffered before they are pushed to the client
+const exportFlushRows = 100
+
+var ErrInvalidExportFormat = fmt.Errorf("invalid export format, use %s or %s", ExportFormatNDJSON, ExportFormatCSV)
+
+// ExportWriter streams rows to the client as they are read, NDJSON writes one JSON object per line and CSV one record
+// per line after the header. Nothing is written before the first row, so a failure before it can still answer with an
+// error envelope
+type ExportWriter struct {
+	w        http.ResponseWriter
+	format   string
+	filename string
+	header   []string
+	csv      *csv.Writer
+	json     *json.Encoder
+	flusher  http.Flusher
+	rows     int
+	started  bool
+}
+
+// NewExportWriter format defaults to NDJSON, filename has no extension and header is the CSV header
+func NewExportWriter(w http.ResponseWriter, format string, filename string, header []string) (*ExportWriter, error) {
+	if format == "" {
+		format = ExportFormatNDJSON
+	}
+	if format != ExportFormatNDJSON && format != ExportFormatCSV {
+		return nil, ErrInvalidExportFormat
+	}
+	flusher, _ := w.(http.Flusher)
+	return &ExportWriter{w: w, format: format, filename: filename, header: header, flusher: flusher}, nil
+}
+
+// WriteRow writes value as NDJSON or record as CSV, an error means the client is gone and the export should stop
+func (e *ExportWriter) WriteRow(value interface{}, record []string) error {
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	var err error
+	if e.format == ExportFormatCSV {
+		err = e.csv.Write(record)
+	} else {
+		err = e.json.Encode(value)
+	}
+	if err != nil {
+		return fmt.Errorf("could not write export row: %v", err)
+	}
+
+	e.rows++
+	if e.rows%exportFlushRows == 0 {
+		return e.flush()
+	}
+	return nil
+}
+
+func (e *ExportWriter) Started() bool {
+	return e.started
+}
+
+// Close finishes a complete export, an export without rows is still answered with the headers
+func (e *ExportWriter) Close() error {
+	if err := e.start(); err != nil {
+		return err
+	}
+	return e.flush()
+}
+
+// Abort ends an export that failed after rows were sent. The status is already written, so the connection is cut
+// instead of finishing the response and the client sees a truncated download rather than a complete looking file
+func (e *ExportWriter) Abort(err error) {
+	if !e.started {
+		HandleEnvelopeError(err, e.w)
+		return
+	}
+	panic(http.ErrAbortHandler)
+}
+
+func (e *ExportWriter) start() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+
+	contentType := "application/x-ndjson"
+	if e.format == ExportFormatCSV {
+		contentType = "text/csv; charset=utf-8"
+	}
+	e.w.Header().Set("Content-Type", contentType)
+	e.w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, e.filename, e.format))
+	e.w.Header().Set("Cache-Control", "no-store")
+	e.w.WriteHeader(http.StatusOK)
+
+	if e.format == ExportFormatCSV {
+		e.csv = csv.NewWriter(e.w)
+		if err := e.csv.Write(e.header); err != nil {
+			return fmt.Errorf("could not write export header: %v", err)
+		}
+		return nil
+	}
+	e.json = json.NewEncoder(e.w)
+	return nil
+}
+
+func (e *ExportWriter) flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return fmt.Errorf("could not write export rows: %v", err)
+		}
+	}
+	if e.flusher != nil {
+		e.flusher.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"time"
+)
+
+const (
+	FaultTargetDB    = "db"
+	FaultTargetRedis = "redis"
+)
+
+var ErrInjectedFault = errors.New("injected fault")
+
+// FaultInjectionConfig stays off unless FAULT_INJECTION_ENABLED=true and never runs in production
+type FaultInjectionConfig struct {
+	Enabled   bool
+	Latency   time.Duration   `validate:"min=0"`
+	ErrorRate float64         `validate:"gte=0,lte=1"`
+	Targets   map[string]bool `validate:"dive,keys,oneof=db redis,endkeys"`
+}
+
+var faultConfig FaultInjectionConfig
+
+// SetFaultInjectionConfig is called once at startup before serving
+func SetFaultInjectionConfig(config FaultInjectionConfig) {
+	faultConfig = config
+	if faultConfig.Enabled {
+		log.Printf("Fault injection enabled: latency=%s error_rate=%.2f targets=%v",
+			faultConfig.Latency, faultConfig.ErrorRate, faultConfig.Targets)
+	}
+}
+
+func LoadFaultInjectionConfig() FaultInjectionConfig {
+	return faultConfig
+}
+
+// InjectFault delays and possibly fails a call to the target, a cancelled context ends the delay early
+func InjectFault(ctx context.Context, target string) error {
+	config := LoadFaultInjectionConfig()
+	if !config.Enabled || !config.Targets[target] {
+		return nil
+	}
+
+	if config.Latency > 0 {
+		timer := time.NewTimer(config.Latency)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if config.ErrorRate > 0 && rand.Float64() < config.ErrorRate {
+		return errors.Join(ErrInjectedFault, errors.New(target+" call failed"))
+	}
+	return nil
+}
@@ -55,6 +55,9 @@ func SetupLogger() (*os.File, error) {
 	// Create a multi writer to write logs to both file and stdout
 	mw := io.MultiWriter(os.Stdout, logFile)
 	// jsonWriter := jsonLogWriter(mw)
+	if PIIScrubbingEnabled() {
+		mw = NewPIIScrubWriter(mw)
+	}
 	log.SetOutput(mw)
 
 	// Set prefix for log messages
@@ -126,6 +129,9 @@ func rotateLogFile(logFileName string) error {
 
 // PrintJSON takes any data structure and prints it in JSON format
 func PrintJSON(message string, v interface{}) {
+	if PIIScrubbingEnabled() {
+		v = ScrubPII(v)
+	}
 	jsonData, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		log.Printf(err.Error())
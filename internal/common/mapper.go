@@ -0,0 +1,155 @@
+package common
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Mapping copies struct fields from a source type to a destination type by field name.
+// A destination field can name its source with the `map:"SourceField"` tag or opt out with `map:"-"`.
+// The field plan is checked once when the mapping is declared: every destination field must have a source
+// and every source field must land somewhere or be listed as ignored, so adding a field to either side
+// without mapping it panics at startup instead of being silently dropped.
+type Mapping struct {
+	src    reflect.Type
+	dst    reflect.Type
+	fields []fieldMapping
+}
+
+type fieldMapping struct {
+	src []int
+	dst []int
+}
+
+// NewMapping builds the mapping from src to dst, both are zero values of the struct types
+func NewMapping(dst interface{}, src interface{}, ignore ...string) *Mapping {
+	dstType := reflect.TypeOf(dst)
+	srcType := reflect.TypeOf(src)
+	if dstType.Kind() != reflect.Struct || srcType.Kind() != reflect.Struct {
+		panic("mapping: source and destination must be structs")
+	}
+
+	srcFields := map[string]reflect.StructField{}
+	for _, field := range reflect.VisibleFields(srcType) {
+		if field.Anonymous || !field.IsExported() {
+			continue
+		}
+		srcFields[field.Name] = field
+	}
+
+	unused := map[string]bool{}
+	for name := range srcFields {
+		unused[name] = true
+	}
+	for _, name := range ignore {
+		if _, ok := srcFields[name]; !ok {
+			panic(fmt.Sprintf("mapping %s -> %s: ignored field %s does not exist", srcType, dstType, name))
+		}
+		delete(unused, name)
+	}
+
+	m := &Mapping{src: srcType, dst: dstType}
+	var problems []string
+	for _, field := range reflect.VisibleFields(dstType) {
+		if field.Anonymous || !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		if tag := field.Tag.Get("map"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			name = tag
+		}
+
+		srcField, ok := srcFields[name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s has no source field %s", field.Name, name))
+			continue
+		}
+		if !assignable(srcField.Type, field.Type) {
+			problems = append(problems, fmt.Sprintf("%s cannot be set from %s (%s to %s)", field.Name, name, srcField.Type, field.Type))
+			continue
+		}
+		delete(unused, name)
+		m.fields = append(m.fields, fieldMapping{src: srcField.Index, dst: field.Index})
+	}
+	var unmapped []string
+	for name := range unused {
+		unmapped = append(unmapped, name)
+	}
+	sort.Strings(unmapped)
+	for _, name := range unmapped {
+		problems = append(problems, fmt.Sprintf("source field %s is not mapped or ignored", name))
+	}
+
+	if len(problems) > 0 {
+		panic(fmt.Sprintf("mapping %s -> %s: %s", srcType, dstType, strings.Join(problems, "; ")))
+	}
+	return m
+}
+
+// Map copies src into dst, dst is a pointer to the destination type and src a value or pointer of the source type
+func (m *Mapping) Map(dst interface{}, src interface{}) {
+	dstValue := reflect.ValueOf(dst)
+	if dstValue.Kind() != reflect.Pointer || dstValue.Elem().Type() != m.dst {
+		panic(fmt.Sprintf("mapping: destination must be *%s", m.dst))
+	}
+	srcValue := reflect.Indirect(reflect.ValueOf(src))
+	if srcValue.Type() != m.src {
+		panic(fmt.Sprintf("mapping: source must be %s", m.src))
+	}
+
+	dstValue = dstValue.Elem()
+	for _, field := range m.fields {
+		setField(dstValue.FieldByIndex(field.dst), srcValue.FieldByIndex(field.src))
+	}
+}
+
+// assignable allows the same type, a value to or from a pointer of it, numeric to numeric and string to string
+func assignable(from reflect.Type, to reflect.Type) bool {
+	switch {
+	case from == to:
+		return true
+	case from.Kind() == reflect.Pointer && from.Elem() == to:
+		return true
+	case to.Kind() == reflect.Pointer && to.Elem() == from:
+		return true
+	case isNumeric(from) && isNumeric(to):
+		return true
+	case from.Kind() == reflect.String && to.Kind() == reflect.String:
+		return true
+	}
+	return false
+}
+
+func setField(dst reflect.Value, src reflect.Value) {
+	switch {
+	case src.Type() == dst.Type():
+		dst.Set(src)
+	case src.Kind() == reflect.Pointer:
+		// A nil pointer leaves the zero value
+		if !src.IsNil() {
+			dst.Set(src.Elem())
+		}
+	case dst.Kind() == reflect.Pointer:
+		// Point to a copy so the destination never aliases the source
+		ptr := reflect.New(src.Type())
+		ptr.Elem().Set(src)
+		dst.Set(ptr)
+	default:
+		dst.Set(src.Convert(dst.Type()))
+	}
+}
+
+func isNumeric(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,206 @@
+package common
+
+import (
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/record"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// roundTrip maps src with m and carries the result back to the source type along the same field plan, so every
+// field the mapping knows about has to survive both ways
+func roundTrip(m *Mapping, src interface{}) (interface{}, interface{}) {
+	dst := reflect.New(m.dst)
+	m.Map(dst.Interface(), src)
+
+	back := reflect.New(m.src).Elem()
+	for _, field := range m.fields {
+		setField(back.FieldByIndex(field.src), dst.Elem().FieldByIndex(field.dst))
+	}
+	return dst.Elem().Interface(), back.Interface()
+}
+
+// zeroFields returns value with the named fields set to their zero value
+func zeroFields(value interface{}, names ...string) interface{} {
+	copied := reflect.New(reflect.TypeOf(value)).Elem()
+	copied.Set(reflect.ValueOf(value))
+	for _, name := range names {
+		field := copied.FieldByName(name)
+		field.Set(reflect.Zero(field.Type()))
+	}
+	return copied.Interface()
+}
+
+func TestMappingRoundTrip(t *testing.T) {
+	// A location other than UTC and a nanosecond part catch a time that is rebuilt instead of copied
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	created := time.Date(2024, 6, 10, 8, 30, 15, 123456789, jakarta)
+	updated := time.Date(2024, 6, 11, 9, 45, 0, 987654321, jakarta)
+	dateOfBirth := time.Date(1995, 2, 14, 0, 0, 0, 0, time.UTC)
+	fullName := "Andreas Iniesta"
+	phoneHash := "5f4dcc3b5aa765d61d8327deb882cf99"
+	latitude, longitude, distance := -6.2, 106.8, 4.5
+
+	account := record.AccountRecord{
+		AccountID:    7,
+		Username:     "andreas.iniesta",
+		PasswordHash: "$2a$10$hash",
+		Email:        "andreas@godating.local",
+		Verified:     true,
+		CreatedAt:    created,
+		UpdatedAt:    updated,
+	}
+	user := record.UserRecord{
+		UserID:      3,
+		AccountID:   7,
+		FullName:    &fullName,
+		DateOfBirth: &dateOfBirth,
+		Age:         29,
+		Gender:      "L",
+		Address:     "Jakarta",
+		Bio:         "Football and coffee",
+		PhoneHash:   &phoneHash,
+		Latitude:    &latitude,
+		Longitude:   &longitude,
+		CreatedAt:   created,
+		UpdatedAt:   updated,
+	}
+	nullUser := user
+	nullUser.FullName, nullUser.DateOfBirth, nullUser.PhoneHash, nullUser.Latitude, nullUser.Longitude = nil, nil, nil, nil, nil
+
+	tests := []struct {
+		name    string
+		mapping *Mapping
+		record  interface{}
+		// ignored are the record fields the mapping leaves out on purpose, they do not come back
+		ignored []string
+		// nulls are the record fields left unset on purpose, every other field must be set by the case
+		nulls []string
+		want  interface{}
+	}{
+		{
+			name:    "account",
+			mapping: NewMapping(domain.Accounts{}, record.AccountRecord{}, "Verified"),
+			record:  account,
+			ignored: []string{"Verified"},
+			want: domain.Accounts{
+				AccountId: 7,
+				Email:     "andreas@godating.local",
+				Username:  "andreas.iniesta",
+				Password:  "$2a$10$hash",
+				CreateAt:  created,
+				UpdateAt:  updated,
+			},
+		},
+		{
+			name:    "account detail",
+			mapping: NewMapping(domain.AccountDetail{}, record.AccountRecord{}, "PasswordHash", "CreatedAt", "UpdatedAt"),
+			record:  account,
+			ignored: []string{"PasswordHash", "CreatedAt", "UpdatedAt"},
+			want: domain.AccountDetail{
+				AccountId: 7,
+				Email:     "andreas@godating.local",
+				Username:  "andreas.iniesta",
+				Verified:  true,
+			},
+		},
+		{
+			name:    "user",
+			mapping: NewMapping(domain.Users{}, record.UserRecord{}, "PhoneHash", "Latitude", "Longitude"),
+			record:  user,
+			ignored: []string{"PhoneHash", "Latitude", "Longitude"},
+			want: domain.Users{
+				UserID:      3,
+				AccountID:   7,
+				FullName:    &fullName,
+				DateOfBirth: &dateOfBirth,
+				Age:         29,
+				Gender:      "L",
+				Address:     "Jakarta",
+				Bio:         "Football and coffee",
+				CreatedAt:   created,
+				UpdatedAt:   updated,
+			},
+		},
+		{
+			name:    "user with null columns",
+			mapping: NewMapping(domain.Users{}, record.UserRecord{}, "PhoneHash", "Latitude", "Longitude"),
+			record:  nullUser,
+			ignored: []string{"PhoneHash", "Latitude", "Longitude"},
+			nulls:   []string{"FullName", "DateOfBirth", "PhoneHash", "Latitude", "Longitude"},
+			want: domain.Users{
+				UserID:    3,
+				AccountID: 7,
+				Age:       29,
+				Gender:    "L",
+				Address:   "Jakarta",
+				Bio:       "Football and coffee",
+				CreatedAt: created,
+				UpdatedAt: updated,
+			},
+		},
+		{
+			name:    "user view",
+			mapping: NewMapping(domain.AllUserViews{}, record.UserAccountRecord{}, "DateOfBirth", "PhoneHash", "Latitude", "Longitude", "CreatedAt", "UpdatedAt"),
+			record:  record.UserAccountRecord{UserRecord: user, Verified: true, Username: "andreas.iniesta", DistanceKm: &distance},
+			ignored: []string{"DateOfBirth", "PhoneHash", "Latitude", "Longitude", "CreatedAt", "UpdatedAt"},
+			want: domain.AllUserViews{
+				UserID:     3,
+				AccountID:  7,
+				FullName:   &fullName,
+				Username:   "andreas.iniesta",
+				Age:        29,
+				Gender:     "L",
+				Address:    "Jakarta",
+				Bio:        "Football and coffee",
+				Verified:   true,
+				DistanceKm: &distance,
+			},
+		},
+		{
+			name:    "user view without distance",
+			mapping: NewMapping(domain.AllUserViews{}, record.UserAccountRecord{}, "DateOfBirth", "PhoneHash", "Latitude", "Longitude", "CreatedAt", "UpdatedAt"),
+			record:  record.UserAccountRecord{UserRecord: nullUser, Verified: true, Username: "andreas.iniesta"},
+			ignored: []string{"DateOfBirth", "PhoneHash", "Latitude", "Longitude", "CreatedAt", "UpdatedAt"},
+			nulls:   []string{"FullName", "DateOfBirth", "PhoneHash", "Latitude", "Longitude", "DistanceKm"},
+			want: domain.AllUserViews{
+				UserID:    3,
+				AccountID: 7,
+				Username:  "andreas.iniesta",
+				Age:       29,
+				Gender:    "L",
+				Address:   "Jakarta",
+				Bio:       "Football and coffee",
+				Verified:  true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A field added to the record without a value here would pass unnoticed, whether mapped or not
+			nulls := map[string]bool{}
+			for _, name := range tt.nulls {
+				nulls[name] = true
+			}
+			value := reflect.ValueOf(tt.record)
+			for _, field := range reflect.VisibleFields(value.Type()) {
+				if field.Anonymous {
+					continue
+				}
+				if zero := value.FieldByIndex(field.Index).IsZero(); zero != nulls[field.Name] {
+					t.Fatalf("record field %s is zero: %v, listed as null: %v", field.Name, zero, nulls[field.Name])
+				}
+			}
+
+			got, back := roundTrip(tt.mapping, tt.record)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mapped\n got %+v\nwant %+v", got, tt.want)
+			}
+			if want := zeroFields(tt.record, tt.ignored...); !reflect.DeepEqual(back, want) {
+				t.Errorf("mapped back\n got %+v\nwant %+v", back, want)
+			}
+		})
+	}
+}
@@ -2,32 +2,98 @@ package common
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
 	"net/http"
-	"strings"
+	"regexp"
 )
 
-func AuthMiddleware(next http.Handler) http.Handler {
+const RequestIDHeader = "X-Request-ID"
+
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestIDMiddleware keeps a well formed incoming X-Request-ID or generates one, it is echoed on the response
+// before the handler runs so response writers and logs can pick it up
+func RequestIDMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			WriteJSONResponse(w, http.StatusUnauthorized, "Missing Authorization header", map[string]string{
-				"message": "Missing Authorization header",
-			}, 1)
-			return
+		requestID := r.Header.Get(RequestIDHeader)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = newRequestID()
 		}
+		w.Header().Set(RequestIDHeader, requestID)
+		ctx := context.WithValue(r.Context(), "request_id", requestID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
 
-		if !strings.HasPrefix(authHeader, "Bearer ") {
-			WriteJSONResponse(w, http.StatusUnauthorized, "Invalid Authorization header format", map[string]string{
-				"message": "Invalid Authorization header format",
-			}, 1)
+// AdminMiddleware guards trust-and-safety endpoints with the shared ADMIN_API_KEY, nothing passes when it is unset.
+// When mTLS is configured the client certificate must also map to the admin role, or to viewer for reads, and its
+// identity goes in the context as the admin identity
+func AdminMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		adminKey := security.AdminAPIKey
+		requestKey := r.Header.Get("X-Admin-Key")
+		if adminKey == "" || subtle.ConstantTimeCompare([]byte(requestKey), []byte(adminKey)) != 1 {
+			WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_admin_key", "Invalid admin key")
 			return
 		}
 
-		token := strings.TrimPrefix(authHeader, "Bearer ")
+		if clientCertEnforced() {
+			identity, role, ok := clientCertIdentity(r)
+			if !ok {
+				WriteEnvelopeError(w, http.StatusUnauthorized, "client_certificate_required", "Client certificate required")
+				return
+			}
+			if !clientCertRoleAllows(role, ClientCertRoleAdmin, r.Method) {
+				WriteEnvelopeError(w, http.StatusForbidden, "invalid_client_certificate", "Client certificate not allowed")
+				return
+			}
+			r = r.WithContext(context.WithValue(r.Context(), "admin_identity", identity))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
 
-		// Here you can add additional validation of the token, e.g., verifying JWT
-		// For now, we'll just pass the token along
-		ctx := context.WithValue(r.Context(), "token", token)
+// ServiceClientMiddleware guards endpoints for sibling services. A service authenticates with HTTP basic auth against
+// SERVICE_CLIENTS, a comma separated list of name:secret, nothing passes when it is unset. The name goes in the context.
+// When mTLS is configured the client certificate must also map to the service role
+func ServiceClientMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, secret, ok := r.BasicAuth()
+		if !ok || !serviceClientAllowed(name, secret) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="godating-dealls"`)
+			WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_client", "Invalid service client credentials")
+			return
+		}
+		if clientCertEnforced() {
+			_, role, ok := clientCertIdentity(r)
+			if !ok || !clientCertRoleAllows(role, ClientCertRoleService, r.Method) {
+				WriteEnvelopeError(w, http.StatusForbidden, "invalid_client_certificate", "Client certificate not allowed")
+				return
+			}
+		}
+		ctx := context.WithValue(r.Context(), "service_client", name)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+func serviceClientAllowed(name string, secret string) bool {
+	allowed := false
+	for clientName, clientSecret := range security.ServiceClients {
+		// Every entry is compared so the time taken does not tell which client names exist
+		if subtle.ConstantTimeCompare([]byte(name), []byte(clientName)) == 1 &&
+			subtle.ConstantTimeCompare([]byte(secret), []byte(clientSecret)) == 1 {
+			allowed = true
+		}
+	}
+	return allowed
+}
@@ -0,0 +1,142 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// Log levels from the most to the least verbose, a logger writes the messages at its level and above
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+var logLevelOrder = []string{LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError}
+
+var (
+	ErrUnknownLogModule = errors.New("unknown log module")
+	ErrInvalidLogLevel  = fmt.Errorf("invalid log level, use one of %s", strings.Join(logLevelOrder, ", "))
+)
+
+// Module loggers, each level is adjusted on its own at runtime so one subsystem can be debugged
+// in production without the others flooding the log
+var (
+	AuthLog      = newModuleLogger("auth")
+	QuotaLog     = newModuleLogger("quota")
+	MessagingLog = newModuleLogger("messaging")
+	PushLog      = newModuleLogger("push")
+	RepoLog      = newModuleLogger("repo")
+	WatchdogLog  = newModuleLogger("watchdog")
+)
+
+var moduleLoggers = map[string]*ModuleLogger{}
+
+type ModuleLogger struct {
+	module string
+	level  atomic.Int32
+}
+
+func newModuleLogger(module string) *ModuleLogger {
+	logger := &ModuleLogger{module: module}
+	logger.level.Store(logLevelIndex(LogLevelInfo))
+	moduleLoggers[module] = logger
+	return logger
+}
+
+func (m *ModuleLogger) Enabled(level string) bool {
+	return logLevelIndex(level) >= m.level.Load()
+}
+
+func (m *ModuleLogger) Debugf(format string, args ...interface{}) {
+	m.printf(LogLevelDebug, format, args...)
+}
+
+func (m *ModuleLogger) Infof(format string, args ...interface{}) {
+	m.printf(LogLevelInfo, format, args...)
+}
+
+func (m *ModuleLogger) Warnf(format string, args ...interface{}) {
+	m.printf(LogLevelWarn, format, args...)
+}
+
+func (m *ModuleLogger) Errorf(format string, args ...interface{}) {
+	m.printf(LogLevelError, format, args...)
+}
+
+// DebugJSON is PrintJSON at debug level, the value is only encoded when it is written
+func (m *ModuleLogger) DebugJSON(message string, v interface{}) {
+	if m.Enabled(LogLevelDebug) {
+		PrintJSON(fmt.Sprintf("[%s] DEBUG %s", m.module, message), v)
+	}
+}
+
+func (m *ModuleLogger) printf(level string, format string, args ...interface{}) {
+	if m.Enabled(level) {
+		log.Printf("[%s] %s %s", m.module, strings.ToUpper(level), fmt.Sprintf(format, args...))
+	}
+}
+
+// SetLogLevel changes the level of one module logger, for this instance until it restarts
+func SetLogLevel(module string, level string) error {
+	logger, ok := moduleLoggers[module]
+	if !ok {
+		return ErrUnknownLogModule
+	}
+	if !ValidLogLevel(level) {
+		return ErrInvalidLogLevel
+	}
+	logger.level.Store(logLevelIndex(level))
+	return nil
+}
+
+// SetLogLevels sets every module logger to defaultLevel, then applies the per module levels
+func SetLogLevels(defaultLevel string, moduleLevels map[string]string) error {
+	for module := range moduleLoggers {
+		if err := SetLogLevel(module, defaultLevel); err != nil {
+			return err
+		}
+	}
+	for module, level := range moduleLevels {
+		if err := SetLogLevel(module, level); err != nil {
+			return fmt.Errorf("%w: %s", err, module)
+		}
+	}
+	return nil
+}
+
+// LogLevels returns the level of every module logger
+func LogLevels() map[string]string {
+	levels := map[string]string{}
+	for module, logger := range moduleLoggers {
+		levels[module] = logLevelOrder[logger.level.Load()]
+	}
+	return levels
+}
+
+func LogModules() []string {
+	modules := make([]string, 0, len(moduleLoggers))
+	for module := range moduleLoggers {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+	return modules
+}
+
+func ValidLogLevel(level string) bool {
+	return logLevelIndex(level) >= 0
+}
+
+func logLevelIndex(level string) int32 {
+	for i, candidate := range logLevelOrder {
+		if candidate == level {
+			return int32(i)
+		}
+	}
+	return -1
+}
@@ -0,0 +1,164 @@
+package common
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync"
+)
+
+// Network rule lists, a deny rule blocks the addresses it covers, allow rules admit only the addresses they cover
+const (
+	NetworkListDeny  = "deny"
+	NetworkListAllow = "allow"
+)
+
+// Route groups of network rules, global rules apply to every request and can only deny
+const (
+	NetworkGroupGlobal   = "global"
+	NetworkGroupAdmin    = "admin"
+	NetworkGroupMetrics  = "metrics"
+	NetworkGroupInternal = "internal"
+)
+
+// networkGroupPaths maps path prefixes to route groups, the most specific prefix first
+var networkGroupPaths = []struct {
+	prefix string
+	groups []string
+}{
+	{"/godating-dealls/api/v1/admin/metrics/", []string{NetworkGroupMetrics, NetworkGroupAdmin}},
+	{"/godating-dealls/api/v1/admin/", []string{NetworkGroupAdmin}},
+	{"/godating-dealls/api/v1/oauth/", []string{NetworkGroupInternal}},
+	{"/debug/pprof/", []string{NetworkGroupAdmin}},
+}
+
+type NetworkRule struct {
+	List   string
+	Group  string
+	Prefix netip.Prefix
+}
+
+// NetworkACL keeps the network rules in memory, the rules live in the database and are swapped in whole
+type NetworkACL struct {
+	mu    sync.RWMutex
+	rules []NetworkRule
+}
+
+func NewNetworkACL() *NetworkACL {
+	return &NetworkACL{}
+}
+
+func (n *NetworkACL) Replace(rules []NetworkRule) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.rules = rules
+}
+
+// Allows checks the address against the global deny rules and the rules of the route groups of the path.
+// A group without allow rules is open, the metrics endpoints use the admin allow rules until they have their own
+func (n *NetworkACL) Allows(address netip.Addr, path string) bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return NetworkRulesAllow(n.rules, address, path)
+}
+
+// NetworkRulesAllow is Allows for a set of rules not loaded yet, e.g. to check a change before it is saved
+func NetworkRulesAllow(rules []NetworkRule, address netip.Addr, path string) bool {
+	groups := networkGroups(path)
+	inGroup := func(group string) bool {
+		for _, g := range groups {
+			if g == group {
+				return true
+			}
+		}
+		return false
+	}
+
+	address = address.Unmap()
+	for _, rule := range rules {
+		if rule.List == NetworkListDeny && (rule.Group == NetworkGroupGlobal || inGroup(rule.Group)) && rule.Prefix.Contains(address) {
+			return false
+		}
+	}
+
+	for _, group := range groups {
+		listed, allowed := false, false
+		for _, rule := range rules {
+			if rule.List == NetworkListAllow && rule.Group == group {
+				listed = true
+				allowed = allowed || rule.Prefix.Contains(address)
+			}
+		}
+		if listed {
+			return allowed
+		}
+	}
+	return true
+}
+
+func networkGroups(path string) []string {
+	for _, route := range networkGroupPaths {
+		if strings.HasPrefix(path, route.prefix) {
+			return route.groups
+		}
+	}
+	return nil
+}
+
+// Middleware answers 403 before routing when the client address is not allowed, an unparsable address
+// only passes when no rule could have applied to it
+func (n *NetworkACL) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		address, err := netip.ParseAddr(TrustedClientAddress(r))
+		if err != nil {
+			n.mu.RLock()
+			empty := len(n.rules) == 0
+			n.mu.RUnlock()
+			if empty {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		if err != nil || !n.Allows(address, r.URL.Path) {
+			WriteEnvelopeError(w, http.StatusForbidden, "network_denied", "Requests from this network are not allowed")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// TrustedClientAddress only reads X-Forwarded-For when the connection comes from a proxy listed in TRUSTED_PROXY_CIDRS,
+// and then takes the nearest address not belonging to a trusted proxy, so a client cannot pick its own address.
+// Network rules and the per address rate limits depend on it
+func TrustedClientAddress(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	isTrusted := func(value string) bool {
+		address, err := netip.ParseAddr(value)
+		if err != nil {
+			return false
+		}
+		for _, prefix := range security.TrustedProxies {
+			if prefix.Contains(address.Unmap()) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if !isTrusted(host) {
+		return host
+	}
+	hops := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop != "" && !isTrusted(hop) {
+			return hop
+		}
+	}
+	return host
+}
@@ -19,3 +19,9 @@ func ComparedPassword(hashedPwd string, plainPassword []byte) (bool, error) {
 	}
 	return true, nil
 }
+
+// IsPasswordHash tells whether a hash made elsewhere can be checked by ComparedPassword
+func IsPasswordHash(hashedPwd string) bool {
+	_, err := bcrypt.Cost([]byte(hashedPwd))
+	return err == nil
+}
@@ -0,0 +1,122 @@
+package common
+
+import (
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	jwtPattern   = regexp.MustCompile(`eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+`)
+
+	// Keys are compared lowercased without underscores so json tags and Go field names both match
+	piiDroppedKeys = []string{
+		"email", "password", "phone", "token", "secret", "fullname", "dateofbirth",
+		"address", "latitude", "longitude", "coord", "location",
+	}
+	piiHashedKeySuffixes = []string{"accountid", "userid", "accountidswipe", "accountididentifier"}
+)
+
+// PIIScrubbingEnabled is controlled by PII_SCRUBBING, telemetry leaving the service must be scrubbed when it is on
+func PIIScrubbingEnabled() bool {
+	return security.PIIScrubbing
+}
+
+// AnonymizeID hashes an identifier with PII_HASH_SALT, the same id always maps to the same value so events stay joinable
+func AnonymizeID(id string) string {
+	return StringEncoder(security.PIIHashSalt + ":" + id)[:16]
+}
+
+// ScrubPII returns a copy of v as generic JSON with identifiers hashed and personal fields dropped
+func ScrubPII(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil
+	}
+	return scrubValue(generic)
+}
+
+// ScrubLogMessage masks emails and JWTs inside free text
+func ScrubLogMessage(message string) string {
+	message = emailPattern.ReplaceAllString(message, "[email]")
+	return jwtPattern.ReplaceAllString(message, "[token]")
+}
+
+// NewPIIScrubWriter wraps a log output so every line is scrubbed before it is written
+func NewPIIScrubWriter(out io.Writer) io.Writer {
+	return &piiScrubWriter{out: out}
+}
+
+type piiScrubWriter struct {
+	out io.Writer
+}
+
+func (p *piiScrubWriter) Write(b []byte) (int, error) {
+	if _, err := p.out.Write([]byte(ScrubLogMessage(string(b)))); err != nil {
+		return 0, err
+	}
+	// Report the original length, callers don't care that the scrubbed line is shorter
+	return len(b), nil
+}
+
+func scrubValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		scrubbed := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			normalized := strings.ToLower(strings.ReplaceAll(key, "_", ""))
+			switch {
+			case isDroppedKey(normalized):
+				continue
+			case isHashedKey(normalized):
+				scrubbed[key] = hashValue(item)
+			default:
+				scrubbed[key] = scrubValue(item)
+			}
+		}
+		return scrubbed
+	case []interface{}:
+		scrubbed := make([]interface{}, len(value))
+		for i, item := range value {
+			scrubbed[i] = scrubValue(item)
+		}
+		return scrubbed
+	case string:
+		return ScrubLogMessage(value)
+	default:
+		return value
+	}
+}
+
+func hashValue(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	data, _ := json.Marshal(v)
+	return AnonymizeID(strings.Trim(string(data), `"`))
+}
+
+func isDroppedKey(key string) bool {
+	for _, dropped := range piiDroppedKeys {
+		if strings.Contains(key, dropped) {
+			return true
+		}
+	}
+	return false
+}
+
+func isHashedKey(key string) bool {
+	for _, suffix := range piiHashedKeySuffixes {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
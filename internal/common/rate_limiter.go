@@ -0,0 +1,84 @@
+package common
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Limiter decides whether one more request counted under key goes through, and if not how long to wait
+type Limiter interface {
+	Allow(ctx context.Context, key string) (bool, time.Duration)
+}
+
+// RateLimiter is a fixed window limiter kept in memory, per instance, for anonymous endpoints
+// and as the fallback of the redis limiter
+type RateLimiter struct {
+	mu      sync.Mutex
+	limit   int
+	window  time.Duration
+	clients map[string]*rateWindow
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		limit:   limit,
+		window:  window,
+		clients: make(map[string]*rateWindow),
+	}
+}
+
+func (rl *RateLimiter) Allow(ctx context.Context, key string) (bool, time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	current, ok := rl.clients[key]
+	if !ok || now.Sub(current.start) >= rl.window {
+		// Drop expired windows so the map doesn't grow with every address ever seen
+		if !ok && len(rl.clients) > 10000 {
+			for k, v := range rl.clients {
+				if now.Sub(v.start) >= rl.window {
+					delete(rl.clients, k)
+				}
+			}
+		}
+		rl.clients[key] = &rateWindow{start: now, count: 1}
+		return true, 0
+	}
+
+	if current.count >= rl.limit {
+		return false, rl.window - now.Sub(current.start)
+	}
+	current.count++
+	return true, 0
+}
+
+// RateLimitMiddleware limits per client address
+func RateLimitMiddleware(rl Limiter, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !AllowRequest(w, r, rl, TrustedClientAddress(r)) {
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// AllowRequest answers 429 with Retry-After in whole seconds when the key is over its limit
+func AllowRequest(w http.ResponseWriter, r *http.Request, rl Limiter, key string) bool {
+	allowed, retryAfter := rl.Allow(r.Context(), key)
+	if allowed {
+		return true
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(max(1, int(math.Ceil(retryAfter.Seconds())))))
+	WriteEnvelopeError(w, http.StatusTooManyRequests, "rate_limited", "Too many requests, try again later")
+	return false
+}
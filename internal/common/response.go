@@ -29,3 +29,78 @@ func WriteJSONResponse(w http.ResponseWriter, statusCode int, message string, da
 	err := json.NewEncoder(w).Encode(response)
 	HandleErrorReturn(err)
 }
+
+// Envelope is the uniform response body: data on success, error on failure, meta always
+type Envelope struct {
+	Data  interface{}    `json:"data"`
+	Error *EnvelopeError `json:"error"`
+	Meta  EnvelopeMeta   `json:"meta"`
+}
+
+type EnvelopeError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type EnvelopeMeta struct {
+	RequestID  string      `json:"request_id"`
+	StatusCode int         `json:"status_code"`
+	Message    string      `json:"message,omitempty"`
+	RequestAt  string      `json:"request_at"`
+	Pagination *Pagination `json:"pagination,omitempty"`
+}
+
+type Pagination struct {
+	Page  int   `json:"page"`
+	Size  int   `json:"size"`
+	Total int64 `json:"total"`
+}
+
+// WriteEnvelope writes a successful response, pagination is nil for single resources
+func WriteEnvelope(w http.ResponseWriter, statusCode int, message string, data interface{}, pagination *Pagination) {
+	writeEnvelope(w, statusCode, Envelope{
+		Data: data,
+		Meta: envelopeMeta(w, statusCode, message, pagination),
+	})
+}
+
+// WriteEnvelopeError writes a failed response, code is a stable machine readable reason such as "invalid_payload"
+func WriteEnvelopeError(w http.ResponseWriter, statusCode int, code string, message string) {
+	writeEnvelope(w, statusCode, Envelope{
+		Error: &EnvelopeError{Code: code, Message: message},
+		Meta:  envelopeMeta(w, statusCode, "", nil),
+	})
+}
+
+// HandleEnvelopeError is the envelope counterpart of HandleInternalServerError
+func HandleEnvelopeError(err error, w http.ResponseWriter) {
+	if err != nil {
+		WriteEnvelopeError(w, http.StatusInternalServerError, "internal_error", err.Error())
+	}
+}
+
+// WriteJSON writes data without the envelope, only for responses whose shape a standard fixes such as token introspection
+func WriteJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	err := json.NewEncoder(w).Encode(data)
+	HandleErrorReturn(err)
+}
+
+func envelopeMeta(w http.ResponseWriter, statusCode int, message string, pagination *Pagination) EnvelopeMeta {
+	return EnvelopeMeta{
+		RequestID:  w.Header().Get(RequestIDHeader),
+		StatusCode: statusCode,
+		Message:    message,
+		RequestAt:  FormatTime(),
+		Pagination: pagination,
+	}
+}
+
+func writeEnvelope(w http.ResponseWriter, statusCode int, envelope Envelope) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	err := json.NewEncoder(w).Encode(envelope)
+	HandleErrorReturn(err)
+}
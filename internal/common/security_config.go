@@ -0,0 +1,30 @@
+package common
+
+import "net/netip"
+
+// SecurityConfig has the credentials and switches the middlewares and the log scrubbing check on every request
+type SecurityConfig struct {
+	// AdminAPIKey guards the admin endpoints, nothing passes when it is empty
+	AdminAPIKey string
+	// ServiceClients maps the name of each sibling service to its secret
+	ServiceClients map[string]string
+	// ClientCertRequired is on once a client CA is configured, from then on the admin and internal routes need
+	// a verified client certificate on top of their key or credentials
+	ClientCertRequired bool
+	// ClientCertRoles maps a SAN of a client certificate to its role. A SAN is a DNS name, a URI such as
+	// spiffe://godating/ops/alice or an email address
+	ClientCertRoles map[string]string `validate:"dive,oneof=admin viewer service"`
+	// TrustedProxies are the networks whose X-Forwarded-For is believed
+	TrustedProxies  []netip.Prefix
+	AntiEnumeration bool
+	PIIScrubbing    bool
+	PIIHashSalt     string
+}
+
+// security keeps anti-enumeration on until main sets the config
+var security = SecurityConfig{AntiEnumeration: true}
+
+// SetSecurityConfig is called once at startup before serving
+func SetSecurityConfig(config SecurityConfig) {
+	security = config
+}
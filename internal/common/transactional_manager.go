@@ -7,6 +7,10 @@ import (
 
 // WithExecuteTransactionalManager manages a insert or update transaction
 func WithExecuteTransactionalManager(ctx context.Context, db *sql.DB, fn func(*sql.Tx) error) error {
+	if err := InjectFault(ctx, FaultTargetDB); err != nil {
+		return err
+	}
+
 	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
@@ -29,6 +33,10 @@ func WithExecuteTransactionalManager(ctx context.Context, db *sql.DB, fn func(*s
 
 // WithReadOnlyTransactionManager manages a read-only transaction
 func WithReadOnlyTransactionManager(ctx context.Context, db *sql.DB, fn func(*sql.Tx) error) error {
+	if err := InjectFault(ctx, FaultTargetDB); err != nil {
+		return err
+	}
+
 	// Begin a read-only transaction
 	tx, err := db.BeginTx(ctx, &sql.TxOptions{
 		ReadOnly: true,
@@ -1,17 +1,97 @@
 package common
 
-func CheckInt64NotNilAndNotZero(val *int64) bool {
-	return val != nil && *val != 0
+import (
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultAdultAge = 18
+
+var (
+	usernamePattern   = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9_]|\.[a-z0-9_]){2,29}$`)
+	reservedUsernames = []string{"admin", "administrator", "support", "root", "system", "godating", "moderator"}
+)
+
+// NewValidator returns the shared validator with the domain rules registered as tags, it panics when a rule cannot
+// be registered so a broken tag stops the service at startup instead of failing every request using it:
+//
+//	adult-age          date of birth (YYYY-MM-DD or time.Time) at least 18 years ago, or the years given as param
+//	safe-username      3 to 30 lowercase letters, digits, "_" or single "."; reserved and system names are refused
+func NewValidator() *validator.Validate {
+	validate := validator.New()
+	rules := map[string]validator.Func{
+		"adult-age":     validateAdultAge,
+		"safe-username": validateSafeUsername,
+	}
+	for tag, rule := range rules {
+		if err := validate.RegisterValidation(tag, rule); err != nil {
+			panic(fmt.Sprintf("could not register the %s validation: %v", tag, err))
+		}
+	}
+	return validate
+}
+
+func validateAdultAge(fl validator.FieldLevel) bool {
+	minAge := intParam(fl.Param(), defaultAdultAge)
+
+	var dateOfBirth time.Time
+	switch value := fl.Field().Interface().(type) {
+	case time.Time:
+		dateOfBirth = value
+	case string:
+		// An empty date is "not set" here, omitempty alone does not skip a pointer to ""
+		if value == "" {
+			return true
+		}
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return false
+		}
+		dateOfBirth = parsed
+	default:
+		return false
+	}
+
+	if dateOfBirth.IsZero() || dateOfBirth.After(time.Now()) {
+		return false
+	}
+	return !dateOfBirth.AddDate(minAge, 0, 0).After(time.Now())
 }
 
-func CheckIntNotNilAndNotZero(val *int) bool {
-	return val != nil && *val != 0
+func validateSafeUsername(fl validator.FieldLevel) bool {
+	if fl.Field().Kind() != reflect.String {
+		return false
+	}
+	return IsSafeUsername(fl.Field().String())
 }
 
-func CheckInt64IsNilAndZero(val *int64) bool {
-	return val == nil || *val == 0
+// IsSafeUsername is the safe-username rule, for usernames the service picks itself
+func IsSafeUsername(username string) bool {
+	if !usernamePattern.MatchString(username) {
+		return false
+	}
+
+	// purged_ is used for accounts anonymised by the dormancy policy
+	if strings.HasPrefix(username, "purged_") {
+		return false
+	}
+	for _, reserved := range reservedUsernames {
+		if strings.Trim(strings.ReplaceAll(username, ".", ""), "_0123456789") == reserved {
+			return false
+		}
+	}
+	return true
 }
 
-func CheckIntIsNilAndZero(val *int) bool {
-	return val == nil || *val == 0
+func intParam(param string, fallback int) int {
+	value, err := strconv.Atoi(param)
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
 }
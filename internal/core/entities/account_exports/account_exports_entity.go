@@ -0,0 +1,11 @@
+package account_exports
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/domain"
+)
+
+type AccountExportEntity interface {
+	ExportAccountDataEntity(ctx context.Context, tx *sql.Tx, accountId int64) ([]domain.AccountExportSectionDto, error)
+}
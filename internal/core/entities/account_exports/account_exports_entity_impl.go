@@ -0,0 +1,32 @@
+package account_exports
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/repo"
+)
+
+type AccountExportEntityImpl struct {
+	AccountExportsRepository repo.AccountExportsRepository
+}
+
+func NewAccountExportEntityImpl(accountExportsRepository repo.AccountExportsRepository) AccountExportEntity {
+	return &AccountExportEntityImpl{AccountExportsRepository: accountExportsRepository}
+}
+
+// ExportAccountDataEntity reads the personal data of the account table by table, in one transaction so the sections
+// agree with each other
+func (a AccountExportEntityImpl) ExportAccountDataEntity(ctx context.Context, tx *sql.Tx, accountId int64) ([]domain.AccountExportSectionDto, error) {
+	records, err := a.AccountExportsRepository.FindAccountExportFromDB(ctx, tx, accountId)
+	if err != nil {
+		return nil, errors.New("failed to export account data")
+	}
+
+	sections := make([]domain.AccountExportSectionDto, 0, len(records))
+	for _, rec := range records {
+		sections = append(sections, domain.AccountExportSectionDto{Name: rec.Name, Rows: rec.Rows})
+	}
+	return sections, nil
+}
@@ -7,9 +7,19 @@ import (
 )
 
 type AccountEntity interface {
-	SaveAccountEntities(ctx context.Context, tx *sql.Tx, dto domain.AccountDto) (domain.Accounts, error)
+	SaveAccountEntities(ctx context.Context, tx *sql.Tx, dto domain.SignUpAccountDto) (domain.Accounts, error)
 	AuthenticateAccount(ctx context.Context, tx *sql.Tx, dto domain.AccountDto) (domain.Accounts, error)
 	FindAccountVerifiedEntities(ctx context.Context, tx *sql.Tx, accountId int64) (bool, error)
 	UpdateAccountVerified(ctx context.Context, tx *sql.Tx, accountId int64) error
+	UpdateAccountPassword(ctx context.Context, tx *sql.Tx, accountId int64, password string) error
 	FindAccountDetails(ctx context.Context, tx *sql.Tx, accountId int64) (domain.AccountDetail, error)
+	CheckAccountAvailableEntities(ctx context.Context, tx *sql.Tx, email string, username string) error
+	FindAccountByEmailEntity(ctx context.Context, tx *sql.Tx, email string) (domain.Accounts, error)
+	FindAccountByProviderEntity(ctx context.Context, tx *sql.Tx, provider string, providerId string) (domain.Accounts, error)
+	LinkAccountProviderEntity(ctx context.Context, tx *sql.Tx, accountId int64, provider string, providerId string) error
+	AssignHomeRegionEntity(ctx context.Context, tx *sql.Tx, accountId int64, region string) (string, error)
+	FindHomeRegionEntity(ctx context.Context, tx *sql.Tx, accountId int64) (string, error)
+	FindHomeRegionByLoginEntity(ctx context.Context, tx *sql.Tx, login string) (string, error)
+	ValidateImportAccountEntity(row domain.AccountImportRow) error
+	ImportAccountEntity(ctx context.Context, tx *sql.Tx, row domain.AccountImportRow) (domain.Accounts, error)
 }
@@ -4,62 +4,88 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/go-playground/validator/v10"
 	"godating-dealls/internal/common"
 	"godating-dealls/internal/domain"
 	"godating-dealls/internal/infra/mysql/record"
 	repository "godating-dealls/internal/infra/mysql/repo"
+	"strings"
+)
+
+// ErrAccountExists is wrapped with which field is taken, callers decide how much of it a client may see
+var ErrAccountExists = errors.New("email or username already exists")
+
+// ErrEmailDomainNotAllowed the email domain is a disposable provider, or not on the allow list during a soft launch
+var ErrEmailDomainNotAllowed = errors.New("email domain is not allowed")
+
+// ErrAccountNotFound no account has the email or the social login that was looked up
+var ErrAccountNotFound = errors.New("account not found")
+
+// ErrProviderAlreadyLinked an account signs in with one social login only, or the social login belongs to another account
+var ErrProviderAlreadyLinked = errors.New("account is already linked to another social login")
+
+// ErrInvalidImportRow is wrapped with what is wrong with the row, the rest of the import goes on without it
+var ErrInvalidImportRow = errors.New("invalid import row")
+
+// ErrPasswordResetRequired the account was imported without a password, forgot password sets one
+var ErrPasswordResetRequired = errors.New("password reset required, request a password reset token with forgot password")
+
+// resetRequiredPasswordHash is not a bcrypt hash so no password matches it
+const resetRequiredPasswordHash = "!reset-required"
+
+var (
+	accountFromRecord       = common.NewMapping(domain.Accounts{}, record.AccountRecord{}, "Verified")
+	accountDetailFromRecord = common.NewMapping(domain.AccountDetail{}, record.AccountRecord{}, "PasswordHash", "CreatedAt", "UpdatedAt")
 )
 
 type AccountEntityImpl struct {
-	repository repository.AccountRepository
-	validate   *validator.Validate
+	repository   repository.AccountRepository
+	validate     *validator.Validate
+	emailDomains *common.EmailDomainPolicy
 }
 
-func NewAccountsEntityImpl(repository repository.AccountRepository, validate *validator.Validate) AccountEntity {
-	return &AccountEntityImpl{repository: repository, validate: validate}
+func NewAccountsEntityImpl(repository repository.AccountRepository, validate *validator.Validate, emailDomains *common.EmailDomainPolicy) AccountEntity {
+	return &AccountEntityImpl{repository: repository, validate: validate, emailDomains: emailDomains}
 }
 
 // SaveAccountEntities this is business rules enterprise of accounts
-func (a AccountEntityImpl) SaveAccountEntities(ctx context.Context, tx *sql.Tx, dto domain.AccountDto) (domain.Accounts, error) {
+func (a AccountEntityImpl) SaveAccountEntities(ctx context.Context, tx *sql.Tx, dto domain.SignUpAccountDto) (domain.Accounts, error) {
 	// validate request dto
-	err := a.validate.Struct(dto)
-	if err != nil {
+	if err := a.validate.Struct(dto); err != nil {
+		var fieldErrors validator.ValidationErrors
+		if errors.As(err, &fieldErrors) && len(fieldErrors) > 0 {
+			return domain.Accounts{}, errors.New(accountFieldMessage(fieldErrors[0].Field()))
+		}
 		return domain.Accounts{}, err
 	}
-
-	// add validate username and email
-	emailIsExist := a.repository.IsExistAccountByEmailFromDB(ctx, tx, *dto.Email)
-	common.PrintJSON("auth entities | email is exist", emailIsExist)
-
-	usernameIsExist := a.repository.IsExistAccountByUsernameFromDB(ctx, tx, *dto.Username)
-	common.PrintJSON("auth entities | username is exist", usernameIsExist)
-
-	if emailIsExist || usernameIsExist {
-		return domain.Accounts{}, errors.New("email or username already exists")
+	if !a.emailDomains.Allows(dto.Email) {
+		return domain.Accounts{}, ErrEmailDomainNotAllowed
 	}
 
 	records := record.AccountRecord{
-		Username:     *dto.Username,
+		Username:     dto.Username,
 		PasswordHash: common.HashingPassword([]byte(dto.Password)),
-		Email:        *dto.Email,
+		Email:        dto.Email,
 		Verified:     false,
 	}
 	common.PrintJSON("auth entities | account record to be saved", records)
 
 	account, err := a.repository.CreateAccountToDB(ctx, tx, records)
+	var duplicate *repository.DuplicateKeyError
+	if errors.As(err, &duplicate) {
+		// MySQL only names the first key that collided, look both up so the caller learns every conflict
+		if conflict := a.CheckAccountAvailableEntities(ctx, tx, records.Email, records.Username); errors.Is(conflict, ErrAccountExists) {
+			return domain.Accounts{}, conflict
+		}
+		return domain.Accounts{}, fmt.Errorf("%w: %s is taken", ErrAccountExists, takenField(duplicate.Key))
+	}
 	if err != nil {
 		return domain.Accounts{}, err
 	}
 
-	result := domain.Accounts{
-		AccountId: account.AccountID,
-		Username:  account.Username,
-		Email:     account.Email,
-		Password:  account.PasswordHash,
-		CreateAt:  account.CreatedAt,
-		UpdateAt:  account.UpdatedAt,
-	}
+	var result domain.Accounts
+	accountFromRecord.Map(&result, account)
 	return result, err
 }
 
@@ -75,42 +101,24 @@ func (a AccountEntityImpl) AuthenticateAccount(ctx context.Context, tx *sql.Tx,
 		if err != nil {
 			return domain.Accounts{}, err
 		}
-		res := domain.Accounts{
-			AccountId: account.AccountID,
-			Username:  account.Username,
-			Email:     account.Email,
-			Password:  account.PasswordHash,
-			CreateAt:  account.CreatedAt,
-			UpdateAt:  account.UpdatedAt,
-		}
+		var res domain.Accounts
+		accountFromRecord.Map(&res, account)
 		return res, err
 	} else if dto.Username != nil && *dto.Username != "" {
 		account, err := a.repository.FindAccountByUsernameFromDB(ctx, tx, *dto.Username)
 		if err != nil {
 			return domain.Accounts{}, err
 		}
-		res := domain.Accounts{
-			AccountId: account.AccountID,
-			Username:  account.Username,
-			Email:     account.Email,
-			Password:  account.PasswordHash,
-			CreateAt:  account.CreatedAt,
-			UpdateAt:  account.UpdatedAt,
-		}
+		var res domain.Accounts
+		accountFromRecord.Map(&res, account)
 		return res, err
 	} else if dto.Email != nil && *dto.Email != "" {
 		account, err := a.repository.FindAccountByEmailFromDB(ctx, tx, *dto.Email)
 		if err != nil {
 			return domain.Accounts{}, err
 		}
-		res := domain.Accounts{
-			AccountId: account.AccountID,
-			Username:  account.Username,
-			Email:     account.Email,
-			Password:  account.PasswordHash,
-			CreateAt:  account.CreatedAt,
-			UpdateAt:  account.UpdatedAt,
-		}
+		var res domain.Accounts
+		accountFromRecord.Map(&res, account)
 		return res, err
 	}
 
@@ -133,16 +141,225 @@ func (a AccountEntityImpl) UpdateAccountVerified(ctx context.Context, tx *sql.Tx
 	return nil
 }
 
+// UpdateAccountPassword replaces the password of an account whose owner was already proven some other way
+func (a AccountEntityImpl) UpdateAccountPassword(ctx context.Context, tx *sql.Tx, accountId int64, password string) error {
+	if err := a.validate.Var(password, "required,min=8,max=72"); err != nil {
+		return errors.New("password must be 8 to 72 characters")
+	}
+
+	err := a.repository.UpdateAccountPasswordByAccountIdFromDB(ctx, tx, accountId, common.HashingPassword([]byte(password)))
+	if err != nil {
+		return errors.New("failed to update account password")
+	}
+	return nil
+}
+
 func (a AccountEntityImpl) FindAccountDetails(ctx context.Context, tx *sql.Tx, accountId int64) (domain.AccountDetail, error) {
 	account, err := a.repository.FindAccountByIdFromDB(ctx, tx, accountId)
 	if err != nil {
 		return domain.AccountDetail{}, errors.New("failed to find account details")
 	}
-	result := domain.AccountDetail{
-		AccountId: account.AccountID,
-		Username:  account.Username,
-		Email:     account.Email,
-		Verified:  account.Verified,
-	}
+	var result domain.AccountDetail
+	accountDetailFromRecord.Map(&result, account)
 	return result, err
 }
+
+// CheckAccountAvailableEntities returns ErrAccountExists naming the taken fields, or nil when both are free.
+// It is an early answer for signup only, the unique constraints still decide when the account is inserted
+func (a AccountEntityImpl) CheckAccountAvailableEntities(ctx context.Context, tx *sql.Tx, email string, username string) error {
+	emailTaken, usernameTaken, err := a.repository.FindAccountConflictsFromDB(ctx, tx, email, username)
+	if err != nil {
+		return errors.New("failed to check account availability")
+	}
+
+	switch {
+	case emailTaken && usernameTaken:
+		return fmt.Errorf("%w: email and username are taken", ErrAccountExists)
+	case emailTaken:
+		return fmt.Errorf("%w: email is taken", ErrAccountExists)
+	case usernameTaken:
+		return fmt.Errorf("%w: username is taken", ErrAccountExists)
+	}
+	return nil
+}
+
+// FindAccountByEmailEntity returns ErrAccountNotFound when no account has the email
+func (a AccountEntityImpl) FindAccountByEmailEntity(ctx context.Context, tx *sql.Tx, email string) (domain.Accounts, error) {
+	emailTaken, _, err := a.repository.FindAccountConflictsFromDB(ctx, tx, email, "")
+	if err != nil {
+		return domain.Accounts{}, errors.New("failed to find account by email")
+	}
+	if !emailTaken {
+		return domain.Accounts{}, ErrAccountNotFound
+	}
+
+	account, err := a.repository.FindAccountByEmailFromDB(ctx, tx, email)
+	if err != nil {
+		return domain.Accounts{}, errors.New("failed to find account by email")
+	}
+	var result domain.Accounts
+	accountFromRecord.Map(&result, account)
+	return result, nil
+}
+
+// FindAccountByProviderEntity returns ErrAccountNotFound when the social login is not linked to an account yet
+func (a AccountEntityImpl) FindAccountByProviderEntity(ctx context.Context, tx *sql.Tx, provider string, providerId string) (domain.Accounts, error) {
+	account, err := a.repository.FindAccountByProviderFromDB(ctx, tx, provider, providerId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Accounts{}, ErrAccountNotFound
+	}
+	if err != nil {
+		return domain.Accounts{}, errors.New("failed to find account by provider")
+	}
+	var result domain.Accounts
+	accountFromRecord.Map(&result, account)
+	return result, nil
+}
+
+// LinkAccountProviderEntity lets the account sign in with the social login from now on
+func (a AccountEntityImpl) LinkAccountProviderEntity(ctx context.Context, tx *sql.Tx, accountId int64, provider string, providerId string) error {
+	linked, err := a.repository.FindAccountProviderFromDB(ctx, tx, accountId)
+	if err != nil {
+		return errors.New("failed to find account provider")
+	}
+	if linked.Valid {
+		return fmt.Errorf("%w: %s", ErrProviderAlreadyLinked, linked.String)
+	}
+
+	err = a.repository.UpdateAccountProviderToDB(ctx, tx, accountId, provider, providerId)
+	var duplicate *repository.DuplicateKeyError
+	if errors.As(err, &duplicate) {
+		return ErrProviderAlreadyLinked
+	}
+	if err != nil {
+		return errors.New("failed to link account provider")
+	}
+	return nil
+}
+
+// AssignHomeRegionEntity gives the account the region when it has none yet and returns its home region, an account
+// keeps its home region wherever it logs in later
+func (a AccountEntityImpl) AssignHomeRegionEntity(ctx context.Context, tx *sql.Tx, accountId int64, region string) (string, error) {
+	home, err := a.FindHomeRegionEntity(ctx, tx, accountId)
+	if err != nil || home != "" {
+		return home, err
+	}
+	if err := a.repository.UpdateAccountHomeRegionToDB(ctx, tx, accountId, region); err != nil {
+		return "", errors.New("failed to assign home region")
+	}
+	return region, nil
+}
+
+// FindHomeRegionEntity returns an empty region for an account that has none yet
+func (a AccountEntityImpl) FindHomeRegionEntity(ctx context.Context, tx *sql.Tx, accountId int64) (string, error) {
+	region, err := a.repository.FindAccountHomeRegionFromDB(ctx, tx, accountId)
+	if err != nil {
+		return "", errors.New("failed to find home region")
+	}
+	return region.String, nil
+}
+
+// FindHomeRegionByLoginEntity login is the username or the email the account logs in with
+func (a AccountEntityImpl) FindHomeRegionByLoginEntity(ctx context.Context, tx *sql.Tx, login string) (string, error) {
+	region, err := a.repository.FindAccountHomeRegionByLoginFromDB(ctx, tx, strings.TrimSpace(login))
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrAccountNotFound
+	}
+	if err != nil {
+		return "", errors.New("failed to find home region")
+	}
+	return region.String, nil
+}
+
+// takenField names the account field behind a unique key such as "accounts.email" or "email"
+func takenField(key string) string {
+	switch {
+	case strings.Contains(key, "email"):
+		return "email"
+	case strings.Contains(key, "username"):
+		return "username"
+	}
+	return "email or username"
+}
+
+// ValidateImportAccountEntity checks an imported row the way sign up checks a new account, except the email domain
+// policy: the accounts already exist on the other platform, the policy is for new sign ups
+func (a AccountEntityImpl) ValidateImportAccountEntity(row domain.AccountImportRow) error {
+	if err := a.validate.Struct(row); err != nil {
+		var fieldErrors validator.ValidationErrors
+		if errors.As(err, &fieldErrors) && len(fieldErrors) > 0 {
+			return fmt.Errorf("%w: %s", ErrInvalidImportRow, accountFieldMessage(fieldErrors[0].Field()))
+		}
+		return fmt.Errorf("%w: %v", ErrInvalidImportRow, err)
+	}
+
+	if row.ResetPassword {
+		return nil
+	}
+	if row.PasswordHash == "" {
+		return fmt.Errorf("%w: password_hash or reset_password is required", ErrInvalidImportRow)
+	}
+	// Only bcrypt hashes can be checked at login, accounts with other hashes are imported with reset_password
+	if !common.IsPasswordHash(row.PasswordHash) {
+		return fmt.Errorf("%w: password_hash is not a bcrypt hash, import the account with reset_password", ErrInvalidImportRow)
+	}
+	return nil
+}
+
+// ImportAccountEntity creates an account of another platform with its password hash, or without a usable password
+// when the row asks for a reset. Taken usernames and emails return ErrAccountExists like sign up
+func (a AccountEntityImpl) ImportAccountEntity(ctx context.Context, tx *sql.Tx, row domain.AccountImportRow) (domain.Accounts, error) {
+	if err := a.ValidateImportAccountEntity(row); err != nil {
+		return domain.Accounts{}, err
+	}
+
+	passwordHash := row.PasswordHash
+	if row.ResetPassword {
+		passwordHash = resetRequiredPasswordHash
+	}
+	records := record.AccountRecord{
+		Username:     row.Username,
+		PasswordHash: passwordHash,
+		Email:        row.Email,
+		Verified:     row.Verified,
+	}
+
+	account, err := a.repository.CreateAccountToDB(ctx, tx, records)
+	var duplicate *repository.DuplicateKeyError
+	if errors.As(err, &duplicate) {
+		return domain.Accounts{}, fmt.Errorf("%w: %s is taken", ErrAccountExists, takenField(duplicate.Key))
+	}
+	if err != nil {
+		return domain.Accounts{}, errors.New("failed to import account")
+	}
+
+	var result domain.Accounts
+	accountFromRecord.Map(&result, account)
+	return result, nil
+}
+
+// IsPasswordResetRequired an imported account keeps resetRequiredPasswordHash until forgot password sets a password
+func IsPasswordResetRequired(passwordHash string) bool {
+	return passwordHash == resetRequiredPasswordHash
+}
+
+// accountFieldMessage names the rule of the first invalid field of a new or imported account
+func accountFieldMessage(field string) string {
+	switch field {
+	case "Username":
+		return "username must be 3 to 30 lowercase letters, digits, dots or underscores"
+	case "Email":
+		return "invalid email"
+	case "DateOfBirth":
+		return "date_of_birth must be a YYYY-MM-DD date at least 18 years ago"
+	case "FullName":
+		return "full_name is longer than 255 characters"
+	case "Gender":
+		return "gender is longer than 5 characters"
+	case "Address":
+		return "address is longer than 255 characters"
+	case "Bio":
+		return "bio is longer than 5000 characters"
+	}
+	return strings.ToLower(field) + " is invalid"
+}
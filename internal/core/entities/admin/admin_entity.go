@@ -0,0 +1,13 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/domain"
+)
+
+type AdminEntity interface {
+	FindAccountTimelineEntity(ctx context.Context, tx *sql.Tx, accountId int64, page int, size int) ([]domain.TimelineEventDto, int64, error)
+	StreamAccountTimelineEntity(ctx context.Context, tx *sql.Tx, accountId int64, fn func(domain.TimelineEventDto) error) error
+	RecordAuditLogEntity(ctx context.Context, tx *sql.Tx, dto domain.AdminAuditLogDto) (domain.AdminAuditLogDto, error)
+}
@@ -0,0 +1,90 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/record"
+	"godating-dealls/internal/infra/mysql/repo"
+)
+
+type AdminEntityImpl struct {
+	AdminRepository repo.AdminRepository
+	Validate        *validator.Validate
+}
+
+func NewAdminEntityImpl(adminRepository repo.AdminRepository, validate *validator.Validate) AdminEntity {
+	return &AdminEntityImpl{AdminRepository: adminRepository, Validate: validate}
+}
+
+func (a AdminEntityImpl) FindAccountTimelineEntity(ctx context.Context, tx *sql.Tx, accountId int64, page int, size int) ([]domain.TimelineEventDto, int64, error) {
+	total, err := a.AdminRepository.CountAccountTimelineFromDB(ctx, tx, accountId)
+	if err != nil {
+		return nil, 0, errors.New("failed to count timeline")
+	}
+
+	records, err := a.AdminRepository.FindAccountTimelineFromDB(ctx, tx, accountId, size, (page-1)*size)
+	if err != nil {
+		return nil, 0, errors.New("failed to find timeline")
+	}
+
+	var events []domain.TimelineEventDto
+	for _, rec := range records {
+		events = append(events, domain.TimelineEventDto{
+			EventType:  rec.EventType,
+			OccurredAt: rec.OccurredAt,
+			Detail:     rec.Detail,
+		})
+	}
+	return events, total, nil
+}
+
+// StreamAccountTimelineEntity an error of fn, like a client that went away, is returned as it is
+func (a AdminEntityImpl) StreamAccountTimelineEntity(ctx context.Context, tx *sql.Tx, accountId int64, fn func(domain.TimelineEventDto) error) error {
+	var fnErr error
+	err := a.AdminRepository.StreamAccountTimelineFromDB(ctx, tx, accountId, func(rec record.TimelineEventRecord) error {
+		fnErr = fn(domain.TimelineEventDto{
+			EventType:  rec.EventType,
+			OccurredAt: rec.OccurredAt,
+			Detail:     rec.Detail,
+		})
+		return fnErr
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	if err != nil {
+		return errors.New("failed to stream timeline")
+	}
+	return nil
+}
+
+// RecordAuditLogEntity every admin change needs a justification, the entry is written in the same transaction as the change
+func (a AdminEntityImpl) RecordAuditLogEntity(ctx context.Context, tx *sql.Tx, dto domain.AdminAuditLogDto) (domain.AdminAuditLogDto, error) {
+	err := a.Validate.Struct(dto)
+	if err != nil {
+		return domain.AdminAuditLogDto{}, err
+	}
+
+	changes, err := json.Marshal(dto.Changes)
+	if err != nil {
+		return domain.AdminAuditLogDto{}, errors.New("invalid audit changes")
+	}
+
+	rec, err := a.AdminRepository.InsertAuditLogToDB(ctx, tx, record.AdminAuditLogRecord{
+		Actor:           dto.Actor,
+		Action:          dto.Action,
+		TargetAccountID: dto.TargetAccountID,
+		Justification:   dto.Justification,
+		Changes:         string(changes),
+	})
+	if err != nil {
+		return domain.AdminAuditLogDto{}, errors.New("failed to record audit log")
+	}
+
+	dto.AuditID = rec.AuditID
+	return dto, nil
+}
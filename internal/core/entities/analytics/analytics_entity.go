@@ -0,0 +1,13 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/domain"
+	"time"
+)
+
+type AnalyticsEntity interface {
+	RollupQuotaUsageEntity(ctx context.Context, tx *sql.Tx, day time.Time) (int64, error)
+	FindQuotaUsageEntity(ctx context.Context, tx *sql.Tx, from time.Time, to time.Time) ([]domain.QuotaUsageDto, error)
+}
@@ -0,0 +1,51 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/repo"
+	"time"
+)
+
+type AnalyticsEntityImpl struct {
+	QuotaUsageRollupsRepository repo.QuotaUsageRollupsRepository
+}
+
+func NewAnalyticsEntityImpl(quotaUsageRollupsRepository repo.QuotaUsageRollupsRepository) AnalyticsEntity {
+	return &AnalyticsEntityImpl{QuotaUsageRollupsRepository: quotaUsageRollupsRepository}
+}
+
+func (a AnalyticsEntityImpl) RollupQuotaUsageEntity(ctx context.Context, tx *sql.Tx, day time.Time) (int64, error) {
+	rows, err := a.QuotaUsageRollupsRepository.RollupQuotaUsageToDB(ctx, tx, day)
+	if err != nil {
+		return 0, errors.New("failed to rollup quota usage")
+	}
+	return rows, nil
+}
+
+func (a AnalyticsEntityImpl) FindQuotaUsageEntity(ctx context.Context, tx *sql.Tx, from time.Time, to time.Time) ([]domain.QuotaUsageDto, error) {
+	if to.Before(from) {
+		return nil, errors.New("invalid date range")
+	}
+
+	records, err := a.QuotaUsageRollupsRepository.FindQuotaUsageRollupsFromDB(ctx, tx, from, to)
+	if err != nil {
+		return nil, errors.New("failed to find quota usage")
+	}
+
+	var usage []domain.QuotaUsageDto
+	for _, rec := range records {
+		usage = append(usage, domain.QuotaUsageDto{
+			Date:              rec.RollupDate,
+			Tier:              rec.Tier,
+			Accounts:          rec.Accounts,
+			ActiveAccounts:    rec.ActiveAccounts,
+			TotalSwipes:       rec.TotalSwipes,
+			TotalLikes:        rec.TotalLikes,
+			ExhaustedAccounts: rec.ExhaustedAccounts,
+		})
+	}
+	return usage, nil
+}
@@ -0,0 +1,17 @@
+package blocks
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/domain"
+)
+
+type BlockEntity interface {
+	BlockAccountEntity(ctx context.Context, tx *sql.Tx, accountId int64, blockedAccountId int64) error
+	UnblockAccountEntity(ctx context.Context, tx *sql.Tx, accountId int64, blockedAccountId int64) error
+	FindBlockedAccountsEntity(ctx context.Context, tx *sql.Tx, accountId int64) ([]domain.BlockedAccountDto, error)
+	EnsureNotBlockedEntity(ctx context.Context, tx *sql.Tx, accountId int64, otherAccountIds []int64) error
+	ReportAccountEntity(ctx context.Context, tx *sql.Tx, dto domain.ReportDto) (domain.ReportDto, error)
+	FindReportsEntity(ctx context.Context, tx *sql.Tx, reportedAccountId int64, page int, size int) ([]domain.ReportDto, int64, error)
+	StreamReportsEntity(ctx context.Context, tx *sql.Tx, reportedAccountId int64, fn func(domain.ReportDto) error) error
+}
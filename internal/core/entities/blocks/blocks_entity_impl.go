@@ -0,0 +1,175 @@
+package blocks
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/record"
+	"godating-dealls/internal/infra/mysql/repo"
+	"strings"
+)
+
+// MaxReportDetails bounds the free text of a report, the reason carries the category
+const MaxReportDetails = 1000
+
+// ReportReasons are the categories a report can be filed under
+var ReportReasons = []string{"spam", "fake_profile", "inappropriate_content", "harassment", "underage", "other"}
+
+var (
+	ErrInvalidBlock  = errors.New("invalid block")
+	ErrBlockNotFound = errors.New("account not found")
+	ErrBlocked       = errors.New("this account is not available")
+	ErrInvalidReport = errors.New("invalid report")
+)
+
+type BlockEntityImpl struct {
+	BlocksRepository  repo.BlocksRepository
+	AccountRepository repo.AccountRepository
+}
+
+func NewBlockEntityImpl(blocksRepository repo.BlocksRepository, accountRepository repo.AccountRepository) BlockEntity {
+	return &BlockEntityImpl{BlocksRepository: blocksRepository, AccountRepository: accountRepository}
+}
+
+// BlockAccountEntity takes both accounts out of each other's discovery, likes and chat, the blocked account is not told
+func (b BlockEntityImpl) BlockAccountEntity(ctx context.Context, tx *sql.Tx, accountId int64, blockedAccountId int64) error {
+	if err := b.ensureOtherAccount(ctx, tx, accountId, blockedAccountId, ErrInvalidBlock); err != nil {
+		return err
+	}
+	if err := b.BlocksRepository.InsertBlockToDB(ctx, tx, accountId, blockedAccountId); err != nil {
+		return errors.New("failed to block account")
+	}
+	return nil
+}
+
+func (b BlockEntityImpl) UnblockAccountEntity(ctx context.Context, tx *sql.Tx, accountId int64, blockedAccountId int64) error {
+	removed, err := b.BlocksRepository.DeleteBlockFromDB(ctx, tx, accountId, blockedAccountId)
+	if err != nil {
+		return errors.New("failed to unblock account")
+	}
+	if removed == 0 {
+		return ErrBlockNotFound
+	}
+	return nil
+}
+
+func (b BlockEntityImpl) FindBlockedAccountsEntity(ctx context.Context, tx *sql.Tx, accountId int64) ([]domain.BlockedAccountDto, error) {
+	records, err := b.BlocksRepository.FindBlocksFromDB(ctx, tx, accountId)
+	if err != nil {
+		return nil, errors.New("failed to find blocked accounts")
+	}
+
+	res := make([]domain.BlockedAccountDto, 0, len(records))
+	for _, rec := range records {
+		res = append(res, domain.BlockedAccountDto{
+			AccountID: rec.BlockedAccountID,
+			Username:  rec.BlockedUsername,
+			BlockedAt: rec.CreatedAt,
+		})
+	}
+	return res, nil
+}
+
+// EnsureNotBlockedEntity returns ErrBlocked when the account and any of the others blocked one another, whoever blocked
+func (b BlockEntityImpl) EnsureNotBlockedEntity(ctx context.Context, tx *sql.Tx, accountId int64, otherAccountIds []int64) error {
+	blocked, err := b.BlocksRepository.FindBlockedEitherWayFromDB(ctx, tx, accountId, otherAccountIds)
+	if err != nil {
+		return errors.New("failed to check blocks")
+	}
+	if blocked {
+		return ErrBlocked
+	}
+	return nil
+}
+
+// ReportAccountEntity only records the report for the admins, blocking is a separate choice of the reporter
+func (b BlockEntityImpl) ReportAccountEntity(ctx context.Context, tx *sql.Tx, dto domain.ReportDto) (domain.ReportDto, error) {
+	dto.Reason = strings.ToLower(strings.TrimSpace(dto.Reason))
+	dto.Details = strings.TrimSpace(dto.Details)
+
+	known := false
+	for _, reason := range ReportReasons {
+		known = known || reason == dto.Reason
+	}
+	if !known {
+		return domain.ReportDto{}, fmt.Errorf("%w: reason must be one of %s", ErrInvalidReport, strings.Join(ReportReasons, ", "))
+	}
+	if len([]rune(dto.Details)) > MaxReportDetails {
+		return domain.ReportDto{}, fmt.Errorf("%w: details must be at most %d characters", ErrInvalidReport, MaxReportDetails)
+	}
+	if err := b.ensureOtherAccount(ctx, tx, dto.ReporterAccountID, dto.ReportedAccountID, ErrInvalidReport); err != nil {
+		return domain.ReportDto{}, err
+	}
+
+	rec, err := b.BlocksRepository.InsertReportToDB(ctx, tx, record.ReportRecord{
+		ReporterAccountID: dto.ReporterAccountID,
+		ReportedAccountID: dto.ReportedAccountID,
+		Reason:            dto.Reason,
+		Details:           dto.Details,
+	})
+	if err != nil {
+		return domain.ReportDto{}, errors.New("failed to report account")
+	}
+	return toReportDto(rec), nil
+}
+
+func (b BlockEntityImpl) FindReportsEntity(ctx context.Context, tx *sql.Tx, reportedAccountId int64, page int, size int) ([]domain.ReportDto, int64, error) {
+	total, err := b.BlocksRepository.CountReportsFromDB(ctx, tx, reportedAccountId)
+	if err != nil {
+		return nil, 0, errors.New("failed to count reports")
+	}
+
+	records, err := b.BlocksRepository.FindReportsFromDB(ctx, tx, reportedAccountId, size, (page-1)*size)
+	if err != nil {
+		return nil, 0, errors.New("failed to find reports")
+	}
+
+	res := make([]domain.ReportDto, 0, len(records))
+	for _, rec := range records {
+		res = append(res, toReportDto(rec))
+	}
+	return res, total, nil
+}
+
+// StreamReportsEntity an error of fn, like a client that went away, is returned as it is
+func (b BlockEntityImpl) StreamReportsEntity(ctx context.Context, tx *sql.Tx, reportedAccountId int64, fn func(domain.ReportDto) error) error {
+	var fnErr error
+	err := b.BlocksRepository.StreamReportsFromDB(ctx, tx, reportedAccountId, func(rec record.ReportRecord) error {
+		fnErr = fn(toReportDto(rec))
+		return fnErr
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	if err != nil {
+		return errors.New("failed to stream reports")
+	}
+	return nil
+}
+
+func (b BlockEntityImpl) ensureOtherAccount(ctx context.Context, tx *sql.Tx, accountId int64, otherAccountId int64, invalid error) error {
+	if otherAccountId <= 0 || otherAccountId == accountId {
+		return fmt.Errorf("%w: account_id must be another account", invalid)
+	}
+	account, err := b.AccountRepository.FindAccountByIdFromDB(ctx, tx, otherAccountId)
+	if err != nil {
+		return errors.New("failed to find account")
+	}
+	if account.AccountID == 0 {
+		return ErrBlockNotFound
+	}
+	return nil
+}
+
+func toReportDto(rec record.ReportRecord) domain.ReportDto {
+	return domain.ReportDto{
+		ReportID:          rec.ReportID,
+		ReporterAccountID: rec.ReporterAccountID,
+		ReportedAccountID: rec.ReportedAccountID,
+		Reason:            rec.Reason,
+		Details:           rec.Details,
+		CreatedAt:         rec.CreatedAt,
+	}
+}
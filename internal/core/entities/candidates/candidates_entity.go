@@ -0,0 +1,11 @@
+package candidates
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/domain"
+)
+
+type CandidateEntity interface {
+	FindCandidatesEntity(ctx context.Context, tx *sql.Tx, accountId int64, filter domain.CandidateFilterDto, weights Weights, page int, size int) ([]domain.CandidateDto, int64, error)
+}
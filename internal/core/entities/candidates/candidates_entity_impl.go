@@ -0,0 +1,81 @@
+package candidates
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/record"
+	"godating-dealls/internal/infra/mysql/repo"
+	"strings"
+)
+
+var ErrInvalidCandidateFilter = errors.New("invalid candidate filter")
+
+// Weights order the candidates, both scores are between 0 and 1 so the weights set how much each one counts
+type Weights struct {
+	Activity     float64 `validate:"min=0"`
+	Completeness float64 `validate:"min=0"`
+}
+
+type CandidateEntityImpl struct {
+	CandidatesRepository repo.CandidatesRepository
+	validate             *validator.Validate
+}
+
+func NewCandidateEntityImpl(candidatesRepository repo.CandidatesRepository, validate *validator.Validate) CandidateEntity {
+	return &CandidateEntityImpl{CandidatesRepository: candidatesRepository, validate: validate}
+}
+
+// FindCandidatesEntity leaves out the viewer, the accounts it already swiped and the accounts blocked either way
+func (c CandidateEntityImpl) FindCandidatesEntity(ctx context.Context, tx *sql.Tx, accountId int64, filter domain.CandidateFilterDto, weights Weights, page int, size int) ([]domain.CandidateDto, int64, error) {
+	for i, gender := range filter.Genders {
+		filter.Genders[i] = strings.ToUpper(strings.TrimSpace(gender))
+	}
+	if err := c.validate.Struct(filter); err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrInvalidCandidateFilter, err)
+	}
+
+	query := record.CandidateQuery{
+		AccountID:          accountId,
+		MinAge:             filter.MinAge,
+		MaxAge:             filter.MaxAge,
+		Genders:            filter.Genders,
+		MaxDistanceKm:      filter.MaxDistanceKm,
+		ActivityWeight:     weights.Activity,
+		CompletenessWeight: weights.Completeness,
+	}
+
+	total, err := c.CandidatesRepository.CountCandidatesFromDB(ctx, tx, query)
+	if err != nil {
+		return nil, 0, errors.New("failed to count candidates")
+	}
+
+	records, err := c.CandidatesRepository.FindCandidatesFromDB(ctx, tx, query, size, (page-1)*size)
+	if err != nil {
+		return nil, 0, errors.New("failed to find candidates")
+	}
+
+	res := make([]domain.CandidateDto, 0, len(records))
+	for _, rec := range records {
+		res = append(res, domain.CandidateDto{
+			AccountID:         rec.AccountID,
+			UserID:            rec.UserID,
+			Verified:          rec.Verified,
+			Username:          rec.Username,
+			FullName:          rec.FullName,
+			Gender:            rec.Gender,
+			Bio:               rec.Bio,
+			Age:               rec.Age,
+			Address:           rec.Address,
+			DistanceKm:        rec.DistanceKm,
+			ActivityScore:     rec.ActivityScore,
+			CompletenessScore: rec.CompletenessScore,
+			SuperLiked:        rec.SuperLiked,
+			Score:             rec.Score,
+		})
+	}
+	return res, total, nil
+}
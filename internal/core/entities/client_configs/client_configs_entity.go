@@ -0,0 +1,14 @@
+package client_configs
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/domain"
+)
+
+type ClientConfigEntity interface {
+	PublishClientConfigBundleEntity(ctx context.Context, tx *sql.Tx, dto domain.ClientConfigBundleDto) (domain.ClientConfigBundleDto, error)
+	FindClientConfigBundlesEntity(ctx context.Context, tx *sql.Tx) ([]domain.ClientConfigBundleDto, error)
+	UpdateClientConfigRolloutEntity(ctx context.Context, tx *sql.Tx, bundleId int64, request domain.ClientConfigRolloutRequest) (domain.ClientConfigBundleDto, error)
+	ResolveClientConfigEntity(ctx context.Context, tx *sql.Tx, accountId int64, appVersion string) ([]domain.ClientConfigBundleDto, error)
+}
@@ -0,0 +1,231 @@
+package client_configs
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/record"
+	"godating-dealls/internal/infra/mysql/repo"
+	"hash/fnv"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const maxClientConfigPayloadBytes = 64 * 1024
+
+var (
+	ErrInvalidClientConfig  = errors.New("invalid client config")
+	ErrClientConfigNotFound = errors.New("client config bundle not found")
+	ErrClientConfigConflict = errors.New("another version of this bundle was published at the same time, try again")
+
+	bundleNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]*$`)
+	appVersionPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+){0,3}$`)
+)
+
+type ClientConfigEntityImpl struct {
+	ClientConfigsRepository repo.ClientConfigsRepository
+	validate                *validator.Validate
+}
+
+func NewClientConfigEntityImpl(clientConfigsRepository repo.ClientConfigsRepository, validate *validator.Validate) ClientConfigEntity {
+	return &ClientConfigEntityImpl{ClientConfigsRepository: clientConfigsRepository, validate: validate}
+}
+
+// PublishClientConfigBundleEntity stores the payload as the next version of the bundle name
+func (c ClientConfigEntityImpl) PublishClientConfigBundleEntity(ctx context.Context, tx *sql.Tx, dto domain.ClientConfigBundleDto) (domain.ClientConfigBundleDto, error) {
+	if dto.MinAppVersion == "" {
+		dto.MinAppVersion = "0"
+	}
+	if err := c.validateBundle(dto); err != nil {
+		return domain.ClientConfigBundleDto{}, err
+	}
+
+	// Compact so the stored payload and every ETag computed from it do not depend on formatting
+	var payload bytes.Buffer
+	if err := json.Compact(&payload, dto.Payload); err != nil {
+		return domain.ClientConfigBundleDto{}, fmt.Errorf("%w: payload is not valid JSON", ErrInvalidClientConfig)
+	}
+
+	bundle, err := c.ClientConfigsRepository.InsertClientConfigBundleToDB(ctx, tx, record.ClientConfigBundleRecord{
+		Name:           dto.Name,
+		MinAppVersion:  dto.MinAppVersion,
+		MaxAppVersion:  dto.MaxAppVersion,
+		RolloutPercent: dto.RolloutPercent,
+		Payload:        payload.Bytes(),
+	})
+	if err != nil {
+		var duplicate *repo.DuplicateKeyError
+		if errors.As(err, &duplicate) {
+			return domain.ClientConfigBundleDto{}, ErrClientConfigConflict
+		}
+		return domain.ClientConfigBundleDto{}, errors.New("failed to publish client config bundle")
+	}
+	return toClientConfigBundleDto(bundle), nil
+}
+
+func (c ClientConfigEntityImpl) FindClientConfigBundlesEntity(ctx context.Context, tx *sql.Tx) ([]domain.ClientConfigBundleDto, error) {
+	bundles, err := c.ClientConfigsRepository.FindClientConfigBundlesFromDB(ctx, tx)
+	if err != nil {
+		return nil, errors.New("failed to find client config bundles")
+	}
+
+	var res []domain.ClientConfigBundleDto
+	for _, bundle := range bundles {
+		res = append(res, toClientConfigBundleDto(bundle))
+	}
+	return res, nil
+}
+
+// UpdateClientConfigRolloutEntity widens, narrows or stops the rollout of one version, the payload never changes
+func (c ClientConfigEntityImpl) UpdateClientConfigRolloutEntity(ctx context.Context, tx *sql.Tx, bundleId int64, request domain.ClientConfigRolloutRequest) (domain.ClientConfigBundleDto, error) {
+	bundle, err := c.ClientConfigsRepository.FindClientConfigBundleByIdFromDB(ctx, tx, bundleId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ClientConfigBundleDto{}, ErrClientConfigNotFound
+		}
+		return domain.ClientConfigBundleDto{}, errors.New("failed to find client config bundle")
+	}
+
+	if request.MinAppVersion != nil {
+		bundle.MinAppVersion = *request.MinAppVersion
+	}
+	if request.MaxAppVersion != nil {
+		// An empty max removes the upper bound
+		bundle.MaxAppVersion = request.MaxAppVersion
+		if *request.MaxAppVersion == "" {
+			bundle.MaxAppVersion = nil
+		}
+	}
+	if request.RolloutPercent != nil {
+		bundle.RolloutPercent = *request.RolloutPercent
+	}
+
+	dto := toClientConfigBundleDto(bundle)
+	if err := c.validateBundle(dto); err != nil {
+		return domain.ClientConfigBundleDto{}, err
+	}
+
+	if err := c.ClientConfigsRepository.UpdateClientConfigRolloutToDB(ctx, tx, bundle); err != nil {
+		return domain.ClientConfigBundleDto{}, errors.New("failed to update client config rollout")
+	}
+	return c.findClientConfigBundle(ctx, tx, bundleId)
+}
+
+// ResolveClientConfigEntity picks per bundle name the newest version whose app version range contains appVersion
+// and whose rollout covers the account. An account keeps its bucket for a bundle, so raising the percentage only adds accounts
+// and the ones outside the rollout fall back to an older version
+func (c ClientConfigEntityImpl) ResolveClientConfigEntity(ctx context.Context, tx *sql.Tx, accountId int64, appVersion string) ([]domain.ClientConfigBundleDto, error) {
+	if !appVersionPattern.MatchString(appVersion) {
+		return nil, fmt.Errorf("%w: app version must look like 1.4.2", ErrInvalidClientConfig)
+	}
+
+	bundles, err := c.ClientConfigsRepository.FindRolledOutClientConfigBundlesFromDB(ctx, tx)
+	if err != nil {
+		return nil, errors.New("failed to find client config bundles")
+	}
+
+	var res []domain.ClientConfigBundleDto
+	resolved := map[string]bool{}
+	for _, bundle := range bundles {
+		if resolved[bundle.Name] {
+			continue
+		}
+		if compareAppVersions(appVersion, bundle.MinAppVersion) < 0 {
+			continue
+		}
+		if bundle.MaxAppVersion != nil && compareAppVersions(appVersion, *bundle.MaxAppVersion) > 0 {
+			continue
+		}
+		if rolloutBucket(bundle.Name, accountId) >= bundle.RolloutPercent {
+			continue
+		}
+		resolved[bundle.Name] = true
+		res = append(res, toClientConfigBundleDto(bundle))
+	}
+	return res, nil
+}
+
+func (c ClientConfigEntityImpl) findClientConfigBundle(ctx context.Context, tx *sql.Tx, bundleId int64) (domain.ClientConfigBundleDto, error) {
+	bundle, err := c.ClientConfigsRepository.FindClientConfigBundleByIdFromDB(ctx, tx, bundleId)
+	if err != nil {
+		return domain.ClientConfigBundleDto{}, errors.New("failed to find client config bundle")
+	}
+	return toClientConfigBundleDto(bundle), nil
+}
+
+func (c ClientConfigEntityImpl) validateBundle(dto domain.ClientConfigBundleDto) error {
+	if err := c.validate.Struct(dto); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidClientConfig, err)
+	}
+	if !bundleNamePattern.MatchString(dto.Name) {
+		return fmt.Errorf("%w: name must be lowercase letters, digits, dots, dashes or underscores", ErrInvalidClientConfig)
+	}
+	if len(dto.Payload) > maxClientConfigPayloadBytes || !isJSONObject(dto.Payload) {
+		return fmt.Errorf("%w: payload must be a JSON object of at most %d bytes", ErrInvalidClientConfig, maxClientConfigPayloadBytes)
+	}
+	if !appVersionPattern.MatchString(dto.MinAppVersion) {
+		return fmt.Errorf("%w: min app version must look like 1.4.2", ErrInvalidClientConfig)
+	}
+	if dto.MaxAppVersion != nil {
+		if !appVersionPattern.MatchString(*dto.MaxAppVersion) {
+			return fmt.Errorf("%w: max app version must look like 1.4.2", ErrInvalidClientConfig)
+		}
+		if compareAppVersions(dto.MinAppVersion, *dto.MaxAppVersion) > 0 {
+			return fmt.Errorf("%w: min app version is above max app version", ErrInvalidClientConfig)
+		}
+	}
+	return nil
+}
+
+func toClientConfigBundleDto(bundle record.ClientConfigBundleRecord) domain.ClientConfigBundleDto {
+	return domain.ClientConfigBundleDto{
+		BundleID:       bundle.BundleID,
+		Name:           bundle.Name,
+		Version:        bundle.Version,
+		MinAppVersion:  bundle.MinAppVersion,
+		MaxAppVersion:  bundle.MaxAppVersion,
+		RolloutPercent: bundle.RolloutPercent,
+		Payload:        bundle.Payload,
+		CreatedAt:      bundle.CreatedAt,
+		UpdatedAt:      bundle.UpdatedAt,
+	}
+}
+
+func isJSONObject(payload json.RawMessage) bool {
+	var object map[string]json.RawMessage
+	return json.Unmarshal(payload, &object) == nil && object != nil
+}
+
+// compareAppVersions compares dotted versions numerically, missing parts count as 0 so 1.4 equals 1.4.0
+func compareAppVersions(a, b string) int {
+	left, right := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(left) || i < len(right); i++ {
+		var l, r int
+		if i < len(left) {
+			l, _ = strconv.Atoi(left[i])
+		}
+		if i < len(right) {
+			r, _ = strconv.Atoi(right[i])
+		}
+		if l != r {
+			if l < r {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// rolloutBucket places the account in 0 to 99 for the bundle name, independently per bundle
+func rolloutBucket(name string, accountId int64) int {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(name + ":" + strconv.FormatInt(accountId, 10)))
+	return int(hash.Sum32() % 100)
+}
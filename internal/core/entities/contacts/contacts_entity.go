@@ -0,0 +1,14 @@
+package contacts
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/domain"
+)
+
+type ContactEntity interface {
+	SaveContactHashesEntity(ctx context.Context, tx *sql.Tx, dto domain.ContactHashesDto) (int64, error)
+	FindContactHashesEntity(ctx context.Context, tx *sql.Tx, accountId int64) ([]string, error)
+	RemoveContactHashEntity(ctx context.Context, tx *sql.Tx, accountId int64, hash string) (int64, error)
+	RemoveAllContactHashesEntity(ctx context.Context, tx *sql.Tx, accountId int64) (int64, error)
+}
@@ -0,0 +1,74 @@
+package contacts
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/repo"
+	"strings"
+)
+
+type ContactEntityImpl struct {
+	ContactExclusionsRepository repo.ContactExclusionsRepository
+	Validate                    *validator.Validate
+}
+
+func NewContactEntityImpl(contactExclusionsRepository repo.ContactExclusionsRepository, validate *validator.Validate) ContactEntity {
+	return &ContactEntityImpl{ContactExclusionsRepository: contactExclusionsRepository, Validate: validate}
+}
+
+func (c ContactEntityImpl) SaveContactHashesEntity(ctx context.Context, tx *sql.Tx, dto domain.ContactHashesDto) (int64, error) {
+	err := c.Validate.Struct(dto)
+	if err != nil {
+		return 0, err
+	}
+
+	// Hex casing is not significant, store lower case and drop duplicates from the upload
+	seen := make(map[string]bool, len(dto.Hashes))
+	var hashes []string
+	for _, hash := range dto.Hashes {
+		hash = strings.ToLower(hash)
+		if seen[hash] {
+			continue
+		}
+		seen[hash] = true
+		hashes = append(hashes, hash)
+	}
+
+	total, err := c.ContactExclusionsRepository.InsertContactHashesToDB(ctx, tx, dto.AccountID, hashes)
+	if err != nil {
+		return 0, errors.New("failed to save contact hashes")
+	}
+	return total, nil
+}
+
+func (c ContactEntityImpl) FindContactHashesEntity(ctx context.Context, tx *sql.Tx, accountId int64) ([]string, error) {
+	records, err := c.ContactExclusionsRepository.FindContactHashesByAccountIdFromDB(ctx, tx, accountId)
+	if err != nil {
+		return nil, errors.New("failed to find contact hashes")
+	}
+
+	hashes := make([]string, 0, len(records))
+	for _, rec := range records {
+		hashes = append(hashes, rec.ContactHash)
+	}
+	return hashes, nil
+}
+
+func (c ContactEntityImpl) RemoveContactHashEntity(ctx context.Context, tx *sql.Tx, accountId int64, hash string) (int64, error) {
+	total, err := c.ContactExclusionsRepository.DeleteContactHashFromDB(ctx, tx, accountId, strings.ToLower(hash))
+	if err != nil {
+		return 0, errors.New("failed to remove contact hash")
+	}
+	return total, nil
+}
+
+func (c ContactEntityImpl) RemoveAllContactHashesEntity(ctx context.Context, tx *sql.Tx, accountId int64) (int64, error) {
+	total, err := c.ContactExclusionsRepository.DeleteAllContactHashesFromDB(ctx, tx, accountId)
+	if err != nil {
+		return 0, errors.New("failed to remove contact hashes")
+	}
+	return total, nil
+}
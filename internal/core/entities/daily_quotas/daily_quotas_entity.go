@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"godating-dealls/internal/domain"
+	"time"
 )
 
 type DailyQuotasEntity interface {
@@ -13,4 +14,7 @@ type DailyQuotasEntity interface {
 	UpdateIncreaseSwipeCount(ctx context.Context, tx *sql.Tx, accountId int64) error
 	UpdateTotalQuotasInPremiumAccount(ctx context.Context, tx *sql.Tx, accountId int64) error
 	FindTotalDailyQuotasAndSwipeCount(ctx context.Context, tx *sql.Tx, accountId int64) (domain.DailyQuotasDto, error)
+	AddBonusQuotaEntity(ctx context.Context, tx *sql.Tx, accountId int64, amount int64) error
+	RestoreSwipeQuotaEntity(ctx context.Context, tx *sql.Tx, accountId int64, swipeDate time.Time, quotaUsed bool) error
+	UseSuperLikeQuotaEntity(ctx context.Context, tx *sql.Tx, accountId int64, limit int) error
 }
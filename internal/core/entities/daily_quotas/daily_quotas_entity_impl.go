@@ -12,6 +12,9 @@ import (
 	"time"
 )
 
+// ErrSuperLikeQuotaExhausted is returned when the super likes of today are used up
+var ErrSuperLikeQuotaExhausted = errors.New("no super likes left today, please try next day")
+
 type DailyQuotasEntityImpl struct {
 	DailyQuotaRepository repo.DailyQuotasRepository
 	Validate             *validator.Validate
@@ -82,3 +85,36 @@ func (d DailyQuotasEntityImpl) FindTotalDailyQuotasAndSwipeCount(ctx context.Con
 	}
 	return result, nil
 }
+
+func (d DailyQuotasEntityImpl) AddBonusQuotaEntity(ctx context.Context, tx *sql.Tx, accountId int64, amount int64) error {
+	if amount <= 0 {
+		return errors.New("invalid bonus quota")
+	}
+
+	err := d.DailyQuotaRepository.UpdateIncreaseTotalQuota(ctx, tx, record.DailyQuotaRecord{AccountID: accountId, TotalQuota: amount})
+	if err != nil {
+		return errors.New("failed to add bonus quota")
+	}
+	return nil
+}
+
+// RestoreSwipeQuotaEntity gives back the swipe of an undone swipe on the quota of the day it was made
+func (d DailyQuotasEntityImpl) RestoreSwipeQuotaEntity(ctx context.Context, tx *sql.Tx, accountId int64, swipeDate time.Time, quotaUsed bool) error {
+	err := d.DailyQuotaRepository.UpdateRestoreSwipeQuota(ctx, tx, record.DailyQuotaRecord{AccountID: accountId, Date: swipeDate}, quotaUsed)
+	if err != nil {
+		return errors.New("failed to restore swipe quota")
+	}
+	return nil
+}
+
+// UseSuperLikeQuotaEntity takes one super like of today, the super like quota is apart from the swipe quota
+func (d DailyQuotasEntityImpl) UseSuperLikeQuotaEntity(ctx context.Context, tx *sql.Tx, accountId int64, limit int) error {
+	taken, err := d.DailyQuotaRepository.UpdateIncreaseSuperLikeCount(ctx, tx, record.DailyQuotaRecord{AccountID: accountId}, limit)
+	if err != nil {
+		return errors.New("failed to update super like count")
+	}
+	if !taken {
+		return ErrSuperLikeQuotaExhausted
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package daily_quotas
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/infra/mysql/repo"
+	"godating-dealls/internal/infra/sqlite/sqlitetest"
+	"testing"
+)
+
+func BenchmarkUpdateIncreaseSwipeCountAndDecreaseTotalQuota(b *testing.B) {
+	db := sqlitetest.Open(b)
+	account := sqlitetest.CreateAccounts(b, db, 1, b.N)[0]
+	entity := NewDailyQuotasEntityImpl(common.NewValidator(), repo.NewDailyQuotasRepositoryImpl())
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := common.WithExecuteTransactionalManager(ctx, db, func(tx *sql.Tx) error {
+			return entity.UpdateIncreaseSwipeCountAndDecreaseTotalQuota(ctx, tx, account.AccountId)
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package dormancy
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/domain"
+	"time"
+)
+
+type DormancyEntity interface {
+	FindInactiveAccountsEntity(ctx context.Context, tx *sql.Tx, state string, inactiveBefore time.Time, stateBefore time.Time, limit int) ([]domain.AccountDormancyDto, error)
+	TransitionEntity(ctx context.Context, tx *sql.Tx, dormancy domain.AccountDormancyDto, toState string) error
+	ReactivateEntity(ctx context.Context, tx *sql.Tx, accountId int64) (bool, error)
+	DeleteAccountEntity(ctx context.Context, tx *sql.Tx, accountId int64) (domain.AccountDormancyDto, error)
+}
@@ -0,0 +1,148 @@
+package dormancy
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/record"
+	"godating-dealls/internal/infra/mysql/repo"
+	"time"
+)
+
+// nextState is the only transition allowed from each state, so a run can never skip the warning
+var nextState = map[string]string{
+	domain.DormancyStateActive:  domain.DormancyStateWarned,
+	domain.DormancyStateWarned:  domain.DormancyStateHidden,
+	domain.DormancyStateHidden:  domain.DormancyStatePurged,
+	domain.DormancyStateDeleted: domain.DormancyStatePurged,
+}
+
+type DormancyEntityImpl struct {
+	AccountDormancyRepository repo.AccountDormancyRepository
+}
+
+func NewDormancyEntityImpl(accountDormancyRepository repo.AccountDormancyRepository) DormancyEntity {
+	return &DormancyEntityImpl{AccountDormancyRepository: accountDormancyRepository}
+}
+
+func (d DormancyEntityImpl) FindInactiveAccountsEntity(ctx context.Context, tx *sql.Tx, state string, inactiveBefore time.Time, stateBefore time.Time, limit int) ([]domain.AccountDormancyDto, error) {
+	records, err := d.AccountDormancyRepository.FindInactiveAccountsFromDB(ctx, tx, state, inactiveBefore, stateBefore, limit)
+	if err != nil {
+		return nil, errors.New("failed to find inactive accounts")
+	}
+
+	var accounts []domain.AccountDormancyDto
+	for _, rec := range records {
+		accounts = append(accounts, domain.AccountDormancyDto{
+			AccountID:    rec.AccountID,
+			State:        rec.State,
+			LastActiveAt: rec.LastActiveAt,
+		})
+	}
+	return accounts, nil
+}
+
+// TransitionEntity moves the account one state forward and records the event, purging also removes the account data
+// and purging a deleted account erases it
+func (d DormancyEntityImpl) TransitionEntity(ctx context.Context, tx *sql.Tx, dormancy domain.AccountDormancyDto, toState string) error {
+	if nextState[dormancy.State] != toState {
+		return errors.New("invalid dormancy transition")
+	}
+
+	switch {
+	case toState == domain.DormancyStatePurged && dormancy.State == domain.DormancyStateDeleted:
+		if err := d.AccountDormancyRepository.EraseAccountDataFromDB(ctx, tx, dormancy.AccountID); err != nil {
+			return err
+		}
+	case toState == domain.DormancyStatePurged:
+		if err := d.AccountDormancyRepository.PurgeAccountDataFromDB(ctx, tx, dormancy.AccountID); err != nil {
+			return err
+		}
+	}
+
+	err := d.AccountDormancyRepository.UpsertDormancyStateToDB(ctx, tx, record.AccountDormancyRecord{
+		AccountID:    dormancy.AccountID,
+		State:        toState,
+		LastActiveAt: dormancy.LastActiveAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	return d.AccountDormancyRepository.InsertDormancyEventToDB(ctx, tx, record.AccountDormancyEventRecord{
+		AccountID:    dormancy.AccountID,
+		FromState:    dormancy.State,
+		ToState:      toState,
+		LastActiveAt: dormancy.LastActiveAt,
+	})
+}
+
+// ReactivateEntity clears a warned or hidden state when the account is used again, a purged account stays purged.
+// Logging in to a deleted account before it is purged cancels the deletion
+func (d DormancyEntityImpl) ReactivateEntity(ctx context.Context, tx *sql.Tx, accountId int64) (bool, error) {
+	dormancy, err := d.AccountDormancyRepository.FindDormancyStateFromDB(ctx, tx, accountId)
+	if err != nil {
+		// No state means the account was already active
+		return false, nil
+	}
+	if dormancy.State == domain.DormancyStatePurged {
+		return false, errors.New("account is purged")
+	}
+
+	if err := d.AccountDormancyRepository.DeleteDormancyStateFromDB(ctx, tx, accountId); err != nil {
+		return false, err
+	}
+
+	err = d.AccountDormancyRepository.InsertDormancyEventToDB(ctx, tx, record.AccountDormancyEventRecord{
+		AccountID:    accountId,
+		FromState:    dormancy.State,
+		ToState:      "reactivated",
+		LastActiveAt: time.Now(),
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DeleteAccountEntity hides the account the owner deleted right away, it is purged once the deletion grace passed.
+// Deleting it again keeps the time of the first deletion
+func (d DormancyEntityImpl) DeleteAccountEntity(ctx context.Context, tx *sql.Tx, accountId int64) (domain.AccountDormancyDto, error) {
+	fromState := domain.DormancyStateActive
+	dormancy, err := d.AccountDormancyRepository.FindDormancyStateFromDB(ctx, tx, accountId)
+	if err == nil {
+		fromState = dormancy.State
+	}
+	switch fromState {
+	case domain.DormancyStatePurged:
+		return domain.AccountDormancyDto{}, errors.New("account is purged")
+	case domain.DormancyStateDeleted:
+		deleted := domain.AccountDormancyDto{AccountID: accountId, State: fromState, LastActiveAt: dormancy.LastActiveAt}
+		if dormancy.UpdatedAt != nil {
+			deleted.UpdatedAt = *dormancy.UpdatedAt
+		}
+		return deleted, nil
+	}
+
+	now := time.Now()
+	err = d.AccountDormancyRepository.UpsertDormancyStateToDB(ctx, tx, record.AccountDormancyRecord{
+		AccountID:    accountId,
+		State:        domain.DormancyStateDeleted,
+		LastActiveAt: now,
+	})
+	if err != nil {
+		return domain.AccountDormancyDto{}, err
+	}
+
+	err = d.AccountDormancyRepository.InsertDormancyEventToDB(ctx, tx, record.AccountDormancyEventRecord{
+		AccountID:    accountId,
+		FromState:    fromState,
+		ToState:      domain.DormancyStateDeleted,
+		LastActiveAt: now,
+	})
+	if err != nil {
+		return domain.AccountDormancyDto{}, err
+	}
+	return domain.AccountDormancyDto{AccountID: accountId, State: domain.DormancyStateDeleted, LastActiveAt: now, UpdatedAt: now}, nil
+}
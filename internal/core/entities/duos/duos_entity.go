@@ -0,0 +1,17 @@
+package duos
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/domain"
+)
+
+type DuoEntity interface {
+	InviteDuoPartnerEntity(ctx context.Context, tx *sql.Tx, accountId int64, partnerAccountId int64) (domain.DuoDto, error)
+	AcceptDuoEntity(ctx context.Context, tx *sql.Tx, accountId int64, duoId int64) (domain.DuoDto, error)
+	FindCurrentDuoEntity(ctx context.Context, tx *sql.Tx, accountId int64) (domain.DuoDto, error)
+	UnlinkDuoEntity(ctx context.Context, tx *sql.Tx, accountId int64) (domain.DuoDto, error)
+	SwipeDuoEntity(ctx context.Context, tx *sql.Tx, accountId int64, targetDuoId int64, action string) (domain.DuoDto, domain.DuoDto, bool, error)
+	FindDuoCandidatesEntity(ctx context.Context, tx *sql.Tx, accountId int64, limit int) ([]domain.DuoCandidateDto, error)
+	FindDuoMatchesEntity(ctx context.Context, tx *sql.Tx, accountId int64) ([]domain.DuoMatchDto, error)
+}
@@ -0,0 +1,274 @@
+package duos
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/record"
+	"godating-dealls/internal/infra/mysql/repo"
+)
+
+// Duo states, an invite is pending until the partner accepts it and an unlinked duo never comes back
+const (
+	DuoStatePending  = "pending"
+	DuoStateActive   = "active"
+	DuoStateUnlinked = "unlinked"
+)
+
+var (
+	ErrInvalidDuo   = errors.New("invalid duo")
+	ErrDuoNotFound  = errors.New("duo not found")
+	ErrAlreadyInDuo = errors.New("already linked in a duo, unlink it first")
+	ErrDuoNotActive = errors.New("your duo is not active yet, your partner has to accept the invite first")
+)
+
+type DuoEntityImpl struct {
+	DuosRepository    repo.DuosRepository
+	AccountRepository repo.AccountRepository
+}
+
+func NewDuoEntityImpl(duosRepository repo.DuosRepository, accountRepository repo.AccountRepository) DuoEntity {
+	return &DuoEntityImpl{DuosRepository: duosRepository, AccountRepository: accountRepository}
+}
+
+// InviteDuoPartnerEntity creates a pending duo, neither account may already be in a pending or active duo
+func (d DuoEntityImpl) InviteDuoPartnerEntity(ctx context.Context, tx *sql.Tx, accountId int64, partnerAccountId int64) (domain.DuoDto, error) {
+	if partnerAccountId <= 0 || partnerAccountId == accountId {
+		return domain.DuoDto{}, fmt.Errorf("%w: partner_account_id must be another account", ErrInvalidDuo)
+	}
+
+	if err := d.DuosRepository.LockDuoAccountsFromDB(ctx, tx, accountId, partnerAccountId); err != nil {
+		return domain.DuoDto{}, errors.New("failed to lock duo accounts")
+	}
+
+	partner, err := d.AccountRepository.FindAccountByIdFromDB(ctx, tx, partnerAccountId)
+	if err != nil {
+		return domain.DuoDto{}, errors.New("failed to find account")
+	}
+	if partner.AccountID == 0 {
+		return domain.DuoDto{}, fmt.Errorf("%w: partner account does not exist", ErrInvalidDuo)
+	}
+
+	for _, member := range []int64{accountId, partnerAccountId} {
+		if _, err := d.DuosRepository.FindCurrentDuoFromDB(ctx, tx, member); err == nil {
+			return domain.DuoDto{}, ErrAlreadyInDuo
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return domain.DuoDto{}, errors.New("failed to find duo")
+		}
+	}
+
+	low, high := accountId, partnerAccountId
+	if high < low {
+		low, high = high, low
+	}
+	duo, err := d.DuosRepository.InsertDuoToDB(ctx, tx, record.DuoRecord{
+		AccountIDLow:       low,
+		AccountIDHigh:      high,
+		InvitedByAccountID: accountId,
+		State:              DuoStatePending,
+	})
+	if err != nil {
+		return domain.DuoDto{}, errors.New("failed to create duo")
+	}
+	return toDuoDto(duo), nil
+}
+
+// AcceptDuoEntity activates a pending duo, only the invited partner can accept it
+func (d DuoEntityImpl) AcceptDuoEntity(ctx context.Context, tx *sql.Tx, accountId int64, duoId int64) (domain.DuoDto, error) {
+	duo, err := d.DuosRepository.FindDuoByIdFromDB(ctx, tx, duoId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.DuoDto{}, ErrDuoNotFound
+		}
+		return domain.DuoDto{}, errors.New("failed to find duo")
+	}
+	if !isDuoMember(duo, accountId) || duo.InvitedByAccountID == accountId || duo.State != DuoStatePending {
+		return domain.DuoDto{}, ErrDuoNotFound
+	}
+
+	if err := d.DuosRepository.LockDuoAccountsFromDB(ctx, tx, duo.AccountIDLow, duo.AccountIDHigh); err != nil {
+		return domain.DuoDto{}, errors.New("failed to lock duo accounts")
+	}
+	// Read again under the lock, the inviter may have cancelled in the meantime
+	if duo, err = d.DuosRepository.FindDuoByIdFromDB(ctx, tx, duoId); err != nil || duo.State != DuoStatePending {
+		return domain.DuoDto{}, ErrDuoNotFound
+	}
+
+	if err := d.DuosRepository.UpdateDuoStateToDB(ctx, tx, duoId, DuoStateActive); err != nil {
+		return domain.DuoDto{}, errors.New("failed to accept duo")
+	}
+	duo, err = d.DuosRepository.FindDuoByIdFromDB(ctx, tx, duoId)
+	if err != nil {
+		return domain.DuoDto{}, errors.New("failed to find duo")
+	}
+	return toDuoDto(duo), nil
+}
+
+func (d DuoEntityImpl) FindCurrentDuoEntity(ctx context.Context, tx *sql.Tx, accountId int64) (domain.DuoDto, error) {
+	duo, err := d.DuosRepository.FindCurrentDuoFromDB(ctx, tx, accountId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.DuoDto{}, ErrDuoNotFound
+		}
+		return domain.DuoDto{}, errors.New("failed to find duo")
+	}
+	return toDuoDto(duo), nil
+}
+
+// UnlinkDuoEntity ends the current duo of the account, it also cancels or declines a pending invite.
+// The duo matches and their conversations stay
+func (d DuoEntityImpl) UnlinkDuoEntity(ctx context.Context, tx *sql.Tx, accountId int64) (domain.DuoDto, error) {
+	duo, err := d.FindCurrentDuoEntity(ctx, tx, accountId)
+	if err != nil {
+		return domain.DuoDto{}, err
+	}
+
+	if err := d.DuosRepository.UpdateDuoStateToDB(ctx, tx, duo.DuoID, DuoStateUnlinked); err != nil {
+		return domain.DuoDto{}, errors.New("failed to unlink duo")
+	}
+	duo.State = DuoStateUnlinked
+	return duo, nil
+}
+
+// SwipeDuoEntity stores the swipe of the active duo of the account on another active duo, and returns both duos
+// and whether the other duo liked back. The duo pair is locked first so a like back is not missed
+func (d DuoEntityImpl) SwipeDuoEntity(ctx context.Context, tx *sql.Tx, accountId int64, targetDuoId int64, action string) (domain.DuoDto, domain.DuoDto, bool, error) {
+	if action != "left" && action != "right" {
+		return domain.DuoDto{}, domain.DuoDto{}, false, fmt.Errorf("%w: action_type must be left or right", ErrInvalidDuo)
+	}
+
+	duo, err := d.activeDuo(ctx, tx, accountId)
+	if err != nil {
+		return domain.DuoDto{}, domain.DuoDto{}, false, err
+	}
+	if targetDuoId == duo.DuoID {
+		return domain.DuoDto{}, domain.DuoDto{}, false, fmt.Errorf("%w: duo_id must be another duo", ErrInvalidDuo)
+	}
+
+	target, err := d.DuosRepository.FindDuoByIdFromDB(ctx, tx, targetDuoId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.DuoDto{}, domain.DuoDto{}, false, ErrDuoNotFound
+		}
+		return domain.DuoDto{}, domain.DuoDto{}, false, errors.New("failed to find duo")
+	}
+	if target.State != DuoStateActive {
+		return domain.DuoDto{}, domain.DuoDto{}, false, ErrDuoNotFound
+	}
+
+	if err := d.DuosRepository.LockDuoPairFromDB(ctx, tx, duo.DuoID, targetDuoId); err != nil {
+		return domain.DuoDto{}, domain.DuoDto{}, false, errors.New("failed to lock duo pair")
+	}
+
+	actionType := "LIKED"
+	if action == "left" {
+		actionType = "PASSED"
+	}
+	err = d.DuosRepository.UpsertDuoSwipeToDB(ctx, tx, record.DuoSwipeRecord{
+		DuoID:             duo.DuoID,
+		TargetDuoID:       targetDuoId,
+		SwipedByAccountID: accountId,
+		Action:            actionType,
+	})
+	if err != nil {
+		return domain.DuoDto{}, domain.DuoDto{}, false, errors.New("failed to insert duo swipe")
+	}
+
+	if actionType != "LIKED" {
+		return duo, toDuoDto(target), false, nil
+	}
+	likedBack, err := d.DuosRepository.FindDuoLikedFromDB(ctx, tx, targetDuoId, duo.DuoID)
+	if err != nil {
+		return domain.DuoDto{}, domain.DuoDto{}, false, errors.New("failed to find duo like")
+	}
+	return duo, toDuoDto(target), likedBack, nil
+}
+
+func (d DuoEntityImpl) FindDuoCandidatesEntity(ctx context.Context, tx *sql.Tx, accountId int64, limit int) ([]domain.DuoCandidateDto, error) {
+	duo, err := d.activeDuo(ctx, tx, accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := d.DuosRepository.FindDuoCandidatesFromDB(ctx, tx, record.DuoRecord{
+		DuoID:         duo.DuoID,
+		AccountIDLow:  duo.AccountIDLow,
+		AccountIDHigh: duo.AccountIDHigh,
+	}, limit)
+	if err != nil {
+		return nil, errors.New("failed to find duo candidates")
+	}
+
+	var res []domain.DuoCandidateDto
+	for _, member := range members {
+		if len(res) == 0 || res[len(res)-1].DuoID != member.DuoID {
+			res = append(res, domain.DuoCandidateDto{DuoID: member.DuoID})
+		}
+		last := &res[len(res)-1]
+		last.Members = append(last.Members, toDuoMemberDto(member.AccountID, member.FullName, member.Age, member.Gender, member.Bio))
+	}
+	return res, nil
+}
+
+func (d DuoEntityImpl) FindDuoMatchesEntity(ctx context.Context, tx *sql.Tx, accountId int64) ([]domain.DuoMatchDto, error) {
+	members, err := d.DuosRepository.FindDuoMatchesFromDB(ctx, tx, accountId)
+	if err != nil {
+		return nil, errors.New("failed to find duo matches")
+	}
+
+	var res []domain.DuoMatchDto
+	for _, member := range members {
+		if len(res) == 0 || res[len(res)-1].MatchID != member.MatchID {
+			res = append(res, domain.DuoMatchDto{MatchID: member.MatchID, CreatedAt: member.CreatedAt})
+		}
+		last := &res[len(res)-1]
+		last.Members = append(last.Members, toDuoMemberDto(member.AccountID, member.FullName, member.Age, member.Gender, member.Bio))
+	}
+	return res, nil
+}
+
+func (d DuoEntityImpl) activeDuo(ctx context.Context, tx *sql.Tx, accountId int64) (domain.DuoDto, error) {
+	duo, err := d.FindCurrentDuoEntity(ctx, tx, accountId)
+	if err != nil {
+		return domain.DuoDto{}, err
+	}
+	if duo.State != DuoStateActive {
+		return domain.DuoDto{}, ErrDuoNotActive
+	}
+	return duo, nil
+}
+
+func isDuoMember(duo record.DuoRecord, accountId int64) bool {
+	return duo.AccountIDLow == accountId || duo.AccountIDHigh == accountId
+}
+
+func toDuoDto(duo record.DuoRecord) domain.DuoDto {
+	return domain.DuoDto{
+		DuoID:              duo.DuoID,
+		AccountIDLow:       duo.AccountIDLow,
+		AccountIDHigh:      duo.AccountIDHigh,
+		InvitedByAccountID: duo.InvitedByAccountID,
+		State:              duo.State,
+		CreatedAt:          duo.CreatedAt,
+		LinkedAt:           duo.LinkedAt,
+	}
+}
+
+func toDuoMemberDto(accountId int64, fullName *string, age *int, gender *string, bio *string) domain.DuoMemberDto {
+	member := domain.DuoMemberDto{AccountID: accountId}
+	if fullName != nil {
+		member.FullName = *fullName
+	}
+	if age != nil {
+		member.Age = *age
+	}
+	if gender != nil {
+		member.Gender = *gender
+	}
+	if bio != nil {
+		member.Bio = *bio
+	}
+	return member
+}
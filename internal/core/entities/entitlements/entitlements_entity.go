@@ -0,0 +1,21 @@
+package entitlements
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/domain"
+)
+
+// Features a premium subscription grants
+const (
+	EntitlementUnlimitedSwipes = "unlimited_swipes"
+	EntitlementVerifiedBadge   = "verified_badge"
+	EntitlementSwipeUndo       = "swipe_undo"
+	EntitlementLikesReceived   = "likes_received"
+)
+
+type EntitlementEntity interface {
+	FindEntitlementsEntity(ctx context.Context, tx *sql.Tx, accountId int64) (domain.EntitlementsDto, error)
+	HasEntitlementEntity(ctx context.Context, tx *sql.Tx, accountId int64, entitlement string) (bool, error)
+	ExpireLapsedPremiumsEntity(ctx context.Context, tx *sql.Tx) (int, error)
+}
@@ -0,0 +1,114 @@
+package entitlements
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/record"
+	"godating-dealls/internal/infra/mysql/repo"
+	"time"
+)
+
+// freeDailyQuota is the daily swipe limit of an account without unlimited swipes
+const freeDailyQuota = 10
+
+var ErrUnknownEntitlement = errors.New("unknown entitlement")
+
+type EntitlementEntityImpl struct {
+	PurchasePackagesRepository repo.PurchasePackagesRepository
+	AccountsRepository         repo.AccountRepository
+	DailyQuotasRepository      repo.DailyQuotasRepository
+}
+
+func NewEntitlementEntityImpl(purchasePackagesRepository repo.PurchasePackagesRepository,
+	accountsRepository repo.AccountRepository,
+	dailyQuotasRepository repo.DailyQuotasRepository) EntitlementEntity {
+	return &EntitlementEntityImpl{
+		PurchasePackagesRepository: purchasePackagesRepository,
+		AccountsRepository:         accountsRepository,
+		DailyQuotasRepository:      dailyQuotasRepository,
+	}
+}
+
+// FindEntitlementsEntity reads the entitlements from the subscriptions running now. The verified flag of the account
+// and an unlimited quota of today are what other queries see, they are taken back here when the subscription granting
+// them has ended, so a lapsed premium never outlives its expiry even before the expiry job runs
+func (e EntitlementEntityImpl) FindEntitlementsEntity(ctx context.Context, tx *sql.Tx, accountId int64) (domain.EntitlementsDto, error) {
+	premiums, err := e.PurchasePackagesRepository.FindActiveAccountPremiumsFromDB(ctx, tx, accountId, time.Now())
+	if err != nil {
+		return domain.EntitlementsDto{}, errors.New("failed to find account premiums")
+	}
+
+	entitlements := toEntitlementsDto(premiums)
+	if !entitlements.Premium {
+		if err := e.AccountsRepository.UpdateAccountUnverifiedByAccountIdFromDB(ctx, tx, accountId); err != nil {
+			return domain.EntitlementsDto{}, errors.New("failed to revoke lapsed premium")
+		}
+	}
+	if !entitlements.UnlimitedSwipes {
+		err := e.DailyQuotasRepository.UpdateTotalQuotaOnPremiumLapse(ctx, tx, record.DailyQuotaRecord{AccountID: accountId, TotalQuota: freeDailyQuota})
+		if err != nil {
+			return domain.EntitlementsDto{}, errors.New("failed to revoke lapsed unlimited quota")
+		}
+	}
+	return entitlements, nil
+}
+
+func (e EntitlementEntityImpl) HasEntitlementEntity(ctx context.Context, tx *sql.Tx, accountId int64, entitlement string) (bool, error) {
+	entitlements, err := e.FindEntitlementsEntity(ctx, tx, accountId)
+	if err != nil {
+		return false, err
+	}
+
+	switch entitlement {
+	case EntitlementUnlimitedSwipes:
+		return entitlements.UnlimitedSwipes, nil
+	case EntitlementVerifiedBadge:
+		return entitlements.VerifiedBadge, nil
+	case EntitlementSwipeUndo:
+		return entitlements.SwipeUndo, nil
+	case EntitlementLikesReceived:
+		return entitlements.LikesReceived, nil
+	default:
+		return false, fmt.Errorf("%w: %s", ErrUnknownEntitlement, entitlement)
+	}
+}
+
+// ExpireLapsedPremiumsEntity revokes the premium of every account whose subscriptions all ended, for the accounts
+// that do not come back to be checked one by one
+func (e EntitlementEntityImpl) ExpireLapsedPremiumsEntity(ctx context.Context, tx *sql.Tx) (int, error) {
+	accountIds, err := e.PurchasePackagesRepository.FindLapsedPremiumAccountIdsFromDB(ctx, tx, time.Now())
+	if err != nil {
+		return 0, errors.New("failed to find lapsed premium accounts")
+	}
+
+	for _, accountId := range accountIds {
+		if _, err := e.FindEntitlementsEntity(ctx, tx, accountId); err != nil {
+			return 0, err
+		}
+	}
+	return len(accountIds), nil
+}
+
+// toEntitlementsDto merges the running subscriptions, they come the one lasting longest first
+func toEntitlementsDto(premiums []record.AccountPremiumRecord) domain.EntitlementsDto {
+	if len(premiums) == 0 {
+		return domain.EntitlementsDto{}
+	}
+
+	expiresAt := premiums[0].ExpiryDate
+	entitlements := domain.EntitlementsDto{
+		Premium:       true,
+		VerifiedBadge: true,
+		SwipeUndo:     true,
+		LikesReceived: true,
+		PackageID:     premiums[0].PackageID,
+		ExpiresAt:     &expiresAt,
+	}
+	for _, premium := range premiums {
+		entitlements.UnlimitedSwipes = entitlements.UnlimitedSwipes || premium.UnlimitedSwipesActive
+	}
+	return entitlements
+}
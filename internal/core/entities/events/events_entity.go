@@ -0,0 +1,18 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/domain"
+	"time"
+)
+
+type EventEntity interface {
+	CreateEventEntity(ctx context.Context, tx *sql.Tx, dto domain.EventDto) (domain.EventDto, error)
+	UpdateEventRoomEntity(ctx context.Context, tx *sql.Tx, eventId int64, roomMatchId int64) error
+	LockLiveEventEntity(ctx context.Context, tx *sql.Tx, eventId int64, now time.Time) (domain.EventDto, error)
+	FindEventsEntity(ctx context.Context, tx *sql.Tx, now time.Time) ([]domain.EventDto, error)
+	FindEndedEventRoomsEntity(ctx context.Context, tx *sql.Tx, now time.Time, limit int) ([]domain.EventDto, error)
+	ConnectEntity(ctx context.Context, tx *sql.Tx, eventId int64, accountId int64, targetAccountId int64) (bool, error)
+	ClearEventRoomEntity(ctx context.Context, tx *sql.Tx, eventId int64) error
+}
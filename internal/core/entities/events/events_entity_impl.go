@@ -0,0 +1,171 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/record"
+	"godating-dealls/internal/infra/mysql/repo"
+	"strings"
+	"time"
+)
+
+// MaxEventDuration keeps the rooms ephemeral, a longer gathering is several events
+const MaxEventDuration = 24 * time.Hour
+
+var (
+	ErrInvalidEvent  = errors.New("invalid event")
+	ErrEventNotFound = errors.New("event not found")
+	ErrEventNotLive  = errors.New("this event is not live, its room opens at the start and closes at the end")
+	ErrNotEventGuest = errors.New("both accounts must have joined the event room")
+)
+
+type EventEntityImpl struct {
+	EventsRepository repo.EventsRepository
+	validate         *validator.Validate
+}
+
+func NewEventEntityImpl(eventsRepository repo.EventsRepository, validate *validator.Validate) EventEntity {
+	return &EventEntityImpl{EventsRepository: eventsRepository, validate: validate}
+}
+
+func (e EventEntityImpl) CreateEventEntity(ctx context.Context, tx *sql.Tx, dto domain.EventDto) (domain.EventDto, error) {
+	dto.Title = strings.TrimSpace(dto.Title)
+	dto.Description = strings.TrimSpace(dto.Description)
+	if err := e.validate.Struct(dto); err != nil {
+		return domain.EventDto{}, fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+	if !dto.EndsAt.After(dto.StartsAt) || dto.EndsAt.Sub(dto.StartsAt) > MaxEventDuration {
+		return domain.EventDto{}, fmt.Errorf("%w: ends_at must be after starts_at and at most %s later", ErrInvalidEvent, MaxEventDuration)
+	}
+	if !dto.EndsAt.After(time.Now()) {
+		return domain.EventDto{}, fmt.Errorf("%w: ends_at must be in the future", ErrInvalidEvent)
+	}
+
+	event, err := e.EventsRepository.InsertEventToDB(ctx, tx, record.EventRecord{
+		Title:       dto.Title,
+		Description: dto.Description,
+		StartsAt:    dto.StartsAt,
+		EndsAt:      dto.EndsAt,
+	})
+	if err != nil {
+		return domain.EventDto{}, errors.New("failed to create event")
+	}
+	return toEventDto(event), nil
+}
+
+func (e EventEntityImpl) UpdateEventRoomEntity(ctx context.Context, tx *sql.Tx, eventId int64, roomMatchId int64) error {
+	if err := e.EventsRepository.UpdateEventRoomToDB(ctx, tx, eventId, &roomMatchId); err != nil {
+		return errors.New("failed to update event room")
+	}
+	return nil
+}
+
+// LockLiveEventEntity returns the event while it is live, the lock is held until the transaction ends
+func (e EventEntityImpl) LockLiveEventEntity(ctx context.Context, tx *sql.Tx, eventId int64, now time.Time) (domain.EventDto, error) {
+	if err := e.EventsRepository.LockEventFromDB(ctx, tx, eventId); err != nil {
+		return domain.EventDto{}, errors.New("failed to lock event")
+	}
+
+	event, err := e.EventsRepository.FindEventByIdFromDB(ctx, tx, eventId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.EventDto{}, ErrEventNotFound
+		}
+		return domain.EventDto{}, errors.New("failed to find event")
+	}
+
+	dto := toEventDto(event)
+	if EventState(dto, now) != domain.EventStateLive || dto.RoomMatchID == 0 {
+		return domain.EventDto{}, ErrEventNotLive
+	}
+	return dto, nil
+}
+
+func (e EventEntityImpl) FindEventsEntity(ctx context.Context, tx *sql.Tx, now time.Time) ([]domain.EventDto, error) {
+	events, err := e.EventsRepository.FindEventsFromDB(ctx, tx, now)
+	if err != nil {
+		return nil, errors.New("failed to find events")
+	}
+	return toEventDtos(events), nil
+}
+
+func (e EventEntityImpl) FindEndedEventRoomsEntity(ctx context.Context, tx *sql.Tx, now time.Time, limit int) ([]domain.EventDto, error) {
+	events, err := e.EventsRepository.FindEndedEventRoomsFromDB(ctx, tx, now, limit)
+	if err != nil {
+		return nil, errors.New("failed to find ended events")
+	}
+	return toEventDtos(events), nil
+}
+
+// ConnectEntity records that the account chose the target, mutual is true once the target chose the account too.
+// The caller locks the event first so two guests choosing each other at the same time both see it
+func (e EventEntityImpl) ConnectEntity(ctx context.Context, tx *sql.Tx, eventId int64, accountId int64, targetAccountId int64) (bool, error) {
+	if targetAccountId <= 0 || targetAccountId == accountId {
+		return false, fmt.Errorf("%w: account_id must be another guest", ErrInvalidEvent)
+	}
+
+	err := e.EventsRepository.InsertEventConnectionToDB(ctx, tx, record.EventConnectionRecord{
+		EventID:         eventId,
+		AccountID:       accountId,
+		TargetAccountID: targetAccountId,
+	})
+	if err != nil {
+		return false, errors.New("failed to connect")
+	}
+
+	mutual, err := e.EventsRepository.FindEventConnectionFromDB(ctx, tx, eventId, targetAccountId, accountId)
+	if err != nil {
+		return false, errors.New("failed to find connection")
+	}
+	return mutual, nil
+}
+
+// ClearEventRoomEntity unlinks the room and forgets who chose whom, the matches that came out of the event stay
+func (e EventEntityImpl) ClearEventRoomEntity(ctx context.Context, tx *sql.Tx, eventId int64) error {
+	if err := e.EventsRepository.UpdateEventRoomToDB(ctx, tx, eventId, nil); err != nil {
+		return errors.New("failed to clear event room")
+	}
+	if err := e.EventsRepository.DeleteEventConnectionsFromDB(ctx, tx, eventId); err != nil {
+		return errors.New("failed to clear event connections")
+	}
+	return nil
+}
+
+// EventState tells whether the event is upcoming, live or ended at now
+func EventState(event domain.EventDto, now time.Time) string {
+	switch {
+	case now.Before(event.StartsAt):
+		return domain.EventStateUpcoming
+	case now.Before(event.EndsAt):
+		return domain.EventStateLive
+	default:
+		return domain.EventStateEnded
+	}
+}
+
+func toEventDtos(events []record.EventRecord) []domain.EventDto {
+	res := make([]domain.EventDto, 0, len(events))
+	for _, event := range events {
+		res = append(res, toEventDto(event))
+	}
+	return res
+}
+
+func toEventDto(event record.EventRecord) domain.EventDto {
+	dto := domain.EventDto{
+		EventID:     event.EventID,
+		Title:       event.Title,
+		Description: event.Description,
+		StartsAt:    event.StartsAt,
+		EndsAt:      event.EndsAt,
+		CreatedAt:   event.CreatedAt,
+	}
+	if event.RoomMatchID != nil {
+		dto.RoomMatchID = *event.RoomMatchID
+	}
+	return dto
+}
@@ -0,0 +1,13 @@
+package hidden_accounts
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/domain"
+)
+
+type HiddenAccountEntity interface {
+	HideAccountEntity(ctx context.Context, tx *sql.Tx, accountId int64, hiddenAccountId int64) error
+	FindHiddenAccountsEntity(ctx context.Context, tx *sql.Tx, accountId int64) ([]domain.HiddenAccountDto, error)
+	UnhideAccountEntity(ctx context.Context, tx *sql.Tx, accountId int64, hiddenAccountId int64) error
+}
@@ -0,0 +1,84 @@
+package hidden_accounts
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/repo"
+)
+
+// MaxHiddenAccounts keeps the discovery filter cheap, it is checked on every candidate query
+const MaxHiddenAccounts = 500
+
+var (
+	ErrInvalidHiddenAccount    = errors.New("invalid hidden account")
+	ErrHiddenAccountNotFound   = errors.New("account not found")
+	ErrHiddenAccountsLimitFull = fmt.Errorf("at most %d accounts can be hidden, unhide one first", MaxHiddenAccounts)
+)
+
+type HiddenAccountEntityImpl struct {
+	HiddenAccountsRepository repo.HiddenAccountsRepository
+	AccountRepository        repo.AccountRepository
+}
+
+func NewHiddenAccountEntityImpl(hiddenAccountsRepository repo.HiddenAccountsRepository, accountRepository repo.AccountRepository) HiddenAccountEntity {
+	return &HiddenAccountEntityImpl{HiddenAccountsRepository: hiddenAccountsRepository, AccountRepository: accountRepository}
+}
+
+// HideAccountEntity only takes the account out of the owner's discovery, the hidden account is not told and
+// can still see the owner, unlike a block
+func (h HiddenAccountEntityImpl) HideAccountEntity(ctx context.Context, tx *sql.Tx, accountId int64, hiddenAccountId int64) error {
+	if hiddenAccountId <= 0 || hiddenAccountId == accountId {
+		return fmt.Errorf("%w: account_id must be another account", ErrInvalidHiddenAccount)
+	}
+	account, err := h.AccountRepository.FindAccountByIdFromDB(ctx, tx, hiddenAccountId)
+	if err != nil {
+		return errors.New("failed to find account")
+	}
+	if account.AccountID == 0 {
+		return ErrHiddenAccountNotFound
+	}
+
+	total, err := h.HiddenAccountsRepository.CountHiddenAccountsFromDB(ctx, tx, accountId)
+	if err != nil {
+		return errors.New("failed to count hidden accounts")
+	}
+	if total >= MaxHiddenAccounts {
+		return ErrHiddenAccountsLimitFull
+	}
+
+	if err := h.HiddenAccountsRepository.InsertHiddenAccountToDB(ctx, tx, accountId, hiddenAccountId); err != nil {
+		return errors.New("failed to hide account")
+	}
+	return nil
+}
+
+func (h HiddenAccountEntityImpl) FindHiddenAccountsEntity(ctx context.Context, tx *sql.Tx, accountId int64) ([]domain.HiddenAccountDto, error) {
+	records, err := h.HiddenAccountsRepository.FindHiddenAccountsFromDB(ctx, tx, accountId)
+	if err != nil {
+		return nil, errors.New("failed to find hidden accounts")
+	}
+
+	res := make([]domain.HiddenAccountDto, 0, len(records))
+	for _, rec := range records {
+		res = append(res, domain.HiddenAccountDto{
+			AccountID: rec.HiddenAccountID,
+			Username:  rec.HiddenUsername,
+			HiddenAt:  rec.CreatedAt,
+		})
+	}
+	return res, nil
+}
+
+func (h HiddenAccountEntityImpl) UnhideAccountEntity(ctx context.Context, tx *sql.Tx, accountId int64, hiddenAccountId int64) error {
+	removed, err := h.HiddenAccountsRepository.DeleteHiddenAccountFromDB(ctx, tx, accountId, hiddenAccountId)
+	if err != nil {
+		return errors.New("failed to unhide account")
+	}
+	if removed == 0 {
+		return ErrHiddenAccountNotFound
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package integrations
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/domain"
+)
+
+type IntegrationEntity interface {
+	SaveIntegrationEntity(ctx context.Context, tx *sql.Tx, dto domain.IntegrationDto) error
+	FindAllIntegrationsEntity(ctx context.Context, tx *sql.Tx) ([]domain.IntegrationDto, error)
+	ReplaceImportedContentEntity(ctx context.Context, tx *sql.Tx, accountId int64, provider string, contents []domain.ImportedContentDto) error
+	FindImportedContentEntity(ctx context.Context, tx *sql.Tx, accountId int64) ([]domain.ImportedContentDto, error)
+	RemoveIntegrationEntity(ctx context.Context, tx *sql.Tx, accountId int64, provider string) error
+}
@@ -0,0 +1,124 @@
+package integrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/record"
+	"godating-dealls/internal/infra/mysql/repo"
+)
+
+// maxImportedItemsPerProvider keeps imported content a highlight of the profile, not a mirror of the provider
+const maxImportedItemsPerProvider = 10
+
+type IntegrationEntityImpl struct {
+	ProfileIntegrationsRepository repo.ProfileIntegrationsRepository
+	ProfileImportsRepository      repo.ProfileImportsRepository
+}
+
+func NewIntegrationEntityImpl(
+	profileIntegrationsRepository repo.ProfileIntegrationsRepository,
+	profileImportsRepository repo.ProfileImportsRepository) IntegrationEntity {
+	return &IntegrationEntityImpl{
+		ProfileIntegrationsRepository: profileIntegrationsRepository,
+		ProfileImportsRepository:      profileImportsRepository,
+	}
+}
+
+func (i IntegrationEntityImpl) SaveIntegrationEntity(ctx context.Context, tx *sql.Tx, dto domain.IntegrationDto) error {
+	if dto.AccountID == 0 || dto.Provider == "" || dto.AccessToken == "" {
+		return errors.New("invalid integration")
+	}
+
+	err := i.ProfileIntegrationsRepository.UpsertIntegrationToDB(ctx, tx, record.ProfileIntegrationRecord{
+		AccountID:    dto.AccountID,
+		Provider:     dto.Provider,
+		AccessToken:  dto.AccessToken,
+		RefreshToken: dto.RefreshToken,
+		ExpiresAt:    dto.ExpiresAt,
+		LastSyncedAt: dto.LastSyncedAt,
+	})
+	if err != nil {
+		return errors.New("failed to save integration")
+	}
+	return nil
+}
+
+func (i IntegrationEntityImpl) FindAllIntegrationsEntity(ctx context.Context, tx *sql.Tx) ([]domain.IntegrationDto, error) {
+	records, err := i.ProfileIntegrationsRepository.FindAllIntegrationsFromDB(ctx, tx)
+	if err != nil {
+		return nil, errors.New("failed to find integrations")
+	}
+
+	var integrations []domain.IntegrationDto
+	for _, rec := range records {
+		integrations = append(integrations, domain.IntegrationDto{
+			AccountID:    rec.AccountID,
+			Provider:     rec.Provider,
+			AccessToken:  rec.AccessToken,
+			RefreshToken: rec.RefreshToken,
+			ExpiresAt:    rec.ExpiresAt,
+			LastSyncedAt: rec.LastSyncedAt,
+		})
+	}
+	return integrations, nil
+}
+
+// ReplaceImportedContentEntity imported content is a snapshot of the provider, each sync replaces the previous one
+func (i IntegrationEntityImpl) ReplaceImportedContentEntity(ctx context.Context, tx *sql.Tx, accountId int64, provider string, contents []domain.ImportedContentDto) error {
+	err := i.ProfileImportsRepository.DeleteImportsFromDB(ctx, tx, accountId, provider)
+	if err != nil {
+		return errors.New("failed to clear imported content")
+	}
+
+	if len(contents) > maxImportedItemsPerProvider {
+		contents = contents[:maxImportedItemsPerProvider]
+	}
+
+	records := make([]record.ProfileImportRecord, 0, len(contents))
+	for _, content := range contents {
+		records = append(records, record.ProfileImportRecord{
+			AccountID:    accountId,
+			Provider:     provider,
+			ContentType:  content.ContentType,
+			ExternalID:   content.ExternalID,
+			ContentValue: content.Value,
+			ContentURL:   content.URL,
+		})
+	}
+
+	err = i.ProfileImportsRepository.InsertImportsToDB(ctx, tx, records)
+	if err != nil {
+		return errors.New("failed to save imported content")
+	}
+	return nil
+}
+
+func (i IntegrationEntityImpl) FindImportedContentEntity(ctx context.Context, tx *sql.Tx, accountId int64) ([]domain.ImportedContentDto, error) {
+	records, err := i.ProfileImportsRepository.FindImportsByAccountIdFromDB(ctx, tx, accountId)
+	if err != nil {
+		return nil, errors.New("failed to find imported content")
+	}
+
+	var contents []domain.ImportedContentDto
+	for _, rec := range records {
+		contents = append(contents, domain.ImportedContentDto{
+			Provider:    rec.Provider,
+			ContentType: rec.ContentType,
+			ExternalID:  rec.ExternalID,
+			Value:       rec.ContentValue,
+			URL:         rec.ContentURL,
+			ImportedAt:  rec.ImportedAt,
+		})
+	}
+	return contents, nil
+}
+
+func (i IntegrationEntityImpl) RemoveIntegrationEntity(ctx context.Context, tx *sql.Tx, accountId int64, provider string) error {
+	err := i.ProfileImportsRepository.DeleteImportsFromDB(ctx, tx, accountId, provider)
+	if err != nil {
+		return errors.New("failed to clear imported content")
+	}
+	return i.ProfileIntegrationsRepository.DeleteIntegrationFromDB(ctx, tx, accountId, provider)
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"godating-dealls/internal/domain"
+	"time"
 )
 
 type LoginHistoriesEntity interface {
 	SaveLoginHistoriesEntities(ctx context.Context, tx *sql.Tx, dto domain.LoginHistoriesDto) error
 	UpdateLoginHistoriesEntities(ctx context.Context, tx *sql.Tx, dto domain.LoginHistoriesDto) error
+	FindOldestLoginHistoriesEntities(ctx context.Context, tx *sql.Tx, before time.Time) (*time.Time, error)
+	SummarizeLoginHistoriesEntities(ctx context.Context, tx *sql.Tx, from time.Time, to time.Time) (int64, error)
 }
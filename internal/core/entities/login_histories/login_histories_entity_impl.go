@@ -3,11 +3,13 @@ package login_histories
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"github.com/go-playground/validator/v10"
 	"godating-dealls/internal/common"
 	"godating-dealls/internal/domain"
 	"godating-dealls/internal/infra/mysql/record"
 	"godating-dealls/internal/infra/mysql/repo"
+	"time"
 )
 
 type LoginHistoriesEntityImpl struct {
@@ -53,3 +55,30 @@ func (l LoginHistoriesEntityImpl) UpdateLoginHistoriesEntities(ctx context.Conte
 	_ = common.HandleErrorDefault(err)
 	return nil
 }
+
+func (l LoginHistoriesEntityImpl) FindOldestLoginHistoriesEntities(ctx context.Context, tx *sql.Tx, before time.Time) (*time.Time, error) {
+	oldest, err := l.LoginRepository.FindOldestLoginHistoryDB(ctx, tx, before)
+	if err != nil {
+		return nil, errors.New("failed to find oldest login history")
+	}
+	return oldest, nil
+}
+
+// SummarizeLoginHistoriesEntities folds the raw rows of one month window into the summaries and deletes them,
+// both must run in the same transaction so a row is never counted twice or lost
+func (l LoginHistoriesEntityImpl) SummarizeLoginHistoriesEntities(ctx context.Context, tx *sql.Tx, from time.Time, to time.Time) (int64, error) {
+	monthStart := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, from.Location())
+	if !to.After(from) || to.After(monthStart.AddDate(0, 1, 0)) {
+		return 0, errors.New("invalid summary window")
+	}
+
+	if _, err := l.LoginRepository.SummarizeLoginHistoryDB(ctx, tx, from, to); err != nil {
+		return 0, err
+	}
+
+	deleted, err := l.LoginRepository.DeleteLoginHistoryDB(ctx, tx, from, to)
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
@@ -0,0 +1,13 @@
+package match_features
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/domain"
+	"time"
+)
+
+type MatchFeatureEntity interface {
+	ComputeFeaturesEntity(ctx context.Context, tx *sql.Tx, computedAt time.Time, scoresBefore time.Time) (domain.MatchFeatureRunDto, error)
+	SaveMatchScoresEntity(ctx context.Context, tx *sql.Tx, scores []domain.MatchScoreDto) (int, error)
+}
@@ -0,0 +1,78 @@
+package match_features
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/record"
+	"godating-dealls/internal/infra/mysql/repo"
+	"time"
+)
+
+const maxMatchScoresPerBatch = 1000
+
+// ErrInvalidMatchScores rejects the whole batch, the model is expected to resend it corrected
+var ErrInvalidMatchScores = errors.New("invalid match scores")
+
+type MatchFeatureEntityImpl struct {
+	MatchFeaturesRepository repo.MatchFeaturesRepository
+	validate                *validator.Validate
+}
+
+func NewMatchFeatureEntityImpl(matchFeaturesRepository repo.MatchFeaturesRepository, validate *validator.Validate) MatchFeatureEntity {
+	return &MatchFeatureEntityImpl{MatchFeaturesRepository: matchFeaturesRepository, validate: validate}
+}
+
+// ComputeFeaturesEntity rebuilds the feature tables as of computedAt and drops scores written before scoresBefore
+func (m MatchFeatureEntityImpl) ComputeFeaturesEntity(ctx context.Context, tx *sql.Tx, computedAt time.Time, scoresBefore time.Time) (domain.MatchFeatureRunDto, error) {
+	var run domain.MatchFeatureRunDto
+	var err error
+
+	run.AccountFeatures, err = m.MatchFeaturesRepository.ComputeAccountFeaturesToDB(ctx, tx, computedAt)
+	if err != nil {
+		return domain.MatchFeatureRunDto{}, errors.New("failed to compute account features")
+	}
+
+	run.PairFeatures, err = m.MatchFeaturesRepository.ComputePairFeaturesToDB(ctx, tx, computedAt)
+	if err != nil {
+		return domain.MatchFeatureRunDto{}, errors.New("failed to compute pair features")
+	}
+
+	run.StaleFeatures, err = m.MatchFeaturesRepository.DeleteStaleFeaturesToDB(ctx, tx, computedAt)
+	if err != nil {
+		return domain.MatchFeatureRunDto{}, errors.New("failed to delete stale features")
+	}
+
+	run.ExpiredScores, err = m.MatchFeaturesRepository.DeleteExpiredMatchScoresToDB(ctx, tx, scoresBefore)
+	if err != nil {
+		return domain.MatchFeatureRunDto{}, errors.New("failed to delete expired match scores")
+	}
+	return run, nil
+}
+
+func (m MatchFeatureEntityImpl) SaveMatchScoresEntity(ctx context.Context, tx *sql.Tx, scores []domain.MatchScoreDto) (int, error) {
+	if len(scores) == 0 || len(scores) > maxMatchScoresPerBatch {
+		return 0, fmt.Errorf("%w: a batch holds 1 to %d scores", ErrInvalidMatchScores, maxMatchScoresPerBatch)
+	}
+
+	records := make([]record.MatchScoreRecord, 0, len(scores))
+	for i, score := range scores {
+		if err := m.validate.Struct(score); err != nil {
+			return 0, fmt.Errorf("%w: score %d: %v", ErrInvalidMatchScores, i, err)
+		}
+		records = append(records, record.MatchScoreRecord{
+			AccountID:          score.AccountID,
+			CandidateAccountID: score.CandidateAccountID,
+			Score:              score.Score,
+			ModelVersion:       score.ModelVersion,
+		})
+	}
+
+	if _, err := m.MatchFeaturesRepository.UpsertMatchScoresToDB(ctx, tx, records); err != nil {
+		return 0, errors.New("failed to save match scores")
+	}
+	return len(records), nil
+}
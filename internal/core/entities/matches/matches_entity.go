@@ -0,0 +1,25 @@
+package matches
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/domain"
+	"time"
+)
+
+type MatchEntity interface {
+	LockMatchPairEntity(ctx context.Context, tx *sql.Tx, accountId int64, otherAccountId int64) error
+	CreateMatchOnMutualLikeEntity(ctx context.Context, tx *sql.Tx, accountId int64, likedAccountId int64) (domain.MatchDto, bool, error)
+	CreateDuoMatchEntity(ctx context.Context, tx *sql.Tx, accountId int64, duoId int64, likedDuoId int64, participants []int64) (domain.MatchDto, bool, error)
+	FindMatchForAccountEntity(ctx context.Context, tx *sql.Tx, matchId int64, accountId int64) (domain.MatchDto, error)
+	FindMatchesEntity(ctx context.Context, tx *sql.Tx, accountId int64) ([]domain.MatchViewDto, error)
+	CreatePairMatchEntity(ctx context.Context, tx *sql.Tx, accountId int64, otherAccountId int64) (domain.MatchDto, bool, error)
+	FindMatchEntity(ctx context.Context, tx *sql.Tx, matchId int64) (domain.MatchDto, error)
+	CreateGroupMatchEntity(ctx context.Context, tx *sql.Tx, title string, participants []int64, expiresAt *time.Time) (domain.MatchDto, error)
+	AddParticipantEntity(ctx context.Context, tx *sql.Tx, match domain.MatchDto, accountId int64) error
+	LeaveMatchEntity(ctx context.Context, tx *sql.Tx, match domain.MatchDto) error
+	UnmatchEntity(ctx context.Context, tx *sql.Tx, match domain.MatchDto) error
+	MarkReadEntity(ctx context.Context, tx *sql.Tx, matchId int64, accountId int64, messageId int64) error
+	FindParticipantsEntity(ctx context.Context, tx *sql.Tx, matchId int64) ([]domain.ChatParticipantDto, error)
+	DeleteMatchEntity(ctx context.Context, tx *sql.Tx, matchId int64) error
+}
@@ -0,0 +1,327 @@
+package matches
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/record"
+	"godating-dealls/internal/infra/mysql/repo"
+	"strings"
+	"time"
+)
+
+// Match kinds, every kind is a conversation. A pair is two accounts that liked each other, a duo match the four
+// accounts of two duos and a group any set of accounts, e.g. the guests of an event
+const (
+	MatchKindPair  = "pair"
+	MatchKindDuo   = "duo"
+	MatchKindGroup = "group"
+)
+
+const (
+	MaxGroupParticipants = 50
+	maxGroupTitle        = 100
+)
+
+// ErrNotMatched is returned for a match that does not exist or that the account is not part of, the two are not told apart
+var ErrNotMatched = errors.New("you are not matched with this account")
+
+var (
+	ErrChatEnded      = errors.New("this chat has ended")
+	ErrInvalidGroup   = errors.New("invalid group chat")
+	ErrGroupFull      = fmt.Errorf("a group chat has at most %d participants", MaxGroupParticipants)
+	ErrLeavePairMatch = errors.New("only group chats can be left, a chat of two ends with the match")
+	ErrUnmatchGroup   = errors.New("only a match of two can be unmatched, a duo or group chat is left instead")
+)
+
+type MatchEntityImpl struct {
+	MatchesRepository repo.MatchesRepository
+}
+
+func NewMatchEntityImpl(matchesRepository repo.MatchesRepository) MatchEntity {
+	return &MatchEntityImpl{MatchesRepository: matchesRepository}
+}
+
+// LockMatchPairEntity must run before the like is stored, it keeps concurrent likes of the same pair in order
+func (m MatchEntityImpl) LockMatchPairEntity(ctx context.Context, tx *sql.Tx, accountId int64, otherAccountId int64) error {
+	if err := m.MatchesRepository.LockMatchPairFromDB(ctx, tx, accountId, otherAccountId); err != nil {
+		return errors.New("failed to lock match pair")
+	}
+	return nil
+}
+
+// CreateMatchOnMutualLikeEntity matches the pair when the liked account already liked back,
+// matched is false when it did not or when the pair was matched before
+func (m MatchEntityImpl) CreateMatchOnMutualLikeEntity(ctx context.Context, tx *sql.Tx, accountId int64, likedAccountId int64) (domain.MatchDto, bool, error) {
+	likedBack, err := m.MatchesRepository.FindLikedFromDB(ctx, tx, likedAccountId, accountId)
+	if err != nil {
+		return domain.MatchDto{}, false, errors.New("failed to find like")
+	}
+	if !likedBack {
+		return domain.MatchDto{}, false, nil
+	}
+	return m.CreatePairMatchEntity(ctx, tx, accountId, likedAccountId)
+}
+
+// CreatePairMatchEntity matches the two accounts without looking at likes, e.g. after both chose each other at an event.
+// created is false when the pair was matched before
+func (m MatchEntityImpl) CreatePairMatchEntity(ctx context.Context, tx *sql.Tx, accountId int64, otherAccountId int64) (domain.MatchDto, bool, error) {
+	low, high := accountId, otherAccountId
+	if high < low {
+		low, high = high, low
+	}
+	match, created, err := m.MatchesRepository.InsertMatchToDB(ctx, tx, record.MatchRecord{AccountIDLow: low, AccountIDHigh: high})
+	if err != nil {
+		return domain.MatchDto{}, false, errors.New("failed to create match")
+	}
+
+	return domain.MatchDto{
+		MatchID:               match.MatchID,
+		Kind:                  MatchKindPair,
+		AccountID:             accountId,
+		MatchedAccountID:      otherAccountId,
+		ParticipantAccountIDs: []int64{accountId, otherAccountId},
+		CreatedAt:             match.CreatedAt,
+	}, created, nil
+}
+
+// CreateDuoMatchEntity matches two duos that liked each other, the caller checks the likes.
+// matched is false when the duos were matched before
+func (m MatchEntityImpl) CreateDuoMatchEntity(ctx context.Context, tx *sql.Tx, accountId int64, duoId int64, likedDuoId int64, participants []int64) (domain.MatchDto, bool, error) {
+	low, high := duoId, likedDuoId
+	if high < low {
+		low, high = high, low
+	}
+	match, created, err := m.MatchesRepository.InsertDuoMatchToDB(ctx, tx, record.MatchRecord{DuoIDLow: &low, DuoIDHigh: &high}, participants)
+	if err != nil {
+		return domain.MatchDto{}, false, errors.New("failed to create match")
+	}
+
+	return domain.MatchDto{
+		MatchID:               match.MatchID,
+		Kind:                  MatchKindDuo,
+		AccountID:             accountId,
+		ParticipantAccountIDs: participants,
+		CreatedAt:             match.CreatedAt,
+	}, created, nil
+}
+
+// FindMatchForAccountEntity returns the match seen from the account, MatchedAccountID is the other side of a pair.
+// For a duo match or a group the account must be one of the current participants, an ephemeral group that ended is ErrChatEnded
+func (m MatchEntityImpl) FindMatchForAccountEntity(ctx context.Context, tx *sql.Tx, matchId int64, accountId int64) (domain.MatchDto, error) {
+	match, err := m.FindMatchEntity(ctx, tx, matchId)
+	if err != nil {
+		return domain.MatchDto{}, err
+	}
+
+	member := false
+	for _, participant := range match.ParticipantAccountIDs {
+		member = member || participant == accountId
+	}
+	if !member {
+		return domain.MatchDto{}, ErrNotMatched
+	}
+	if match.ExpiresAt != nil && !time.Now().Before(*match.ExpiresAt) {
+		return domain.MatchDto{}, ErrChatEnded
+	}
+
+	match.AccountID = accountId
+	if match.Kind == MatchKindPair {
+		for _, participant := range match.ParticipantAccountIDs {
+			if participant != accountId {
+				match.MatchedAccountID = participant
+			}
+		}
+		match.ParticipantAccountIDs = []int64{accountId, match.MatchedAccountID}
+	}
+	return match, nil
+}
+
+// FindMatchEntity returns the match as nobody in particular sees it, AccountID and MatchedAccountID are 0
+func (m MatchEntityImpl) FindMatchEntity(ctx context.Context, tx *sql.Tx, matchId int64) (domain.MatchDto, error) {
+	match, err := m.MatchesRepository.FindMatchByIdFromDB(ctx, tx, matchId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.MatchDto{}, ErrNotMatched
+		}
+		return domain.MatchDto{}, errors.New("failed to find match")
+	}
+
+	res := domain.MatchDto{MatchID: match.MatchID, Kind: matchKind(match), ExpiresAt: match.ExpiresAt, CreatedAt: match.CreatedAt}
+	if match.Title != nil {
+		res.Title = *match.Title
+	}
+	if res.Kind == MatchKindPair {
+		res.ParticipantAccountIDs = []int64{match.AccountIDLow, match.AccountIDHigh}
+		return res, nil
+	}
+
+	res.ParticipantAccountIDs, err = m.MatchesRepository.FindMatchParticipantsFromDB(ctx, tx, matchId)
+	if err != nil {
+		return domain.MatchDto{}, errors.New("failed to find match participants")
+	}
+	return res, nil
+}
+
+func (m MatchEntityImpl) FindMatchesEntity(ctx context.Context, tx *sql.Tx, accountId int64) ([]domain.MatchViewDto, error) {
+	matches, err := m.MatchesRepository.FindMatchesByAccountFromDB(ctx, tx, accountId)
+	if err != nil {
+		return nil, errors.New("failed to find matches")
+	}
+
+	var res []domain.MatchViewDto
+	for _, match := range matches {
+		view := domain.MatchViewDto{
+			MatchID:          match.MatchID,
+			MatchedAccountID: match.MatchedAccountID,
+			CreatedAt:        match.CreatedAt,
+		}
+		if match.FullName != nil {
+			view.FullName = *match.FullName
+		}
+		if match.Age != nil {
+			view.Age = *match.Age
+		}
+		if match.Gender != nil {
+			view.Gender = *match.Gender
+		}
+		if match.Bio != nil {
+			view.Bio = *match.Bio
+		}
+		res = append(res, view)
+	}
+	return res, nil
+}
+
+// CreateGroupMatchEntity starts a group conversation of the accounts, the caller decides who may be in it.
+// A group with expiresAt is ephemeral, nobody can read or write in it from then on
+func (m MatchEntityImpl) CreateGroupMatchEntity(ctx context.Context, tx *sql.Tx, title string, participants []int64, expiresAt *time.Time) (domain.MatchDto, error) {
+	title = strings.TrimSpace(title)
+	if title == "" || len([]rune(title)) > maxGroupTitle {
+		return domain.MatchDto{}, fmt.Errorf("%w: title is required and at most %d characters", ErrInvalidGroup, maxGroupTitle)
+	}
+
+	seen := map[int64]bool{}
+	var accounts []int64
+	for _, accountId := range participants {
+		if accountId > 0 && !seen[accountId] {
+			seen[accountId] = true
+			accounts = append(accounts, accountId)
+		}
+	}
+	if len(accounts) > MaxGroupParticipants {
+		return domain.MatchDto{}, ErrGroupFull
+	}
+
+	match, err := m.MatchesRepository.InsertGroupMatchToDB(ctx, tx, title, accounts, expiresAt)
+	if err != nil {
+		return domain.MatchDto{}, errors.New("failed to create group chat")
+	}
+
+	return domain.MatchDto{
+		MatchID:               match.MatchID,
+		Kind:                  MatchKindGroup,
+		Title:                 title,
+		ExpiresAt:             expiresAt,
+		ParticipantAccountIDs: accounts,
+		CreatedAt:             match.CreatedAt,
+	}, nil
+}
+
+// AddParticipantEntity only adds to groups, the accounts of a pair or a duo match are fixed by the likes
+func (m MatchEntityImpl) AddParticipantEntity(ctx context.Context, tx *sql.Tx, match domain.MatchDto, accountId int64) error {
+	if match.Kind != MatchKindGroup {
+		return fmt.Errorf("%w: participants can only be added to a group chat", ErrInvalidGroup)
+	}
+	for _, participant := range match.ParticipantAccountIDs {
+		if participant == accountId {
+			return nil
+		}
+	}
+	if len(match.ParticipantAccountIDs) >= MaxGroupParticipants {
+		return ErrGroupFull
+	}
+
+	if err := m.MatchesRepository.InsertMatchParticipantToDB(ctx, tx, match.MatchID, accountId); err != nil {
+		return errors.New("failed to add participant")
+	}
+	return nil
+}
+
+// LeaveMatchEntity takes the account out of a duo match or a group, it keeps the messages it sent
+func (m MatchEntityImpl) LeaveMatchEntity(ctx context.Context, tx *sql.Tx, match domain.MatchDto) error {
+	if match.Kind == MatchKindPair {
+		return ErrLeavePairMatch
+	}
+
+	left, err := m.MatchesRepository.UpdateMatchParticipantLeftToDB(ctx, tx, match.MatchID, match.AccountID)
+	if err != nil {
+		return errors.New("failed to leave chat")
+	}
+	if left == 0 {
+		return ErrNotMatched
+	}
+	return nil
+}
+
+// UnmatchEntity ends a pair match for both accounts, the conversation goes with it
+func (m MatchEntityImpl) UnmatchEntity(ctx context.Context, tx *sql.Tx, match domain.MatchDto) error {
+	if match.Kind != MatchKindPair {
+		return ErrUnmatchGroup
+	}
+
+	if err := m.MatchesRepository.DeleteMatchFromDB(ctx, tx, match.MatchID); err != nil {
+		return errors.New("failed to unmatch")
+	}
+	return nil
+}
+
+func (m MatchEntityImpl) MarkReadEntity(ctx context.Context, tx *sql.Tx, matchId int64, accountId int64, messageId int64) error {
+	if err := m.MatchesRepository.UpdateMatchParticipantReadToDB(ctx, tx, matchId, accountId, messageId); err != nil {
+		return errors.New("failed to mark messages read")
+	}
+	return nil
+}
+
+func (m MatchEntityImpl) FindParticipantsEntity(ctx context.Context, tx *sql.Tx, matchId int64) ([]domain.ChatParticipantDto, error) {
+	participants, err := m.MatchesRepository.FindMatchParticipantDetailsFromDB(ctx, tx, matchId)
+	if err != nil {
+		return nil, errors.New("failed to find match participants")
+	}
+
+	res := make([]domain.ChatParticipantDto, 0, len(participants))
+	for _, participant := range participants {
+		dto := domain.ChatParticipantDto{
+			AccountID:         participant.AccountID,
+			Username:          participant.Username,
+			LastReadMessageID: participant.LastReadMessageID,
+			JoinedAt:          participant.JoinedAt,
+		}
+		if participant.FullName != nil {
+			dto.FullName = *participant.FullName
+		}
+		res = append(res, dto)
+	}
+	return res, nil
+}
+
+// DeleteMatchEntity removes the conversation for good, only for ephemeral groups that ended
+func (m MatchEntityImpl) DeleteMatchEntity(ctx context.Context, tx *sql.Tx, matchId int64) error {
+	if err := m.MatchesRepository.DeleteMatchFromDB(ctx, tx, matchId); err != nil {
+		return errors.New("failed to delete match")
+	}
+	return nil
+}
+
+func matchKind(match record.MatchRecord) string {
+	switch {
+	case match.DuoIDLow != nil:
+		return MatchKindDuo
+	case match.AccountIDLow != 0:
+		return MatchKindPair
+	default:
+		return MatchKindGroup
+	}
+}
@@ -0,0 +1,13 @@
+package messages
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/domain"
+)
+
+type MessageEntity interface {
+	SendMessageEntity(ctx context.Context, tx *sql.Tx, dto domain.ChatMessageDto) (domain.ChatMessageDto, error)
+	FindMessagesEntity(ctx context.Context, tx *sql.Tx, matchId int64, beforeId int64, size int) ([]domain.ChatMessageDto, error)
+	CountUnreadMessagesEntity(ctx context.Context, tx *sql.Tx, matchId int64, accountId int64) (int64, error)
+}
@@ -0,0 +1,78 @@
+package messages
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/record"
+	"godating-dealls/internal/infra/mysql/repo"
+	"strings"
+)
+
+var ErrInvalidMessage = errors.New("invalid message")
+
+type MessageEntityImpl struct {
+	MessagesRepository repo.MessagesRepository
+	validate           *validator.Validate
+}
+
+func NewMessageEntityImpl(messagesRepository repo.MessagesRepository, validate *validator.Validate) MessageEntity {
+	return &MessageEntityImpl{MessagesRepository: messagesRepository, validate: validate}
+}
+
+// SendMessageEntity stores the message, the caller checks that the sender is part of the match
+func (m MessageEntityImpl) SendMessageEntity(ctx context.Context, tx *sql.Tx, dto domain.ChatMessageDto) (domain.ChatMessageDto, error) {
+	dto.Body = strings.TrimSpace(dto.Body)
+	if err := m.validate.Struct(dto); err != nil {
+		return domain.ChatMessageDto{}, fmt.Errorf("%w: body is required and at most 2000 characters", ErrInvalidMessage)
+	}
+
+	message, err := m.MessagesRepository.InsertMessageToDB(ctx, tx, record.MessageRecord{
+		MatchID:         dto.MatchID,
+		SenderAccountID: dto.SenderAccountID,
+		Body:            dto.Body,
+	})
+	if err != nil {
+		return domain.ChatMessageDto{}, errors.New("failed to send message")
+	}
+	return toChatMessageDto(message), nil
+}
+
+func (m MessageEntityImpl) FindMessagesEntity(ctx context.Context, tx *sql.Tx, matchId int64, beforeId int64, size int) ([]domain.ChatMessageDto, error) {
+	messages, err := m.MessagesRepository.FindMessagesByMatchFromDB(ctx, tx, matchId, beforeId, size)
+	if err != nil {
+		return nil, errors.New("failed to find messages")
+	}
+
+	var res []domain.ChatMessageDto
+	for _, message := range messages {
+		res = append(res, toChatMessageDto(message))
+	}
+	return res, nil
+}
+
+func (m MessageEntityImpl) CountUnreadMessagesEntity(ctx context.Context, tx *sql.Tx, matchId int64, accountId int64) (int64, error) {
+	unread, err := m.MessagesRepository.CountUnreadMessagesFromDB(ctx, tx, matchId, accountId)
+	if err != nil {
+		return 0, errors.New("failed to count unread messages")
+	}
+	return unread, nil
+}
+
+func toChatMessageDto(message record.MessageRecord) domain.ChatMessageDto {
+	res := domain.ChatMessageDto{
+		MessageID:       message.MessageID,
+		MatchID:         message.MatchID,
+		SenderAccountID: message.SenderAccountID,
+		SenderUsername:  message.SenderUsername,
+		Body:            message.Body,
+		CreatedAt:       message.CreatedAt,
+	}
+	if message.SenderFullName != nil {
+		res.SenderFullName = *message.SenderFullName
+	}
+	return res
+}
@@ -0,0 +1,13 @@
+package network_rules
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/domain"
+)
+
+type NetworkRuleEntity interface {
+	SaveNetworkRuleEntity(ctx context.Context, tx *sql.Tx, dto domain.NetworkRuleDto) (domain.NetworkRuleDto, error)
+	DeleteNetworkRuleEntity(ctx context.Context, tx *sql.Tx, ruleId int64) error
+	FindNetworkRulesEntity(ctx context.Context, tx *sql.Tx) ([]domain.NetworkRuleDto, error)
+}
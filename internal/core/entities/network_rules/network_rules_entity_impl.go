@@ -0,0 +1,114 @@
+package network_rules
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/record"
+	"godating-dealls/internal/infra/mysql/repo"
+	"net/netip"
+	"strings"
+)
+
+var (
+	ErrInvalidNetworkRule  = errors.New("invalid network rule")
+	ErrNetworkRuleNotFound = errors.New("network rule not found")
+	ErrNetworkRuleExists   = errors.New("network rule already exists")
+	ErrNetworkRuleLockout  = errors.New("network rule would lock out the admin api")
+)
+
+type NetworkRuleEntityImpl struct {
+	NetworkRulesRepository repo.NetworkRulesRepository
+	validate               *validator.Validate
+}
+
+func NewNetworkRuleEntityImpl(networkRulesRepository repo.NetworkRulesRepository, validate *validator.Validate) NetworkRuleEntity {
+	return &NetworkRuleEntityImpl{NetworkRulesRepository: networkRulesRepository, validate: validate}
+}
+
+// SaveNetworkRuleEntity stores the range in its canonical form, 10.1.2.3/8 becomes 10.0.0.0/8
+func (n NetworkRuleEntityImpl) SaveNetworkRuleEntity(ctx context.Context, tx *sql.Tx, dto domain.NetworkRuleDto) (domain.NetworkRuleDto, error) {
+	if err := n.validate.Struct(dto); err != nil {
+		return domain.NetworkRuleDto{}, fmt.Errorf("%w: %v", ErrInvalidNetworkRule, err)
+	}
+	if dto.RouteGroup == common.NetworkGroupGlobal && dto.List == common.NetworkListAllow {
+		return domain.NetworkRuleDto{}, fmt.Errorf("%w: the global group only has a deny list", ErrInvalidNetworkRule)
+	}
+	prefix, err := ParseNetworkPrefix(dto.CIDR)
+	if err != nil {
+		return domain.NetworkRuleDto{}, fmt.Errorf("%w: cidr must look like 203.0.113.0/24, 2001:db8::/32 or a single address", ErrInvalidNetworkRule)
+	}
+
+	rule, err := n.NetworkRulesRepository.InsertNetworkRuleToDB(ctx, tx, record.NetworkRuleRecord{
+		List:       dto.List,
+		RouteGroup: dto.RouteGroup,
+		CIDR:       prefix.String(),
+		Note:       strings.TrimSpace(dto.Note),
+	})
+	if err != nil {
+		var duplicate *repo.DuplicateKeyError
+		if errors.As(err, &duplicate) {
+			return domain.NetworkRuleDto{}, ErrNetworkRuleExists
+		}
+		return domain.NetworkRuleDto{}, errors.New("failed to save network rule")
+	}
+	return toNetworkRuleDto(rule), nil
+}
+
+func (n NetworkRuleEntityImpl) DeleteNetworkRuleEntity(ctx context.Context, tx *sql.Tx, ruleId int64) error {
+	deleted, err := n.NetworkRulesRepository.DeleteNetworkRuleToDB(ctx, tx, ruleId)
+	if err != nil {
+		return errors.New("failed to delete network rule")
+	}
+	if !deleted {
+		return ErrNetworkRuleNotFound
+	}
+	return nil
+}
+
+func (n NetworkRuleEntityImpl) FindNetworkRulesEntity(ctx context.Context, tx *sql.Tx) ([]domain.NetworkRuleDto, error) {
+	rules, err := n.NetworkRulesRepository.FindNetworkRulesFromDB(ctx, tx)
+	if err != nil {
+		return nil, errors.New("failed to find network rules")
+	}
+
+	var res []domain.NetworkRuleDto
+	for _, rule := range rules {
+		res = append(res, toNetworkRuleDto(rule))
+	}
+	return res, nil
+}
+
+// ParseNetworkPrefix accepts a CIDR range or a single address and returns the masked range
+func ParseNetworkPrefix(cidr string) (netip.Prefix, error) {
+	cidr = strings.TrimSpace(cidr)
+	if !strings.Contains(cidr, "/") {
+		address, err := netip.ParseAddr(cidr)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		address = address.Unmap()
+		return netip.PrefixFrom(address, address.BitLen()), nil
+	}
+
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return prefix.Masked(), nil
+}
+
+func toNetworkRuleDto(rule record.NetworkRuleRecord) domain.NetworkRuleDto {
+	return domain.NetworkRuleDto{
+		RuleID:     rule.RuleID,
+		List:       rule.List,
+		RouteGroup: rule.RouteGroup,
+		CIDR:       rule.CIDR,
+		Note:       rule.Note,
+		CreatedAt:  rule.CreatedAt,
+	}
+}
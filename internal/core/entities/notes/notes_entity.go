@@ -0,0 +1,14 @@
+package notes
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/domain"
+)
+
+type NoteEntity interface {
+	SaveNoteEntity(ctx context.Context, tx *sql.Tx, dto domain.NoteDto) (domain.NoteDto, error)
+	FindNotesEntity(ctx context.Context, tx *sql.Tx, ownerAccountId int64, targetAccountId int64) ([]domain.NoteDto, error)
+	UpdateNoteEntity(ctx context.Context, tx *sql.Tx, dto domain.NoteDto) (domain.NoteDto, error)
+	DeleteNoteEntity(ctx context.Context, tx *sql.Tx, ownerAccountId int64, noteId int64) error
+}
@@ -0,0 +1,105 @@
+package notes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/record"
+	"godating-dealls/internal/infra/mysql/repo"
+	"time"
+)
+
+type NoteEntityImpl struct {
+	NotesRepository repo.NotesRepository
+	Validate        *validator.Validate
+}
+
+func NewNoteEntityImpl(notesRepository repo.NotesRepository, validate *validator.Validate) NoteEntity {
+	return &NoteEntityImpl{NotesRepository: notesRepository, Validate: validate}
+}
+
+// SaveNoteEntity notes are private to the owner, a note about yourself is meaningless
+func (n NoteEntityImpl) SaveNoteEntity(ctx context.Context, tx *sql.Tx, dto domain.NoteDto) (domain.NoteDto, error) {
+	err := n.Validate.Struct(dto)
+	if err != nil {
+		return domain.NoteDto{}, err
+	}
+
+	if dto.OwnerAccountID == dto.TargetAccountID {
+		return domain.NoteDto{}, errors.New("cannot write a note about your own profile")
+	}
+
+	rec, err := n.NotesRepository.InsertNoteToDB(ctx, tx, record.NoteRecord{
+		OwnerAccountID:  dto.OwnerAccountID,
+		TargetAccountID: dto.TargetAccountID,
+		Content:         dto.Content,
+	})
+	if err != nil {
+		return domain.NoteDto{}, errors.New("failed to insert note")
+	}
+
+	dto.NoteID = rec.NoteID
+	dto.CreatedAt = time.Now()
+	dto.UpdatedAt = dto.CreatedAt
+	return dto, nil
+}
+
+func (n NoteEntityImpl) FindNotesEntity(ctx context.Context, tx *sql.Tx, ownerAccountId int64, targetAccountId int64) ([]domain.NoteDto, error) {
+	records, err := n.NotesRepository.FindNotesByOwnerFromDB(ctx, tx, ownerAccountId, targetAccountId)
+	if err != nil {
+		return nil, errors.New("failed to find notes")
+	}
+
+	var notes []domain.NoteDto
+	for _, rec := range records {
+		notes = append(notes, domain.NoteDto{
+			NoteID:          rec.NoteID,
+			OwnerAccountID:  rec.OwnerAccountID,
+			TargetAccountID: rec.TargetAccountID,
+			Content:         rec.Content,
+			CreatedAt:       rec.CreatedAt,
+			UpdatedAt:       rec.UpdatedAt,
+		})
+	}
+	return notes, nil
+}
+
+// UpdateNoteEntity only changes the content, the note is returned as stored so the target is the one it was written about
+func (n NoteEntityImpl) UpdateNoteEntity(ctx context.Context, tx *sql.Tx, dto domain.NoteDto) (domain.NoteDto, error) {
+	if dto.NoteID == 0 {
+		return domain.NoteDto{}, errors.New("note id is required")
+	}
+
+	err := n.Validate.StructPartial(dto, "OwnerAccountID", "Content")
+	if err != nil {
+		return domain.NoteDto{}, err
+	}
+
+	err = n.NotesRepository.UpdateNoteToDB(ctx, tx, record.NoteRecord{
+		NoteID:         dto.NoteID,
+		OwnerAccountID: dto.OwnerAccountID,
+		Content:        dto.Content,
+	})
+	if err != nil {
+		return domain.NoteDto{}, err
+	}
+
+	rec, err := n.NotesRepository.FindNoteByIdFromDB(ctx, tx, dto.OwnerAccountID, dto.NoteID)
+	if err != nil {
+		return domain.NoteDto{}, errors.New("failed to find note")
+	}
+	return domain.NoteDto{
+		NoteID:          rec.NoteID,
+		OwnerAccountID:  rec.OwnerAccountID,
+		TargetAccountID: rec.TargetAccountID,
+		Content:         rec.Content,
+		CreatedAt:       rec.CreatedAt,
+		UpdatedAt:       rec.UpdatedAt,
+	}, nil
+}
+
+func (n NoteEntityImpl) DeleteNoteEntity(ctx context.Context, tx *sql.Tx, ownerAccountId int64, noteId int64) error {
+	return n.NotesRepository.DeleteNoteFromDB(ctx, tx, ownerAccountId, noteId)
+}
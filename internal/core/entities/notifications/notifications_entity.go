@@ -0,0 +1,23 @@
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/domain"
+	"time"
+)
+
+type NotificationEntity interface {
+	RegisterDeviceEntity(ctx context.Context, tx *sql.Tx, accountId int64, provider string, token string) (domain.DeviceDto, error)
+	FindDevicesEntity(ctx context.Context, tx *sql.Tx, accountIds []int64) (map[int64][]domain.DeviceDto, error)
+	RemoveDevicesEntity(ctx context.Context, tx *sql.Tx, tokens []string) error
+	NotifyEntity(ctx context.Context, tx *sql.Tx, accountIds []int64, notification domain.NotificationDto) error
+	ClaimDuePushesEntity(ctx context.Context, tx *sql.Tx, now time.Time, limit int, lease time.Duration) ([]domain.PushDto, error)
+	CompletePushEntity(ctx context.Context, tx *sql.Tx, pushId int64, status string, lastError string) error
+	RetryPushEntity(ctx context.Context, tx *sql.Tx, push domain.PushDto, now time.Time, lastError string) error
+	PurgeDeliveredPushesEntity(ctx context.Context, tx *sql.Tx, before time.Time) (int64, error)
+	FindFeedEntity(ctx context.Context, tx *sql.Tx, accountId int64, unreadOnly bool, page int, size int) ([]domain.FeedNotificationDto, error)
+	CountFeedEntity(ctx context.Context, tx *sql.Tx, accountId int64) (total int64, unread int64, err error)
+	MarkFeedReadEntity(ctx context.Context, tx *sql.Tx, accountId int64, notificationIds []int64) (int64, error)
+	PurgeFeedEntity(ctx context.Context, tx *sql.Tx, before time.Time) (int64, error)
+}
@@ -0,0 +1,309 @@
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/record"
+	"godating-dealls/internal/infra/mysql/repo"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Kinds of notification, the app opens the screen of the kind
+const (
+	KindMatch     = "match"
+	KindMessage   = "message"
+	KindSuperLike = "super_like"
+)
+
+// Delivery states of a push, skipped when the account has no device
+const (
+	PushPending = "pending"
+	PushSent    = "sent"
+	PushSkipped = "skipped"
+	PushFailed  = "failed"
+)
+
+// feedKinds are the kinds also written to the in-app feed, a message is already unread in its chat
+var feedKinds = map[string]bool{KindMatch: true, KindSuperLike: true}
+
+// MaxMarkReadIds is how many notifications one request can mark read, no id marks all of them
+const MaxMarkReadIds = 100
+
+// DeviceProviders are the push services a device token can be registered with
+var DeviceProviders = []string{"fcm", "apns"}
+
+// MaxPushAttempts a push still failing after this many attempts is given up
+const MaxPushAttempts = 5
+
+// MaxDeviceTokenLength is the size of the token column, FCM and APNs tokens are well below it
+const MaxDeviceTokenLength = 512
+
+// deviceToken covers the FCM registration tokens and the hex APNs tokens, the token ends up in the APNs url
+var deviceToken = regexp.MustCompile(`^[A-Za-z0-9_:.\-]+$`)
+
+var (
+	ErrInvalidDevice          = errors.New("invalid device")
+	ErrInvalidNotificationIds = errors.New("invalid notification ids")
+)
+
+type NotificationEntityImpl struct {
+	PushNotificationsRepository repo.PushNotificationsRepository
+	NotificationsRepository     repo.NotificationsRepository
+}
+
+func NewNotificationEntityImpl(pushNotificationsRepository repo.PushNotificationsRepository, notificationsRepository repo.NotificationsRepository) NotificationEntity {
+	return &NotificationEntityImpl{
+		PushNotificationsRepository: pushNotificationsRepository,
+		NotificationsRepository:     notificationsRepository,
+	}
+}
+
+func (n NotificationEntityImpl) RegisterDeviceEntity(ctx context.Context, tx *sql.Tx, accountId int64, provider string, token string) (domain.DeviceDto, error) {
+	provider = strings.ToLower(strings.TrimSpace(provider))
+	token = strings.TrimSpace(token)
+	known := false
+	for _, candidate := range DeviceProviders {
+		known = known || candidate == provider
+	}
+	if !known {
+		return domain.DeviceDto{}, fmt.Errorf("%w: provider must be one of %s", ErrInvalidDevice, strings.Join(DeviceProviders, ", "))
+	}
+	if token == "" || len(token) > MaxDeviceTokenLength || !deviceToken.MatchString(token) {
+		return domain.DeviceDto{}, fmt.Errorf("%w: token must be a device token of at most %d characters", ErrInvalidDevice, MaxDeviceTokenLength)
+	}
+
+	rec, err := n.PushNotificationsRepository.UpsertDeviceTokenToDB(ctx, tx, record.DeviceTokenRecord{AccountID: accountId, Provider: provider, Token: token})
+	if err != nil {
+		return domain.DeviceDto{}, errors.New("failed to register device")
+	}
+	return toDeviceDto(rec), nil
+}
+
+func (n NotificationEntityImpl) FindDevicesEntity(ctx context.Context, tx *sql.Tx, accountIds []int64) (map[int64][]domain.DeviceDto, error) {
+	records, err := n.PushNotificationsRepository.FindDeviceTokensByAccountIdsFromDB(ctx, tx, accountIds)
+	if err != nil {
+		return nil, errors.New("failed to find devices")
+	}
+
+	res := make(map[int64][]domain.DeviceDto, len(accountIds))
+	for _, rec := range records {
+		res[rec.AccountID] = append(res[rec.AccountID], toDeviceDto(rec))
+	}
+	return res, nil
+}
+
+// RemoveDevicesEntity forgets the tokens the providers reported as no longer valid
+func (n NotificationEntityImpl) RemoveDevicesEntity(ctx context.Context, tx *sql.Tx, tokens []string) error {
+	if _, err := n.PushNotificationsRepository.DeleteDeviceTokensFromDB(ctx, tx, tokens); err != nil {
+		return errors.New("failed to remove devices")
+	}
+	return nil
+}
+
+// NotifyEntity queues a push for each account in the transaction of the change, so a rolled back change is never told
+// and a committed one is delivered by the push delivery job even when the instance stops right after. The kinds of the
+// feed are written to it in the same transaction
+func (n NotificationEntityImpl) NotifyEntity(ctx context.Context, tx *sql.Tx, accountIds []int64, notification domain.NotificationDto) error {
+	var data sql.NullString
+	if len(notification.Data) > 0 {
+		encoded, err := json.Marshal(notification.Data)
+		if err != nil {
+			return errors.New("failed to encode notification")
+		}
+		data = sql.NullString{String: string(encoded), Valid: true}
+	}
+
+	now := time.Now()
+	pushes := make([]record.PushNotificationRecord, 0, len(accountIds))
+	for _, accountId := range accountIds {
+		pushes = append(pushes, record.PushNotificationRecord{
+			AccountID:     accountId,
+			Kind:          notification.Kind,
+			Title:         notification.Title,
+			Body:          notification.Body,
+			Data:          data,
+			NextAttemptAt: now,
+		})
+	}
+	if err := n.PushNotificationsRepository.InsertPushNotificationsToDB(ctx, tx, pushes); err != nil {
+		return errors.New("failed to queue notification")
+	}
+
+	if !feedKinds[notification.Kind] {
+		return nil
+	}
+	feed := make([]record.NotificationRecord, 0, len(accountIds))
+	for _, accountId := range accountIds {
+		feed = append(feed, record.NotificationRecord{
+			AccountID: accountId,
+			Kind:      notification.Kind,
+			Title:     notification.Title,
+			Body:      notification.Body,
+			Data:      data,
+		})
+	}
+	if err := n.NotificationsRepository.InsertNotificationsToDB(ctx, tx, feed); err != nil {
+		return errors.New("failed to add notification to feed")
+	}
+	return nil
+}
+
+// ClaimDuePushesEntity takes the due pushes for the lease, each claim is counted as an attempt
+func (n NotificationEntityImpl) ClaimDuePushesEntity(ctx context.Context, tx *sql.Tx, now time.Time, limit int, lease time.Duration) ([]domain.PushDto, error) {
+	records, err := n.PushNotificationsRepository.FindDuePushNotificationsFromDB(ctx, tx, now, limit)
+	if err != nil {
+		return nil, errors.New("failed to find due pushes")
+	}
+
+	pushIds := make([]int64, 0, len(records))
+	res := make([]domain.PushDto, 0, len(records))
+	for _, rec := range records {
+		pushIds = append(pushIds, rec.PushID)
+		res = append(res, toPushDto(rec))
+	}
+	if err := n.PushNotificationsRepository.LeasePushNotificationsToDB(ctx, tx, pushIds, now.Add(lease)); err != nil {
+		return nil, errors.New("failed to claim due pushes")
+	}
+	return res, nil
+}
+
+func (n NotificationEntityImpl) CompletePushEntity(ctx context.Context, tx *sql.Tx, pushId int64, status string, lastError string) error {
+	if err := n.PushNotificationsRepository.UpdatePushNotificationStatusToDB(ctx, tx, pushId, status, truncate(lastError)); err != nil {
+		return errors.New("failed to update push")
+	}
+	return nil
+}
+
+// RetryPushEntity waits longer after each failed attempt, 1, 4, 9 and 16 minutes, then the push is given up
+func (n NotificationEntityImpl) RetryPushEntity(ctx context.Context, tx *sql.Tx, pushDto domain.PushDto, now time.Time, lastError string) error {
+	if pushDto.Attempts >= MaxPushAttempts {
+		return n.CompletePushEntity(ctx, tx, pushDto.PushID, PushFailed, lastError)
+	}
+
+	backoff := time.Duration(pushDto.Attempts*pushDto.Attempts) * time.Minute
+	if err := n.PushNotificationsRepository.RetryPushNotificationToDB(ctx, tx, pushDto.PushID, now.Add(backoff), truncate(lastError)); err != nil {
+		return errors.New("failed to update push")
+	}
+	return nil
+}
+
+func (n NotificationEntityImpl) PurgeDeliveredPushesEntity(ctx context.Context, tx *sql.Tx, before time.Time) (int64, error) {
+	purged, err := n.PushNotificationsRepository.DeletePushNotificationsBeforeFromDB(ctx, tx, before)
+	if err != nil {
+		return 0, errors.New("failed to purge pushes")
+	}
+	return purged, nil
+}
+
+func (n NotificationEntityImpl) FindFeedEntity(ctx context.Context, tx *sql.Tx, accountId int64, unreadOnly bool, page int, size int) ([]domain.FeedNotificationDto, error) {
+	records, err := n.NotificationsRepository.FindNotificationsByAccountIdFromDB(ctx, tx, accountId, unreadOnly, size, (page-1)*size)
+	if err != nil {
+		return nil, errors.New("failed to find notifications")
+	}
+
+	res := make([]domain.FeedNotificationDto, 0, len(records))
+	for _, rec := range records {
+		res = append(res, toFeedNotificationDto(rec))
+	}
+	return res, nil
+}
+
+func (n NotificationEntityImpl) CountFeedEntity(ctx context.Context, tx *sql.Tx, accountId int64) (int64, int64, error) {
+	total, unread, err := n.NotificationsRepository.CountNotificationsByAccountIdFromDB(ctx, tx, accountId)
+	if err != nil {
+		return 0, 0, errors.New("failed to count notifications")
+	}
+	return total, unread, nil
+}
+
+// MarkFeedReadEntity returns how many notifications were unread before, marking a read one again keeps its read time
+func (n NotificationEntityImpl) MarkFeedReadEntity(ctx context.Context, tx *sql.Tx, accountId int64, notificationIds []int64) (int64, error) {
+	if len(notificationIds) > MaxMarkReadIds {
+		return 0, fmt.Errorf("%w: at most %d ids at once, none marks every notification read", ErrInvalidNotificationIds, MaxMarkReadIds)
+	}
+	for _, notificationId := range notificationIds {
+		if notificationId <= 0 {
+			return 0, fmt.Errorf("%w: %d is not a notification id", ErrInvalidNotificationIds, notificationId)
+		}
+	}
+
+	marked, err := n.NotificationsRepository.MarkNotificationsReadToDB(ctx, tx, accountId, notificationIds, time.Now())
+	if err != nil {
+		return 0, errors.New("failed to mark notifications read")
+	}
+	return marked, nil
+}
+
+func (n NotificationEntityImpl) PurgeFeedEntity(ctx context.Context, tx *sql.Tx, before time.Time) (int64, error) {
+	purged, err := n.NotificationsRepository.DeleteNotificationsBeforeFromDB(ctx, tx, before)
+	if err != nil {
+		return 0, errors.New("failed to purge notifications")
+	}
+	return purged, nil
+}
+
+func toDeviceDto(rec record.DeviceTokenRecord) domain.DeviceDto {
+	return domain.DeviceDto{
+		DeviceID:  rec.DeviceID,
+		AccountID: rec.AccountID,
+		Provider:  rec.Provider,
+		Token:     rec.Token,
+		CreatedAt: rec.CreatedAt,
+		UpdatedAt: rec.UpdatedAt,
+	}
+}
+
+// toPushDto the attempt of the claim is counted, the lease already added it to the row
+func toPushDto(rec record.PushNotificationRecord) domain.PushDto {
+	var data map[string]string
+	if rec.Data.Valid {
+		_ = json.Unmarshal([]byte(rec.Data.String), &data)
+	}
+	return domain.PushDto{
+		PushID:    rec.PushID,
+		AccountID: rec.AccountID,
+		Notification: domain.NotificationDto{
+			Kind:  rec.Kind,
+			Title: rec.Title,
+			Body:  rec.Body,
+			Data:  data,
+		},
+		Attempts: rec.Attempts + 1,
+	}
+}
+
+func toFeedNotificationDto(rec record.NotificationRecord) domain.FeedNotificationDto {
+	var data map[string]string
+	if rec.Data.Valid {
+		_ = json.Unmarshal([]byte(rec.Data.String), &data)
+	}
+	res := domain.FeedNotificationDto{
+		NotificationID: rec.NotificationID,
+		AccountID:      rec.AccountID,
+		Notification: domain.NotificationDto{
+			Kind:  rec.Kind,
+			Title: rec.Title,
+			Body:  rec.Body,
+			Data:  data,
+		},
+		CreatedAt: rec.CreatedAt,
+	}
+	if rec.ReadAt.Valid {
+		res.ReadAt = &rec.ReadAt.Time
+	}
+	return res
+}
+
+// truncate keeps the error within the last_error column
+func truncate(lastError string) string {
+	if len(lastError) > 512 {
+		return lastError[:512]
+	}
+	return lastError
+}
@@ -0,0 +1,16 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/domain"
+	"time"
+)
+
+type OutboxEntity interface {
+	RecordEventEntity(ctx context.Context, tx *sql.Tx, kind string, aggregateId int64, payload interface{}) error
+	ClaimDueEventsEntity(ctx context.Context, tx *sql.Tx, now time.Time, limit int, lease time.Duration) ([]domain.OutboxEventDto, error)
+	CompleteEventEntity(ctx context.Context, tx *sql.Tx, eventId int64) error
+	RetryEventEntity(ctx context.Context, tx *sql.Tx, event domain.OutboxEventDto, now time.Time, lastError string) error
+	PurgePublishedEventsEntity(ctx context.Context, tx *sql.Tx, before time.Time) (int64, error)
+}
@@ -0,0 +1,103 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/record"
+	"godating-dealls/internal/infra/mysql/repo"
+	"time"
+)
+
+// Kinds of domain event, the consumers on the bus subscribe by kind
+const (
+	EventAccountRegistered = "account.registered"
+	EventSwipeRecorded     = "swipe.recorded"
+	EventMatchCreated      = "match.created"
+)
+
+// maxRetryBackoff an event is never given up, a bus that is down for long is tried again at this pace
+const maxRetryBackoff = 10 * time.Minute
+
+type OutboxEntityImpl struct {
+	OutboxEventsRepository repo.OutboxEventsRepository
+}
+
+func NewOutboxEntityImpl(outboxEventsRepository repo.OutboxEventsRepository) OutboxEntity {
+	return &OutboxEntityImpl{OutboxEventsRepository: outboxEventsRepository}
+}
+
+// RecordEventEntity writes the event in the transaction of the change, it is published only once that commits
+func (o OutboxEntityImpl) RecordEventEntity(ctx context.Context, tx *sql.Tx, kind string, aggregateId int64, payload interface{}) error {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return errors.New("failed to encode outbox event")
+	}
+	err = o.OutboxEventsRepository.InsertOutboxEventToDB(ctx, tx, record.OutboxEventRecord{
+		Kind:        kind,
+		AggregateID: aggregateId,
+		Payload:     string(encoded),
+	})
+	if err != nil {
+		return errors.New("failed to record outbox event")
+	}
+	return nil
+}
+
+// ClaimDueEventsEntity takes the due events for the lease, each claim is counted as an attempt
+func (o OutboxEntityImpl) ClaimDueEventsEntity(ctx context.Context, tx *sql.Tx, now time.Time, limit int, lease time.Duration) ([]domain.OutboxEventDto, error) {
+	records, err := o.OutboxEventsRepository.FindDueOutboxEventsFromDB(ctx, tx, now, limit)
+	if err != nil {
+		return nil, errors.New("failed to find due outbox events")
+	}
+
+	eventIds := make([]int64, 0, len(records))
+	res := make([]domain.OutboxEventDto, 0, len(records))
+	for _, rec := range records {
+		eventIds = append(eventIds, rec.EventID)
+		res = append(res, domain.OutboxEventDto{
+			EventID:     rec.EventID,
+			Kind:        rec.Kind,
+			AggregateID: rec.AggregateID,
+			Payload:     rec.Payload,
+			Attempts:    rec.Attempts + 1,
+			CreatedAt:   rec.CreatedAt,
+		})
+	}
+	if err := o.OutboxEventsRepository.LeaseOutboxEventsToDB(ctx, tx, eventIds, now.Add(lease)); err != nil {
+		return nil, errors.New("failed to claim due outbox events")
+	}
+	return res, nil
+}
+
+func (o OutboxEntityImpl) CompleteEventEntity(ctx context.Context, tx *sql.Tx, eventId int64) error {
+	if err := o.OutboxEventsRepository.MarkOutboxEventPublishedToDB(ctx, tx, eventId); err != nil {
+		return errors.New("failed to update outbox event")
+	}
+	return nil
+}
+
+// RetryEventEntity waits longer after each failed attempt, 1, 4, 9 minutes and so on up to maxRetryBackoff
+func (o OutboxEntityImpl) RetryEventEntity(ctx context.Context, tx *sql.Tx, event domain.OutboxEventDto, now time.Time, lastError string) error {
+	backoff := time.Duration(event.Attempts*event.Attempts) * time.Minute
+	if backoff > maxRetryBackoff {
+		backoff = maxRetryBackoff
+	}
+	if len(lastError) > 512 {
+		lastError = lastError[:512]
+	}
+	if err := o.OutboxEventsRepository.RetryOutboxEventToDB(ctx, tx, event.EventID, now.Add(backoff), lastError); err != nil {
+		return errors.New("failed to update outbox event")
+	}
+	return nil
+}
+
+func (o OutboxEntityImpl) PurgePublishedEventsEntity(ctx context.Context, tx *sql.Tx, before time.Time) (int64, error) {
+	purged, err := o.OutboxEventsRepository.DeletePublishedOutboxEventsBeforeFromDB(ctx, tx, before)
+	if err != nil {
+		return 0, errors.New("failed to purge outbox events")
+	}
+	return purged, nil
+}
@@ -4,10 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"godating-dealls/internal/domain"
+	"time"
 )
 
 type PackageEntity interface {
 	GetAllPackagesEntity(ctx context.Context, tx *sql.Tx) ([]domain.PackageDto, error)
-	PurchasePackage(ctx context.Context, tx *sql.Tx, dto domain.PackageDto) error
-	FindAccountPremiumPackage(ctx context.Context, tx *sql.Tx, accountId int64) (domain.AccountPurchasePackage, error)
+	PurchasePremiumPackageEntity(ctx context.Context, tx *sql.Tx, accountId int64, request domain.PremiumPurchaseRequest, startsAt time.Time) (domain.PackageDto, error)
 }
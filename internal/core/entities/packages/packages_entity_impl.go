@@ -4,12 +4,18 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"godating-dealls/internal/domain"
 	"godating-dealls/internal/infra/mysql/record"
 	"godating-dealls/internal/infra/mysql/repo"
 	"time"
 )
 
+var (
+	ErrPackageNotFound     = errors.New("package not found")
+	ErrPackagePriceChanged = errors.New("package price changed")
+)
+
 type PackageEntityImpl struct {
 	PackagesRepository         repo.PackagesRepository
 	PurchasePackagesRepository repo.PurchasePackagesRepository
@@ -44,30 +50,43 @@ func (p PackageEntityImpl) GetAllPackagesEntity(ctx context.Context, tx *sql.Tx)
 	return packages, nil
 }
 
-func (p PackageEntityImpl) PurchasePackage(ctx context.Context, tx *sql.Tx, dto domain.PackageDto) error {
-	rec := record.AccountPremiumRecord{
-		AccountID:             dto.AccountID,
-		PackageID:             dto.PackageID,
-		PurchaseDate:          time.Now(),
-		ExpiryDate:            time.Now().AddDate(0, int(dto.PackageDurationInMonthly), 0),
-		UnlimitedSwipesActive: dto.UnlimitedSwipes,
-		Status:                dto.Status,
-	}
-	err := p.PurchasePackagesRepository.PurchasePackagesByAccount(ctx, tx, rec)
+// PurchasePremiumPackageEntity adds a subscription to the package as it is sold now, running from startsAt so a renewal
+// bought before the end of the current subscription extends it rather than overlapping it
+func (p PackageEntityImpl) PurchasePremiumPackageEntity(ctx context.Context, tx *sql.Tx, accountId int64, request domain.PremiumPurchaseRequest, startsAt time.Time) (domain.PackageDto, error) {
+	pkg, err := p.PackagesRepository.FindPackageByIdFromDB(ctx, tx, request.PackageID)
 	if err != nil {
-		return errors.New("purchase packages by account is failed")
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.PackageDto{}, ErrPackageNotFound
+		}
+		return domain.PackageDto{}, errors.New("failed to find package")
+	}
+	if !pkg.Status {
+		return domain.PackageDto{}, ErrPackageNotFound
+	}
+	if request.Price != nil && *request.Price != pkg.Price {
+		return domain.PackageDto{}, fmt.Errorf("%w: the package now costs %.0f", ErrPackagePriceChanged, pkg.Price)
 	}
-	return nil
-}
 
-func (p PackageEntityImpl) FindAccountPremiumPackage(ctx context.Context, tx *sql.Tx, accountId int64) (domain.AccountPurchasePackage, error) {
-	premiumPackage, err := p.PurchasePackagesRepository.FindAccountPremiumByAccountId(ctx, tx, accountId)
-	if err != nil {
-		return domain.AccountPurchasePackage{}, errors.New("find account premium package is failed")
+	rec := record.AccountPremiumRecord{
+		AccountID:             accountId,
+		PackageID:             pkg.PackageID,
+		PurchaseDate:          time.Now(),
+		ExpiryDate:            startsAt.AddDate(0, int(pkg.PackageDurationInMonthly), 0),
+		UnlimitedSwipesActive: pkg.UnlimitedSwipes,
+		Status:                true,
 	}
-	res := domain.AccountPurchasePackage{
-		AccountID: premiumPackage.AccountID,
-		ExpiresIn: premiumPackage.ExpiryDate,
+	if err := p.PurchasePackagesRepository.PurchasePackagesByAccount(ctx, tx, rec); err != nil {
+		return domain.PackageDto{}, errors.New("purchase packages by account is failed")
 	}
-	return res, nil
+
+	return domain.PackageDto{
+		PackageID:                pkg.PackageID,
+		PackageName:              pkg.PackageName,
+		Description:              pkg.Description,
+		PackageDurationInMonthly: pkg.PackageDurationInMonthly,
+		Price:                    pkg.Price,
+		UnlimitedSwipes:          pkg.UnlimitedSwipes,
+		Status:                   pkg.Status,
+		AccountID:                accountId,
+	}, nil
 }
@@ -0,0 +1,15 @@
+package passkeys
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/domain"
+)
+
+type PasskeyEntity interface {
+	SavePasskeyEntity(ctx context.Context, tx *sql.Tx, dto domain.PasskeyDto) (domain.PasskeyDto, error)
+	FindPasskeyEntity(ctx context.Context, tx *sql.Tx, credentialId []byte) (domain.PasskeyDto, error)
+	FindPasskeysEntity(ctx context.Context, tx *sql.Tx, accountId int64) ([]domain.PasskeyDto, error)
+	UpdatePasskeySignCountEntity(ctx context.Context, tx *sql.Tx, credentialId []byte, signCount uint32) error
+	DeletePasskeyEntity(ctx context.Context, tx *sql.Tx, accountId int64, credentialId []byte) error
+}
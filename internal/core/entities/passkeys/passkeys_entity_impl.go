@@ -0,0 +1,108 @@
+package passkeys
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/record"
+	"godating-dealls/internal/infra/mysql/repo"
+	"strings"
+	"time"
+)
+
+// maxPasskeysPerAccount keeps the exclude list sent to authenticators short
+const maxPasskeysPerAccount = 10
+
+type PasskeyEntityImpl struct {
+	PasskeyCredentialsRepository repo.PasskeyCredentialsRepository
+	Validate                     *validator.Validate
+}
+
+func NewPasskeyEntityImpl(passkeyCredentialsRepository repo.PasskeyCredentialsRepository, validate *validator.Validate) PasskeyEntity {
+	return &PasskeyEntityImpl{PasskeyCredentialsRepository: passkeyCredentialsRepository, Validate: validate}
+}
+
+// SavePasskeyEntity a credential id belongs to one account only, registering it again is refused
+func (p PasskeyEntityImpl) SavePasskeyEntity(ctx context.Context, tx *sql.Tx, dto domain.PasskeyDto) (domain.PasskeyDto, error) {
+	dto.Name = strings.TrimSpace(dto.Name)
+	err := p.Validate.Struct(dto)
+	if err != nil {
+		return domain.PasskeyDto{}, err
+	}
+	if len(dto.CredentialID) == 0 {
+		return domain.PasskeyDto{}, errors.New("credential id is required")
+	}
+
+	existing, err := p.PasskeyCredentialsRepository.FindPasskeysByAccountIdFromDB(ctx, tx, dto.AccountID)
+	if err != nil {
+		return domain.PasskeyDto{}, errors.New("failed to find passkeys")
+	}
+	if len(existing) >= maxPasskeysPerAccount {
+		return domain.PasskeyDto{}, errors.New("passkey limit reached, remove one before adding another")
+	}
+
+	err = p.PasskeyCredentialsRepository.InsertPasskeyToDB(ctx, tx, record.PasskeyCredentialRecord{
+		CredentialID: dto.CredentialID,
+		AccountID:    dto.AccountID,
+		Name:         dto.Name,
+		PublicKey:    dto.PublicKey,
+		SignCount:    dto.SignCount,
+	})
+	var duplicate *repo.DuplicateKeyError
+	if errors.As(err, &duplicate) {
+		return domain.PasskeyDto{}, errors.New("passkey already registered")
+	}
+	if err != nil {
+		return domain.PasskeyDto{}, errors.New("failed to insert passkey")
+	}
+
+	dto.CreatedAt = time.Now()
+	return dto, nil
+}
+
+func (p PasskeyEntityImpl) FindPasskeyEntity(ctx context.Context, tx *sql.Tx, credentialId []byte) (domain.PasskeyDto, error) {
+	rec, err := p.PasskeyCredentialsRepository.FindPasskeyByCredentialIdFromDB(ctx, tx, credentialId)
+	if err != nil {
+		return domain.PasskeyDto{}, err
+	}
+	return passkeyFromRecord(rec), nil
+}
+
+func (p PasskeyEntityImpl) FindPasskeysEntity(ctx context.Context, tx *sql.Tx, accountId int64) ([]domain.PasskeyDto, error) {
+	records, err := p.PasskeyCredentialsRepository.FindPasskeysByAccountIdFromDB(ctx, tx, accountId)
+	if err != nil {
+		return nil, errors.New("failed to find passkeys")
+	}
+
+	var passkeys []domain.PasskeyDto
+	for _, rec := range records {
+		passkeys = append(passkeys, passkeyFromRecord(rec))
+	}
+	return passkeys, nil
+}
+
+func (p PasskeyEntityImpl) UpdatePasskeySignCountEntity(ctx context.Context, tx *sql.Tx, credentialId []byte, signCount uint32) error {
+	err := p.PasskeyCredentialsRepository.UpdatePasskeySignCountToDB(ctx, tx, credentialId, signCount)
+	if err != nil {
+		return errors.New("failed to update passkey")
+	}
+	return nil
+}
+
+func (p PasskeyEntityImpl) DeletePasskeyEntity(ctx context.Context, tx *sql.Tx, accountId int64, credentialId []byte) error {
+	return p.PasskeyCredentialsRepository.DeletePasskeyFromDB(ctx, tx, accountId, credentialId)
+}
+
+func passkeyFromRecord(rec record.PasskeyCredentialRecord) domain.PasskeyDto {
+	return domain.PasskeyDto{
+		CredentialID: rec.CredentialID,
+		AccountID:    rec.AccountID,
+		Name:         rec.Name,
+		PublicKey:    rec.PublicKey,
+		SignCount:    rec.SignCount,
+		CreatedAt:    rec.CreatedAt,
+		LastUsedAt:   rec.LastUsedAt,
+	}
+}
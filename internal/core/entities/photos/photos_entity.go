@@ -0,0 +1,28 @@
+package photos
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/domain"
+	"time"
+)
+
+type PhotoEntity interface {
+	ValidatePhotoUploadEntity(upload domain.PhotoUpload) (string, error)
+	AddPhotoEntity(ctx context.Context, tx *sql.Tx, accountId int64, storageKey string, upload domain.PhotoUpload) (domain.PhotoDto, error)
+	FindPhotosEntity(ctx context.Context, tx *sql.Tx, accountId int64) ([]domain.PhotoDto, error)
+	FindAllPhotosEntity(ctx context.Context, tx *sql.Tx, accountId int64) ([]domain.PhotoDto, error)
+	FindPhotosByAccountsEntity(ctx context.Context, tx *sql.Tx, accountIds []int64) (map[int64][]domain.PhotoDto, error)
+	ReorderPhotosEntity(ctx context.Context, tx *sql.Tx, accountId int64, photoIds []int64) ([]domain.PhotoDto, error)
+	SetPrimaryPhotoEntity(ctx context.Context, tx *sql.Tx, accountId int64, photoId int64) ([]domain.PhotoDto, error)
+	DeletePhotoEntity(ctx context.Context, tx *sql.Tx, accountId int64, photoId int64) (domain.PhotoDto, error)
+	FindTrashedPhotosEntity(ctx context.Context, tx *sql.Tx, accountId int64, now time.Time) ([]domain.PhotoDto, error)
+	RestorePhotoEntity(ctx context.Context, tx *sql.Tx, accountId int64, photoId int64, now time.Time) ([]domain.PhotoDto, error)
+	FindExpiredTrashedPhotosEntity(ctx context.Context, tx *sql.Tx, now time.Time, limit int) ([]domain.PhotoDto, error)
+	PurgePhotoEntity(ctx context.Context, tx *sql.Tx, photoId int64) error
+	SetSmartPhotosEntity(ctx context.Context, tx *sql.Tx, accountId int64, enabled bool) error
+	FindSmartPhotosEntity(ctx context.Context, tx *sql.Tx, accountId int64) (domain.SmartPhotosDto, error)
+	ArrangePhotosForViewerEntity(ctx context.Context, tx *sql.Tx, viewerAccountId int64, photosByAccount map[int64][]domain.PhotoDto) (map[int64][]domain.PhotoDto, error)
+	RecordPhotoLikeEntity(ctx context.Context, tx *sql.Tx, ownerAccountId int64, viewerAccountId int64) error
+	RevokePhotoLikeEntity(ctx context.Context, tx *sql.Tx, ownerAccountId int64, viewerAccountId int64) error
+}
@@ -0,0 +1,402 @@
+package photos
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/record"
+	"godating-dealls/internal/infra/mysql/repo"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	MaxProfilePhotos = 6
+	MaxPhotoBytes    = 5 << 20
+
+	// MinSmartPhotoImpressions is how many viewers must have seen a photo first before it can be named the best one
+	MinSmartPhotoImpressions = 30
+
+	// PhotoTrashRetention is how long a deleted photo can be restored, the cleanup job purges it afterwards
+	PhotoTrashRetention = 30 * 24 * time.Hour
+)
+
+var (
+	ErrInvalidPhoto      = errors.New("invalid photo")
+	ErrPhotoLimitReached = fmt.Errorf("a profile has at most %d photos, delete one first", MaxProfilePhotos)
+	ErrPhotoNotFound     = errors.New("photo not found")
+
+	// photoExtensions are the accepted content types, with the extension of the stored file
+	photoExtensions = map[string]string{
+		"image/jpeg": ".jpg",
+		"image/png":  ".png",
+		"image/webp": ".webp",
+	}
+)
+
+type PhotoEntityImpl struct {
+	ProfilePhotosRepository repo.ProfilePhotosRepository
+	SmartPhotosRepository   repo.SmartPhotosRepository
+}
+
+func NewPhotoEntityImpl(profilePhotosRepository repo.ProfilePhotosRepository, smartPhotosRepository repo.SmartPhotosRepository) PhotoEntity {
+	return &PhotoEntityImpl{ProfilePhotosRepository: profilePhotosRepository, SmartPhotosRepository: smartPhotosRepository}
+}
+
+// ValidatePhotoUploadEntity runs before the file is stored and returns the extension to store it with
+func (p PhotoEntityImpl) ValidatePhotoUploadEntity(upload domain.PhotoUpload) (string, error) {
+	if len(upload.Data) == 0 {
+		return "", fmt.Errorf("%w: file is empty", ErrInvalidPhoto)
+	}
+	if len(upload.Data) > MaxPhotoBytes {
+		return "", fmt.Errorf("%w: file is larger than %d MB", ErrInvalidPhoto, MaxPhotoBytes>>20)
+	}
+	extension, ok := photoExtensions[upload.ContentType]
+	if !ok {
+		return "", fmt.Errorf("%w: only jpeg, png and webp images are accepted", ErrInvalidPhoto)
+	}
+	return extension, nil
+}
+
+// AddPhotoEntity appends the photo after the existing ones, the first photo of a profile becomes its primary photo
+func (p PhotoEntityImpl) AddPhotoEntity(ctx context.Context, tx *sql.Tx, accountId int64, storageKey string, upload domain.PhotoUpload) (domain.PhotoDto, error) {
+	existing, err := p.lockAndFindPhotos(ctx, tx, accountId)
+	if err != nil {
+		return domain.PhotoDto{}, err
+	}
+	if len(existing) >= MaxProfilePhotos {
+		return domain.PhotoDto{}, ErrPhotoLimitReached
+	}
+
+	photo, err := p.ProfilePhotosRepository.InsertProfilePhotoToDB(ctx, tx, record.ProfilePhotoRecord{
+		AccountID:   accountId,
+		StorageKey:  storageKey,
+		ContentType: upload.ContentType,
+		SizeBytes:   int64(len(upload.Data)),
+		Position:    len(existing),
+		IsPrimary:   len(existing) == 0,
+	})
+	if err != nil {
+		return domain.PhotoDto{}, errors.New("failed to save photo")
+	}
+	return toPhotoDto(photo), nil
+}
+
+// FindPhotosEntity returns the primary photo first, then the others in their order
+func (p PhotoEntityImpl) FindPhotosEntity(ctx context.Context, tx *sql.Tx, accountId int64) ([]domain.PhotoDto, error) {
+	photos, err := p.ProfilePhotosRepository.FindProfilePhotosFromDB(ctx, tx, accountId)
+	if err != nil {
+		return nil, errors.New("failed to find photos")
+	}
+	return toPhotoDtos(photos), nil
+}
+
+// FindAllPhotosEntity returns the photos of the account with the ones in the trash, e.g. to delete every stored file
+func (p PhotoEntityImpl) FindAllPhotosEntity(ctx context.Context, tx *sql.Tx, accountId int64) ([]domain.PhotoDto, error) {
+	photos, err := p.ProfilePhotosRepository.FindAllProfilePhotosFromDB(ctx, tx, accountId)
+	if err != nil {
+		return nil, errors.New("failed to find photos")
+	}
+	return toPhotoDtos(photos), nil
+}
+
+func (p PhotoEntityImpl) FindPhotosByAccountsEntity(ctx context.Context, tx *sql.Tx, accountIds []int64) (map[int64][]domain.PhotoDto, error) {
+	photos, err := p.ProfilePhotosRepository.FindProfilePhotosByAccountsFromDB(ctx, tx, accountIds)
+	if err != nil {
+		return nil, errors.New("failed to find photos")
+	}
+
+	res := map[int64][]domain.PhotoDto{}
+	for _, photo := range photos {
+		res[photo.AccountID] = append(res[photo.AccountID], toPhotoDto(photo))
+	}
+	return res, nil
+}
+
+// ReorderPhotosEntity photoIds must name every photo of the account exactly once, the primary photo keeps its flag
+func (p PhotoEntityImpl) ReorderPhotosEntity(ctx context.Context, tx *sql.Tx, accountId int64, photoIds []int64) ([]domain.PhotoDto, error) {
+	existing, err := p.lockAndFindPhotos(ctx, tx, accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	byId := map[int64]record.ProfilePhotoRecord{}
+	for _, photo := range existing {
+		byId[photo.PhotoID] = photo
+	}
+	if len(photoIds) != len(existing) {
+		return nil, fmt.Errorf("%w: the order must list all %d photos", ErrInvalidPhoto, len(existing))
+	}
+	seen := map[int64]bool{}
+	for _, photoId := range photoIds {
+		if _, ok := byId[photoId]; !ok || seen[photoId] {
+			return nil, fmt.Errorf("%w: the order must list every photo once", ErrInvalidPhoto)
+		}
+		seen[photoId] = true
+	}
+
+	for position, photoId := range photoIds {
+		if err := p.ProfilePhotosRepository.UpdateProfilePhotoOrderToDB(ctx, tx, photoId, position, byId[photoId].IsPrimary); err != nil {
+			return nil, errors.New("failed to reorder photos")
+		}
+	}
+	return p.FindPhotosEntity(ctx, tx, accountId)
+}
+
+func (p PhotoEntityImpl) SetPrimaryPhotoEntity(ctx context.Context, tx *sql.Tx, accountId int64, photoId int64) ([]domain.PhotoDto, error) {
+	existing, err := p.lockAndFindPhotos(ctx, tx, accountId)
+	if err != nil {
+		return nil, err
+	}
+	if !containsPhoto(existing, photoId) {
+		return nil, ErrPhotoNotFound
+	}
+
+	for _, photo := range existing {
+		if err := p.ProfilePhotosRepository.UpdateProfilePhotoOrderToDB(ctx, tx, photo.PhotoID, photo.Position, photo.PhotoID == photoId); err != nil {
+			return nil, errors.New("failed to set primary photo")
+		}
+	}
+	return p.FindPhotosEntity(ctx, tx, accountId)
+}
+
+// DeletePhotoEntity moves the photo to the trash and closes the gap in the order, without its primary photo the profile
+// falls back to the first remaining one. The row and the stored file stay until the photo is purged
+func (p PhotoEntityImpl) DeletePhotoEntity(ctx context.Context, tx *sql.Tx, accountId int64, photoId int64) (domain.PhotoDto, error) {
+	existing, err := p.lockAndFindPhotos(ctx, tx, accountId)
+	if err != nil {
+		return domain.PhotoDto{}, err
+	}
+
+	var deleted record.ProfilePhotoRecord
+	var remaining []record.ProfilePhotoRecord
+	for _, photo := range existing {
+		if photo.PhotoID == photoId {
+			deleted = photo
+			continue
+		}
+		remaining = append(remaining, photo)
+	}
+	if deleted.PhotoID == 0 {
+		return domain.PhotoDto{}, ErrPhotoNotFound
+	}
+
+	if err := p.ProfilePhotosRepository.TrashProfilePhotoToDB(ctx, tx, photoId); err != nil {
+		return domain.PhotoDto{}, errors.New("failed to delete photo")
+	}
+	for position, photo := range remaining {
+		primary := photo.IsPrimary || (deleted.IsPrimary && position == 0)
+		if err := p.ProfilePhotosRepository.UpdateProfilePhotoOrderToDB(ctx, tx, photo.PhotoID, position, primary); err != nil {
+			return domain.PhotoDto{}, errors.New("failed to delete photo")
+		}
+	}
+	return toPhotoDto(deleted), nil
+}
+
+// FindTrashedPhotosEntity returns the photos that can still be restored, the latest deleted first
+func (p PhotoEntityImpl) FindTrashedPhotosEntity(ctx context.Context, tx *sql.Tx, accountId int64, now time.Time) ([]domain.PhotoDto, error) {
+	photos, err := p.ProfilePhotosRepository.FindTrashedProfilePhotosFromDB(ctx, tx, accountId, now.Add(-PhotoTrashRetention))
+	if err != nil {
+		return nil, errors.New("failed to find deleted photos")
+	}
+	return toPhotoDtos(photos), nil
+}
+
+// RestorePhotoEntity puts the photo back after the others, as the primary photo when the profile has none.
+// A photo past the retention is not found even when the cleanup job did not purge it yet
+func (p PhotoEntityImpl) RestorePhotoEntity(ctx context.Context, tx *sql.Tx, accountId int64, photoId int64, now time.Time) ([]domain.PhotoDto, error) {
+	existing, err := p.lockAndFindPhotos(ctx, tx, accountId)
+	if err != nil {
+		return nil, err
+	}
+	trashed, err := p.ProfilePhotosRepository.FindTrashedProfilePhotosFromDB(ctx, tx, accountId, now.Add(-PhotoTrashRetention))
+	if err != nil {
+		return nil, errors.New("failed to find deleted photos")
+	}
+	if !containsPhoto(trashed, photoId) {
+		return nil, ErrPhotoNotFound
+	}
+	if len(existing) >= MaxProfilePhotos {
+		return nil, ErrPhotoLimitReached
+	}
+
+	if err := p.ProfilePhotosRepository.RestoreProfilePhotoToDB(ctx, tx, photoId, len(existing), len(existing) == 0); err != nil {
+		return nil, errors.New("failed to restore photo")
+	}
+	return p.FindPhotosEntity(ctx, tx, accountId)
+}
+
+func (p PhotoEntityImpl) FindExpiredTrashedPhotosEntity(ctx context.Context, tx *sql.Tx, now time.Time, limit int) ([]domain.PhotoDto, error) {
+	photos, err := p.ProfilePhotosRepository.FindExpiredTrashedPhotosFromDB(ctx, tx, now.Add(-PhotoTrashRetention), limit)
+	if err != nil {
+		return nil, errors.New("failed to find expired deleted photos")
+	}
+	return toPhotoDtos(photos), nil
+}
+
+// PurgePhotoEntity deletes the row for good, the stored file is left to the caller, it must only go once the transaction committed
+func (p PhotoEntityImpl) PurgePhotoEntity(ctx context.Context, tx *sql.Tx, photoId int64) error {
+	if err := p.ProfilePhotosRepository.DeleteProfilePhotoToDB(ctx, tx, photoId); err != nil {
+		return errors.New("failed to purge photo")
+	}
+	return nil
+}
+
+func (p PhotoEntityImpl) SetSmartPhotosEntity(ctx context.Context, tx *sql.Tx, accountId int64, enabled bool) error {
+	if err := p.SmartPhotosRepository.UpsertSmartPhotoSettingToDB(ctx, tx, accountId, enabled); err != nil {
+		return errors.New("failed to save smart photos setting")
+	}
+	return nil
+}
+
+// FindSmartPhotosEntity reports per photo how many viewers were shown it first and how many of them liked the profile.
+// The best photo has the highest like rate among at least two photos that reached MinSmartPhotoImpressions
+func (p PhotoEntityImpl) FindSmartPhotosEntity(ctx context.Context, tx *sql.Tx, accountId int64) (domain.SmartPhotosDto, error) {
+	var res domain.SmartPhotosDto
+	setting, err := p.SmartPhotosRepository.FindSmartPhotoSettingFromDB(ctx, tx, accountId)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return domain.SmartPhotosDto{}, errors.New("failed to find smart photos setting")
+	}
+	res.Enabled = setting.Enabled
+
+	performances, err := p.SmartPhotosRepository.FindPhotoPerformanceFromDB(ctx, tx, accountId)
+	if err != nil {
+		return domain.SmartPhotosDto{}, errors.New("failed to find photo performance")
+	}
+
+	compared := 0
+	var best domain.PhotoPerformanceDto
+	for _, performance := range performances {
+		dto := domain.PhotoPerformanceDto{PhotoID: performance.PhotoID, Impressions: performance.Impressions, Likes: performance.Likes}
+		res.Photos = append(res.Photos, dto)
+		if dto.Impressions < MinSmartPhotoImpressions {
+			continue
+		}
+		compared++
+		// Compare the rates without dividing, likes/impressions > best.likes/best.impressions
+		if best.PhotoID == 0 || dto.Likes*best.Impressions > best.Likes*dto.Impressions {
+			best = dto
+		}
+	}
+	if compared >= 2 {
+		res.BestPhotoID = &best.PhotoID
+	}
+	return res, nil
+}
+
+// ArrangePhotosForViewerEntity moves the photo a viewer is given to the front for the owners with smart photos on,
+// and records it as that viewer's impression. A viewer keeps the same photo as long as the photos of the owner do not change
+func (p PhotoEntityImpl) ArrangePhotosForViewerEntity(ctx context.Context, tx *sql.Tx, viewerAccountId int64, photosByAccount map[int64][]domain.PhotoDto) (map[int64][]domain.PhotoDto, error) {
+	var candidates []int64
+	for ownerAccountId, photos := range photosByAccount {
+		if ownerAccountId != viewerAccountId && len(photos) > 1 {
+			candidates = append(candidates, ownerAccountId)
+		}
+	}
+	owners, err := p.SmartPhotosRepository.FindSmartPhotoAccountsFromDB(ctx, tx, candidates)
+	if err != nil {
+		return nil, errors.New("failed to find smart photos settings")
+	}
+
+	for _, ownerAccountId := range owners {
+		photos := photosByAccount[ownerAccountId]
+		// Rotate over the upload order, not over the primary first order
+		ordered := append([]domain.PhotoDto(nil), photos...)
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return ordered[i].Position < ordered[j].Position
+		})
+		shown := ordered[smartPhotoVariant(ownerAccountId, viewerAccountId, len(ordered))]
+
+		arranged := []domain.PhotoDto{shown}
+		for _, photo := range photos {
+			if photo.PhotoID != shown.PhotoID {
+				arranged = append(arranged, photo)
+			}
+		}
+		photosByAccount[ownerAccountId] = arranged
+
+		err := p.SmartPhotosRepository.UpsertPhotoImpressionToDB(ctx, tx, record.PhotoImpressionRecord{
+			OwnerAccountID:  ownerAccountId,
+			ViewerAccountID: viewerAccountId,
+			PhotoID:         shown.PhotoID,
+		})
+		if err != nil {
+			return nil, errors.New("failed to record photo impression")
+		}
+	}
+	return photosByAccount, nil
+}
+
+// RecordPhotoLikeEntity credits the like to the photo the viewer was shown first, nothing happens without an impression
+func (p PhotoEntityImpl) RecordPhotoLikeEntity(ctx context.Context, tx *sql.Tx, ownerAccountId int64, viewerAccountId int64) error {
+	if err := p.SmartPhotosRepository.UpdatePhotoImpressionLikedToDB(ctx, tx, ownerAccountId, viewerAccountId, true); err != nil {
+		return errors.New("failed to record photo like")
+	}
+	return nil
+}
+
+// RevokePhotoLikeEntity takes back the like credited by RecordPhotoLikeEntity, for an undone swipe
+func (p PhotoEntityImpl) RevokePhotoLikeEntity(ctx context.Context, tx *sql.Tx, ownerAccountId int64, viewerAccountId int64) error {
+	if err := p.SmartPhotosRepository.UpdatePhotoImpressionLikedToDB(ctx, tx, ownerAccountId, viewerAccountId, false); err != nil {
+		return errors.New("failed to revoke photo like")
+	}
+	return nil
+}
+
+// lockAndFindPhotos returns the photos of the locked account in their order, ignoring the primary flag
+func (p PhotoEntityImpl) lockAndFindPhotos(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.ProfilePhotoRecord, error) {
+	if err := p.ProfilePhotosRepository.LockPhotoOwnerFromDB(ctx, tx, accountId); err != nil {
+		return nil, errors.New("failed to lock account")
+	}
+	photos, err := p.ProfilePhotosRepository.FindProfilePhotosFromDB(ctx, tx, accountId)
+	if err != nil {
+		return nil, errors.New("failed to find photos")
+	}
+
+	sort.SliceStable(photos, func(i, j int) bool {
+		return photos[i].Position < photos[j].Position
+	})
+	return photos, nil
+}
+
+// smartPhotoVariant places the viewer in 0 to n-1 for the owner, independently per owner
+func smartPhotoVariant(ownerAccountId int64, viewerAccountId int64, n int) int {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(strconv.FormatInt(ownerAccountId, 10) + ":" + strconv.FormatInt(viewerAccountId, 10)))
+	return int(hash.Sum32() % uint32(n))
+}
+
+func containsPhoto(photos []record.ProfilePhotoRecord, photoId int64) bool {
+	for _, photo := range photos {
+		if photo.PhotoID == photoId {
+			return true
+		}
+	}
+	return false
+}
+
+func toPhotoDtos(photos []record.ProfilePhotoRecord) []domain.PhotoDto {
+	var res []domain.PhotoDto
+	for _, photo := range photos {
+		res = append(res, toPhotoDto(photo))
+	}
+	return res
+}
+
+func toPhotoDto(photo record.ProfilePhotoRecord) domain.PhotoDto {
+	return domain.PhotoDto{
+		PhotoID:     photo.PhotoID,
+		AccountID:   photo.AccountID,
+		StorageKey:  photo.StorageKey,
+		ContentType: photo.ContentType,
+		SizeBytes:   photo.SizeBytes,
+		Position:    photo.Position,
+		Primary:     photo.IsPrimary,
+		CreatedAt:   photo.CreatedAt,
+		DeletedAt:   photo.DeletedAt,
+	}
+}
@@ -0,0 +1,15 @@
+package profile_changes
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/domain"
+)
+
+type ProfileChangeEntity interface {
+	SubmitProfileChangeEntity(ctx context.Context, tx *sql.Tx, dto domain.ProfileChangeDto) (domain.ProfileChangeDto, error)
+	FindProfileChangesEntity(ctx context.Context, tx *sql.Tx, accountId int64) ([]domain.ProfileChangeDto, error)
+	FindPendingProfileChangesEntity(ctx context.Context, tx *sql.Tx) ([]domain.ProfileChangeDto, error)
+	StartProfileChangeReviewEntity(ctx context.Context, tx *sql.Tx, requestId int64) (domain.ProfileChangeDto, error)
+	CloseProfileChangeReviewEntity(ctx context.Context, tx *sql.Tx, dto domain.ProfileChangeDto) (domain.ProfileChangeDto, error)
+}
@@ -0,0 +1,161 @@
+package profile_changes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/record"
+	"godating-dealls/internal/infra/mysql/repo"
+)
+
+var (
+	ErrProfileFieldLocked    = errors.New("field is locked after verification, submit a profile change request")
+	ErrInvalidProfileChange  = errors.New("invalid profile change request")
+	ErrProfileChangePending  = errors.New("a change request for this field is already pending")
+	ErrProfileChangeNotFound = errors.New("profile change request not found")
+	ErrProfileChangeDecided  = errors.New("profile change request is already decided")
+)
+
+type ProfileChangeEntityImpl struct {
+	ProfileChangesRepository repo.ProfileChangesRepository
+	validate                 *validator.Validate
+}
+
+func NewProfileChangeEntityImpl(profileChangesRepository repo.ProfileChangesRepository, validate *validator.Validate) ProfileChangeEntity {
+	return &ProfileChangeEntityImpl{ProfileChangesRepository: profileChangesRepository, validate: validate}
+}
+
+// SubmitProfileChangeEntity queues the request for a moderator, the requested value must already satisfy the profile rules
+func (p ProfileChangeEntityImpl) SubmitProfileChangeEntity(ctx context.Context, tx *sql.Tx, dto domain.ProfileChangeDto) (domain.ProfileChangeDto, error) {
+	if err := p.validate.Struct(dto); err != nil {
+		return domain.ProfileChangeDto{}, fmt.Errorf("%w: %v", ErrInvalidProfileChange, err)
+	}
+	if dto.RequestedValue == dto.CurrentValue {
+		return domain.ProfileChangeDto{}, fmt.Errorf("%w: requested value equals the current value", ErrInvalidProfileChange)
+	}
+	switch dto.Field {
+	case "date_of_birth":
+		if err := p.validate.Var(dto.RequestedValue, "adult-age"); err != nil {
+			return domain.ProfileChangeDto{}, fmt.Errorf("%w: date of birth must be YYYY-MM-DD and at least 18 years ago", ErrInvalidProfileChange)
+		}
+	case "gender":
+		if err := p.validate.Var(dto.RequestedValue, "max=5"); err != nil {
+			return domain.ProfileChangeDto{}, fmt.Errorf("%w: gender is at most 5 characters", ErrInvalidProfileChange)
+		}
+	}
+
+	request, err := p.ProfileChangesRepository.InsertProfileChangeRequestToDB(ctx, tx, record.ProfileChangeRequestRecord{
+		AccountID:      dto.AccountID,
+		Field:          dto.Field,
+		CurrentValue:   dto.CurrentValue,
+		RequestedValue: dto.RequestedValue,
+		Evidence:       dto.Evidence,
+		Reason:         dto.Reason,
+	})
+	if err != nil {
+		var duplicate *repo.DuplicateKeyError
+		if errors.As(err, &duplicate) {
+			return domain.ProfileChangeDto{}, ErrProfileChangePending
+		}
+		return domain.ProfileChangeDto{}, errors.New("failed to submit profile change request")
+	}
+	return toProfileChangeDto(request), nil
+}
+
+func (p ProfileChangeEntityImpl) FindProfileChangesEntity(ctx context.Context, tx *sql.Tx, accountId int64) ([]domain.ProfileChangeDto, error) {
+	requests, err := p.ProfileChangesRepository.FindProfileChangeRequestsByAccountFromDB(ctx, tx, accountId)
+	if err != nil {
+		return nil, errors.New("failed to find profile change requests")
+	}
+	return toProfileChangeDtos(requests), nil
+}
+
+func (p ProfileChangeEntityImpl) FindPendingProfileChangesEntity(ctx context.Context, tx *sql.Tx) ([]domain.ProfileChangeDto, error) {
+	requests, err := p.ProfileChangesRepository.FindPendingProfileChangeRequestsFromDB(ctx, tx)
+	if err != nil {
+		return nil, errors.New("failed to find pending profile change requests")
+	}
+	return toProfileChangeDtos(requests), nil
+}
+
+// StartProfileChangeReviewEntity locks a pending request until the transaction deciding it ends
+func (p ProfileChangeEntityImpl) StartProfileChangeReviewEntity(ctx context.Context, tx *sql.Tx, requestId int64) (domain.ProfileChangeDto, error) {
+	request, err := p.ProfileChangesRepository.FindProfileChangeRequestForUpdateFromDB(ctx, tx, requestId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ProfileChangeDto{}, ErrProfileChangeNotFound
+		}
+		return domain.ProfileChangeDto{}, errors.New("failed to find profile change request")
+	}
+	if request.Status != domain.ProfileChangePending {
+		return domain.ProfileChangeDto{}, ErrProfileChangeDecided
+	}
+	return toProfileChangeDto(request), nil
+}
+
+// CloseProfileChangeReviewEntity stores the decision, the reviewer and the audit entry that records it
+func (p ProfileChangeEntityImpl) CloseProfileChangeReviewEntity(ctx context.Context, tx *sql.Tx, dto domain.ProfileChangeDto) (domain.ProfileChangeDto, error) {
+	err := p.ProfileChangesRepository.UpdateProfileChangeReviewToDB(ctx, tx, record.ProfileChangeRequestRecord{
+		RequestID:  dto.RequestID,
+		Status:     dto.Status,
+		Reviewer:   dto.Reviewer,
+		ReviewNote: dto.ReviewNote,
+		AuditID:    dto.AuditID,
+	})
+	if err != nil {
+		return domain.ProfileChangeDto{}, errors.New("failed to update profile change request")
+	}
+
+	request, err := p.ProfileChangesRepository.FindProfileChangeRequestByIdFromDB(ctx, tx, dto.RequestID)
+	if err != nil {
+		return domain.ProfileChangeDto{}, errors.New("failed to find profile change request")
+	}
+	return toProfileChangeDto(request), nil
+}
+
+func toProfileChangeDtos(requests []record.ProfileChangeRequestRecord) []domain.ProfileChangeDto {
+	var res []domain.ProfileChangeDto
+	for _, request := range requests {
+		res = append(res, toProfileChangeDto(request))
+	}
+	return res
+}
+
+func toProfileChangeDto(request record.ProfileChangeRequestRecord) domain.ProfileChangeDto {
+	return domain.ProfileChangeDto{
+		RequestID:      request.RequestID,
+		AccountID:      request.AccountID,
+		Field:          request.Field,
+		CurrentValue:   request.CurrentValue,
+		RequestedValue: request.RequestedValue,
+		Evidence:       request.Evidence,
+		Reason:         request.Reason,
+		Status:         request.Status,
+		Reviewer:       request.Reviewer,
+		ReviewNote:     request.ReviewNote,
+		AuditID:        request.AuditID,
+		CreatedAt:      request.CreatedAt,
+		ReviewedAt:     request.ReviewedAt,
+	}
+}
+
+// LockedFieldValues returns the fields a verified account can no longer edit itself, with their stored value.
+// They prove who the user is, so they change only through a reviewed profile change request
+func LockedFieldValues(user domain.Users) map[string]string {
+	values := map[string]string{
+		"full_name":     "",
+		"date_of_birth": "",
+		"gender":        user.Gender,
+	}
+	if user.FullName != nil {
+		values["full_name"] = *user.FullName
+	}
+	if user.DateOfBirth != nil {
+		values["date_of_birth"] = common.FormatFromTimeToStr(user.DateOfBirth)
+	}
+	return values
+}
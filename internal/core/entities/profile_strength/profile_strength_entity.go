@@ -0,0 +1,7 @@
+package profile_strength
+
+import "godating-dealls/internal/domain"
+
+type ProfileStrengthEntity interface {
+	EvaluateProfileEntity(user domain.Users, imports []domain.ImportedContentDto, uploadedPhotos int) domain.ProfileStrengthDto
+}
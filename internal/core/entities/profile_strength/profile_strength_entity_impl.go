@@ -0,0 +1,62 @@
+package profile_strength
+
+import (
+	"godating-dealls/internal/domain"
+	"sort"
+	"strings"
+)
+
+// shortBioLength bios under this length rarely give a match anything to start a conversation with
+const shortBioLength = 50
+
+type ProfileStrengthEntityImpl struct{}
+
+func NewProfileStrengthEntityImpl() ProfileStrengthEntity {
+	return &ProfileStrengthEntityImpl{}
+}
+
+// EvaluateProfileEntity scores the profile out of 100, every missing signal costs its impact and becomes a suggestion.
+// Uploaded photos and photos imported from Instagram count the same
+func (p ProfileStrengthEntityImpl) EvaluateProfileEntity(user domain.Users, imports []domain.ImportedContentDto, uploadedPhotos int) domain.ProfileStrengthDto {
+	photos, artists := uploadedPhotos, 0
+	for _, content := range imports {
+		switch content.ContentType {
+		case "photo":
+			photos++
+		case "top_artist":
+			artists++
+		}
+	}
+
+	var suggestions []domain.ProfileSuggestionDto
+	add := func(missing bool, key string, message string, impact int) {
+		if missing {
+			suggestions = append(suggestions, domain.ProfileSuggestionDto{Key: key, Message: message, Impact: impact})
+		}
+	}
+
+	bio := strings.TrimSpace(user.Bio)
+	add(photos == 0, "add_photos", "Upload photos or connect Instagram, profiles with photos get far more likes", 30)
+	add(bio == "", "add_bio", "Write a short bio so others know what you are about", 20)
+	add(bio != "" && len(bio) < shortBioLength, "expand_bio", "Add a little more to your bio, a detail or question gives matches something to reply to", 10)
+	add(user.DateOfBirth == nil, "add_date_of_birth", "Add your date of birth so you show up in the right age ranges", 15)
+	add(artists == 0, "add_interests", "Connect Spotify to show your music taste", 10)
+	add(user.FullName == nil || strings.TrimSpace(*user.FullName) == "", "add_full_name", "Add your name", 10)
+	add(strings.TrimSpace(user.Address) == "", "add_location", "Add your city so nearby people can find you", 10)
+	add(strings.TrimSpace(user.Gender) == "", "add_gender", "Add your gender", 5)
+
+	// Highest impact first, the order above breaks ties
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Impact > suggestions[j].Impact
+	})
+
+	score := 100
+	for _, suggestion := range suggestions {
+		score -= suggestion.Impact
+	}
+	if score < 0 {
+		score = 0
+	}
+
+	return domain.ProfileStrengthDto{Score: score, Suggestions: suggestions}
+}
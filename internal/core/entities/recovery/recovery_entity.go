@@ -0,0 +1,19 @@
+package recovery
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/domain"
+	"time"
+)
+
+type RecoveryEntity interface {
+	SaveRecoveryContactsEntity(ctx context.Context, tx *sql.Tx, dto domain.RecoveryContactsDto) error
+	FindRecoveryContactsEntity(ctx context.Context, tx *sql.Tx, accountId int64) (domain.RecoveryContactsDto, error)
+	StartRecoveryEntity(ctx context.Context, tx *sql.Tx, accountId int64, waitingPeriod time.Duration, expiresAfter time.Duration) (domain.RecoveryRequestDto, string, error)
+	FindPendingRecoveryRequestsEntity(ctx context.Context, tx *sql.Tx, accountId int64) ([]domain.RecoveryRequestDto, error)
+	FindRecoveryRequestsToApproveEntity(ctx context.Context, tx *sql.Tx, contactAccountId int64) ([]domain.RecoveryRequestDto, error)
+	ApproveRecoveryEntity(ctx context.Context, tx *sql.Tx, contactAccountId int64, requestId int64) (string, error)
+	CompleteRecoveryEntity(ctx context.Context, tx *sql.Tx, token string, codes []string) (int64, error)
+	CancelRecoveryEntity(ctx context.Context, tx *sql.Tx, accountId int64) error
+}
@@ -0,0 +1,314 @@
+package recovery
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/record"
+	"godating-dealls/internal/infra/mysql/repo"
+	"strings"
+	"time"
+)
+
+const (
+	minRecoveryContacts    = 3
+	maxRecoveryContacts    = 5
+	minRecoveryThreshold   = 2
+	maxPendingRecoveries   = 3
+	maxRecoveryFailures    = 5
+	recoveryCodeBytes      = 5
+	recoveryTokenBytes     = 32
+	recoveryCodeSeparation = 4
+)
+
+// ErrRecoveryRejected is a completion with wrong or missing codes, the attempt is counted against the request
+var ErrRecoveryRejected = errors.New("recovery could not be completed, check the codes from your contacts")
+
+type RecoveryEntityImpl struct {
+	RecoveryRepository repo.RecoveryRepository
+}
+
+func NewRecoveryEntityImpl(recoveryRepository repo.RecoveryRepository) RecoveryEntity {
+	return &RecoveryEntityImpl{RecoveryRepository: recoveryRepository}
+}
+
+// SaveRecoveryContactsEntity a single contact must never be enough, so at least 3 contacts and 2 approvals are required.
+// Changing the contacts cancels the open requests, their approvals came from the old list
+func (r RecoveryEntityImpl) SaveRecoveryContactsEntity(ctx context.Context, tx *sql.Tx, dto domain.RecoveryContactsDto) error {
+	seen := map[int64]bool{}
+	for _, contactAccountId := range dto.ContactAccountIDs {
+		if contactAccountId == dto.AccountID {
+			return errors.New("you cannot be your own trusted contact")
+		}
+		if seen[contactAccountId] {
+			return errors.New("trusted contacts must be different accounts")
+		}
+		seen[contactAccountId] = true
+	}
+
+	if len(seen) < minRecoveryContacts || len(seen) > maxRecoveryContacts {
+		return fmt.Errorf("choose %d to %d trusted contacts", minRecoveryContacts, maxRecoveryContacts)
+	}
+	if dto.Threshold < minRecoveryThreshold || dto.Threshold > len(seen) {
+		return fmt.Errorf("threshold must be between %d and the number of contacts", minRecoveryThreshold)
+	}
+
+	err := r.RecoveryRepository.ReplaceRecoveryContactsToDB(ctx, tx, dto.AccountID, dto.ContactAccountIDs, dto.Threshold)
+	if err != nil {
+		return errors.New("failed to save trusted contacts")
+	}
+
+	err = r.RecoveryRepository.ClosePendingRecoveryRequestsToDB(ctx, tx, dto.AccountID, domain.RecoveryStatusCancelled)
+	if err != nil {
+		return errors.New("failed to cancel recovery requests")
+	}
+	return nil
+}
+
+func (r RecoveryEntityImpl) FindRecoveryContactsEntity(ctx context.Context, tx *sql.Tx, accountId int64) (domain.RecoveryContactsDto, error) {
+	contacts, err := r.RecoveryRepository.FindRecoveryContactsFromDB(ctx, tx, accountId)
+	if err != nil {
+		return domain.RecoveryContactsDto{}, errors.New("failed to find trusted contacts")
+	}
+	setting, err := r.RecoveryRepository.FindRecoverySettingFromDB(ctx, tx, accountId)
+	if err != nil {
+		return domain.RecoveryContactsDto{}, errors.New("failed to find trusted contacts")
+	}
+
+	dto := domain.RecoveryContactsDto{AccountID: accountId, Threshold: setting.Threshold}
+	for _, contact := range contacts {
+		dto.ContactAccountIDs = append(dto.ContactAccountIDs, contact.ContactAccountID)
+	}
+	return dto, nil
+}
+
+// StartRecoveryEntity opens a request and returns the token only the requester gets, the request cannot be completed
+// before the waiting period so the owner has time to notice and cancel it
+func (r RecoveryEntityImpl) StartRecoveryEntity(ctx context.Context, tx *sql.Tx, accountId int64, waitingPeriod time.Duration, expiresAfter time.Duration) (domain.RecoveryRequestDto, string, error) {
+	setting, err := r.RecoveryRepository.FindRecoverySettingFromDB(ctx, tx, accountId)
+	if err != nil {
+		return domain.RecoveryRequestDto{}, "", errors.New("failed to find trusted contacts")
+	}
+	if setting.Threshold == 0 {
+		return domain.RecoveryRequestDto{}, "", errors.New("account has no trusted contacts")
+	}
+
+	pending, err := r.RecoveryRepository.FindPendingRecoveryRequestsFromDB(ctx, tx, accountId)
+	if err != nil {
+		return domain.RecoveryRequestDto{}, "", errors.New("failed to find recovery requests")
+	}
+	if len(pending) >= maxPendingRecoveries {
+		return domain.RecoveryRequestDto{}, "", errors.New("too many recovery requests in progress")
+	}
+
+	secret, err := randomSecret(recoveryTokenBytes)
+	if err != nil {
+		return domain.RecoveryRequestDto{}, "", errors.New("failed to create recovery token")
+	}
+	token := hex.EncodeToString(secret)
+
+	createdAt := time.Now().Truncate(time.Second)
+	rec, err := r.RecoveryRepository.InsertRecoveryRequestToDB(ctx, tx, record.RecoveryRequestRecord{
+		AccountID:         accountId,
+		TokenHash:         common.StringEncoder(token),
+		Status:            domain.RecoveryStatusPending,
+		ApprovalsRequired: setting.Threshold,
+		CreatedAt:         createdAt,
+		AvailableAt:       createdAt.Add(waitingPeriod),
+		ExpiresAt:         createdAt.Add(expiresAfter),
+	})
+	if err != nil {
+		return domain.RecoveryRequestDto{}, "", errors.New("failed to create recovery request")
+	}
+	return toRecoveryRequestDto(rec, nil), token, nil
+}
+
+func (r RecoveryEntityImpl) FindPendingRecoveryRequestsEntity(ctx context.Context, tx *sql.Tx, accountId int64) ([]domain.RecoveryRequestDto, error) {
+	records, err := r.RecoveryRepository.FindPendingRecoveryRequestsFromDB(ctx, tx, accountId)
+	if err != nil {
+		return nil, errors.New("failed to find recovery requests")
+	}
+	return r.withApprovals(ctx, tx, records)
+}
+
+func (r RecoveryEntityImpl) FindRecoveryRequestsToApproveEntity(ctx context.Context, tx *sql.Tx, contactAccountId int64) ([]domain.RecoveryRequestDto, error) {
+	records, err := r.RecoveryRepository.FindPendingRecoveryRequestsForContactFromDB(ctx, tx, contactAccountId)
+	if err != nil {
+		return nil, errors.New("failed to find recovery requests")
+	}
+	return r.withApprovals(ctx, tx, records)
+}
+
+// ApproveRecoveryEntity returns the code the contact hands to the requester, it is only shown once
+func (r RecoveryEntityImpl) ApproveRecoveryEntity(ctx context.Context, tx *sql.Tx, contactAccountId int64, requestId int64) (string, error) {
+	request, err := r.RecoveryRepository.FindRecoveryRequestByIdFromDB(ctx, tx, requestId)
+	if err != nil {
+		return "", err
+	}
+	if request.Status != domain.RecoveryStatusPending || !request.ExpiresAt.After(time.Now()) {
+		return "", errors.New("recovery request is no longer open")
+	}
+
+	contacts, err := r.RecoveryRepository.FindRecoveryContactsFromDB(ctx, tx, request.AccountID)
+	if err != nil {
+		return "", errors.New("failed to find trusted contacts")
+	}
+	if !isRecoveryContact(contacts, contactAccountId) {
+		return "", errors.New("recovery request not found")
+	}
+
+	secret, err := randomSecret(recoveryCodeBytes)
+	if err != nil {
+		return "", errors.New("failed to create recovery code")
+	}
+	code := formatRecoveryCode(secret)
+
+	err = r.RecoveryRepository.InsertRecoveryApprovalToDB(ctx, tx, record.RecoveryApprovalRecord{
+		RequestID:        requestId,
+		ContactAccountID: contactAccountId,
+		CodeHash:         common.StringEncoder(normalizeRecoveryCode(code)),
+	})
+	var duplicate *repo.DuplicateKeyError
+	if errors.As(err, &duplicate) {
+		return "", errors.New("you already approved this recovery request")
+	}
+	if err != nil {
+		return "", errors.New("failed to approve recovery request")
+	}
+	return code, nil
+}
+
+// CompleteRecoveryEntity closes the request when enough current contacts vouched for it and returns the account.
+// Wrong codes return ErrRecoveryRejected, the caller must commit so the failed attempt is kept
+func (r RecoveryEntityImpl) CompleteRecoveryEntity(ctx context.Context, tx *sql.Tx, token string, codes []string) (int64, error) {
+	request, err := r.RecoveryRepository.FindRecoveryRequestByTokenHashFromDB(ctx, tx, common.StringEncoder(token))
+	if err != nil || request.Status != domain.RecoveryStatusPending || !request.ExpiresAt.After(time.Now()) {
+		return 0, errors.New("recovery request is no longer open")
+	}
+	if time.Now().Before(request.AvailableAt) {
+		return 0, fmt.Errorf("recovery can be completed after %s", common.FormatTimeByParam(request.AvailableAt))
+	}
+
+	contacts, err := r.RecoveryRepository.FindRecoveryContactsFromDB(ctx, tx, request.AccountID)
+	if err != nil {
+		return 0, errors.New("failed to find trusted contacts")
+	}
+	approvals, err := r.RecoveryRepository.FindRecoveryApprovalsFromDB(ctx, tx, request.RequestID)
+	if err != nil {
+		return 0, errors.New("failed to find recovery approvals")
+	}
+
+	var hashes []string
+	for _, code := range codes {
+		hashes = append(hashes, common.StringEncoder(normalizeRecoveryCode(code)))
+	}
+
+	// A contact removed since approving no longer counts
+	matched := 0
+	for _, approval := range approvals {
+		if !isRecoveryContact(contacts, approval.ContactAccountID) {
+			continue
+		}
+		for _, hash := range hashes {
+			if subtle.ConstantTimeCompare([]byte(hash), []byte(approval.CodeHash)) == 1 {
+				matched++
+				break
+			}
+		}
+	}
+
+	if matched < request.ApprovalsRequired {
+		attempts, err := r.RecoveryRepository.IncrementRecoveryFailedAttemptsToDB(ctx, tx, request.RequestID)
+		if err != nil {
+			return 0, errors.New("failed to update recovery request")
+		}
+		if attempts >= maxRecoveryFailures {
+			err = r.RecoveryRepository.CloseRecoveryRequestToDB(ctx, tx, request.RequestID, domain.RecoveryStatusCancelled)
+			if err != nil {
+				return 0, errors.New("failed to cancel recovery request")
+			}
+		}
+		return 0, ErrRecoveryRejected
+	}
+
+	err = r.RecoveryRepository.CloseRecoveryRequestToDB(ctx, tx, request.RequestID, domain.RecoveryStatusCompleted)
+	if err != nil {
+		return 0, err
+	}
+	// The other open requests for the account are void once it is recovered
+	err = r.RecoveryRepository.ClosePendingRecoveryRequestsToDB(ctx, tx, request.AccountID, domain.RecoveryStatusCancelled)
+	if err != nil {
+		return 0, errors.New("failed to cancel recovery requests")
+	}
+	return request.AccountID, nil
+}
+
+func (r RecoveryEntityImpl) CancelRecoveryEntity(ctx context.Context, tx *sql.Tx, accountId int64) error {
+	err := r.RecoveryRepository.ClosePendingRecoveryRequestsToDB(ctx, tx, accountId, domain.RecoveryStatusCancelled)
+	if err != nil {
+		return errors.New("failed to cancel recovery requests")
+	}
+	return nil
+}
+
+func (r RecoveryEntityImpl) withApprovals(ctx context.Context, tx *sql.Tx, records []record.RecoveryRequestRecord) ([]domain.RecoveryRequestDto, error) {
+	var requests []domain.RecoveryRequestDto
+	for _, rec := range records {
+		approvals, err := r.RecoveryRepository.FindRecoveryApprovalsFromDB(ctx, tx, rec.RequestID)
+		if err != nil {
+			return nil, errors.New("failed to find recovery approvals")
+		}
+		requests = append(requests, toRecoveryRequestDto(rec, approvals))
+	}
+	return requests, nil
+}
+
+func toRecoveryRequestDto(rec record.RecoveryRequestRecord, approvals []record.RecoveryApprovalRecord) domain.RecoveryRequestDto {
+	dto := domain.RecoveryRequestDto{
+		RequestID:         rec.RequestID,
+		AccountID:         rec.AccountID,
+		Status:            rec.Status,
+		ApprovalsRequired: rec.ApprovalsRequired,
+		CreatedAt:         rec.CreatedAt,
+		AvailableAt:       rec.AvailableAt,
+		ExpiresAt:         rec.ExpiresAt,
+	}
+	for _, approval := range approvals {
+		dto.ApproverAccountIDs = append(dto.ApproverAccountIDs, approval.ContactAccountID)
+	}
+	return dto
+}
+
+func isRecoveryContact(contacts []record.RecoveryContactRecord, accountId int64) bool {
+	for _, contact := range contacts {
+		if contact.ContactAccountID == accountId {
+			return true
+		}
+	}
+	return false
+}
+
+func randomSecret(size int) ([]byte, error) {
+	secret := make([]byte, size)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// formatRecoveryCode is 8 base32 characters in two groups, easy to read out over the phone
+func formatRecoveryCode(secret []byte) string {
+	code := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(secret)
+	return code[:recoveryCodeSeparation] + "-" + code[recoveryCodeSeparation:]
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
@@ -0,0 +1,14 @@
+package rewards
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/domain"
+)
+
+type RewardEntity interface {
+	RecordLoginEntity(ctx context.Context, tx *sql.Tx, accountId int64, timezone string) (domain.LoginStreakDto, error)
+	FindLoginStreakEntity(ctx context.Context, tx *sql.Tx, accountId int64) (domain.LoginStreakDto, error)
+	ClaimRewardEntity(ctx context.Context, tx *sql.Tx, accountId int64) (domain.LoginRewardDto, error)
+	ExtraLikesForStreak(streak int) int64
+}
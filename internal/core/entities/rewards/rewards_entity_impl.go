@@ -0,0 +1,129 @@
+package rewards
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/record"
+	"godating-dealls/internal/infra/mysql/repo"
+	"time"
+)
+
+const (
+	dayLayout = "2006-01-02"
+	// maxStreakReward streak rewards grow by one extra like per day up to this cap
+	maxStreakReward = 7
+)
+
+type RewardEntityImpl struct {
+	LoginStreaksRepository repo.LoginStreaksRepository
+}
+
+func NewRewardEntityImpl(loginStreaksRepository repo.LoginStreaksRepository) RewardEntity {
+	return &RewardEntityImpl{LoginStreaksRepository: loginStreaksRepository}
+}
+
+// RecordLoginEntity advances the streak once per local day, a missed day starts it over
+func (r RewardEntityImpl) RecordLoginEntity(ctx context.Context, tx *sql.Tx, accountId int64, timezone string) (domain.LoginStreakDto, error) {
+	streak, err := r.LoginStreaksRepository.FindLoginStreakFromDB(ctx, tx, accountId)
+	exists := err == nil
+	if !exists {
+		streak = record.LoginStreakRecord{AccountID: accountId, Timezone: "UTC"}
+	}
+	if timezone != "" {
+		if _, err := time.LoadLocation(timezone); err == nil {
+			streak.Timezone = timezone
+		}
+	}
+
+	now := time.Now().In(location(streak.Timezone))
+	today := now.Format(dayLayout)
+	yesterday := now.AddDate(0, 0, -1).Format(dayLayout)
+	lastLoginDay := streak.LastLoginDay.Format(dayLayout)
+
+	switch {
+	case exists && lastLoginDay == today:
+		// Already counted today
+	case exists && lastLoginDay == yesterday:
+		streak.CurrentStreak++
+	default:
+		streak.CurrentStreak = 1
+	}
+	if streak.CurrentStreak > streak.LongestStreak {
+		streak.LongestStreak = streak.CurrentStreak
+	}
+	streak.LastLoginDay, _ = time.Parse(dayLayout, today)
+
+	err = r.LoginStreaksRepository.UpsertLoginStreakToDB(ctx, tx, streak)
+	if err != nil {
+		return domain.LoginStreakDto{}, errors.New("failed to save login streak")
+	}
+	return toLoginStreakDto(streak), nil
+}
+
+func (r RewardEntityImpl) FindLoginStreakEntity(ctx context.Context, tx *sql.Tx, accountId int64) (domain.LoginStreakDto, error) {
+	streak, err := r.LoginStreaksRepository.FindLoginStreakFromDB(ctx, tx, accountId)
+	if err != nil {
+		return domain.LoginStreakDto{}, err
+	}
+	return toLoginStreakDto(streak), nil
+}
+
+func (r RewardEntityImpl) ClaimRewardEntity(ctx context.Context, tx *sql.Tx, accountId int64) (domain.LoginRewardDto, error) {
+	streak, err := r.LoginStreaksRepository.FindLoginStreakFromDB(ctx, tx, accountId)
+	if err != nil {
+		return domain.LoginRewardDto{}, errors.New("no login streak yet")
+	}
+
+	dto := toLoginStreakDto(streak)
+	if dto.LastLoginDay != dto.Today {
+		return domain.LoginRewardDto{}, errors.New("login today before claiming the reward")
+	}
+	if dto.ClaimedToday {
+		return domain.LoginRewardDto{}, errors.New("reward already claimed today")
+	}
+
+	claimedDay, _ := time.Parse(dayLayout, dto.Today)
+	err = r.LoginStreaksRepository.UpdateClaimedDayToDB(ctx, tx, accountId, claimedDay)
+	if err != nil {
+		return domain.LoginRewardDto{}, errors.New("failed to claim reward")
+	}
+
+	return domain.LoginRewardDto{
+		AccountID:     accountId,
+		CurrentStreak: streak.CurrentStreak,
+		ExtraLikes:    r.ExtraLikesForStreak(streak.CurrentStreak),
+	}, nil
+}
+
+func (r RewardEntityImpl) ExtraLikesForStreak(streak int) int64 {
+	if streak < 1 {
+		return 0
+	}
+	if streak > maxStreakReward {
+		return maxStreakReward
+	}
+	return int64(streak)
+}
+
+func toLoginStreakDto(streak record.LoginStreakRecord) domain.LoginStreakDto {
+	today := time.Now().In(location(streak.Timezone)).Format(dayLayout)
+	return domain.LoginStreakDto{
+		AccountID:     streak.AccountID,
+		Timezone:      streak.Timezone,
+		CurrentStreak: streak.CurrentStreak,
+		LongestStreak: streak.LongestStreak,
+		Today:         today,
+		LastLoginDay:  streak.LastLoginDay.Format(dayLayout),
+		ClaimedToday:  streak.LastClaimedDay != nil && streak.LastClaimedDay.Format(dayLayout) == today,
+	}
+}
+
+func location(timezone string) *time.Location {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
@@ -0,0 +1,15 @@
+package safety
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/domain"
+	"time"
+)
+
+type SafetyEntity interface {
+	FindSafetySettingsEntity(ctx context.Context, tx *sql.Tx, accountId int64) (domain.SafetySettingsDto, error)
+	SaveSafetySettingsEntity(ctx context.Context, tx *sql.Tx, dto domain.SafetySettingsDto) error
+	FirstConversationWindow() time.Duration
+	CheckFirstConversationMessageEntity(ctx context.Context, tx *sql.Tx, match domain.MatchDto, body string) error
+}
@@ -0,0 +1,100 @@
+package safety
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/record"
+	"godating-dealls/internal/infra/mysql/repo"
+	"regexp"
+	"time"
+)
+
+var (
+	// ErrMessageFiltered the message shares contact details or asks for something the late night mode does not allow
+	// in the first conversation of a match
+	ErrMessageFiltered = errors.New("message is not allowed this early in a conversation with late night mode")
+	// ErrMediaLocked links and media are only unlocked once the first conversation of a match is over
+	ErrMediaLocked = errors.New("links and media are locked this early in a conversation with late night mode")
+)
+
+var (
+	// Chat messages are text, pictures and files are shared as links
+	mediaPattern = regexp.MustCompile(`(?i)(https?://|www\.|data:)\S+|\b[a-z0-9-]+(\.[a-z0-9-]+)*\.(com|net|org|io|me|ly|gl|app|co|id|link|page|site|xyz)\b`)
+	// Contact details move the conversation somewhere the service cannot protect it
+	emailPattern  = regexp.MustCompile(`(?i)[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}`)
+	phonePattern  = regexp.MustCompile(`\+?\d(?:[\s().-]*\d){7,}`)
+	handlePattern = regexp.MustCompile(`(?i)(^|\s)@[a-z0-9_.]{3,}`)
+)
+
+type SafetyEntityImpl struct {
+	SafetySettingsRepository repo.SafetySettingsRepository
+	policy                   Policy
+	blockedTerms             []*regexp.Regexp
+}
+
+func NewSafetyEntityImpl(safetySettingsRepository repo.SafetySettingsRepository, policy Policy) SafetyEntity {
+	blockedTerms := make([]*regexp.Regexp, 0, len(policy.BlockedTerms))
+	for _, term := range policy.BlockedTerms {
+		blockedTerms = append(blockedTerms, regexp.MustCompile(`(?i)\b`+regexp.QuoteMeta(term)+`\b`))
+	}
+	return &SafetyEntityImpl{SafetySettingsRepository: safetySettingsRepository, policy: policy, blockedTerms: blockedTerms}
+}
+
+func (s SafetyEntityImpl) FindSafetySettingsEntity(ctx context.Context, tx *sql.Tx, accountId int64) (domain.SafetySettingsDto, error) {
+	setting, err := s.SafetySettingsRepository.FindSafetySettingFromDB(ctx, tx, accountId)
+	if err != nil {
+		return domain.SafetySettingsDto{}, errors.New("failed to find safety settings")
+	}
+	return domain.SafetySettingsDto{AccountID: setting.AccountID, LateNightMode: setting.LateNightMode, UpdatedAt: setting.UpdatedAt}, nil
+}
+
+func (s SafetyEntityImpl) SaveSafetySettingsEntity(ctx context.Context, tx *sql.Tx, dto domain.SafetySettingsDto) error {
+	err := s.SafetySettingsRepository.UpsertSafetySettingToDB(ctx, tx, record.SafetySettingRecord{
+		AccountID:     dto.AccountID,
+		LateNightMode: dto.LateNightMode,
+	})
+	if err != nil {
+		return errors.New("failed to save safety settings")
+	}
+	return nil
+}
+
+// FirstConversationWindow is how long after the match the late night mode restricts the conversation
+func (s SafetyEntityImpl) FirstConversationWindow() time.Duration {
+	return s.policy.FirstConversation
+}
+
+// CheckFirstConversationMessageEntity filters a message of a match younger than the first conversation window when
+// one of the participants turned the late night mode on, the sender does not learn which one did. Links and media
+// unlock when the window ends, contact details and the blocked terms are refused until then
+func (s SafetyEntityImpl) CheckFirstConversationMessageEntity(ctx context.Context, tx *sql.Tx, match domain.MatchDto, body string) error {
+	unlockAt := match.CreatedAt.Add(s.policy.FirstConversation)
+	if !time.Now().Before(unlockAt) {
+		return nil
+	}
+
+	count, err := s.SafetySettingsRepository.CountLateNightModeAccountsFromDB(ctx, tx, match.ParticipantAccountIDs)
+	if err != nil {
+		return errors.New("failed to find safety settings")
+	}
+	if count == 0 {
+		return nil
+	}
+
+	switch {
+	case emailPattern.MatchString(body), phonePattern.MatchString(body), handlePattern.MatchString(body):
+		return fmt.Errorf("%w: contact details can be shared from %s", ErrMessageFiltered, common.FormatTimeByParam(unlockAt))
+	case mediaPattern.MatchString(body):
+		return fmt.Errorf("%w: they unlock at %s", ErrMediaLocked, common.FormatTimeByParam(unlockAt))
+	}
+	for _, term := range s.blockedTerms {
+		if term.MatchString(body) {
+			return fmt.Errorf("%w: the message asks for money or explicit content", ErrMessageFiltered)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package safety
+
+import (
+	"strings"
+	"time"
+)
+
+const DefaultFirstConversationHours = 24
+
+// defaultBlockedTerms are asked for early by the scams and the harassment the late night mode is there for
+var defaultBlockedTerms = []string{
+	"nudes", "nude pic", "sexting", "send pics", "onlyfans", "sugar daddy", "sugar baby",
+	"cashapp", "cash app", "venmo", "paypal", "western union", "gift card", "bitcoin", "crypto",
+}
+
+// Policy holds how long the first conversation of a match stays restricted and the terms it must not contain
+type Policy struct {
+	FirstConversation time.Duration `validate:"gt=0"`
+	BlockedTerms      []string
+}
+
+// NewPolicy blocks the default terms and the extra ones, SAFETY_BLOCKED_TERMS
+func NewPolicy(firstConversation time.Duration, extraTerms []string) Policy {
+	policy := Policy{
+		FirstConversation: firstConversation,
+		BlockedTerms:      append([]string(nil), defaultBlockedTerms...),
+	}
+	for _, term := range extraTerms {
+		if term = strings.ToLower(strings.TrimSpace(term)); term != "" {
+			policy.BlockedTerms = append(policy.BlockedTerms, term)
+		}
+	}
+	return policy
+}
@@ -0,0 +1,14 @@
+package share_links
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/domain"
+)
+
+type ShareLinkEntity interface {
+	CreateShareLinkEntity(ctx context.Context, tx *sql.Tx, dto domain.ShareLinkDto) (domain.ShareLinkDto, error)
+	FindShareLinksEntity(ctx context.Context, tx *sql.Tx, accountId int64) ([]domain.ShareLinkDto, error)
+	RevokeShareLinkEntity(ctx context.Context, tx *sql.Tx, accountId int64, linkId int64) error
+	ResolveShareLinkEntity(ctx context.Context, tx *sql.Tx, linkId int64) (domain.ShareLinkDto, error)
+}
@@ -0,0 +1,98 @@
+package share_links
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/record"
+	"godating-dealls/internal/infra/mysql/repo"
+	"time"
+)
+
+const defaultShareLinkExpiresInHours = 72
+
+type ShareLinkEntityImpl struct {
+	ProfileShareLinksRepository repo.ProfileShareLinksRepository
+	Validate                    *validator.Validate
+}
+
+func NewShareLinkEntityImpl(profileShareLinksRepository repo.ProfileShareLinksRepository, validate *validator.Validate) ShareLinkEntity {
+	return &ShareLinkEntityImpl{ProfileShareLinksRepository: profileShareLinksRepository, Validate: validate}
+}
+
+func (s ShareLinkEntityImpl) CreateShareLinkEntity(ctx context.Context, tx *sql.Tx, dto domain.ShareLinkDto) (domain.ShareLinkDto, error) {
+	if dto.ExpiresInHours == 0 {
+		dto.ExpiresInHours = defaultShareLinkExpiresInHours
+	}
+
+	err := s.Validate.Struct(dto)
+	if err != nil {
+		return domain.ShareLinkDto{}, err
+	}
+
+	// Truncated to seconds so the signed token can be rebuilt from the stored expiry
+	dto.CreatedAt = time.Now().Truncate(time.Second)
+	dto.ExpiresAt = dto.CreatedAt.Add(time.Duration(dto.ExpiresInHours) * time.Hour)
+
+	rec, err := s.ProfileShareLinksRepository.InsertShareLinkToDB(ctx, tx, record.ProfileShareLinkRecord{
+		AccountID: dto.AccountID,
+		ExpiresAt: dto.ExpiresAt,
+	})
+	if err != nil {
+		return domain.ShareLinkDto{}, errors.New("failed to create share link")
+	}
+
+	dto.LinkID = rec.LinkID
+	return dto, nil
+}
+
+func (s ShareLinkEntityImpl) FindShareLinksEntity(ctx context.Context, tx *sql.Tx, accountId int64) ([]domain.ShareLinkDto, error) {
+	records, err := s.ProfileShareLinksRepository.FindShareLinksByAccountIdFromDB(ctx, tx, accountId)
+	if err != nil {
+		return nil, errors.New("failed to find share links")
+	}
+
+	var links []domain.ShareLinkDto
+	for _, rec := range records {
+		links = append(links, toShareLinkDto(rec))
+	}
+	return links, nil
+}
+
+func (s ShareLinkEntityImpl) RevokeShareLinkEntity(ctx context.Context, tx *sql.Tx, accountId int64, linkId int64) error {
+	return s.ProfileShareLinksRepository.RevokeShareLinkToDB(ctx, tx, accountId, linkId)
+}
+
+// ResolveShareLinkEntity returns the link only while it is usable and counts the view
+func (s ShareLinkEntityImpl) ResolveShareLinkEntity(ctx context.Context, tx *sql.Tx, linkId int64) (domain.ShareLinkDto, error) {
+	rec, err := s.ProfileShareLinksRepository.FindShareLinkByIdFromDB(ctx, tx, linkId)
+	if err != nil {
+		return domain.ShareLinkDto{}, err
+	}
+
+	if rec.RevokedAt != nil || rec.ExpiresAt.Before(time.Now()) {
+		return domain.ShareLinkDto{}, errors.New("share link is no longer available")
+	}
+
+	err = s.ProfileShareLinksRepository.IncrementShareLinkViewToDB(ctx, tx, linkId)
+	if err != nil {
+		return domain.ShareLinkDto{}, errors.New("failed to count share link view")
+	}
+
+	link := toShareLinkDto(rec)
+	link.ViewCount++
+	return link, nil
+}
+
+func toShareLinkDto(rec record.ProfileShareLinkRecord) domain.ShareLinkDto {
+	return domain.ShareLinkDto{
+		LinkID:    rec.LinkID,
+		AccountID: rec.AccountID,
+		ExpiresAt: rec.ExpiresAt,
+		RevokedAt: rec.RevokedAt,
+		ViewCount: rec.ViewCount,
+		CreatedAt: rec.CreatedAt,
+	}
+}
@@ -0,0 +1,15 @@
+package status_messages
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/domain"
+	"time"
+)
+
+type StatusMessageEntity interface {
+	SaveStatusMessageEntity(ctx context.Context, tx *sql.Tx, dto domain.StatusMessageDto) (domain.StatusMessageDto, error)
+	DeleteStatusMessageEntity(ctx context.Context, tx *sql.Tx, messageId int64) error
+	FindStatusMessagesEntity(ctx context.Context, tx *sql.Tx) ([]domain.StatusMessageDto, error)
+	FindUnendedStatusMessagesEntity(ctx context.Context, tx *sql.Tx, now time.Time) ([]domain.StatusMessageDto, error)
+}
@@ -0,0 +1,121 @@
+package status_messages
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/record"
+	"godating-dealls/internal/infra/mysql/repo"
+	"time"
+)
+
+var (
+	ErrInvalidStatusMessage  = errors.New("invalid status message")
+	ErrStatusMessageNotFound = errors.New("status message not found")
+)
+
+type StatusMessageEntityImpl struct {
+	StatusMessagesRepository repo.StatusMessagesRepository
+	validate                 *validator.Validate
+}
+
+func NewStatusMessageEntityImpl(statusMessagesRepository repo.StatusMessagesRepository, validate *validator.Validate) StatusMessageEntity {
+	return &StatusMessageEntityImpl{StatusMessagesRepository: statusMessagesRepository, validate: validate}
+}
+
+// SaveStatusMessageEntity creates the message without a MessageID and replaces the existing one otherwise
+func (s StatusMessageEntityImpl) SaveStatusMessageEntity(ctx context.Context, tx *sql.Tx, dto domain.StatusMessageDto) (domain.StatusMessageDto, error) {
+	if err := s.validate.Struct(dto); err != nil {
+		return domain.StatusMessageDto{}, fmt.Errorf("%w: %v", ErrInvalidStatusMessage, err)
+	}
+	if dto.EndsAt != nil && !dto.EndsAt.After(dto.StartsAt) {
+		return domain.StatusMessageDto{}, fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidStatusMessage)
+	}
+
+	message := record.StatusMessageRecord{
+		MessageID: dto.MessageID,
+		Severity:  dto.Severity,
+		Title:     dto.Title,
+		Message:   dto.Message,
+		StartsAt:  dto.StartsAt,
+		EndsAt:    dto.EndsAt,
+	}
+
+	if dto.MessageID == 0 {
+		saved, err := s.StatusMessagesRepository.InsertStatusMessageToDB(ctx, tx, message)
+		if err != nil {
+			return domain.StatusMessageDto{}, errors.New("failed to save status message")
+		}
+		return toStatusMessageDto(saved), nil
+	}
+
+	if _, err := s.findStatusMessage(ctx, tx, dto.MessageID); err != nil {
+		return domain.StatusMessageDto{}, err
+	}
+	if err := s.StatusMessagesRepository.UpdateStatusMessageToDB(ctx, tx, message); err != nil {
+		return domain.StatusMessageDto{}, errors.New("failed to save status message")
+	}
+	return s.findStatusMessage(ctx, tx, dto.MessageID)
+}
+
+func (s StatusMessageEntityImpl) DeleteStatusMessageEntity(ctx context.Context, tx *sql.Tx, messageId int64) error {
+	deleted, err := s.StatusMessagesRepository.DeleteStatusMessageToDB(ctx, tx, messageId)
+	if err != nil {
+		return errors.New("failed to delete status message")
+	}
+	if !deleted {
+		return ErrStatusMessageNotFound
+	}
+	return nil
+}
+
+func (s StatusMessageEntityImpl) FindStatusMessagesEntity(ctx context.Context, tx *sql.Tx) ([]domain.StatusMessageDto, error) {
+	messages, err := s.StatusMessagesRepository.FindStatusMessagesFromDB(ctx, tx)
+	if err != nil {
+		return nil, errors.New("failed to find status messages")
+	}
+	return toStatusMessageDtos(messages), nil
+}
+
+func (s StatusMessageEntityImpl) FindUnendedStatusMessagesEntity(ctx context.Context, tx *sql.Tx, now time.Time) ([]domain.StatusMessageDto, error) {
+	messages, err := s.StatusMessagesRepository.FindUnendedStatusMessagesFromDB(ctx, tx, now)
+	if err != nil {
+		return nil, errors.New("failed to find status messages")
+	}
+	return toStatusMessageDtos(messages), nil
+}
+
+func (s StatusMessageEntityImpl) findStatusMessage(ctx context.Context, tx *sql.Tx, messageId int64) (domain.StatusMessageDto, error) {
+	message, err := s.StatusMessagesRepository.FindStatusMessageByIdFromDB(ctx, tx, messageId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.StatusMessageDto{}, ErrStatusMessageNotFound
+		}
+		return domain.StatusMessageDto{}, errors.New("failed to find status message")
+	}
+	return toStatusMessageDto(message), nil
+}
+
+func toStatusMessageDtos(messages []record.StatusMessageRecord) []domain.StatusMessageDto {
+	var res []domain.StatusMessageDto
+	for _, message := range messages {
+		res = append(res, toStatusMessageDto(message))
+	}
+	return res
+}
+
+func toStatusMessageDto(message record.StatusMessageRecord) domain.StatusMessageDto {
+	return domain.StatusMessageDto{
+		MessageID: message.MessageID,
+		Severity:  message.Severity,
+		Title:     message.Title,
+		Message:   message.Message,
+		StartsAt:  message.StartsAt,
+		EndsAt:    message.EndsAt,
+		CreatedAt: message.CreatedAt,
+		UpdatedAt: message.UpdatedAt,
+	}
+}
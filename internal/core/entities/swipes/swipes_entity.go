@@ -7,6 +7,10 @@ import (
 )
 
 type SwipeEntity interface {
-	InsertSwipeActionEntity(ctx context.Context, tx *sql.Tx, accountId int64, userId int64, action string, accountIdSwipe int64) error
+	InsertSwipeActionEntity(ctx context.Context, tx *sql.Tx, accountId int64, userId int64, action string, accountIdSwipe int64) (int64, error)
+	RememberLastSwipeEntity(ctx context.Context, tx *sql.Tx, dto domain.LastSwipeDto) error
+	UndoLastSwipeEntity(ctx context.Context, tx *sql.Tx, accountId int64) (domain.LastSwipeDto, error)
 	FindTotalSwipeActionEntity(ctx context.Context, tx *sql.Tx, accountIdSwipe int64) (domain.TotalSwipeAction, error)
+	InsertSuperLikeEntity(ctx context.Context, tx *sql.Tx, accountId int64, userId int64, targetAccountId int64) (int64, error)
+	FindLikesReceivedEntity(ctx context.Context, tx *sql.Tx, accountId int64, page int, size int) ([]domain.LikeReceivedDto, int64, error)
 }
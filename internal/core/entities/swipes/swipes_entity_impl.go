@@ -7,6 +7,18 @@ import (
 	"godating-dealls/internal/domain"
 	"godating-dealls/internal/infra/mysql/record"
 	"godating-dealls/internal/infra/mysql/repo"
+	"time"
+)
+
+// SwipeUndoWindow is how long after a swipe it can still be undone
+const SwipeUndoWindow = 5 * time.Minute
+
+var (
+	ErrNothingToUndo     = errors.New("no swipe to undo, only the last swipe of the last 5 minutes can be undone")
+	ErrSwipeUndoMatch    = errors.New("this like made a match and cannot be undone")
+	ErrInvalidSuperLike  = errors.New("invalid super like, an account cannot super like itself")
+	ErrInvalidSwipe      = errors.New("invalid swipe, an account cannot swipe itself")
+	ErrAlreadySuperLiked = errors.New("this account was already super liked")
 )
 
 type SwipeEntityImpl struct {
@@ -17,7 +29,7 @@ func NewSwipeEntityImpl(swipesRepository repo.SwipesRepository) SwipeEntity {
 	return &SwipeEntityImpl{SwipesRepository: swipesRepository}
 }
 
-func (s SwipeEntityImpl) InsertSwipeActionEntity(ctx context.Context, tx *sql.Tx, accountId int64, userId int64, action string, accountIdSwipe int64) error {
+func (s SwipeEntityImpl) InsertSwipeActionEntity(ctx context.Context, tx *sql.Tx, accountId int64, userId int64, action string, accountIdSwipe int64) (int64, error) {
 	var actionType string
 	if action == "left" {
 		actionType = "PASSED"
@@ -25,18 +37,64 @@ func (s SwipeEntityImpl) InsertSwipeActionEntity(ctx context.Context, tx *sql.Tx
 		actionType = "LIKED"
 	}
 
-	err := s.SwipesRepository.InsertSwipesToDB(ctx, tx, record.SwipeRecord{
+	return s.SwipesRepository.InsertSwipesToDB(ctx, tx, record.SwipeRecord{
 		AccountID:      accountId,
 		UserID:         userId,
 		Action:         actionType,
 		AccountIDSwipe: accountIdSwipe,
 	})
+}
+
+// RememberLastSwipeEntity replaces the swipe the account can undo
+func (s SwipeEntityImpl) RememberLastSwipeEntity(ctx context.Context, tx *sql.Tx, dto domain.LastSwipeDto) error {
+	err := s.SwipesRepository.UpsertLastSwipeToDB(ctx, tx, record.LastSwipeRecord{
+		AccountID:      dto.AccountID,
+		SwipeID:        dto.SwipeID,
+		AccountIDSwipe: dto.AccountIDSwipe,
+		Action:         dto.Action,
+		QuotaUsed:      dto.QuotaUsed,
+		MatchID:        dto.MatchID,
+	})
 	if err != nil {
-		return err
+		return errors.New("failed to remember last swipe")
 	}
 	return nil
 }
 
+// UndoLastSwipeEntity deletes the last swipe of the account when it is recent enough, a swipe can be undone once.
+// A like that made a match stays, the other account already saw the match
+func (s SwipeEntityImpl) UndoLastSwipeEntity(ctx context.Context, tx *sql.Tx, accountId int64) (domain.LastSwipeDto, error) {
+	lastSwipe, err := s.SwipesRepository.FindLastSwipeFromDB(ctx, tx, accountId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.LastSwipeDto{}, ErrNothingToUndo
+		}
+		return domain.LastSwipeDto{}, errors.New("failed to find last swipe")
+	}
+	if time.Since(lastSwipe.SwipedAt) > SwipeUndoWindow {
+		return domain.LastSwipeDto{}, ErrNothingToUndo
+	}
+	if lastSwipe.MatchID != nil {
+		return domain.LastSwipeDto{}, ErrSwipeUndoMatch
+	}
+
+	if err := s.SwipesRepository.DeleteSwipeFromDB(ctx, tx, accountId, lastSwipe.SwipeID); err != nil {
+		return domain.LastSwipeDto{}, errors.New("failed to delete swipe")
+	}
+	if err := s.SwipesRepository.DeleteLastSwipeFromDB(ctx, tx, accountId); err != nil {
+		return domain.LastSwipeDto{}, errors.New("failed to delete last swipe")
+	}
+
+	return domain.LastSwipeDto{
+		AccountID:      lastSwipe.AccountID,
+		SwipeID:        lastSwipe.SwipeID,
+		AccountIDSwipe: lastSwipe.AccountIDSwipe,
+		Action:         lastSwipe.Action,
+		QuotaUsed:      lastSwipe.QuotaUsed,
+		SwipeDate:      lastSwipe.SwipeDate,
+	}, nil
+}
+
 func (s SwipeEntityImpl) FindTotalSwipeActionEntity(ctx context.Context, tx *sql.Tx, accountIdSwipe int64) (domain.TotalSwipeAction, error) {
 	swipeTotal, err := s.SwipesRepository.FindTotalSwipes(ctx, tx, accountIdSwipe)
 	if err != nil {
@@ -54,3 +112,52 @@ func (s SwipeEntityImpl) FindTotalSwipeActionEntity(ctx context.Context, tx *sql
 
 	return res, nil
 }
+
+// InsertSuperLikeEntity stores the super like with the LIKED swipe it is, an account super likes another once
+func (s SwipeEntityImpl) InsertSuperLikeEntity(ctx context.Context, tx *sql.Tx, accountId int64, userId int64, targetAccountId int64) (int64, error) {
+	if targetAccountId <= 0 || targetAccountId == accountId {
+		return 0, ErrInvalidSuperLike
+	}
+
+	swipeId, err := s.InsertSwipeActionEntity(ctx, tx, accountId, userId, "right", targetAccountId)
+	if err != nil {
+		return 0, errors.New("failed to insert swipe action entity")
+	}
+
+	err = s.SwipesRepository.InsertSuperLikeToDB(ctx, tx, record.SuperLikeRecord{
+		AccountID:       accountId,
+		TargetAccountID: targetAccountId,
+		SwipeID:         swipeId,
+	})
+	var duplicate *repo.DuplicateKeyError
+	if errors.As(err, &duplicate) {
+		return 0, ErrAlreadySuperLiked
+	}
+	if err != nil {
+		return 0, errors.New("failed to insert super like")
+	}
+	return swipeId, nil
+}
+
+// FindLikesReceivedEntity returns a page of the accounts that liked the account and are still waiting for its swipe
+func (s SwipeEntityImpl) FindLikesReceivedEntity(ctx context.Context, tx *sql.Tx, accountId int64, page int, size int) ([]domain.LikeReceivedDto, int64, error) {
+	total, err := s.SwipesRepository.CountLikesReceivedFromDB(ctx, tx, accountId)
+	if err != nil {
+		return nil, 0, errors.New("failed to count likes received")
+	}
+
+	records, err := s.SwipesRepository.FindLikesReceivedFromDB(ctx, tx, accountId, size, (page-1)*size)
+	if err != nil {
+		return nil, 0, errors.New("failed to find likes received")
+	}
+
+	res := make([]domain.LikeReceivedDto, 0, len(records))
+	for _, rec := range records {
+		res = append(res, domain.LikeReceivedDto{
+			AccountID:  rec.AccountID,
+			LikedOn:    rec.LikedOn,
+			SuperLiked: rec.SuperLiked,
+		})
+	}
+	return res, total, nil
+}
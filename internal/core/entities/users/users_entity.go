@@ -10,7 +10,10 @@ type UserEntity interface {
 	SaveUserEntities(ctx context.Context, tx *sql.Tx, dto domain.UserDto) error
 	FindUserEntities(ctx context.Context, tx *sql.Tx, accountId int64) (domain.Users, error)
 	FindAllUserEntities(ctx context.Context, tx *sql.Tx) ([]domain.AllUsers, error)
-	FindAllUserViewsEntities(ctx context.Context, tx *sql.Tx, verified bool, shouldNext bool, accountIdIdentifier int64) ([]domain.AllUserViews, error)
+	FindAllUserViewsEntities(ctx context.Context, tx *sql.Tx, verified bool, shouldNext bool, accountIdIdentifier int64, radiusKm int) ([]domain.AllUserViews, error)
 	FindUserDetailEntity(ctx context.Context, tx *sql.Tx, accountId int64) (domain.Users, error)
+	FindUserDetailsEntity(ctx context.Context, tx *sql.Tx, accountIds []int64) (map[int64]domain.Users, error)
 	UpdateUserEntities(ctx context.Context, tx *sql.Tx, dto domain.PatchUser) (domain.PatchUserDto, error)
+	UpdateUserLocationEntity(ctx context.Context, tx *sql.Tx, accountId int64, dto domain.UserLocationDto) error
+	ClearUserLocationEntity(ctx context.Context, tx *sql.Tx, accountId int64) error
 }
@@ -13,6 +13,13 @@ import (
 	"time"
 )
 
+var (
+	ErrInvalidLocation = errors.New("invalid location")
+
+	userFromRecord     = common.NewMapping(domain.Users{}, record.UserRecord{}, "PhoneHash", "Latitude", "Longitude")
+	userViewFromRecord = common.NewMapping(domain.AllUserViews{}, record.UserAccountRecord{}, "DateOfBirth", "PhoneHash", "Latitude", "Longitude", "CreatedAt", "UpdatedAt")
+)
+
 type UserEntityImpl struct {
 	repository repository.UserRepository
 	validate   *validator.Validate
@@ -35,15 +42,17 @@ func (u UserEntityImpl) SaveUserEntities(ctx context.Context, tx *sql.Tx, dto do
 		return errors.New("users already exists")
 	}
 
-	// Set default user data
+	// Sign up only sets the name, an imported profile comes with the rest
 	records := record.UserRecord{
-		AccountID:   dto.AccountID,
-		DateOfBirth: nil,
-		Age:         0,
-		Gender:      "",
-		Address:     "",
-		Bio:         "",
-		FullName:    dto.FullName,
+		AccountID: dto.AccountID,
+		Age:       calculateAge(dto.DateOfBirth),
+		Gender:    dto.Gender,
+		Address:   dto.Address,
+		Bio:       dto.Bio,
+		FullName:  dto.FullName,
+	}
+	if !dto.DateOfBirth.IsZero() {
+		records.DateOfBirth = &dto.DateOfBirth
 	}
 	common.PrintJSON("user entities | user record to be saved", records)
 
@@ -86,16 +95,16 @@ func (u UserEntityImpl) FindAllUserEntities(ctx context.Context, tx *sql.Tx) ([]
 	return allUser, nil
 }
 
-func (u UserEntityImpl) FindAllUserViewsEntities(ctx context.Context, tx *sql.Tx, verified bool, shouldNext bool, accountIdIdentifier int64) ([]domain.AllUserViews, error) {
+func (u UserEntityImpl) FindAllUserViewsEntities(ctx context.Context, tx *sql.Tx, verified bool, shouldNext bool, accountIdIdentifier int64, radiusKm int) ([]domain.AllUserViews, error) {
 	var allUsers []record.UserAccountRecord
 	if shouldNext {
-		allUsers1, err := u.repository.GetAllUsersViewsFromDB(ctx, verified, accountIdIdentifier, tx)
+		allUsers1, err := u.repository.GetAllUsersViewsFromDB(ctx, verified, accountIdIdentifier, radiusKm, tx)
 		if err != nil {
 			return nil, errors.New("could not get all users")
 		}
 		allUsers = append(allUsers, allUsers1...)
 	} else {
-		allUsers2, err := u.repository.GetAllUsersNextViewsFromDB(ctx, verified, accountIdIdentifier, tx)
+		allUsers2, err := u.repository.GetAllUsersNextViewsFromDB(ctx, verified, accountIdIdentifier, radiusKm, tx)
 		if err != nil {
 			return nil, errors.New("could not get all users")
 		}
@@ -104,16 +113,8 @@ func (u UserEntityImpl) FindAllUserViewsEntities(ctx context.Context, tx *sql.Tx
 
 	var allUser []domain.AllUserViews
 	for _, user := range allUsers {
-		usr := domain.AllUserViews{
-			UserID:    user.UserID,
-			AccountID: user.AccountID,
-			Username:  user.Username,
-			FullName:  user.FullName,
-			Gender:    user.Gender,
-			Age:       user.Age,
-			Bio:       user.Bio,
-			Verified:  user.Verified,
-		}
+		var usr domain.AllUserViews
+		userViewFromRecord.Map(&usr, user)
 
 		allUser = append(allUser, usr)
 	}
@@ -127,20 +128,28 @@ func (u UserEntityImpl) FindUserDetailEntity(ctx context.Context, tx *sql.Tx, ac
 		return domain.Users{}, err
 	}
 
-	usr := domain.Users{
-		UserID:      user.UserID,
-		AccountID:   user.AccountID,
-		FullName:    user.FullName,
-		DateOfBirth: user.DateOfBirth,
-		Age:         user.Age,
-		Gender:      user.Gender,
-		Address:     user.Address,
-		Bio:         user.Bio,
-	}
+	var usr domain.Users
+	userFromRecord.Map(&usr, user)
 
 	return usr, nil
 }
 
+// FindUserDetailsEntity returns the users of the accounts by account id, the accounts without a user are missing
+func (u UserEntityImpl) FindUserDetailsEntity(ctx context.Context, tx *sql.Tx, accountIds []int64) (map[int64]domain.Users, error) {
+	records, err := u.repository.GetUsersByAccountIdsFromDB(ctx, tx, accountIds)
+	if err != nil {
+		return nil, errors.New("failed to find users")
+	}
+
+	users := make(map[int64]domain.Users, len(records))
+	for _, rec := range records {
+		var usr domain.Users
+		userFromRecord.Map(&usr, rec)
+		users[rec.AccountID] = usr
+	}
+	return users, nil
+}
+
 func (u UserEntityImpl) UpdateUserEntities(ctx context.Context, tx *sql.Tx, dto domain.PatchUser) (domain.PatchUserDto, error) {
 	err := u.validate.Struct(dto)
 	if err != nil {
@@ -158,6 +167,12 @@ func (u UserEntityImpl) UpdateUserEntities(ctx context.Context, tx *sql.Tx, dto
 		Age:         calculateAge(dateOfBirth),
 	}
 
+	// Only the hash of the phone number is stored, it is used to match uploaded contact hashes
+	if dto.PhoneNumber != nil && *dto.PhoneNumber != "" {
+		phoneHash := common.PhoneNumberEncoder(*dto.PhoneNumber)
+		rec.PhoneHash = &phoneHash
+	}
+
 	user, err := u.repository.UpdateUserToDB(ctx, tx, rec)
 	if err != nil {
 		return domain.PatchUserDto{}, errors.New("could not update user")
@@ -178,6 +193,30 @@ func (u UserEntityImpl) UpdateUserEntities(ctx context.Context, tx *sql.Tx, dto
 	return patchUserDto, nil
 }
 
+func (u UserEntityImpl) UpdateUserLocationEntity(ctx context.Context, tx *sql.Tx, accountId int64, dto domain.UserLocationDto) error {
+	if err := u.validate.Struct(dto); err != nil {
+		return fmt.Errorf("%w: latitude must be within -90 and 90 and longitude within -180 and 180", ErrInvalidLocation)
+	}
+
+	if _, err := u.repository.GetUserByAccountIdFromDB(ctx, tx, accountId); err != nil {
+		return errors.New("failed to find user")
+	}
+
+	if err := u.repository.UpdateUserLocationToDB(ctx, tx, accountId, dto.Latitude, dto.Longitude); err != nil {
+		return errors.New("could not update user location")
+	}
+	return nil
+}
+
+// ClearUserLocationEntity removes the location, the account then sees candidates at any distance and is left out
+// for viewers who set a location
+func (u UserEntityImpl) ClearUserLocationEntity(ctx context.Context, tx *sql.Tx, accountId int64) error {
+	if err := u.repository.UpdateUserLocationToDB(ctx, tx, accountId, nil, nil); err != nil {
+		return errors.New("could not clear user location")
+	}
+	return nil
+}
+
 func calculateAge(dateOfBirth time.Time) int {
 	if dateOfBirth.IsZero() {
 		return 0
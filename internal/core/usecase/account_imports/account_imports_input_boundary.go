@@ -0,0 +1,10 @@
+package account_imports
+
+import (
+	"context"
+	"godating-dealls/internal/domain"
+)
+
+type InputAccountImportBoundary interface {
+	ExecuteImportAccounts(ctx context.Context, request domain.AccountImportRequest, progress func(domain.AccountImportResponse), boundary OutputAccountImportBoundary) error
+}
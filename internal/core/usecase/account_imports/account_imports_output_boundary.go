@@ -0,0 +1,7 @@
+package account_imports
+
+import "godating-dealls/internal/domain"
+
+type OutputAccountImportBoundary interface {
+	AccountImportResponse(response domain.AccountImportResponse, err error)
+}
@@ -0,0 +1,108 @@
+package account_imports
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/domain"
+	"io"
+	"strings"
+)
+
+// ErrInvalidImportFile is wrapped with where the dump could not be read, nothing of it is imported
+var ErrInvalidImportFile = errors.New("invalid import file")
+
+// importColumns are the columns a CSV dump may have, named like the JSON fields of domain.AccountImportRow
+var importColumns = map[string]func(row *domain.AccountImportRow, value string) error{
+	"username":      func(row *domain.AccountImportRow, value string) error { row.Username = value; return nil },
+	"email":         func(row *domain.AccountImportRow, value string) error { row.Email = value; return nil },
+	"password_hash": func(row *domain.AccountImportRow, value string) error { row.PasswordHash = value; return nil },
+	"reset_password": func(row *domain.AccountImportRow, value string) (err error) {
+		row.ResetPassword, err = parseImportBool(value)
+		return err
+	},
+	"verified": func(row *domain.AccountImportRow, value string) (err error) {
+		row.Verified, err = parseImportBool(value)
+		return err
+	},
+	"full_name":     func(row *domain.AccountImportRow, value string) error { row.FullName = value; return nil },
+	"date_of_birth": func(row *domain.AccountImportRow, value string) error { row.DateOfBirth = value; return nil },
+	"gender":        func(row *domain.AccountImportRow, value string) error { row.Gender = value; return nil },
+	"address":       func(row *domain.AccountImportRow, value string) error { row.Address = value; return nil },
+	"bio":           func(row *domain.AccountImportRow, value string) error { row.Bio = value; return nil },
+}
+
+// ReadAccountImport parses a dump in format csv or json. A CSV dump starts with a header naming its columns in any
+// order, username and email are required. A JSON dump is an array of rows
+func ReadAccountImport(r io.Reader, format string) ([]domain.AccountImportRow, error) {
+	switch strings.ToLower(format) {
+	case "csv":
+		return readImportCSV(r)
+	case "json":
+		var rows []domain.AccountImportRow
+		decoder := json.NewDecoder(r)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&rows); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+		}
+		return rows, nil
+	}
+	return nil, fmt.Errorf("%w: format must be csv or json", ErrInvalidImportFile)
+}
+
+func readImportCSV(r io.Reader) ([]domain.AccountImportRow, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not read the header: %v", ErrInvalidImportFile, err)
+	}
+
+	setters := make([]func(row *domain.AccountImportRow, value string) error, len(header))
+	seen := make(map[string]bool, len(header))
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		setter, ok := importColumns[column]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidImportFile, column)
+		}
+		if seen[column] {
+			return nil, fmt.Errorf("%w: column %q is repeated", ErrInvalidImportFile, column)
+		}
+		seen[column] = true
+		setters[i] = setter
+	}
+	if !seen["username"] || !seen["email"] {
+		return nil, fmt.Errorf("%w: the username and email columns are required", ErrInvalidImportFile)
+	}
+
+	var rows []domain.AccountImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+		}
+
+		var row domain.AccountImportRow
+		for i, value := range record {
+			if err := setters[i](&row, value); err != nil {
+				return nil, fmt.Errorf("%w: row %d, %s: %v", ErrInvalidImportFile, len(rows)+1, header[i], err)
+			}
+		}
+		rows = append(rows, row)
+	}
+}
+
+// parseImportBool takes what exports usually write for a flag, an empty value is false
+func parseImportBool(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "0", "false", "no", "n":
+		return false, nil
+	case "1", "true", "yes", "y":
+		return true, nil
+	}
+	return false, fmt.Errorf("%q is not true or false", value)
+}
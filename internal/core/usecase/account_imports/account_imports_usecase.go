@@ -0,0 +1,171 @@
+package account_imports
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/accounts"
+	"godating-dealls/internal/core/entities/outbox"
+	"godating-dealls/internal/core/entities/users"
+	"godating-dealls/internal/domain"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxImportRows is the most rows the import api takes in one request, the import command has no limit
+	MaxImportRows = 10000
+	// progressEvery rows the progress of a running import is reported
+	progressEvery = 500
+)
+
+// Row outcomes listed in the rejected rows of an import
+const (
+	importStatusExisting  = "existing"
+	importStatusDuplicate = "duplicate"
+	importStatusInvalid   = "invalid"
+	importStatusFailed    = "failed"
+)
+
+type AccountImportUsecase struct {
+	DB            *sql.DB
+	AccountEntity accounts.AccountEntity
+	UserEntity    users.UserEntity
+	OutboxEntity  outbox.OutboxEntity
+}
+
+func NewAccountImportUsecase(db *sql.DB, accountEntity accounts.AccountEntity, userEntity users.UserEntity, outboxEntity outbox.OutboxEntity) InputAccountImportBoundary {
+	return &AccountImportUsecase{DB: db, AccountEntity: accountEntity, UserEntity: userEntity, OutboxEntity: outboxEntity}
+}
+
+// ExecuteImportAccounts creates every row in a transaction of its own, so a bad row only fails itself. Rows whose
+// username or email is taken are skipped, a dump can be imported again after a failure and only the missing
+// accounts are created. A row repeating the username or email of an earlier row of the dump is skipped too.
+// progress, when set, is called every progressEvery rows
+func (a AccountImportUsecase) ExecuteImportAccounts(ctx context.Context, request domain.AccountImportRequest, progress func(domain.AccountImportResponse), boundary OutputAccountImportBoundary) error {
+	res := domain.AccountImportResponse{
+		Total:    len(request.Rows),
+		DryRun:   request.DryRun,
+		Rejected: []domain.AccountImportRowResult{},
+	}
+	emails := make(map[string]int)
+	usernames := make(map[string]int)
+
+	for i, row := range request.Rows {
+		if err := ctx.Err(); err != nil {
+			log.Printf("Account import stopped after %d of %d rows: %v", res.Processed, res.Total, err)
+			return err
+		}
+		number := i + 1
+		row.Username = strings.TrimSpace(row.Username)
+		row.Email = strings.TrimSpace(row.Email)
+
+		status, err := a.importRow(ctx, row, number, emails, usernames, request.DryRun)
+		res.Processed++
+		switch status {
+		case "":
+			res.Created++
+			if row.ResetPassword || row.PasswordHash == "" {
+				res.ResetRequired++
+			}
+		case importStatusExisting:
+			res.Existing++
+		case importStatusDuplicate:
+			res.Duplicates++
+		case importStatusInvalid:
+			res.Invalid++
+		default:
+			res.Failed++
+		}
+		if status != "" {
+			res.Rejected = append(res.Rejected, domain.AccountImportRowResult{
+				Row:      number,
+				Username: row.Username,
+				Email:    row.Email,
+				Status:   status,
+				Reason:   err.Error(),
+			})
+		}
+
+		if progress != nil && res.Processed%progressEvery == 0 && res.Processed < res.Total {
+			progress(res)
+		}
+	}
+
+	log.Printf("Account import of %d rows (dry run %t): %d created, %d existing, %d duplicates, %d invalid, %d failed",
+		res.Total, res.DryRun, res.Created, res.Existing, res.Duplicates, res.Invalid, res.Failed)
+	boundary.AccountImportResponse(res, nil)
+	return nil
+}
+
+// importRow returns the status of a rejected row with the reason, or an empty status once the row is created.
+// On a dry run the row is only checked
+func (a AccountImportUsecase) importRow(ctx context.Context, row domain.AccountImportRow, number int, emails map[string]int, usernames map[string]int, dryRun bool) (string, error) {
+	if err := a.AccountEntity.ValidateImportAccountEntity(row); err != nil {
+		return importStatusInvalid, err
+	}
+
+	emailKey, usernameKey := strings.ToLower(row.Email), strings.ToLower(row.Username)
+	if first, ok := emails[emailKey]; ok {
+		return importStatusDuplicate, fmt.Errorf("email is already used by row %d", first)
+	}
+	if first, ok := usernames[usernameKey]; ok {
+		return importStatusDuplicate, fmt.Errorf("username is already used by row %d", first)
+	}
+	emails[emailKey] = number
+	usernames[usernameKey] = number
+
+	// Like sign up the lookup names every taken field, the unique constraints still decide at the insert
+	fn := func(tx *sql.Tx) error {
+		if err := a.AccountEntity.CheckAccountAvailableEntities(ctx, tx, row.Email, row.Username); err != nil || dryRun {
+			return err
+		}
+		return a.createAccount(ctx, tx, row)
+	}
+	var err error
+	if dryRun {
+		err = common.WithReadOnlyTransactionManager(ctx, a.DB, fn)
+	} else {
+		err = common.WithExecuteTransactionalManager(ctx, a.DB, fn)
+	}
+
+	switch {
+	case errors.Is(err, accounts.ErrAccountExists):
+		return importStatusExisting, err
+	case err != nil:
+		log.Printf("Account import of row %d failed: %v", number, err)
+		return importStatusFailed, err
+	}
+	return "", nil
+}
+
+func (a AccountImportUsecase) createAccount(ctx context.Context, tx *sql.Tx, row domain.AccountImportRow) error {
+	account, err := a.AccountEntity.ImportAccountEntity(ctx, tx, row)
+	if err != nil {
+		return err
+	}
+
+	// The date of birth passed validation, the layout is the one it was validated with
+	dateOfBirth, _ := time.Parse("2006-01-02", row.DateOfBirth)
+	fullName := row.FullName
+	userDto := domain.UserDto{
+		AccountID:   account.AccountId,
+		FullName:    &fullName,
+		DateOfBirth: dateOfBirth,
+		Gender:      row.Gender,
+		Address:     row.Address,
+		Bio:         row.Bio,
+	}
+	if err := a.UserEntity.SaveUserEntities(ctx, tx, userDto); err != nil {
+		return err
+	}
+
+	return a.OutboxEntity.RecordEventEntity(ctx, tx, outbox.EventAccountRegistered, account.AccountId, domain.AccountRegisteredEvent{
+		AccountID: account.AccountId,
+		Username:  account.Username,
+		Provider:  "import",
+	})
+}
@@ -0,0 +1,83 @@
+package accounts
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"godating-dealls/internal/infra/redisclient"
+	"log"
+	"strconv"
+	"time"
+)
+
+// ExecuteDeleteAccount deletes the account of the token. It leaves discovery at once and every session ends, the
+// account deletion purge job erases it once the deletion grace passed. Logging in before that cancels the deletion
+func (a AccountUsecase) ExecuteDeleteAccount(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputAccountBoundary) error {
+	var deleted domain.AccountDormancyDto
+	fn := func(tx *sql.Tx) error {
+		var err error
+		deleted, err = a.DormancyEntity.DeleteAccountEntity(ctx, tx, claims.AccountId)
+		return err
+	}
+	if err := common.WithExecuteTransactionalManager(ctx, a.Db, fn); err != nil {
+		log.Println("Transaction failed:", err)
+		return err
+	}
+
+	redisKey := redisclient.AccessTokenKey.Key(common.StringEncoder(fmt.Sprintf("%d:%s", claims.AccountId, claims.Email)))
+	if err := a.Rds.ClearFromRedis(ctx, redisKey); err != nil {
+		log.Printf("Failed to clear access token after account deletion: %v", err)
+	}
+	revokedKey := redisclient.RefreshRevokedKey.Key(strconv.FormatInt(claims.AccountId, 10))
+	if err := a.Rds.StoreToRedis(ctx, revokedKey, time.Now().UnixMilli()); err != nil {
+		log.Printf("Failed to revoke refresh tokens after account deletion: %v", err)
+	}
+
+	boundary.DeleteAccountResponse(domain.DeleteAccountResponse{
+		AccountID:  claims.AccountId,
+		DeletedAt:  common.FormatTimeByParam(deleted.UpdatedAt),
+		PurgeAfter: common.FormatTimeByParam(deleted.UpdatedAt.Add(a.DeletionGrace)),
+	}, nil)
+	return nil
+}
+
+// ExecuteExportAccountData answers with everything stored about the account of the token, the photos with the link
+// to their file
+func (a AccountUsecase) ExecuteExportAccountData(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputAccountBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		sections, err := a.AccountExportEntity.ExportAccountDataEntity(ctx, tx, claims.AccountId)
+		if err != nil {
+			return err
+		}
+
+		res := domain.AccountExportResponse{
+			AccountID:  claims.AccountId,
+			ExportedAt: common.FormatTime(),
+			Sections:   make(map[string][]map[string]interface{}, len(sections)),
+		}
+		for _, section := range sections {
+			for _, row := range section.Rows {
+				for column, value := range row {
+					if t, ok := value.(time.Time); ok {
+						row[column] = common.FormatTimeByParam(t)
+					}
+				}
+				if key, ok := row["storage_key"].(string); ok && section.Name == "photos" {
+					row["url"] = a.Storage.URL(key)
+				}
+			}
+			res.Sections[section.Name] = section.Rows
+		}
+		boundary.AccountExportResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, a.Db, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
@@ -3,9 +3,12 @@ package accounts
 import (
 	"context"
 	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
 )
 
 type InputAccountBoundary interface {
-	ExecuteFetchAccountDetail(ctx context.Context, token string, boundary OutputAccountBoundary) error
-	ExecuteViewAccountDetail(ctx context.Context, token string, request domain.ViewedAccountRequest, boundary OutputAccountBoundary) error
+	ExecuteFetchAccountDetail(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputAccountBoundary) error
+	ExecuteViewAccountDetail(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.ViewedAccountRequest, boundary OutputAccountBoundary) error
+	ExecuteDeleteAccount(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputAccountBoundary) error
+	ExecuteExportAccountData(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputAccountBoundary) error
 }
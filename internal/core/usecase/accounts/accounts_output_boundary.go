@@ -5,4 +5,6 @@ import "godating-dealls/internal/domain"
 type OutputAccountBoundary interface {
 	AccountDetailResponse(response domain.AccountResponse, err error)
 	ViewAccountResponse(response domain.ViewedAccountResponse, err error)
+	DeleteAccountResponse(response domain.DeleteAccountResponse, err error)
+	AccountExportResponse(response domain.AccountExportResponse, err error)
 }
@@ -5,12 +5,18 @@ import (
 	"database/sql"
 	"errors"
 	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/account_exports"
 	"godating-dealls/internal/core/entities/accounts"
+	"godating-dealls/internal/core/entities/dormancy"
+	"godating-dealls/internal/core/entities/photos"
 	"godating-dealls/internal/core/entities/swipes"
 	"godating-dealls/internal/core/entities/users"
 	"godating-dealls/internal/core/entities/views"
 	"godating-dealls/internal/domain"
 	"godating-dealls/internal/infra/jsonwebtoken"
+	"godating-dealls/internal/infra/redisclient"
+	"godating-dealls/internal/infra/storage"
+	"time"
 )
 
 type AccountUsecase struct {
@@ -19,6 +25,13 @@ type AccountUsecase struct {
 	SwipeEntity   swipes.SwipeEntity
 	UserEntity    users.UserEntity
 	ViewEntity    views.ViewEntity
+	PhotoEntity   photos.PhotoEntity
+	Storage       storage.Storage
+	// DormancyEntity deletes the account, the account deletion purge job erases it after DeletionGrace
+	DormancyEntity      dormancy.DormancyEntity
+	AccountExportEntity account_exports.AccountExportEntity
+	Rds                 redisclient.RedisInterface
+	DeletionGrace       time.Duration
 }
 
 func NewAccountsUsecase(
@@ -26,23 +39,30 @@ func NewAccountsUsecase(
 	accountEntity accounts.AccountEntity,
 	swipeEntity swipes.SwipeEntity,
 	userEntity users.UserEntity,
-	viewEntity views.ViewEntity) InputAccountBoundary {
+	viewEntity views.ViewEntity,
+	photoEntity photos.PhotoEntity,
+	storage storage.Storage,
+	dormancyEntity dormancy.DormancyEntity,
+	accountExportEntity account_exports.AccountExportEntity,
+	rds redisclient.RedisInterface,
+	deletionGrace time.Duration) InputAccountBoundary {
 	return &AccountUsecase{
-		Db:            db,
-		AccountEntity: accountEntity,
-		SwipeEntity:   swipeEntity,
-		UserEntity:    userEntity,
-		ViewEntity:    viewEntity,
+		Db:                  db,
+		AccountEntity:       accountEntity,
+		SwipeEntity:         swipeEntity,
+		UserEntity:          userEntity,
+		ViewEntity:          viewEntity,
+		PhotoEntity:         photoEntity,
+		Storage:             storage,
+		DormancyEntity:      dormancyEntity,
+		AccountExportEntity: accountExportEntity,
+		Rds:                 rds,
+		DeletionGrace:       deletionGrace,
 	}
 }
 
-func (a AccountUsecase) ExecuteFetchAccountDetail(ctx context.Context, token string, boundary OutputAccountBoundary) error {
+func (a AccountUsecase) ExecuteFetchAccountDetail(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputAccountBoundary) error {
 	fn := func(tx *sql.Tx) error {
-		claims, err := jsonwebtoken.VerifyJWTToken(token)
-		if err != nil {
-			return errors.New("invalid token")
-		}
-
 		account, err := a.AccountEntity.FindAccountDetails(ctx, tx, claims.AccountId)
 		if err != nil {
 			return errors.New("invalid fetch account")
@@ -63,6 +83,11 @@ func (a AccountUsecase) ExecuteFetchAccountDetail(ctx context.Context, token str
 			return errors.New("invalid fetch views")
 		}
 
+		photoURLs, err := a.findPhotoURLs(ctx, tx, claims.AccountId, claims.AccountId)
+		if err != nil {
+			return err
+		}
+
 		accountData := domain.AccountDataResponse{
 			UserID:      user.UserID,
 			AccountID:   account.AccountId,
@@ -75,6 +100,7 @@ func (a AccountUsecase) ExecuteFetchAccountDetail(ctx context.Context, token str
 			Bio:         user.Bio,
 			FullName:    user.FullName,
 			DateOfBirth: user.DateOfBirth,
+			Photos:      photoURLs,
 		}
 
 		accountView := domain.AccountViewResponse{
@@ -98,13 +124,8 @@ func (a AccountUsecase) ExecuteFetchAccountDetail(ctx context.Context, token str
 	return nil
 }
 
-func (a AccountUsecase) ExecuteViewAccountDetail(ctx context.Context, token string, request domain.ViewedAccountRequest, boundary OutputAccountBoundary) error {
+func (a AccountUsecase) ExecuteViewAccountDetail(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.ViewedAccountRequest, boundary OutputAccountBoundary) error {
 	fn := func(tx *sql.Tx) error {
-		claims, err := jsonwebtoken.VerifyJWTToken(token)
-		if err != nil {
-			return errors.New("invalid token")
-		}
-
 		account, err := a.AccountEntity.FindAccountDetails(ctx, tx, request.AccountIDView)
 		if err != nil {
 			return errors.New("invalid fetch accounts")
@@ -123,12 +144,18 @@ func (a AccountUsecase) ExecuteViewAccountDetail(ctx context.Context, token stri
 			return errors.New("invalid post views account")
 		}
 
+		photoURLs, err := a.findPhotoURLs(ctx, tx, claims.AccountId, request.AccountIDView)
+		if err != nil {
+			return err
+		}
+
 		res := domain.ViewedAccountResponse{
 			AccountID: account.AccountId,
 			UserName:  account.Username,
 			Email:     account.Email,
 			Verified:  account.Verified,
 			FullName:  user.FullName,
+			Photos:    photoURLs,
 		}
 		boundary.ViewAccountResponse(res, nil)
 
@@ -141,3 +168,21 @@ func (a AccountUsecase) ExecuteViewAccountDetail(ctx context.Context, token stri
 	}
 	return nil
 }
+
+// findPhotoURLs links the uploaded photos of the account, primary photo first, or the smart photo of the viewer first
+func (a AccountUsecase) findPhotoURLs(ctx context.Context, tx *sql.Tx, viewerAccountId int64, accountId int64) ([]string, error) {
+	uploaded, err := a.PhotoEntity.FindPhotosEntity(ctx, tx, accountId)
+	if err != nil {
+		return nil, err
+	}
+	arranged, err := a.PhotoEntity.ArrangePhotosForViewerEntity(ctx, tx, viewerAccountId, map[int64][]domain.PhotoDto{accountId: uploaded})
+	if err != nil {
+		return nil, err
+	}
+	uploaded = arranged[accountId]
+	urls := make([]string, 0, len(uploaded))
+	for _, photo := range uploaded {
+		urls = append(urls, a.Storage.URL(photo.StorageKey))
+	}
+	return urls, nil
+}
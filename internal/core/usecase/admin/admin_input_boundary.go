@@ -0,0 +1,22 @@
+package admin
+
+import (
+	"context"
+	"godating-dealls/internal/domain"
+	"time"
+)
+
+type InputAdminBoundary interface {
+	ExecuteAccountTimeline(ctx context.Context, accountId int64, page int, size int, boundary OutputAdminBoundary) error
+	ExecuteExportAccountTimeline(ctx context.Context, accountId int64, boundary OutputAdminBoundary) error
+	ExecuteRectifyUser(ctx context.Context, accountId int64, request domain.RectificationRequest, boundary OutputAdminBoundary) error
+	ExecuteQuotaUsageMetrics(ctx context.Context, from *time.Time, to *time.Time, boundary OutputAdminBoundary) error
+	ExecuteListJobs(ctx context.Context, boundary OutputAdminBoundary) error
+	ExecuteTriggerJob(ctx context.Context, name string, boundary OutputAdminBoundary) error
+	ExecutePauseJob(ctx context.Context, name string, boundary OutputAdminBoundary) error
+	ExecuteResumeJob(ctx context.Context, name string, boundary OutputAdminBoundary) error
+	ExecuteGeoRestrictionMetrics(ctx context.Context, boundary OutputAdminBoundary) error
+	ExecuteRuntimeMetrics(ctx context.Context, boundary OutputAdminBoundary) error
+	ExecuteFetchLogLevels(ctx context.Context, boundary OutputAdminBoundary) error
+	ExecuteSetLogLevel(ctx context.Context, module string, request domain.LogLevelRequest, boundary OutputAdminBoundary) error
+}
@@ -0,0 +1,16 @@
+package admin
+
+import "godating-dealls/internal/domain"
+
+type OutputAdminBoundary interface {
+	AccountTimelineResponse(response domain.AccountTimelineResponse, err error)
+	// TimelineExportRow is called once per exported timeline event, an error stops the export
+	TimelineExportRow(response domain.TimelineEventResponse) error
+	RectificationResponse(response domain.RectificationResponse, err error)
+	QuotaUsageResponse(response []domain.QuotaUsageResponse, err error)
+	JobsResponse(response []domain.JobStatusResponse, err error)
+	JobResponse(response domain.JobStatusResponse, err error)
+	GeoRestrictionMetricsResponse(response domain.GeoRestrictionMetricsResponse, err error)
+	RuntimeMetricsResponse(response domain.RuntimeMetricsResponse, err error)
+	LogLevelsResponse(response []domain.LogLevelResponse, err error)
+}
@@ -0,0 +1,374 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/accounts"
+	"godating-dealls/internal/core/entities/admin"
+	"godating-dealls/internal/core/entities/analytics"
+	"godating-dealls/internal/core/entities/users"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/geoip"
+	"godating-dealls/internal/infra/scheduler"
+	"godating-dealls/internal/infra/watchdog"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTimelinePageSize = 50
+	maxTimelinePageSize     = 200
+	defaultMetricsDays      = 30
+)
+
+type AdminUsecase struct {
+	DB              *sql.DB
+	AdminEntity     admin.AdminEntity
+	AccountEntity   accounts.AccountEntity
+	UserEntity      users.UserEntity
+	AnalyticsEntity analytics.AnalyticsEntity
+	Scheduler       *scheduler.Scheduler
+	GeoGuard        *geoip.Guard
+	Watchdog        *watchdog.Watchdog
+}
+
+func NewAdminUsecase(
+	db *sql.DB,
+	adminEntity admin.AdminEntity,
+	accountEntity accounts.AccountEntity,
+	userEntity users.UserEntity,
+	analyticsEntity analytics.AnalyticsEntity,
+	jobScheduler *scheduler.Scheduler,
+	geoGuard *geoip.Guard,
+	runtimeWatchdog *watchdog.Watchdog) InputAdminBoundary {
+	return &AdminUsecase{
+		DB:              db,
+		AdminEntity:     adminEntity,
+		AccountEntity:   accountEntity,
+		UserEntity:      userEntity,
+		AnalyticsEntity: analyticsEntity,
+		Scheduler:       jobScheduler,
+		GeoGuard:        geoGuard,
+		Watchdog:        runtimeWatchdog,
+	}
+}
+
+// ExecuteAccountTimeline admin requests are authenticated by the admin middleware, not by a user token
+func (a AdminUsecase) ExecuteAccountTimeline(ctx context.Context, accountId int64, page int, size int, boundary OutputAdminBoundary) error {
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = defaultTimelinePageSize
+	}
+	if size > maxTimelinePageSize {
+		size = maxTimelinePageSize
+	}
+
+	fn := func(tx *sql.Tx) error {
+		_, err := a.AccountEntity.FindAccountDetails(ctx, tx, accountId)
+		if err != nil {
+			return errors.New("invalid fetch account")
+		}
+
+		events, total, err := a.AdminEntity.FindAccountTimelineEntity(ctx, tx, accountId, page, size)
+		if err != nil {
+			return err
+		}
+
+		res := domain.AccountTimelineResponse{
+			AccountID: accountId,
+			Page:      page,
+			Size:      size,
+			Total:     total,
+			Events:    []domain.TimelineEventResponse{},
+		}
+		for _, event := range events {
+			res.Events = append(res.Events, domain.TimelineEventResponse{
+				EventType:  event.EventType,
+				OccurredAt: common.FormatTimeByParam(event.OccurredAt),
+				Detail:     event.Detail,
+			})
+		}
+		boundary.AccountTimelineResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, a.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteExportAccountTimeline streams the whole timeline to the boundary while it is read, the request context
+// ends the query when the client disconnects
+func (a AdminUsecase) ExecuteExportAccountTimeline(ctx context.Context, accountId int64, boundary OutputAdminBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		_, err := a.AccountEntity.FindAccountDetails(ctx, tx, accountId)
+		if err != nil {
+			return errors.New("invalid fetch account")
+		}
+
+		return a.AdminEntity.StreamAccountTimelineEntity(ctx, tx, accountId, func(event domain.TimelineEventDto) error {
+			return boundary.TimelineExportRow(domain.TimelineEventResponse{
+				EventType:  event.EventType,
+				OccurredAt: common.FormatTimeByParam(event.OccurredAt),
+				Detail:     event.Detail,
+			})
+		})
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, a.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteRectifyUser corrects profile fields on the user's request, the change and its audit entry commit together
+func (a AdminUsecase) ExecuteRectifyUser(ctx context.Context, accountId int64, request domain.RectificationRequest, boundary OutputAdminBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		current, err := a.UserEntity.FindUserDetailEntity(ctx, tx, accountId)
+		if err != nil {
+			return errors.New("failed to find user")
+		}
+
+		currentName := ""
+		if current.FullName != nil {
+			currentName = *current.FullName
+		}
+		currentDateOfBirth := ""
+		if current.DateOfBirth != nil {
+			currentDateOfBirth = common.FormatFromTimeToStr(current.DateOfBirth)
+		}
+
+		// Start from the stored profile so fields left out of the request are kept
+		patch := domain.PatchUser{
+			UserID:      current.UserID,
+			FullName:    &currentName,
+			Gender:      &current.Gender,
+			Address:     &current.Address,
+			Bio:         &current.Bio,
+			DateOfBirth: &currentDateOfBirth,
+		}
+
+		changes := map[string]domain.FieldChange{}
+		rectify := func(field string, before string, after *string, target **string) {
+			if after != nil && *after != before {
+				changes[field] = domain.FieldChange{Before: before, After: *after}
+				*target = after
+			}
+		}
+		rectify("full_name", currentName, request.FullName, &patch.FullName)
+		rectify("date_of_birth", currentDateOfBirth, request.DateOfBirth, &patch.DateOfBirth)
+		rectify("gender", current.Gender, request.Gender, &patch.Gender)
+		rectify("address", current.Address, request.Address, &patch.Address)
+		rectify("bio", current.Bio, request.Bio, &patch.Bio)
+
+		if len(changes) == 0 {
+			return errors.New("no field to correct")
+		}
+
+		_, err = a.UserEntity.UpdateUserEntities(ctx, tx, patch)
+		if err != nil {
+			return err
+		}
+
+		audit, err := a.AdminEntity.RecordAuditLogEntity(ctx, tx, domain.AdminAuditLogDto{
+			Actor:           request.Actor,
+			Action:          "rectification",
+			TargetAccountID: accountId,
+			Justification:   request.Justification,
+			Changes:         changes,
+		})
+		if err != nil {
+			return err
+		}
+
+		boundary.RectificationResponse(domain.RectificationResponse{
+			AccountID:     accountId,
+			AuditID:       audit.AuditID,
+			ChangedFields: changes,
+			Message:       "User data corrected",
+		}, nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, a.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteQuotaUsageMetrics reads the nightly rollups, without a range it covers the last 30 days
+func (a AdminUsecase) ExecuteQuotaUsageMetrics(ctx context.Context, from *time.Time, to *time.Time, boundary OutputAdminBoundary) error {
+	end := time.Now()
+	if to != nil {
+		end = *to
+	}
+	start := end.AddDate(0, 0, -defaultMetricsDays)
+	if from != nil {
+		start = *from
+	}
+
+	fn := func(tx *sql.Tx) error {
+		usage, err := a.AnalyticsEntity.FindQuotaUsageEntity(ctx, tx, start, end)
+		if err != nil {
+			return err
+		}
+
+		var res []domain.QuotaUsageResponse
+		for _, u := range usage {
+			item := domain.QuotaUsageResponse{
+				Date:              u.Date.Format("2006-01-02"),
+				Tier:              u.Tier,
+				Accounts:          u.Accounts,
+				ActiveAccounts:    u.ActiveAccounts,
+				ExhaustedAccounts: u.ExhaustedAccounts,
+			}
+			if u.Accounts > 0 {
+				item.AvgSwipesPerDay = float64(u.TotalSwipes) / float64(u.Accounts)
+				item.AvgLikesPerDay = float64(u.TotalLikes) / float64(u.Accounts)
+				item.ExhaustionRatePerc = float64(u.ExhaustedAccounts) * 100 / float64(u.Accounts)
+			}
+			res = append(res, item)
+		}
+		boundary.QuotaUsageResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, a.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (a AdminUsecase) ExecuteListJobs(ctx context.Context, boundary OutputAdminBoundary) error {
+	var res []domain.JobStatusResponse
+	for _, job := range a.Scheduler.List() {
+		res = append(res, toJobStatusResponse(job))
+	}
+	boundary.JobsResponse(res, nil)
+	return nil
+}
+
+// ExecuteTriggerJob the job runs in the background, its outcome shows up in the job list
+func (a AdminUsecase) ExecuteTriggerJob(ctx context.Context, name string, boundary OutputAdminBoundary) error {
+	if err := a.Scheduler.Trigger(name); err != nil {
+		return err
+	}
+	return a.jobResponse(name, boundary)
+}
+
+func (a AdminUsecase) ExecutePauseJob(ctx context.Context, name string, boundary OutputAdminBoundary) error {
+	if err := a.Scheduler.Pause(name); err != nil {
+		return err
+	}
+	return a.jobResponse(name, boundary)
+}
+
+func (a AdminUsecase) ExecuteResumeJob(ctx context.Context, name string, boundary OutputAdminBoundary) error {
+	if err := a.Scheduler.Resume(name); err != nil {
+		return err
+	}
+	return a.jobResponse(name, boundary)
+}
+
+// ExecuteGeoRestrictionMetrics counts are kept per instance since it started
+func (a AdminUsecase) ExecuteGeoRestrictionMetrics(ctx context.Context, boundary OutputAdminBoundary) error {
+	metrics := a.GeoGuard.Metrics()
+	boundary.GeoRestrictionMetricsResponse(domain.GeoRestrictionMetricsResponse{
+		Enabled:         metrics.Enabled,
+		Blocked:         metrics.Blocked,
+		Flagged:         metrics.Flagged,
+		LookupFailures:  metrics.LookupFailures,
+		BlockedByReason: metrics.BlockedByReason,
+		BlockedByRoute:  metrics.BlockedByRoute,
+	}, nil)
+	return nil
+}
+
+// ExecuteRuntimeMetrics samples this instance now, each instance has its own watchdog
+func (a AdminUsecase) ExecuteRuntimeMetrics(ctx context.Context, boundary OutputAdminBoundary) error {
+	metrics := a.Watchdog.Metrics()
+	res := domain.RuntimeMetricsResponse{
+		SampledAt:             common.FormatTimeByParam(metrics.SampledAt),
+		Goroutines:            metrics.Goroutines,
+		HeapAllocBytes:        metrics.HeapAllocBytes,
+		HeapObjects:           metrics.HeapObjects,
+		NumGC:                 metrics.NumGC,
+		DBOpenConnections:     metrics.DBOpenConnections,
+		DBInUse:               metrics.DBInUse,
+		DBIdle:                metrics.DBIdle,
+		DBWaitCount:           metrics.DBWaitCount,
+		RedisTotalConnections: metrics.RedisTotalConnections,
+		RedisIdleConnections:  metrics.RedisIdleConnections,
+		RedisTimeouts:         metrics.RedisTimeouts,
+		Warnings:              metrics.Warnings,
+		Dumps:                 metrics.Dumps,
+		LastDump:              metrics.LastDump,
+	}
+	if metrics.LastWarnAt != nil {
+		res.LastWarnAt = common.FormatTimeByParam(*metrics.LastWarnAt)
+	}
+	boundary.RuntimeMetricsResponse(res, nil)
+	return nil
+}
+
+func (a AdminUsecase) ExecuteFetchLogLevels(ctx context.Context, boundary OutputAdminBoundary) error {
+	boundary.LogLevelsResponse(logLevelsResponse(), nil)
+	return nil
+}
+
+// ExecuteSetLogLevel the level applies to this instance until it restarts, then LOG_LEVEL and LOG_LEVELS apply again
+func (a AdminUsecase) ExecuteSetLogLevel(ctx context.Context, module string, request domain.LogLevelRequest, boundary OutputAdminBoundary) error {
+	if err := common.SetLogLevel(module, strings.ToLower(request.Level)); err != nil {
+		return err
+	}
+	log.Printf("Log level of %s set to %s", module, strings.ToLower(request.Level))
+	boundary.LogLevelsResponse(logLevelsResponse(), nil)
+	return nil
+}
+
+func logLevelsResponse() []domain.LogLevelResponse {
+	levels := common.LogLevels()
+	var res []domain.LogLevelResponse
+	for _, module := range common.LogModules() {
+		res = append(res, domain.LogLevelResponse{Module: module, Level: levels[module]})
+	}
+	return res
+}
+
+func (a AdminUsecase) jobResponse(name string, boundary OutputAdminBoundary) error {
+	job, err := a.Scheduler.Find(name)
+	if err != nil {
+		return err
+	}
+	boundary.JobResponse(toJobStatusResponse(job), nil)
+	return nil
+}
+
+func toJobStatusResponse(job scheduler.JobStatus) domain.JobStatusResponse {
+	res := domain.JobStatusResponse{
+		Name:           job.Name,
+		Schedule:       job.Schedule,
+		Paused:         job.Paused,
+		Running:        job.Running,
+		LastStatus:     job.LastStatus,
+		LastDurationMs: job.LastDuration.Milliseconds(),
+		LastError:      job.LastError,
+	}
+	if job.LastRunAt != nil {
+		res.LastRunAt = common.FormatTimeByParam(*job.LastRunAt)
+	}
+	if job.NextRunAt != nil {
+		res.NextRunAt = common.FormatTimeByParam(*job.NextRunAt)
+	}
+	return res
+}
@@ -0,0 +1,7 @@
+package analytics
+
+import "context"
+
+type InputAnalyticsBoundary interface {
+	ExecuteQuotaUsageRollup(ctx context.Context) error
+}
@@ -0,0 +1,39 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/analytics"
+	"log"
+	"time"
+)
+
+type AnalyticsUsecase struct {
+	DB              *sql.DB
+	AnalyticsEntity analytics.AnalyticsEntity
+}
+
+func NewAnalyticsUsecase(db *sql.DB, analyticsEntity analytics.AnalyticsEntity) InputAnalyticsBoundary {
+	return &AnalyticsUsecase{DB: db, AnalyticsEntity: analyticsEntity}
+}
+
+// ExecuteQuotaUsageRollup summarises yesterday, the day whose quotas are final, rerunning it overwrites the same rows
+func (a AnalyticsUsecase) ExecuteQuotaUsageRollup(ctx context.Context) error {
+	day := time.Now().AddDate(0, 0, -1)
+
+	fn := func(tx *sql.Tx) error {
+		rows, err := a.AnalyticsEntity.RollupQuotaUsageEntity(ctx, tx, day)
+		if err != nil {
+			return err
+		}
+		log.Printf("Quota usage rollup for %s: %d rows written", day.Format("2006-01-02"), rows)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, a.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
@@ -3,10 +3,25 @@ package auths
 import (
 	"context"
 	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
 )
 
 type InputAuthBoundary interface {
 	ExecuteLoginUsecase(ctx context.Context, request domain.LoginRequest, boundary OutputAuthBoundary) error
 	ExecuteRegisterUsecase(ctx context.Context, request domain.RegisterRequest, boundary OutputAuthBoundary) error
-	ExecuteLogoutUsecase(ctx context.Context, accessToken *string, boundary OutputAuthBoundary) error
+	ExecuteLogoutUsecase(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputAuthBoundary) error
+	ExecuteRefreshTokenUsecase(ctx context.Context, request domain.RefreshTokenRequest, boundary OutputAuthBoundary) error
+	ExecuteForgotPassword(ctx context.Context, request domain.ForgotPasswordRequest, boundary OutputAuthBoundary) error
+	ExecuteResetPassword(ctx context.Context, request domain.ResetPasswordRequest, boundary OutputAuthBoundary) error
+	ExecuteIssueScopedToken(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.ScopedTokenRequest, boundary OutputAuthBoundary) error
+	ExecuteIntrospectToken(ctx context.Context, token string, boundary OutputAuthBoundary) error
+	ExecuteLoginThrottleMetrics(ctx context.Context, boundary OutputAuthBoundary) error
+	ExecutePasskeyRegisterOptions(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputAuthBoundary) error
+	ExecutePasskeyRegister(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.PasskeyRegisterRequest, boundary OutputAuthBoundary) error
+	ExecutePasskeyLoginOptions(ctx context.Context, boundary OutputAuthBoundary) error
+	ExecutePasskeyLogin(ctx context.Context, request domain.PasskeyLoginRequest, boundary OutputAuthBoundary) error
+	ExecuteOAuthLogin(ctx context.Context, provider string, request domain.OAuthLoginRequest, boundary OutputAuthBoundary) error
+	ExecuteFetchPasskeys(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputAuthBoundary) error
+	ExecuteDeletePasskey(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, credentialId string, boundary OutputAuthBoundary) error
+	ExecuteRegionLookup(ctx context.Context, login string, address string, boundary OutputAuthBoundary) error
 }
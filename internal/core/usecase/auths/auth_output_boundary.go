@@ -8,4 +8,15 @@ type OutputAuthBoundary interface {
 	LoginResponse(response res.LoginResponse, err error)
 	RegisterResponse(response res.RegisterResponse, err error)
 	LogoutResponse(response res.LogoutResponse, err error)
+	RefreshTokenResponse(response res.RefreshTokenResponse, err error)
+	PasswordResetResponse(response res.PasswordResetResponse, err error)
+	ScopedTokenResponse(response res.ScopedTokenResponse, err error)
+	IntrospectionResponse(response res.IntrospectionResponse, err error)
+	LoginThrottleMetricsResponse(response res.LoginThrottleMetrics, err error)
+	PasskeyCreationOptionsResponse(response res.PasskeyCreationOptions, err error)
+	PasskeyRequestOptionsResponse(response res.PasskeyRequestOptions, err error)
+	PasskeyResponse(response res.PasskeyResponse, err error)
+	PasskeysResponse(response []res.PasskeyResponse, err error)
+	DeletePasskeyResponse(response res.DeletePasskeyResponse, err error)
+	RegionLookupResponse(response res.RegionResponse, err error)
 }
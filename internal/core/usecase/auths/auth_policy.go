@@ -0,0 +1,16 @@
+package auths
+
+// Policy of the logins and password resets, it is read by config.Load
+type Policy struct {
+	LoginThrottle LoginThrottlePolicy
+	// PasswordResetURL is the page of the app the reset email links to with the token, PASSWORD_RESET_URL. Without it
+	// the email only has the token
+	PasswordResetURL string `validate:"omitempty,url"`
+}
+
+// LoginThrottlePolicy has how many failed logins a credential and an address get within the ttl of their redis
+// namespace, LOGIN_THROTTLE_CREDENTIAL_LIMIT and LOGIN_THROTTLE_ADDRESS_LIMIT
+type LoginThrottlePolicy struct {
+	CredentialLimit int64 `validate:"gt=0"`
+	AddressLimit    int64 `validate:"gt=0"`
+}
@@ -7,12 +7,31 @@ import (
 	"fmt"
 	"godating-dealls/internal/common"
 	"godating-dealls/internal/core/entities/accounts"
+	"godating-dealls/internal/core/entities/dormancy"
 	"godating-dealls/internal/core/entities/login_histories"
+	"godating-dealls/internal/core/entities/outbox"
+	"godating-dealls/internal/core/entities/passkeys"
+	"godating-dealls/internal/core/entities/rewards"
 	"godating-dealls/internal/core/entities/users"
 	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/analytics"
 	"godating-dealls/internal/infra/jsonwebtoken"
+	"godating-dealls/internal/infra/notifier"
+	"godating-dealls/internal/infra/oauth"
 	"godating-dealls/internal/infra/redisclient"
-	"log"
+	"godating-dealls/internal/infra/regions"
+	"godating-dealls/internal/infra/webauthn"
+	"strings"
+)
+
+var registerResponse = common.NewMapping(domain.RegisterResponse{}, domain.Accounts{}, "CreateAt", "UpdateAt")
+
+// Generic errors for anti-enumeration mode, they must read the same whether or not the account exists.
+// With the mode off a wrong password still wraps ErrInvalidCredentials so it answers the same status
+var (
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrRegistrationFailed = errors.New("registration could not be completed, if you already have an account try to login")
+	dummyPasswordHash     = common.HashingPassword([]byte("godating-dealls-dummy-password"))
 )
 
 type AuthUsecase struct {
@@ -21,6 +40,17 @@ type AuthUsecase struct {
 	UserEntity           users.UserEntity
 	Rds                  redisclient.RedisInterface
 	LoginHistoriesEntity login_histories.LoginHistoriesEntity
+	RewardEntity         rewards.RewardEntity
+	DormancyEntity       dormancy.DormancyEntity
+	PasskeyEntity        passkeys.PasskeyEntity
+	Notifier             notifier.Notifier
+	WebAuthn             webauthn.Config
+	OAuth                oauth.Registry
+	Regions              *regions.Registry
+	OutboxEntity         outbox.OutboxEntity
+	Analytics            analytics.Publisher
+	Policy               Policy
+	throttle             *loginThrottle
 }
 
 func NewAuthUsecase(
@@ -28,17 +58,43 @@ func NewAuthUsecase(
 	accountEntity accounts.AccountEntity,
 	userEntity users.UserEntity,
 	rds redisclient.RedisInterface,
-	loginHistoriesEntity login_histories.LoginHistoriesEntity) InputAuthBoundary {
+	loginHistoriesEntity login_histories.LoginHistoriesEntity,
+	rewardEntity rewards.RewardEntity,
+	dormancyEntity dormancy.DormancyEntity,
+	passkeyEntity passkeys.PasskeyEntity,
+	notifier notifier.Notifier,
+	regionRegistry *regions.Registry,
+	outboxEntity outbox.OutboxEntity,
+	analyticsPublisher analytics.Publisher,
+	webAuthn webauthn.Config,
+	oauthRegistry oauth.Registry,
+	policy Policy) InputAuthBoundary {
 	return &AuthUsecase{
 		DB:                   db,
 		AccountEntity:        accountEntity,
 		UserEntity:           userEntity,
 		Rds:                  rds,
 		LoginHistoriesEntity: loginHistoriesEntity,
+		RewardEntity:         rewardEntity,
+		DormancyEntity:       dormancyEntity,
+		PasskeyEntity:        passkeyEntity,
+		Notifier:             notifier,
+		WebAuthn:             webAuthn,
+		OAuth:                oauthRegistry,
+		Regions:              regionRegistry,
+		OutboxEntity:         outboxEntity,
+		Analytics:            analyticsPublisher,
+		Policy:               policy,
+		throttle:             newLoginThrottle(rds, policy.LoginThrottle),
 	}
 }
 
 func (au *AuthUsecase) ExecuteLoginUsecase(ctx context.Context, request domain.LoginRequest, boundary OutputAuthBoundary) error {
+	if err := au.throttle.Check(ctx, request); err != nil {
+		return err
+	}
+
+	var accountId int64
 	fn := func(tx *sql.Tx) error {
 		accountDTO := domain.AccountDto{
 			Username: &request.Username,
@@ -48,71 +104,79 @@ func (au *AuthUsecase) ExecuteLoginUsecase(ctx context.Context, request domain.L
 
 		account, err := au.AccountEntity.AuthenticateAccount(ctx, tx, accountDTO)
 		if err != nil {
-			return errors.New("failed to authenticate account")
-		}
-
-		passwordIsValid, err := common.ComparedPassword(account.Password, []byte(request.Password))
-		if err != nil {
-			return errors.New("failed to compare password")
+			// Spend the same hashing time as a wrong password so the response time does not reveal the account
+			_, _ = common.ComparedPassword(dummyPasswordHash, []byte(request.Password))
+			au.throttle.RecordFailure(ctx, request)
+			return common.ConcealReason(ctx, ErrInvalidCredentials, fmt.Errorf("%w: account not found", ErrInvalidCredentials))
 		}
 
-		if !passwordIsValid {
-			return errors.New("invalid password")
+		// An imported account without a usable password, no password matches it until forgot password sets one
+		if accounts.IsPasswordResetRequired(account.Password) {
+			_, _ = common.ComparedPassword(dummyPasswordHash, []byte(request.Password))
+			au.throttle.RecordFailure(ctx, request)
+			return common.ConcealReason(ctx, ErrInvalidCredentials, accounts.ErrPasswordResetRequired)
 		}
 
-		user, err := au.UserEntity.FindUserEntities(ctx, tx, account.AccountId)
-		if err != nil {
-			return errors.New("failed to find user")
-		}
-
-		token, err := jsonwebtoken.GenerateJWTToken(user.UserID, account.AccountId, account.Email)
+		passwordIsValid, err := common.ComparedPassword(account.Password, []byte(request.Password))
 		if err != nil {
-			return errors.New("failed to generate JWT token")
+			au.throttle.RecordFailure(ctx, request)
+			return common.ConcealReason(ctx, ErrInvalidCredentials, fmt.Errorf("%w: wrong password", ErrInvalidCredentials))
 		}
 
-		// Store to logins history
-		loginDto := domain.LoginHistoriesDto{
-			UserID:    user.UserID,
-			AccountID: account.AccountId,
+		if !passwordIsValid {
+			au.throttle.RecordFailure(ctx, request)
+			return common.ConcealReason(ctx, ErrInvalidCredentials, fmt.Errorf("%w: wrong password", ErrInvalidCredentials))
 		}
-		err = au.LoginHistoriesEntity.SaveLoginHistoriesEntities(ctx, tx, loginDto)
-		common.HandleErrorWithParam(err, "Failed to save login history")
 
-		// Store token to redis
-		redisKey := common.StringEncoder(fmt.Sprintf("access_token:%s:%s", account.AccountId, account.Email))
-		err = au.Rds.StoreToRedis(ctx, redisKey, token)
+		res, err := au.startSession(ctx, tx, account.AccountId, account.Email, account.Username, request.Timezone)
 		if err != nil {
-			return errors.New("failed to save token")
-		}
-
-		res := domain.LoginResponse{
-			Username:    account.Username,
-			Email:       account.Email,
-			AccessToken: token,
+			return err
 		}
+		accountId = account.AccountId
 		boundary.LoginResponse(res, nil)
 		return nil
 	}
 
 	err := common.WithExecuteTransactionalManager(ctx, au.DB, fn)
 	if err != nil {
-		log.Println("Transaction failed:", err)
+		common.AuthLog.Errorf("Transaction failed: %v", err)
+		return err
 	}
-	return err
+	au.throttle.RecordSuccess(ctx, request)
+	au.Analytics.Publish(ctx, analytics.NewEvent(analytics.EventLoggedIn, accountId, map[string]interface{}{"method": "password"}))
+	return nil
+}
+
+func (au *AuthUsecase) ExecuteLoginThrottleMetrics(ctx context.Context, boundary OutputAuthBoundary) error {
+	boundary.LoginThrottleMetricsResponse(au.throttle.Metrics(), nil)
+	return nil
 }
 
 func (au *AuthUsecase) ExecuteRegisterUsecase(ctx context.Context, request domain.RegisterRequest, boundary OutputAuthBoundary) error {
+	var accountId int64
 	fn := func(tx *sql.Tx) error {
-		accountDTO := domain.AccountDto{
-			Username: &request.Username,
+		accountDTO := domain.SignUpAccountDto{
+			Username: request.Username,
 			Password: request.Password,
-			Email:    &request.Email,
+			Email:    request.Email,
 		}
 		common.PrintJSON("auth usecase | account dto", accountDTO)
+		err := au.checkAccountAvailable(ctx, tx, request.Email, request.Username)
+		if errors.Is(err, accounts.ErrAccountExists) {
+			return common.ConcealReason(ctx, ErrRegistrationFailed, err)
+		}
+		if err != nil {
+			return err
+		}
+
 		account, err := au.AccountEntity.SaveAccountEntities(ctx, tx, accountDTO)
+		if errors.Is(err, accounts.ErrAccountExists) {
+			return common.ConcealReason(ctx, ErrRegistrationFailed, err)
+		}
 		if err != nil {
 			return err
 		}
+		au.clearAccountAbsent(ctx, request.Email, request.Username)
 
 		userDto := domain.UserDto{
 			AccountID: account.AccountId,
@@ -122,13 +186,13 @@ func (au *AuthUsecase) ExecuteRegisterUsecase(ctx context.Context, request domai
 		if err := au.UserEntity.SaveUserEntities(ctx, tx, userDto); err != nil {
 			return err
 		}
-
-		res := domain.RegisterResponse{
-			AccountId: account.AccountId,
-			Email:     account.Email,
-			Username:  account.Username,
-			Password:  account.Password,
+		if err := au.recordRegistration(ctx, tx, account, "password"); err != nil {
+			return err
 		}
+		accountId = account.AccountId
+
+		var res domain.RegisterResponse
+		registerResponse.Map(&res, account)
 		boundary.RegisterResponse(res, nil)
 
 		return nil
@@ -136,25 +200,29 @@ func (au *AuthUsecase) ExecuteRegisterUsecase(ctx context.Context, request domai
 
 	err := common.WithExecuteTransactionalManager(ctx, au.DB, fn)
 	if err != nil {
-		log.Println("Transaction failed:", err)
+		common.AuthLog.Errorf("Transaction failed: %v", err)
+		return err
 	}
-	return err
+	au.Analytics.Publish(ctx, analytics.NewEvent(analytics.EventSignedUp, accountId, map[string]interface{}{"provider": "password"}))
+	return nil
 }
 
-func (au *AuthUsecase) ExecuteLogoutUsecase(ctx context.Context, accessToken *string, boundary OutputAuthBoundary) error {
+// ExecuteLogoutUsecase ends the session of the claims the auth middleware verified for the request
+func (au *AuthUsecase) ExecuteLogoutUsecase(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputAuthBoundary) error {
 	fn := func(tx *sql.Tx) error {
-		verify, err := jsonwebtoken.VerifyJWTToken(*accessToken)
-		common.HandleErrorReturn(err)
-
-		err = au.LoginHistoriesEntity.UpdateLoginHistoriesEntities(ctx, tx, domain.LoginHistoriesDto{
-			UserID:    verify.UserId,
-			AccountID: verify.AccountId,
+		err := au.LoginHistoriesEntity.UpdateLoginHistoriesEntities(ctx, tx, domain.LoginHistoriesDto{
+			UserID:    claims.UserId,
+			AccountID: claims.AccountId,
 		})
-		common.HandleErrorReturn(err)
+		if err != nil {
+			return err
+		}
 
-		redisKey := common.StringEncoder(fmt.Sprintf("access_token:%s:%s", verify.AccountId, verify.Email))
-		err = au.Rds.ClearFromRedis(ctx, redisKey)
-		common.HandleErrorReturn(err)
+		redisKey := redisclient.AccessTokenKey.Key(common.StringEncoder(fmt.Sprintf("%d:%s", claims.AccountId, claims.Email)))
+		if err := au.Rds.ClearFromRedis(ctx, redisKey); err != nil {
+			return err
+		}
+		au.endRefreshSession(ctx, claims.SessionId)
 
 		res := domain.LogoutResponse{
 			Message: "User successfully logged out",
@@ -166,7 +234,128 @@ func (au *AuthUsecase) ExecuteLogoutUsecase(ctx context.Context, accessToken *st
 
 	err := common.WithExecuteTransactionalManager(ctx, au.DB, fn)
 	if err != nil {
-		log.Println("Transaction failed:", err)
+		common.AuthLog.Errorf("Transaction failed: %v", err)
 	}
 	return err
 }
+
+// checkAccountAvailable skips the lookup when both values were found free moments ago. Only absence is cached,
+// a stale entry at worst lets the request reach the insert where the unique constraints reject it
+func (au *AuthUsecase) checkAccountAvailable(ctx context.Context, tx *sql.Tx, email string, username string) error {
+	keys := accountAbsentKeys(email, username)
+
+	cached := true
+	for _, key := range keys {
+		if _, err := au.Rds.LoadFromRedis(ctx, key); err != nil {
+			cached = false
+			break
+		}
+	}
+	if cached {
+		return nil
+	}
+
+	if err := au.AccountEntity.CheckAccountAvailableEntities(ctx, tx, email, username); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := au.Rds.StoreToRedis(ctx, key, true); err != nil {
+			common.AuthLog.Warnf("failed to cache absent account: %v", err)
+		}
+	}
+	return nil
+}
+
+func (au *AuthUsecase) clearAccountAbsent(ctx context.Context, email string, username string) {
+	for _, key := range accountAbsentKeys(email, username) {
+		if err := au.Rds.ClearFromRedis(ctx, key); err != nil {
+			common.AuthLog.Warnf("failed to clear absent account: %v", err)
+		}
+	}
+}
+
+// accountAbsentKeys hashes the values so emails are not stored in redis, emails compare case insensitively in mysql
+func accountAbsentKeys(email string, username string) []string {
+	return []string{
+		redisclient.AccountAbsentKey.Key("email", common.StringEncoder(strings.ToLower(strings.TrimSpace(email)))),
+		redisclient.AccountAbsentKey.Key("username", common.StringEncoder(strings.ToLower(strings.TrimSpace(username)))),
+	}
+}
+
+// startSession issues the access token once the account is authenticated, whatever the credential was
+func (au *AuthUsecase) startSession(ctx context.Context, tx *sql.Tx, accountId int64, email string, username string, timezone string) (domain.LoginResponse, error) {
+	user, err := au.UserEntity.FindUserEntities(ctx, tx, accountId)
+	if err != nil {
+		return domain.LoginResponse{}, errors.New("failed to find user")
+	}
+
+	sessionId, refreshToken, err := au.startRefreshSession(ctx, accountId)
+	if err != nil {
+		return domain.LoginResponse{}, err
+	}
+
+	// The region an account first logs in to becomes its home, the chat connections of the account go there
+	var homeRegion string
+	if au.Regions.Enabled() {
+		homeRegion, err = au.AccountEntity.AssignHomeRegionEntity(ctx, tx, accountId, au.Regions.Current)
+		if err != nil {
+			return domain.LoginResponse{}, err
+		}
+	}
+
+	token, err := jsonwebtoken.GenerateJWTToken(user.UserID, accountId, email, sessionId, homeRegion)
+	if err != nil {
+		return domain.LoginResponse{}, errors.New("failed to generate JWT token")
+	}
+
+	// Store to logins history
+	loginDto := domain.LoginHistoriesDto{
+		UserID:    user.UserID,
+		AccountID: accountId,
+	}
+	err = au.LoginHistoriesEntity.SaveLoginHistoriesEntities(ctx, tx, loginDto)
+	common.HandleErrorWithParam(err, "Failed to save login history")
+
+	// Logging in brings a warned or hidden account back to discovery, and cancels the deletion of a deleted one
+	reactivated, err := au.DormancyEntity.ReactivateEntity(ctx, tx, accountId)
+	if err != nil {
+		return domain.LoginResponse{}, errors.New("failed to reactivate account")
+	}
+	if reactivated {
+		common.AuthLog.Infof("Dormant or deleted account %d reactivated by login", accountId)
+	}
+
+	// A streak failure must not block the login itself
+	_, err = au.RewardEntity.RecordLoginEntity(ctx, tx, accountId, timezone)
+	if err != nil {
+		common.AuthLog.Warnf("Failed to record login streak: %v", err)
+	}
+
+	// Store token to redis
+	redisKey := redisclient.AccessTokenKey.Key(common.StringEncoder(fmt.Sprintf("%d:%s", accountId, email)))
+	err = au.Rds.StoreToRedis(ctx, redisKey, token)
+	if err != nil {
+		return domain.LoginResponse{}, errors.New("failed to save token")
+	}
+
+	res := domain.LoginResponse{
+		Username:     username,
+		Email:        email,
+		AccessToken:  token,
+		RefreshToken: refreshToken,
+	}
+	if homeRegion != "" {
+		home := au.Regions.Home(homeRegion)
+		res.Region = &domain.RegionResponse{Name: home.Name, BaseURL: home.BaseURL}
+	}
+	return res, nil
+}
+
+// recordRegistration writes the new account to the outbox, provider is password or the social login provider
+func (au *AuthUsecase) recordRegistration(ctx context.Context, tx *sql.Tx, account domain.Accounts, provider string) error {
+	return au.OutboxEntity.RecordEventEntity(ctx, tx, outbox.EventAccountRegistered, account.AccountId, domain.AccountRegisteredEvent{
+		AccountID: account.AccountId,
+		Username:  account.Username,
+		Provider:  provider,
+	})
+}
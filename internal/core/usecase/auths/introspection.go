@@ -0,0 +1,51 @@
+package auths
+
+import (
+	"context"
+	"errors"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"strconv"
+	"strings"
+)
+
+// ExecuteIntrospectToken lets another service check a token without the signing key. Besides signature and expiry
+// the session must still be alive, so a token of a logged out, reused or recovered session is inactive
+func (au *AuthUsecase) ExecuteIntrospectToken(ctx context.Context, token string, boundary OutputAuthBoundary) error {
+	claims, err := jsonwebtoken.VerifyJWTToken(token)
+	if err != nil {
+		boundary.IntrospectionResponse(domain.IntrospectionResponse{Active: false}, nil)
+		return nil
+	}
+
+	// Tokens issued before sessions existed carry no session id, they can only expire
+	if claims.SessionId != "" {
+		_, err := au.loadRefreshSession(ctx, refreshGrant{AccountID: claims.AccountId, SessionID: claims.SessionId})
+		if errors.Is(err, ErrInvalidRefreshToken) {
+			boundary.IntrospectionResponse(domain.IntrospectionResponse{Active: false}, nil)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	res := domain.IntrospectionResponse{
+		Active:    true,
+		Scope:     strings.Join(claims.GrantedScopes(), " "),
+		TokenType: "Bearer",
+		Sub:       strconv.FormatInt(claims.AccountId, 10),
+		AccountID: claims.AccountId,
+		UserID:    claims.UserId,
+		Email:     claims.Email,
+		SessionID: claims.SessionId,
+	}
+	if claims.ExpiresAt != nil {
+		res.Exp = claims.ExpiresAt.Unix()
+	}
+	if claims.IssuedAt != nil {
+		res.Iat = claims.IssuedAt.Unix()
+	}
+	boundary.IntrospectionResponse(res, nil)
+	return nil
+}
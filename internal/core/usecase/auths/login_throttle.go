@@ -0,0 +1,127 @@
+package auths
+
+import (
+	"context"
+	"errors"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/redisclient"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrLoginThrottled is returned before the credentials are checked, handlers answer it with 429
+var ErrLoginThrottled = errors.New("too many failed login attempts, try again later")
+
+const (
+	DefaultCredentialFailureLimit = 5
+	DefaultAddressFailureLimit    = 30
+)
+
+// loginThrottle counts failed logins in redis on two independent keys. The credential key includes the source
+// address, so failures sent for someone's email from one address never block that person logging in from
+// another, while the address key stops one source from spraying many credentials.
+type loginThrottle struct {
+	rds             redisclient.RedisInterface
+	credentialLimit int64
+	addressLimit    int64
+
+	failuresRecorded    atomic.Int64
+	blockedByCredential atomic.Int64
+	blockedByAddress    atomic.Int64
+	unavailable         atomic.Int64
+}
+
+func newLoginThrottle(rds redisclient.RedisInterface, policy LoginThrottlePolicy) *loginThrottle {
+	return &loginThrottle{
+		rds:             rds,
+		credentialLimit: policy.CredentialLimit,
+		addressLimit:    policy.AddressLimit,
+	}
+}
+
+// Check refuses the attempt once either counter reached its limit. Redis being down fails open,
+// the in memory per address limiter on the route still applies
+func (lt *loginThrottle) Check(ctx context.Context, request domain.LoginRequest) error {
+	credentialKey, addressKey := loginFailureKeys(request)
+
+	failures, err := lt.load(ctx, addressKey)
+	if err != nil {
+		return nil
+	}
+	if failures >= lt.addressLimit {
+		lt.blockedByAddress.Add(1)
+		return ErrLoginThrottled
+	}
+
+	failures, err = lt.load(ctx, credentialKey)
+	if err != nil {
+		return nil
+	}
+	if failures >= lt.credentialLimit {
+		lt.blockedByCredential.Add(1)
+		return ErrLoginThrottled
+	}
+	return nil
+}
+
+// RecordFailure counts a rejected attempt on both keys
+func (lt *loginThrottle) RecordFailure(ctx context.Context, request domain.LoginRequest) {
+	credentialKey, addressKey := loginFailureKeys(request)
+	lt.failuresRecorded.Add(1)
+	for _, key := range []string{credentialKey, addressKey} {
+		if _, err := lt.rds.IncrementInRedis(ctx, key); err != nil {
+			lt.unavailable.Add(1)
+			common.AuthLog.Warnf("failed to record login failure: %v", err)
+		}
+	}
+}
+
+// RecordSuccess forgets the credential failures from this address, the address counter keeps running
+func (lt *loginThrottle) RecordSuccess(ctx context.Context, request domain.LoginRequest) {
+	credentialKey, _ := loginFailureKeys(request)
+	if err := lt.rds.ClearFromRedis(ctx, credentialKey); err != nil {
+		common.AuthLog.Warnf("failed to clear login failures: %v", err)
+	}
+}
+
+func (lt *loginThrottle) Metrics() domain.LoginThrottleMetrics {
+	return domain.LoginThrottleMetrics{
+		FailuresRecorded:    lt.failuresRecorded.Load(),
+		BlockedByCredential: lt.blockedByCredential.Load(),
+		BlockedByAddress:    lt.blockedByAddress.Load(),
+		CredentialLimit:     lt.credentialLimit,
+		AddressLimit:        lt.addressLimit,
+		WindowSeconds:       int64(redisclient.LoginFailureCredentialKey.TTL.Seconds()),
+		ThrottleUnavailable: lt.unavailable.Load(),
+	}
+}
+
+// load reads a counter, a missing key is zero
+func (lt *loginThrottle) load(ctx context.Context, key string) (int64, error) {
+	value, err := lt.rds.LoadFromRedis(ctx, key)
+	if err != nil {
+		if errors.Is(err, redisclient.ErrKeyNotFound) {
+			return 0, nil
+		}
+		lt.unavailable.Add(1)
+		common.AuthLog.Warnf("failed to load login failures: %v", err)
+		return 0, err
+	}
+	count, ok := value.(float64)
+	if !ok {
+		return 0, nil
+	}
+	return int64(count), nil
+}
+
+// loginFailureKeys hashes the credential so emails are not stored in redis
+func loginFailureKeys(request domain.LoginRequest) (string, string) {
+	credential := request.Email
+	if credential == "" {
+		credential = request.Username
+	}
+	credential = strings.ToLower(strings.TrimSpace(credential))
+	return redisclient.LoginFailureCredentialKey.Key(common.StringEncoder(credential), request.ClientAddress),
+		redisclient.LoginFailureAddressKey.Key(request.ClientAddress)
+}
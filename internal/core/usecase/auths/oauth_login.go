@@ -0,0 +1,206 @@
+package auths
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/accounts"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/analytics"
+	"godating-dealls/internal/infra/oauth"
+	"godating-dealls/internal/infra/redisclient"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const oauthUsernameAttempts = 5
+
+var (
+	// ErrOAuthProviderUnknown the provider is not supported or has no client id configured
+	ErrOAuthProviderUnknown = errors.New("unknown sign in provider")
+	// ErrOAuthEmailRequired the provider did not share a verified email, an account is only created or linked by one
+	ErrOAuthEmailRequired = errors.New("the sign in provider did not share a verified email")
+)
+
+// ExecuteOAuthLogin signs in with the ID token of Google or Facebook. The first sign in links the account that has the
+// verified email of the provider, or creates one, later ones find the account by the provider subject
+func (au *AuthUsecase) ExecuteOAuthLogin(ctx context.Context, provider string, request domain.OAuthLoginRequest, boundary OutputAuthBoundary) error {
+	verifier, err := au.OAuth.Find(provider)
+	if err != nil {
+		return ErrOAuthProviderUnknown
+	}
+	// The keys of the provider are fetched outside of the transaction
+	identity, err := verifier.VerifyIDToken(ctx, request.IDToken, request.Nonce)
+	if err != nil {
+		common.AuthLog.Warnf("Rejected %s id token: %v", provider, err)
+		return oauth.ErrInvalidIDToken
+	}
+
+	var accountId int64
+	newAccount := false
+	fn := func(tx *sql.Tx) error {
+		account, err := au.AccountEntity.FindAccountByProviderEntity(ctx, tx, identity.Provider, identity.Subject)
+		if errors.Is(err, accounts.ErrAccountNotFound) {
+			if identity.Email == "" || !identity.EmailVerified {
+				return ErrOAuthEmailRequired
+			}
+			account, err = au.AccountEntity.FindAccountByEmailEntity(ctx, tx, identity.Email)
+			switch {
+			case err == nil:
+				err = au.linkOAuthAccount(ctx, tx, account, identity)
+			case errors.Is(err, accounts.ErrAccountNotFound):
+				account, err = au.createOAuthAccount(ctx, tx, identity)
+				newAccount = true
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		res, err := au.startSession(ctx, tx, account.AccountId, account.Email, account.Username, request.Timezone)
+		if err != nil {
+			return err
+		}
+		res.NewAccount = newAccount
+		accountId = account.AccountId
+		boundary.LoginResponse(res, nil)
+		return nil
+	}
+
+	err = common.WithExecuteTransactionalManager(ctx, au.DB, fn)
+	if err != nil {
+		common.AuthLog.Errorf("Transaction failed: %v", err)
+		return err
+	}
+	if newAccount {
+		au.Analytics.Publish(ctx, analytics.NewEvent(analytics.EventSignedUp, accountId, map[string]interface{}{"provider": identity.Provider}))
+	}
+	au.Analytics.Publish(ctx, analytics.NewEvent(analytics.EventLoggedIn, accountId, map[string]interface{}{"method": identity.Provider}))
+	return nil
+}
+
+// linkOAuthAccount links the account that registered the email with a password. Nobody proved owning that email to
+// this service, whoever registered it first could be waiting for the owner to link it, so the password is replaced
+// and the sessions are ended. The owner resets the password to log in with one again
+func (au *AuthUsecase) linkOAuthAccount(ctx context.Context, tx *sql.Tx, account domain.Accounts, identity oauth.Identity) error {
+	if err := au.AccountEntity.LinkAccountProviderEntity(ctx, tx, account.AccountId, identity.Provider, identity.Subject); err != nil {
+		return err
+	}
+
+	redisKey := redisclient.AccessTokenKey.Key(common.StringEncoder(fmt.Sprintf("%d:%s", account.AccountId, account.Email)))
+	if err := au.Rds.ClearFromRedis(ctx, redisKey); err != nil {
+		common.AuthLog.Warnf("Failed to clear access token after linking %s: %v", identity.Provider, err)
+	}
+	// Revoked before the password is hashed, the session started afterwards is later than the revocation
+	revokedKey := redisclient.RefreshRevokedKey.Key(strconv.FormatInt(account.AccountId, 10))
+	if err := au.Rds.StoreToRedis(ctx, revokedKey, time.Now().UnixMilli()); err != nil {
+		common.AuthLog.Warnf("Failed to revoke refresh tokens after linking %s: %v", identity.Provider, err)
+	}
+
+	password, err := randomPassword()
+	if err != nil {
+		return err
+	}
+	if err := au.AccountEntity.UpdateAccountPassword(ctx, tx, account.AccountId, password); err != nil {
+		return err
+	}
+	common.AuthLog.Infof("Account %d linked to %s", account.AccountId, identity.Provider)
+	return nil
+}
+
+// createOAuthAccount creates the account with a username derived from the email and a password nobody knows
+func (au *AuthUsecase) createOAuthAccount(ctx context.Context, tx *sql.Tx, identity oauth.Identity) (domain.Accounts, error) {
+	password, err := randomPassword()
+	if err != nil {
+		return domain.Accounts{}, err
+	}
+
+	var account domain.Accounts
+	for attempt := 0; ; attempt++ {
+		username, err := oauthUsername(identity.Email)
+		if err != nil {
+			return domain.Accounts{}, err
+		}
+		err = au.AccountEntity.CheckAccountAvailableEntities(ctx, tx, "", username)
+		if err == nil {
+			account, err = au.AccountEntity.SaveAccountEntities(ctx, tx, domain.SignUpAccountDto{
+				Username: username,
+				Password: password,
+				Email:    identity.Email,
+			})
+		}
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, accounts.ErrAccountExists) || attempt+1 == oauthUsernameAttempts {
+			return domain.Accounts{}, err
+		}
+	}
+	au.clearAccountAbsent(ctx, account.Email, account.Username)
+
+	if err := au.AccountEntity.LinkAccountProviderEntity(ctx, tx, account.AccountId, identity.Provider, identity.Subject); err != nil {
+		return domain.Accounts{}, err
+	}
+
+	// The profile only has the name the provider shared, the app asks for the rest
+	userDto := domain.UserDto{
+		AccountID: account.AccountId,
+		FullName:  &identity.Name,
+	}
+	if err := au.UserEntity.SaveUserEntities(ctx, tx, userDto); err != nil {
+		return domain.Accounts{}, err
+	}
+	if err := au.recordRegistration(ctx, tx, account, identity.Provider); err != nil {
+		return domain.Accounts{}, err
+	}
+	common.AuthLog.Infof("Account %d created by %s sign in", account.AccountId, identity.Provider)
+	return account, nil
+}
+
+// oauthUsername is the local part of the email reduced to the username characters with a random number, e.g.
+// jane_doe_4821 for Jane.Doe+dating@gmail.com
+func oauthUsername(email string) (string, error) {
+	local := strings.ToLower(strings.SplitN(strings.SplitN(email, "@", 2)[0], "+", 2)[0])
+	var base strings.Builder
+	for _, char := range local {
+		switch {
+		case char >= 'a' && char <= 'z', char >= '0' && char <= '9':
+			base.WriteRune(char)
+		case char == '_' || char == '.':
+			if base.Len() > 0 {
+				base.WriteRune('_')
+			}
+		}
+	}
+	name := base.String()
+	if len(name) > 20 {
+		name = name[:20]
+	}
+	name = strings.TrimRight(name, "_")
+
+	number, err := rand.Int(rand.Reader, big.NewInt(10000))
+	if err != nil {
+		return "", errors.New("failed to generate username")
+	}
+	username := fmt.Sprintf("%s_%04d", name, number.Int64())
+	// Too short, or reserved such as admin_1234
+	if !common.IsSafeUsername(username) {
+		username = fmt.Sprintf("user_%04d", number.Int64())
+	}
+	return username, nil
+}
+
+// randomPassword is the password of an account that signs in with a provider, it is never shown
+func randomPassword() (string, error) {
+	value := make([]byte, 24)
+	if _, err := rand.Read(value); err != nil {
+		return "", errors.New("failed to generate password")
+	}
+	return hex.EncodeToString(value), nil
+}
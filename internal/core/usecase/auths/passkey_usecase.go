@@ -0,0 +1,333 @@
+package auths
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/analytics"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"godating-dealls/internal/infra/redisclient"
+	"godating-dealls/internal/infra/webauthn"
+)
+
+const (
+	passkeyCeremonyRegister = "register"
+	passkeyCeremonyLogin    = "login"
+)
+
+// passkeyCeremony is stored under the challenge until the ceremony finishes, a challenge is used once
+type passkeyCeremony struct {
+	Purpose   string `json:"purpose"`
+	AccountID int64  `json:"account_id"`
+}
+
+func (au *AuthUsecase) ExecutePasskeyRegisterOptions(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputAuthBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		account, err := au.AccountEntity.FindAccountDetails(ctx, tx, claims.AccountId)
+		if err != nil {
+			return err
+		}
+
+		// Listing the existing passkeys stops an authenticator from registering the same one twice
+		existing, err := au.PasskeyEntity.FindPasskeysEntity(ctx, tx, claims.AccountId)
+		if err != nil {
+			return err
+		}
+		exclude := make([]domain.PasskeyCredentialDescriptor, 0, len(existing))
+		for _, passkey := range existing {
+			exclude = append(exclude, domain.PasskeyCredentialDescriptor{Type: "public-key", ID: webauthn.Encode(passkey.CredentialID)})
+		}
+
+		challenge, err := au.beginPasskeyCeremony(ctx, passkeyCeremony{Purpose: passkeyCeremonyRegister, AccountID: claims.AccountId})
+		if err != nil {
+			return err
+		}
+
+		params := make([]domain.PasskeyCredentialParam, 0, len(webauthn.SupportedAlgorithms))
+		for _, alg := range webauthn.SupportedAlgorithms {
+			params = append(params, domain.PasskeyCredentialParam{Type: "public-key", Alg: alg})
+		}
+
+		boundary.PasskeyCreationOptionsResponse(domain.PasskeyCreationOptions{
+			Challenge: challenge,
+			RP:        domain.PasskeyRelyingParty{ID: au.WebAuthn.RPID, Name: au.WebAuthn.RPName},
+			User: domain.PasskeyUser{
+				ID:          webauthn.Encode(passkeyUserHandle(account.AccountId)),
+				Name:        account.Username,
+				DisplayName: account.Username,
+			},
+			PubKeyCredParams:   params,
+			Timeout:            redisclient.PasskeyChallengeKey.TTL.Milliseconds(),
+			ExcludeCredentials: exclude,
+			AuthenticatorSelection: domain.PasskeyAuthenticatorSelection{
+				ResidentKey:      "required",
+				UserVerification: "required",
+			},
+			Attestation: "none",
+		}, nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, au.DB, fn)
+	if err != nil {
+		common.AuthLog.Errorf("Transaction failed: %v", err)
+	}
+	return err
+}
+
+func (au *AuthUsecase) ExecutePasskeyRegister(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.PasskeyRegisterRequest, boundary OutputAuthBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		clientDataJSON, err := webauthn.Decode(request.Credential.Response.ClientDataJSON)
+		if err != nil {
+			return errors.New("invalid client data")
+		}
+		attestationObject, err := webauthn.Decode(request.Credential.Response.AttestationObject)
+		if err != nil {
+			return errors.New("invalid attestation object")
+		}
+
+		challenge, ceremony, err := au.takePasskeyCeremony(ctx, clientDataJSON)
+		if err != nil {
+			return err
+		}
+		if ceremony.Purpose != passkeyCeremonyRegister || ceremony.AccountID != claims.AccountId {
+			return errors.New("passkey challenge was not issued for this registration")
+		}
+
+		credential, err := au.WebAuthn.VerifyRegistration(challenge, clientDataJSON, attestationObject)
+		if err != nil {
+			return err
+		}
+		if request.Credential.RawID != "" && request.Credential.RawID != webauthn.Encode(credential.ID) {
+			return errors.New("credential id mismatch")
+		}
+
+		name := request.Name
+		if name == "" {
+			name = "Passkey"
+		}
+		passkey, err := au.PasskeyEntity.SavePasskeyEntity(ctx, tx, domain.PasskeyDto{
+			CredentialID: credential.ID,
+			AccountID:    claims.AccountId,
+			Name:         name,
+			PublicKey:    credential.PublicKey,
+			SignCount:    credential.SignCount,
+		})
+		if err != nil {
+			return err
+		}
+
+		boundary.PasskeyResponse(toPasskeyResponse(passkey), nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, au.DB, fn)
+	if err != nil {
+		common.AuthLog.Errorf("Transaction failed: %v", err)
+	}
+	return err
+}
+
+func (au *AuthUsecase) ExecutePasskeyLoginOptions(ctx context.Context, boundary OutputAuthBoundary) error {
+	challenge, err := au.beginPasskeyCeremony(ctx, passkeyCeremony{Purpose: passkeyCeremonyLogin})
+	if err != nil {
+		common.AuthLog.Warnf("Failed to begin passkey login: %v", err)
+		return err
+	}
+
+	boundary.PasskeyRequestOptionsResponse(domain.PasskeyRequestOptions{
+		Challenge:        challenge,
+		RPID:             au.WebAuthn.RPID,
+		Timeout:          redisclient.PasskeyChallengeKey.TTL.Milliseconds(),
+		UserVerification: "required",
+	}, nil)
+	return nil
+}
+
+// ExecutePasskeyLogin is the passwordless alternative to ExecuteLoginUsecase, it ends in the same session
+func (au *AuthUsecase) ExecutePasskeyLogin(ctx context.Context, request domain.PasskeyLoginRequest, boundary OutputAuthBoundary) error {
+	var accountId int64
+	fn := func(tx *sql.Tx) error {
+		response := request.Credential.Response
+		clientDataJSON, err := webauthn.Decode(response.ClientDataJSON)
+		if err != nil {
+			return errors.New("invalid client data")
+		}
+		authenticatorData, err := webauthn.Decode(response.AuthenticatorData)
+		if err != nil {
+			return errors.New("invalid authenticator data")
+		}
+		signature, err := webauthn.Decode(response.Signature)
+		if err != nil {
+			return errors.New("invalid signature")
+		}
+		credentialId, err := webauthn.Decode(request.Credential.RawID)
+		if err != nil || len(credentialId) == 0 {
+			return errors.New("invalid credential id")
+		}
+
+		challenge, ceremony, err := au.takePasskeyCeremony(ctx, clientDataJSON)
+		if err != nil {
+			return err
+		}
+		if ceremony.Purpose != passkeyCeremonyLogin {
+			return errors.New("passkey challenge was not issued for a login")
+		}
+
+		passkey, err := au.PasskeyEntity.FindPasskeyEntity(ctx, tx, credentialId)
+		if err != nil {
+			return common.ConcealReason(ctx, ErrInvalidCredentials, fmt.Errorf("%w: %v", ErrInvalidCredentials, err))
+		}
+		if response.UserHandle != "" && response.UserHandle != webauthn.Encode(passkeyUserHandle(passkey.AccountID)) {
+			return common.ConcealReason(ctx, ErrInvalidCredentials, fmt.Errorf("%w: passkey user handle mismatch", ErrInvalidCredentials))
+		}
+
+		signCount, err := au.WebAuthn.VerifyAssertion(challenge, webauthn.Credential{
+			ID:        passkey.CredentialID,
+			PublicKey: passkey.PublicKey,
+			SignCount: passkey.SignCount,
+		}, clientDataJSON, authenticatorData, signature)
+		if err != nil {
+			return common.ConcealReason(ctx, ErrInvalidCredentials, fmt.Errorf("%w: %v", ErrInvalidCredentials, err))
+		}
+		err = au.PasskeyEntity.UpdatePasskeySignCountEntity(ctx, tx, passkey.CredentialID, signCount)
+		if err != nil {
+			return err
+		}
+
+		account, err := au.AccountEntity.FindAccountDetails(ctx, tx, passkey.AccountID)
+		if err != nil {
+			return err
+		}
+		res, err := au.startSession(ctx, tx, account.AccountId, account.Email, account.Username, request.Timezone)
+		if err != nil {
+			return err
+		}
+		accountId = account.AccountId
+
+		boundary.LoginResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, au.DB, fn)
+	if err != nil {
+		common.AuthLog.Errorf("Transaction failed: %v", err)
+		return err
+	}
+	au.Analytics.Publish(ctx, analytics.NewEvent(analytics.EventLoggedIn, accountId, map[string]interface{}{"method": "passkey"}))
+	return nil
+}
+
+func (au *AuthUsecase) ExecuteFetchPasskeys(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputAuthBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		passkeys, err := au.PasskeyEntity.FindPasskeysEntity(ctx, tx, claims.AccountId)
+		if err != nil {
+			return err
+		}
+
+		res := make([]domain.PasskeyResponse, 0, len(passkeys))
+		for _, passkey := range passkeys {
+			res = append(res, toPasskeyResponse(passkey))
+		}
+		boundary.PasskeysResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, au.DB, fn)
+	if err != nil {
+		common.AuthLog.Errorf("Transaction failed: %v", err)
+	}
+	return err
+}
+
+func (au *AuthUsecase) ExecuteDeletePasskey(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, credentialId string, boundary OutputAuthBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		rawId, err := webauthn.Decode(credentialId)
+		if err != nil || len(rawId) == 0 {
+			return errors.New("invalid credential id")
+		}
+
+		err = au.PasskeyEntity.DeletePasskeyEntity(ctx, tx, claims.AccountId, rawId)
+		if err != nil {
+			return err
+		}
+
+		boundary.DeletePasskeyResponse(domain.DeletePasskeyResponse{
+			CredentialID: credentialId,
+			Message:      "Passkey removed",
+		}, nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, au.DB, fn)
+	if err != nil {
+		common.AuthLog.Errorf("Transaction failed: %v", err)
+	}
+	return err
+}
+
+// beginPasskeyCeremony issues a challenge and remembers what it was issued for, it returns the encoded challenge
+func (au *AuthUsecase) beginPasskeyCeremony(ctx context.Context, ceremony passkeyCeremony) (string, error) {
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return "", errors.New("failed to create passkey challenge")
+	}
+
+	encoded := webauthn.Encode(challenge)
+	err = au.Rds.StoreToRedis(ctx, redisclient.PasskeyChallengeKey.Key(encoded), ceremony)
+	if err != nil {
+		return "", errors.New("failed to save passkey challenge")
+	}
+	return encoded, nil
+}
+
+// takePasskeyCeremony finds the ceremony by the challenge the authenticator signed and consumes it
+func (au *AuthUsecase) takePasskeyCeremony(ctx context.Context, clientDataJSON []byte) ([]byte, passkeyCeremony, error) {
+	clientData, err := webauthn.ParseClientData(clientDataJSON)
+	if err != nil {
+		return nil, passkeyCeremony{}, err
+	}
+	challenge, err := webauthn.Decode(clientData.Challenge)
+	if err != nil || len(challenge) == 0 {
+		return nil, passkeyCeremony{}, errors.New("invalid passkey challenge")
+	}
+
+	value, err := au.Rds.TakeFromRedis(ctx, redisclient.PasskeyChallengeKey.Key(webauthn.Encode(challenge)))
+	if err != nil {
+		return nil, passkeyCeremony{}, errors.New("passkey challenge expired or already used")
+	}
+	stored, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, passkeyCeremony{}, errors.New("invalid passkey challenge")
+	}
+
+	var ceremony passkeyCeremony
+	ceremony.Purpose, _ = stored["purpose"].(string)
+	if accountId, ok := stored["account_id"].(float64); ok {
+		ceremony.AccountID = int64(accountId)
+	}
+	return challenge, ceremony, nil
+}
+
+// passkeyUserHandle is the opaque WebAuthn user id, the account id as 8 bytes
+func passkeyUserHandle(accountId int64) []byte {
+	handle := make([]byte, 8)
+	binary.BigEndian.PutUint64(handle, uint64(accountId))
+	return handle
+}
+
+func toPasskeyResponse(passkey domain.PasskeyDto) domain.PasskeyResponse {
+	res := domain.PasskeyResponse{
+		CredentialID: webauthn.Encode(passkey.CredentialID),
+		Name:         passkey.Name,
+		CreatedAt:    common.FormatTimeByParam(passkey.CreatedAt),
+	}
+	if passkey.LastUsedAt != nil {
+		res.LastUsedAt = common.FormatTimeByParam(*passkey.LastUsedAt)
+	}
+	return res
+}
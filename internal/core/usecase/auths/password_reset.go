@@ -0,0 +1,146 @@
+package auths
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/notifier"
+	"godating-dealls/internal/infra/redisclient"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const forgotPasswordMessage = "If an account uses this email a password reset token was sent to it"
+
+// ErrInvalidPasswordResetToken is the same for an unknown, expired, used or replaced reset token
+var ErrInvalidPasswordResetToken = errors.New("invalid or expired password reset token, request a new one")
+
+// ExecuteForgotPassword answers the same way whether or not an account uses the email. The token is created and sent
+// in the background so the response time does not tell either, a new token replaces the previous one of the account
+func (au *AuthUsecase) ExecuteForgotPassword(ctx context.Context, request domain.ForgotPasswordRequest, boundary OutputAuthBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		account, err := au.AccountEntity.AuthenticateAccount(ctx, tx, domain.AccountDto{Email: &request.Email})
+		if err != nil || account.AccountId == 0 {
+			common.AuthLog.Infof("Password reset not sent: %v", err)
+		} else {
+			go au.sendPasswordReset(context.WithoutCancel(ctx), account.AccountId, account.Email)
+		}
+
+		boundary.PasswordResetResponse(domain.PasswordResetResponse{Message: forgotPasswordMessage}, nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, au.DB, fn)
+	if err != nil {
+		common.AuthLog.Errorf("Transaction failed: %v", err)
+	}
+	return err
+}
+
+// ExecuteResetPassword sets the new password and ends every session of the account, like a completed recovery
+func (au *AuthUsecase) ExecuteResetPassword(ctx context.Context, request domain.ResetPasswordRequest, boundary OutputAuthBoundary) error {
+	tokenHash := hashPasswordResetToken(request.Token)
+	tokenKey := redisclient.PasswordResetKey.Key(tokenHash)
+
+	value, err := au.Rds.LoadFromRedis(ctx, tokenKey)
+	if err != nil {
+		if errors.Is(err, redisclient.ErrKeyNotFound) {
+			return ErrInvalidPasswordResetToken
+		}
+		return errors.New("failed to load password reset token")
+	}
+	storedAccountId, ok := value.(float64)
+	if !ok {
+		return ErrInvalidPasswordResetToken
+	}
+	accountId := int64(storedAccountId)
+
+	accountKey := redisclient.PasswordResetAccountKey.Key(strconv.FormatInt(accountId, 10))
+	latest, err := au.Rds.LoadFromRedis(ctx, accountKey)
+	if err != nil || latest != tokenHash {
+		return ErrInvalidPasswordResetToken
+	}
+
+	fn := func(tx *sql.Tx) error {
+		err := au.AccountEntity.UpdateAccountPassword(ctx, tx, accountId, request.NewPassword)
+		if err != nil {
+			return err
+		}
+
+		// Take the token only after the password was accepted, a rejected password leaves it usable.
+		// Of two resets racing with the same token only the one that takes it commits
+		if _, err := au.Rds.TakeFromRedis(ctx, tokenKey); err != nil {
+			if errors.Is(err, redisclient.ErrKeyNotFound) {
+				return ErrInvalidPasswordResetToken
+			}
+			return errors.New("failed to use password reset token")
+		}
+		if err := au.Rds.ClearFromRedis(ctx, accountKey); err != nil {
+			common.AuthLog.Warnf("Failed to clear password reset of account: %v", err)
+		}
+
+		account, err := au.AccountEntity.FindAccountDetails(ctx, tx, accountId)
+		if err != nil {
+			return err
+		}
+		redisKey := redisclient.AccessTokenKey.Key(common.StringEncoder(fmt.Sprintf("%d:%s", account.AccountId, account.Email)))
+		if err := au.Rds.ClearFromRedis(ctx, redisKey); err != nil {
+			common.AuthLog.Warnf("Failed to clear access token after password reset: %v", err)
+		}
+		revokedKey := redisclient.RefreshRevokedKey.Key(strconv.FormatInt(account.AccountId, 10))
+		if err := au.Rds.StoreToRedis(ctx, revokedKey, time.Now().UnixMilli()); err != nil {
+			common.AuthLog.Warnf("Failed to revoke refresh tokens after password reset: %v", err)
+		}
+
+		boundary.PasswordResetResponse(domain.PasswordResetResponse{Message: "Password reset, login with your new password"}, nil)
+		return nil
+	}
+
+	err = common.WithExecuteTransactionalManager(ctx, au.DB, fn)
+	if err != nil {
+		common.AuthLog.Errorf("Transaction failed: %v", err)
+	}
+	return err
+}
+
+// sendPasswordReset stores only the hash of the token, the token itself only reaches the notifier
+func (au *AuthUsecase) sendPasswordReset(ctx context.Context, accountId int64, email string) {
+	token, err := randomRefreshValue(32)
+	if err != nil {
+		common.AuthLog.Warnf("Failed to create password reset token: %v", err)
+		return
+	}
+	tokenHash := hashPasswordResetToken(token)
+
+	if err := au.Rds.StoreToRedis(ctx, redisclient.PasswordResetKey.Key(tokenHash), accountId); err != nil {
+		common.AuthLog.Warnf("Failed to store password reset token: %v", err)
+		return
+	}
+	if err := au.Rds.StoreToRedis(ctx, redisclient.PasswordResetAccountKey.Key(strconv.FormatInt(accountId, 10)), tokenHash); err != nil {
+		common.AuthLog.Warnf("Failed to store password reset token: %v", err)
+		return
+	}
+
+	body := fmt.Sprintf("Use this token to reset your password, it expires in %d minutes and works once:\n\n%s\n",
+		int(redisclient.PasswordResetKey.TTL.Minutes()), token)
+	if resetURL := au.Policy.PasswordResetURL; resetURL != "" {
+		body += fmt.Sprintf("\nOr open %s?token=%s\n", resetURL, url.QueryEscape(token))
+	}
+	body += "\nIf you did not ask for it you can ignore this message, your password stays the same.\n"
+
+	err = au.Notifier.Notify(ctx, notifier.Message{To: email, Subject: "Reset your password", Body: body})
+	if err != nil {
+		common.AuthLog.Warnf("Failed to send password reset: %v", err)
+	}
+}
+
+func hashPasswordResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,211 @@
+package auths
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"godating-dealls/internal/infra/redisclient"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidRefreshToken is the same for an unknown, expired, used or revoked refresh token
+var ErrInvalidRefreshToken = errors.New("invalid refresh token, login again")
+
+// refreshGrant is stored under the hash of an unused refresh token, only the hash ever reaches redis
+type refreshGrant struct {
+	AccountID int64  `json:"account_id"`
+	SessionID string `json:"session_id"`
+}
+
+// refreshSession points to the one refresh token of the session that may still be exchanged
+type refreshSession struct {
+	TokenHash string `json:"token_hash"`
+	StartedAt int64  `json:"started_at"`
+}
+
+// ExecuteRefreshTokenUsecase rotates the refresh token: the presented one is used up and a new pair is issued.
+// A refresh token presented a second time means it was copied, the whole session is revoked then
+func (au *AuthUsecase) ExecuteRefreshTokenUsecase(ctx context.Context, request domain.RefreshTokenRequest, boundary OutputAuthBoundary) error {
+	tokenHash := hashRefreshToken(request.RefreshToken)
+
+	value, err := au.Rds.TakeFromRedis(ctx, redisclient.RefreshTokenKey.Key(tokenHash))
+	if err != nil {
+		if errors.Is(err, redisclient.ErrKeyNotFound) {
+			au.revokeReusedRefreshToken(ctx, tokenHash)
+			return ErrInvalidRefreshToken
+		}
+		return errors.New("failed to load refresh token")
+	}
+	stored, ok := value.(map[string]interface{})
+	if !ok {
+		return ErrInvalidRefreshToken
+	}
+	var grant refreshGrant
+	grant.SessionID, _ = stored["session_id"].(string)
+	if accountId, ok := stored["account_id"].(float64); ok {
+		grant.AccountID = int64(accountId)
+	}
+
+	// Remember the token as used before anything else, so a copy presented later revokes the session
+	err = au.Rds.StoreToRedis(ctx, redisclient.RefreshTokenUsedKey.Key(tokenHash), grant.SessionID)
+	if err != nil {
+		common.AuthLog.Warnf("failed to remember used refresh token: %v", err)
+	}
+
+	session, err := au.loadRefreshSession(ctx, grant)
+	if err != nil {
+		return err
+	}
+	if session.TokenHash != tokenHash {
+		return ErrInvalidRefreshToken
+	}
+
+	fn := func(tx *sql.Tx) error {
+		account, err := au.AccountEntity.FindAccountDetails(ctx, tx, grant.AccountID)
+		if err != nil {
+			return err
+		}
+		user, err := au.UserEntity.FindUserEntities(ctx, tx, grant.AccountID)
+		if err != nil {
+			return errors.New("failed to find user")
+		}
+
+		var homeRegion string
+		if au.Regions.Enabled() {
+			if homeRegion, err = au.AccountEntity.FindHomeRegionEntity(ctx, tx, account.AccountId); err != nil {
+				return err
+			}
+		}
+
+		accessToken, err := jsonwebtoken.GenerateJWTToken(user.UserID, account.AccountId, account.Email, grant.SessionID, homeRegion)
+		if err != nil {
+			return errors.New("failed to generate JWT token")
+		}
+		refreshToken, err := au.issueRefreshToken(ctx, account.AccountId, grant.SessionID, session.StartedAt)
+		if err != nil {
+			return err
+		}
+
+		redisKey := redisclient.AccessTokenKey.Key(common.StringEncoder(fmt.Sprintf("%d:%s", account.AccountId, account.Email)))
+		if err := au.Rds.StoreToRedis(ctx, redisKey, accessToken); err != nil {
+			return errors.New("failed to save token")
+		}
+
+		boundary.RefreshTokenResponse(domain.RefreshTokenResponse{
+			AccessToken:  accessToken,
+			RefreshToken: refreshToken,
+		}, nil)
+		return nil
+	}
+
+	err = common.WithReadOnlyTransactionManager(ctx, au.DB, fn)
+	if err != nil {
+		common.AuthLog.Errorf("Transaction failed: %v", err)
+	}
+	return err
+}
+
+// startRefreshSession opens a new login session and returns its id with the first refresh token
+func (au *AuthUsecase) startRefreshSession(ctx context.Context, accountId int64) (string, string, error) {
+	sessionId, err := randomRefreshValue(16)
+	if err != nil {
+		return "", "", errors.New("failed to start session")
+	}
+	refreshToken, err := au.issueRefreshToken(ctx, accountId, sessionId, time.Now().UnixMilli())
+	if err != nil {
+		return "", "", err
+	}
+	return sessionId, refreshToken, nil
+}
+
+// issueRefreshToken stores a new refresh token for the session and makes it the only one that can be exchanged
+func (au *AuthUsecase) issueRefreshToken(ctx context.Context, accountId int64, sessionId string, startedAt int64) (string, error) {
+	refreshToken, err := randomRefreshValue(32)
+	if err != nil {
+		return "", errors.New("failed to generate refresh token")
+	}
+	tokenHash := hashRefreshToken(refreshToken)
+
+	err = au.Rds.StoreToRedis(ctx, redisclient.RefreshTokenKey.Key(tokenHash), refreshGrant{AccountID: accountId, SessionID: sessionId})
+	if err != nil {
+		return "", errors.New("failed to save refresh token")
+	}
+	err = au.Rds.StoreToRedis(ctx, redisclient.RefreshSessionKey.Key(sessionId), refreshSession{TokenHash: tokenHash, StartedAt: startedAt})
+	if err != nil {
+		return "", errors.New("failed to save refresh token")
+	}
+	return refreshToken, nil
+}
+
+// loadRefreshSession fails for a session ended by logout, by reuse of a token or by an account recovery after it started
+func (au *AuthUsecase) loadRefreshSession(ctx context.Context, grant refreshGrant) (refreshSession, error) {
+	value, err := au.Rds.LoadFromRedis(ctx, redisclient.RefreshSessionKey.Key(grant.SessionID))
+	if err != nil {
+		if errors.Is(err, redisclient.ErrKeyNotFound) {
+			return refreshSession{}, ErrInvalidRefreshToken
+		}
+		return refreshSession{}, errors.New("failed to load session")
+	}
+	stored, ok := value.(map[string]interface{})
+	if !ok {
+		return refreshSession{}, ErrInvalidRefreshToken
+	}
+	var session refreshSession
+	session.TokenHash, _ = stored["token_hash"].(string)
+	if startedAt, ok := stored["started_at"].(float64); ok {
+		session.StartedAt = int64(startedAt)
+	}
+
+	revoked, err := au.Rds.LoadFromRedis(ctx, redisclient.RefreshRevokedKey.Key(strconv.FormatInt(grant.AccountID, 10)))
+	if err == nil {
+		if revokedAt, ok := revoked.(float64); ok && session.StartedAt <= int64(revokedAt) {
+			return refreshSession{}, ErrInvalidRefreshToken
+		}
+	} else if !errors.Is(err, redisclient.ErrKeyNotFound) {
+		return refreshSession{}, errors.New("failed to load session")
+	}
+	return session, nil
+}
+
+// endRefreshSession makes every refresh token of the session unusable
+func (au *AuthUsecase) endRefreshSession(ctx context.Context, sessionId string) {
+	if sessionId == "" {
+		return
+	}
+	if err := au.Rds.ClearFromRedis(ctx, redisclient.RefreshSessionKey.Key(sessionId)); err != nil {
+		common.AuthLog.Warnf("failed to end refresh session: %v", err)
+	}
+}
+
+func (au *AuthUsecase) revokeReusedRefreshToken(ctx context.Context, tokenHash string) {
+	value, err := au.Rds.LoadFromRedis(ctx, redisclient.RefreshTokenUsedKey.Key(tokenHash))
+	if err != nil {
+		return
+	}
+	if sessionId, ok := value.(string); ok {
+		common.AuthLog.Warnf("Refresh token reused, revoking session %s", sessionId)
+		au.endRefreshSession(ctx, sessionId)
+	}
+}
+
+func hashRefreshToken(refreshToken string) string {
+	sum := sha256.Sum256([]byte(refreshToken))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomRefreshValue(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,37 @@
+package auths
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/accounts"
+	"godating-dealls/internal/domain"
+)
+
+// ExecuteRegionLookup tells the app where to log in before it has a token, the home region of the login or the
+// region nearest to the client for an unknown login or none. Both answers look the same, a login homed in the
+// nearest region is indistinguishable from an unknown one
+func (au *AuthUsecase) ExecuteRegionLookup(ctx context.Context, login string, address string, boundary OutputAuthBoundary) error {
+	region := au.Regions.Nearest(ctx, address)
+	if login != "" {
+		fn := func(tx *sql.Tx) error {
+			home, err := au.AccountEntity.FindHomeRegionByLoginEntity(ctx, tx, login)
+			if errors.Is(err, accounts.ErrAccountNotFound) || home == "" {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			region = au.Regions.Home(home)
+			return nil
+		}
+		if err := common.WithReadOnlyTransactionManager(ctx, au.DB, fn); err != nil {
+			common.AuthLog.Errorf("Transaction failed: %v", err)
+			return err
+		}
+	}
+
+	boundary.RegionLookupResponse(domain.RegionResponse{Name: region.Name, BaseURL: region.BaseURL}, nil)
+	return nil
+}
@@ -0,0 +1,65 @@
+package auths
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"slices"
+	"time"
+)
+
+const (
+	defaultScopedTokenMinutes = 60
+	maxScopedTokenMinutes     = 24 * 60
+)
+
+var ErrInvalidScope = errors.New("invalid scope")
+
+// ExecuteIssueScopedToken mints a limited token from a full session token, a limited token cannot mint another one
+func (au *AuthUsecase) ExecuteIssueScopedToken(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.ScopedTokenRequest, boundary OutputAuthBoundary) error {
+	if !claims.HasScope(jsonwebtoken.ScopeAccount) {
+		return fmt.Errorf("%w: only a login token can mint scoped tokens", ErrInvalidScope)
+	}
+
+	if len(request.Scopes) == 0 {
+		return fmt.Errorf("%w: at least one scope is required", ErrInvalidScope)
+	}
+	var scopes []string
+	for _, scope := range request.Scopes {
+		if !slices.Contains(jsonwebtoken.MintableScopes, scope) {
+			return fmt.Errorf("%w: %q, allowed scopes are %v", ErrInvalidScope, scope, jsonwebtoken.MintableScopes)
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	slices.Sort(scopes)
+
+	minutes := request.ExpiresInMinutes
+	if minutes == 0 {
+		minutes = defaultScopedTokenMinutes
+	}
+	if minutes < 0 || minutes > maxScopedTokenMinutes {
+		return fmt.Errorf("%w: expires_in_minutes must be between 1 and %d", ErrInvalidScope, maxScopedTokenMinutes)
+	}
+	// A scoped token never outlives the token it is minted from
+	expireAt := time.Now().Add(time.Duration(minutes) * time.Minute)
+	if claims.ExpiresAt != nil && claims.ExpiresAt.Time.Before(expireAt) {
+		expireAt = claims.ExpiresAt.Time
+	}
+
+	scopedToken, err := jsonwebtoken.GenerateScopedJWTToken(claims, scopes, expireAt)
+	if err != nil {
+		return errors.New("failed to generate scoped token")
+	}
+
+	boundary.ScopedTokenResponse(domain.ScopedTokenResponse{
+		AccessToken: scopedToken,
+		Scopes:      scopes,
+		ExpiresAt:   common.FormatTimeByParam(expireAt),
+	}, nil)
+	return nil
+}
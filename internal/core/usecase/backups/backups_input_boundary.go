@@ -0,0 +1,7 @@
+package backups
+
+import "context"
+
+type InputBackupBoundary interface {
+	ExecuteVerifyBackups(ctx context.Context) error
+}
@@ -0,0 +1,44 @@
+package backups
+
+import (
+	"context"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/infra/alerts"
+	"godating-dealls/internal/infra/backups"
+	"log"
+	"time"
+)
+
+type BackupUsecase struct {
+	Verifier *backups.Verifier
+	Alerter  alerts.Alerter
+}
+
+func NewBackupUsecase(verifier *backups.Verifier, alerter alerts.Alerter) InputBackupBoundary {
+	return &BackupUsecase{Verifier: verifier, Alerter: alerter}
+}
+
+// ExecuteVerifyBackups a failed verification is alerted, the returned error is only for the job log
+func (b BackupUsecase) ExecuteVerifyBackups(ctx context.Context) error {
+	report, err := b.Verifier.Verify(ctx)
+	if err != nil {
+		alertErr := b.Alerter.Alert(ctx, alerts.Alert{
+			Source:   "backup-verification",
+			Severity: "critical",
+			Message:  err.Error(),
+			Details: map[string]string{
+				"file":       report.File,
+				"created_at": common.FormatTimeByParam(report.CreatedAt),
+			},
+			RaisedAt: time.Now(),
+		})
+		if alertErr != nil {
+			log.Printf("Failed to send backup alert: %v", alertErr)
+		}
+		return err
+	}
+
+	log.Printf("Backup verified: %s created at %s, sha256 %s, %s",
+		report.File, common.FormatTimeByParam(report.CreatedAt), report.Checksum, report.RestoreCheck)
+	return nil
+}
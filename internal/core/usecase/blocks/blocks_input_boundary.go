@@ -0,0 +1,16 @@
+package blocks
+
+import (
+	"context"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+)
+
+type InputBlockBoundary interface {
+	ExecuteBlockAccount(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, blockedAccountId int64, boundary OutputBlockBoundary) error
+	ExecuteFetchBlockedAccounts(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputBlockBoundary) error
+	ExecuteUnblockAccount(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, blockedAccountId int64, boundary OutputBlockBoundary) error
+	ExecuteReportAccount(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, reportedAccountId int64, request domain.ReportAccountRequest, boundary OutputBlockBoundary) error
+	ExecuteFetchReports(ctx context.Context, reportedAccountId int64, page int, size int, boundary OutputBlockBoundary) error
+	ExecuteExportReports(ctx context.Context, reportedAccountId int64, boundary OutputBlockBoundary) error
+}
@@ -0,0 +1,11 @@
+package blocks
+
+import "godating-dealls/internal/domain"
+
+type OutputBlockBoundary interface {
+	BlockedAccountsResponse(response domain.BlockedAccountsResponse, err error)
+	ReportResponse(response domain.ReportResponse, err error)
+	ReportsResponse(response domain.ReportsResponse, err error)
+	// ReportExportRow is called once per exported report, an error stops the export
+	ReportExportRow(response domain.ReportResponse) error
+}
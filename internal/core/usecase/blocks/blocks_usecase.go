@@ -0,0 +1,177 @@
+package blocks
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/blocks"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"log"
+)
+
+const (
+	defaultReportsPageSize = 50
+	maxReportsPageSize     = 200
+)
+
+type BlockUsecase struct {
+	DB          *sql.DB
+	BlockEntity blocks.BlockEntity
+}
+
+func NewBlockUsecase(db *sql.DB, blockEntity blocks.BlockEntity) InputBlockBoundary {
+	return &BlockUsecase{DB: db, BlockEntity: blockEntity}
+}
+
+func (b BlockUsecase) ExecuteBlockAccount(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, blockedAccountId int64, boundary OutputBlockBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		if err := b.BlockEntity.BlockAccountEntity(ctx, tx, claims.AccountId, blockedAccountId); err != nil {
+			return err
+		}
+		return b.blockedAccounts(ctx, tx, claims.AccountId, boundary)
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, b.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (b BlockUsecase) ExecuteFetchBlockedAccounts(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputBlockBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		return b.blockedAccounts(ctx, tx, claims.AccountId, boundary)
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, b.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteUnblockAccount does not bring back the match, both accounts have to like each other again
+func (b BlockUsecase) ExecuteUnblockAccount(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, blockedAccountId int64, boundary OutputBlockBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		if err := b.BlockEntity.UnblockAccountEntity(ctx, tx, claims.AccountId, blockedAccountId); err != nil {
+			return err
+		}
+		return b.blockedAccounts(ctx, tx, claims.AccountId, boundary)
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, b.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (b BlockUsecase) ExecuteReportAccount(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, reportedAccountId int64, request domain.ReportAccountRequest, boundary OutputBlockBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		report, err := b.BlockEntity.ReportAccountEntity(ctx, tx, domain.ReportDto{
+			ReporterAccountID: claims.AccountId,
+			ReportedAccountID: reportedAccountId,
+			Reason:            request.Reason,
+			Details:           request.Details,
+		})
+		if err != nil {
+			return err
+		}
+
+		boundary.ReportResponse(toReportResponse(report), nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, b.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteFetchReports admin requests are authenticated by the admin middleware, reportedAccountId 0 lists every report
+func (b BlockUsecase) ExecuteFetchReports(ctx context.Context, reportedAccountId int64, page int, size int, boundary OutputBlockBoundary) error {
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = defaultReportsPageSize
+	}
+	if size > maxReportsPageSize {
+		size = maxReportsPageSize
+	}
+
+	fn := func(tx *sql.Tx) error {
+		reports, total, err := b.BlockEntity.FindReportsEntity(ctx, tx, reportedAccountId, page, size)
+		if err != nil {
+			return err
+		}
+
+		res := domain.ReportsResponse{
+			Page:    page,
+			Size:    size,
+			Total:   total,
+			Reports: make([]domain.ReportResponse, 0, len(reports)),
+		}
+		for _, report := range reports {
+			res.Reports = append(res.Reports, toReportResponse(report))
+		}
+		boundary.ReportsResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, b.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteExportReports streams every report to the boundary while it is read, the request context ends the query
+// when the client disconnects
+func (b BlockUsecase) ExecuteExportReports(ctx context.Context, reportedAccountId int64, boundary OutputBlockBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		return b.BlockEntity.StreamReportsEntity(ctx, tx, reportedAccountId, func(report domain.ReportDto) error {
+			return boundary.ReportExportRow(toReportResponse(report))
+		})
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, b.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (b BlockUsecase) blockedAccounts(ctx context.Context, tx *sql.Tx, accountId int64, boundary OutputBlockBoundary) error {
+	blocked, err := b.BlockEntity.FindBlockedAccountsEntity(ctx, tx, accountId)
+	if err != nil {
+		return err
+	}
+
+	res := domain.BlockedAccountsResponse{
+		Accounts:     make([]domain.BlockedAccountResponse, 0, len(blocked)),
+		TotalBlocked: int64(len(blocked)),
+	}
+	for _, account := range blocked {
+		res.Accounts = append(res.Accounts, domain.BlockedAccountResponse{
+			AccountID: account.AccountID,
+			Username:  account.Username,
+			BlockedAt: common.FormatTimeByParam(account.BlockedAt),
+		})
+	}
+	boundary.BlockedAccountsResponse(res, nil)
+	return nil
+}
+
+func toReportResponse(report domain.ReportDto) domain.ReportResponse {
+	return domain.ReportResponse{
+		ReportID:          report.ReportID,
+		ReporterAccountID: report.ReporterAccountID,
+		ReportedAccountID: report.ReportedAccountID,
+		Reason:            report.Reason,
+		Details:           report.Details,
+		ReportedAt:        common.FormatTimeByParam(report.CreatedAt),
+	}
+}
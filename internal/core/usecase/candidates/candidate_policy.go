@@ -0,0 +1,16 @@
+package candidates
+
+import "godating-dealls/internal/core/entities/candidates"
+
+const (
+	DefaultCandidateActivityWeight     = 0.6
+	DefaultCandidateCompletenessWeight = 0.4
+)
+
+// CandidatePolicy has the radius used when the viewer does not pick a distance and the weights of the ordering. The
+// radius is DISCOVERY_RADIUS_KM like the daily selection, the weights CANDIDATE_ACTIVITY_WEIGHT and
+// CANDIDATE_COMPLETENESS_WEIGHT
+type CandidatePolicy struct {
+	RadiusKm int `validate:"gt=0"`
+	Weights  candidates.Weights
+}
@@ -0,0 +1,11 @@
+package candidates
+
+import (
+	"context"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+)
+
+type InputCandidateBoundary interface {
+	ExecuteFetchCandidates(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, filter domain.CandidateFilterDto, page int, size int, boundary OutputCandidateBoundary) error
+}
@@ -0,0 +1,7 @@
+package candidates
+
+import "godating-dealls/internal/domain"
+
+type OutputCandidateBoundary interface {
+	CandidatesResponse(response domain.CandidatesResponse, err error)
+}
@@ -0,0 +1,113 @@
+package candidates
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/candidates"
+	"godating-dealls/internal/core/entities/photos"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"godating-dealls/internal/infra/storage"
+	"log"
+	"math"
+)
+
+const (
+	defaultCandidatesPageSize = 20
+	maxCandidatesPageSize     = 50
+)
+
+type CandidateUsecase struct {
+	DB              *sql.DB
+	CandidateEntity candidates.CandidateEntity
+	PhotoEntity     photos.PhotoEntity
+	Storage         storage.Storage
+	Policy          CandidatePolicy
+}
+
+func NewCandidateUsecase(db *sql.DB, candidateEntity candidates.CandidateEntity, photoEntity photos.PhotoEntity, storage storage.Storage, policy CandidatePolicy) InputCandidateBoundary {
+	return &CandidateUsecase{
+		DB:              db,
+		CandidateEntity: candidateEntity,
+		PhotoEntity:     photoEntity,
+		Storage:         storage,
+		Policy:          policy,
+	}
+}
+
+// ExecuteFetchCandidates pages through the candidates best score first. Unlike the daily views it does not record a
+// selection, so the same filter returns the same pages until the viewer swipes or the scores change
+func (c CandidateUsecase) ExecuteFetchCandidates(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, filter domain.CandidateFilterDto, page int, size int, boundary OutputCandidateBoundary) error {
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = defaultCandidatesPageSize
+	}
+	if size > maxCandidatesPageSize {
+		size = maxCandidatesPageSize
+	}
+	if filter.MaxDistanceKm == 0 {
+		filter.MaxDistanceKm = c.Policy.RadiusKm
+	}
+
+	fn := func(tx *sql.Tx) error {
+		candidateList, total, err := c.CandidateEntity.FindCandidatesEntity(ctx, tx, claims.AccountId, filter, c.Policy.Weights, page, size)
+		if err != nil {
+			return err
+		}
+
+		accountIds := make([]int64, 0, len(candidateList))
+		for _, candidate := range candidateList {
+			accountIds = append(accountIds, candidate.AccountID)
+		}
+		photosByAccount, err := c.PhotoEntity.FindPhotosByAccountsEntity(ctx, tx, accountIds)
+		if err != nil {
+			return err
+		}
+		photosByAccount, err = c.PhotoEntity.ArrangePhotosForViewerEntity(ctx, tx, claims.AccountId, photosByAccount)
+		if err != nil {
+			return err
+		}
+
+		res := domain.CandidatesResponse{
+			Page:       page,
+			Size:       size,
+			Total:      total,
+			Candidates: make([]domain.CandidateResponse, 0, len(candidateList)),
+		}
+		for _, candidate := range candidateList {
+			response := domain.CandidateResponse{
+				UserID:     candidate.UserID,
+				AccountID:  candidate.AccountID,
+				FullName:   candidate.FullName,
+				Username:   candidate.Username,
+				Photos:     make([]string, 0),
+				Age:        candidate.Age,
+				Gender:     candidate.Gender,
+				Address:    candidate.Address,
+				Bio:        candidate.Bio,
+				Verified:   candidate.Verified,
+				SuperLiked: candidate.SuperLiked,
+				Score:      math.Round(candidate.Score*1000) / 1000,
+			}
+			if candidate.DistanceKm != nil {
+				distanceKm := int(math.Max(1, math.Ceil(*candidate.DistanceKm)))
+				response.DistanceKm = &distanceKm
+			}
+			for _, photo := range photosByAccount[candidate.AccountID] {
+				response.Photos = append(response.Photos, c.Storage.URL(photo.StorageKey))
+			}
+			res.Candidates = append(res.Candidates, response)
+		}
+		boundary.CandidatesResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, c.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
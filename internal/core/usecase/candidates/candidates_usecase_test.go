@@ -0,0 +1,61 @@
+package candidates
+
+import (
+	"context"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/candidates"
+	"godating-dealls/internal/core/entities/photos"
+	"godating-dealls/internal/core/usecase/users"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"godating-dealls/internal/infra/mysql/repo"
+	"godating-dealls/internal/infra/sqlite/sqlitetest"
+	"godating-dealls/internal/infra/storage"
+	"testing"
+)
+
+// discardCandidateBoundary stands in for the presenter, it keeps the first error and how many candidates came back
+type discardCandidateBoundary struct {
+	err   error
+	count int
+}
+
+func (d *discardCandidateBoundary) CandidatesResponse(response domain.CandidatesResponse, err error) {
+	if d.err == nil {
+		d.err = err
+	}
+	d.count = len(response.Candidates)
+}
+
+// BenchmarkExecuteFetchCandidates ranks 200 candidates by the weighted activity and completeness score and serves the
+// first page, the discovery path behind the candidates endpoint
+func BenchmarkExecuteFetchCandidates(b *testing.B) {
+	db := sqlitetest.Open(b)
+	viewer := sqlitetest.CreateAccounts(b, db, 201, 10)[0]
+	claims := &jsonwebtoken.JWTTokenClaims{AccountId: viewer.AccountId, UserId: viewer.UserId, Email: viewer.Email}
+	ctx := jsonwebtoken.WithClaims(context.Background(), claims)
+	usecase := NewCandidateUsecase(db,
+		candidates.NewCandidateEntityImpl(repo.NewCandidatesRepositoryImpl(), common.NewValidator()),
+		photos.NewPhotoEntityImpl(repo.NewProfilePhotosRepositoryImpl(), repo.NewSmartPhotosRepositoryImpl()),
+		storage.NewLocalStorage(storage.LocalConfig{Dir: storage.DefaultLocalDir, PublicURL: storage.DefaultLocalPublicURL}),
+		CandidatePolicy{
+			RadiusKm: users.DefaultDiscoveryRadiusKm,
+			Weights:  candidates.Weights{Activity: DefaultCandidateActivityWeight, Completeness: DefaultCandidateCompletenessWeight},
+		})
+	boundary := &discardCandidateBoundary{}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := usecase.ExecuteFetchCandidates(ctx, claims, domain.CandidateFilterDto{MinAge: 18, MaxAge: 100}, 1, defaultCandidatesPageSize, boundary); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	if boundary.err != nil {
+		b.Fatal(boundary.err)
+	}
+	if boundary.count == 0 {
+		b.Fatal("no candidates were ranked")
+	}
+}
@@ -0,0 +1,14 @@
+package client_configs
+
+import (
+	"context"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+)
+
+type InputClientConfigBoundary interface {
+	ExecuteFetchClientConfig(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, appVersion string, boundary OutputClientConfigBoundary) error
+	ExecutePublishClientConfig(ctx context.Context, request domain.ClientConfigBundleRequest, boundary OutputClientConfigBoundary) error
+	ExecuteFetchClientConfigBundles(ctx context.Context, boundary OutputClientConfigBoundary) error
+	ExecuteUpdateClientConfigRollout(ctx context.Context, bundleId int64, request domain.ClientConfigRolloutRequest, boundary OutputClientConfigBoundary) error
+}
@@ -0,0 +1,10 @@
+package client_configs
+
+import "godating-dealls/internal/domain"
+
+type OutputClientConfigBoundary interface {
+	ClientConfigResponse(response domain.ClientConfigResponse, err error)
+	PublishClientConfigResponse(response domain.ClientConfigBundleResponse, err error)
+	ClientConfigBundlesResponse(response []domain.ClientConfigBundleResponse, err error)
+	ClientConfigRolloutResponse(response domain.ClientConfigBundleResponse, err error)
+}
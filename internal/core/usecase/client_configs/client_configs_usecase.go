@@ -0,0 +1,135 @@
+package client_configs
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/client_configs"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"log"
+)
+
+type ClientConfigUsecase struct {
+	DB                 *sql.DB
+	ClientConfigEntity client_configs.ClientConfigEntity
+}
+
+func NewClientConfigUsecase(db *sql.DB, clientConfigEntity client_configs.ClientConfigEntity) InputClientConfigBoundary {
+	return &ClientConfigUsecase{DB: db, ClientConfigEntity: clientConfigEntity}
+}
+
+func (c ClientConfigUsecase) ExecuteFetchClientConfig(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, appVersion string, boundary OutputClientConfigBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		bundles, err := c.ClientConfigEntity.ResolveClientConfigEntity(ctx, tx, claims.AccountId, appVersion)
+		if err != nil {
+			return err
+		}
+
+		res := domain.ClientConfigResponse{Bundles: []domain.ClientConfigItemResponse{}}
+		for _, bundle := range bundles {
+			res.Bundles = append(res.Bundles, domain.ClientConfigItemResponse{
+				Name:    bundle.Name,
+				Version: bundle.Version,
+				Payload: bundle.Payload,
+			})
+		}
+		boundary.ClientConfigResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, c.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (c ClientConfigUsecase) ExecutePublishClientConfig(ctx context.Context, request domain.ClientConfigBundleRequest, boundary OutputClientConfigBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		bundle, err := c.ClientConfigEntity.PublishClientConfigBundleEntity(ctx, tx, domain.ClientConfigBundleDto{
+			Name:           request.Name,
+			MinAppVersion:  request.MinAppVersion,
+			MaxAppVersion:  request.MaxAppVersion,
+			RolloutPercent: request.RolloutPercent,
+			Payload:        request.Payload,
+		})
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Client config %s version %d published for %d%% of app versions %s to %s",
+			bundle.Name, bundle.Version, bundle.RolloutPercent, bundle.MinAppVersion, maxAppVersionLabel(bundle))
+		boundary.PublishClientConfigResponse(toClientConfigBundleResponse(bundle), nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, c.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (c ClientConfigUsecase) ExecuteFetchClientConfigBundles(ctx context.Context, boundary OutputClientConfigBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		bundles, err := c.ClientConfigEntity.FindClientConfigBundlesEntity(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		res := make([]domain.ClientConfigBundleResponse, 0, len(bundles))
+		for _, bundle := range bundles {
+			res = append(res, toClientConfigBundleResponse(bundle))
+		}
+		boundary.ClientConfigBundlesResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, c.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (c ClientConfigUsecase) ExecuteUpdateClientConfigRollout(ctx context.Context, bundleId int64, request domain.ClientConfigRolloutRequest, boundary OutputClientConfigBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		bundle, err := c.ClientConfigEntity.UpdateClientConfigRolloutEntity(ctx, tx, bundleId, request)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Client config %s version %d now rolled out to %d%% of app versions %s to %s",
+			bundle.Name, bundle.Version, bundle.RolloutPercent, bundle.MinAppVersion, maxAppVersionLabel(bundle))
+		boundary.ClientConfigRolloutResponse(toClientConfigBundleResponse(bundle), nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, c.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func toClientConfigBundleResponse(bundle domain.ClientConfigBundleDto) domain.ClientConfigBundleResponse {
+	return domain.ClientConfigBundleResponse{
+		BundleID:       bundle.BundleID,
+		Name:           bundle.Name,
+		Version:        bundle.Version,
+		MinAppVersion:  bundle.MinAppVersion,
+		MaxAppVersion:  bundle.MaxAppVersion,
+		RolloutPercent: bundle.RolloutPercent,
+		Payload:        bundle.Payload,
+		CreatedAt:      common.FormatTimeByParam(bundle.CreatedAt),
+		UpdatedAt:      common.FormatTimeByParam(bundle.UpdatedAt),
+	}
+}
+
+func maxAppVersionLabel(bundle domain.ClientConfigBundleDto) string {
+	if bundle.MaxAppVersion == nil {
+		return "latest"
+	}
+	return *bundle.MaxAppVersion
+}
@@ -0,0 +1,13 @@
+package contacts
+
+import (
+	"context"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+)
+
+type InputContactBoundary interface {
+	ExecuteUploadContactHashes(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.ContactHashesRequest, boundary OutputContactBoundary) error
+	ExecuteFetchContactHashes(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputContactBoundary) error
+	ExecuteRemoveContactHashes(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, hash string, boundary OutputContactBoundary) error
+}
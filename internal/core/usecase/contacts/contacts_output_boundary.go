@@ -0,0 +1,8 @@
+package contacts
+
+import "godating-dealls/internal/domain"
+
+type OutputContactBoundary interface {
+	ContactExclusionsResponse(response domain.ContactExclusionsResponse, err error)
+	ContactExclusionsUpdateResponse(response domain.ContactExclusionsUpdateResponse, err error)
+}
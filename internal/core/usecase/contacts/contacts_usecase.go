@@ -0,0 +1,93 @@
+package contacts
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/contacts"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"log"
+)
+
+type ContactUsecase struct {
+	DB            *sql.DB
+	ContactEntity contacts.ContactEntity
+}
+
+func NewContactUsecase(db *sql.DB, contactEntity contacts.ContactEntity) InputContactBoundary {
+	return &ContactUsecase{DB: db, ContactEntity: contactEntity}
+}
+
+func (c ContactUsecase) ExecuteUploadContactHashes(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.ContactHashesRequest, boundary OutputContactBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		total, err := c.ContactEntity.SaveContactHashesEntity(ctx, tx, domain.ContactHashesDto{
+			AccountID: claims.AccountId,
+			Hashes:    request.Hashes,
+		})
+		if err != nil {
+			return err
+		}
+
+		boundary.ContactExclusionsUpdateResponse(domain.ContactExclusionsUpdateResponse{
+			TotalChanged: total,
+			Message:      "Contacts will be hidden from your discovery",
+		}, nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, c.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (c ContactUsecase) ExecuteFetchContactHashes(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputContactBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		hashes, err := c.ContactEntity.FindContactHashesEntity(ctx, tx, claims.AccountId)
+		if err != nil {
+			return err
+		}
+
+		boundary.ContactExclusionsResponse(domain.ContactExclusionsResponse{
+			Hashes:        hashes,
+			TotalExcluded: int64(len(hashes)),
+		}, nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, c.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteRemoveContactHashes removes one hash from the exclusion list, or the whole list when hash is empty
+func (c ContactUsecase) ExecuteRemoveContactHashes(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, hash string, boundary OutputContactBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		var total int64
+		var err error
+		if hash == "" {
+			total, err = c.ContactEntity.RemoveAllContactHashesEntity(ctx, tx, claims.AccountId)
+		} else {
+			total, err = c.ContactEntity.RemoveContactHashEntity(ctx, tx, claims.AccountId, hash)
+		}
+		if err != nil {
+			return err
+		}
+
+		boundary.ContactExclusionsUpdateResponse(domain.ContactExclusionsUpdateResponse{
+			TotalChanged: total,
+			Message:      "Contacts removed from exclusion list",
+		}, nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, c.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
@@ -0,0 +1,13 @@
+package daily_quotas
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/domain"
+)
+
+// EntitlementBoundary is what the quota rules ask about the subscription of an account, the entitlements entity implements it
+type EntitlementBoundary interface {
+	FindEntitlementsEntity(ctx context.Context, tx *sql.Tx, accountId int64) (domain.EntitlementsDto, error)
+	HasEntitlementEntity(ctx context.Context, tx *sql.Tx, accountId int64, entitlement string) (bool, error)
+}
@@ -1,8 +1,11 @@
 package daily_quotas
 
-import "context"
+import (
+	"context"
+	"godating-dealls/internal/infra/jsonwebtoken"
+)
 
 type InputDailyQuotaBoundary interface {
 	ExecuteAutoUpdateDailyQuotaUsecase(ctx context.Context) error
-	ExecuteFindDailyQuotaUsecase(ctx context.Context, token string, boundary DailyQuotasOutputBoundary) error
+	ExecuteFindDailyQuotaUsecase(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary DailyQuotasOutputBoundary) error
 }
@@ -7,11 +7,11 @@ import (
 	"godating-dealls/internal/common"
 	"godating-dealls/internal/core/entities/accounts"
 	"godating-dealls/internal/core/entities/daily_quotas"
-	"godating-dealls/internal/core/entities/packages"
+	"godating-dealls/internal/core/entities/entitlements"
 	"godating-dealls/internal/core/entities/users"
 	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/analytics"
 	"godating-dealls/internal/infra/jsonwebtoken"
-	"log"
 	"strconv"
 )
 
@@ -20,7 +20,8 @@ type DailyQuotasUsecase struct {
 	DailyQuotasEntity daily_quotas.DailyQuotasEntity
 	UserEntity        users.UserEntity
 	AccountEntity     accounts.AccountEntity
-	PackageEntity     packages.PackageEntity
+	Entitlements      EntitlementBoundary
+	Analytics         analytics.Publisher
 }
 
 func NewDailyQuotasUsecase(
@@ -28,13 +29,15 @@ func NewDailyQuotasUsecase(
 	dailyQuotasEntity daily_quotas.DailyQuotasEntity,
 	userEntity users.UserEntity,
 	accountEntity accounts.AccountEntity,
-	packageEntity packages.PackageEntity) InputDailyQuotaBoundary {
+	entitlementBoundary EntitlementBoundary,
+	analyticsPublisher analytics.Publisher) InputDailyQuotaBoundary {
 	return &DailyQuotasUsecase{
 		DB:                db,
 		DailyQuotasEntity: dailyQuotasEntity,
 		UserEntity:        userEntity,
 		AccountEntity:     accountEntity,
-		PackageEntity:     packageEntity,
+		Entitlements:      entitlementBoundary,
+		Analytics:         analyticsPublisher,
 	}
 }
 
@@ -42,21 +45,21 @@ func (d DailyQuotasUsecase) ExecuteAutoUpdateDailyQuotaUsecase(ctx context.Conte
 	fn := func(tx *sql.Tx) error {
 		usersList, err := d.UserEntity.FindAllUserEntities(ctx, tx)
 		common.HandleErrorReturn(err)
-		common.PrintJSON("daily usecase | users", usersList)
+		common.QuotaLog.DebugJSON("daily usecase | users", usersList)
 
 		for _, user := range usersList {
+			// Unlimited when a running subscription includes unlimited swipes, not the verified flag which may have lapsed
+			unlimited, err := d.Entitlements.HasEntitlementEntity(ctx, tx, user.AccountID, entitlements.EntitlementUnlimitedSwipes)
+			if err != nil {
+				return err
+			}
 			dailyQuotaDto := domain.DailyQuotasDto{
 				AccountID:      user.AccountID,
-				UserIsVerified: user.Verified,
-			}
-
-			// Check user is premium
-			if user.Verified == true {
-				dailyQuotaDto.UserIsVerified = true
+				UserIsVerified: unlimited,
 			}
-			common.PrintJSON("daily usecase | daily quotas", dailyQuotaDto)
+			common.QuotaLog.DebugJSON("daily usecase | daily quotas", dailyQuotaDto)
 
-			err := d.DailyQuotasEntity.UpdateOrInsertDailyQuotaEntities(ctx, tx, dailyQuotaDto)
+			err = d.DailyQuotasEntity.UpdateOrInsertDailyQuotaEntities(ctx, tx, dailyQuotaDto)
 			common.HandleErrorReturn(err)
 		}
 		return nil
@@ -64,40 +67,39 @@ func (d DailyQuotasUsecase) ExecuteAutoUpdateDailyQuotaUsecase(ctx context.Conte
 
 	err := common.WithExecuteTransactionalManager(ctx, d.DB, fn)
 	if err != nil {
-		log.Println("Transaction failed:", err)
+		common.QuotaLog.Errorf("Transaction failed: %v", err)
 	}
 	return err
 }
 
-func (d DailyQuotasUsecase) ExecuteFindDailyQuotaUsecase(ctx context.Context, token string, boundary DailyQuotasOutputBoundary) error {
+func (d DailyQuotasUsecase) ExecuteFindDailyQuotaUsecase(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary DailyQuotasOutputBoundary) error {
+	var quotaChecked analytics.Event
 	fn := func(tx *sql.Tx) error {
-		claims, err := jsonwebtoken.VerifyJWTToken(token)
-		if err != nil {
-			return errors.New("invalid token")
-		}
-
-		verifiedAccounts, err := d.AccountEntity.FindAccountVerifiedEntities(ctx, tx, claims.AccountId)
+		entitlementsDto, err := d.Entitlements.FindEntitlementsEntity(ctx, tx, claims.AccountId)
 		if err != nil {
-			return errors.New("invalid find accounts")
+			return errors.New("invalid find entitlements")
 		}
 
 		quota, err := d.DailyQuotasEntity.FindTotalDailyQuotasAndSwipeCount(ctx, tx, claims.AccountId)
-		if err != nil {
+		if err != nil && !entitlementsDto.UnlimitedSwipes {
 			return errors.New("invalid find quota account")
 		}
 
-		res := domain.DailyQuotaResponse{
-			TotalQuotas: strconv.FormatInt(quota.TotalQuota, 10),
-			SwipeCount:  quota.SwipeCount,
-		}
-
-		if verifiedAccounts {
-			expireQuota, err := d.PackageEntity.FindAccountPremiumPackage(ctx, tx, claims.AccountId)
-			if err != nil {
-				return errors.New("invalid find account premium package")
-			}
-			res.TotalQuotas = "Unlimited Until " + expireQuota.ExpiresIn.Format("2006-01-02 15:04:05")
+		res := domain.DailyQuotaResponse{SwipeCount: quota.SwipeCount}
+		if entitlementsDto.UnlimitedSwipes {
+			// The quota of today is not counted down, its number says nothing about how many swipes are left
+			unlimitedUntil := entitlementsDto.ExpiresAt.Format("2006-01-02 15:04:05")
+			res.TotalQuotas = "Unlimited"
+			res.Unlimited = true
+			res.UnlimitedUntil = &unlimitedUntil
+		} else {
+			res.TotalQuotas = strconv.FormatInt(max(quota.TotalQuota, 0), 10)
 		}
+		quotaChecked = analytics.NewEvent(analytics.EventQuotaChecked, claims.AccountId, map[string]interface{}{
+			"remaining":   max(quota.TotalQuota, 0),
+			"swipe_count": quota.SwipeCount,
+			"unlimited":   entitlementsDto.UnlimitedSwipes,
+		})
 
 		boundary.DailyQuotaResponse(res, nil)
 		return nil
@@ -107,5 +109,6 @@ func (d DailyQuotasUsecase) ExecuteFindDailyQuotaUsecase(ctx context.Context, to
 	if err != nil {
 		return errors.New("execute transactional manager failed: " + err.Error())
 	}
-	return err
+	d.Analytics.Publish(ctx, quotaChecked)
+	return nil
 }
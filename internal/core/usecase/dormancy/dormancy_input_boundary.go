@@ -0,0 +1,8 @@
+package dormancy
+
+import "context"
+
+type InputDormancyBoundary interface {
+	ExecuteDormancyPipeline(ctx context.Context) error
+	ExecuteDeletionPurge(ctx context.Context) error
+}
@@ -0,0 +1,23 @@
+package dormancy
+
+import "time"
+
+const (
+	DefaultWarnAfterMonths  = 12
+	DefaultHideAfterMonths  = 13
+	DefaultPurgeAfterMonths = 24
+	DefaultGraceDays        = 30
+	DefaultDeletionDays     = 30
+	DefaultBatchSize        = 500
+)
+
+// Policy is the retention policy, inactivity is counted from the last login and grace is the minimum time between two steps.
+// DeletionGrace is how long an account the owner deleted can still be restored by logging in before it is purged
+type Policy struct {
+	WarnAfterMonths  int           `validate:"gt=0"`
+	HideAfterMonths  int           `validate:"gtfield=WarnAfterMonths"`
+	PurgeAfterMonths int           `validate:"gtfield=HideAfterMonths"`
+	Grace            time.Duration `validate:"gt=0"`
+	DeletionGrace    time.Duration `validate:"gt=0"`
+	BatchSize        int           `validate:"gt=0"`
+}
@@ -0,0 +1,107 @@
+package dormancy
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/dormancy"
+	"godating-dealls/internal/core/entities/photos"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/storage"
+	"log"
+	"time"
+)
+
+type DormancyUsecase struct {
+	DB             *sql.DB
+	DormancyEntity dormancy.DormancyEntity
+	Policy         Policy
+	PhotoEntity    photos.PhotoEntity
+	Storage        storage.Storage
+}
+
+func NewDormancyUsecase(db *sql.DB, dormancyEntity dormancy.DormancyEntity, policy Policy, photoEntity photos.PhotoEntity, storage storage.Storage) InputDormancyBoundary {
+	return &DormancyUsecase{DB: db, DormancyEntity: dormancyEntity, Policy: policy, PhotoEntity: photoEntity, Storage: storage}
+}
+
+// ExecuteDormancyPipeline runs the stages from the last to the first so an account moves at most one step per run
+func (d DormancyUsecase) ExecuteDormancyPipeline(ctx context.Context) error {
+	now := time.Now()
+	stages := []struct {
+		from        string
+		to          string
+		afterMonths int
+	}{
+		{domain.DormancyStateHidden, domain.DormancyStatePurged, d.Policy.PurgeAfterMonths},
+		{domain.DormancyStateWarned, domain.DormancyStateHidden, d.Policy.HideAfterMonths},
+		{domain.DormancyStateActive, domain.DormancyStateWarned, d.Policy.WarnAfterMonths},
+	}
+
+	for _, stage := range stages {
+		var accounts []domain.AccountDormancyDto
+		err := common.WithReadOnlyTransactionManager(ctx, d.DB, func(tx *sql.Tx) error {
+			var err error
+			accounts, err = d.DormancyEntity.FindInactiveAccountsEntity(ctx, tx, stage.from, now.AddDate(0, -stage.afterMonths, 0), now.Add(-d.Policy.Grace), d.Policy.BatchSize)
+			return err
+		})
+		if err != nil {
+			log.Println("Transaction failed:", err)
+			return err
+		}
+
+		moved := d.moveAccounts(ctx, accounts, stage.to)
+		log.Printf("Dormancy %s: %d of %d accounts", stage.to, moved, len(accounts))
+	}
+	return nil
+}
+
+// ExecuteDeletionPurge erases the accounts whose owner deleted them at least the deletion grace ago, run by the
+// account deletion purge job. Whatever their last login, logging in would have cancelled the deletion
+func (d DormancyUsecase) ExecuteDeletionPurge(ctx context.Context) error {
+	now := time.Now()
+	var accounts []domain.AccountDormancyDto
+	err := common.WithReadOnlyTransactionManager(ctx, d.DB, func(tx *sql.Tx) error {
+		var err error
+		accounts, err = d.DormancyEntity.FindInactiveAccountsEntity(ctx, tx, domain.DormancyStateDeleted, now, now.Add(-d.Policy.DeletionGrace), d.Policy.BatchSize)
+		return err
+	})
+	if err != nil {
+		log.Println("Transaction failed:", err)
+		return err
+	}
+
+	moved := d.moveAccounts(ctx, accounts, domain.DormancyStatePurged)
+	log.Printf("Account deletion purge: %d of %d accounts", moved, len(accounts))
+	return nil
+}
+
+// moveAccounts transitions the accounts and returns how many moved, each account commits on its own so one failure
+// does not hold back the rest of the batch
+func (d DormancyUsecase) moveAccounts(ctx context.Context, accounts []domain.AccountDormancyDto, toState string) int {
+	moved := 0
+	for _, account := range accounts {
+		var purgedPhotos []domain.PhotoDto
+		err := common.WithExecuteTransactionalManager(ctx, d.DB, func(tx *sql.Tx) error {
+			if toState == domain.DormancyStatePurged {
+				var err error
+				if purgedPhotos, err = d.PhotoEntity.FindAllPhotosEntity(ctx, tx, account.AccountID); err != nil {
+					return err
+				}
+			}
+			return d.DormancyEntity.TransitionEntity(ctx, tx, account, toState)
+		})
+		if err != nil {
+			log.Printf("dormancy %s failed for account %d: %v", toState, account.AccountID, err)
+			continue
+		}
+		// The rows went with the purge, the stored files only go once it committed
+		for _, photo := range purgedPhotos {
+			if err := d.Storage.Delete(ctx, photo.StorageKey); err != nil && !errors.Is(err, storage.ErrObjectNotFound) {
+				log.Printf("dormancy purge could not delete photo %s: %v", photo.StorageKey, err)
+			}
+		}
+		moved++
+	}
+	return moved
+}
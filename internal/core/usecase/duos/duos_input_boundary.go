@@ -0,0 +1,17 @@
+package duos
+
+import (
+	"context"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+)
+
+type InputDuoBoundary interface {
+	ExecuteInviteDuo(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.DuoInviteRequest, boundary OutputDuoBoundary) error
+	ExecuteAcceptDuo(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, duoId int64, boundary OutputDuoBoundary) error
+	ExecuteFetchDuo(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputDuoBoundary) error
+	ExecuteUnlinkDuo(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputDuoBoundary) error
+	ExecuteFetchDuoCandidates(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputDuoBoundary) error
+	ExecuteSwipeDuo(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.DuoSwipeRequest, boundary OutputDuoBoundary) error
+	ExecuteFetchDuoMatches(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputDuoBoundary) error
+}
@@ -0,0 +1,10 @@
+package duos
+
+import "godating-dealls/internal/domain"
+
+type OutputDuoBoundary interface {
+	DuoResponse(response domain.DuoResponse, err error)
+	DuoCandidatesResponse(response []domain.DuoCandidateResponse, err error)
+	DuoSwipeResponse(response domain.SwipeResponse, err error)
+	DuoMatchesResponse(response []domain.DuoMatchResponse, err error)
+}
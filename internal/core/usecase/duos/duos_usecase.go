@@ -0,0 +1,215 @@
+package duos
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/duos"
+	"godating-dealls/internal/core/entities/matches"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"log"
+)
+
+// duoCandidatesPageSize is how many duos the duo discovery shows at once
+const duoCandidatesPageSize = 10
+
+type DuoUsecase struct {
+	DB          *sql.DB
+	DuoEntity   duos.DuoEntity
+	MatchEntity matches.MatchEntity
+}
+
+func NewDuoUsecase(db *sql.DB, duoEntity duos.DuoEntity, matchEntity matches.MatchEntity) InputDuoBoundary {
+	return &DuoUsecase{DB: db, DuoEntity: duoEntity, MatchEntity: matchEntity}
+}
+
+func (d DuoUsecase) ExecuteInviteDuo(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.DuoInviteRequest, boundary OutputDuoBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		duo, err := d.DuoEntity.InviteDuoPartnerEntity(ctx, tx, claims.AccountId, request.PartnerAccountID)
+		if err != nil {
+			return err
+		}
+		boundary.DuoResponse(toDuoResponse(duo, claims.AccountId), nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, d.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (d DuoUsecase) ExecuteAcceptDuo(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, duoId int64, boundary OutputDuoBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		duo, err := d.DuoEntity.AcceptDuoEntity(ctx, tx, claims.AccountId, duoId)
+		if err != nil {
+			return err
+		}
+		boundary.DuoResponse(toDuoResponse(duo, claims.AccountId), nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, d.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (d DuoUsecase) ExecuteFetchDuo(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputDuoBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		duo, err := d.DuoEntity.FindCurrentDuoEntity(ctx, tx, claims.AccountId)
+		if err != nil {
+			return err
+		}
+		boundary.DuoResponse(toDuoResponse(duo, claims.AccountId), nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, d.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteUnlinkDuo leaves the duo, cancels a sent invite or declines a received one
+func (d DuoUsecase) ExecuteUnlinkDuo(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputDuoBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		duo, err := d.DuoEntity.UnlinkDuoEntity(ctx, tx, claims.AccountId)
+		if err != nil {
+			return err
+		}
+		boundary.DuoResponse(toDuoResponse(duo, claims.AccountId), nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, d.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteFetchDuoCandidates lists duos for the duo discovery mode, either member of an active duo can swipe for it
+func (d DuoUsecase) ExecuteFetchDuoCandidates(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputDuoBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		candidates, err := d.DuoEntity.FindDuoCandidatesEntity(ctx, tx, claims.AccountId, duoCandidatesPageSize)
+		if err != nil {
+			return err
+		}
+
+		res := make([]domain.DuoCandidateResponse, 0, len(candidates))
+		for _, candidate := range candidates {
+			res = append(res, domain.DuoCandidateResponse{
+				DuoID:   candidate.DuoID,
+				Members: toDuoMemberResponses(candidate.Members),
+			})
+		}
+		boundary.DuoCandidatesResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, d.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteSwipeDuo swipes for the duo of the account, two duos that liked each other get one match
+// whose conversation is a group chat of the four accounts. Duo swipes do not use the daily swipe quota
+func (d DuoUsecase) ExecuteSwipeDuo(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.DuoSwipeRequest, boundary OutputDuoBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		duo, likedDuo, likedBack, err := d.DuoEntity.SwipeDuoEntity(ctx, tx, claims.AccountId, request.DuoID, request.ActionType)
+		if err != nil {
+			return err
+		}
+
+		res := domain.SwipeResponse{Message: "Duo Liked!"}
+		if request.ActionType == "left" {
+			res.Message = "Duo Passed!"
+		}
+		if likedBack {
+			participants := []int64{duo.AccountIDLow, duo.AccountIDHigh, likedDuo.AccountIDLow, likedDuo.AccountIDHigh}
+			match, matched, err := d.MatchEntity.CreateDuoMatchEntity(ctx, tx, claims.AccountId, duo.DuoID, likedDuo.DuoID, participants)
+			if err != nil {
+				return err
+			}
+			if matched {
+				res.Matched = true
+				res.MatchID = match.MatchID
+				res.Message = "It's a double date!"
+			}
+		}
+		boundary.DuoSwipeResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, d.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteFetchDuoMatches lists the duo matches with the three other accounts, the chat endpoints of matches
+// work with their match id
+func (d DuoUsecase) ExecuteFetchDuoMatches(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputDuoBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		views, err := d.DuoEntity.FindDuoMatchesEntity(ctx, tx, claims.AccountId)
+		if err != nil {
+			return err
+		}
+
+		res := make([]domain.DuoMatchResponse, 0, len(views))
+		for _, view := range views {
+			res = append(res, domain.DuoMatchResponse{
+				MatchID:   view.MatchID,
+				Members:   toDuoMemberResponses(view.Members),
+				MatchedAt: common.FormatTimeByParam(view.CreatedAt),
+			})
+		}
+		boundary.DuoMatchesResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, d.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func toDuoResponse(duo domain.DuoDto, accountId int64) domain.DuoResponse {
+	res := domain.DuoResponse{
+		DuoID:              duo.DuoID,
+		PartnerAccountID:   duo.AccountIDLow,
+		InvitedByAccountID: duo.InvitedByAccountID,
+		State:              duo.State,
+		CreatedAt:          common.FormatTimeByParam(duo.CreatedAt),
+	}
+	if duo.AccountIDLow == accountId {
+		res.PartnerAccountID = duo.AccountIDHigh
+	}
+	if duo.LinkedAt != nil {
+		res.LinkedAt = common.FormatTimeByParam(*duo.LinkedAt)
+	}
+	return res
+}
+
+func toDuoMemberResponses(members []domain.DuoMemberDto) []domain.DuoMemberResponse {
+	res := make([]domain.DuoMemberResponse, 0, len(members))
+	for _, member := range members {
+		res = append(res, domain.DuoMemberResponse{
+			AccountID: member.AccountID,
+			FullName:  member.FullName,
+			Age:       member.Age,
+			Gender:    member.Gender,
+			Bio:       member.Bio,
+		})
+	}
+	return res
+}
@@ -0,0 +1,7 @@
+package email_domains
+
+import "context"
+
+type InputEmailDomainBoundary interface {
+	ExecuteRefreshEmailDomains(ctx context.Context) error
+}
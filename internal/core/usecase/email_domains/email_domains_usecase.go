@@ -0,0 +1,38 @@
+package email_domains
+
+import (
+	"context"
+	"errors"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/infra/emaildomains"
+	"log"
+)
+
+type EmailDomainUsecase struct {
+	Source emaildomains.Source
+	Policy *common.EmailDomainPolicy
+}
+
+func NewEmailDomainUsecase(source emaildomains.Source, policy *common.EmailDomainPolicy) InputEmailDomainBoundary {
+	return &EmailDomainUsecase{Source: source, Policy: policy}
+}
+
+// ExecuteRefreshEmailDomains keeps the previous list when the download fails or comes back empty,
+// an outage of the source must not open sign up to every disposable provider
+func (e EmailDomainUsecase) ExecuteRefreshEmailDomains(ctx context.Context) error {
+	if e.Source == nil {
+		return nil
+	}
+
+	domains, err := e.Source.Fetch(ctx)
+	if err != nil {
+		return err
+	}
+	if len(domains) == 0 {
+		return errors.New("email domain list is empty, keeping the current list")
+	}
+
+	e.Policy.ReplaceRemote(domains)
+	log.Printf("Loaded %d email domains for the %s list", len(domains), e.Policy.Mode())
+	return nil
+}
@@ -0,0 +1,15 @@
+package events
+
+import (
+	"context"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+)
+
+type InputEventBoundary interface {
+	ExecuteCreateEvent(ctx context.Context, request domain.EventRequest, boundary OutputEventBoundary) error
+	ExecuteFetchEvents(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputEventBoundary) error
+	ExecuteJoinEvent(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, eventId int64, boundary OutputEventBoundary) error
+	ExecuteConnectAtEvent(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, eventId int64, request domain.EventConnectRequest, boundary OutputEventBoundary) error
+	ExecuteCleanupEventRooms(ctx context.Context) error
+}
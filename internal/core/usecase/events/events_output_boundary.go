@@ -0,0 +1,10 @@
+package events
+
+import "godating-dealls/internal/domain"
+
+type OutputEventBoundary interface {
+	EventCreatedResponse(response domain.EventResponse, err error)
+	EventResponse(response domain.EventResponse, err error)
+	EventsResponse(response []domain.EventResponse, err error)
+	EventConnectionResponse(response domain.EventConnectionResponse, err error)
+}
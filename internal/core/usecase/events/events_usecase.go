@@ -0,0 +1,209 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/blocks"
+	"godating-dealls/internal/core/entities/events"
+	"godating-dealls/internal/core/entities/matches"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"log"
+	"time"
+)
+
+// cleanupBatchSize rooms are purged per run, a backlog is worked off by the next runs
+const cleanupBatchSize = 100
+
+type EventUsecase struct {
+	DB          *sql.DB
+	EventEntity events.EventEntity
+	MatchEntity matches.MatchEntity
+	BlockEntity blocks.BlockEntity
+}
+
+func NewEventUsecase(db *sql.DB, eventEntity events.EventEntity, matchEntity matches.MatchEntity, blockEntity blocks.BlockEntity) InputEventBoundary {
+	return &EventUsecase{DB: db, EventEntity: eventEntity, MatchEntity: matchEntity, BlockEntity: blockEntity}
+}
+
+// ExecuteCreateEvent admin requests are authenticated by the admin middleware. The room is a group chat
+// that ends with the event, guests join it once the event started
+func (e EventUsecase) ExecuteCreateEvent(ctx context.Context, request domain.EventRequest, boundary OutputEventBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		event, err := e.EventEntity.CreateEventEntity(ctx, tx, domain.EventDto{
+			Title:       request.Title,
+			Description: request.Description,
+			StartsAt:    request.StartsAt,
+			EndsAt:      request.EndsAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		room, err := e.MatchEntity.CreateGroupMatchEntity(ctx, tx, event.Title, nil, &event.EndsAt)
+		if err != nil {
+			return err
+		}
+		if err := e.EventEntity.UpdateEventRoomEntity(ctx, tx, event.EventID, room.MatchID); err != nil {
+			return err
+		}
+		event.RoomMatchID = room.MatchID
+
+		boundary.EventCreatedResponse(toEventResponse(event, time.Now()), nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, e.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (e EventUsecase) ExecuteFetchEvents(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputEventBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		now := time.Now()
+		list, err := e.EventEntity.FindEventsEntity(ctx, tx, now)
+		if err != nil {
+			return err
+		}
+
+		res := make([]domain.EventResponse, 0, len(list))
+		for _, event := range list {
+			res = append(res, toEventResponse(event, now))
+		}
+		boundary.EventsResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, e.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteJoinEvent adds the account to the room of a live event, the room is then used like any other chat
+func (e EventUsecase) ExecuteJoinEvent(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, eventId int64, boundary OutputEventBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		now := time.Now()
+		event, err := e.EventEntity.LockLiveEventEntity(ctx, tx, eventId, now)
+		if err != nil {
+			return err
+		}
+		room, err := e.MatchEntity.FindMatchEntity(ctx, tx, event.RoomMatchID)
+		if err != nil {
+			return err
+		}
+		if err := e.MatchEntity.AddParticipantEntity(ctx, tx, room, claims.AccountId); err != nil {
+			return err
+		}
+
+		boundary.EventResponse(toEventResponse(event, now), nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, e.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteConnectAtEvent lets a guest choose another guest of the room, once both chose each other they are
+// matched and keep their chat after the room is gone. The other guest is not told about a one sided choice
+func (e EventUsecase) ExecuteConnectAtEvent(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, eventId int64, request domain.EventConnectRequest, boundary OutputEventBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		// The event lock also keeps two guests choosing each other at the same time in order
+		event, err := e.EventEntity.LockLiveEventEntity(ctx, tx, eventId, time.Now())
+		if err != nil {
+			return err
+		}
+		room, err := e.MatchEntity.FindMatchEntity(ctx, tx, event.RoomMatchID)
+		if err != nil {
+			return err
+		}
+		guests := map[int64]bool{}
+		for _, participant := range room.ParticipantAccountIDs {
+			guests[participant] = true
+		}
+		if !guests[claims.AccountId] || !guests[request.AccountID] {
+			return events.ErrNotEventGuest
+		}
+		if err := e.BlockEntity.EnsureNotBlockedEntity(ctx, tx, claims.AccountId, []int64{request.AccountID}); err != nil {
+			return err
+		}
+
+		mutual, err := e.EventEntity.ConnectEntity(ctx, tx, event.EventID, claims.AccountId, request.AccountID)
+		if err != nil {
+			return err
+		}
+
+		res := domain.EventConnectionResponse{
+			EventID:   event.EventID,
+			AccountID: request.AccountID,
+			Message:   "Connection sent, you are matched if they connect with you too",
+		}
+		if mutual {
+			match, _, err := e.MatchEntity.CreatePairMatchEntity(ctx, tx, claims.AccountId, request.AccountID)
+			if err != nil {
+				return err
+			}
+			res.Matched = true
+			res.MatchID = match.MatchID
+			res.Message = "It's a match, your chat stays after the event"
+		}
+
+		boundary.EventConnectionResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, e.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteCleanupEventRooms purges the rooms of ended events with their messages, run by the event room cleanup job
+func (e EventUsecase) ExecuteCleanupEventRooms(ctx context.Context) error {
+	fn := func(tx *sql.Tx) error {
+		ended, err := e.EventEntity.FindEndedEventRoomsEntity(ctx, tx, time.Now(), cleanupBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range ended {
+			if err := e.EventEntity.ClearEventRoomEntity(ctx, tx, event.EventID); err != nil {
+				return err
+			}
+			if err := e.MatchEntity.DeleteMatchEntity(ctx, tx, event.RoomMatchID); err != nil {
+				return err
+			}
+		}
+		log.Printf("Event rooms purged: %d", len(ended))
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, e.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func toEventResponse(event domain.EventDto, now time.Time) domain.EventResponse {
+	res := domain.EventResponse{
+		EventID:     event.EventID,
+		Title:       event.Title,
+		Description: event.Description,
+		State:       events.EventState(event, now),
+		StartsAt:    common.FormatTimeByParam(event.StartsAt),
+		EndsAt:      common.FormatTimeByParam(event.EndsAt),
+	}
+	if res.State == domain.EventStateLive {
+		res.RoomMatchID = event.RoomMatchID
+	}
+	return res
+}
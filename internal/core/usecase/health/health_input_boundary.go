@@ -0,0 +1,8 @@
+package health
+
+import "context"
+
+type InputHealthBoundary interface {
+	ExecuteReadiness(ctx context.Context, boundary OutputHealthBoundary) error
+	ExecuteServiceStatus(ctx context.Context, boundary OutputHealthBoundary) error
+}
@@ -0,0 +1,8 @@
+package health
+
+import "godating-dealls/internal/domain"
+
+type OutputHealthBoundary interface {
+	ReadinessResponse(response domain.ReadinessResponse, err error)
+	ServiceStatusResponse(response domain.ServiceStatusResponse, err error)
+}
@@ -0,0 +1,78 @@
+package health
+
+import (
+	"context"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/mysql/migrations"
+	"godating-dealls/internal/infra/uptime"
+	"math"
+	"time"
+)
+
+type HealthUsecase struct {
+	SchemaGuard   *migrations.SchemaGuard
+	StatusMonitor *uptime.Monitor
+}
+
+func NewHealthUsecase(schemaGuard *migrations.SchemaGuard, statusMonitor *uptime.Monitor) InputHealthBoundary {
+	return &HealthUsecase{SchemaGuard: schemaGuard, StatusMonitor: statusMonitor}
+}
+
+// ExecuteReadiness is not ready while the schema drifted from this build, the queries would fail on it one by one
+func (h HealthUsecase) ExecuteReadiness(ctx context.Context, boundary OutputHealthBoundary) error {
+	report, err := h.SchemaGuard.Report(ctx)
+	if err != nil {
+		return err
+	}
+
+	res := domain.ReadinessResponse{
+		Ready: !report.Drifted(),
+		Schema: domain.SchemaDriftResponse{
+			Drifted:           report.Drifted(),
+			ExpectedVersion:   report.ExpectedVersion,
+			AppliedVersion:    report.AppliedVersion,
+			PendingMigrations: report.PendingMigrations,
+			EditedMigrations:  report.EditedMigrations,
+			UnknownMigrations: report.UnknownMigrations,
+			MissingTables:     report.MissingTables,
+			MissingColumns:    report.MissingColumns,
+			MismatchedColumns: report.MismatchedColumns,
+			CheckedAt:         common.FormatTimeByParam(report.CheckedAt),
+		},
+	}
+	boundary.ReadinessResponse(res, nil)
+	return nil
+}
+
+// ExecuteServiceStatus answers from the last probes of the monitor, the dependencies are never called on a request
+func (h HealthUsecase) ExecuteServiceStatus(ctx context.Context, boundary OutputHealthBoundary) error {
+	snapshot := h.StatusMonitor.Snapshot()
+
+	res := domain.ServiceStatusResponse{
+		Status:        snapshot.State,
+		StartedAt:     common.FormatTimeByParam(snapshot.StartedAt),
+		UptimeSeconds: int64(time.Since(snapshot.StartedAt).Seconds()),
+		WindowMinutes: int(snapshot.Window.Minutes()),
+		Components:    make([]domain.ComponentStatusResponse, 0, len(snapshot.Components)),
+	}
+	for _, component := range snapshot.Components {
+		status := domain.ComponentStatusResponse{
+			Name:      component.Name,
+			State:     component.State,
+			Checks:    component.Checks,
+			Failures:  component.Failures,
+			ErrorRate: math.Round(component.ErrorRate*10000) / 10000,
+			LatencyMs: component.Latency.Milliseconds(),
+		}
+		if !component.LastCheckedAt.IsZero() {
+			status.LastCheckedAt = common.FormatTimeByParam(component.LastCheckedAt)
+		}
+		if !component.LastFailureAt.IsZero() {
+			status.LastFailureAt = common.FormatTimeByParam(component.LastFailureAt)
+		}
+		res.Components = append(res.Components, status)
+	}
+	boundary.ServiceStatusResponse(res, nil)
+	return nil
+}
@@ -0,0 +1,13 @@
+package hidden_accounts
+
+import (
+	"context"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+)
+
+type InputHiddenAccountBoundary interface {
+	ExecuteHideAccount(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.HideAccountRequest, boundary OutputHiddenAccountBoundary) error
+	ExecuteFetchHiddenAccounts(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputHiddenAccountBoundary) error
+	ExecuteUnhideAccount(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, hiddenAccountId int64, boundary OutputHiddenAccountBoundary) error
+}
@@ -0,0 +1,7 @@
+package hidden_accounts
+
+import "godating-dealls/internal/domain"
+
+type OutputHiddenAccountBoundary interface {
+	HiddenAccountsResponse(response domain.HiddenAccountsResponse, err error)
+}
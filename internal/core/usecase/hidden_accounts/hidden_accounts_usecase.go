@@ -0,0 +1,84 @@
+package hidden_accounts
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/hidden_accounts"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"log"
+)
+
+type HiddenAccountUsecase struct {
+	DB                  *sql.DB
+	HiddenAccountEntity hidden_accounts.HiddenAccountEntity
+}
+
+func NewHiddenAccountUsecase(db *sql.DB, hiddenAccountEntity hidden_accounts.HiddenAccountEntity) InputHiddenAccountBoundary {
+	return &HiddenAccountUsecase{DB: db, HiddenAccountEntity: hiddenAccountEntity}
+}
+
+func (h HiddenAccountUsecase) ExecuteHideAccount(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.HideAccountRequest, boundary OutputHiddenAccountBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		if err := h.HiddenAccountEntity.HideAccountEntity(ctx, tx, claims.AccountId, request.AccountID); err != nil {
+			return err
+		}
+		return h.hiddenAccounts(ctx, tx, claims.AccountId, boundary)
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, h.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (h HiddenAccountUsecase) ExecuteFetchHiddenAccounts(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputHiddenAccountBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		return h.hiddenAccounts(ctx, tx, claims.AccountId, boundary)
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, h.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteUnhideAccount lets the account be recommended again from the next discovery list
+func (h HiddenAccountUsecase) ExecuteUnhideAccount(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, hiddenAccountId int64, boundary OutputHiddenAccountBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		if err := h.HiddenAccountEntity.UnhideAccountEntity(ctx, tx, claims.AccountId, hiddenAccountId); err != nil {
+			return err
+		}
+		return h.hiddenAccounts(ctx, tx, claims.AccountId, boundary)
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, h.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (h HiddenAccountUsecase) hiddenAccounts(ctx context.Context, tx *sql.Tx, accountId int64, boundary OutputHiddenAccountBoundary) error {
+	hidden, err := h.HiddenAccountEntity.FindHiddenAccountsEntity(ctx, tx, accountId)
+	if err != nil {
+		return err
+	}
+
+	res := domain.HiddenAccountsResponse{
+		Accounts:    make([]domain.HiddenAccountResponse, 0, len(hidden)),
+		TotalHidden: int64(len(hidden)),
+	}
+	for _, account := range hidden {
+		res.Accounts = append(res.Accounts, domain.HiddenAccountResponse{
+			AccountID: account.AccountID,
+			Username:  account.Username,
+			HiddenAt:  common.FormatTimeByParam(account.HiddenAt),
+		})
+	}
+	boundary.HiddenAccountsResponse(res, nil)
+	return nil
+}
@@ -0,0 +1,14 @@
+package integrations
+
+import (
+	"context"
+	"godating-dealls/internal/infra/jsonwebtoken"
+)
+
+type InputIntegrationBoundary interface {
+	ExecuteConnectIntegration(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, provider string, boundary OutputIntegrationBoundary) error
+	ExecuteIntegrationCallback(ctx context.Context, provider string, code string, state string, boundary OutputIntegrationBoundary) error
+	ExecuteFetchImportedContent(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputIntegrationBoundary) error
+	ExecuteDisconnectIntegration(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, provider string, boundary OutputIntegrationBoundary) error
+	ExecuteRefreshIntegrations(ctx context.Context) error
+}
@@ -0,0 +1,9 @@
+package integrations
+
+import "godating-dealls/internal/domain"
+
+type OutputIntegrationBoundary interface {
+	ConnectIntegrationResponse(response domain.ConnectIntegrationResponse, err error)
+	ImportedContentResponse(response []domain.ImportedContentResponse, err error)
+	DisconnectIntegrationResponse(response domain.DisconnectIntegrationResponse, err error)
+}
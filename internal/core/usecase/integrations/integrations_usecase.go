@@ -0,0 +1,273 @@
+package integrations
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/integrations"
+	"godating-dealls/internal/domain"
+	provider "godating-dealls/internal/infra/integrations"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"godating-dealls/internal/infra/redisclient"
+	"log"
+	"time"
+)
+
+// refreshWindow tokens expiring within this window are refreshed before the content sync
+const refreshWindow = 24 * time.Hour
+
+type IntegrationUsecase struct {
+	DB                *sql.DB
+	IntegrationEntity integrations.IntegrationEntity
+	Rds               redisclient.RedisInterface
+	Providers         provider.Registry
+}
+
+func NewIntegrationUsecase(
+	db *sql.DB,
+	integrationEntity integrations.IntegrationEntity,
+	rds redisclient.RedisInterface,
+	providers provider.Registry) InputIntegrationBoundary {
+	return &IntegrationUsecase{
+		DB:                db,
+		IntegrationEntity: integrationEntity,
+		Rds:               rds,
+		Providers:         providers,
+	}
+}
+
+func (i IntegrationUsecase) ExecuteConnectIntegration(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, providerName string, boundary OutputIntegrationBoundary) error {
+	p, err := i.Providers.Find(providerName)
+	if err != nil {
+		return err
+	}
+
+	// The state ties the provider redirect back to the account that started the flow
+	state, err := generateState()
+	if err != nil {
+		return errors.New("failed to generate state")
+	}
+	err = i.Rds.StoreToRedis(ctx, integrationStateKey(state), map[string]interface{}{
+		"account_id": claims.AccountId,
+		"provider":   p.Name(),
+	})
+	if err != nil {
+		return errors.New("failed to save state")
+	}
+
+	boundary.ConnectIntegrationResponse(domain.ConnectIntegrationResponse{
+		Provider:     p.Name(),
+		AuthorizeURL: p.AuthorizeURL(state),
+	}, nil)
+	return nil
+}
+
+func (i IntegrationUsecase) ExecuteIntegrationCallback(ctx context.Context, providerName string, code string, state string, boundary OutputIntegrationBoundary) error {
+	p, err := i.Providers.Find(providerName)
+	if err != nil {
+		return err
+	}
+
+	accountId, err := i.consumeState(ctx, state, p.Name())
+	if err != nil {
+		return err
+	}
+
+	// Provider calls stay outside the transaction so a slow provider doesn't hold a DB connection
+	oauthToken, err := p.ExchangeCode(ctx, code)
+	if err != nil {
+		log.Printf("integration %s exchange failed: %v", p.Name(), err)
+		return errors.New("failed to exchange authorization code")
+	}
+	items, err := p.FetchContent(ctx, oauthToken.AccessToken)
+	if err != nil {
+		log.Printf("integration %s fetch content failed: %v", p.Name(), err)
+		return errors.New("failed to import content")
+	}
+
+	fn := func(tx *sql.Tx) error {
+		err := i.saveSync(ctx, tx, accountId, p.Name(), oauthToken, items)
+		if err != nil {
+			return err
+		}
+
+		contents, err := i.IntegrationEntity.FindImportedContentEntity(ctx, tx, accountId)
+		if err != nil {
+			return err
+		}
+		boundary.ImportedContentResponse(toImportedContentResponses(contents), nil)
+		return nil
+	}
+
+	err = common.WithExecuteTransactionalManager(ctx, i.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (i IntegrationUsecase) ExecuteFetchImportedContent(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputIntegrationBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		contents, err := i.IntegrationEntity.FindImportedContentEntity(ctx, tx, claims.AccountId)
+		if err != nil {
+			return err
+		}
+		boundary.ImportedContentResponse(toImportedContentResponses(contents), nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, i.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (i IntegrationUsecase) ExecuteDisconnectIntegration(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, providerName string, boundary OutputIntegrationBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		err := i.IntegrationEntity.RemoveIntegrationEntity(ctx, tx, claims.AccountId, providerName)
+		if err != nil {
+			return err
+		}
+
+		boundary.DisconnectIntegrationResponse(domain.DisconnectIntegrationResponse{
+			Provider: providerName,
+			Message:  "Integration disconnected and imported content removed",
+		}, nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, i.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteRefreshIntegrations re-imports content for every connected account, one failing integration doesn't stop the others
+func (i IntegrationUsecase) ExecuteRefreshIntegrations(ctx context.Context) error {
+	var connected []domain.IntegrationDto
+	err := common.WithReadOnlyTransactionManager(ctx, i.DB, func(tx *sql.Tx) error {
+		var err error
+		connected, err = i.IntegrationEntity.FindAllIntegrationsEntity(ctx, tx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, integration := range connected {
+		p, err := i.Providers.Find(integration.Provider)
+		if err != nil {
+			log.Printf("integration refresh skipped for account %d: %v", integration.AccountID, err)
+			continue
+		}
+
+		oauthToken := provider.Token{
+			AccessToken:  integration.AccessToken,
+			RefreshToken: integration.RefreshToken,
+			ExpiresAt:    integration.ExpiresAt,
+		}
+		if time.Until(oauthToken.ExpiresAt) < refreshWindow {
+			oauthToken, err = p.RefreshToken(ctx, oauthToken)
+			if err != nil {
+				log.Printf("integration %s token refresh failed for account %d: %v", p.Name(), integration.AccountID, err)
+				continue
+			}
+		}
+
+		items, err := p.FetchContent(ctx, oauthToken.AccessToken)
+		if err != nil {
+			log.Printf("integration %s fetch content failed for account %d: %v", p.Name(), integration.AccountID, err)
+			continue
+		}
+
+		err = common.WithExecuteTransactionalManager(ctx, i.DB, func(tx *sql.Tx) error {
+			return i.saveSync(ctx, tx, integration.AccountID, p.Name(), oauthToken, items)
+		})
+		if err != nil {
+			log.Printf("integration %s sync failed for account %d: %v", p.Name(), integration.AccountID, err)
+		}
+	}
+	return nil
+}
+
+func (i IntegrationUsecase) saveSync(ctx context.Context, tx *sql.Tx, accountId int64, providerName string, oauthToken provider.Token, items []provider.ImportedItem) error {
+	syncedAt := time.Now()
+	err := i.IntegrationEntity.SaveIntegrationEntity(ctx, tx, domain.IntegrationDto{
+		AccountID:    accountId,
+		Provider:     providerName,
+		AccessToken:  oauthToken.AccessToken,
+		RefreshToken: oauthToken.RefreshToken,
+		ExpiresAt:    oauthToken.ExpiresAt,
+		LastSyncedAt: &syncedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	contents := make([]domain.ImportedContentDto, 0, len(items))
+	for _, item := range items {
+		contents = append(contents, domain.ImportedContentDto{
+			Provider:    providerName,
+			ContentType: item.ContentType,
+			ExternalID:  item.ExternalID,
+			Value:       item.Value,
+			URL:         item.URL,
+		})
+	}
+	return i.IntegrationEntity.ReplaceImportedContentEntity(ctx, tx, accountId, providerName, contents)
+}
+
+func (i IntegrationUsecase) consumeState(ctx context.Context, state string, providerName string) (int64, error) {
+	if state == "" {
+		return 0, errors.New("missing state")
+	}
+
+	key := integrationStateKey(state)
+	value, err := i.Rds.LoadFromRedis(ctx, key)
+	if err != nil {
+		return 0, errors.New("invalid or expired state")
+	}
+	// A state can only be used once
+	_ = i.Rds.ClearFromRedis(ctx, key)
+
+	data, ok := value.(map[string]interface{})
+	if !ok || data["provider"] != providerName {
+		return 0, errors.New("invalid state")
+	}
+	accountId, ok := data["account_id"].(float64)
+	if !ok {
+		return 0, errors.New("invalid state")
+	}
+	return int64(accountId), nil
+}
+
+func integrationStateKey(state string) string {
+	return redisclient.IntegrationStateKey.Key(state)
+}
+
+func generateState() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func toImportedContentResponses(contents []domain.ImportedContentDto) []domain.ImportedContentResponse {
+	var res []domain.ImportedContentResponse
+	for _, content := range contents {
+		res = append(res, domain.ImportedContentResponse{
+			Source:      content.Provider,
+			ContentType: content.ContentType,
+			Value:       content.Value,
+			URL:         content.URL,
+			ImportedAt:  common.FormatTimeByParam(content.ImportedAt),
+		})
+	}
+	return res
+}
@@ -0,0 +1,10 @@
+package likes
+
+import (
+	"context"
+	"godating-dealls/internal/infra/jsonwebtoken"
+)
+
+type InputLikesBoundary interface {
+	ExecuteFetchLikesReceived(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, page int, size int, boundary OutputLikesBoundary) error
+}
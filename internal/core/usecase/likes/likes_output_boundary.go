@@ -0,0 +1,7 @@
+package likes
+
+import "godating-dealls/internal/domain"
+
+type OutputLikesBoundary interface {
+	LikesReceivedResponse(response domain.LikesReceivedResponse, err error)
+}
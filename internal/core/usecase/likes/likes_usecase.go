@@ -0,0 +1,125 @@
+package likes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/entitlements"
+	"godating-dealls/internal/core/entities/photos"
+	"godating-dealls/internal/core/entities/swipes"
+	"godating-dealls/internal/core/entities/users"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"godating-dealls/internal/infra/storage"
+	"log"
+)
+
+const (
+	defaultLikesPageSize = 20
+	maxLikesPageSize     = 50
+)
+
+// ErrLikesReceivedPremium is returned to accounts without a premium subscription
+var ErrLikesReceivedPremium = errors.New("seeing who liked you needs a premium subscription")
+
+type LikeUsecase struct {
+	DB                *sql.DB
+	SwipeEntity       swipes.SwipeEntity
+	UserEntity        users.UserEntity
+	EntitlementEntity entitlements.EntitlementEntity
+	PhotoEntity       photos.PhotoEntity
+	Storage           storage.Storage
+}
+
+func NewLikeUsecase(db *sql.DB, swipeEntity swipes.SwipeEntity, userEntity users.UserEntity, entitlementEntity entitlements.EntitlementEntity, photoEntity photos.PhotoEntity, storage storage.Storage) InputLikesBoundary {
+	return &LikeUsecase{
+		DB:                db,
+		SwipeEntity:       swipeEntity,
+		UserEntity:        userEntity,
+		EntitlementEntity: entitlementEntity,
+		PhotoEntity:       photoEntity,
+		Storage:           storage,
+	}
+}
+
+// ExecuteFetchLikesReceived is a premium feature, it pages through the accounts that liked the viewer and the viewer
+// did not swipe yet, super likes first then the latest like. Swiping one of them right makes a match
+func (l LikeUsecase) ExecuteFetchLikesReceived(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, page int, size int, boundary OutputLikesBoundary) error {
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = defaultLikesPageSize
+	}
+	if size > maxLikesPageSize {
+		size = maxLikesPageSize
+	}
+
+	fn := func(tx *sql.Tx) error {
+		// Not read only, a lapsed premium is revoked while its entitlements are read
+		entitled, err := l.EntitlementEntity.HasEntitlementEntity(ctx, tx, claims.AccountId, entitlements.EntitlementLikesReceived)
+		if err != nil {
+			return err
+		}
+		if !entitled {
+			return ErrLikesReceivedPremium
+		}
+
+		likeList, total, err := l.SwipeEntity.FindLikesReceivedEntity(ctx, tx, claims.AccountId, page, size)
+		if err != nil {
+			return err
+		}
+
+		accountIds := make([]int64, 0, len(likeList))
+		for _, like := range likeList {
+			accountIds = append(accountIds, like.AccountID)
+		}
+		usersByAccount, err := l.UserEntity.FindUserDetailsEntity(ctx, tx, accountIds)
+		if err != nil {
+			return err
+		}
+		photosByAccount, err := l.PhotoEntity.FindPhotosByAccountsEntity(ctx, tx, accountIds)
+		if err != nil {
+			return err
+		}
+		photosByAccount, err = l.PhotoEntity.ArrangePhotosForViewerEntity(ctx, tx, claims.AccountId, photosByAccount)
+		if err != nil {
+			return err
+		}
+
+		res := domain.LikesReceivedResponse{
+			Page:  page,
+			Size:  size,
+			Total: total,
+			Likes: make([]domain.LikeReceivedResponse, 0, len(likeList)),
+		}
+		for _, like := range likeList {
+			user := usersByAccount[like.AccountID]
+			response := domain.LikeReceivedResponse{
+				UserID:     user.UserID,
+				AccountID:  like.AccountID,
+				FullName:   user.FullName,
+				Photos:     make([]string, 0),
+				Age:        user.Age,
+				Gender:     user.Gender,
+				Address:    user.Address,
+				Bio:        user.Bio,
+				SuperLiked: like.SuperLiked,
+				LikedOn:    like.LikedOn.Format("2006-01-02"),
+			}
+			for _, photo := range photosByAccount[like.AccountID] {
+				response.Photos = append(response.Photos, l.Storage.URL(photo.StorageKey))
+			}
+			res.Likes = append(res.Likes, response)
+		}
+		boundary.LikesReceivedResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, l.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
@@ -0,0 +1,7 @@
+package login_histories
+
+import "context"
+
+type InputLoginHistoriesBoundary interface {
+	ExecuteLoginHistoryRetention(ctx context.Context) error
+}
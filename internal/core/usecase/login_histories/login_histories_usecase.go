@@ -0,0 +1,62 @@
+package login_histories
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/login_histories"
+	"log"
+	"time"
+)
+
+// DefaultRetentionDays raw login rows are kept that many days, config.Load reads LOGIN_HISTORY_RETENTION_DAYS
+const DefaultRetentionDays = 180
+
+type LoginHistoriesUsecase struct {
+	DB                   *sql.DB
+	LoginHistoriesEntity login_histories.LoginHistoriesEntity
+	RetentionDays        int
+}
+
+func NewLoginHistoriesUsecase(db *sql.DB, loginHistoriesEntity login_histories.LoginHistoriesEntity, retentionDays int) InputLoginHistoriesBoundary {
+	return &LoginHistoriesUsecase{DB: db, LoginHistoriesEntity: loginHistoriesEntity, RetentionDays: retentionDays}
+}
+
+// ExecuteLoginHistoryRetention summarises rows older than the retention window month by month, oldest first,
+// each month commits on its own so an interrupted run resumes where it stopped
+func (l LoginHistoriesUsecase) ExecuteLoginHistoryRetention(ctx context.Context) error {
+	cutoff := time.Now().AddDate(0, 0, -l.RetentionDays)
+
+	var total int64
+	for {
+		var deleted int64
+		fn := func(tx *sql.Tx) error {
+			oldest, err := l.LoginHistoriesEntity.FindOldestLoginHistoriesEntities(ctx, tx, cutoff)
+			if err != nil || oldest == nil {
+				return err
+			}
+
+			from := time.Date(oldest.Year(), oldest.Month(), 1, 0, 0, 0, 0, oldest.Location())
+			to := from.AddDate(0, 1, 0)
+			if to.After(cutoff) {
+				to = cutoff
+			}
+
+			deleted, err = l.LoginHistoriesEntity.SummarizeLoginHistoriesEntities(ctx, tx, from, to)
+			return err
+		}
+
+		err := common.WithExecuteTransactionalManager(ctx, l.DB, fn)
+		if err != nil {
+			log.Println("Transaction failed:", err)
+			return err
+		}
+		if deleted == 0 {
+			break
+		}
+		total += deleted
+	}
+
+	log.Printf("Login history retention: %d rows older than %d days summarised", total, l.RetentionDays)
+	return nil
+}
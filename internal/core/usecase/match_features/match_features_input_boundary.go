@@ -0,0 +1,11 @@
+package match_features
+
+import (
+	"context"
+	"godating-dealls/internal/domain"
+)
+
+type InputMatchFeatureBoundary interface {
+	ExecuteComputeMatchFeatures(ctx context.Context) error
+	ExecuteSaveMatchScores(ctx context.Context, request domain.MatchScoresRequest, boundary OutputMatchFeatureBoundary) error
+}
@@ -0,0 +1,7 @@
+package match_features
+
+import "godating-dealls/internal/domain"
+
+type OutputMatchFeatureBoundary interface {
+	MatchScoresResponse(response domain.MatchScoresResponse, err error)
+}
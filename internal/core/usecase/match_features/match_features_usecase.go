@@ -0,0 +1,73 @@
+package match_features
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/match_features"
+	"godating-dealls/internal/domain"
+	"log"
+	"time"
+)
+
+// matchScoreMaxAge matches the INTERVAL in the discovery queries, older scores are no longer used for ranking
+const matchScoreMaxAge = 7 * 24 * time.Hour
+
+type MatchFeatureUsecase struct {
+	DB                 *sql.DB
+	MatchFeatureEntity match_features.MatchFeatureEntity
+}
+
+func NewMatchFeatureUsecase(db *sql.DB, matchFeatureEntity match_features.MatchFeatureEntity) InputMatchFeatureBoundary {
+	return &MatchFeatureUsecase{DB: db, MatchFeatureEntity: matchFeatureEntity}
+}
+
+// ExecuteComputeMatchFeatures refreshes the feature tables the external model reads, one run replaces the previous snapshot
+func (m MatchFeatureUsecase) ExecuteComputeMatchFeatures(ctx context.Context) error {
+	now := time.Now().Truncate(time.Second)
+
+	fn := func(tx *sql.Tx) error {
+		run, err := m.MatchFeatureEntity.ComputeFeaturesEntity(ctx, tx, now, now.Add(-matchScoreMaxAge))
+		if err != nil {
+			return err
+		}
+		log.Printf("Match features as of %s: %d account rows, %d pair rows written, %d stale rows and %d expired scores deleted",
+			common.FormatTimeByParam(now), run.AccountFeatures, run.PairFeatures, run.StaleFeatures, run.ExpiredScores)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, m.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteSaveMatchScores stores a batch of model predictions, a score replaces the previous one of the same pair
+func (m MatchFeatureUsecase) ExecuteSaveMatchScores(ctx context.Context, request domain.MatchScoresRequest, boundary OutputMatchFeatureBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		scores := make([]domain.MatchScoreDto, 0, len(request.Scores))
+		for _, score := range request.Scores {
+			scores = append(scores, domain.MatchScoreDto{
+				AccountID:          score.AccountID,
+				CandidateAccountID: score.CandidateAccountID,
+				Score:              score.Score,
+				ModelVersion:       request.ModelVersion,
+			})
+		}
+
+		accepted, err := m.MatchFeatureEntity.SaveMatchScoresEntity(ctx, tx, scores)
+		if err != nil {
+			return err
+		}
+
+		boundary.MatchScoresResponse(domain.MatchScoresResponse{ModelVersion: request.ModelVersion, Accepted: accepted}, nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, m.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
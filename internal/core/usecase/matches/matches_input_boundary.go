@@ -0,0 +1,11 @@
+package matches
+
+import (
+	"context"
+	"godating-dealls/internal/infra/jsonwebtoken"
+)
+
+type InputMatchBoundary interface {
+	ExecuteFetchMatches(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputMatchBoundary) error
+	ExecuteUnmatch(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, matchId int64, boundary OutputMatchBoundary) error
+}
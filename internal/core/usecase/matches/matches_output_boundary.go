@@ -0,0 +1,7 @@
+package matches
+
+import "godating-dealls/internal/domain"
+
+type OutputMatchBoundary interface {
+	MatchesResponse(response []domain.MatchResponse, err error)
+}
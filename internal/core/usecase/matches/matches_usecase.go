@@ -0,0 +1,76 @@
+package matches
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/matches"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"log"
+)
+
+type MatchUsecase struct {
+	DB          *sql.DB
+	MatchEntity matches.MatchEntity
+}
+
+func NewMatchUsecase(db *sql.DB, matchEntity matches.MatchEntity) InputMatchBoundary {
+	return &MatchUsecase{DB: db, MatchEntity: matchEntity}
+}
+
+// ExecuteFetchMatches lists the accounts that liked the user back, matches are created by the swipe usecase
+func (m MatchUsecase) ExecuteFetchMatches(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputMatchBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		return m.matches(ctx, tx, claims.AccountId, boundary)
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, m.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteUnmatch ends a pair match for both accounts, the response is the matches the user has left
+func (m MatchUsecase) ExecuteUnmatch(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, matchId int64, boundary OutputMatchBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		match, err := m.MatchEntity.FindMatchForAccountEntity(ctx, tx, matchId, claims.AccountId)
+		if err != nil {
+			return err
+		}
+		if err := m.MatchEntity.UnmatchEntity(ctx, tx, match); err != nil {
+			return err
+		}
+		return m.matches(ctx, tx, claims.AccountId, boundary)
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, m.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (m MatchUsecase) matches(ctx context.Context, tx *sql.Tx, accountId int64, boundary OutputMatchBoundary) error {
+	views, err := m.MatchEntity.FindMatchesEntity(ctx, tx, accountId)
+	if err != nil {
+		return err
+	}
+
+	res := make([]domain.MatchResponse, 0, len(views))
+	for _, view := range views {
+		res = append(res, domain.MatchResponse{
+			MatchID:          view.MatchID,
+			MatchedAccountID: view.MatchedAccountID,
+			FullName:         view.FullName,
+			Age:              view.Age,
+			Gender:           view.Gender,
+			Bio:              view.Bio,
+			MatchedAt:        common.FormatTimeByParam(view.CreatedAt),
+		})
+	}
+
+	boundary.MatchesResponse(res, nil)
+	return nil
+}
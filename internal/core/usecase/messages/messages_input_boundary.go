@@ -0,0 +1,17 @@
+package messages
+
+import (
+	"context"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+)
+
+type InputMessageBoundary interface {
+	ExecuteSendMessage(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, matchId int64, request domain.ChatMessageRequest, boundary OutputMessageBoundary) error
+	ExecuteFetchMessages(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, matchId int64, beforeId int64, size int, boundary OutputMessageBoundary) error
+	ExecuteSubscribeMessages(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputMessageBoundary) error
+	ExecuteFetchParticipants(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, matchId int64, boundary OutputMessageBoundary) error
+	ExecuteMarkRead(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, matchId int64, request domain.ChatReadRequest, boundary OutputMessageBoundary) error
+	ExecuteLeaveChat(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, matchId int64, boundary OutputMessageBoundary) error
+	ExecuteDeliverReplicatedMessage(ctx context.Context, request domain.ReplicatedMessageRequest, boundary OutputMessageBoundary) error
+}
@@ -0,0 +1,11 @@
+package messages
+
+import "godating-dealls/internal/domain"
+
+type OutputMessageBoundary interface {
+	MessageResponse(response domain.ChatMessageResponse, err error)
+	MessagesResponse(response []domain.ChatMessageResponse, err error)
+	LiveMessageResponse(response domain.ChatMessageResponse)
+	ParticipantsResponse(response domain.ChatParticipantsResponse, err error)
+	ReplicatedMessageResponse(response domain.ReplicatedMessageResponse, err error)
+}
@@ -0,0 +1,290 @@
+package messages
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/blocks"
+	"godating-dealls/internal/core/entities/matches"
+	"godating-dealls/internal/core/entities/messages"
+	"godating-dealls/internal/core/entities/notifications"
+	"godating-dealls/internal/core/entities/safety"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"godating-dealls/internal/infra/realtime"
+	"godating-dealls/internal/infra/regions"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMessagesPageSize = 50
+	maxMessagesPageSize     = 100
+)
+
+// ErrInvalidReplicatedMessage the event of another region has no message or nobody to deliver it to
+var ErrInvalidReplicatedMessage = errors.New("replicated message has no message or accounts")
+
+type MessageUsecase struct {
+	DB                 *sql.DB
+	MessageEntity      messages.MessageEntity
+	MatchEntity        matches.MatchEntity
+	BlockEntity        blocks.BlockEntity
+	NotificationEntity notifications.NotificationEntity
+	SafetyEntity       safety.SafetyEntity
+	Hub                *realtime.Hub
+	// Replicator sends the messages to the other regions, a participant may hold its connections there
+	Replicator *regions.Replicator
+}
+
+func NewMessageUsecase(db *sql.DB, messageEntity messages.MessageEntity, matchEntity matches.MatchEntity, blockEntity blocks.BlockEntity, notificationEntity notifications.NotificationEntity, safetyEntity safety.SafetyEntity, hub *realtime.Hub, replicator *regions.Replicator) InputMessageBoundary {
+	return &MessageUsecase{DB: db, MessageEntity: messageEntity, MatchEntity: matchEntity, BlockEntity: blockEntity, NotificationEntity: notificationEntity, SafetyEntity: safetyEntity, Hub: hub, Replicator: replicator}
+}
+
+// ExecuteSendMessage stores the message when sender and receiver are matched, and pushes it to the live
+// connections of every participant once committed, the sender's other devices see it too. A duo match
+// is a group conversation of the four accounts, nobody can send while blocked with one of the others. In a group
+// of strangers, e.g. an event room, a block does not silence anyone. The other participants of a pair or duo match
+// also get a push, which does not show the message on the lock screen. While one of them has the late night mode on,
+// the first conversation of a new pair or duo match is filtered more strictly and links stay locked
+func (m MessageUsecase) ExecuteSendMessage(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, matchId int64, request domain.ChatMessageRequest, boundary OutputMessageBoundary) error {
+	var sent domain.ChatMessageResponse
+	var participants []int64
+	fn := func(tx *sql.Tx) error {
+		match, err := m.MatchEntity.FindMatchForAccountEntity(ctx, tx, matchId, claims.AccountId)
+		if err != nil {
+			return err
+		}
+		if match.Kind != matches.MatchKindGroup {
+			if err := m.BlockEntity.EnsureNotBlockedEntity(ctx, tx, claims.AccountId, match.ParticipantAccountIDs); err != nil {
+				return err
+			}
+			if err := m.SafetyEntity.CheckFirstConversationMessageEntity(ctx, tx, match, request.Body); err != nil {
+				return err
+			}
+		}
+
+		message, err := m.MessageEntity.SendMessageEntity(ctx, tx, domain.ChatMessageDto{
+			MatchID:         match.MatchID,
+			SenderAccountID: claims.AccountId,
+			Body:            request.Body,
+		})
+		if err != nil {
+			return err
+		}
+
+		if match.Kind != matches.MatchKindGroup {
+			var receivers []int64
+			for _, accountId := range match.ParticipantAccountIDs {
+				if accountId != claims.AccountId {
+					receivers = append(receivers, accountId)
+				}
+			}
+			err := m.NotificationEntity.NotifyEntity(ctx, tx, receivers, domain.NotificationDto{
+				Kind:  notifications.KindMessage,
+				Title: "New message",
+				Body:  "You have a new message from a match.",
+				Data:  map[string]string{"match_id": strconv.FormatInt(match.MatchID, 10), "message_id": strconv.FormatInt(message.MessageID, 10)},
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		sent = toChatMessageResponse(message)
+		participants = match.ParticipantAccountIDs
+		boundary.MessageResponse(sent, nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, m.DB, fn)
+	if err != nil {
+		common.MessagingLog.Errorf("Transaction failed: %v", err)
+		return err
+	}
+
+	for _, accountId := range participants {
+		m.Hub.Publish(accountId, sent)
+	}
+	m.Replicator.Replicate(domain.ReplicatedMessageRequest{AccountIDs: participants, Message: sent})
+	return nil
+}
+
+// ExecuteDeliverReplicatedMessage pushes a message another region stored to the live connections held here. It is
+// not replicated again, the sending region already sent it to every peer
+func (m MessageUsecase) ExecuteDeliverReplicatedMessage(ctx context.Context, request domain.ReplicatedMessageRequest, boundary OutputMessageBoundary) error {
+	if request.Message.MessageID == 0 || len(request.AccountIDs) == 0 {
+		return ErrInvalidReplicatedMessage
+	}
+	for _, accountId := range request.AccountIDs {
+		m.Hub.Publish(accountId, request.Message)
+	}
+	boundary.ReplicatedMessageResponse(domain.ReplicatedMessageResponse{Accounts: len(request.AccountIDs)}, nil)
+	return nil
+}
+
+// ExecuteFetchMessages pages backwards through the conversation of a match, newest first, nobody reads a pair or
+// duo conversation while blocked with one of the others
+func (m MessageUsecase) ExecuteFetchMessages(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, matchId int64, beforeId int64, size int, boundary OutputMessageBoundary) error {
+	if size <= 0 {
+		size = defaultMessagesPageSize
+	}
+	if size > maxMessagesPageSize {
+		size = maxMessagesPageSize
+	}
+
+	fn := func(tx *sql.Tx) error {
+		match, err := m.MatchEntity.FindMatchForAccountEntity(ctx, tx, matchId, claims.AccountId)
+		if err != nil {
+			return err
+		}
+		// Like sending, a block either way closes the history of a pair or duo match, not of a group of strangers
+		if match.Kind != matches.MatchKindGroup {
+			if err := m.BlockEntity.EnsureNotBlockedEntity(ctx, tx, claims.AccountId, match.ParticipantAccountIDs); err != nil {
+				return err
+			}
+		}
+
+		history, err := m.MessageEntity.FindMessagesEntity(ctx, tx, match.MatchID, beforeId, size)
+		if err != nil {
+			return err
+		}
+
+		res := make([]domain.ChatMessageResponse, 0, len(history))
+		for _, message := range history {
+			res = append(res, toChatMessageResponse(message))
+		}
+		boundary.MessagesResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, m.DB, fn)
+	if err != nil {
+		common.MessagingLog.Errorf("Transaction failed: %v", err)
+	}
+	return err
+}
+
+// ExecuteSubscribeMessages delivers the messages of every match of the account as they are sent,
+// it blocks until the connection ends or the token expires
+func (m MessageUsecase) ExecuteSubscribeMessages(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputMessageBoundary) error {
+	events, unsubscribe := m.Hub.Subscribe(claims.AccountId)
+	defer unsubscribe()
+
+	expiry := time.NewTimer(time.Until(claims.ExpiresAt.Time))
+	defer expiry.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-expiry.C:
+			return errors.New("token has expired")
+		case event := <-events:
+			if message, ok := event.(domain.ChatMessageResponse); ok {
+				boundary.LiveMessageResponse(message)
+			}
+		}
+	}
+}
+
+// ExecuteFetchParticipants lists who is in the conversation and how far each of them has read
+func (m MessageUsecase) ExecuteFetchParticipants(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, matchId int64, boundary OutputMessageBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		match, err := m.MatchEntity.FindMatchForAccountEntity(ctx, tx, matchId, claims.AccountId)
+		if err != nil {
+			return err
+		}
+		return m.participants(ctx, tx, match, boundary)
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, m.DB, fn)
+	if err != nil {
+		common.MessagingLog.Errorf("Transaction failed: %v", err)
+	}
+	return err
+}
+
+// ExecuteMarkRead moves the read state of the account forward, the other participants see it in the participants list
+func (m MessageUsecase) ExecuteMarkRead(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, matchId int64, request domain.ChatReadRequest, boundary OutputMessageBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		match, err := m.MatchEntity.FindMatchForAccountEntity(ctx, tx, matchId, claims.AccountId)
+		if err != nil {
+			return err
+		}
+		if err := m.MatchEntity.MarkReadEntity(ctx, tx, match.MatchID, claims.AccountId, request.MessageID); err != nil {
+			return err
+		}
+		return m.participants(ctx, tx, match, boundary)
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, m.DB, fn)
+	if err != nil {
+		common.MessagingLog.Errorf("Transaction failed: %v", err)
+	}
+	return err
+}
+
+// ExecuteLeaveChat takes the account out of a duo match or a group chat, it stops receiving its messages.
+// The response is the conversation as the remaining participants see it
+func (m MessageUsecase) ExecuteLeaveChat(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, matchId int64, boundary OutputMessageBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		match, err := m.MatchEntity.FindMatchForAccountEntity(ctx, tx, matchId, claims.AccountId)
+		if err != nil {
+			return err
+		}
+		if err := m.MatchEntity.LeaveMatchEntity(ctx, tx, match); err != nil {
+			return err
+		}
+		return m.participants(ctx, tx, match, boundary)
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, m.DB, fn)
+	if err != nil {
+		common.MessagingLog.Errorf("Transaction failed: %v", err)
+	}
+	return err
+}
+
+func (m MessageUsecase) participants(ctx context.Context, tx *sql.Tx, match domain.MatchDto, boundary OutputMessageBoundary) error {
+	participants, err := m.MatchEntity.FindParticipantsEntity(ctx, tx, match.MatchID)
+	if err != nil {
+		return err
+	}
+	unread, err := m.MessageEntity.CountUnreadMessagesEntity(ctx, tx, match.MatchID, match.AccountID)
+	if err != nil {
+		return err
+	}
+
+	res := domain.ChatParticipantsResponse{
+		MatchID:      match.MatchID,
+		Kind:         match.Kind,
+		Title:        match.Title,
+		UnreadCount:  unread,
+		Participants: make([]domain.ChatParticipantResponse, 0, len(participants)),
+	}
+	for _, participant := range participants {
+		res.Participants = append(res.Participants, domain.ChatParticipantResponse{
+			AccountID:         participant.AccountID,
+			Username:          participant.Username,
+			FullName:          participant.FullName,
+			LastReadMessageID: participant.LastReadMessageID,
+			JoinedAt:          common.FormatTimeByParam(participant.JoinedAt),
+		})
+	}
+	boundary.ParticipantsResponse(res, nil)
+	return nil
+}
+
+func toChatMessageResponse(message domain.ChatMessageDto) domain.ChatMessageResponse {
+	return domain.ChatMessageResponse{
+		MessageID:       message.MessageID,
+		MatchID:         message.MatchID,
+		SenderAccountID: message.SenderAccountID,
+		SenderUsername:  message.SenderUsername,
+		SenderFullName:  message.SenderFullName,
+		Body:            message.Body,
+		SentAt:          common.FormatTimeByParam(message.CreatedAt),
+	}
+}
@@ -0,0 +1,13 @@
+package network_rules
+
+import (
+	"context"
+	"godating-dealls/internal/domain"
+)
+
+type InputNetworkRuleBoundary interface {
+	ExecuteLoadNetworkRules(ctx context.Context) error
+	ExecuteFetchNetworkRules(ctx context.Context, boundary OutputNetworkRuleBoundary) error
+	ExecuteCreateNetworkRule(ctx context.Context, clientAddress string, request domain.NetworkRuleRequest, boundary OutputNetworkRuleBoundary) error
+	ExecuteDeleteNetworkRule(ctx context.Context, clientAddress string, ruleId int64, boundary OutputNetworkRuleBoundary) error
+}
@@ -0,0 +1,9 @@
+package network_rules
+
+import "godating-dealls/internal/domain"
+
+type OutputNetworkRuleBoundary interface {
+	NetworkRulesResponse(response []domain.NetworkRuleResponse, err error)
+	NetworkRuleResponse(response domain.NetworkRuleResponse, err error)
+	DeleteNetworkRuleResponse(response domain.DeleteNetworkRuleResponse, err error)
+}
@@ -0,0 +1,163 @@
+package network_rules
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/network_rules"
+	"godating-dealls/internal/domain"
+	"log"
+	"net/netip"
+)
+
+// networkRulesPath is where the rules are managed, a change that would refuse the admin making it is rejected
+const networkRulesPath = "/godating-dealls/api/v1/admin/network-rules"
+
+type NetworkRuleUsecase struct {
+	DB                *sql.DB
+	NetworkRuleEntity network_rules.NetworkRuleEntity
+	NetworkACL        *common.NetworkACL
+}
+
+func NewNetworkRuleUsecase(db *sql.DB, networkRuleEntity network_rules.NetworkRuleEntity, networkACL *common.NetworkACL) InputNetworkRuleBoundary {
+	return &NetworkRuleUsecase{DB: db, NetworkRuleEntity: networkRuleEntity, NetworkACL: networkACL}
+}
+
+// ExecuteLoadNetworkRules replaces the rules in memory, it runs at startup and as a job so changes made on
+// another instance arrive too
+func (n NetworkRuleUsecase) ExecuteLoadNetworkRules(ctx context.Context) error {
+	var rules []domain.NetworkRuleDto
+	fn := func(tx *sql.Tx) error {
+		var err error
+		rules, err = n.NetworkRuleEntity.FindNetworkRulesEntity(ctx, tx)
+		return err
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, n.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+		return err
+	}
+	n.NetworkACL.Replace(toNetworkRules(rules))
+	return nil
+}
+
+func (n NetworkRuleUsecase) ExecuteFetchNetworkRules(ctx context.Context, boundary OutputNetworkRuleBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		rules, err := n.NetworkRuleEntity.FindNetworkRulesEntity(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		res := make([]domain.NetworkRuleResponse, 0, len(rules))
+		for _, rule := range rules {
+			res = append(res, toNetworkRuleResponse(rule))
+		}
+		boundary.NetworkRulesResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, n.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (n NetworkRuleUsecase) ExecuteCreateNetworkRule(ctx context.Context, clientAddress string, request domain.NetworkRuleRequest, boundary OutputNetworkRuleBoundary) error {
+	var saved domain.NetworkRuleDto
+	fn := func(tx *sql.Tx) error {
+		var err error
+		saved, err = n.NetworkRuleEntity.SaveNetworkRuleEntity(ctx, tx, domain.NetworkRuleDto{
+			List:       request.List,
+			RouteGroup: request.RouteGroup,
+			CIDR:       request.CIDR,
+			Note:       request.Note,
+		})
+		if err != nil {
+			return err
+		}
+		return n.checkNotLockedOut(ctx, tx, clientAddress)
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, n.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+		return err
+	}
+
+	n.reload(ctx)
+	boundary.NetworkRuleResponse(toNetworkRuleResponse(saved), nil)
+	return nil
+}
+
+func (n NetworkRuleUsecase) ExecuteDeleteNetworkRule(ctx context.Context, clientAddress string, ruleId int64, boundary OutputNetworkRuleBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		if err := n.NetworkRuleEntity.DeleteNetworkRuleEntity(ctx, tx, ruleId); err != nil {
+			return err
+		}
+		return n.checkNotLockedOut(ctx, tx, clientAddress)
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, n.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+		return err
+	}
+
+	n.reload(ctx)
+	boundary.DeleteNetworkRuleResponse(domain.DeleteNetworkRuleResponse{
+		RuleID:  ruleId,
+		Message: "Network rule removed",
+	}, nil)
+	return nil
+}
+
+// checkNotLockedOut evaluates the rules as the transaction left them, before they are committed
+func (n NetworkRuleUsecase) checkNotLockedOut(ctx context.Context, tx *sql.Tx, clientAddress string) error {
+	address, err := netip.ParseAddr(clientAddress)
+	if err != nil {
+		return fmt.Errorf("%w: the address of this request is unknown", network_rules.ErrNetworkRuleLockout)
+	}
+
+	rules, err := n.NetworkRuleEntity.FindNetworkRulesEntity(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if !common.NetworkRulesAllow(toNetworkRules(rules), address, networkRulesPath) {
+		return fmt.Errorf("%w: %s would no longer reach it", network_rules.ErrNetworkRuleLockout, address)
+	}
+	return nil
+}
+
+// reload applies a committed change on this instance right away, the others pick it up with the refresh job
+func (n NetworkRuleUsecase) reload(ctx context.Context) {
+	if err := n.ExecuteLoadNetworkRules(ctx); err != nil {
+		log.Println("Failed to reload network rules:", err)
+	}
+}
+
+func toNetworkRules(rules []domain.NetworkRuleDto) []common.NetworkRule {
+	res := make([]common.NetworkRule, 0, len(rules))
+	for _, rule := range rules {
+		prefix, err := network_rules.ParseNetworkPrefix(rule.CIDR)
+		if err != nil {
+			log.Printf("Skipping network rule %d with invalid cidr %q", rule.RuleID, rule.CIDR)
+			continue
+		}
+		res = append(res, common.NetworkRule{List: rule.List, Group: rule.RouteGroup, Prefix: prefix})
+	}
+	return res
+}
+
+func toNetworkRuleResponse(rule domain.NetworkRuleDto) domain.NetworkRuleResponse {
+	return domain.NetworkRuleResponse{
+		RuleID:     rule.RuleID,
+		List:       rule.List,
+		RouteGroup: rule.RouteGroup,
+		CIDR:       rule.CIDR,
+		Note:       rule.Note,
+		CreatedAt:  common.FormatTimeByParam(rule.CreatedAt),
+	}
+}
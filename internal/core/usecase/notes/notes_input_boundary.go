@@ -0,0 +1,14 @@
+package notes
+
+import (
+	"context"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+)
+
+type InputNoteBoundary interface {
+	ExecuteCreateNote(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.NoteRequest, boundary OutputNoteBoundary) error
+	ExecuteFetchNotes(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, targetAccountId int64, boundary OutputNoteBoundary) error
+	ExecuteUpdateNote(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, noteId int64, request domain.NoteRequest, boundary OutputNoteBoundary) error
+	ExecuteDeleteNote(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, noteId int64, boundary OutputNoteBoundary) error
+}
@@ -0,0 +1,9 @@
+package notes
+
+import "godating-dealls/internal/domain"
+
+type OutputNoteBoundary interface {
+	NoteResponse(response domain.NoteResponse, err error)
+	NotesResponse(response []domain.NoteResponse, err error)
+	DeleteNoteResponse(response domain.DeleteNoteResponse, err error)
+}
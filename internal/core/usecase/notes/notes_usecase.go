@@ -0,0 +1,137 @@
+package notes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/accounts"
+	"godating-dealls/internal/core/entities/notes"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"log"
+)
+
+type NoteUsecase struct {
+	DB            *sql.DB
+	NoteEntity    notes.NoteEntity
+	AccountEntity accounts.AccountEntity
+}
+
+func NewNoteUsecase(db *sql.DB, noteEntity notes.NoteEntity, accountEntity accounts.AccountEntity) InputNoteBoundary {
+	return &NoteUsecase{
+		DB:            db,
+		NoteEntity:    noteEntity,
+		AccountEntity: accountEntity,
+	}
+}
+
+func (n NoteUsecase) ExecuteCreateNote(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.NoteRequest, boundary OutputNoteBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		// Make sure the profile the note is about exists
+		_, err := n.AccountEntity.FindAccountDetails(ctx, tx, request.TargetAccountID)
+		if err != nil {
+			return errors.New("invalid fetch account")
+		}
+
+		note, err := n.NoteEntity.SaveNoteEntity(ctx, tx, domain.NoteDto{
+			OwnerAccountID:  claims.AccountId,
+			TargetAccountID: request.TargetAccountID,
+			Content:         request.Content,
+		})
+		if err != nil {
+			return err
+		}
+
+		boundary.NoteResponse(toNoteResponse(note), nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, n.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (n NoteUsecase) ExecuteFetchNotes(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, targetAccountId int64, boundary OutputNoteBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		// Notes are always scoped to the owner so the other party can never read them
+		notesList, err := n.NoteEntity.FindNotesEntity(ctx, tx, claims.AccountId, targetAccountId)
+		if err != nil {
+			return err
+		}
+
+		var res []domain.NoteResponse
+		for _, note := range notesList {
+			res = append(res, toNoteResponse(note))
+		}
+		boundary.NotesResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, n.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (n NoteUsecase) ExecuteUpdateNote(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, noteId int64, request domain.NoteRequest, boundary OutputNoteBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		// The target of a note never changes, only the content of the request is used
+		note, err := n.NoteEntity.UpdateNoteEntity(ctx, tx, domain.NoteDto{
+			NoteID:         noteId,
+			OwnerAccountID: claims.AccountId,
+			Content:        request.Content,
+		})
+		if err != nil {
+			return err
+		}
+
+		boundary.NoteResponse(toNoteResponse(note), nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, n.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (n NoteUsecase) ExecuteDeleteNote(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, noteId int64, boundary OutputNoteBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		err := n.NoteEntity.DeleteNoteEntity(ctx, tx, claims.AccountId, noteId)
+		if err != nil {
+			return err
+		}
+
+		boundary.DeleteNoteResponse(domain.DeleteNoteResponse{
+			NoteID:  noteId,
+			Message: "Note deleted",
+		}, nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, n.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func toNoteResponse(note domain.NoteDto) domain.NoteResponse {
+	res := domain.NoteResponse{
+		NoteID:          note.NoteID,
+		TargetAccountID: note.TargetAccountID,
+		Content:         note.Content,
+	}
+	if !note.CreatedAt.IsZero() {
+		res.CreatedAt = common.FormatTimeByParam(note.CreatedAt)
+	}
+	if !note.UpdatedAt.IsZero() {
+		res.UpdatedAt = common.FormatTimeByParam(note.UpdatedAt)
+	}
+	return res
+}
@@ -0,0 +1,14 @@
+package notifications
+
+import (
+	"context"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+)
+
+type InputNotificationBoundary interface {
+	ExecuteRegisterDevice(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.RegisterDeviceRequest, boundary OutputNotificationBoundary) error
+	ExecuteDeliverPushes(ctx context.Context) error
+	ExecuteFetchNotifications(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, unreadOnly bool, page int, size int, boundary OutputNotificationBoundary) error
+	ExecuteMarkNotificationsRead(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.MarkNotificationsReadRequest, boundary OutputNotificationBoundary) error
+}
@@ -0,0 +1,9 @@
+package notifications
+
+import "godating-dealls/internal/domain"
+
+type OutputNotificationBoundary interface {
+	DeviceResponse(response domain.DeviceResponse, err error)
+	NotificationsResponse(response domain.NotificationsResponse, err error)
+	NotificationsReadResponse(response domain.NotificationsReadResponse, err error)
+}
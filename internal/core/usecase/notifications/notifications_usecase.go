@@ -0,0 +1,287 @@
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/notifications"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"godating-dealls/internal/infra/push"
+	"log"
+	"time"
+)
+
+const (
+	// deliveryBatchSize pushes are claimed at once, a run claims batches until the queue has no due push left
+	deliveryBatchSize  = 100
+	maxDeliveryBatches = 20
+	// deliveryLease is how long a claimed push is left to its worker before another one takes it again
+	deliveryLease = 5 * time.Minute
+	// deliveredRetention the pushes are kept this long after they were queued, for support questions
+	deliveredRetention = 7 * 24 * time.Hour
+	// feedRetention the feed keeps the notifications of the last 90 days, read or not
+	feedRetention = 90 * 24 * time.Hour
+
+	defaultNotificationsPageSize = 20
+	maxNotificationsPageSize     = 50
+)
+
+type NotificationUsecase struct {
+	DB                 *sql.DB
+	NotificationEntity notifications.NotificationEntity
+	Providers          map[string]push.Provider
+}
+
+func NewNotificationUsecase(db *sql.DB, notificationEntity notifications.NotificationEntity, providers map[string]push.Provider) InputNotificationBoundary {
+	return &NotificationUsecase{DB: db, NotificationEntity: notificationEntity, Providers: providers}
+}
+
+// ExecuteRegisterDevice is called by the app on every start, the token can change at any time
+func (n NotificationUsecase) ExecuteRegisterDevice(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.RegisterDeviceRequest, boundary OutputNotificationBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		device, err := n.NotificationEntity.RegisterDeviceEntity(ctx, tx, claims.AccountId, request.Provider, request.Token)
+		if err != nil {
+			return err
+		}
+
+		boundary.DeviceResponse(domain.DeviceResponse{
+			DeviceID:     device.DeviceID,
+			Provider:     device.Provider,
+			RegisteredAt: common.FormatTimeByParam(device.UpdatedAt),
+		}, nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, n.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteDeliverPushes is the worker of the push queue, run by the push delivery job. The pushes are claimed in one
+// transaction and settled in another, so no row is locked while the providers are called
+func (n NotificationUsecase) ExecuteDeliverPushes(ctx context.Context) error {
+	handled := 0
+	for batch := 0; batch < maxDeliveryBatches && ctx.Err() == nil; batch++ {
+		claimed, err := n.deliverBatch(ctx)
+		if err != nil {
+			return err
+		}
+		handled += claimed
+		if claimed < deliveryBatchSize {
+			break
+		}
+	}
+
+	var purged, purgedFeed int64
+	fn := func(tx *sql.Tx) error {
+		var err error
+		purged, err = n.NotificationEntity.PurgeDeliveredPushesEntity(ctx, tx, time.Now().Add(-deliveredRetention))
+		if err != nil {
+			return err
+		}
+		purgedFeed, err = n.NotificationEntity.PurgeFeedEntity(ctx, tx, time.Now().Add(-feedRetention))
+		return err
+	}
+	if err := common.WithExecuteTransactionalManager(ctx, n.DB, fn); err != nil {
+		log.Println("Transaction failed:", err)
+		return err
+	}
+
+	if handled > 0 || purged > 0 || purgedFeed > 0 {
+		common.PushLog.Infof("Pushes handled: %d, purged: %d, feed notifications purged: %d", handled, purged, purgedFeed)
+	}
+	return nil
+}
+
+// ExecuteFetchNotifications pages through the in-app feed of the account, newest first, unreadOnly leaves out the
+// read ones. The unread count is the one of the whole feed
+func (n NotificationUsecase) ExecuteFetchNotifications(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, unreadOnly bool, page int, size int, boundary OutputNotificationBoundary) error {
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = defaultNotificationsPageSize
+	}
+	if size > maxNotificationsPageSize {
+		size = maxNotificationsPageSize
+	}
+
+	fn := func(tx *sql.Tx) error {
+		feed, err := n.NotificationEntity.FindFeedEntity(ctx, tx, claims.AccountId, unreadOnly, page, size)
+		if err != nil {
+			return err
+		}
+		total, unread, err := n.NotificationEntity.CountFeedEntity(ctx, tx, claims.AccountId)
+		if err != nil {
+			return err
+		}
+		if unreadOnly {
+			total = unread
+		}
+
+		res := domain.NotificationsResponse{
+			Page:          page,
+			Size:          size,
+			Total:         total,
+			UnreadCount:   unread,
+			Notifications: make([]domain.NotificationResponse, 0, len(feed)),
+		}
+		for _, notification := range feed {
+			res.Notifications = append(res.Notifications, toNotificationResponse(notification))
+		}
+		boundary.NotificationsResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, n.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteMarkNotificationsRead marks the notifications read, the app calls it without ids once the feed was seen
+func (n NotificationUsecase) ExecuteMarkNotificationsRead(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.MarkNotificationsReadRequest, boundary OutputNotificationBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		marked, err := n.NotificationEntity.MarkFeedReadEntity(ctx, tx, claims.AccountId, request.NotificationIDs)
+		if err != nil {
+			return err
+		}
+		_, unread, err := n.NotificationEntity.CountFeedEntity(ctx, tx, claims.AccountId)
+		if err != nil {
+			return err
+		}
+
+		boundary.NotificationsReadResponse(domain.NotificationsReadResponse{Marked: marked, UnreadCount: unread}, nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, n.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func toNotificationResponse(notification domain.FeedNotificationDto) domain.NotificationResponse {
+	res := domain.NotificationResponse{
+		NotificationID: notification.NotificationID,
+		Kind:           notification.Notification.Kind,
+		Title:          notification.Notification.Title,
+		Body:           notification.Notification.Body,
+		Data:           notification.Notification.Data,
+		Read:           notification.ReadAt != nil,
+		CreatedAt:      common.FormatTimeByParam(notification.CreatedAt),
+	}
+	if res.Data == nil {
+		res.Data = map[string]string{}
+	}
+	if notification.ReadAt != nil {
+		res.ReadAt = common.FormatTimeByParam(*notification.ReadAt)
+	}
+	return res
+}
+
+// pushResult is how the delivery of one push went on the devices of its account
+type pushResult struct {
+	push   domain.PushDto
+	status string
+	err    error
+}
+
+func (n NotificationUsecase) deliverBatch(ctx context.Context) (int, error) {
+	var pushes []domain.PushDto
+	var devices map[int64][]domain.DeviceDto
+	claim := func(tx *sql.Tx) error {
+		var err error
+		pushes, err = n.NotificationEntity.ClaimDuePushesEntity(ctx, tx, time.Now(), deliveryBatchSize, deliveryLease)
+		if err != nil {
+			return err
+		}
+		accountIds := make([]int64, 0, len(pushes))
+		for _, p := range pushes {
+			accountIds = append(accountIds, p.AccountID)
+		}
+		devices, err = n.NotificationEntity.FindDevicesEntity(ctx, tx, accountIds)
+		return err
+	}
+	if err := common.WithExecuteTransactionalManager(ctx, n.DB, claim); err != nil {
+		log.Println("Transaction failed:", err)
+		return 0, err
+	}
+	if len(pushes) == 0 {
+		return 0, nil
+	}
+
+	results := make([]pushResult, 0, len(pushes))
+	var invalidTokens []string
+	for _, p := range pushes {
+		result, invalid := n.send(ctx, p, devices[p.AccountID])
+		results = append(results, result)
+		invalidTokens = append(invalidTokens, invalid...)
+	}
+
+	// The lease ends a claimed push that could not be settled, it is sent again then
+	settle := func(tx *sql.Tx) error {
+		now := time.Now()
+		for _, result := range results {
+			var err error
+			if result.err != nil {
+				err = n.NotificationEntity.RetryPushEntity(ctx, tx, result.push, now, result.err.Error())
+			} else {
+				err = n.NotificationEntity.CompletePushEntity(ctx, tx, result.push.PushID, result.status, "")
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return n.NotificationEntity.RemoveDevicesEntity(ctx, tx, invalidTokens)
+	}
+	if err := common.WithExecuteTransactionalManager(context.WithoutCancel(ctx), n.DB, settle); err != nil {
+		log.Println("Transaction failed:", err)
+		return 0, err
+	}
+	return len(pushes), nil
+}
+
+// send pushes to every device of the account. The push counts as sent when one device got it, it is only retried
+// when every device failed for a reason other than an invalid token
+func (n NotificationUsecase) send(ctx context.Context, p domain.PushDto, devices []domain.DeviceDto) (pushResult, []string) {
+	message := push.Message{Title: p.Notification.Title, Body: p.Notification.Body, Data: map[string]string{"kind": p.Notification.Kind}}
+	for key, value := range p.Notification.Data {
+		message.Data[key] = value
+	}
+
+	var invalidTokens []string
+	var lastErr error
+	sent := false
+	for _, device := range devices {
+		provider, ok := n.Providers[device.Provider]
+		if !ok {
+			continue
+		}
+		err := provider.Send(ctx, device.Token, message)
+		switch {
+		case err == nil:
+			sent = true
+		case errors.Is(err, push.ErrInvalidToken):
+			invalidTokens = append(invalidTokens, device.Token)
+		default:
+			common.PushLog.Warnf("Push %d to device %d failed: %v", p.PushID, device.DeviceID, err)
+			lastErr = err
+		}
+	}
+
+	switch {
+	case sent:
+		return pushResult{push: p, status: notifications.PushSent}, invalidTokens
+	case lastErr != nil:
+		return pushResult{push: p, err: lastErr}, invalidTokens
+	default:
+		return pushResult{push: p, status: notifications.PushSkipped}, invalidTokens
+	}
+}
@@ -0,0 +1,8 @@
+package outbox
+
+import "context"
+
+type InputOutboxBoundary interface {
+	ExecuteRelayEvents(ctx context.Context) (int, error)
+	ExecuteRelayLoop(ctx context.Context)
+}
@@ -0,0 +1,150 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/outbox"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/eventbus"
+	"log"
+	"time"
+)
+
+const (
+	// relayBatchSize events are claimed at once, a pass claims batches until no event is due
+	relayBatchSize  = 100
+	maxRelayBatches = 20
+	// relayLease is how long a claimed event is left to its relayer before another one takes it again
+	relayLease = time.Minute
+	// publishedRetention the published events are kept this long, to look into what a consumer missed
+	publishedRetention = 3 * 24 * time.Hour
+	purgeInterval      = time.Hour
+
+	// DefaultRelayInterval config.Load reads OUTBOX_RELAY_INTERVAL
+	DefaultRelayInterval = time.Second
+)
+
+type OutboxUsecase struct {
+	DB           *sql.DB
+	OutboxEntity outbox.OutboxEntity
+	Bus          eventbus.Bus
+	// Interval is the pause of the relayer once the outbox has no due event, OUTBOX_RELAY_INTERVAL
+	Interval time.Duration
+}
+
+func NewOutboxUsecase(db *sql.DB, outboxEntity outbox.OutboxEntity, bus eventbus.Bus, interval time.Duration) InputOutboxBoundary {
+	return &OutboxUsecase{DB: db, OutboxEntity: outboxEntity, Bus: bus, Interval: interval}
+}
+
+// ExecuteRelayLoop publishes the outbox until the context ends, every instance runs one. The claims keep two
+// relayers from publishing the same event at once
+func (o OutboxUsecase) ExecuteRelayLoop(ctx context.Context) {
+	log.Printf("Outbox relayer started on the %s bus", o.Bus.Name())
+	lastPurge := time.Time{}
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Outbox relayer stopped")
+			return
+		case <-timer.C:
+		}
+
+		if _, err := o.ExecuteRelayEvents(ctx); err != nil && ctx.Err() == nil {
+			log.Println("Outbox relay failed:", err)
+		}
+		if time.Since(lastPurge) >= purgeInterval && ctx.Err() == nil {
+			o.purge(ctx)
+			lastPurge = time.Now()
+		}
+		timer.Reset(o.Interval)
+	}
+}
+
+// ExecuteRelayEvents publishes the due events, oldest first, and returns how many were published. The events are
+// claimed in one transaction and settled in another, so no row is locked while the bus is called
+func (o OutboxUsecase) ExecuteRelayEvents(ctx context.Context) (int, error) {
+	published := 0
+	for batch := 0; batch < maxRelayBatches && ctx.Err() == nil; batch++ {
+		claimed, sent, err := o.relayBatch(ctx)
+		published += sent
+		if err != nil {
+			return published, err
+		}
+		if claimed < relayBatchSize {
+			break
+		}
+	}
+	return published, nil
+}
+
+func (o OutboxUsecase) relayBatch(ctx context.Context) (int, int, error) {
+	var events []domain.OutboxEventDto
+	claim := func(tx *sql.Tx) error {
+		var err error
+		events, err = o.OutboxEntity.ClaimDueEventsEntity(ctx, tx, time.Now(), relayBatchSize, relayLease)
+		return err
+	}
+	if err := common.WithExecuteTransactionalManager(ctx, o.DB, claim); err != nil {
+		return 0, 0, err
+	}
+	if len(events) == 0 {
+		return 0, 0, nil
+	}
+
+	failures := map[int64]error{}
+	for _, event := range events {
+		err := o.Bus.Publish(ctx, eventbus.Event{
+			ID:          event.EventID,
+			Kind:        event.Kind,
+			AggregateID: event.AggregateID,
+			Payload:     json.RawMessage(event.Payload),
+			OccurredAt:  event.CreatedAt,
+		})
+		if err != nil {
+			log.Printf("Outbox event %d (%s) not published, attempt %d: %v", event.EventID, event.Kind, event.Attempts, err)
+			failures[event.EventID] = err
+		}
+	}
+
+	// The lease ends a claimed event that could not be settled, it is published again then
+	settle := func(tx *sql.Tx) error {
+		now := time.Now()
+		for _, event := range events {
+			var err error
+			if failure, failed := failures[event.EventID]; failed {
+				err = o.OutboxEntity.RetryEventEntity(ctx, tx, event, now, failure.Error())
+			} else {
+				err = o.OutboxEntity.CompleteEventEntity(ctx, tx, event.EventID)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := common.WithExecuteTransactionalManager(context.WithoutCancel(ctx), o.DB, settle); err != nil {
+		return len(events), 0, err
+	}
+	return len(events), len(events) - len(failures), nil
+}
+
+func (o OutboxUsecase) purge(ctx context.Context) {
+	var purged int64
+	fn := func(tx *sql.Tx) error {
+		var err error
+		purged, err = o.OutboxEntity.PurgePublishedEventsEntity(ctx, tx, time.Now().Add(-publishedRetention))
+		return err
+	}
+	if err := common.WithExecuteTransactionalManager(ctx, o.DB, fn); err != nil {
+		log.Println("Transaction failed:", err)
+		return
+	}
+	if purged > 0 {
+		log.Printf("Outbox events purged: %d", purged)
+	}
+}
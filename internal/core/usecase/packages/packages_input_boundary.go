@@ -3,9 +3,13 @@ package packages
 import (
 	"context"
 	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
 )
 
 type InputPackageBoundary interface {
-	ExecuteGetAllPackages(ctx context.Context, token string, boundary BoundaryPackageOutput) error
-	ExecutePurchasedPackages(ctx context.Context, token string, request domain.PurchasePackageRequest, boundary BoundaryPackageOutput) error
+	ExecuteGetAllPackages(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary BoundaryPackageOutput) error
+	ExecutePurchasedPackages(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.PurchasePackageRequest, boundary BoundaryPackageOutput) error
+	ExecutePurchasePremium(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.PremiumPurchaseRequest, boundary BoundaryPackageOutput) error
+	ExecuteFindPremiumStatus(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary BoundaryPackageOutput) error
+	ExecuteExpirePremiums(ctx context.Context) error
 }
@@ -5,4 +5,6 @@ import "godating-dealls/internal/domain"
 type BoundaryPackageOutput interface {
 	PackageResponse([]domain.PackageResponse, error)
 	PurchasePackageResponse(domain.PurchasePackageResponse, error)
+	PremiumPurchaseResponse(domain.PremiumStatusResponse, error)
+	PremiumStatusResponse(domain.PremiumStatusResponse, error)
 }
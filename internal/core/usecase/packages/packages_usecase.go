@@ -7,10 +7,12 @@ import (
 	"godating-dealls/internal/common"
 	"godating-dealls/internal/core/entities/accounts"
 	"godating-dealls/internal/core/entities/daily_quotas"
+	entitlementsentity "godating-dealls/internal/core/entities/entitlements"
 	"godating-dealls/internal/core/entities/packages"
 	"godating-dealls/internal/domain"
 	"godating-dealls/internal/infra/jsonwebtoken"
 	"log"
+	"time"
 )
 
 type PackageUsecase struct {
@@ -18,28 +20,25 @@ type PackageUsecase struct {
 	PackageEntity     packages.PackageEntity
 	AccountEntity     accounts.AccountEntity
 	DailyQuotasEntity daily_quotas.DailyQuotasEntity
+	EntitlementEntity entitlementsentity.EntitlementEntity
 }
 
 func NewPackageUsecase(db *sql.DB,
 	packageEntity packages.PackageEntity,
 	accountEntity accounts.AccountEntity,
-	dailyQuotasEntity daily_quotas.DailyQuotasEntity) InputPackageBoundary {
+	dailyQuotasEntity daily_quotas.DailyQuotasEntity,
+	entitlementEntity entitlementsentity.EntitlementEntity) InputPackageBoundary {
 	return &PackageUsecase{
 		DB:                db,
 		PackageEntity:     packageEntity,
 		AccountEntity:     accountEntity,
 		DailyQuotasEntity: dailyQuotasEntity,
+		EntitlementEntity: entitlementEntity,
 	}
 }
 
-func (p PackageUsecase) ExecuteGetAllPackages(ctx context.Context, token string, boundary BoundaryPackageOutput) error {
+func (p PackageUsecase) ExecuteGetAllPackages(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary BoundaryPackageOutput) error {
 	fn := func(tx *sql.Tx) error {
-		// Verify token is not expired
-		_, err := jsonwebtoken.VerifyJWTToken(token)
-		if err != nil {
-			return errors.New("invalid token")
-		}
-
 		res, err := p.PackageEntity.GetAllPackagesEntity(ctx, tx)
 		if err != nil {
 			return errors.New("could not get all packages entity")
@@ -70,49 +69,146 @@ func (p PackageUsecase) ExecuteGetAllPackages(ctx context.Context, token string,
 	return err
 }
 
-func (p PackageUsecase) ExecutePurchasedPackages(ctx context.Context, token string, request domain.PurchasePackageRequest, boundary BoundaryPackageOutput) error {
+// ExecutePurchasedPackages is the purchase of the first app versions, the package is bought at its current price
+// whatever duration or swipes the request claims
+func (p PackageUsecase) ExecutePurchasedPackages(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.PurchasePackageRequest, boundary BoundaryPackageOutput) error {
 	fn := func(tx *sql.Tx) error {
-		claims, err := jsonwebtoken.VerifyJWTToken(token)
+		pkg, _, err := p.purchasePremium(ctx, tx, claims.AccountId, domain.PremiumPurchaseRequest{PackageID: request.PackageID, Price: &request.Price})
 		if err != nil {
-			return errors.New("invalid token")
+			return err
 		}
 
-		packageDto := domain.PackageDto{
-			PackageID:                request.PackageID,
-			PackageName:              request.PackageName,
-			Price:                    request.Price,
-			PackageDurationInMonthly: request.PackageDurationInMonthly,
-			UnlimitedSwipes:          request.UnlimitedSwipes,
-			AccountID:                claims.AccountId,
-		}
-		err = p.PackageEntity.PurchasePackage(ctx, tx, packageDto)
-		if err != nil {
-			return errors.New("could not purchase package")
-		}
+		boundary.PurchasePackageResponse(domain.PurchasePackageResponse{
+			PackageID: pkg.PackageID,
+			Price:     pkg.Price,
+			Message:   "Purchased package successfully",
+		}, nil)
+
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, p.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
 
-		// if success purchase update total quota today to unlimited and account to verified
-		err = p.AccountEntity.UpdateAccountVerified(ctx, tx, claims.AccountId)
+func (p PackageUsecase) ExecutePurchasePremium(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.PremiumPurchaseRequest, boundary BoundaryPackageOutput) error {
+	fn := func(tx *sql.Tx) error {
+		_, entitlements, err := p.purchasePremium(ctx, tx, claims.AccountId, request)
 		if err != nil {
-			return errors.New("could not update account verified")
+			return err
 		}
 
-		err = p.DailyQuotasEntity.UpdateTotalQuotasInPremiumAccount(ctx, tx, claims.AccountId)
+		boundary.PremiumPurchaseResponse(toPremiumStatusResponse(entitlements), nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, p.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (p PackageUsecase) ExecuteFindPremiumStatus(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary BoundaryPackageOutput) error {
+	fn := func(tx *sql.Tx) error {
+		// Not read only, a lapsed premium is revoked while its entitlements are read
+		entitlements, err := p.EntitlementEntity.FindEntitlementsEntity(ctx, tx, claims.AccountId)
 		if err != nil {
-			return errors.New("could not update total quotas")
+			return err
 		}
 
-		boundary.PurchasePackageResponse(domain.PurchasePackageResponse{
-			PackageID: request.PackageID,
-			Price:     request.Price,
-			Message:   "Purchased package successfully",
-		}, nil)
+		boundary.PremiumStatusResponse(toPremiumStatusResponse(entitlements), nil)
+		return nil
+	}
 
+	err := common.WithExecuteTransactionalManager(ctx, p.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteExpirePremiums takes the verified badge and the unlimited quota back from the accounts whose subscriptions ended
+func (p PackageUsecase) ExecuteExpirePremiums(ctx context.Context) error {
+	fn := func(tx *sql.Tx) error {
+		expired, err := p.EntitlementEntity.ExpireLapsedPremiumsEntity(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if expired > 0 {
+			log.Printf("Expired the premium of %d accounts", expired)
+		}
 		return nil
 	}
 
 	err := common.WithExecuteTransactionalManager(ctx, p.DB, fn)
 	if err != nil {
-		return errors.New("transactional failed")
+		log.Println("Transaction failed:", err)
 	}
 	return err
 }
+
+// purchasePremium extends a running subscription from its end, then grants what the package includes right away,
+// the verified badge and for packages with unlimited swipes the quota of today
+func (p PackageUsecase) purchasePremium(ctx context.Context, tx *sql.Tx, accountId int64, request domain.PremiumPurchaseRequest) (domain.PackageDto, domain.EntitlementsDto, error) {
+	current, err := p.EntitlementEntity.FindEntitlementsEntity(ctx, tx, accountId)
+	if err != nil {
+		return domain.PackageDto{}, domain.EntitlementsDto{}, err
+	}
+
+	startsAt := time.Now()
+	if current.ExpiresAt != nil {
+		startsAt = *current.ExpiresAt
+	}
+
+	pkg, err := p.PackageEntity.PurchasePremiumPackageEntity(ctx, tx, accountId, request, startsAt)
+	if err != nil {
+		return domain.PackageDto{}, domain.EntitlementsDto{}, err
+	}
+
+	err = p.AccountEntity.UpdateAccountVerified(ctx, tx, accountId)
+	if err != nil {
+		return domain.PackageDto{}, domain.EntitlementsDto{}, errors.New("could not update account verified")
+	}
+
+	if pkg.UnlimitedSwipes {
+		err = p.DailyQuotasEntity.UpdateTotalQuotasInPremiumAccount(ctx, tx, accountId)
+		if err != nil {
+			return domain.PackageDto{}, domain.EntitlementsDto{}, errors.New("could not update total quotas")
+		}
+	}
+
+	entitlements, err := p.EntitlementEntity.FindEntitlementsEntity(ctx, tx, accountId)
+	if err != nil {
+		return domain.PackageDto{}, domain.EntitlementsDto{}, err
+	}
+	return pkg, entitlements, nil
+}
+
+func toPremiumStatusResponse(entitlements domain.EntitlementsDto) domain.PremiumStatusResponse {
+	res := domain.PremiumStatusResponse{Premium: entitlements.Premium, Entitlements: []string{}}
+	if entitlements.UnlimitedSwipes {
+		res.Entitlements = append(res.Entitlements, entitlementsentity.EntitlementUnlimitedSwipes)
+	}
+	if entitlements.VerifiedBadge {
+		res.Entitlements = append(res.Entitlements, entitlementsentity.EntitlementVerifiedBadge)
+	}
+	if entitlements.SwipeUndo {
+		res.Entitlements = append(res.Entitlements, entitlementsentity.EntitlementSwipeUndo)
+	}
+	if entitlements.LikesReceived {
+		res.Entitlements = append(res.Entitlements, entitlementsentity.EntitlementLikesReceived)
+	}
+	if entitlements.Premium {
+		packageId := entitlements.PackageID
+		res.PackageID = &packageId
+	}
+	if entitlements.ExpiresAt != nil {
+		expiresAt := entitlements.ExpiresAt.Format(time.RFC3339)
+		res.ExpiresAt = &expiresAt
+	}
+	return res
+}
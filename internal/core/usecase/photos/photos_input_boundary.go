@@ -0,0 +1,20 @@
+package photos
+
+import (
+	"context"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+)
+
+type InputPhotoBoundary interface {
+	ExecuteUploadPhoto(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, upload domain.PhotoUpload, boundary OutputPhotoBoundary) error
+	ExecuteFetchPhotos(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputPhotoBoundary) error
+	ExecuteReorderPhotos(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.ReorderPhotosRequest, boundary OutputPhotoBoundary) error
+	ExecuteSetPrimaryPhoto(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, photoId int64, boundary OutputPhotoBoundary) error
+	ExecuteDeletePhoto(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, photoId int64, boundary OutputPhotoBoundary) error
+	ExecuteFetchTrashedPhotos(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputPhotoBoundary) error
+	ExecuteRestorePhoto(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, photoId int64, boundary OutputPhotoBoundary) error
+	ExecutePurgeTrashedPhotos(ctx context.Context) error
+	ExecuteSetSmartPhotos(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.SmartPhotosRequest, boundary OutputPhotoBoundary) error
+	ExecuteFetchSmartPhotos(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputPhotoBoundary) error
+}
@@ -0,0 +1,12 @@
+package photos
+
+import "godating-dealls/internal/domain"
+
+type OutputPhotoBoundary interface {
+	PhotoResponse(response domain.PhotoResponse, err error)
+	PhotosResponse(response []domain.PhotoResponse, err error)
+	DeletePhotoResponse(response []domain.PhotoResponse, err error)
+	TrashedPhotosResponse(response []domain.TrashedPhotoResponse, err error)
+	RestorePhotoResponse(response []domain.PhotoResponse, err error)
+	SmartPhotosResponse(response domain.SmartPhotosResponse, err error)
+}
@@ -0,0 +1,319 @@
+package photos
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/photos"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"godating-dealls/internal/infra/storage"
+	"log"
+	"math"
+	"time"
+)
+
+// purgeBatchSize photos are purged per run, a backlog is worked off by the next runs
+const purgeBatchSize = 500
+
+type PhotoUsecase struct {
+	DB          *sql.DB
+	PhotoEntity photos.PhotoEntity
+	Storage     storage.Storage
+}
+
+func NewPhotoUsecase(db *sql.DB, photoEntity photos.PhotoEntity, storage storage.Storage) InputPhotoBoundary {
+	return &PhotoUsecase{DB: db, PhotoEntity: photoEntity, Storage: storage}
+}
+
+// ExecuteUploadPhoto stores the file before the row, a file whose row could not be saved is removed again
+func (p PhotoUsecase) ExecuteUploadPhoto(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, upload domain.PhotoUpload, boundary OutputPhotoBoundary) error {
+	extension, err := p.PhotoEntity.ValidatePhotoUploadEntity(upload)
+	if err != nil {
+		return err
+	}
+	name := make([]byte, 16)
+	if _, err := rand.Read(name); err != nil {
+		return errors.New("failed to name photo")
+	}
+	storageKey := fmt.Sprintf("photos/%d/%s%s", claims.AccountId, hex.EncodeToString(name), extension)
+
+	if err := p.Storage.Put(ctx, storageKey, upload.ContentType, upload.Data); err != nil {
+		log.Println("Photo upload failed:", err)
+		return errors.New("failed to store photo")
+	}
+
+	var photo domain.PhotoDto
+	fn := func(tx *sql.Tx) error {
+		photo, err = p.PhotoEntity.AddPhotoEntity(ctx, tx, claims.AccountId, storageKey, upload)
+		return err
+	}
+
+	err = common.WithExecuteTransactionalManager(ctx, p.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+		p.deleteStoredPhoto(storageKey)
+		return err
+	}
+
+	boundary.PhotoResponse(p.toPhotoResponse(photo), nil)
+	return nil
+}
+
+func (p PhotoUsecase) ExecuteFetchPhotos(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputPhotoBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		photos, err := p.PhotoEntity.FindPhotosEntity(ctx, tx, claims.AccountId)
+		if err != nil {
+			return err
+		}
+		boundary.PhotosResponse(p.toPhotoResponses(photos), nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, p.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (p PhotoUsecase) ExecuteReorderPhotos(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.ReorderPhotosRequest, boundary OutputPhotoBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		photos, err := p.PhotoEntity.ReorderPhotosEntity(ctx, tx, claims.AccountId, request.PhotoIDs)
+		if err != nil {
+			return err
+		}
+		boundary.PhotosResponse(p.toPhotoResponses(photos), nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, p.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (p PhotoUsecase) ExecuteSetPrimaryPhoto(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, photoId int64, boundary OutputPhotoBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		photos, err := p.PhotoEntity.SetPrimaryPhotoEntity(ctx, tx, claims.AccountId, photoId)
+		if err != nil {
+			return err
+		}
+		boundary.PhotosResponse(p.toPhotoResponses(photos), nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, p.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteDeletePhoto moves the photo to the trash, its file stays until the purge so the photo can be restored
+func (p PhotoUsecase) ExecuteDeletePhoto(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, photoId int64, boundary OutputPhotoBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		if _, err := p.PhotoEntity.DeletePhotoEntity(ctx, tx, claims.AccountId, photoId); err != nil {
+			return err
+		}
+		remaining, err := p.PhotoEntity.FindPhotosEntity(ctx, tx, claims.AccountId)
+		if err != nil {
+			return err
+		}
+		boundary.DeletePhotoResponse(p.toPhotoResponses(remaining), nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, p.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (p PhotoUsecase) ExecuteFetchTrashedPhotos(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputPhotoBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		trashed, err := p.PhotoEntity.FindTrashedPhotosEntity(ctx, tx, claims.AccountId, time.Now())
+		if err != nil {
+			return err
+		}
+
+		res := make([]domain.TrashedPhotoResponse, 0, len(trashed))
+		for _, photo := range trashed {
+			res = append(res, domain.TrashedPhotoResponse{
+				PhotoID:   photo.PhotoID,
+				URL:       p.Storage.URL(photo.StorageKey),
+				CreatedAt: common.FormatTimeByParam(photo.CreatedAt),
+				DeletedAt: common.FormatTimeByParam(*photo.DeletedAt),
+				PurgeAt:   common.FormatTimeByParam(photo.DeletedAt.Add(photos.PhotoTrashRetention)),
+			})
+		}
+		boundary.TrashedPhotosResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, p.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (p PhotoUsecase) ExecuteRestorePhoto(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, photoId int64, boundary OutputPhotoBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		restored, err := p.PhotoEntity.RestorePhotoEntity(ctx, tx, claims.AccountId, photoId, time.Now())
+		if err != nil {
+			return err
+		}
+		boundary.RestorePhotoResponse(p.toPhotoResponses(restored), nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, p.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecutePurgeTrashedPhotos deletes the photos past the trash retention, run by the photo trash cleanup job.
+// The stored files go once the rows are gone, a failed removal only leaves an unreferenced file
+func (p PhotoUsecase) ExecutePurgeTrashedPhotos(ctx context.Context) error {
+	var purged []domain.PhotoDto
+	fn := func(tx *sql.Tx) error {
+		expired, err := p.PhotoEntity.FindExpiredTrashedPhotosEntity(ctx, tx, time.Now(), purgeBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, photo := range expired {
+			if err := p.PhotoEntity.PurgePhotoEntity(ctx, tx, photo.PhotoID); err != nil {
+				return err
+			}
+		}
+		purged = expired
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, p.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+		return err
+	}
+
+	for _, photo := range purged {
+		p.deleteStoredPhoto(photo.StorageKey)
+	}
+	log.Printf("Deleted photos purged: %d", len(purged))
+	return nil
+}
+
+// ExecuteSetSmartPhotos turning smart photos off keeps the counts, they are reported again when it is turned back on
+func (p PhotoUsecase) ExecuteSetSmartPhotos(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.SmartPhotosRequest, boundary OutputPhotoBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		if err := p.PhotoEntity.SetSmartPhotosEntity(ctx, tx, claims.AccountId, *request.Enabled); err != nil {
+			return err
+		}
+		res, err := p.smartPhotos(ctx, tx, claims.AccountId)
+		if err != nil {
+			return err
+		}
+		boundary.SmartPhotosResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, p.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (p PhotoUsecase) ExecuteFetchSmartPhotos(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputPhotoBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		res, err := p.smartPhotos(ctx, tx, claims.AccountId)
+		if err != nil {
+			return err
+		}
+		boundary.SmartPhotosResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, p.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (p PhotoUsecase) smartPhotos(ctx context.Context, tx *sql.Tx, accountId int64) (domain.SmartPhotosResponse, error) {
+	report, err := p.PhotoEntity.FindSmartPhotosEntity(ctx, tx, accountId)
+	if err != nil {
+		return domain.SmartPhotosResponse{}, err
+	}
+	uploaded, err := p.PhotoEntity.FindPhotosEntity(ctx, tx, accountId)
+	if err != nil {
+		return domain.SmartPhotosResponse{}, err
+	}
+	storageKeys := map[int64]string{}
+	for _, photo := range uploaded {
+		storageKeys[photo.PhotoID] = photo.StorageKey
+	}
+
+	res := domain.SmartPhotosResponse{
+		Enabled:     report.Enabled,
+		Photos:      make([]domain.PhotoPerformanceResponse, 0, len(report.Photos)),
+		BestPhotoID: report.BestPhotoID,
+	}
+	for _, performance := range report.Photos {
+		item := domain.PhotoPerformanceResponse{
+			PhotoID:     performance.PhotoID,
+			URL:         p.Storage.URL(storageKeys[performance.PhotoID]),
+			Impressions: performance.Impressions,
+			Likes:       performance.Likes,
+		}
+		if performance.Impressions > 0 {
+			item.LikeRate = math.Round(float64(performance.Likes)/float64(performance.Impressions)*1000) / 1000
+		}
+		res.Photos = append(res.Photos, item)
+	}
+
+	switch {
+	case report.BestPhotoID != nil:
+		res.Message = "This photo gets the most likes when it is shown first, consider making it your primary photo"
+	case !report.Enabled:
+		res.Message = "Turn smart photos on to show a different photo first to different people and find your best one"
+	default:
+		res.Message = fmt.Sprintf("Not enough views yet, a photo is compared once %d people saw it first", photos.MinSmartPhotoImpressions)
+	}
+	return res, nil
+}
+
+func (p PhotoUsecase) deleteStoredPhoto(storageKey string) {
+	// Not tied to the request, the client may already be gone
+	if err := p.Storage.Delete(context.Background(), storageKey); err != nil && !errors.Is(err, storage.ErrObjectNotFound) {
+		log.Printf("Failed to delete stored photo %s: %v", storageKey, err)
+	}
+}
+
+func (p PhotoUsecase) toPhotoResponses(photos []domain.PhotoDto) []domain.PhotoResponse {
+	res := make([]domain.PhotoResponse, 0, len(photos))
+	for _, photo := range photos {
+		res = append(res, p.toPhotoResponse(photo))
+	}
+	return res
+}
+
+func (p PhotoUsecase) toPhotoResponse(photo domain.PhotoDto) domain.PhotoResponse {
+	return domain.PhotoResponse{
+		PhotoID:   photo.PhotoID,
+		URL:       p.Storage.URL(photo.StorageKey),
+		Position:  photo.Position,
+		Primary:   photo.Primary,
+		CreatedAt: common.FormatTimeByParam(photo.CreatedAt),
+	}
+}
@@ -0,0 +1,14 @@
+package profile_changes
+
+import (
+	"context"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+)
+
+type InputProfileChangeBoundary interface {
+	ExecuteSubmitProfileChange(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.ProfileChangeRequest, boundary OutputProfileChangeBoundary) error
+	ExecuteFetchProfileChanges(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputProfileChangeBoundary) error
+	ExecuteFetchPendingProfileChanges(ctx context.Context, boundary OutputProfileChangeBoundary) error
+	ExecuteReviewProfileChange(ctx context.Context, requestId int64, request domain.ProfileChangeReviewRequest, boundary OutputProfileChangeBoundary) error
+}
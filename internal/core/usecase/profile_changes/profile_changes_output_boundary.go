@@ -0,0 +1,9 @@
+package profile_changes
+
+import "godating-dealls/internal/domain"
+
+type OutputProfileChangeBoundary interface {
+	ProfileChangeResponse(response domain.ProfileChangeResponse, err error)
+	ProfileChangesResponse(response []domain.ProfileChangeResponse, err error)
+	ReviewProfileChangeResponse(response domain.ProfileChangeResponse, err error)
+}
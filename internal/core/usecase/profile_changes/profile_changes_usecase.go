@@ -0,0 +1,246 @@
+package profile_changes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/accounts"
+	"godating-dealls/internal/core/entities/admin"
+	profilechanges "godating-dealls/internal/core/entities/profile_changes"
+	"godating-dealls/internal/core/entities/users"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"log"
+	"strings"
+)
+
+const (
+	decisionApprove = "approve"
+	decisionReject  = "reject"
+)
+
+type ProfileChangeUsecase struct {
+	DB                  *sql.DB
+	ProfileChangeEntity profilechanges.ProfileChangeEntity
+	UserEntity          users.UserEntity
+	AccountEntity       accounts.AccountEntity
+	AdminEntity         admin.AdminEntity
+}
+
+func NewProfileChangeUsecase(
+	db *sql.DB,
+	profileChangeEntity profilechanges.ProfileChangeEntity,
+	userEntity users.UserEntity,
+	accountEntity accounts.AccountEntity,
+	adminEntity admin.AdminEntity) InputProfileChangeBoundary {
+	return &ProfileChangeUsecase{
+		DB:                  db,
+		ProfileChangeEntity: profileChangeEntity,
+		UserEntity:          userEntity,
+		AccountEntity:       accountEntity,
+		AdminEntity:         adminEntity,
+	}
+}
+
+// ExecuteSubmitProfileChange records the current and the requested value of a locked field for a moderator to decide
+func (p ProfileChangeUsecase) ExecuteSubmitProfileChange(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.ProfileChangeRequest, boundary OutputProfileChangeBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		// Fields of an account that is not verified are not locked, they are edited directly
+		verified, err := p.AccountEntity.FindAccountVerifiedEntities(ctx, tx, claims.AccountId)
+		if err != nil {
+			return err
+		}
+		if !verified {
+			return fmt.Errorf("%w: account is not verified, edit the profile directly", profilechanges.ErrInvalidProfileChange)
+		}
+
+		current, err := p.UserEntity.FindUserDetailEntity(ctx, tx, claims.AccountId)
+		if err != nil {
+			return errors.New("failed to find user")
+		}
+
+		change, err := p.ProfileChangeEntity.SubmitProfileChangeEntity(ctx, tx, domain.ProfileChangeDto{
+			AccountID:      claims.AccountId,
+			Field:          request.Field,
+			CurrentValue:   profilechanges.LockedFieldValues(current)[request.Field],
+			RequestedValue: strings.TrimSpace(request.RequestedValue),
+			Evidence:       strings.TrimSpace(request.Evidence),
+			Reason:         strings.TrimSpace(request.Reason),
+		})
+		if err != nil {
+			return err
+		}
+
+		boundary.ProfileChangeResponse(toProfileChangeResponse(change), nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, p.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (p ProfileChangeUsecase) ExecuteFetchProfileChanges(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputProfileChangeBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		changes, err := p.ProfileChangeEntity.FindProfileChangesEntity(ctx, tx, claims.AccountId)
+		if err != nil {
+			return err
+		}
+
+		boundary.ProfileChangesResponse(toProfileChangeResponses(changes), nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, p.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteFetchPendingProfileChanges is the moderation queue, admin requests are authenticated by the admin middleware
+func (p ProfileChangeUsecase) ExecuteFetchPendingProfileChanges(ctx context.Context, boundary OutputProfileChangeBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		changes, err := p.ProfileChangeEntity.FindPendingProfileChangesEntity(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		boundary.ProfileChangesResponse(toProfileChangeResponses(changes), nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, p.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteReviewProfileChange approves or rejects a request, an approval updates the profile,
+// and either decision is written to the admin audit log in the same transaction
+func (p ProfileChangeUsecase) ExecuteReviewProfileChange(ctx context.Context, requestId int64, request domain.ProfileChangeReviewRequest, boundary OutputProfileChangeBoundary) error {
+	if request.Decision != decisionApprove && request.Decision != decisionReject {
+		return fmt.Errorf("%w: decision must be approve or reject", profilechanges.ErrInvalidProfileChange)
+	}
+	if strings.TrimSpace(request.Actor) == "" || len(strings.TrimSpace(request.Justification)) < 10 {
+		return fmt.Errorf("%w: actor and a justification of at least 10 characters are required", profilechanges.ErrInvalidProfileChange)
+	}
+
+	fn := func(tx *sql.Tx) error {
+		change, err := p.ProfileChangeEntity.StartProfileChangeReviewEntity(ctx, tx, requestId)
+		if err != nil {
+			return err
+		}
+
+		audit := domain.AdminAuditLogDto{
+			Actor:           request.Actor,
+			Action:          "profile_change_rejected",
+			TargetAccountID: change.AccountID,
+			Justification:   request.Justification,
+		}
+		change.Status = domain.ProfileChangeRejected
+
+		if request.Decision == decisionApprove {
+			current, err := p.UserEntity.FindUserDetailEntity(ctx, tx, change.AccountID)
+			if err != nil {
+				return errors.New("failed to find user")
+			}
+
+			// The before value is read again, the profile may have been rectified since the request was made
+			before := profilechanges.LockedFieldValues(current)[change.Field]
+			if err := p.applyProfileChange(ctx, tx, current, change); err != nil {
+				return err
+			}
+
+			audit.Action = "profile_change_approved"
+			audit.Changes = map[string]domain.FieldChange{change.Field: {Before: before, After: change.RequestedValue}}
+			change.Status = domain.ProfileChangeApproved
+		}
+
+		audit, err = p.AdminEntity.RecordAuditLogEntity(ctx, tx, audit)
+		if err != nil {
+			return err
+		}
+
+		change.Reviewer = &request.Actor
+		change.ReviewNote = &request.Justification
+		change.AuditID = &audit.AuditID
+		change, err = p.ProfileChangeEntity.CloseProfileChangeReviewEntity(ctx, tx, change)
+		if err != nil {
+			return err
+		}
+
+		boundary.ReviewProfileChangeResponse(toProfileChangeResponse(change), nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, p.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// applyProfileChange starts from the stored profile so only the requested field changes
+func (p ProfileChangeUsecase) applyProfileChange(ctx context.Context, tx *sql.Tx, current domain.Users, change domain.ProfileChangeDto) error {
+	values := profilechanges.LockedFieldValues(current)
+	fullName, dateOfBirth, gender := values["full_name"], values["date_of_birth"], values["gender"]
+	patch := domain.PatchUser{
+		UserID:      current.UserID,
+		FullName:    &fullName,
+		Gender:      &gender,
+		Address:     &current.Address,
+		Bio:         &current.Bio,
+		DateOfBirth: &dateOfBirth,
+	}
+
+	requested := change.RequestedValue
+	switch change.Field {
+	case "full_name":
+		patch.FullName = &requested
+	case "date_of_birth":
+		patch.DateOfBirth = &requested
+	case "gender":
+		patch.Gender = &requested
+	}
+
+	_, err := p.UserEntity.UpdateUserEntities(ctx, tx, patch)
+	return err
+}
+
+func toProfileChangeResponses(changes []domain.ProfileChangeDto) []domain.ProfileChangeResponse {
+	res := make([]domain.ProfileChangeResponse, 0, len(changes))
+	for _, change := range changes {
+		res = append(res, toProfileChangeResponse(change))
+	}
+	return res
+}
+
+func toProfileChangeResponse(change domain.ProfileChangeDto) domain.ProfileChangeResponse {
+	res := domain.ProfileChangeResponse{
+		RequestID:      change.RequestID,
+		AccountID:      change.AccountID,
+		Field:          change.Field,
+		CurrentValue:   change.CurrentValue,
+		RequestedValue: change.RequestedValue,
+		Evidence:       change.Evidence,
+		Reason:         change.Reason,
+		Status:         change.Status,
+		CreatedAt:      common.FormatTimeByParam(change.CreatedAt),
+	}
+	if change.ReviewNote != nil {
+		res.ReviewNote = *change.ReviewNote
+	}
+	if change.AuditID != nil {
+		res.AuditID = *change.AuditID
+	}
+	if change.ReviewedAt != nil {
+		res.ReviewedAt = common.FormatTimeByParam(*change.ReviewedAt)
+	}
+	return res
+}
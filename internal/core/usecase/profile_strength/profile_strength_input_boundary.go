@@ -0,0 +1,10 @@
+package profile_strength
+
+import (
+	"context"
+	"godating-dealls/internal/infra/jsonwebtoken"
+)
+
+type InputProfileStrengthBoundary interface {
+	ExecuteProfileStrength(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputProfileStrengthBoundary) error
+}
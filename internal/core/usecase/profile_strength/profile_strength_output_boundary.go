@@ -0,0 +1,7 @@
+package profile_strength
+
+import "godating-dealls/internal/domain"
+
+type OutputProfileStrengthBoundary interface {
+	ProfileStrengthResponse(response domain.ProfileStrengthResponse, err error)
+}
@@ -0,0 +1,77 @@
+package profile_strength
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/integrations"
+	"godating-dealls/internal/core/entities/photos"
+	"godating-dealls/internal/core/entities/profile_strength"
+	"godating-dealls/internal/core/entities/users"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"log"
+)
+
+type ProfileStrengthUsecase struct {
+	DB                    *sql.DB
+	ProfileStrengthEntity profile_strength.ProfileStrengthEntity
+	UserEntity            users.UserEntity
+	IntegrationEntity     integrations.IntegrationEntity
+	PhotoEntity           photos.PhotoEntity
+}
+
+func NewProfileStrengthUsecase(
+	db *sql.DB,
+	profileStrengthEntity profile_strength.ProfileStrengthEntity,
+	userEntity users.UserEntity,
+	integrationEntity integrations.IntegrationEntity,
+	photoEntity photos.PhotoEntity) InputProfileStrengthBoundary {
+	return &ProfileStrengthUsecase{
+		DB:                    db,
+		ProfileStrengthEntity: profileStrengthEntity,
+		UserEntity:            userEntity,
+		IntegrationEntity:     integrationEntity,
+		PhotoEntity:           photoEntity,
+	}
+}
+
+func (p ProfileStrengthUsecase) ExecuteProfileStrength(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputProfileStrengthBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		user, err := p.UserEntity.FindUserDetailEntity(ctx, tx, claims.AccountId)
+		if err != nil {
+			return errors.New("failed to find user")
+		}
+
+		imports, err := p.IntegrationEntity.FindImportedContentEntity(ctx, tx, claims.AccountId)
+		if err != nil {
+			return err
+		}
+
+		uploaded, err := p.PhotoEntity.FindPhotosEntity(ctx, tx, claims.AccountId)
+		if err != nil {
+			return err
+		}
+
+		strength := p.ProfileStrengthEntity.EvaluateProfileEntity(user, imports, len(uploaded))
+
+		res := domain.ProfileStrengthResponse{Score: strength.Score, Suggestions: []domain.ProfileSuggestionResponse{}}
+		for i, suggestion := range strength.Suggestions {
+			res.Suggestions = append(res.Suggestions, domain.ProfileSuggestionResponse{
+				Key:      suggestion.Key,
+				Message:  suggestion.Message,
+				Priority: i + 1,
+				Impact:   suggestion.Impact,
+			})
+		}
+		boundary.ProfileStrengthResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, p.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
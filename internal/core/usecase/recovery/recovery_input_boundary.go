@@ -0,0 +1,17 @@
+package recovery
+
+import (
+	"context"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+)
+
+type InputRecoveryBoundary interface {
+	ExecuteSaveRecoveryContacts(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.RecoveryContactsRequest, boundary OutputRecoveryBoundary) error
+	ExecuteFetchRecoverySettings(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputRecoveryBoundary) error
+	ExecuteCancelRecovery(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputRecoveryBoundary) error
+	ExecuteStartRecovery(ctx context.Context, request domain.StartRecoveryRequest, boundary OutputRecoveryBoundary) error
+	ExecuteFetchRecoveryApprovals(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputRecoveryBoundary) error
+	ExecuteApproveRecovery(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, requestId int64, boundary OutputRecoveryBoundary) error
+	ExecuteCompleteRecovery(ctx context.Context, request domain.CompleteRecoveryRequest, boundary OutputRecoveryBoundary) error
+}
@@ -0,0 +1,11 @@
+package recovery
+
+import "godating-dealls/internal/domain"
+
+type OutputRecoveryBoundary interface {
+	RecoverySettingsResponse(response domain.RecoverySettingsResponse, err error)
+	StartRecoveryResponse(response domain.StartRecoveryResponse, err error)
+	RecoveryApprovalsResponse(response []domain.RecoveryRequestResponse, err error)
+	RecoveryApprovalResponse(response domain.RecoveryApprovalResponse, err error)
+	RecoveryMessageResponse(response domain.RecoveryMessageResponse, err error)
+}
@@ -0,0 +1,15 @@
+package recovery
+
+import "time"
+
+const (
+	DefaultWaitingHours = 24
+	DefaultExpiresHours = 72
+)
+
+// Policy is how long a recovery waits before it can complete and how long it stays open in total, contacts need some
+// time to approve after the waiting period
+type Policy struct {
+	WaitingPeriod time.Duration `validate:"gt=0"`
+	ExpiresAfter  time.Duration `validate:"gtfield=WaitingPeriod"`
+}
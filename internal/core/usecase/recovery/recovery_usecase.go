@@ -0,0 +1,297 @@
+package recovery
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/accounts"
+	"godating-dealls/internal/core/entities/recovery"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"godating-dealls/internal/infra/redisclient"
+	"log"
+	"strconv"
+	"time"
+)
+
+const startRecoveryMessage = "If the account has trusted contacts a recovery was started, ask them to approve it and give you their codes"
+
+type RecoveryUsecase struct {
+	DB             *sql.DB
+	RecoveryEntity recovery.RecoveryEntity
+	AccountEntity  accounts.AccountEntity
+	Rds            redisclient.RedisInterface
+	Policy         Policy
+}
+
+func NewRecoveryUsecase(
+	db *sql.DB,
+	recoveryEntity recovery.RecoveryEntity,
+	accountEntity accounts.AccountEntity,
+	rds redisclient.RedisInterface,
+	policy Policy) InputRecoveryBoundary {
+	return &RecoveryUsecase{
+		DB:             db,
+		RecoveryEntity: recoveryEntity,
+		AccountEntity:  accountEntity,
+		Rds:            rds,
+		Policy:         policy,
+	}
+}
+
+func (r RecoveryUsecase) ExecuteSaveRecoveryContacts(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.RecoveryContactsRequest, boundary OutputRecoveryBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		var contactAccountIds []int64
+		for _, username := range request.Usernames {
+			contact, err := r.AccountEntity.AuthenticateAccount(ctx, tx, domain.AccountDto{Username: &username})
+			if err != nil || contact.AccountId == 0 {
+				return fmt.Errorf("trusted contact %s not found", username)
+			}
+			contactAccountIds = append(contactAccountIds, contact.AccountId)
+		}
+
+		err := r.RecoveryEntity.SaveRecoveryContactsEntity(ctx, tx, domain.RecoveryContactsDto{
+			AccountID:         claims.AccountId,
+			ContactAccountIDs: contactAccountIds,
+			Threshold:         request.Threshold,
+		})
+		if err != nil {
+			return err
+		}
+
+		res, err := r.recoverySettings(ctx, tx, claims.AccountId)
+		if err != nil {
+			return err
+		}
+		boundary.RecoverySettingsResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, r.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (r RecoveryUsecase) ExecuteFetchRecoverySettings(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputRecoveryBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		res, err := r.recoverySettings(ctx, tx, claims.AccountId)
+		if err != nil {
+			return err
+		}
+		boundary.RecoverySettingsResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, r.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteCancelRecovery lets the owner stop every open recovery of their account, e.g. one they did not start
+func (r RecoveryUsecase) ExecuteCancelRecovery(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputRecoveryBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		err := r.RecoveryEntity.CancelRecoveryEntity(ctx, tx, claims.AccountId)
+		if err != nil {
+			return err
+		}
+		boundary.RecoveryMessageResponse(domain.RecoveryMessageResponse{Message: "Recovery requests cancelled"}, nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, r.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteStartRecovery answers the same way whether or not the account exists or has contacts,
+// only the real requester's token can ever complete
+func (r RecoveryUsecase) ExecuteStartRecovery(ctx context.Context, request domain.StartRecoveryRequest, boundary OutputRecoveryBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		now := time.Now().Truncate(time.Second)
+		res := domain.StartRecoveryResponse{
+			AvailableAt: common.FormatTimeByParam(now.Add(r.Policy.WaitingPeriod)),
+			ExpiresAt:   common.FormatTimeByParam(now.Add(r.Policy.ExpiresAfter)),
+			Message:     startRecoveryMessage,
+		}
+
+		account, err := r.AccountEntity.AuthenticateAccount(ctx, tx, domain.AccountDto{Email: &request.Email, Username: &request.Username})
+		if err == nil && account.AccountId != 0 {
+			var recoveryToken string
+			_, recoveryToken, err = r.RecoveryEntity.StartRecoveryEntity(ctx, tx, account.AccountId, r.Policy.WaitingPeriod, r.Policy.ExpiresAfter)
+			res.RecoveryToken = recoveryToken
+		}
+		if err != nil {
+			log.Printf("Recovery not started: %v", err)
+		}
+		if res.RecoveryToken == "" {
+			res.RecoveryToken = decoyRecoveryToken()
+		}
+
+		boundary.StartRecoveryResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, r.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteFetchRecoveryApprovals lists the open requests of accounts that chose the caller as a trusted contact
+func (r RecoveryUsecase) ExecuteFetchRecoveryApprovals(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputRecoveryBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		requests, err := r.RecoveryEntity.FindRecoveryRequestsToApproveEntity(ctx, tx, claims.AccountId)
+		if err != nil {
+			return err
+		}
+
+		res := make([]domain.RecoveryRequestResponse, 0, len(requests))
+		for _, request := range requests {
+			account, err := r.AccountEntity.FindAccountDetails(ctx, tx, request.AccountID)
+			if err != nil {
+				return err
+			}
+			item := toRecoveryRequestResponse(request)
+			item.Username = account.Username
+			for _, approver := range request.ApproverAccountIDs {
+				if approver == claims.AccountId {
+					item.Approved = true
+				}
+			}
+			res = append(res, item)
+		}
+		boundary.RecoveryApprovalsResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, r.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (r RecoveryUsecase) ExecuteApproveRecovery(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, requestId int64, boundary OutputRecoveryBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		code, err := r.RecoveryEntity.ApproveRecoveryEntity(ctx, tx, claims.AccountId, requestId)
+		if err != nil {
+			return err
+		}
+
+		boundary.RecoveryApprovalResponse(domain.RecoveryApprovalResponse{
+			RequestID: requestId,
+			Code:      code,
+			Message:   "Only give this code to your contact after you confirmed it is really them asking, for example in a call",
+		}, nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, r.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteCompleteRecovery sets the new password and ends the current session of the account
+func (r RecoveryUsecase) ExecuteCompleteRecovery(ctx context.Context, request domain.CompleteRecoveryRequest, boundary OutputRecoveryBoundary) error {
+	var rejected error
+	fn := func(tx *sql.Tx) error {
+		accountId, err := r.RecoveryEntity.CompleteRecoveryEntity(ctx, tx, request.RecoveryToken, request.Codes)
+		if errors.Is(err, recovery.ErrRecoveryRejected) {
+			// Commit so the failed attempt is counted
+			rejected = err
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		err = r.AccountEntity.UpdateAccountPassword(ctx, tx, accountId, request.NewPassword)
+		if err != nil {
+			return err
+		}
+
+		account, err := r.AccountEntity.FindAccountDetails(ctx, tx, accountId)
+		if err != nil {
+			return err
+		}
+		redisKey := redisclient.AccessTokenKey.Key(common.StringEncoder(fmt.Sprintf("%d:%s", account.AccountId, account.Email)))
+		if err := r.Rds.ClearFromRedis(ctx, redisKey); err != nil {
+			log.Printf("Failed to clear access token after recovery: %v", err)
+		}
+		// Refresh tokens of sessions started before the recovery stop working as well
+		revokedKey := redisclient.RefreshRevokedKey.Key(strconv.FormatInt(account.AccountId, 10))
+		if err := r.Rds.StoreToRedis(ctx, revokedKey, time.Now().UnixMilli()); err != nil {
+			log.Printf("Failed to revoke refresh tokens after recovery: %v", err)
+		}
+
+		boundary.RecoveryMessageResponse(domain.RecoveryMessageResponse{Message: "Account recovered, login with your new password"}, nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, r.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+		return err
+	}
+	return rejected
+}
+
+func (r RecoveryUsecase) recoverySettings(ctx context.Context, tx *sql.Tx, accountId int64) (domain.RecoverySettingsResponse, error) {
+	settings, err := r.RecoveryEntity.FindRecoveryContactsEntity(ctx, tx, accountId)
+	if err != nil {
+		return domain.RecoverySettingsResponse{}, err
+	}
+
+	res := domain.RecoverySettingsResponse{
+		Contacts:        []domain.RecoveryContactResponse{},
+		Threshold:       settings.Threshold,
+		PendingRequests: []domain.RecoveryRequestResponse{},
+	}
+	for _, contactAccountId := range settings.ContactAccountIDs {
+		contact, err := r.AccountEntity.FindAccountDetails(ctx, tx, contactAccountId)
+		if err != nil {
+			return domain.RecoverySettingsResponse{}, err
+		}
+		res.Contacts = append(res.Contacts, domain.RecoveryContactResponse{AccountID: contact.AccountId, Username: contact.Username})
+	}
+
+	pending, err := r.RecoveryEntity.FindPendingRecoveryRequestsEntity(ctx, tx, accountId)
+	if err != nil {
+		return domain.RecoverySettingsResponse{}, err
+	}
+	for _, request := range pending {
+		res.PendingRequests = append(res.PendingRequests, toRecoveryRequestResponse(request))
+	}
+	return res, nil
+}
+
+func toRecoveryRequestResponse(request domain.RecoveryRequestDto) domain.RecoveryRequestResponse {
+	return domain.RecoveryRequestResponse{
+		RequestID:         request.RequestID,
+		ApprovalsRequired: request.ApprovalsRequired,
+		Approvals:         len(request.ApproverAccountIDs),
+		CreatedAt:         common.FormatTimeByParam(request.CreatedAt),
+		AvailableAt:       common.FormatTimeByParam(request.AvailableAt),
+		ExpiresAt:         common.FormatTimeByParam(request.ExpiresAt),
+	}
+}
+
+// decoyRecoveryToken has the shape of a real token but matches no request
+func decoyRecoveryToken() string {
+	buf := make([]byte, 32)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
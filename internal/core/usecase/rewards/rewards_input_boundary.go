@@ -0,0 +1,11 @@
+package rewards
+
+import (
+	"context"
+	"godating-dealls/internal/infra/jsonwebtoken"
+)
+
+type InputRewardBoundary interface {
+	ExecuteFetchLoginStreak(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputRewardBoundary) error
+	ExecuteClaimReward(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputRewardBoundary) error
+}
@@ -0,0 +1,8 @@
+package rewards
+
+import "godating-dealls/internal/domain"
+
+type OutputRewardBoundary interface {
+	LoginStreakResponse(response domain.LoginStreakResponse, err error)
+	ClaimRewardResponse(response domain.ClaimRewardResponse, err error)
+}
@@ -0,0 +1,101 @@
+package rewards
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/accounts"
+	"godating-dealls/internal/core/entities/daily_quotas"
+	"godating-dealls/internal/core/entities/rewards"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"log"
+)
+
+type RewardUsecase struct {
+	DB                *sql.DB
+	RewardEntity      rewards.RewardEntity
+	DailyQuotasEntity daily_quotas.DailyQuotasEntity
+	AccountEntity     accounts.AccountEntity
+}
+
+func NewRewardUsecase(
+	db *sql.DB,
+	rewardEntity rewards.RewardEntity,
+	dailyQuotasEntity daily_quotas.DailyQuotasEntity,
+	accountEntity accounts.AccountEntity) InputRewardBoundary {
+	return &RewardUsecase{
+		DB:                db,
+		RewardEntity:      rewardEntity,
+		DailyQuotasEntity: dailyQuotasEntity,
+		AccountEntity:     accountEntity,
+	}
+}
+
+func (r RewardUsecase) ExecuteFetchLoginStreak(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputRewardBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		streak, err := r.RewardEntity.FindLoginStreakEntity(ctx, tx, claims.AccountId)
+		if err != nil {
+			return err
+		}
+
+		res := domain.LoginStreakResponse{
+			CurrentStreak: streak.CurrentStreak,
+			LongestStreak: streak.LongestStreak,
+			Timezone:      streak.Timezone,
+			Today:         streak.Today,
+			ClaimedToday:  streak.ClaimedToday,
+		}
+		if streak.LastLoginDay == streak.Today && !streak.ClaimedToday {
+			res.NextExtraLikes = r.RewardEntity.ExtraLikesForStreak(streak.CurrentStreak)
+		}
+		boundary.LoginStreakResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, r.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (r RewardUsecase) ExecuteClaimReward(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputRewardBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		reward, err := r.RewardEntity.ClaimRewardEntity(ctx, tx, claims.AccountId)
+		if err != nil {
+			return err
+		}
+
+		// Verified accounts already swipe without limit, the claim still keeps the streak honest
+		verified, err := r.AccountEntity.FindAccountVerifiedEntities(ctx, tx, claims.AccountId)
+		if err != nil {
+			return errors.New("invalid fetch account")
+		}
+
+		message := "Extra likes added to today's quota"
+		if verified {
+			reward.ExtraLikes = 0
+			message = "Your swipes are already unlimited"
+		} else {
+			err = r.DailyQuotasEntity.AddBonusQuotaEntity(ctx, tx, claims.AccountId, reward.ExtraLikes)
+			if err != nil {
+				return err
+			}
+		}
+
+		boundary.ClaimRewardResponse(domain.ClaimRewardResponse{
+			CurrentStreak: reward.CurrentStreak,
+			ExtraLikes:    reward.ExtraLikes,
+			Message:       message,
+		}, nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, r.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
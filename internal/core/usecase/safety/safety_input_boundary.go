@@ -0,0 +1,12 @@
+package safety
+
+import (
+	"context"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+)
+
+type InputSafetyBoundary interface {
+	ExecuteFetchSafetySettings(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputSafetyBoundary) error
+	ExecuteUpdateSafetySettings(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.SafetySettingsRequest, boundary OutputSafetyBoundary) error
+}
@@ -0,0 +1,7 @@
+package safety
+
+import "godating-dealls/internal/domain"
+
+type OutputSafetyBoundary interface {
+	SafetySettingsResponse(response domain.SafetySettingsResponse, err error)
+}
@@ -0,0 +1,88 @@
+package safety
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/safety"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"log"
+)
+
+// ErrInvalidSafetySettings the request left a setting out, every setting is sent on an update
+var ErrInvalidSafetySettings = errors.New("late_night_mode is required")
+
+type SafetyUsecase struct {
+	DB           *sql.DB
+	SafetyEntity safety.SafetyEntity
+}
+
+func NewSafetyUsecase(db *sql.DB, safetyEntity safety.SafetyEntity) InputSafetyBoundary {
+	return &SafetyUsecase{DB: db, SafetyEntity: safetyEntity}
+}
+
+func (s SafetyUsecase) ExecuteFetchSafetySettings(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputSafetyBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		res, err := s.safetySettings(ctx, tx, claims.AccountId)
+		if err != nil {
+			return err
+		}
+		boundary.SafetySettingsResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, s.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteUpdateSafetySettings applies to the conversations of the matches younger than the first conversation
+// window as well, not only to the matches made afterwards
+func (s SafetyUsecase) ExecuteUpdateSafetySettings(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.SafetySettingsRequest, boundary OutputSafetyBoundary) error {
+	if request.LateNightMode == nil {
+		return ErrInvalidSafetySettings
+	}
+
+	fn := func(tx *sql.Tx) error {
+		err := s.SafetyEntity.SaveSafetySettingsEntity(ctx, tx, domain.SafetySettingsDto{
+			AccountID:     claims.AccountId,
+			LateNightMode: *request.LateNightMode,
+		})
+		if err != nil {
+			return err
+		}
+
+		res, err := s.safetySettings(ctx, tx, claims.AccountId)
+		if err != nil {
+			return err
+		}
+		boundary.SafetySettingsResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, s.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (s SafetyUsecase) safetySettings(ctx context.Context, tx *sql.Tx, accountId int64) (domain.SafetySettingsResponse, error) {
+	settings, err := s.SafetyEntity.FindSafetySettingsEntity(ctx, tx, accountId)
+	if err != nil {
+		return domain.SafetySettingsResponse{}, err
+	}
+
+	res := domain.SafetySettingsResponse{
+		LateNightMode:          settings.LateNightMode,
+		FirstConversationHours: int(s.SafetyEntity.FirstConversationWindow().Hours()),
+	}
+	if !settings.UpdatedAt.IsZero() {
+		res.UpdatedAt = common.FormatTimeByParam(settings.UpdatedAt)
+	}
+	return res, nil
+}
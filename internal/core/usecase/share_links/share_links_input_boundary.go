@@ -0,0 +1,14 @@
+package share_links
+
+import (
+	"context"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+)
+
+type InputShareLinkBoundary interface {
+	ExecuteCreateShareLink(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.ShareLinkRequest, boundary OutputShareLinkBoundary) error
+	ExecuteFetchShareLinks(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputShareLinkBoundary) error
+	ExecuteRevokeShareLink(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, linkId int64, boundary OutputShareLinkBoundary) error
+	ExecuteViewPublicProfile(ctx context.Context, shareToken string, boundary OutputShareLinkBoundary) error
+}
@@ -0,0 +1,10 @@
+package share_links
+
+import "godating-dealls/internal/domain"
+
+type OutputShareLinkBoundary interface {
+	ShareLinkResponse(response domain.ShareLinkResponse, err error)
+	ShareLinksResponse(response []domain.ShareLinkResponse, err error)
+	RevokeShareLinkResponse(response domain.RevokeShareLinkResponse, err error)
+	PublicProfileResponse(response domain.PublicProfileResponse, err error)
+}
@@ -0,0 +1,189 @@
+package share_links
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/integrations"
+	"godating-dealls/internal/core/entities/photos"
+	"godating-dealls/internal/core/entities/share_links"
+	"godating-dealls/internal/core/entities/users"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"godating-dealls/internal/infra/storage"
+	"log"
+	"time"
+)
+
+// DefaultPublicProfileBaseURL the share token is appended to it, config.Load reads PUBLIC_PROFILE_BASE_URL
+const DefaultPublicProfileBaseURL = "http://localhost:8000/godating-dealls/api/v1/public/profiles/"
+
+type ShareLinkUsecase struct {
+	DB                *sql.DB
+	ShareLinkEntity   share_links.ShareLinkEntity
+	UserEntity        users.UserEntity
+	IntegrationEntity integrations.IntegrationEntity
+	PhotoEntity       photos.PhotoEntity
+	Storage           storage.Storage
+	BaseURL           string
+}
+
+func NewShareLinkUsecase(
+	db *sql.DB,
+	shareLinkEntity share_links.ShareLinkEntity,
+	userEntity users.UserEntity,
+	integrationEntity integrations.IntegrationEntity,
+	photoEntity photos.PhotoEntity,
+	storage storage.Storage,
+	baseURL string) InputShareLinkBoundary {
+	return &ShareLinkUsecase{
+		DB:                db,
+		ShareLinkEntity:   shareLinkEntity,
+		UserEntity:        userEntity,
+		IntegrationEntity: integrationEntity,
+		PhotoEntity:       photoEntity,
+		Storage:           storage,
+		BaseURL:           baseURL,
+	}
+}
+
+func (s ShareLinkUsecase) ExecuteCreateShareLink(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.ShareLinkRequest, boundary OutputShareLinkBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		link, err := s.ShareLinkEntity.CreateShareLinkEntity(ctx, tx, domain.ShareLinkDto{
+			AccountID:      claims.AccountId,
+			ExpiresInHours: request.ExpiresInHours,
+		})
+		if err != nil {
+			return err
+		}
+
+		res, err := s.toShareLinkResponse(link)
+		if err != nil {
+			return err
+		}
+		boundary.ShareLinkResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, s.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (s ShareLinkUsecase) ExecuteFetchShareLinks(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputShareLinkBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		links, err := s.ShareLinkEntity.FindShareLinksEntity(ctx, tx, claims.AccountId)
+		if err != nil {
+			return err
+		}
+
+		var res []domain.ShareLinkResponse
+		for _, link := range links {
+			item, err := s.toShareLinkResponse(link)
+			if err != nil {
+				return err
+			}
+			res = append(res, item)
+		}
+		boundary.ShareLinksResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, s.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (s ShareLinkUsecase) ExecuteRevokeShareLink(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, linkId int64, boundary OutputShareLinkBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		err := s.ShareLinkEntity.RevokeShareLinkEntity(ctx, tx, claims.AccountId, linkId)
+		if err != nil {
+			return err
+		}
+
+		boundary.RevokeShareLinkResponse(domain.RevokeShareLinkResponse{
+			LinkID:  linkId,
+			Message: "Share link revoked",
+		}, nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, s.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteViewPublicProfile anonymous access, only name, age and photos are ever exposed
+func (s ShareLinkUsecase) ExecuteViewPublicProfile(ctx context.Context, shareToken string, boundary OutputShareLinkBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		claims, err := jsonwebtoken.VerifyShareLinkToken(shareToken)
+		if err != nil {
+			return errors.New("invalid share link")
+		}
+
+		link, err := s.ShareLinkEntity.ResolveShareLinkEntity(ctx, tx, claims.LinkId)
+		if err != nil {
+			return err
+		}
+
+		user, err := s.UserEntity.FindUserDetailEntity(ctx, tx, link.AccountID)
+		if err != nil {
+			return errors.New("profile not found")
+		}
+
+		contents, err := s.IntegrationEntity.FindImportedContentEntity(ctx, tx, link.AccountID)
+		if err != nil {
+			return err
+		}
+
+		uploaded, err := s.PhotoEntity.FindPhotosEntity(ctx, tx, link.AccountID)
+		if err != nil {
+			return err
+		}
+
+		res := domain.PublicProfileResponse{Age: user.Age, Photos: []string{}}
+		if user.FullName != nil {
+			res.FullName = *user.FullName
+		}
+		// Uploaded photos come before the ones imported from integrations
+		for _, photo := range uploaded {
+			res.Photos = append(res.Photos, s.Storage.URL(photo.StorageKey))
+		}
+		for _, content := range contents {
+			if content.ContentType == "photo" && content.URL != "" {
+				res.Photos = append(res.Photos, content.URL)
+			}
+		}
+		boundary.PublicProfileResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, s.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (s ShareLinkUsecase) toShareLinkResponse(link domain.ShareLinkDto) (domain.ShareLinkResponse, error) {
+	shareToken, err := jsonwebtoken.GenerateShareLinkToken(link.LinkID, link.ExpiresAt)
+	if err != nil {
+		return domain.ShareLinkResponse{}, errors.New("failed to sign share link")
+	}
+
+	return domain.ShareLinkResponse{
+		LinkID:    link.LinkID,
+		URL:       s.BaseURL + shareToken,
+		ExpiresAt: common.FormatTimeByParam(link.ExpiresAt),
+		ViewCount: link.ViewCount,
+		Active:    link.RevokedAt == nil && link.ExpiresAt.After(time.Now()),
+		CreatedAt: common.FormatTimeByParam(link.CreatedAt),
+	}, nil
+}
@@ -0,0 +1,40 @@
+package status_messages
+
+import (
+	"godating-dealls/internal/domain"
+	"sync"
+	"time"
+)
+
+// statusCacheTTL bounds how late a message written on another instance shows up, the apps cache the answer as long
+const statusCacheTTL = 30 * time.Second
+
+// statusMessageCache keeps the live and scheduled messages in memory so polling apps do not reach the database,
+// a message is filtered by its schedule on every read and appears on time even from the cache
+type statusMessageCache struct {
+	mu       sync.Mutex
+	messages []domain.StatusMessageDto
+	loadedAt time.Time
+}
+
+func (c *statusMessageCache) get(now time.Time) ([]domain.StatusMessageDto, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.loadedAt.IsZero() || now.Sub(c.loadedAt) >= statusCacheTTL {
+		return nil, false
+	}
+	return c.messages, true
+}
+
+func (c *statusMessageCache) set(messages []domain.StatusMessageDto, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.messages = messages
+	c.loadedAt = now
+}
+
+func (c *statusMessageCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.loadedAt = time.Time{}
+}
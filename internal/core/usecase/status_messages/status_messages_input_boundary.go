@@ -0,0 +1,13 @@
+package status_messages
+
+import (
+	"context"
+	"godating-dealls/internal/domain"
+)
+
+type InputStatusMessageBoundary interface {
+	ExecuteFetchStatus(ctx context.Context, boundary OutputStatusMessageBoundary) error
+	ExecuteFetchStatusMessages(ctx context.Context, boundary OutputStatusMessageBoundary) error
+	ExecuteSaveStatusMessage(ctx context.Context, messageId int64, request domain.StatusMessageRequest, boundary OutputStatusMessageBoundary) error
+	ExecuteDeleteStatusMessage(ctx context.Context, messageId int64, boundary OutputStatusMessageBoundary) error
+}
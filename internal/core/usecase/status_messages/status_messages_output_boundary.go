@@ -0,0 +1,10 @@
+package status_messages
+
+import "godating-dealls/internal/domain"
+
+type OutputStatusMessageBoundary interface {
+	StatusResponse(response domain.StatusResponse, err error)
+	StatusMessagesResponse(response []domain.StatusMessageResponse, err error)
+	StatusMessageResponse(response domain.StatusMessageResponse, err error)
+	DeleteStatusMessageResponse(response domain.DeleteStatusMessageResponse, err error)
+}
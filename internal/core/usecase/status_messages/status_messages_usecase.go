@@ -0,0 +1,161 @@
+package status_messages
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/status_messages"
+	"godating-dealls/internal/domain"
+	"log"
+	"sort"
+	"time"
+)
+
+var severityRank = map[string]int{
+	domain.StatusSeverityCritical: 0,
+	domain.StatusSeverityWarning:  1,
+	domain.StatusSeverityInfo:     2,
+}
+
+type StatusMessageUsecase struct {
+	DB                  *sql.DB
+	StatusMessageEntity status_messages.StatusMessageEntity
+	cache               *statusMessageCache
+}
+
+func NewStatusMessageUsecase(db *sql.DB, statusMessageEntity status_messages.StatusMessageEntity) InputStatusMessageBoundary {
+	return &StatusMessageUsecase{DB: db, StatusMessageEntity: statusMessageEntity, cache: &statusMessageCache{}}
+}
+
+// ExecuteFetchStatus answers the app poll from the cache, most severe and then newest message first
+func (s StatusMessageUsecase) ExecuteFetchStatus(ctx context.Context, boundary OutputStatusMessageBoundary) error {
+	now := time.Now()
+	messages, ok := s.cache.get(now)
+	if !ok {
+		fn := func(tx *sql.Tx) error {
+			var err error
+			messages, err = s.StatusMessageEntity.FindUnendedStatusMessagesEntity(ctx, tx, now)
+			return err
+		}
+
+		err := common.WithReadOnlyTransactionManager(ctx, s.DB, fn)
+		if err != nil {
+			log.Println("Transaction failed:", err)
+			return err
+		}
+		s.cache.set(messages, now)
+	}
+
+	var live []domain.StatusMessageDto
+	for _, message := range messages {
+		if message.StartsAt.After(now) || (message.EndsAt != nil && !message.EndsAt.After(now)) {
+			continue
+		}
+		live = append(live, message)
+	}
+	sort.SliceStable(live, func(i, j int) bool {
+		if severityRank[live[i].Severity] != severityRank[live[j].Severity] {
+			return severityRank[live[i].Severity] < severityRank[live[j].Severity]
+		}
+		return live[i].StartsAt.After(live[j].StartsAt)
+	})
+
+	res := domain.StatusResponse{Messages: []domain.StatusMessageResponse{}}
+	for _, message := range live {
+		res.Messages = append(res.Messages, toStatusMessageResponse(message))
+	}
+	boundary.StatusResponse(res, nil)
+	return nil
+}
+
+func (s StatusMessageUsecase) ExecuteFetchStatusMessages(ctx context.Context, boundary OutputStatusMessageBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		messages, err := s.StatusMessageEntity.FindStatusMessagesEntity(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		res := make([]domain.StatusMessageResponse, 0, len(messages))
+		for _, message := range messages {
+			res = append(res, toStatusMessageResponse(message))
+		}
+		boundary.StatusMessagesResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithReadOnlyTransactionManager(ctx, s.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+// ExecuteSaveStatusMessage creates the message when messageId is 0 and replaces it otherwise
+func (s StatusMessageUsecase) ExecuteSaveStatusMessage(ctx context.Context, messageId int64, request domain.StatusMessageRequest, boundary OutputStatusMessageBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		startsAt := time.Now().Truncate(time.Second)
+		if request.StartsAt != nil {
+			startsAt = *request.StartsAt
+		}
+
+		message, err := s.StatusMessageEntity.SaveStatusMessageEntity(ctx, tx, domain.StatusMessageDto{
+			MessageID: messageId,
+			Severity:  request.Severity,
+			Title:     request.Title,
+			Message:   request.Message,
+			StartsAt:  startsAt,
+			EndsAt:    request.EndsAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		boundary.StatusMessageResponse(toStatusMessageResponse(message), nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, s.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+		return err
+	}
+	s.cache.invalidate()
+	return nil
+}
+
+func (s StatusMessageUsecase) ExecuteDeleteStatusMessage(ctx context.Context, messageId int64, boundary OutputStatusMessageBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		err := s.StatusMessageEntity.DeleteStatusMessageEntity(ctx, tx, messageId)
+		if err != nil {
+			return err
+		}
+
+		boundary.DeleteStatusMessageResponse(domain.DeleteStatusMessageResponse{
+			MessageID: messageId,
+			Message:   "Status message removed",
+		}, nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, s.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+		return err
+	}
+	s.cache.invalidate()
+	return nil
+}
+
+func toStatusMessageResponse(message domain.StatusMessageDto) domain.StatusMessageResponse {
+	res := domain.StatusMessageResponse{
+		MessageID: message.MessageID,
+		Severity:  message.Severity,
+		Title:     message.Title,
+		Message:   message.Message,
+		StartsAt:  common.FormatTimeByParam(message.StartsAt),
+	}
+	if message.EndsAt != nil {
+		res.EndsAt = common.FormatTimeByParam(*message.EndsAt)
+	}
+	return res
+}
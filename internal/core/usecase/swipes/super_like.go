@@ -0,0 +1,133 @@
+package swipes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/daily_quotas"
+	"godating-dealls/internal/core/entities/entitlements"
+	"godating-dealls/internal/core/entities/notifications"
+	"godating-dealls/internal/core/entities/swipes"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/analytics"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"godating-dealls/internal/infra/notifier"
+	"log"
+)
+
+// ErrSuperLikeTargetNotFound is returned when the super liked account does not exist
+var ErrSuperLikeTargetNotFound = errors.New("account not found")
+
+// ErrSwipeTargetNotFound is returned when the swiped account does not exist
+var ErrSwipeTargetNotFound = errors.New("account not found")
+
+// ExecuteSuperLike is a like that takes the super like quota instead of the swipe quota. The sender is put at the
+// top of the candidates of the target and the target is told about it once the super like is stored, by email and
+// with a push unless the super like completed a match
+func (s SwipeUsecase) ExecuteSuperLike(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.SuperLikeRequest, boundary OutputSwipesBoundary) error {
+	var targetEmail string
+	var accountId int64
+	var tracked []analytics.Event
+	fn := func(tx *sql.Tx) error {
+		accountId = claims.AccountId
+
+		if request.AccountIdSwipe <= 0 || request.AccountIdSwipe == accountId {
+			return swipes.ErrInvalidSuperLike
+		}
+		if err := s.BlockEntity.EnsureNotBlockedEntity(ctx, tx, accountId, []int64{request.AccountIdSwipe}); err != nil {
+			return err
+		}
+		target, err := s.AccountEntity.FindAccountDetails(ctx, tx, request.AccountIdSwipe)
+		if err != nil {
+			return ErrSuperLikeTargetNotFound
+		}
+		targetEmail = target.Email
+
+		premium, err := s.EntitlementEntity.HasEntitlementEntity(ctx, tx, accountId, entitlements.EntitlementUnlimitedSwipes)
+		if err != nil {
+			return err
+		}
+		limit := s.SuperLikePolicy.DailyLimit
+		if premium {
+			limit = s.SuperLikePolicy.PremiumDailyLimit
+		}
+
+		// The pair is locked before the like is stored so a like back is not missed, like a swipe
+		if err := s.MatchEntity.LockMatchPairEntity(ctx, tx, accountId, request.AccountIdSwipe); err != nil {
+			return err
+		}
+		if err := s.DailyQuotasEntity.UseSuperLikeQuotaEntity(ctx, tx, accountId, limit); err != nil {
+			return err
+		}
+		swipeId, err := s.SwipeEntity.InsertSuperLikeEntity(ctx, tx, accountId, claims.UserId, request.AccountIdSwipe)
+		if err != nil {
+			return err
+		}
+		if err := s.recordSwipe(ctx, tx, swipeId, accountId, request.AccountIdSwipe, "super_like"); err != nil {
+			return err
+		}
+		tracked = append(tracked, analytics.NewEvent(analytics.EventSwiped, accountId, map[string]interface{}{"action": "super_like", "unlimited": premium}))
+		// A super like counts as a swipe of the day without taking the swipe quota
+		if err := s.DailyQuotasEntity.UpdateIncreaseSwipeCount(ctx, tx, accountId); err != nil {
+			return errors.New("failed to update swipe count")
+		}
+
+		if err := s.PhotoEntity.RecordPhotoLikeEntity(ctx, tx, request.AccountIdSwipe, accountId); err != nil {
+			return err
+		}
+		res := domain.SwipeResponse{Message: "Account Super Liked!"}
+		match, matched, err := s.MatchEntity.CreateMatchOnMutualLikeEntity(ctx, tx, accountId, request.AccountIdSwipe)
+		if err != nil {
+			return err
+		}
+		if matched {
+			res.Matched = true
+			res.MatchID = match.MatchID
+			res.Message = "It's a match!"
+			if err := s.notifyMatch(ctx, tx, request.AccountIdSwipe, match.MatchID); err != nil {
+				return err
+			}
+			if err := s.recordMatch(ctx, tx, match.MatchID, accountId, request.AccountIdSwipe); err != nil {
+				return err
+			}
+			tracked = append(tracked, matchedEvents(match.MatchID, accountId, request.AccountIdSwipe)...)
+		} else {
+			err := s.NotificationEntity.NotifyEntity(ctx, tx, []int64{request.AccountIdSwipe}, domain.NotificationDto{
+				Kind:  notifications.KindSuperLike,
+				Title: "Someone super liked you",
+				Body:  "Open the app to see who is first in your candidates.",
+			})
+			if err != nil {
+				return err
+			}
+		}
+		boundary.SuperLikeResponse(res, nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, s.DB, fn)
+	if errors.Is(err, daily_quotas.ErrSuperLikeQuotaExhausted) {
+		s.Analytics.Publish(ctx, analytics.NewEvent(analytics.EventQuotaExceeded, accountId, map[string]interface{}{"quota": "super_like"}))
+	}
+	if err != nil {
+		log.Println("Transaction failed:", err)
+		return err
+	}
+	s.Analytics.Publish(ctx, tracked...)
+
+	go s.notifySuperLike(context.WithoutCancel(ctx), targetEmail)
+	return nil
+}
+
+// notifySuperLike does not name the sender, the target finds it at the top of its candidates
+func (s SwipeUsecase) notifySuperLike(ctx context.Context, email string) {
+	err := s.Notifier.Notify(ctx, notifier.Message{
+		To:      email,
+		Subject: "Someone super liked you",
+		Body:    "Someone super liked you today, open the app to see who is first in your candidates.\n",
+	})
+	if err != nil {
+		log.Println("Failed to send super like notification:", err)
+	}
+}
@@ -0,0 +1,13 @@
+package swipes
+
+const (
+	DefaultSuperLikeDailyLimit        = 1
+	DefaultSuperLikePremiumDailyLimit = 5
+)
+
+// SuperLikePolicy has how many super likes an account sends a day, PremiumDailyLimit is for accounts with
+// unlimited swipes. It is read by config.Load from SUPER_LIKE_DAILY_LIMIT and SUPER_LIKE_PREMIUM_DAILY_LIMIT
+type SuperLikePolicy struct {
+	DailyLimit        int `validate:"min=0"`
+	PremiumDailyLimit int `validate:"min=0"`
+}
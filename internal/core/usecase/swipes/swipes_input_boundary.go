@@ -3,8 +3,11 @@ package swipes
 import (
 	"context"
 	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
 )
 
 type InputSwipeBoundary interface {
-	ExecuteSwipes(ctx context.Context, token string, request domain.SwipeRequest, boundary OutputSwipesBoundary) error
+	ExecuteSwipes(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.SwipeRequest, boundary OutputSwipesBoundary) error
+	ExecuteUndoSwipe(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputSwipesBoundary) error
+	ExecuteSuperLike(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.SuperLikeRequest, boundary OutputSwipesBoundary) error
 }
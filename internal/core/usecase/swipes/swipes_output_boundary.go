@@ -4,4 +4,6 @@ import res "godating-dealls/internal/domain"
 
 type OutputSwipesBoundary interface {
 	SwipeResponse(response res.SwipeResponse, err error)
+	SwipeUndoResponse(response res.SwipeUndoResponse, err error)
+	SuperLikeResponse(response res.SwipeResponse, err error)
 }
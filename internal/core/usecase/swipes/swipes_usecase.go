@@ -6,39 +6,83 @@ import (
 	"errors"
 	"godating-dealls/internal/common"
 	"godating-dealls/internal/core/entities/accounts"
+	"godating-dealls/internal/core/entities/blocks"
 	"godating-dealls/internal/core/entities/daily_quotas"
+	"godating-dealls/internal/core/entities/entitlements"
+	"godating-dealls/internal/core/entities/matches"
+	"godating-dealls/internal/core/entities/notifications"
+	"godating-dealls/internal/core/entities/outbox"
+	"godating-dealls/internal/core/entities/photos"
 	"godating-dealls/internal/core/entities/swipes"
 	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/analytics"
 	"godating-dealls/internal/infra/jsonwebtoken"
+	"godating-dealls/internal/infra/notifier"
 	"log"
+	"strconv"
 )
 
+// ErrSwipeUndoPremium is returned to accounts without a premium subscription
+var ErrSwipeUndoPremium = errors.New("undoing a swipe needs a premium subscription")
+
 type SwipeUsecase struct {
-	DB                *sql.DB
-	SwipeEntity       swipes.SwipeEntity
-	DailyQuotasEntity daily_quotas.DailyQuotasEntity
-	AccountEntity     accounts.AccountEntity
+	DB                 *sql.DB
+	SwipeEntity        swipes.SwipeEntity
+	DailyQuotasEntity  daily_quotas.DailyQuotasEntity
+	AccountEntity      accounts.AccountEntity
+	MatchEntity        matches.MatchEntity
+	EntitlementEntity  entitlements.EntitlementEntity
+	PhotoEntity        photos.PhotoEntity
+	BlockEntity        blocks.BlockEntity
+	NotificationEntity notifications.NotificationEntity
+	Notifier           notifier.Notifier
+	SuperLikePolicy    SuperLikePolicy
+	OutboxEntity       outbox.OutboxEntity
+	Analytics          analytics.Publisher
 }
 
-func NewSwipeUsecase(db *sql.DB, swipeEntity swipes.SwipeEntity, dailyQuotasEntity daily_quotas.DailyQuotasEntity, accountEntity accounts.AccountEntity) InputSwipeBoundary {
-	return &SwipeUsecase{DB: db, SwipeEntity: swipeEntity, DailyQuotasEntity: dailyQuotasEntity, AccountEntity: accountEntity}
+func NewSwipeUsecase(db *sql.DB, swipeEntity swipes.SwipeEntity, dailyQuotasEntity daily_quotas.DailyQuotasEntity, accountEntity accounts.AccountEntity, matchEntity matches.MatchEntity, entitlementEntity entitlements.EntitlementEntity, photoEntity photos.PhotoEntity,
+	blockEntity blocks.BlockEntity, notificationEntity notifications.NotificationEntity, notifier notifier.Notifier, superLikePolicy SuperLikePolicy, outboxEntity outbox.OutboxEntity,
+	analyticsPublisher analytics.Publisher) InputSwipeBoundary {
+	return &SwipeUsecase{DB: db, SwipeEntity: swipeEntity, DailyQuotasEntity: dailyQuotasEntity, AccountEntity: accountEntity, MatchEntity: matchEntity, EntitlementEntity: entitlementEntity, PhotoEntity: photoEntity,
+		BlockEntity: blockEntity, NotificationEntity: notificationEntity, Notifier: notifier, SuperLikePolicy: superLikePolicy, OutboxEntity: outboxEntity,
+		Analytics: analyticsPublisher}
 }
 
-func (s SwipeUsecase) ExecuteSwipes(ctx context.Context, token string, request domain.SwipeRequest, boundary OutputSwipesBoundary) error {
+func (s SwipeUsecase) ExecuteSwipes(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.SwipeRequest, boundary OutputSwipesBoundary) error {
+	var tracked []analytics.Event
 	fn := func(tx *sql.Tx) error {
-		// Verify token is not expired
-		claims, err := jsonwebtoken.VerifyJWTToken(token)
-		if err != nil {
-			return errors.New("invalid token")
+		accountIdIdentifier := claims.AccountId
+		// Like a super like, a swipe never reaches an account blocked either way
+		if request.AccountIdSwipe <= 0 || request.AccountIdSwipe == accountIdIdentifier {
+			return swipes.ErrInvalidSwipe
+		}
+		if err := s.BlockEntity.EnsureNotBlockedEntity(ctx, tx, accountIdIdentifier, []int64{request.AccountIdSwipe}); err != nil {
+			return err
+		}
+		if _, err := s.AccountEntity.FindAccountDetails(ctx, tx, request.AccountIdSwipe); err != nil {
+			return ErrSwipeTargetNotFound
 		}
 
-		accountIdIdentifier := claims.AccountId
-		verifiedAccount, err := s.AccountEntity.FindAccountVerifiedEntities(ctx, tx, accountIdIdentifier)
+		unlimitedSwipes, err := s.EntitlementEntity.HasEntitlementEntity(ctx, tx, accountIdIdentifier, entitlements.EntitlementUnlimitedSwipes)
+		if err != nil {
+			return err
+		}
 
 		var message string
+		liked := request.ActionType != "left"
+
+		// A like may complete a match, the pair is locked before the swipe is stored so the like back is not missed
+		if liked {
+			if err := s.MatchEntity.LockMatchPairEntity(ctx, tx, accountIdIdentifier, request.AccountIdSwipe); err != nil {
+				return err
+			}
+		}
 
-		if verifiedAccount {
-			err := s.SwipeEntity.InsertSwipeActionEntity(ctx, tx, claims.AccountId, claims.UserId, request.ActionType, request.AccountIdSwipe)
+		swiped := false
+		var swipeId int64
+		if unlimitedSwipes {
+			swipeId, err = s.SwipeEntity.InsertSwipeActionEntity(ctx, tx, claims.AccountId, claims.UserId, request.ActionType, request.AccountIdSwipe)
 			if err != nil {
 				return errors.New("failed to insert swipe action entity")
 			}
@@ -48,39 +92,131 @@ func (s SwipeUsecase) ExecuteSwipes(ctx context.Context, token string, request d
 			if err != nil {
 				return errors.New("failed to update swipe count")
 			}
+			swiped = true
 		} else {
 			// before swipe check total quota is not limited
 			totalQuotaSwipe, err := s.DailyQuotasEntity.FetchTotalDailyQuotas(ctx, tx, accountIdIdentifier)
 			if err != nil {
 				return errors.New("failed to fetch total swipe count")
 			}
-			// if user is limited cannot be swipes
-			if totalQuotaSwipe > 0 && totalQuotaSwipe <= 10 {
+			// if user is limited cannot be swipes, quota can be above the default 10 with login rewards
+			if totalQuotaSwipe > 0 {
 				err := s.DailyQuotasEntity.UpdateIncreaseSwipeCountAndDecreaseTotalQuota(ctx, tx, accountIdIdentifier)
 				if err != nil {
 					return errors.New("failed to update swipe count and total count")
 				}
 
-				err = s.SwipeEntity.InsertSwipeActionEntity(ctx, tx, claims.AccountId, claims.UserId, request.ActionType, request.AccountIdSwipe)
+				swipeId, err = s.SwipeEntity.InsertSwipeActionEntity(ctx, tx, claims.AccountId, claims.UserId, request.ActionType, request.AccountIdSwipe)
 				if err != nil {
 					return errors.New("failed to insert swipe action entity")
 				}
+				swiped = true
 			} else {
 				message = "The total quota for swipe users is limited, please try next day!"
+				tracked = append(tracked, analytics.NewEvent(analytics.EventQuotaExceeded, accountIdIdentifier, map[string]interface{}{"quota": "swipe"}))
+			}
+		}
+
+		res := domain.SwipeResponse{Message: message}
+		if swiped {
+			if err := s.recordSwipe(ctx, tx, swipeId, accountIdIdentifier, request.AccountIdSwipe, request.ActionType); err != nil {
+				return err
+			}
+			tracked = append(tracked, analytics.NewEvent(analytics.EventSwiped, accountIdIdentifier, map[string]interface{}{
+				"action":    request.ActionType,
+				"unlimited": unlimitedSwipes,
+			}))
+		}
+		if swiped && liked {
+			// Credit the like to the smart photo the account was shown first
+			if err := s.PhotoEntity.RecordPhotoLikeEntity(ctx, tx, request.AccountIdSwipe, accountIdIdentifier); err != nil {
+				return err
+			}
+
+			match, matched, err := s.MatchEntity.CreateMatchOnMutualLikeEntity(ctx, tx, accountIdIdentifier, request.AccountIdSwipe)
+			if err != nil {
+				return err
+			}
+			if matched {
+				res.Matched = true
+				res.MatchID = match.MatchID
+				res.Message = "It's a match!"
+				if err := s.notifyMatch(ctx, tx, request.AccountIdSwipe, match.MatchID); err != nil {
+					return err
+				}
+				if err := s.recordMatch(ctx, tx, match.MatchID, accountIdIdentifier, request.AccountIdSwipe); err != nil {
+					return err
+				}
+				tracked = append(tracked, matchedEvents(match.MatchID, accountIdIdentifier, request.AccountIdSwipe)...)
 			}
 		}
 
-		if message == "" {
+		if swiped {
+			lastSwipe := domain.LastSwipeDto{
+				AccountID:      accountIdIdentifier,
+				SwipeID:        swipeId,
+				AccountIDSwipe: request.AccountIdSwipe,
+				Action:         request.ActionType,
+				QuotaUsed:      !unlimitedSwipes,
+			}
+			if res.Matched {
+				lastSwipe.MatchID = &res.MatchID
+			}
+			if err := s.SwipeEntity.RememberLastSwipeEntity(ctx, tx, lastSwipe); err != nil {
+				return err
+			}
+		}
+
+		if res.Message == "" {
 			if request.ActionType == "left" {
-				message = "Account Passed!"
+				res.Message = "Account Passed!"
 			} else {
-				message = "Account Liked!"
+				res.Message = "Account Liked!"
 			}
 		}
-		boundary.SwipeResponse(domain.SwipeResponse{
-			Message: message,
-		}, nil)
+		boundary.SwipeResponse(res, nil)
+
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, s.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+		return err
+	}
+	s.Analytics.Publish(ctx, tracked...)
+	return nil
+}
+
+// ExecuteUndoSwipe is a premium feature, it takes back the last swipe of the account within the undo window.
+// The swiped account shows up in discovery again and the swipe is given back on the daily quota
+func (s SwipeUsecase) ExecuteUndoSwipe(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputSwipesBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		entitled, err := s.EntitlementEntity.HasEntitlementEntity(ctx, tx, claims.AccountId, entitlements.EntitlementSwipeUndo)
+		if err != nil {
+			return err
+		}
+		if !entitled {
+			return ErrSwipeUndoPremium
+		}
+
+		lastSwipe, err := s.SwipeEntity.UndoLastSwipeEntity(ctx, tx, claims.AccountId)
+		if err != nil {
+			return err
+		}
+		if err := s.DailyQuotasEntity.RestoreSwipeQuotaEntity(ctx, tx, claims.AccountId, lastSwipe.SwipeDate, lastSwipe.QuotaUsed); err != nil {
+			return err
+		}
 
+		res := domain.SwipeUndoResponse{AccountIdSwipe: lastSwipe.AccountIDSwipe, ActionType: "left", Message: "Pass undone!"}
+		if lastSwipe.Action != "left" {
+			if err := s.PhotoEntity.RevokePhotoLikeEntity(ctx, tx, lastSwipe.AccountIDSwipe, claims.AccountId); err != nil {
+				return err
+			}
+			res.ActionType = "right"
+			res.Message = "Like undone!"
+		}
+		boundary.SwipeUndoResponse(res, nil)
 		return nil
 	}
 
@@ -90,3 +226,38 @@ func (s SwipeUsecase) ExecuteSwipes(ctx context.Context, token string, request d
 	}
 	return err
 }
+
+// notifyMatch pushes the match to the account liked first, the account completing it sees it in the response
+func (s SwipeUsecase) notifyMatch(ctx context.Context, tx *sql.Tx, matchedAccountId int64, matchId int64) error {
+	return s.NotificationEntity.NotifyEntity(ctx, tx, []int64{matchedAccountId}, domain.NotificationDto{
+		Kind:  notifications.KindMatch,
+		Title: "It's a match!",
+		Body:  "You and someone you liked like each other, say hi.",
+		Data:  map[string]string{"match_id": strconv.FormatInt(matchId, 10)},
+	})
+}
+
+// recordSwipe writes the swipe to the outbox with the swipe itself, action is left, right or super_like
+func (s SwipeUsecase) recordSwipe(ctx context.Context, tx *sql.Tx, swipeId int64, accountId int64, targetAccountId int64, action string) error {
+	return s.OutboxEntity.RecordEventEntity(ctx, tx, outbox.EventSwipeRecorded, accountId, domain.SwipeRecordedEvent{
+		SwipeID:         swipeId,
+		AccountID:       accountId,
+		TargetAccountID: targetAccountId,
+		Action:          action,
+	})
+}
+
+func (s SwipeUsecase) recordMatch(ctx context.Context, tx *sql.Tx, matchId int64, accountId int64, matchedAccountId int64) error {
+	return s.OutboxEntity.RecordEventEntity(ctx, tx, outbox.EventMatchCreated, matchId, domain.MatchCreatedEvent{
+		MatchID:    matchId,
+		AccountIDs: []int64{accountId, matchedAccountId},
+	})
+}
+
+// matchedEvents count the match in the funnels of both accounts, liked_back is set for the one whose like completed it
+func matchedEvents(matchId int64, accountId int64, matchedAccountId int64) []analytics.Event {
+	return []analytics.Event{
+		analytics.NewEvent(analytics.EventMatched, accountId, map[string]interface{}{"match_id": matchId, "liked_back": true}),
+		analytics.NewEvent(analytics.EventMatched, matchedAccountId, map[string]interface{}{"match_id": matchId, "liked_back": false}),
+	}
+}
@@ -0,0 +1,81 @@
+package swipes
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/accounts"
+	"godating-dealls/internal/core/entities/blocks"
+	"godating-dealls/internal/core/entities/daily_quotas"
+	"godating-dealls/internal/core/entities/entitlements"
+	"godating-dealls/internal/core/entities/matches"
+	"godating-dealls/internal/core/entities/notifications"
+	"godating-dealls/internal/core/entities/outbox"
+	"godating-dealls/internal/core/entities/photos"
+	"godating-dealls/internal/core/entities/swipes"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/analytics"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"godating-dealls/internal/infra/mysql/repo"
+	"godating-dealls/internal/infra/notifier"
+	"godating-dealls/internal/infra/sqlite/sqlitetest"
+	"testing"
+)
+
+func newBenchmarkSwipeUsecase(db *sql.DB) InputSwipeBoundary {
+	val := common.NewValidator()
+	accountRepository := repo.NewAccountsRepositoryImpl()
+	dailyQuotaRepository := repo.NewDailyQuotasRepositoryImpl()
+	return NewSwipeUsecase(db,
+		swipes.NewSwipeEntityImpl(repo.NewSwipesRepositoryImpl()),
+		daily_quotas.NewDailyQuotasEntityImpl(val, dailyQuotaRepository),
+		accounts.NewAccountsEntityImpl(accountRepository, val, common.NewEmailDomainPolicy(common.EmailDomainRules{Mode: common.EmailDomainModeDeny})),
+		matches.NewMatchEntityImpl(repo.NewMatchesRepositoryImpl()),
+		entitlements.NewEntitlementEntityImpl(repo.NewPurchasePackagesRepositoryImpl(), accountRepository, dailyQuotaRepository),
+		photos.NewPhotoEntityImpl(repo.NewProfilePhotosRepositoryImpl(), repo.NewSmartPhotosRepositoryImpl()),
+		blocks.NewBlockEntityImpl(repo.NewBlocksRepositoryImpl(), accountRepository),
+		notifications.NewNotificationEntityImpl(repo.NewPushNotificationsRepositoryImpl(), repo.NewNotificationsRepositoryImpl()),
+		notifier.LogNotifier{},
+		SuperLikePolicy{DailyLimit: DefaultSuperLikeDailyLimit, PremiumDailyLimit: DefaultSuperLikePremiumDailyLimit},
+		outbox.NewOutboxEntityImpl(repo.NewOutboxEventsRepositoryImpl()),
+		analytics.NopPublisher{})
+}
+
+// discardSwipesBoundary stands in for the presenter, it only keeps the first error the usecase reports
+type discardSwipesBoundary struct {
+	err error
+}
+
+func (d *discardSwipesBoundary) SwipeResponse(response domain.SwipeResponse, err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+func (d *discardSwipesBoundary) SwipeUndoResponse(response domain.SwipeUndoResponse, err error) {}
+
+func (d *discardSwipesBoundary) SuperLikeResponse(response domain.SwipeResponse, err error) {}
+
+// BenchmarkExecuteSwipes likes a different account on every iteration, the quota of the day covers all of them
+func BenchmarkExecuteSwipes(b *testing.B) {
+	db := sqlitetest.Open(b)
+	seeded := sqlitetest.CreateAccounts(b, db, b.N+1, b.N)
+	swiper, targets := seeded[0], seeded[1:]
+	claims := &jsonwebtoken.JWTTokenClaims{AccountId: swiper.AccountId, UserId: swiper.UserId, Email: swiper.Email}
+	ctx := jsonwebtoken.WithClaims(context.Background(), claims)
+	usecase := newBenchmarkSwipeUsecase(db)
+	boundary := &discardSwipesBoundary{}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		request := domain.SwipeRequest{ActionType: "right", AccountIdSwipe: targets[i].AccountId}
+		if err := usecase.ExecuteSwipes(ctx, claims, request, boundary); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	if boundary.err != nil {
+		b.Fatal(boundary.err)
+	}
+}
@@ -0,0 +1,8 @@
+package users
+
+const DefaultDiscoveryRadiusKm = 50
+
+// DiscoveryPolicy limits the daily candidates to those within RadiusKm of the viewer location, DISCOVERY_RADIUS_KM
+type DiscoveryPolicy struct {
+	RadiusKm int `validate:"gt=0"`
+}
@@ -0,0 +1,80 @@
+package users
+
+import (
+	"context"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/redisclient"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const seenTodayDateLayout = "2006-01-02"
+
+// seenTodayKey days are UTC like the daily selection task
+func seenTodayKey(accountId int64, day time.Time) string {
+	return redisclient.SeenTodayKey.Key(strconv.FormatInt(accountId, 10), day.UTC().Format(seenTodayDateLayout))
+}
+
+// excludeSeenToday leaves out the accounts already shown to the viewer today. Without redis the list is served as it
+// is, the selection histories still keep the free daily selection to one per day
+func (u UserUsecase) excludeSeenToday(ctx context.Context, accountId int64, usersList []domain.AllUserViews) []domain.AllUserViews {
+	if u.Rds == nil || len(usersList) == 0 {
+		return usersList
+	}
+	members, err := u.Rds.SetMembersFromRedis(ctx, seenTodayKey(accountId, time.Now()))
+	if err != nil {
+		log.Printf("Failed to load accounts seen today: %v", err)
+		return usersList
+	}
+	seen := make(map[string]bool, len(members))
+	for _, member := range members {
+		seen[member] = true
+	}
+
+	unseen := usersList[:0]
+	for _, user := range usersList {
+		if !seen[strconv.FormatInt(user.AccountID, 10)] {
+			unseen = append(unseen, user)
+		}
+	}
+	return unseen
+}
+
+func (u UserUsecase) markSeenToday(ctx context.Context, accountId int64, accountIds []int64) {
+	if u.Rds == nil || len(accountIds) == 0 {
+		return
+	}
+	members := make([]string, 0, len(accountIds))
+	for _, id := range accountIds {
+		members = append(members, strconv.FormatInt(id, 10))
+	}
+	if err := u.Rds.AddToSetInRedis(ctx, seenTodayKey(accountId, time.Now()), members...); err != nil {
+		log.Printf("Failed to record accounts seen today: %v", err)
+	}
+}
+
+// ExecuteClearSeenToday is the nightly cleanup, it deletes the sets of past days. The sets also expire on their own,
+// clearing them when the day ends frees the memory a day earlier
+func (u UserUsecase) ExecuteClearSeenToday(ctx context.Context) error {
+	keys, err := u.Rds.ScanKeysFromRedis(ctx, redisclient.SeenTodayKey)
+	if err != nil {
+		return err
+	}
+
+	today := time.Now().UTC().Format(seenTodayDateLayout)
+	cleared := 0
+	for _, key := range keys {
+		day := key[strings.LastIndex(key, ":")+1:]
+		if day >= today {
+			continue
+		}
+		if err := u.Rds.ClearFromRedis(ctx, key); err != nil {
+			return err
+		}
+		cleared++
+	}
+	log.Printf("Cleared %d seen today sets of past days", cleared)
+	return nil
+}
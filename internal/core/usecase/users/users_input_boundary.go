@@ -3,9 +3,13 @@ package users
 import (
 	"context"
 	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
 )
 
 type InputUserBoundary interface {
-	ExecuteUserViewsUsecase(ctx context.Context, token string, boundary OutputUserBoundary) error
-	ExecutePatchUserUsecase(ctx context.Context, token string, request domain.PatchUserRequest, boundary OutputUserBoundary) error
+	ExecuteUserViewsUsecase(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputUserBoundary) error
+	ExecutePatchUserUsecase(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.PatchUserRequest, boundary OutputUserBoundary) error
+	ExecuteUpdateLocationUsecase(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.UserLocationRequest, boundary OutputUserBoundary) error
+	ExecuteClearLocationUsecase(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputUserBoundary) error
+	ExecuteClearSeenToday(ctx context.Context) error
 }
@@ -7,4 +7,6 @@ import (
 type OutputUserBoundary interface {
 	UserViewsResponse(response []res.UserViewsResponse, err error)
 	PatchUserResponse(response res.PatchUserResponse, err error)
+	UserLocationResponse(response res.UserLocationResponse, err error)
+	ClearUserLocationResponse(err error)
 }
@@ -4,23 +4,38 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"godating-dealls/internal/common"
 	"godating-dealls/internal/core/entities/accounts"
+	"godating-dealls/internal/core/entities/photos"
+	profilechanges "godating-dealls/internal/core/entities/profile_changes"
 	"godating-dealls/internal/core/entities/selection_histories"
 	"godating-dealls/internal/core/entities/task_history"
 	"godating-dealls/internal/core/entities/users"
 	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/analytics"
 	"godating-dealls/internal/infra/jsonwebtoken"
+	"godating-dealls/internal/infra/redisclient"
+	"godating-dealls/internal/infra/storage"
 	"log"
+	"math"
 	"time"
 )
 
+var userViewResponse = common.NewMapping(domain.UserViewsResponse{}, domain.AllUserViews{}, "DistanceKm")
+
 type UserUsecase struct {
 	DB                     *sql.DB
 	UserEntity             users.UserEntity
 	AccountEntity          accounts.AccountEntity
 	SelectionHistoryEntity selection_histories.SelectionHistoryEntity
 	TaskHistoryEntity      task_history.TaskHistoryEntity
+	PhotoEntity            photos.PhotoEntity
+	Storage                storage.Storage
+	DiscoveryPolicy        DiscoveryPolicy
+	// Rds holds the accounts seen today, nil serves the daily list without leaving them out
+	Rds       redisclient.RedisInterface
+	Analytics analytics.Publisher
 }
 
 func NewUserUsecase(
@@ -28,22 +43,31 @@ func NewUserUsecase(
 	userEntity users.UserEntity,
 	accountEntity accounts.AccountEntity,
 	selectionHistoryEntity selection_histories.SelectionHistoryEntity,
-	taskHistoryEntity task_history.TaskHistoryEntity) InputUserBoundary {
+	taskHistoryEntity task_history.TaskHistoryEntity,
+	photoEntity photos.PhotoEntity,
+	storage storage.Storage,
+	discoveryPolicy DiscoveryPolicy,
+	rds redisclient.RedisInterface,
+	analyticsPublisher analytics.Publisher) InputUserBoundary {
 	return &UserUsecase{
 		DB:                     db,
 		UserEntity:             userEntity,
 		AccountEntity:          accountEntity,
 		SelectionHistoryEntity: selectionHistoryEntity,
 		TaskHistoryEntity:      taskHistoryEntity,
+		PhotoEntity:            photoEntity,
+		Storage:                storage,
+		DiscoveryPolicy:        discoveryPolicy,
+		Rds:                    rds,
+		Analytics:              analyticsPublisher,
 	}
 }
 
-func (u UserUsecase) ExecuteUserViewsUsecase(ctx context.Context, token string, boundary OutputUserBoundary) error {
+func (u UserUsecase) ExecuteUserViewsUsecase(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputUserBoundary) error {
+	var viewerAccountId int64
+	var shown []int64
+	var selectionSeen analytics.Event
 	fn := func(tx *sql.Tx) error {
-		// Verify token is not expired
-		claims, err := jsonwebtoken.VerifyJWTToken(token)
-		common.HandleErrorReturn(err)
-
 		// first find account type by claims if account verified return all, if not just 10 data
 		accountIdIdentifier := claims.AccountId
 		verifiedAccount, err := u.AccountEntity.FindAccountVerifiedEntities(ctx, tx, accountIdIdentifier)
@@ -52,9 +76,16 @@ func (u UserUsecase) ExecuteUserViewsUsecase(ctx context.Context, token string,
 		shouldRun, err := u.shouldRunHistoricalSelectionTask(ctx, tx, accountIdIdentifier)
 		common.HandleErrorReturn(err)
 
-		usersList, err := u.UserEntity.FindAllUserViewsEntities(ctx, tx, verifiedAccount, shouldRun, accountIdIdentifier)
+		usersList, err := u.UserEntity.FindAllUserViewsEntities(ctx, tx, verifiedAccount, shouldRun, accountIdIdentifier, u.DiscoveryPolicy.RadiusKm)
 		common.HandleErrorReturn(err)
 
+		// A new selection leaves out the accounts already shown today. The free selection of the day is served again
+		// until it is swiped, it is the daily quota itself rather than a repeat
+		newSelection := verifiedAccount || shouldRun
+		if newSelection {
+			usersList = u.excludeSeenToday(ctx, accountIdIdentifier, usersList)
+		}
+
 		if shouldRun {
 			if len(usersList) > 0 {
 				for _, user := range usersList {
@@ -67,21 +98,43 @@ func (u UserUsecase) ExecuteUserViewsUsecase(ctx context.Context, token string,
 			common.HandleErrorReturn(err)
 		}
 
+		accountIds := make([]int64, 0, len(usersList))
+		for _, user := range usersList {
+			accountIds = append(accountIds, user.AccountID)
+		}
+		if newSelection {
+			viewerAccountId, shown = accountIdIdentifier, accountIds
+		}
+		selectionSeen = analytics.NewEvent(analytics.EventSelectionSeen, accountIdIdentifier, map[string]interface{}{
+			"candidates":    len(accountIds),
+			"new_selection": newSelection,
+			"verified":      verifiedAccount,
+		})
+		photosByAccount, err := u.PhotoEntity.FindPhotosByAccountsEntity(ctx, tx, accountIds)
+		if err != nil {
+			return err
+		}
+		photosByAccount, err = u.PhotoEntity.ArrangePhotosForViewerEntity(ctx, tx, accountIdIdentifier, photosByAccount)
+		if err != nil {
+			return err
+		}
+
 		// Build response
 		var userViews []domain.UserViewsResponse
 		for _, user := range usersList {
-			userViews = append(userViews, domain.UserViewsResponse{
-				UserID:    user.UserID,
-				AccountID: user.AccountID,
-				Username:  user.Username,
-				FullName:  user.FullName,
-				Age:       user.Age,
-				Gender:    user.Gender,
-				Bio:       user.Bio,
-				Verified:  user.Verified,
-				Videos:    make([]string, 0),
-				Photos:    make([]string, 0),
-			})
+			userView := domain.UserViewsResponse{
+				Videos: make([]string, 0),
+				Photos: make([]string, 0),
+			}
+			userViewResponse.Map(&userView, user)
+			if user.DistanceKm != nil {
+				distanceKm := int(math.Max(1, math.Ceil(*user.DistanceKm)))
+				userView.DistanceKm = &distanceKm
+			}
+			for _, photo := range photosByAccount[user.AccountID] {
+				userView.Photos = append(userView.Photos, u.Storage.URL(photo.StorageKey))
+			}
+			userViews = append(userViews, userView)
 		}
 		boundary.UserViewsResponse(userViews, nil)
 
@@ -91,8 +144,12 @@ func (u UserUsecase) ExecuteUserViewsUsecase(ctx context.Context, token string,
 	err := common.WithExecuteTransactionalManager(ctx, u.DB, fn)
 	if err != nil {
 		log.Println("Transaction failed:", err)
+		return err
 	}
-	return err
+	// Only what was committed and served counts as seen
+	u.markSeenToday(ctx, viewerAccountId, shown)
+	u.Analytics.Publish(ctx, selectionSeen)
+	return nil
 }
 
 // shouldRunHistoricalSelectionTask checks if the historical selection task should run today.
@@ -110,14 +167,31 @@ func (u UserUsecase) shouldRunHistoricalSelectionTask(ctx context.Context, tx *s
 	return lastRunTimestamp < today.Unix(), nil
 }
 
-func (u UserUsecase) ExecutePatchUserUsecase(ctx context.Context, token string, request domain.PatchUserRequest, boundary OutputUserBoundary) error {
+func (u UserUsecase) ExecutePatchUserUsecase(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.PatchUserRequest, boundary OutputUserBoundary) error {
 	fn := func(tx *sql.Tx) error {
-		// Verify token is not expired
-		claims, err := jsonwebtoken.VerifyJWTToken(token)
+		userID := claims.UserId
+
+		// Identity fields of a verified account change only through a reviewed profile change request
+		verified, err := u.AccountEntity.FindAccountVerifiedEntities(ctx, tx, claims.AccountId)
 		if err != nil {
-			return errors.New("invalid token")
+			return err
+		}
+		if verified {
+			current, err := u.UserEntity.FindUserDetailEntity(ctx, tx, claims.AccountId)
+			if err != nil {
+				return errors.New("failed to find user")
+			}
+			requested := map[string]*string{
+				"full_name":     request.FullName,
+				"date_of_birth": request.DateOfBirth,
+				"gender":        request.Gender,
+			}
+			for field, value := range profilechanges.LockedFieldValues(current) {
+				if requested[field] != nil && *requested[field] != value {
+					return fmt.Errorf("%w: %s", profilechanges.ErrProfileFieldLocked, field)
+				}
+			}
 		}
-		userID := claims.UserId
 
 		patch := domain.PatchUser{
 			UserID:      userID,
@@ -126,6 +200,7 @@ func (u UserUsecase) ExecutePatchUserUsecase(ctx context.Context, token string,
 			Bio:         request.Bio,
 			Address:     request.Address,
 			DateOfBirth: request.DateOfBirth,
+			PhoneNumber: request.PhoneNumber,
 		}
 		res, err := u.UserEntity.UpdateUserEntities(ctx, tx, patch)
 		boundary.PatchUserResponse(domain.PatchUserResponse{
@@ -145,7 +220,46 @@ func (u UserUsecase) ExecutePatchUserUsecase(ctx context.Context, token string,
 
 	err := common.WithExecuteTransactionalManager(ctx, u.DB, fn)
 	if err != nil {
-		return errors.New("execute transactional failed: " + err.Error())
+		return fmt.Errorf("execute transactional failed: %w", err)
+	}
+	return err
+}
+
+func (u UserUsecase) ExecuteUpdateLocationUsecase(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, request domain.UserLocationRequest, boundary OutputUserBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		dto := domain.UserLocationDto{Latitude: request.Latitude, Longitude: request.Longitude}
+		if err := u.UserEntity.UpdateUserLocationEntity(ctx, tx, claims.AccountId, dto); err != nil {
+			return err
+		}
+
+		boundary.UserLocationResponse(domain.UserLocationResponse{
+			Latitude:  *request.Latitude,
+			Longitude: *request.Longitude,
+			RadiusKm:  u.DiscoveryPolicy.RadiusKm,
+		}, nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, u.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
+	}
+	return err
+}
+
+func (u UserUsecase) ExecuteClearLocationUsecase(ctx context.Context, claims *jsonwebtoken.JWTTokenClaims, boundary OutputUserBoundary) error {
+	fn := func(tx *sql.Tx) error {
+		if err := u.UserEntity.ClearUserLocationEntity(ctx, tx, claims.AccountId); err != nil {
+			return err
+		}
+
+		boundary.ClearUserLocationResponse(nil)
+		return nil
+	}
+
+	err := common.WithExecuteTransactionalManager(ctx, u.DB, fn)
+	if err != nil {
+		log.Println("Transaction failed:", err)
 	}
 	return err
 }
@@ -0,0 +1,74 @@
+package users
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/accounts"
+	"godating-dealls/internal/core/entities/photos"
+	"godating-dealls/internal/core/entities/selection_histories"
+	"godating-dealls/internal/core/entities/task_history"
+	"godating-dealls/internal/core/entities/users"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/analytics"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"godating-dealls/internal/infra/mysql/repo"
+	"godating-dealls/internal/infra/sqlite/sqlitetest"
+	"godating-dealls/internal/infra/storage"
+	"testing"
+)
+
+func newBenchmarkUserUsecase(db *sql.DB) InputUserBoundary {
+	val := common.NewValidator()
+	return NewUserUsecase(db,
+		users.NewUserEntityImpl(repo.NewUsersRepositoryImpl(), val),
+		accounts.NewAccountsEntityImpl(repo.NewAccountsRepositoryImpl(), val, common.NewEmailDomainPolicy(common.EmailDomainRules{Mode: common.EmailDomainModeDeny})),
+		selection_histories.NewSelectionHistoryEntityImpl(repo.NewSelectionHistoriesRepositoryImpl()),
+		task_history.NewTaskHistoryEntityImpl(repo.NewTaskHistorySQLRepository()),
+		photos.NewPhotoEntityImpl(repo.NewProfilePhotosRepositoryImpl(), repo.NewSmartPhotosRepositoryImpl()),
+		storage.NewLocalStorage(storage.LocalConfig{Dir: storage.DefaultLocalDir, PublicURL: storage.DefaultLocalPublicURL}),
+		DiscoveryPolicy{RadiusKm: DefaultDiscoveryRadiusKm},
+		// Without redis every iteration gets the same candidates, the seen today set would empty the list
+		nil,
+		analytics.NopPublisher{})
+}
+
+// discardUserBoundary stands in for the presenter, it only keeps the first error the usecase reports
+type discardUserBoundary struct {
+	err error
+}
+
+func (d *discardUserBoundary) UserViewsResponse(response []domain.UserViewsResponse, err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+func (d *discardUserBoundary) PatchUserResponse(response domain.PatchUserResponse, err error) {}
+
+func (d *discardUserBoundary) UserLocationResponse(response domain.UserLocationResponse, err error) {}
+
+func (d *discardUserBoundary) ClearUserLocationResponse(err error) {}
+
+// BenchmarkExecuteUserViewsUsecase serves the daily selection of an unverified account, after the first iteration it
+// is the same selection served again
+func BenchmarkExecuteUserViewsUsecase(b *testing.B) {
+	db := sqlitetest.Open(b)
+	viewer := sqlitetest.CreateAccounts(b, db, 50, 10)[0]
+	claims := &jsonwebtoken.JWTTokenClaims{AccountId: viewer.AccountId, UserId: viewer.UserId, Email: viewer.Email}
+	ctx := jsonwebtoken.WithClaims(context.Background(), claims)
+	usecase := newBenchmarkUserUsecase(db)
+	boundary := &discardUserBoundary{}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := usecase.ExecuteUserViewsUsecase(ctx, claims, boundary); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	if boundary.err != nil {
+		b.Fatal(boundary.err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"godating-dealls/internal/core/usecase/accounts"
 	presenters "godating-dealls/internal/delivery/presenter"
 	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
 	"net/http"
 )
 
@@ -20,22 +21,22 @@ func NewAccountHandler(inputAccountBoundary accounts.InputAccountBoundary) *Acco
 func (ac *AccountHandler) FetchAccountDetailsHandler(w http.ResponseWriter, r *http.Request) {
 	// If user premium is unlimited, if not is just 10 data
 	ctx := r.Context()
-	token, ok := ctx.Value("token").(string)
-	if !ok || token == "" {
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
 		http.Error(w, "Invalid token", http.StatusUnauthorized)
 		return
 	}
 
 	presenter := presenters.NewAccountPresenter(w)
 
-	err := ac.InputAccountBoundary.ExecuteFetchAccountDetail(ctx, token, presenter)
+	err := ac.InputAccountBoundary.ExecuteFetchAccountDetail(ctx, claims, presenter)
 	common.HandleErrorReturn(err)
 }
 
 func (ac *AccountHandler) AccountViewHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	token, ok := ctx.Value("token").(string)
-	if !ok || token == "" {
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
 		http.Error(w, "Invalid token", http.StatusUnauthorized)
 		return
 	}
@@ -48,6 +49,34 @@ func (ac *AccountHandler) AccountViewHandler(w http.ResponseWriter, r *http.Requ
 
 	presenter := presenters.NewAccountPresenter(w)
 
-	err := ac.InputAccountBoundary.ExecuteViewAccountDetail(ctx, token, request, presenter)
+	err := ac.InputAccountBoundary.ExecuteViewAccountDetail(ctx, claims, request, presenter)
 	common.HandleErrorReturn(err)
 }
+
+func (ac *AccountHandler) DeleteAccountHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	presenter := presenters.NewAccountPresenter(w)
+
+	err := ac.InputAccountBoundary.ExecuteDeleteAccount(ctx, claims, presenter)
+	common.HandleEnvelopeError(err, w)
+}
+
+func (ac *AccountHandler) ExportAccountDataHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	presenter := presenters.NewAccountPresenter(w)
+
+	err := ac.InputAccountBoundary.ExecuteExportAccountData(ctx, claims, presenter)
+	common.HandleEnvelopeError(err, w)
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/account_imports"
+	"godating-dealls/internal/delivery/presenter"
+	"godating-dealls/internal/domain"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// maxImportBytes is enough for MaxImportRows rows with long bios
+const maxImportBytes = 32 << 20
+
+type AccountImportHandler struct {
+	InputAccountImportBoundary account_imports.InputAccountImportBoundary
+}
+
+func NewAccountImportHandler(inputAccountImportBoundary account_imports.InputAccountImportBoundary) *AccountImportHandler {
+	return &AccountImportHandler{InputAccountImportBoundary: inputAccountImportBoundary}
+}
+
+// ImportAccountsHandler takes the dump as the body, csv or json from the format query or else the Content-Type.
+// dry_run=true only checks the rows
+func (ah *AccountImportHandler) ImportAccountsHandler(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+		if strings.Contains(r.Header.Get("Content-Type"), "csv") {
+			format = "csv"
+		}
+	}
+
+	rows, err := account_imports.ReadAccountImport(r.Body, format)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		common.WriteEnvelopeError(w, http.StatusRequestEntityTooLarge, "import_too_large", "The import is larger than 32 MB, split the dump or use the import command")
+		return
+	}
+	if err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_import_file", err.Error())
+		return
+	}
+	if len(rows) > account_imports.MaxImportRows {
+		message := fmt.Sprintf("An import takes at most %d rows, split the dump or use the import command", account_imports.MaxImportRows)
+		common.WriteEnvelopeError(w, http.StatusRequestEntityTooLarge, "too_many_rows", message)
+		return
+	}
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+
+	progress := func(response domain.AccountImportResponse) {
+		log.Printf("Account import: %d of %d rows, %d created", response.Processed, response.Total, response.Created)
+	}
+	presenter := presenters.NewAccountImportPresenter(w)
+
+	err = ah.InputAccountImportBoundary.ExecuteImportAccounts(r.Context(), domain.AccountImportRequest{Rows: rows, DryRun: dryRun}, progress, presenter)
+	common.HandleEnvelopeError(err, w)
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/admin"
+	"godating-dealls/internal/delivery/presenter"
+	"godating-dealls/internal/domain"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type AdminHandler struct {
+	InputAdminBoundary admin.InputAdminBoundary
+}
+
+func NewAdminHandler(inputAdminBoundary admin.InputAdminBoundary) *AdminHandler {
+	return &AdminHandler{InputAdminBoundary: inputAdminBoundary}
+}
+
+func (ah *AdminHandler) AccountTimelineHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountId, err := strconv.ParseInt(r.PathValue("account_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid account id", http.StatusBadRequest)
+		return
+	}
+
+	// Paging is optional, the usecase applies defaults and limits
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+
+	presenter := presenters.NewAdminPresenter(w)
+
+	err = ah.InputAdminBoundary.ExecuteAccountTimeline(ctx, accountId, page, size, presenter)
+	common.HandleInternalServerError(err, w)
+}
+
+// ExportAccountTimelineHandler streams the whole timeline as NDJSON or CSV instead of a page of it
+func (ah *AdminHandler) ExportAccountTimelineHandler(w http.ResponseWriter, r *http.Request) {
+	accountId, err := strconv.ParseInt(r.PathValue("account_id"), 10, 64)
+	if err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_account_id", "Invalid account id")
+		return
+	}
+
+	export, err := common.NewExportWriter(w, r.URL.Query().Get("format"), fmt.Sprintf("account-%d-timeline", accountId), presenters.TimelineExportHeader)
+	if err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_export_format", err.Error())
+		return
+	}
+
+	presenter := presenters.NewAdminExportPresenter(w, export)
+
+	err = ah.InputAdminBoundary.ExecuteExportAccountTimeline(r.Context(), accountId, presenter)
+	if err != nil {
+		export.Abort(err)
+		return
+	}
+	_ = export.Close()
+}
+
+func (ah *AdminHandler) RectifyUserHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountId, err := strconv.ParseInt(r.PathValue("account_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid account id", http.StatusBadRequest)
+		return
+	}
+
+	var request domain.RectificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	// The client certificate identity is audited instead of the actor the body claims
+	if identity, ok := ctx.Value("admin_identity").(string); ok {
+		request.Actor = identity
+	}
+
+	presenter := presenters.NewAdminPresenter(w)
+
+	err = ah.InputAdminBoundary.ExecuteRectifyUser(ctx, accountId, request, presenter)
+	common.HandleInternalServerError(err, w)
+}
+
+func (ah *AdminHandler) QuotaUsageMetricsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Range is optional, dates are inclusive and formatted as YYYY-MM-DD
+	var from, to *time.Time
+	for param, target := range map[string]**time.Time{"from": &from, "to": &to} {
+		value := r.URL.Query().Get(param)
+		if value == "" {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			http.Error(w, "Invalid "+param+" date", http.StatusBadRequest)
+			return
+		}
+		*target = &date
+	}
+
+	presenter := presenters.NewAdminPresenter(w)
+
+	err := ah.InputAdminBoundary.ExecuteQuotaUsageMetrics(ctx, from, to, presenter)
+	common.HandleInternalServerError(err, w)
+}
+
+func (ah *AdminHandler) ListJobsHandler(w http.ResponseWriter, r *http.Request) {
+	presenter := presenters.NewAdminPresenter(w)
+
+	err := ah.InputAdminBoundary.ExecuteListJobs(r.Context(), presenter)
+	common.HandleInternalServerError(err, w)
+}
+
+func (ah *AdminHandler) GeoRestrictionMetricsHandler(w http.ResponseWriter, r *http.Request) {
+	presenter := presenters.NewAdminPresenter(w)
+
+	err := ah.InputAdminBoundary.ExecuteGeoRestrictionMetrics(r.Context(), presenter)
+	common.HandleEnvelopeError(err, w)
+}
+
+func (ah *AdminHandler) RuntimeMetricsHandler(w http.ResponseWriter, r *http.Request) {
+	presenter := presenters.NewAdminPresenter(w)
+
+	err := ah.InputAdminBoundary.ExecuteRuntimeMetrics(r.Context(), presenter)
+	common.HandleEnvelopeError(err, w)
+}
+
+func (ah *AdminHandler) TriggerJobHandler(w http.ResponseWriter, r *http.Request) {
+	presenter := presenters.NewAdminPresenter(w)
+
+	err := ah.InputAdminBoundary.ExecuteTriggerJob(r.Context(), r.PathValue("name"), presenter)
+	common.HandleInternalServerError(err, w)
+}
+
+func (ah *AdminHandler) PauseJobHandler(w http.ResponseWriter, r *http.Request) {
+	presenter := presenters.NewAdminPresenter(w)
+
+	err := ah.InputAdminBoundary.ExecutePauseJob(r.Context(), r.PathValue("name"), presenter)
+	common.HandleInternalServerError(err, w)
+}
+
+func (ah *AdminHandler) ResumeJobHandler(w http.ResponseWriter, r *http.Request) {
+	presenter := presenters.NewAdminPresenter(w)
+
+	err := ah.InputAdminBoundary.ExecuteResumeJob(r.Context(), r.PathValue("name"), presenter)
+	common.HandleInternalServerError(err, w)
+}
+
+func (ah *AdminHandler) FetchLogLevelsHandler(w http.ResponseWriter, r *http.Request) {
+	presenter := presenters.NewAdminPresenter(w)
+
+	err := ah.InputAdminBoundary.ExecuteFetchLogLevels(r.Context(), presenter)
+	common.HandleEnvelopeError(err, w)
+}
+
+func (ah *AdminHandler) SetLogLevelHandler(w http.ResponseWriter, r *http.Request) {
+	var request domain.LogLevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_request", "Invalid request payload")
+		return
+	}
+
+	presenter := presenters.NewAdminPresenter(w)
+
+	err := ah.InputAdminBoundary.ExecuteSetLogLevel(r.Context(), r.PathValue("module"), request, presenter)
+	if err != nil {
+		handleLogLevelError(w, err)
+	}
+}
+
+func handleLogLevelError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, common.ErrUnknownLogModule):
+		common.WriteEnvelopeError(w, http.StatusNotFound, "not_found", "Unknown log module, use one of "+strings.Join(common.LogModules(), ", "))
+	case errors.Is(err, common.ErrInvalidLogLevel):
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_log_level", err.Error())
+	default:
+		common.HandleEnvelopeError(err, w)
+	}
+}
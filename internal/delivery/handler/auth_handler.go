@@ -2,11 +2,14 @@ package handler
 
 import (
 	"encoding/json"
-	"fmt"
+	"errors"
 	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/entities/accounts"
 	input "godating-dealls/internal/core/usecase/auths"
 	"godating-dealls/internal/delivery/presenter"
 	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"godating-dealls/internal/infra/oauth"
 	"log"
 	"net/http"
 )
@@ -24,7 +27,7 @@ func NewAuthHandler(usecase input.InputAuthBoundary) *AuthHandler {
 func (ah *AuthHandler) RegisterUserHandler(w http.ResponseWriter, r *http.Request) {
 	var request domain.RegisterRequest
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
 		return
 	}
 	common.PrintJSON("Handler | Register Request", request)
@@ -35,16 +38,27 @@ func (ah *AuthHandler) RegisterUserHandler(w http.ResponseWriter, r *http.Reques
 
 	// Call the use case method passing the presenter
 	err := ah.usecase.ExecuteRegisterUsecase(ctx, request, presenter)
-	common.HandleInternalServerError(err, w)
+	switch {
+	case errors.Is(err, accounts.ErrEmailDomainNotAllowed):
+		common.WriteEnvelopeError(w, http.StatusUnprocessableEntity, "email_domain_not_allowed", "Sign up with this email domain is not allowed")
+	case errors.Is(err, input.ErrRegistrationFailed):
+		// Anti-enumeration mode, the answer must not tell whether the account exists
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "registration_failed", err.Error())
+	case errors.Is(err, accounts.ErrAccountExists):
+		common.WriteEnvelopeError(w, http.StatusConflict, "account_exists", err.Error())
+	default:
+		common.HandleEnvelopeError(err, w)
+	}
 }
 
 func (ah *AuthHandler) LoginUserHandler(w http.ResponseWriter, r *http.Request) {
 	var request domain.LoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
 		return
 	}
 	log.Println(request)
+	request.ClientAddress = common.TrustedClientAddress(r)
 
 	ctx := r.Context()
 
@@ -53,21 +67,243 @@ func (ah *AuthHandler) LoginUserHandler(w http.ResponseWriter, r *http.Request)
 
 	// Call the use case method passing the presenter
 	err := ah.usecase.ExecuteLoginUsecase(ctx, request, presenter)
-	common.HandleInternalServerError(err, w)
+	if errors.Is(err, input.ErrLoginThrottled) {
+		common.WriteEnvelopeError(w, http.StatusTooManyRequests, "too_many_attempts", err.Error())
+		return
+	}
+	if errors.Is(err, accounts.ErrPasswordResetRequired) {
+		common.WriteEnvelopeError(w, http.StatusForbidden, "password_reset_required", err.Error())
+		return
+	}
+	if errors.Is(err, input.ErrInvalidCredentials) {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_credentials", err.Error())
+		return
+	}
+	common.HandleEnvelopeError(err, w)
 }
 
 func (ah *AuthHandler) LogoutUserHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	token, ok := ctx.Value("token").(string)
-	if !ok || token == "" {
-		http.Error(w, "Invalid token", http.StatusUnauthorized)
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
 		return
 	}
 
 	presenter := presenters.NewAuthPresenter(w)
-	fmt.Println(token)
 
 	// Call the use case method passing the presenter
-	err := ah.usecase.ExecuteLogoutUsecase(ctx, &token, presenter)
-	common.HandleInternalServerError(err, w)
+	err := ah.usecase.ExecuteLogoutUsecase(ctx, claims, presenter)
+	common.HandleEnvelopeError(err, w)
+}
+
+func (ah *AuthHandler) RefreshTokenHandler(w http.ResponseWriter, r *http.Request) {
+	var request domain.RefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.RefreshToken == "" {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
+		return
+	}
+
+	presenter := presenters.NewAuthPresenter(w)
+
+	err := ah.usecase.ExecuteRefreshTokenUsecase(r.Context(), request, presenter)
+	if errors.Is(err, input.ErrInvalidRefreshToken) {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_refresh_token", err.Error())
+		return
+	}
+	common.HandleEnvelopeError(err, w)
+}
+
+func (ah *AuthHandler) ForgotPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	var request domain.ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Email == "" {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Email is required")
+		return
+	}
+
+	presenter := presenters.NewAuthPresenter(w)
+
+	err := ah.usecase.ExecuteForgotPassword(r.Context(), request, presenter)
+	common.HandleEnvelopeError(err, w)
+}
+
+func (ah *AuthHandler) ResetPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	var request domain.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Token == "" {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Token and new password are required")
+		return
+	}
+
+	presenter := presenters.NewAuthPresenter(w)
+
+	err := ah.usecase.ExecuteResetPassword(r.Context(), request, presenter)
+	if errors.Is(err, input.ErrInvalidPasswordResetToken) {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_reset_token", err.Error())
+		return
+	}
+	common.HandleEnvelopeError(err, w)
+}
+
+func (ah *AuthHandler) IssueScopedTokenHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	var request domain.ScopedTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
+		return
+	}
+
+	presenter := presenters.NewAuthPresenter(w)
+
+	err := ah.usecase.ExecuteIssueScopedToken(ctx, claims, request, presenter)
+	if errors.Is(err, input.ErrInvalidScope) {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_scope", err.Error())
+		return
+	}
+	common.HandleEnvelopeError(err, w)
+}
+
+// IntrospectTokenHandler takes the token form encoded as in RFC 7662, token_type_hint is accepted and ignored
+func (ah *AuthHandler) IntrospectTokenHandler(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("token") == "" {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_request", "Missing token")
+		return
+	}
+
+	presenter := presenters.NewAuthPresenter(w)
+
+	err := ah.usecase.ExecuteIntrospectToken(r.Context(), r.PostForm.Get("token"), presenter)
+	common.HandleEnvelopeError(err, w)
+}
+
+func (ah *AuthHandler) LoginThrottleMetricsHandler(w http.ResponseWriter, r *http.Request) {
+	presenter := presenters.NewAuthPresenter(w)
+
+	err := ah.usecase.ExecuteLoginThrottleMetrics(r.Context(), presenter)
+	common.HandleEnvelopeError(err, w)
+}
+
+func (ah *AuthHandler) PasskeyRegisterOptionsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	presenter := presenters.NewAuthPresenter(w)
+
+	err := ah.usecase.ExecutePasskeyRegisterOptions(ctx, claims, presenter)
+	common.HandleEnvelopeError(err, w)
+}
+
+func (ah *AuthHandler) PasskeyRegisterHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	var request domain.PasskeyRegisterRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
+		return
+	}
+
+	presenter := presenters.NewAuthPresenter(w)
+
+	err := ah.usecase.ExecutePasskeyRegister(ctx, claims, request, presenter)
+	common.HandleEnvelopeError(err, w)
+}
+
+func (ah *AuthHandler) PasskeyLoginOptionsHandler(w http.ResponseWriter, r *http.Request) {
+	presenter := presenters.NewAuthPresenter(w)
+
+	err := ah.usecase.ExecutePasskeyLoginOptions(r.Context(), presenter)
+	common.HandleEnvelopeError(err, w)
+}
+
+func (ah *AuthHandler) PasskeyLoginHandler(w http.ResponseWriter, r *http.Request) {
+	var request domain.PasskeyLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
+		return
+	}
+
+	presenter := presenters.NewAuthPresenter(w)
+
+	err := ah.usecase.ExecutePasskeyLogin(r.Context(), request, presenter)
+	if errors.Is(err, input.ErrInvalidCredentials) {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_credentials", err.Error())
+		return
+	}
+	common.HandleEnvelopeError(err, w)
+}
+
+func (ah *AuthHandler) OAuthLoginHandler(w http.ResponseWriter, r *http.Request) {
+	var request domain.OAuthLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
+		return
+	}
+
+	presenter := presenters.NewAuthPresenter(w)
+
+	err := ah.usecase.ExecuteOAuthLogin(r.Context(), r.PathValue("provider"), request, presenter)
+	switch {
+	case errors.Is(err, input.ErrOAuthProviderUnknown):
+		common.WriteEnvelopeError(w, http.StatusNotFound, "unknown_provider", err.Error())
+	case errors.Is(err, oauth.ErrInvalidIDToken):
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_id_token", "Invalid id token")
+	case errors.Is(err, input.ErrOAuthEmailRequired):
+		common.WriteEnvelopeError(w, http.StatusUnprocessableEntity, "email_required", err.Error())
+	case errors.Is(err, accounts.ErrProviderAlreadyLinked):
+		common.WriteEnvelopeError(w, http.StatusConflict, "provider_already_linked", err.Error())
+	case errors.Is(err, accounts.ErrEmailDomainNotAllowed):
+		common.WriteEnvelopeError(w, http.StatusUnprocessableEntity, "email_domain_not_allowed", "Sign up with this email domain is not allowed")
+	default:
+		common.HandleEnvelopeError(err, w)
+	}
+}
+
+func (ah *AuthHandler) FetchPasskeysHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	presenter := presenters.NewAuthPresenter(w)
+
+	err := ah.usecase.ExecuteFetchPasskeys(ctx, claims, presenter)
+	common.HandleEnvelopeError(err, w)
+}
+
+func (ah *AuthHandler) DeletePasskeyHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	presenter := presenters.NewAuthPresenter(w)
+
+	err := ah.usecase.ExecuteDeletePasskey(ctx, claims, r.PathValue("credential_id"), presenter)
+	common.HandleEnvelopeError(err, w)
+}
+
+// RegionLookupHandler login is optional, without one the region nearest to the client is returned
+func (ah *AuthHandler) RegionLookupHandler(w http.ResponseWriter, r *http.Request) {
+	presenter := presenters.NewAuthPresenter(w)
+
+	err := ah.usecase.ExecuteRegionLookup(r.Context(), r.URL.Query().Get("login"), common.TrustedClientAddress(r), presenter)
+	common.HandleEnvelopeError(err, w)
 }
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"godating-dealls/internal/common"
+	blocksentity "godating-dealls/internal/core/entities/blocks"
+	"godating-dealls/internal/core/usecase/blocks"
+	"godating-dealls/internal/delivery/presenter"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"net/http"
+	"strconv"
+)
+
+type BlockHandler struct {
+	InputBlockBoundary blocks.InputBlockBoundary
+}
+
+func NewBlockHandler(inputBlockBoundary blocks.InputBlockBoundary) *BlockHandler {
+	return &BlockHandler{InputBlockBoundary: inputBlockBoundary}
+}
+
+func (bh *BlockHandler) BlockAccountHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	blockedAccountId, err := strconv.ParseInt(r.PathValue("account_id"), 10, 64)
+	if err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_account_id", "Invalid account id")
+		return
+	}
+
+	presenter := presenters.NewBlockPresenter(w)
+
+	err = bh.InputBlockBoundary.ExecuteBlockAccount(ctx, claims, blockedAccountId, presenter)
+	handleBlockError(err, w)
+}
+
+func (bh *BlockHandler) FetchBlockedAccountsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	presenter := presenters.NewBlockPresenter(w)
+
+	err := bh.InputBlockBoundary.ExecuteFetchBlockedAccounts(ctx, claims, presenter)
+	handleBlockError(err, w)
+}
+
+func (bh *BlockHandler) UnblockAccountHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	blockedAccountId, err := strconv.ParseInt(r.PathValue("account_id"), 10, 64)
+	if err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_account_id", "Invalid account id")
+		return
+	}
+
+	presenter := presenters.NewBlockPresenter(w)
+
+	err = bh.InputBlockBoundary.ExecuteUnblockAccount(ctx, claims, blockedAccountId, presenter)
+	handleBlockError(err, w)
+}
+
+func (bh *BlockHandler) ReportAccountHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	reportedAccountId, err := strconv.ParseInt(r.PathValue("account_id"), 10, 64)
+	if err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_account_id", "Invalid account id")
+		return
+	}
+
+	var request domain.ReportAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
+		return
+	}
+
+	presenter := presenters.NewBlockPresenter(w)
+
+	err = bh.InputBlockBoundary.ExecuteReportAccount(ctx, claims, reportedAccountId, request, presenter)
+	handleBlockError(err, w)
+}
+
+// FetchReportsHandler is an admin route, account_id narrows the reports to the reported account
+func (bh *BlockHandler) FetchReportsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var reportedAccountId int64
+	if value := r.URL.Query().Get("account_id"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_account_id", "Invalid account id")
+			return
+		}
+		reportedAccountId = parsed
+	}
+
+	// Paging is optional, the usecase applies defaults and limits
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+
+	presenter := presenters.NewBlockPresenter(w)
+
+	err := bh.InputBlockBoundary.ExecuteFetchReports(ctx, reportedAccountId, page, size, presenter)
+	handleBlockError(err, w)
+}
+
+// ExportReportsHandler is an admin route streaming every report as NDJSON or CSV, account_id narrows it like the listing
+func (bh *BlockHandler) ExportReportsHandler(w http.ResponseWriter, r *http.Request) {
+	var reportedAccountId int64
+	if value := r.URL.Query().Get("account_id"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_account_id", "Invalid account id")
+			return
+		}
+		reportedAccountId = parsed
+	}
+
+	export, err := common.NewExportWriter(w, r.URL.Query().Get("format"), "reports", presenters.ReportExportHeader)
+	if err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_export_format", err.Error())
+		return
+	}
+
+	presenter := presenters.NewBlockExportPresenter(w, export)
+
+	err = bh.InputBlockBoundary.ExecuteExportReports(r.Context(), reportedAccountId, presenter)
+	if err != nil {
+		export.Abort(err)
+		return
+	}
+	_ = export.Close()
+}
+
+func handleBlockError(err error, w http.ResponseWriter) {
+	switch {
+	case errors.Is(err, blocksentity.ErrInvalidBlock):
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_block", err.Error())
+	case errors.Is(err, blocksentity.ErrInvalidReport):
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_report", err.Error())
+	case errors.Is(err, blocksentity.ErrBlockNotFound):
+		common.WriteEnvelopeError(w, http.StatusNotFound, "not_found", err.Error())
+	default:
+		common.HandleEnvelopeError(err, w)
+	}
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"errors"
+	"godating-dealls/internal/common"
+	candidatesentity "godating-dealls/internal/core/entities/candidates"
+	"godating-dealls/internal/core/usecase/candidates"
+	"godating-dealls/internal/delivery/presenter"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type CandidateHandler struct {
+	InputCandidateBoundary candidates.InputCandidateBoundary
+}
+
+func NewCandidateHandler(inputCandidateBoundary candidates.InputCandidateBoundary) *CandidateHandler {
+	return &CandidateHandler{InputCandidateBoundary: inputCandidateBoundary}
+}
+
+// FetchCandidatesHandler every filter is optional, gender is a comma separated list and the ages default to 18 to 100
+func (ch *CandidateHandler) FetchCandidatesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	query := r.URL.Query()
+	filter := domain.CandidateFilterDto{MinAge: 18, MaxAge: 100}
+	for name, target := range map[string]*int{"min_age": &filter.MinAge, "max_age": &filter.MaxAge, "max_distance_km": &filter.MaxDistanceKm} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_candidate_filter", "Invalid "+name)
+			return
+		}
+		*target = parsed
+	}
+	if value := query.Get("gender"); value != "" {
+		filter.Genders = strings.Split(value, ",")
+	}
+
+	// Paging is optional, the usecase applies defaults and limits
+	page, _ := strconv.Atoi(query.Get("page"))
+	size, _ := strconv.Atoi(query.Get("size"))
+
+	presenter := presenters.NewCandidatePresenter(w)
+
+	err := ch.InputCandidateBoundary.ExecuteFetchCandidates(ctx, claims, filter, page, size, presenter)
+	handleCandidateError(err, w)
+}
+
+func handleCandidateError(err error, w http.ResponseWriter) {
+	switch {
+	case errors.Is(err, candidatesentity.ErrInvalidCandidateFilter):
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_candidate_filter", err.Error())
+	default:
+		common.HandleEnvelopeError(err, w)
+	}
+}
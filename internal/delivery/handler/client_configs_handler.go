@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"godating-dealls/internal/common"
+	clientconfigsentity "godating-dealls/internal/core/entities/client_configs"
+	"godating-dealls/internal/core/usecase/client_configs"
+	"godating-dealls/internal/delivery/presenter"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"net/http"
+	"strconv"
+)
+
+type ClientConfigHandler struct {
+	InputClientConfigBoundary client_configs.InputClientConfigBoundary
+}
+
+func NewClientConfigHandler(inputClientConfigBoundary client_configs.InputClientConfigBoundary) *ClientConfigHandler {
+	return &ClientConfigHandler{InputClientConfigBoundary: inputClientConfigBoundary}
+}
+
+func (ch *ClientConfigHandler) FetchClientConfigHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	appVersion := r.Header.Get("X-App-Version")
+	if appVersion == "" {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "missing_app_version", "X-App-Version header is required")
+		return
+	}
+
+	presenter := presenters.NewClientConfigPresenter(w, r.Header.Get("If-None-Match"))
+
+	err := ch.InputClientConfigBoundary.ExecuteFetchClientConfig(ctx, claims, appVersion, presenter)
+	handleClientConfigError(err, w)
+}
+
+func (ch *ClientConfigHandler) PublishClientConfigHandler(w http.ResponseWriter, r *http.Request) {
+	var request domain.ClientConfigBundleRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
+		return
+	}
+
+	presenter := presenters.NewClientConfigPresenter(w, "")
+
+	err := ch.InputClientConfigBoundary.ExecutePublishClientConfig(r.Context(), request, presenter)
+	handleClientConfigError(err, w)
+}
+
+func (ch *ClientConfigHandler) FetchClientConfigBundlesHandler(w http.ResponseWriter, r *http.Request) {
+	presenter := presenters.NewClientConfigPresenter(w, "")
+
+	err := ch.InputClientConfigBoundary.ExecuteFetchClientConfigBundles(r.Context(), presenter)
+	handleClientConfigError(err, w)
+}
+
+func (ch *ClientConfigHandler) UpdateClientConfigRolloutHandler(w http.ResponseWriter, r *http.Request) {
+	bundleId, err := strconv.ParseInt(r.PathValue("bundle_id"), 10, 64)
+	if err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_bundle_id", "Invalid bundle id")
+		return
+	}
+
+	var request domain.ClientConfigRolloutRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
+		return
+	}
+
+	presenter := presenters.NewClientConfigPresenter(w, "")
+
+	err = ch.InputClientConfigBoundary.ExecuteUpdateClientConfigRollout(r.Context(), bundleId, request, presenter)
+	handleClientConfigError(err, w)
+}
+
+func handleClientConfigError(err error, w http.ResponseWriter) {
+	switch {
+	case errors.Is(err, clientconfigsentity.ErrInvalidClientConfig):
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_client_config", err.Error())
+	case errors.Is(err, clientconfigsentity.ErrClientConfigNotFound):
+		common.WriteEnvelopeError(w, http.StatusNotFound, "not_found", err.Error())
+	case errors.Is(err, clientconfigsentity.ErrClientConfigConflict):
+		common.WriteEnvelopeError(w, http.StatusConflict, "conflict", err.Error())
+	default:
+		common.HandleEnvelopeError(err, w)
+	}
+}
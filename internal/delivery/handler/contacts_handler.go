@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"encoding/json"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/contacts"
+	"godating-dealls/internal/delivery/presenter"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"net/http"
+)
+
+type ContactHandler struct {
+	InputContactBoundary contacts.InputContactBoundary
+}
+
+func NewContactHandler(inputContactBoundary contacts.InputContactBoundary) *ContactHandler {
+	return &ContactHandler{InputContactBoundary: inputContactBoundary}
+}
+
+func (ch *ContactHandler) UploadContactsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	var request domain.ContactHashesRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	presenter := presenters.NewContactPresenter(w)
+
+	err := ch.InputContactBoundary.ExecuteUploadContactHashes(ctx, claims, request, presenter)
+	common.HandleInternalServerError(err, w)
+}
+
+func (ch *ContactHandler) FetchContactsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	presenter := presenters.NewContactPresenter(w)
+
+	err := ch.InputContactBoundary.ExecuteFetchContactHashes(ctx, claims, presenter)
+	common.HandleInternalServerError(err, w)
+}
+
+func (ch *ContactHandler) RemoveContactsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	presenter := presenters.NewContactPresenter(w)
+
+	// Empty path value on DELETE /contacts clears the whole list
+	err := ch.InputContactBoundary.ExecuteRemoveContactHashes(ctx, claims, r.PathValue("hash"), presenter)
+	common.HandleInternalServerError(err, w)
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"godating-dealls/internal/common"
+	duosentity "godating-dealls/internal/core/entities/duos"
+	"godating-dealls/internal/core/usecase/duos"
+	"godating-dealls/internal/delivery/presenter"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"net/http"
+	"strconv"
+)
+
+type DuoHandler struct {
+	InputDuoBoundary duos.InputDuoBoundary
+}
+
+func NewDuoHandler(inputDuoBoundary duos.InputDuoBoundary) *DuoHandler {
+	return &DuoHandler{InputDuoBoundary: inputDuoBoundary}
+}
+
+func (dh *DuoHandler) InviteDuoHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	var request domain.DuoInviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
+		return
+	}
+
+	presenter := presenters.NewDuoPresenter(w)
+
+	err := dh.InputDuoBoundary.ExecuteInviteDuo(ctx, claims, request, presenter)
+	handleDuoError(err, w)
+}
+
+func (dh *DuoHandler) AcceptDuoHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	duoId, err := strconv.ParseInt(r.PathValue("duo_id"), 10, 64)
+	if err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_duo_id", "Invalid duo id")
+		return
+	}
+
+	presenter := presenters.NewDuoPresenter(w)
+
+	err = dh.InputDuoBoundary.ExecuteAcceptDuo(ctx, claims, duoId, presenter)
+	handleDuoError(err, w)
+}
+
+func (dh *DuoHandler) FetchDuoHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	presenter := presenters.NewDuoPresenter(w)
+
+	err := dh.InputDuoBoundary.ExecuteFetchDuo(ctx, claims, presenter)
+	handleDuoError(err, w)
+}
+
+func (dh *DuoHandler) UnlinkDuoHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	presenter := presenters.NewDuoPresenter(w)
+
+	err := dh.InputDuoBoundary.ExecuteUnlinkDuo(ctx, claims, presenter)
+	handleDuoError(err, w)
+}
+
+func (dh *DuoHandler) FetchDuoCandidatesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	presenter := presenters.NewDuoPresenter(w)
+
+	err := dh.InputDuoBoundary.ExecuteFetchDuoCandidates(ctx, claims, presenter)
+	handleDuoError(err, w)
+}
+
+func (dh *DuoHandler) SwipeDuoHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	var request domain.DuoSwipeRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
+		return
+	}
+
+	presenter := presenters.NewDuoPresenter(w)
+
+	err := dh.InputDuoBoundary.ExecuteSwipeDuo(ctx, claims, request, presenter)
+	handleDuoError(err, w)
+}
+
+func (dh *DuoHandler) FetchDuoMatchesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	presenter := presenters.NewDuoPresenter(w)
+
+	err := dh.InputDuoBoundary.ExecuteFetchDuoMatches(ctx, claims, presenter)
+	handleDuoError(err, w)
+}
+
+func handleDuoError(err error, w http.ResponseWriter) {
+	switch {
+	case errors.Is(err, duosentity.ErrInvalidDuo):
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_duo", err.Error())
+	case errors.Is(err, duosentity.ErrDuoNotFound):
+		common.WriteEnvelopeError(w, http.StatusNotFound, "not_found", err.Error())
+	case errors.Is(err, duosentity.ErrAlreadyInDuo):
+		common.WriteEnvelopeError(w, http.StatusConflict, "already_in_duo", err.Error())
+	case errors.Is(err, duosentity.ErrDuoNotActive):
+		common.WriteEnvelopeError(w, http.StatusConflict, "duo_not_active", err.Error())
+	default:
+		common.HandleEnvelopeError(err, w)
+	}
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"godating-dealls/internal/common"
+	blocksentity "godating-dealls/internal/core/entities/blocks"
+	eventsentity "godating-dealls/internal/core/entities/events"
+	"godating-dealls/internal/core/entities/matches"
+	"godating-dealls/internal/core/usecase/events"
+	"godating-dealls/internal/delivery/presenter"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"net/http"
+	"strconv"
+)
+
+type EventHandler struct {
+	InputEventBoundary events.InputEventBoundary
+}
+
+func NewEventHandler(inputEventBoundary events.InputEventBoundary) *EventHandler {
+	return &EventHandler{InputEventBoundary: inputEventBoundary}
+}
+
+// CreateEventHandler is an admin route
+func (eh *EventHandler) CreateEventHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var request domain.EventRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
+		return
+	}
+
+	presenter := presenters.NewEventPresenter(w)
+
+	err := eh.InputEventBoundary.ExecuteCreateEvent(ctx, request, presenter)
+	handleEventError(err, w)
+}
+
+func (eh *EventHandler) FetchEventsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	presenter := presenters.NewEventPresenter(w)
+
+	err := eh.InputEventBoundary.ExecuteFetchEvents(ctx, claims, presenter)
+	handleEventError(err, w)
+}
+
+func (eh *EventHandler) JoinEventHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	eventId, err := strconv.ParseInt(r.PathValue("event_id"), 10, 64)
+	if err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_event_id", "Invalid event id")
+		return
+	}
+
+	presenter := presenters.NewEventPresenter(w)
+
+	err = eh.InputEventBoundary.ExecuteJoinEvent(ctx, claims, eventId, presenter)
+	handleEventError(err, w)
+}
+
+func (eh *EventHandler) ConnectAtEventHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	eventId, err := strconv.ParseInt(r.PathValue("event_id"), 10, 64)
+	if err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_event_id", "Invalid event id")
+		return
+	}
+
+	var request domain.EventConnectRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
+		return
+	}
+
+	presenter := presenters.NewEventPresenter(w)
+
+	err = eh.InputEventBoundary.ExecuteConnectAtEvent(ctx, claims, eventId, request, presenter)
+	handleEventError(err, w)
+}
+
+func handleEventError(err error, w http.ResponseWriter) {
+	switch {
+	case errors.Is(err, eventsentity.ErrInvalidEvent):
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_event", err.Error())
+	case errors.Is(err, eventsentity.ErrEventNotFound):
+		common.WriteEnvelopeError(w, http.StatusNotFound, "not_found", err.Error())
+	case errors.Is(err, eventsentity.ErrEventNotLive):
+		common.WriteEnvelopeError(w, http.StatusConflict, "event_not_live", err.Error())
+	case errors.Is(err, eventsentity.ErrNotEventGuest):
+		common.WriteEnvelopeError(w, http.StatusForbidden, "not_event_guest", err.Error())
+	case errors.Is(err, matches.ErrGroupFull):
+		common.WriteEnvelopeError(w, http.StatusConflict, "chat_full", err.Error())
+	case errors.Is(err, blocksentity.ErrBlocked):
+		common.WriteEnvelopeError(w, http.StatusForbidden, "blocked", err.Error())
+	default:
+		common.HandleEnvelopeError(err, w)
+	}
+}
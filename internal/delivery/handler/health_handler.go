@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/health"
+	"godating-dealls/internal/delivery/presenter"
+	"net/http"
+)
+
+type HealthHandler struct {
+	InputHealthBoundary health.InputHealthBoundary
+}
+
+func NewHealthHandler(inputHealthBoundary health.InputHealthBoundary) *HealthHandler {
+	return &HealthHandler{InputHealthBoundary: inputHealthBoundary}
+}
+
+func (hh *HealthHandler) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	presenter := presenters.NewHealthPresenter(w)
+
+	err := hh.InputHealthBoundary.ExecuteReadiness(r.Context(), presenter)
+	if err != nil {
+		// The schema could not be checked, e.g. the database is down, the instance is not ready either
+		common.WriteEnvelopeError(w, http.StatusServiceUnavailable, "not_ready", err.Error())
+	}
+}
+
+func (hh *HealthHandler) ServiceStatusHandler(w http.ResponseWriter, r *http.Request) {
+	presenter := presenters.NewHealthPresenter(w)
+
+	err := hh.InputHealthBoundary.ExecuteServiceStatus(r.Context(), presenter)
+	common.HandleEnvelopeError(err, w)
+}
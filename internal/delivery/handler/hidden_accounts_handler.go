@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"godating-dealls/internal/common"
+	hiddenaccountsentity "godating-dealls/internal/core/entities/hidden_accounts"
+	"godating-dealls/internal/core/usecase/hidden_accounts"
+	"godating-dealls/internal/delivery/presenter"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"net/http"
+	"strconv"
+)
+
+type HiddenAccountHandler struct {
+	InputHiddenAccountBoundary hidden_accounts.InputHiddenAccountBoundary
+}
+
+func NewHiddenAccountHandler(inputHiddenAccountBoundary hidden_accounts.InputHiddenAccountBoundary) *HiddenAccountHandler {
+	return &HiddenAccountHandler{InputHiddenAccountBoundary: inputHiddenAccountBoundary}
+}
+
+func (hh *HiddenAccountHandler) HideAccountHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	var request domain.HideAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
+		return
+	}
+
+	presenter := presenters.NewHiddenAccountPresenter(w)
+
+	err := hh.InputHiddenAccountBoundary.ExecuteHideAccount(ctx, claims, request, presenter)
+	handleHiddenAccountError(err, w)
+}
+
+func (hh *HiddenAccountHandler) FetchHiddenAccountsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	presenter := presenters.NewHiddenAccountPresenter(w)
+
+	err := hh.InputHiddenAccountBoundary.ExecuteFetchHiddenAccounts(ctx, claims, presenter)
+	handleHiddenAccountError(err, w)
+}
+
+func (hh *HiddenAccountHandler) UnhideAccountHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	hiddenAccountId, err := strconv.ParseInt(r.PathValue("account_id"), 10, 64)
+	if err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_account_id", "Invalid account id")
+		return
+	}
+
+	presenter := presenters.NewHiddenAccountPresenter(w)
+
+	err = hh.InputHiddenAccountBoundary.ExecuteUnhideAccount(ctx, claims, hiddenAccountId, presenter)
+	handleHiddenAccountError(err, w)
+}
+
+func handleHiddenAccountError(err error, w http.ResponseWriter) {
+	switch {
+	case errors.Is(err, hiddenaccountsentity.ErrInvalidHiddenAccount):
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_hidden_account", err.Error())
+	case errors.Is(err, hiddenaccountsentity.ErrHiddenAccountNotFound):
+		common.WriteEnvelopeError(w, http.StatusNotFound, "not_found", err.Error())
+	case errors.Is(err, hiddenaccountsentity.ErrHiddenAccountsLimitFull):
+		common.WriteEnvelopeError(w, http.StatusConflict, "hidden_accounts_limit_reached", err.Error())
+	default:
+		common.HandleEnvelopeError(err, w)
+	}
+}
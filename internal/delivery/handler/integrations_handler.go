@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/integrations"
+	"godating-dealls/internal/delivery/presenter"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"net/http"
+)
+
+type IntegrationHandler struct {
+	InputIntegrationBoundary integrations.InputIntegrationBoundary
+}
+
+func NewIntegrationHandler(inputIntegrationBoundary integrations.InputIntegrationBoundary) *IntegrationHandler {
+	return &IntegrationHandler{InputIntegrationBoundary: inputIntegrationBoundary}
+}
+
+func (ih *IntegrationHandler) ConnectIntegrationHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	presenter := presenters.NewIntegrationPresenter(w)
+
+	err := ih.InputIntegrationBoundary.ExecuteConnectIntegration(ctx, claims, r.PathValue("provider"), presenter)
+	common.HandleInternalServerError(err, w)
+}
+
+// IntegrationCallbackHandler is the OAuth redirect target, the account is resolved from the state instead of a token
+func (ih *IntegrationHandler) IntegrationCallbackHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	if errParam := query.Get("error"); errParam != "" {
+		http.Error(w, "Authorization denied", http.StatusBadRequest)
+		return
+	}
+
+	code := query.Get("code")
+	if code == "" {
+		http.Error(w, "Missing authorization code", http.StatusBadRequest)
+		return
+	}
+
+	presenter := presenters.NewIntegrationPresenter(w)
+
+	err := ih.InputIntegrationBoundary.ExecuteIntegrationCallback(ctx, r.PathValue("provider"), code, query.Get("state"), presenter)
+	common.HandleInternalServerError(err, w)
+}
+
+func (ih *IntegrationHandler) FetchImportedContentHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	presenter := presenters.NewIntegrationPresenter(w)
+
+	err := ih.InputIntegrationBoundary.ExecuteFetchImportedContent(ctx, claims, presenter)
+	common.HandleInternalServerError(err, w)
+}
+
+func (ih *IntegrationHandler) DisconnectIntegrationHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	presenter := presenters.NewIntegrationPresenter(w)
+
+	err := ih.InputIntegrationBoundary.ExecuteDisconnectIntegration(ctx, claims, r.PathValue("provider"), presenter)
+	common.HandleInternalServerError(err, w)
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"errors"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/likes"
+	"godating-dealls/internal/delivery/presenter"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"net/http"
+	"strconv"
+)
+
+type LikesHandler struct {
+	InputLikesBoundary likes.InputLikesBoundary
+}
+
+func NewLikesHandler(inputLikesBoundary likes.InputLikesBoundary) *LikesHandler {
+	return &LikesHandler{InputLikesBoundary: inputLikesBoundary}
+}
+
+func (lh *LikesHandler) FetchLikesReceivedHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	// Paging is optional, the usecase applies defaults and limits
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+
+	presenter := presenters.NewLikesPresenter(w)
+
+	err := lh.InputLikesBoundary.ExecuteFetchLikesReceived(ctx, claims, page, size, presenter)
+	switch {
+	case errors.Is(err, likes.ErrLikesReceivedPremium):
+		common.WriteEnvelopeError(w, http.StatusForbidden, "premium_required", err.Error())
+	default:
+		common.HandleEnvelopeError(err, w)
+	}
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"godating-dealls/internal/common"
+	matchfeaturesentity "godating-dealls/internal/core/entities/match_features"
+	"godating-dealls/internal/core/usecase/match_features"
+	"godating-dealls/internal/delivery/presenter"
+	"godating-dealls/internal/domain"
+	"net/http"
+)
+
+type MatchFeatureHandler struct {
+	InputMatchFeatureBoundary match_features.InputMatchFeatureBoundary
+}
+
+func NewMatchFeatureHandler(inputMatchFeatureBoundary match_features.InputMatchFeatureBoundary) *MatchFeatureHandler {
+	return &MatchFeatureHandler{InputMatchFeatureBoundary: inputMatchFeatureBoundary}
+}
+
+func (mh *MatchFeatureHandler) SaveMatchScoresHandler(w http.ResponseWriter, r *http.Request) {
+	var request domain.MatchScoresRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
+		return
+	}
+
+	presenter := presenters.NewMatchFeaturePresenter(w)
+
+	err := mh.InputMatchFeatureBoundary.ExecuteSaveMatchScores(r.Context(), request, presenter)
+	if errors.Is(err, matchfeaturesentity.ErrInvalidMatchScores) {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_scores", err.Error())
+		return
+	}
+	common.HandleEnvelopeError(err, w)
+}
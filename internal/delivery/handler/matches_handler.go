@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"errors"
+	"godating-dealls/internal/common"
+	matchesentity "godating-dealls/internal/core/entities/matches"
+	"godating-dealls/internal/core/usecase/matches"
+	"godating-dealls/internal/delivery/presenter"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"net/http"
+	"strconv"
+)
+
+type MatchHandler struct {
+	InputMatchBoundary matches.InputMatchBoundary
+}
+
+func NewMatchHandler(inputMatchBoundary matches.InputMatchBoundary) *MatchHandler {
+	return &MatchHandler{InputMatchBoundary: inputMatchBoundary}
+}
+
+func (mh *MatchHandler) FetchMatchesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	presenter := presenters.NewMatchPresenter(w)
+
+	err := mh.InputMatchBoundary.ExecuteFetchMatches(ctx, claims, presenter)
+	common.HandleEnvelopeError(err, w)
+}
+
+func (mh *MatchHandler) UnmatchHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	matchId, err := strconv.ParseInt(r.PathValue("match_id"), 10, 64)
+	if err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_match_id", "Invalid match id")
+		return
+	}
+
+	presenter := presenters.NewMatchPresenter(w)
+
+	err = mh.InputMatchBoundary.ExecuteUnmatch(ctx, claims, matchId, presenter)
+	handleUnmatchError(err, w)
+}
+
+func handleUnmatchError(err error, w http.ResponseWriter) {
+	switch {
+	case errors.Is(err, matchesentity.ErrNotMatched):
+		common.WriteEnvelopeError(w, http.StatusForbidden, "not_matched", err.Error())
+	case errors.Is(err, matchesentity.ErrUnmatchGroup):
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_chat", err.Error())
+	default:
+		common.HandleEnvelopeError(err, w)
+	}
+}
@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"godating-dealls/internal/common"
+	blocksentity "godating-dealls/internal/core/entities/blocks"
+	"godating-dealls/internal/core/entities/matches"
+	messagesentity "godating-dealls/internal/core/entities/messages"
+	safetyentity "godating-dealls/internal/core/entities/safety"
+	"godating-dealls/internal/core/usecase/messages"
+	"godating-dealls/internal/delivery/presenter"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"golang.org/x/net/websocket"
+	"net/http"
+	"strconv"
+)
+
+type MessageHandler struct {
+	InputMessageBoundary messages.InputMessageBoundary
+}
+
+func NewMessageHandler(inputMessageBoundary messages.InputMessageBoundary) *MessageHandler {
+	return &MessageHandler{InputMessageBoundary: inputMessageBoundary}
+}
+
+func (mh *MessageHandler) SendMessageHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	matchId, err := strconv.ParseInt(r.PathValue("match_id"), 10, 64)
+	if err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_match_id", "Invalid match id")
+		return
+	}
+
+	var request domain.ChatMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
+		return
+	}
+
+	presenter := presenters.NewMessagePresenter(w)
+
+	err = mh.InputMessageBoundary.ExecuteSendMessage(ctx, claims, matchId, request, presenter)
+	if err != nil {
+		status, code := messageErrorStatus(err)
+		common.WriteEnvelopeError(w, status, code, err.Error())
+	}
+}
+
+func (mh *MessageHandler) FetchMessagesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	matchId, err := strconv.ParseInt(r.PathValue("match_id"), 10, 64)
+	if err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_match_id", "Invalid match id")
+		return
+	}
+
+	// Paging is optional, before_id is the oldest message id the app already has
+	var beforeId int64
+	size := 0
+	if value := r.URL.Query().Get("before_id"); value != "" {
+		if beforeId, err = strconv.ParseInt(value, 10, 64); err != nil {
+			common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_before_id", "Invalid before id")
+			return
+		}
+	}
+	if value := r.URL.Query().Get("size"); value != "" {
+		if size, err = strconv.Atoi(value); err != nil {
+			common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_size", "Invalid size")
+			return
+		}
+	}
+
+	presenter := presenters.NewMessagePresenter(w)
+
+	err = mh.InputMessageBoundary.ExecuteFetchMessages(ctx, claims, matchId, beforeId, size, presenter)
+	if err != nil {
+		status, code := messageErrorStatus(err)
+		common.WriteEnvelopeError(w, status, code, err.Error())
+	}
+}
+
+func (mh *MessageHandler) FetchParticipantsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	matchId, err := strconv.ParseInt(r.PathValue("match_id"), 10, 64)
+	if err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_match_id", "Invalid match id")
+		return
+	}
+
+	presenter := presenters.NewMessagePresenter(w)
+
+	err = mh.InputMessageBoundary.ExecuteFetchParticipants(ctx, claims, matchId, presenter)
+	if err != nil {
+		status, code := messageErrorStatus(err)
+		common.WriteEnvelopeError(w, status, code, err.Error())
+	}
+}
+
+func (mh *MessageHandler) MarkReadHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	matchId, err := strconv.ParseInt(r.PathValue("match_id"), 10, 64)
+	if err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_match_id", "Invalid match id")
+		return
+	}
+
+	var request domain.ChatReadRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.MessageID < 0 {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
+		return
+	}
+
+	presenter := presenters.NewMessagePresenter(w)
+
+	err = mh.InputMessageBoundary.ExecuteMarkRead(ctx, claims, matchId, request, presenter)
+	if err != nil {
+		status, code := messageErrorStatus(err)
+		common.WriteEnvelopeError(w, status, code, err.Error())
+	}
+}
+
+func (mh *MessageHandler) LeaveChatHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	matchId, err := strconv.ParseInt(r.PathValue("match_id"), 10, 64)
+	if err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_match_id", "Invalid match id")
+		return
+	}
+
+	presenter := presenters.NewMessagePresenter(w)
+
+	err = mh.InputMessageBoundary.ExecuteLeaveChat(ctx, claims, matchId, presenter)
+	if err != nil {
+		status, code := messageErrorStatus(err)
+		common.WriteEnvelopeError(w, status, code, err.Error())
+	}
+}
+
+// ReceiveReplicatedMessageHandler is called by the other regions with the messages they stored, see regions.Replicator
+func (mh *MessageHandler) ReceiveReplicatedMessageHandler(w http.ResponseWriter, r *http.Request) {
+	var request domain.ReplicatedMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
+		return
+	}
+
+	presenter := presenters.NewMessagePresenter(w)
+
+	err := mh.InputMessageBoundary.ExecuteDeliverReplicatedMessage(r.Context(), request, presenter)
+	if errors.Is(err, messages.ErrInvalidReplicatedMessage) {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", err.Error())
+		return
+	}
+	common.HandleEnvelopeError(err, w)
+}
+
+// ChatSocketHandler upgrades to a websocket that pushes every message of the user's matches as it is sent.
+// The app sends {"match_id": 5, "body": "Hi"} frames on it and receives a sent, message or error frame back
+func (mh *MessageHandler) ChatSocketHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := jsonwebtoken.ClaimsFromContext(r.Context())
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	// Authentication is the bearer token, not a cookie, so a cross origin page cannot ride on a user's session
+	server := websocket.Server{Handler: func(conn *websocket.Conn) {
+		mh.serveChatSocket(conn, claims)
+	}}
+	server.ServeHTTP(w, r)
+}
+
+func (mh *MessageHandler) serveChatSocket(conn *websocket.Conn, claims *jsonwebtoken.JWTTokenClaims) {
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(conn.Request().Context())
+	defer cancel()
+
+	presenter := presenters.NewChatSocketPresenter(conn)
+
+	// Frames from the app are read on their own goroutine, the connection ends when the app closes it
+	go func() {
+		defer cancel()
+		for {
+			var request domain.ChatSocketRequest
+			if err := websocket.JSON.Receive(conn, &request); err != nil {
+				return
+			}
+			err := mh.InputMessageBoundary.ExecuteSendMessage(ctx, claims, request.MatchID, domain.ChatMessageRequest{Body: request.Body}, presenter)
+			if err != nil {
+				_, code := messageErrorStatus(err)
+				presenter.ErrorResponse(code, err.Error())
+			}
+		}
+	}()
+
+	err := mh.InputMessageBoundary.ExecuteSubscribeMessages(ctx, claims, presenter)
+	if err != nil {
+		presenter.ErrorResponse("invalid_token", err.Error())
+	}
+}
+
+func messageErrorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, messagesentity.ErrInvalidMessage):
+		return http.StatusBadRequest, "invalid_message"
+	case errors.Is(err, matches.ErrNotMatched):
+		return http.StatusForbidden, "not_matched"
+	case errors.Is(err, blocksentity.ErrBlocked):
+		return http.StatusForbidden, "blocked"
+	case errors.Is(err, safetyentity.ErrMessageFiltered):
+		return http.StatusUnprocessableEntity, "message_filtered"
+	case errors.Is(err, safetyentity.ErrMediaLocked):
+		return http.StatusForbidden, "media_locked"
+	case errors.Is(err, matches.ErrLeavePairMatch), errors.Is(err, matches.ErrInvalidGroup):
+		return http.StatusBadRequest, "invalid_chat"
+	case errors.Is(err, matches.ErrGroupFull):
+		return http.StatusConflict, "chat_full"
+	case errors.Is(err, matches.ErrChatEnded):
+		return http.StatusGone, "chat_ended"
+	default:
+		return http.StatusInternalServerError, "internal_error"
+	}
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"godating-dealls/internal/common"
+	networkrulesentity "godating-dealls/internal/core/entities/network_rules"
+	"godating-dealls/internal/core/usecase/network_rules"
+	"godating-dealls/internal/delivery/presenter"
+	"godating-dealls/internal/domain"
+	"net/http"
+	"strconv"
+)
+
+type NetworkRuleHandler struct {
+	InputNetworkRuleBoundary network_rules.InputNetworkRuleBoundary
+}
+
+func NewNetworkRuleHandler(inputNetworkRuleBoundary network_rules.InputNetworkRuleBoundary) *NetworkRuleHandler {
+	return &NetworkRuleHandler{InputNetworkRuleBoundary: inputNetworkRuleBoundary}
+}
+
+func (nh *NetworkRuleHandler) FetchNetworkRulesHandler(w http.ResponseWriter, r *http.Request) {
+	presenter := presenters.NewNetworkRulePresenter(w)
+
+	err := nh.InputNetworkRuleBoundary.ExecuteFetchNetworkRules(r.Context(), presenter)
+	common.HandleEnvelopeError(err, w)
+}
+
+func (nh *NetworkRuleHandler) CreateNetworkRuleHandler(w http.ResponseWriter, r *http.Request) {
+	var request domain.NetworkRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
+		return
+	}
+
+	presenter := presenters.NewNetworkRulePresenter(w)
+
+	err := nh.InputNetworkRuleBoundary.ExecuteCreateNetworkRule(r.Context(), common.TrustedClientAddress(r), request, presenter)
+	handleNetworkRuleError(err, w)
+}
+
+func (nh *NetworkRuleHandler) DeleteNetworkRuleHandler(w http.ResponseWriter, r *http.Request) {
+	ruleId, err := strconv.ParseInt(r.PathValue("rule_id"), 10, 64)
+	if err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_rule_id", "Invalid rule id")
+		return
+	}
+
+	presenter := presenters.NewNetworkRulePresenter(w)
+
+	err = nh.InputNetworkRuleBoundary.ExecuteDeleteNetworkRule(r.Context(), common.TrustedClientAddress(r), ruleId, presenter)
+	handleNetworkRuleError(err, w)
+}
+
+func handleNetworkRuleError(err error, w http.ResponseWriter) {
+	switch {
+	case errors.Is(err, networkrulesentity.ErrInvalidNetworkRule):
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_network_rule", err.Error())
+	case errors.Is(err, networkrulesentity.ErrNetworkRuleNotFound):
+		common.WriteEnvelopeError(w, http.StatusNotFound, "not_found", err.Error())
+	case errors.Is(err, networkrulesentity.ErrNetworkRuleExists), errors.Is(err, networkrulesentity.ErrNetworkRuleLockout):
+		common.WriteEnvelopeError(w, http.StatusConflict, "conflict", err.Error())
+	default:
+		common.HandleEnvelopeError(err, w)
+	}
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"encoding/json"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/notes"
+	"godating-dealls/internal/delivery/presenter"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"net/http"
+	"strconv"
+)
+
+type NoteHandler struct {
+	InputNoteBoundary notes.InputNoteBoundary
+}
+
+func NewNoteHandler(inputNoteBoundary notes.InputNoteBoundary) *NoteHandler {
+	return &NoteHandler{InputNoteBoundary: inputNoteBoundary}
+}
+
+func (nh *NoteHandler) CreateNoteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	var request domain.NoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	presenter := presenters.NewNotePresenter(w)
+
+	err := nh.InputNoteBoundary.ExecuteCreateNote(ctx, claims, request, presenter)
+	common.HandleInternalServerError(err, w)
+}
+
+func (nh *NoteHandler) FetchNotesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	// Optional filter to the notes about one profile
+	var targetAccountId int64
+	if param := r.URL.Query().Get("account_id"); param != "" {
+		id, err := strconv.ParseInt(param, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid account id", http.StatusBadRequest)
+			return
+		}
+		targetAccountId = id
+	}
+
+	presenter := presenters.NewNotePresenter(w)
+
+	err := nh.InputNoteBoundary.ExecuteFetchNotes(ctx, claims, targetAccountId, presenter)
+	common.HandleInternalServerError(err, w)
+}
+
+func (nh *NoteHandler) UpdateNoteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	noteId, err := strconv.ParseInt(r.PathValue("note_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid note id", http.StatusBadRequest)
+		return
+	}
+
+	var request domain.NoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	presenter := presenters.NewNotePresenter(w)
+
+	err = nh.InputNoteBoundary.ExecuteUpdateNote(ctx, claims, noteId, request, presenter)
+	common.HandleInternalServerError(err, w)
+}
+
+func (nh *NoteHandler) DeleteNoteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	noteId, err := strconv.ParseInt(r.PathValue("note_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid note id", http.StatusBadRequest)
+		return
+	}
+
+	presenter := presenters.NewNotePresenter(w)
+
+	err = nh.InputNoteBoundary.ExecuteDeleteNote(ctx, claims, noteId, presenter)
+	common.HandleInternalServerError(err, w)
+}
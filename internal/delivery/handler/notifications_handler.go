@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"godating-dealls/internal/common"
+	notificationsentity "godating-dealls/internal/core/entities/notifications"
+	"godating-dealls/internal/core/usecase/notifications"
+	"godating-dealls/internal/delivery/presenter"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"net/http"
+	"strconv"
+)
+
+type NotificationHandler struct {
+	InputNotificationBoundary notifications.InputNotificationBoundary
+}
+
+func NewNotificationHandler(inputNotificationBoundary notifications.InputNotificationBoundary) *NotificationHandler {
+	return &NotificationHandler{InputNotificationBoundary: inputNotificationBoundary}
+}
+
+func (nh *NotificationHandler) RegisterDeviceHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	var request domain.RegisterDeviceRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
+		return
+	}
+
+	presenter := presenters.NewNotificationPresenter(w)
+
+	err := nh.InputNotificationBoundary.ExecuteRegisterDevice(ctx, claims, request, presenter)
+	switch {
+	case errors.Is(err, notificationsentity.ErrInvalidDevice):
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_device", err.Error())
+	default:
+		common.HandleEnvelopeError(err, w)
+	}
+}
+
+func (nh *NotificationHandler) FetchNotificationsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	// Paging is optional, the usecase applies defaults and limits
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+	unreadOnly, _ := strconv.ParseBool(r.URL.Query().Get("unread"))
+
+	presenter := presenters.NewNotificationPresenter(w)
+
+	err := nh.InputNotificationBoundary.ExecuteFetchNotifications(ctx, claims, unreadOnly, page, size, presenter)
+	common.HandleEnvelopeError(err, w)
+}
+
+func (nh *NotificationHandler) MarkNotificationsReadHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	var request domain.MarkNotificationsReadRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
+		return
+	}
+
+	presenter := presenters.NewNotificationPresenter(w)
+
+	err := nh.InputNotificationBoundary.ExecuteMarkNotificationsRead(ctx, claims, request, presenter)
+	switch {
+	case errors.Is(err, notificationsentity.ErrInvalidNotificationIds):
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_notification_ids", err.Error())
+	default:
+		common.HandleEnvelopeError(err, w)
+	}
+}
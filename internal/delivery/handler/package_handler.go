@@ -2,10 +2,13 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"godating-dealls/internal/common"
+	packagesentity "godating-dealls/internal/core/entities/packages"
 	"godating-dealls/internal/core/usecase/packages"
 	"godating-dealls/internal/delivery/presenter"
 	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
 	"net/http"
 )
 
@@ -20,8 +23,8 @@ func NewPackageHandler(inputPackageBoundary packages.InputPackageBoundary) *Pack
 func (ph *PackageHandler) GetPackageHandler(w http.ResponseWriter, r *http.Request) {
 	// If user premium is unlimited, if not is just 10 data
 	ctx := r.Context()
-	token, ok := ctx.Value("token").(string)
-	if !ok || token == "" {
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
 		http.Error(w, "Invalid token", http.StatusUnauthorized)
 		return
 	}
@@ -30,7 +33,7 @@ func (ph *PackageHandler) GetPackageHandler(w http.ResponseWriter, r *http.Reque
 	presenter := presenters.NewPackagePresenter(w)
 
 	// Call the use case method passing the presenter
-	err := ph.InputPackageBoundary.ExecuteGetAllPackages(ctx, token, presenter)
+	err := ph.InputPackageBoundary.ExecuteGetAllPackages(ctx, claims, presenter)
 	common.HandleInternalServerError(err, w)
 }
 
@@ -44,8 +47,8 @@ func (ph *PackageHandler) PurchasePackages(w http.ResponseWriter, r *http.Reques
 
 	// If user premium is unlimited, if not is just 10 data
 	ctx := r.Context()
-	token, ok := ctx.Value("token").(string)
-	if !ok || token == "" {
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
 		http.Error(w, "Invalid token", http.StatusUnauthorized)
 		return
 	}
@@ -54,6 +57,51 @@ func (ph *PackageHandler) PurchasePackages(w http.ResponseWriter, r *http.Reques
 	presenter := presenters.NewPackagePresenter(w)
 
 	// Call the use case method passing the presenter
-	err := ph.InputPackageBoundary.ExecutePurchasedPackages(ctx, token, request, presenter)
-	common.HandleInternalServerError(err, w)
+	err := ph.InputPackageBoundary.ExecutePurchasedPackages(ctx, claims, request, presenter)
+	handlePremiumError(err, w)
+}
+
+func (ph *PackageHandler) PurchasePremiumHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	var request domain.PremiumPurchaseRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
+		return
+	}
+
+	presenter := presenters.NewPackagePresenter(w)
+
+	err := ph.InputPackageBoundary.ExecutePurchasePremium(ctx, claims, request, presenter)
+	handlePremiumError(err, w)
+}
+
+func (ph *PackageHandler) PremiumStatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	presenter := presenters.NewPackagePresenter(w)
+
+	err := ph.InputPackageBoundary.ExecuteFindPremiumStatus(ctx, claims, presenter)
+	common.HandleEnvelopeError(err, w)
+}
+
+func handlePremiumError(err error, w http.ResponseWriter) {
+	switch {
+	case errors.Is(err, packagesentity.ErrPackageNotFound):
+		common.WriteEnvelopeError(w, http.StatusNotFound, "not_found", err.Error())
+	case errors.Is(err, packagesentity.ErrPackagePriceChanged):
+		common.WriteEnvelopeError(w, http.StatusConflict, "price_changed", err.Error())
+	default:
+		common.HandleEnvelopeError(err, w)
+	}
 }
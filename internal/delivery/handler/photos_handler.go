@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"godating-dealls/internal/common"
+	photosentity "godating-dealls/internal/core/entities/photos"
+	"godating-dealls/internal/core/usecase/photos"
+	"godating-dealls/internal/delivery/presenter"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+type PhotoHandler struct {
+	InputPhotoBoundary photos.InputPhotoBoundary
+}
+
+func NewPhotoHandler(inputPhotoBoundary photos.InputPhotoBoundary) *PhotoHandler {
+	return &PhotoHandler{InputPhotoBoundary: inputPhotoBoundary}
+}
+
+// UploadPhotoHandler expects a multipart form with the image in the photo field
+func (ph *PhotoHandler) UploadPhotoHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	// Room for the multipart headers on top of the largest accepted photo
+	r.Body = http.MaxBytesReader(w, r.Body, photosentity.MaxPhotoBytes+64<<10)
+	file, _, err := r.FormFile("photo")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			common.WriteEnvelopeError(w, http.StatusRequestEntityTooLarge, "photo_too_large", "Photo is larger than 5 MB")
+			return
+		}
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Missing photo file")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, photosentity.MaxPhotoBytes+1))
+	if err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Could not read photo file")
+		return
+	}
+	// The declared content type is not trusted, it is sniffed from the first bytes
+	upload := domain.PhotoUpload{ContentType: http.DetectContentType(data), Data: data}
+
+	presenter := presenters.NewPhotoPresenter(w)
+
+	err = ph.InputPhotoBoundary.ExecuteUploadPhoto(ctx, claims, upload, presenter)
+	handlePhotoError(err, w)
+}
+
+func (ph *PhotoHandler) FetchPhotosHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	presenter := presenters.NewPhotoPresenter(w)
+
+	err := ph.InputPhotoBoundary.ExecuteFetchPhotos(ctx, claims, presenter)
+	common.HandleEnvelopeError(err, w)
+}
+
+func (ph *PhotoHandler) ReorderPhotosHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	var request domain.ReorderPhotosRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
+		return
+	}
+
+	presenter := presenters.NewPhotoPresenter(w)
+
+	err := ph.InputPhotoBoundary.ExecuteReorderPhotos(ctx, claims, request, presenter)
+	handlePhotoError(err, w)
+}
+
+func (ph *PhotoHandler) SetPrimaryPhotoHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	photoId, err := strconv.ParseInt(r.PathValue("photo_id"), 10, 64)
+	if err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_photo_id", "Invalid photo id")
+		return
+	}
+
+	presenter := presenters.NewPhotoPresenter(w)
+
+	err = ph.InputPhotoBoundary.ExecuteSetPrimaryPhoto(ctx, claims, photoId, presenter)
+	handlePhotoError(err, w)
+}
+
+func (ph *PhotoHandler) DeletePhotoHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	photoId, err := strconv.ParseInt(r.PathValue("photo_id"), 10, 64)
+	if err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_photo_id", "Invalid photo id")
+		return
+	}
+
+	presenter := presenters.NewPhotoPresenter(w)
+
+	err = ph.InputPhotoBoundary.ExecuteDeletePhoto(ctx, claims, photoId, presenter)
+	handlePhotoError(err, w)
+}
+
+func (ph *PhotoHandler) FetchTrashedPhotosHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	presenter := presenters.NewPhotoPresenter(w)
+
+	err := ph.InputPhotoBoundary.ExecuteFetchTrashedPhotos(ctx, claims, presenter)
+	handlePhotoError(err, w)
+}
+
+func (ph *PhotoHandler) RestorePhotoHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	photoId, err := strconv.ParseInt(r.PathValue("photo_id"), 10, 64)
+	if err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_photo_id", "Invalid photo id")
+		return
+	}
+
+	presenter := presenters.NewPhotoPresenter(w)
+
+	err = ph.InputPhotoBoundary.ExecuteRestorePhoto(ctx, claims, photoId, presenter)
+	handlePhotoError(err, w)
+}
+
+func (ph *PhotoHandler) SetSmartPhotosHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	var request domain.SmartPhotosRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Enabled == nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Enabled is required")
+		return
+	}
+
+	presenter := presenters.NewPhotoPresenter(w)
+
+	err := ph.InputPhotoBoundary.ExecuteSetSmartPhotos(ctx, claims, request, presenter)
+	handlePhotoError(err, w)
+}
+
+func (ph *PhotoHandler) FetchSmartPhotosHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	presenter := presenters.NewPhotoPresenter(w)
+
+	err := ph.InputPhotoBoundary.ExecuteFetchSmartPhotos(ctx, claims, presenter)
+	handlePhotoError(err, w)
+}
+
+func handlePhotoError(err error, w http.ResponseWriter) {
+	switch {
+	case errors.Is(err, photosentity.ErrInvalidPhoto):
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_photo", err.Error())
+	case errors.Is(err, photosentity.ErrPhotoNotFound):
+		common.WriteEnvelopeError(w, http.StatusNotFound, "not_found", err.Error())
+	case errors.Is(err, photosentity.ErrPhotoLimitReached):
+		common.WriteEnvelopeError(w, http.StatusConflict, "photo_limit_reached", err.Error())
+	default:
+		common.HandleEnvelopeError(err, w)
+	}
+}
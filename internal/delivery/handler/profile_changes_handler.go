@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"godating-dealls/internal/common"
+	profilechangesentity "godating-dealls/internal/core/entities/profile_changes"
+	"godating-dealls/internal/core/usecase/profile_changes"
+	"godating-dealls/internal/delivery/presenter"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"net/http"
+	"strconv"
+)
+
+type ProfileChangeHandler struct {
+	InputProfileChangeBoundary profile_changes.InputProfileChangeBoundary
+}
+
+func NewProfileChangeHandler(inputProfileChangeBoundary profile_changes.InputProfileChangeBoundary) *ProfileChangeHandler {
+	return &ProfileChangeHandler{InputProfileChangeBoundary: inputProfileChangeBoundary}
+}
+
+func (ph *ProfileChangeHandler) SubmitProfileChangeHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	var request domain.ProfileChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
+		return
+	}
+
+	presenter := presenters.NewProfileChangePresenter(w)
+
+	err := ph.InputProfileChangeBoundary.ExecuteSubmitProfileChange(ctx, claims, request, presenter)
+	handleProfileChangeError(err, w)
+}
+
+func (ph *ProfileChangeHandler) FetchProfileChangesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	presenter := presenters.NewProfileChangePresenter(w)
+
+	err := ph.InputProfileChangeBoundary.ExecuteFetchProfileChanges(ctx, claims, presenter)
+	common.HandleEnvelopeError(err, w)
+}
+
+func (ph *ProfileChangeHandler) FetchPendingProfileChangesHandler(w http.ResponseWriter, r *http.Request) {
+	presenter := presenters.NewProfileChangePresenter(w)
+
+	err := ph.InputProfileChangeBoundary.ExecuteFetchPendingProfileChanges(r.Context(), presenter)
+	common.HandleEnvelopeError(err, w)
+}
+
+func (ph *ProfileChangeHandler) ReviewProfileChangeHandler(w http.ResponseWriter, r *http.Request) {
+	requestId, err := strconv.ParseInt(r.PathValue("request_id"), 10, 64)
+	if err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_request_id", "Invalid request id")
+		return
+	}
+
+	var request domain.ProfileChangeReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
+		return
+	}
+	// The client certificate identity is recorded as reviewer instead of the actor the body claims
+	if identity, ok := r.Context().Value("admin_identity").(string); ok {
+		request.Actor = identity
+	}
+
+	presenter := presenters.NewProfileChangePresenter(w)
+
+	err = ph.InputProfileChangeBoundary.ExecuteReviewProfileChange(r.Context(), requestId, request, presenter)
+	handleProfileChangeError(err, w)
+}
+
+func handleProfileChangeError(err error, w http.ResponseWriter) {
+	switch {
+	case errors.Is(err, profilechangesentity.ErrInvalidProfileChange):
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_profile_change", err.Error())
+	case errors.Is(err, profilechangesentity.ErrProfileChangeNotFound):
+		common.WriteEnvelopeError(w, http.StatusNotFound, "not_found", err.Error())
+	case errors.Is(err, profilechangesentity.ErrProfileChangePending), errors.Is(err, profilechangesentity.ErrProfileChangeDecided):
+		common.WriteEnvelopeError(w, http.StatusConflict, "conflict", err.Error())
+	default:
+		common.HandleEnvelopeError(err, w)
+	}
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/profile_strength"
+	"godating-dealls/internal/delivery/presenter"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"net/http"
+)
+
+type ProfileStrengthHandler struct {
+	InputProfileStrengthBoundary profile_strength.InputProfileStrengthBoundary
+}
+
+func NewProfileStrengthHandler(inputProfileStrengthBoundary profile_strength.InputProfileStrengthBoundary) *ProfileStrengthHandler {
+	return &ProfileStrengthHandler{InputProfileStrengthBoundary: inputProfileStrengthBoundary}
+}
+
+func (ph *ProfileStrengthHandler) ProfileStrengthHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	presenter := presenters.NewProfileStrengthPresenter(w)
+
+	err := ph.InputProfileStrengthBoundary.ExecuteProfileStrength(ctx, claims, presenter)
+	common.HandleInternalServerError(err, w)
+}
@@ -4,6 +4,7 @@ import (
 	"godating-dealls/internal/common"
 	"godating-dealls/internal/core/usecase/daily_quotas"
 	"godating-dealls/internal/delivery/presenter"
+	"godating-dealls/internal/infra/jsonwebtoken"
 	"net/http"
 )
 
@@ -18,8 +19,8 @@ func NewQuotaHandler(inputDailyQuotaBoundary daily_quotas.InputDailyQuotaBoundar
 func (q *QuotaHandler) CheckQuotaAccountHandler(w http.ResponseWriter, r *http.Request) {
 	// If user premium is unlimited, if not is just 10 data
 	ctx := r.Context()
-	token, ok := ctx.Value("token").(string)
-	if !ok || token == "" {
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
 		http.Error(w, "Invalid token", http.StatusUnauthorized)
 		return
 	}
@@ -28,6 +29,6 @@ func (q *QuotaHandler) CheckQuotaAccountHandler(w http.ResponseWriter, r *http.R
 	presenter := presenters.NewQuotaPresenter(w)
 
 	// Call the use case method passing the presenter
-	err := q.InputDailyQuotaBoundary.ExecuteFindDailyQuotaUsecase(ctx, token, presenter)
+	err := q.InputDailyQuotaBoundary.ExecuteFindDailyQuotaUsecase(ctx, claims, presenter)
 	common.HandleInternalServerError(err, w)
 }
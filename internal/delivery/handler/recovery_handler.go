@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"godating-dealls/internal/common"
+	recoveryentity "godating-dealls/internal/core/entities/recovery"
+	"godating-dealls/internal/core/usecase/recovery"
+	"godating-dealls/internal/delivery/presenter"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"net/http"
+	"strconv"
+)
+
+type RecoveryHandler struct {
+	InputRecoveryBoundary recovery.InputRecoveryBoundary
+}
+
+func NewRecoveryHandler(inputRecoveryBoundary recovery.InputRecoveryBoundary) *RecoveryHandler {
+	return &RecoveryHandler{InputRecoveryBoundary: inputRecoveryBoundary}
+}
+
+func (rh *RecoveryHandler) SaveRecoveryContactsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	var request domain.RecoveryContactsRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
+		return
+	}
+
+	presenter := presenters.NewRecoveryPresenter(w)
+
+	err := rh.InputRecoveryBoundary.ExecuteSaveRecoveryContacts(ctx, claims, request, presenter)
+	common.HandleEnvelopeError(err, w)
+}
+
+func (rh *RecoveryHandler) FetchRecoverySettingsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	presenter := presenters.NewRecoveryPresenter(w)
+
+	err := rh.InputRecoveryBoundary.ExecuteFetchRecoverySettings(ctx, claims, presenter)
+	common.HandleEnvelopeError(err, w)
+}
+
+func (rh *RecoveryHandler) CancelRecoveryHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	presenter := presenters.NewRecoveryPresenter(w)
+
+	err := rh.InputRecoveryBoundary.ExecuteCancelRecovery(ctx, claims, presenter)
+	common.HandleEnvelopeError(err, w)
+}
+
+func (rh *RecoveryHandler) StartRecoveryHandler(w http.ResponseWriter, r *http.Request) {
+	var request domain.StartRecoveryRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Email == "" || request.Username == "" {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Email and username are required")
+		return
+	}
+
+	presenter := presenters.NewRecoveryPresenter(w)
+
+	err := rh.InputRecoveryBoundary.ExecuteStartRecovery(r.Context(), request, presenter)
+	common.HandleEnvelopeError(err, w)
+}
+
+func (rh *RecoveryHandler) FetchRecoveryApprovalsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	presenter := presenters.NewRecoveryPresenter(w)
+
+	err := rh.InputRecoveryBoundary.ExecuteFetchRecoveryApprovals(ctx, claims, presenter)
+	common.HandleEnvelopeError(err, w)
+}
+
+func (rh *RecoveryHandler) ApproveRecoveryHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	requestId, err := strconv.ParseInt(r.PathValue("request_id"), 10, 64)
+	if err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_request_id", "Invalid request id")
+		return
+	}
+
+	presenter := presenters.NewRecoveryPresenter(w)
+
+	err = rh.InputRecoveryBoundary.ExecuteApproveRecovery(ctx, claims, requestId, presenter)
+	common.HandleEnvelopeError(err, w)
+}
+
+func (rh *RecoveryHandler) CompleteRecoveryHandler(w http.ResponseWriter, r *http.Request) {
+	var request domain.CompleteRecoveryRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
+		return
+	}
+
+	presenter := presenters.NewRecoveryPresenter(w)
+
+	err := rh.InputRecoveryBoundary.ExecuteCompleteRecovery(r.Context(), request, presenter)
+	if errors.Is(err, recoveryentity.ErrRecoveryRejected) {
+		common.WriteEnvelopeError(w, http.StatusForbidden, "recovery_rejected", err.Error())
+		return
+	}
+	common.HandleEnvelopeError(err, w)
+}
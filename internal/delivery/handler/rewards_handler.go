@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/rewards"
+	"godating-dealls/internal/delivery/presenter"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"net/http"
+)
+
+type RewardHandler struct {
+	InputRewardBoundary rewards.InputRewardBoundary
+}
+
+func NewRewardHandler(inputRewardBoundary rewards.InputRewardBoundary) *RewardHandler {
+	return &RewardHandler{InputRewardBoundary: inputRewardBoundary}
+}
+
+func (rh *RewardHandler) FetchLoginStreakHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	presenter := presenters.NewRewardPresenter(w)
+
+	err := rh.InputRewardBoundary.ExecuteFetchLoginStreak(ctx, claims, presenter)
+	common.HandleInternalServerError(err, w)
+}
+
+func (rh *RewardHandler) ClaimRewardHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	presenter := presenters.NewRewardPresenter(w)
+
+	err := rh.InputRewardBoundary.ExecuteClaimReward(ctx, claims, presenter)
+	common.HandleInternalServerError(err, w)
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/safety"
+	"godating-dealls/internal/delivery/presenter"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"net/http"
+)
+
+type SafetyHandler struct {
+	InputSafetyBoundary safety.InputSafetyBoundary
+}
+
+func NewSafetyHandler(inputSafetyBoundary safety.InputSafetyBoundary) *SafetyHandler {
+	return &SafetyHandler{InputSafetyBoundary: inputSafetyBoundary}
+}
+
+func (sh *SafetyHandler) FetchSafetySettingsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	presenter := presenters.NewSafetyPresenter(w)
+
+	err := sh.InputSafetyBoundary.ExecuteFetchSafetySettings(ctx, claims, presenter)
+	common.HandleEnvelopeError(err, w)
+}
+
+func (sh *SafetyHandler) UpdateSafetySettingsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	var request domain.SafetySettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
+		return
+	}
+
+	presenter := presenters.NewSafetyPresenter(w)
+
+	err := sh.InputSafetyBoundary.ExecuteUpdateSafetySettings(ctx, claims, request, presenter)
+	if errors.Is(err, safety.ErrInvalidSafetySettings) {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", err.Error())
+		return
+	}
+	common.HandleEnvelopeError(err, w)
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/share_links"
+	"godating-dealls/internal/delivery/presenter"
+	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"net/http"
+	"strconv"
+)
+
+type ShareLinkHandler struct {
+	InputShareLinkBoundary share_links.InputShareLinkBoundary
+}
+
+func NewShareLinkHandler(inputShareLinkBoundary share_links.InputShareLinkBoundary) *ShareLinkHandler {
+	return &ShareLinkHandler{InputShareLinkBoundary: inputShareLinkBoundary}
+}
+
+func (sh *ShareLinkHandler) CreateShareLinkHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	// Body is optional, an empty body uses the default expiry
+	var request domain.ShareLinkRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, "Invalid request payload", http.StatusBadRequest)
+			return
+		}
+	}
+
+	presenter := presenters.NewShareLinkPresenter(w)
+
+	err := sh.InputShareLinkBoundary.ExecuteCreateShareLink(ctx, claims, request, presenter)
+	common.HandleInternalServerError(err, w)
+}
+
+func (sh *ShareLinkHandler) FetchShareLinksHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	presenter := presenters.NewShareLinkPresenter(w)
+
+	err := sh.InputShareLinkBoundary.ExecuteFetchShareLinks(ctx, claims, presenter)
+	common.HandleInternalServerError(err, w)
+}
+
+func (sh *ShareLinkHandler) RevokeShareLinkHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	linkId, err := strconv.ParseInt(r.PathValue("link_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid link id", http.StatusBadRequest)
+		return
+	}
+
+	presenter := presenters.NewShareLinkPresenter(w)
+
+	err = sh.InputShareLinkBoundary.ExecuteRevokeShareLink(ctx, claims, linkId, presenter)
+	common.HandleInternalServerError(err, w)
+}
+
+func (sh *ShareLinkHandler) PublicProfileHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	shareToken := r.PathValue("share_token")
+	if shareToken == "" {
+		http.Error(w, "Invalid share link", http.StatusBadRequest)
+		return
+	}
+
+	presenter := presenters.NewShareLinkPresenter(w)
+
+	err := sh.InputShareLinkBoundary.ExecuteViewPublicProfile(ctx, shareToken, presenter)
+	common.HandleInternalServerError(err, w)
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"godating-dealls/internal/common"
+	statusmessagesentity "godating-dealls/internal/core/entities/status_messages"
+	"godating-dealls/internal/core/usecase/status_messages"
+	"godating-dealls/internal/delivery/presenter"
+	"godating-dealls/internal/domain"
+	"net/http"
+	"strconv"
+)
+
+type StatusMessageHandler struct {
+	InputStatusMessageBoundary status_messages.InputStatusMessageBoundary
+}
+
+func NewStatusMessageHandler(inputStatusMessageBoundary status_messages.InputStatusMessageBoundary) *StatusMessageHandler {
+	return &StatusMessageHandler{InputStatusMessageBoundary: inputStatusMessageBoundary}
+}
+
+// StatusHandler is polled by the apps, also before login, and answers from memory
+func (sh *StatusMessageHandler) StatusHandler(w http.ResponseWriter, r *http.Request) {
+	presenter := presenters.NewStatusMessagePresenter(w, r.Header.Get("If-None-Match"))
+
+	err := sh.InputStatusMessageBoundary.ExecuteFetchStatus(r.Context(), presenter)
+	common.HandleEnvelopeError(err, w)
+}
+
+func (sh *StatusMessageHandler) FetchStatusMessagesHandler(w http.ResponseWriter, r *http.Request) {
+	presenter := presenters.NewStatusMessagePresenter(w, "")
+
+	err := sh.InputStatusMessageBoundary.ExecuteFetchStatusMessages(r.Context(), presenter)
+	common.HandleEnvelopeError(err, w)
+}
+
+func (sh *StatusMessageHandler) CreateStatusMessageHandler(w http.ResponseWriter, r *http.Request) {
+	sh.saveStatusMessage(w, r, 0)
+}
+
+func (sh *StatusMessageHandler) UpdateStatusMessageHandler(w http.ResponseWriter, r *http.Request) {
+	messageId, err := strconv.ParseInt(r.PathValue("message_id"), 10, 64)
+	if err != nil || messageId <= 0 {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_message_id", "Invalid message id")
+		return
+	}
+	sh.saveStatusMessage(w, r, messageId)
+}
+
+func (sh *StatusMessageHandler) DeleteStatusMessageHandler(w http.ResponseWriter, r *http.Request) {
+	messageId, err := strconv.ParseInt(r.PathValue("message_id"), 10, 64)
+	if err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_message_id", "Invalid message id")
+		return
+	}
+
+	presenter := presenters.NewStatusMessagePresenter(w, "")
+
+	err = sh.InputStatusMessageBoundary.ExecuteDeleteStatusMessage(r.Context(), messageId, presenter)
+	handleStatusMessageError(err, w)
+}
+
+func (sh *StatusMessageHandler) saveStatusMessage(w http.ResponseWriter, r *http.Request, messageId int64) {
+	var request domain.StatusMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload, times are RFC 3339")
+		return
+	}
+
+	presenter := presenters.NewStatusMessagePresenter(w, "")
+
+	err := sh.InputStatusMessageBoundary.ExecuteSaveStatusMessage(r.Context(), messageId, request, presenter)
+	handleStatusMessageError(err, w)
+}
+
+func handleStatusMessageError(err error, w http.ResponseWriter) {
+	switch {
+	case errors.Is(err, statusmessagesentity.ErrInvalidStatusMessage):
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_status_message", err.Error())
+	case errors.Is(err, statusmessagesentity.ErrStatusMessageNotFound):
+		common.WriteEnvelopeError(w, http.StatusNotFound, "not_found", err.Error())
+	default:
+		common.HandleEnvelopeError(err, w)
+	}
+}
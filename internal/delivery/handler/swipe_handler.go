@@ -2,10 +2,15 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"godating-dealls/internal/common"
+	blocksentity "godating-dealls/internal/core/entities/blocks"
+	"godating-dealls/internal/core/entities/daily_quotas"
+	swipesentity "godating-dealls/internal/core/entities/swipes"
 	"godating-dealls/internal/core/usecase/swipes"
 	"godating-dealls/internal/delivery/presenter"
 	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
 	"net/http"
 )
 
@@ -19,8 +24,8 @@ func NewSwipeHandler(InputSwipeBoundary swipes.InputSwipeBoundary) *SwipeHandler
 
 func (sh *SwipeHandler) SwipeHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	token, ok := ctx.Value("token").(string)
-	if !ok || token == "" {
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
 		http.Error(w, "Invalid token", http.StatusUnauthorized)
 		return
 	}
@@ -35,6 +40,71 @@ func (sh *SwipeHandler) SwipeHandler(w http.ResponseWriter, r *http.Request) {
 	presenter := presenters.NewSwipePresenter(w)
 
 	// Call the use case method passing the presenter
-	err := sh.InputSwipeBoundary.ExecuteSwipes(ctx, token, request, presenter)
-	common.HandleInternalServerError(err, w)
+	err := sh.InputSwipeBoundary.ExecuteSwipes(ctx, claims, request, presenter)
+	switch {
+	case errors.Is(err, swipesentity.ErrInvalidSwipe):
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_swipe", err.Error())
+	case errors.Is(err, swipes.ErrSwipeTargetNotFound):
+		common.WriteEnvelopeError(w, http.StatusNotFound, "account_not_found", err.Error())
+	case errors.Is(err, blocksentity.ErrBlocked):
+		common.WriteEnvelopeError(w, http.StatusForbidden, "blocked", err.Error())
+	default:
+		common.HandleInternalServerError(err, w)
+	}
+}
+
+func (sh *SwipeHandler) UndoSwipeHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	presenter := presenters.NewSwipePresenter(w)
+
+	err := sh.InputSwipeBoundary.ExecuteUndoSwipe(ctx, claims, presenter)
+	switch {
+	case errors.Is(err, swipes.ErrSwipeUndoPremium):
+		common.WriteEnvelopeError(w, http.StatusForbidden, "premium_required", err.Error())
+	case errors.Is(err, swipesentity.ErrNothingToUndo):
+		common.WriteEnvelopeError(w, http.StatusNotFound, "nothing_to_undo", err.Error())
+	case errors.Is(err, swipesentity.ErrSwipeUndoMatch):
+		common.WriteEnvelopeError(w, http.StatusConflict, "swipe_matched", err.Error())
+	default:
+		common.HandleEnvelopeError(err, w)
+	}
+}
+
+func (sh *SwipeHandler) SuperLikeHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	var request domain.SuperLikeRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_request", "Invalid request payload")
+		return
+	}
+
+	presenter := presenters.NewSwipePresenter(w)
+
+	err := sh.InputSwipeBoundary.ExecuteSuperLike(ctx, claims, request, presenter)
+	switch {
+	case errors.Is(err, swipesentity.ErrInvalidSuperLike):
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_super_like", err.Error())
+	case errors.Is(err, swipes.ErrSuperLikeTargetNotFound):
+		common.WriteEnvelopeError(w, http.StatusNotFound, "account_not_found", err.Error())
+	case errors.Is(err, blocksentity.ErrBlocked):
+		common.WriteEnvelopeError(w, http.StatusForbidden, "blocked", err.Error())
+	case errors.Is(err, swipesentity.ErrAlreadySuperLiked):
+		common.WriteEnvelopeError(w, http.StatusConflict, "already_super_liked", err.Error())
+	case errors.Is(err, daily_quotas.ErrSuperLikeQuotaExhausted):
+		common.WriteEnvelopeError(w, http.StatusTooManyRequests, "super_like_quota_exhausted", err.Error())
+	default:
+		common.HandleEnvelopeError(err, w)
+	}
 }
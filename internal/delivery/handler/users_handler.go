@@ -2,10 +2,14 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"godating-dealls/internal/common"
+	profilechanges "godating-dealls/internal/core/entities/profile_changes"
+	usersentity "godating-dealls/internal/core/entities/users"
 	"godating-dealls/internal/core/usecase/users"
 	"godating-dealls/internal/delivery/presenter"
 	"godating-dealls/internal/domain"
+	"godating-dealls/internal/infra/jsonwebtoken"
 	"net/http"
 )
 
@@ -20,9 +24,9 @@ func NewUsersHandler(userInput users.InputUserBoundary) *UsersHandler {
 func (uh *UsersHandler) UserViewsHandler(w http.ResponseWriter, r *http.Request) {
 	// If user premium is unlimited, if not is just 10 data
 	ctx := r.Context()
-	token, ok := ctx.Value("token").(string)
-	if !ok || token == "" {
-		http.Error(w, "Invalid token", http.StatusUnauthorized)
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
 		return
 	}
 
@@ -30,27 +34,69 @@ func (uh *UsersHandler) UserViewsHandler(w http.ResponseWriter, r *http.Request)
 	presenter := presenters.NewUserPresenter(w)
 
 	// Call the use case method passing the presenter
-	err := uh.UserInput.ExecuteUserViewsUsecase(ctx, token, presenter)
-	common.HandleInternalServerError(err, w)
+	err := uh.UserInput.ExecuteUserViewsUsecase(ctx, claims, presenter)
+	common.HandleEnvelopeError(err, w)
 }
 
 func (uh *UsersHandler) UpdateUserHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	token, ok := ctx.Value("token").(string)
-	if !ok || token == "" {
-		http.Error(w, "Invalid token", http.StatusUnauthorized)
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
 		return
 	}
 
 	var request domain.PatchUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
 		return
 	}
 
 	presenter := presenters.NewUserPresenter(w)
 
 	// Call the use case method passing the presenter
-	err := uh.UserInput.ExecutePatchUserUsecase(ctx, token, request, presenter)
-	common.HandleInternalServerError(err, w)
+	err := uh.UserInput.ExecutePatchUserUsecase(ctx, claims, request, presenter)
+	if errors.Is(err, profilechanges.ErrProfileFieldLocked) {
+		common.WriteEnvelopeError(w, http.StatusConflict, "field_locked", err.Error())
+		return
+	}
+	common.HandleEnvelopeError(err, w)
+}
+
+func (uh *UsersHandler) UpdateLocationHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	var request domain.UserLocationRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_payload", "Invalid request payload")
+		return
+	}
+
+	presenter := presenters.NewUserPresenter(w)
+
+	err := uh.UserInput.ExecuteUpdateLocationUsecase(ctx, claims, request, presenter)
+	if errors.Is(err, usersentity.ErrInvalidLocation) {
+		common.WriteEnvelopeError(w, http.StatusBadRequest, "invalid_location", err.Error())
+		return
+	}
+	common.HandleEnvelopeError(err, w)
+}
+
+func (uh *UsersHandler) ClearLocationHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := jsonwebtoken.ClaimsFromContext(ctx)
+	if !ok {
+		common.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+		return
+	}
+
+	presenter := presenters.NewUserPresenter(w)
+
+	err := uh.UserInput.ExecuteClearLocationUsecase(ctx, claims, presenter)
+	common.HandleEnvelopeError(err, w)
 }
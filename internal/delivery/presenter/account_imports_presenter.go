@@ -0,0 +1,26 @@
+package presenters
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/account_imports"
+	"godating-dealls/internal/domain"
+	"net/http"
+)
+
+type AccountImportPresenter struct {
+	w http.ResponseWriter
+}
+
+func NewAccountImportPresenter(w http.ResponseWriter) account_imports.OutputAccountImportBoundary {
+	return &AccountImportPresenter{w: w}
+}
+
+// AccountImportResponse answers 200 even with rejected rows, they are listed with the reason
+func (ap *AccountImportPresenter) AccountImportResponse(response domain.AccountImportResponse, err error) {
+	common.HandleEnvelopeError(err, ap.w)
+	if response.DryRun {
+		common.WriteEnvelope(ap.w, http.StatusOK, "Import checked, nothing was created", response, nil)
+		return
+	}
+	common.WriteEnvelope(ap.w, http.StatusOK, "Accounts imported", response, nil)
+}
@@ -1,6 +1,7 @@
 package presenters
 
 import (
+	"fmt"
 	"godating-dealls/internal/common"
 	"godating-dealls/internal/core/usecase/accounts"
 	"godating-dealls/internal/domain"
@@ -25,3 +26,15 @@ func (a AccountPresenter) ViewAccountResponse(response domain.ViewedAccountRespo
 	common.HandleInternalServerError(err, a.w)
 	common.WriteJSONResponse(a.w, http.StatusOK, "View account successfully", response, 1)
 }
+
+func (a AccountPresenter) DeleteAccountResponse(response domain.DeleteAccountResponse, err error) {
+	common.HandleEnvelopeError(err, a.w)
+	common.WriteEnvelope(a.w, http.StatusOK, "Account deleted, log in before purge_after to restore it", response, nil)
+}
+
+// AccountExportResponse is a download of the archive itself, without the envelope
+func (a AccountPresenter) AccountExportResponse(response domain.AccountExportResponse, err error) {
+	common.HandleEnvelopeError(err, a.w)
+	a.w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="godating-export-%d.json"`, response.AccountID))
+	common.WriteJSON(a.w, http.StatusOK, response)
+}
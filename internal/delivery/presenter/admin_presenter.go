@@ -0,0 +1,72 @@
+package presenters
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/admin"
+	"godating-dealls/internal/domain"
+	"net/http"
+)
+
+type AdminPresenter struct {
+	w      http.ResponseWriter
+	export *common.ExportWriter
+}
+
+func NewAdminPresenter(w http.ResponseWriter) admin.OutputAdminBoundary {
+	return &AdminPresenter{w: w}
+}
+
+func NewAdminExportPresenter(w http.ResponseWriter, export *common.ExportWriter) admin.OutputAdminBoundary {
+	return &AdminPresenter{w: w, export: export}
+}
+
+func (a AdminPresenter) AccountTimelineResponse(response domain.AccountTimelineResponse, err error) {
+	common.HandleInternalServerError(err, a.w)
+	common.WriteJSONResponse(a.w, http.StatusOK, "Get account timeline successfully", response, response.Total)
+}
+
+// TimelineExportHeader is the CSV header of TimelineExportRow
+var TimelineExportHeader = []string{"event_type", "occurred_at", "detail"}
+
+func (a AdminPresenter) TimelineExportRow(response domain.TimelineEventResponse) error {
+	return a.export.WriteRow(response, []string{response.EventType, response.OccurredAt, response.Detail})
+}
+
+func (a AdminPresenter) RectificationResponse(response domain.RectificationResponse, err error) {
+	common.HandleInternalServerError(err, a.w)
+	common.WriteJSONResponse(a.w, http.StatusOK, "Correct user data successfully", response, int64(len(response.ChangedFields)))
+}
+
+func (a AdminPresenter) QuotaUsageResponse(response []domain.QuotaUsageResponse, err error) {
+	common.HandleInternalServerError(err, a.w)
+	if response == nil {
+		common.WriteJSONResponse(a.w, http.StatusOK, "Get quota usage successfully", domain.UserViewNilResponse{Message: "Quota usage not found"}, 0)
+		return
+	}
+	common.WriteJSONResponse(a.w, http.StatusOK, "Get quota usage successfully", response, int64(len(response)))
+}
+
+func (a AdminPresenter) JobsResponse(response []domain.JobStatusResponse, err error) {
+	common.HandleInternalServerError(err, a.w)
+	common.WriteJSONResponse(a.w, http.StatusOK, "Get jobs successfully", response, int64(len(response)))
+}
+
+func (a AdminPresenter) JobResponse(response domain.JobStatusResponse, err error) {
+	common.HandleInternalServerError(err, a.w)
+	common.WriteJSONResponse(a.w, http.StatusOK, "Update job successfully", response, 1)
+}
+
+func (a AdminPresenter) GeoRestrictionMetricsResponse(response domain.GeoRestrictionMetricsResponse, err error) {
+	common.HandleEnvelopeError(err, a.w)
+	common.WriteEnvelope(a.w, http.StatusOK, "Geo restriction metrics", response, nil)
+}
+
+func (a AdminPresenter) RuntimeMetricsResponse(response domain.RuntimeMetricsResponse, err error) {
+	common.HandleEnvelopeError(err, a.w)
+	common.WriteEnvelope(a.w, http.StatusOK, "Runtime metrics", response, nil)
+}
+
+func (a AdminPresenter) LogLevelsResponse(response []domain.LogLevelResponse, err error) {
+	common.HandleEnvelopeError(err, a.w)
+	common.WriteEnvelope(a.w, http.StatusOK, "Log levels", response, nil)
+}
@@ -19,17 +19,73 @@ func NewAuthPresenter(w http.ResponseWriter) usecase.OutputAuthBoundary {
 
 // RegisterResponse sends the registration response to the client
 func (ap *AuthPresenter) RegisterResponse(response domain.RegisterResponse, err error) {
-	common.HandleInternalServerError(err, ap.w)
-	common.WriteJSONResponse(ap.w, http.StatusCreated, "Created account successfully", response, 1)
+	common.HandleEnvelopeError(err, ap.w)
+	common.WriteEnvelope(ap.w, http.StatusCreated, "Created account successfully", response, nil)
 }
 
 // LoginResponse sends the login response to the client
 func (ap *AuthPresenter) LoginResponse(response domain.LoginResponse, err error) {
-	common.HandleInternalServerError(err, ap.w)
-	common.WriteJSONResponse(ap.w, http.StatusOK, "Login account successfully", response, 1)
+	common.HandleEnvelopeError(err, ap.w)
+	common.WriteEnvelope(ap.w, http.StatusOK, "Login account successfully", response, nil)
 }
 
 func (ap *AuthPresenter) LogoutResponse(response domain.LogoutResponse, err error) {
-	common.HandleInternalServerError(err, ap.w)
-	common.WriteJSONResponse(ap.w, http.StatusOK, "Logout account successfully", response, 1)
+	common.HandleEnvelopeError(err, ap.w)
+	common.WriteEnvelope(ap.w, http.StatusOK, "Logout account successfully", response, nil)
+}
+
+func (ap *AuthPresenter) RefreshTokenResponse(response domain.RefreshTokenResponse, err error) {
+	common.HandleEnvelopeError(err, ap.w)
+	common.WriteEnvelope(ap.w, http.StatusOK, "Refreshed token successfully", response, nil)
+}
+
+func (ap *AuthPresenter) PasswordResetResponse(response domain.PasswordResetResponse, err error) {
+	common.HandleEnvelopeError(err, ap.w)
+	common.WriteEnvelope(ap.w, http.StatusOK, response.Message, response, nil)
+}
+
+func (ap *AuthPresenter) ScopedTokenResponse(response domain.ScopedTokenResponse, err error) {
+	common.HandleEnvelopeError(err, ap.w)
+	common.WriteEnvelope(ap.w, http.StatusCreated, "Issued scoped token successfully", response, nil)
+}
+
+// IntrospectionResponse is written without the envelope, RFC 7662 clients expect active at the top level
+func (ap *AuthPresenter) IntrospectionResponse(response domain.IntrospectionResponse, err error) {
+	common.HandleEnvelopeError(err, ap.w)
+	common.WriteJSON(ap.w, http.StatusOK, response)
+}
+
+func (ap *AuthPresenter) LoginThrottleMetricsResponse(response domain.LoginThrottleMetrics, err error) {
+	common.HandleEnvelopeError(err, ap.w)
+	common.WriteEnvelope(ap.w, http.StatusOK, "Login throttle metrics", response, nil)
+}
+
+func (ap *AuthPresenter) PasskeyCreationOptionsResponse(response domain.PasskeyCreationOptions, err error) {
+	common.HandleEnvelopeError(err, ap.w)
+	common.WriteEnvelope(ap.w, http.StatusOK, "Passkey registration options", response, nil)
+}
+
+func (ap *AuthPresenter) PasskeyRequestOptionsResponse(response domain.PasskeyRequestOptions, err error) {
+	common.HandleEnvelopeError(err, ap.w)
+	common.WriteEnvelope(ap.w, http.StatusOK, "Passkey login options", response, nil)
+}
+
+func (ap *AuthPresenter) PasskeyResponse(response domain.PasskeyResponse, err error) {
+	common.HandleEnvelopeError(err, ap.w)
+	common.WriteEnvelope(ap.w, http.StatusCreated, "Registered passkey successfully", response, nil)
+}
+
+func (ap *AuthPresenter) PasskeysResponse(response []domain.PasskeyResponse, err error) {
+	common.HandleEnvelopeError(err, ap.w)
+	common.WriteEnvelope(ap.w, http.StatusOK, "Get passkeys successfully", response, nil)
+}
+
+func (ap *AuthPresenter) DeletePasskeyResponse(response domain.DeletePasskeyResponse, err error) {
+	common.HandleEnvelopeError(err, ap.w)
+	common.WriteEnvelope(ap.w, http.StatusOK, "Removed passkey successfully", response, nil)
+}
+
+func (ap *AuthPresenter) RegionLookupResponse(response domain.RegionResponse, err error) {
+	common.HandleEnvelopeError(err, ap.w)
+	common.WriteEnvelope(ap.w, http.StatusOK, "Region found successfully", response, nil)
 }
@@ -0,0 +1,51 @@
+package presenters
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/blocks"
+	"godating-dealls/internal/domain"
+	"net/http"
+	"strconv"
+)
+
+type BlockPresenter struct {
+	w      http.ResponseWriter
+	export *common.ExportWriter
+}
+
+func NewBlockPresenter(w http.ResponseWriter) blocks.OutputBlockBoundary {
+	return &BlockPresenter{w: w}
+}
+
+func NewBlockExportPresenter(w http.ResponseWriter, export *common.ExportWriter) blocks.OutputBlockBoundary {
+	return &BlockPresenter{w: w, export: export}
+}
+
+func (bp *BlockPresenter) BlockedAccountsResponse(response domain.BlockedAccountsResponse, err error) {
+	common.HandleEnvelopeError(err, bp.w)
+	common.WriteEnvelope(bp.w, http.StatusOK, "Get blocked accounts successfully", response, nil)
+}
+
+func (bp *BlockPresenter) ReportResponse(response domain.ReportResponse, err error) {
+	common.HandleEnvelopeError(err, bp.w)
+	common.WriteEnvelope(bp.w, http.StatusCreated, "Account reported successfully", response, nil)
+}
+
+func (bp *BlockPresenter) ReportsResponse(response domain.ReportsResponse, err error) {
+	common.HandleEnvelopeError(err, bp.w)
+	common.WriteEnvelope(bp.w, http.StatusOK, "Get reports successfully", response, nil)
+}
+
+// ReportExportHeader is the CSV header of ReportExportRow
+var ReportExportHeader = []string{"report_id", "reporter_account_id", "reported_account_id", "reason", "details", "reported_at"}
+
+func (bp *BlockPresenter) ReportExportRow(response domain.ReportResponse) error {
+	return bp.export.WriteRow(response, []string{
+		strconv.FormatInt(response.ReportID, 10),
+		strconv.FormatInt(response.ReporterAccountID, 10),
+		strconv.FormatInt(response.ReportedAccountID, 10),
+		response.Reason,
+		response.Details,
+		response.ReportedAt,
+	})
+}
@@ -0,0 +1,21 @@
+package presenters
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/candidates"
+	"godating-dealls/internal/domain"
+	"net/http"
+)
+
+type CandidatePresenter struct {
+	w http.ResponseWriter
+}
+
+func NewCandidatePresenter(w http.ResponseWriter) candidates.OutputCandidateBoundary {
+	return &CandidatePresenter{w: w}
+}
+
+func (cp *CandidatePresenter) CandidatesResponse(response domain.CandidatesResponse, err error) {
+	common.HandleEnvelopeError(err, cp.w)
+	common.WriteEnvelope(cp.w, http.StatusOK, "Get candidates successfully", response, &common.Pagination{Page: response.Page, Size: response.Size, Total: response.Total})
+}
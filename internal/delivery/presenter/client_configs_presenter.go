@@ -0,0 +1,43 @@
+package presenters
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/client_configs"
+	"godating-dealls/internal/domain"
+	"net/http"
+)
+
+// clientConfigMaxAge is how long an app may reuse its config before asking again with If-None-Match
+const clientConfigMaxAge = "private, max-age=300"
+
+type ClientConfigPresenter struct {
+	w           http.ResponseWriter
+	ifNoneMatch string
+}
+
+// NewClientConfigPresenter takes the If-None-Match header of the request so an unchanged config is answered with 304
+func NewClientConfigPresenter(w http.ResponseWriter, ifNoneMatch string) client_configs.OutputClientConfigBoundary {
+	return &ClientConfigPresenter{w: w, ifNoneMatch: ifNoneMatch}
+}
+
+// ClientConfigResponse depends on the account and the app version, so shared caches must not reuse it
+func (cp *ClientConfigPresenter) ClientConfigResponse(response domain.ClientConfigResponse, err error) {
+	common.HandleEnvelopeError(err, cp.w)
+	cp.w.Header().Set("Vary", "Authorization, X-App-Version")
+	writeEnvelopeWithETag(cp.w, cp.ifNoneMatch, clientConfigMaxAge, "Get client config successfully", response)
+}
+
+func (cp *ClientConfigPresenter) PublishClientConfigResponse(response domain.ClientConfigBundleResponse, err error) {
+	common.HandleEnvelopeError(err, cp.w)
+	common.WriteEnvelope(cp.w, http.StatusCreated, "Published client config successfully", response, nil)
+}
+
+func (cp *ClientConfigPresenter) ClientConfigBundlesResponse(response []domain.ClientConfigBundleResponse, err error) {
+	common.HandleEnvelopeError(err, cp.w)
+	common.WriteEnvelope(cp.w, http.StatusOK, "Get client config bundles successfully", response, nil)
+}
+
+func (cp *ClientConfigPresenter) ClientConfigRolloutResponse(response domain.ClientConfigBundleResponse, err error) {
+	common.HandleEnvelopeError(err, cp.w)
+	common.WriteEnvelope(cp.w, http.StatusOK, "Updated client config rollout successfully", response, nil)
+}
@@ -0,0 +1,26 @@
+package presenters
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/contacts"
+	"godating-dealls/internal/domain"
+	"net/http"
+)
+
+type ContactPresenter struct {
+	w http.ResponseWriter
+}
+
+func NewContactPresenter(w http.ResponseWriter) contacts.OutputContactBoundary {
+	return &ContactPresenter{w: w}
+}
+
+func (c ContactPresenter) ContactExclusionsResponse(response domain.ContactExclusionsResponse, err error) {
+	common.HandleInternalServerError(err, c.w)
+	common.WriteJSONResponse(c.w, http.StatusOK, "Get contact exclusions successfully", response, response.TotalExcluded)
+}
+
+func (c ContactPresenter) ContactExclusionsUpdateResponse(response domain.ContactExclusionsUpdateResponse, err error) {
+	common.HandleInternalServerError(err, c.w)
+	common.WriteJSONResponse(c.w, http.StatusOK, "Update contact exclusions successfully", response, 1)
+}
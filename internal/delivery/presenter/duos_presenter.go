@@ -0,0 +1,36 @@
+package presenters
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/duos"
+	"godating-dealls/internal/domain"
+	"net/http"
+)
+
+type DuoPresenter struct {
+	w http.ResponseWriter
+}
+
+func NewDuoPresenter(w http.ResponseWriter) duos.OutputDuoBoundary {
+	return &DuoPresenter{w: w}
+}
+
+func (dp *DuoPresenter) DuoResponse(response domain.DuoResponse, err error) {
+	common.HandleEnvelopeError(err, dp.w)
+	common.WriteEnvelope(dp.w, http.StatusOK, "Get duo successfully", response, nil)
+}
+
+func (dp *DuoPresenter) DuoCandidatesResponse(response []domain.DuoCandidateResponse, err error) {
+	common.HandleEnvelopeError(err, dp.w)
+	common.WriteEnvelope(dp.w, http.StatusOK, "Get duo candidates successfully", response, &common.Pagination{Page: 1, Size: len(response), Total: int64(len(response))})
+}
+
+func (dp *DuoPresenter) DuoSwipeResponse(response domain.SwipeResponse, err error) {
+	common.HandleEnvelopeError(err, dp.w)
+	common.WriteEnvelope(dp.w, http.StatusOK, "Swipe duo successfully", response, nil)
+}
+
+func (dp *DuoPresenter) DuoMatchesResponse(response []domain.DuoMatchResponse, err error) {
+	common.HandleEnvelopeError(err, dp.w)
+	common.WriteEnvelope(dp.w, http.StatusOK, "Get duo matches successfully", response, &common.Pagination{Page: 1, Size: len(response), Total: int64(len(response))})
+}
@@ -0,0 +1,41 @@
+package presenters
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"godating-dealls/internal/common"
+	"net/http"
+	"strings"
+)
+
+// writeEnvelopeWithETag tags the data with a strong ETag of its content and answers 304 when the client already has it,
+// the tag leaves out the envelope meta because request id and time change on every call
+func writeEnvelopeWithETag(w http.ResponseWriter, ifNoneMatch string, cacheControl string, message string, data interface{}) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		common.HandleEnvelopeError(err, w)
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", cacheControl)
+	if etagMatches(ifNoneMatch, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	common.WriteEnvelope(w, http.StatusOK, message, data, nil)
+}
+
+// etagMatches accepts the list form of If-None-Match and weak tags added by proxies
+func etagMatches(ifNoneMatch string, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package presenters
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/events"
+	"godating-dealls/internal/domain"
+	"net/http"
+)
+
+type EventPresenter struct {
+	w http.ResponseWriter
+}
+
+func NewEventPresenter(w http.ResponseWriter) events.OutputEventBoundary {
+	return &EventPresenter{w: w}
+}
+
+func (ep *EventPresenter) EventCreatedResponse(response domain.EventResponse, err error) {
+	common.HandleEnvelopeError(err, ep.w)
+	common.WriteEnvelope(ep.w, http.StatusCreated, "Event created successfully", response, nil)
+}
+
+func (ep *EventPresenter) EventResponse(response domain.EventResponse, err error) {
+	common.HandleEnvelopeError(err, ep.w)
+	common.WriteEnvelope(ep.w, http.StatusOK, "Joined event successfully", response, nil)
+}
+
+func (ep *EventPresenter) EventsResponse(response []domain.EventResponse, err error) {
+	common.HandleEnvelopeError(err, ep.w)
+	common.WriteEnvelope(ep.w, http.StatusOK, "Get events successfully", response, nil)
+}
+
+func (ep *EventPresenter) EventConnectionResponse(response domain.EventConnectionResponse, err error) {
+	common.HandleEnvelopeError(err, ep.w)
+	common.WriteEnvelope(ep.w, http.StatusOK, response.Message, response, nil)
+}
@@ -0,0 +1,33 @@
+package presenters
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/health"
+	"godating-dealls/internal/domain"
+	"net/http"
+)
+
+type HealthPresenter struct {
+	w http.ResponseWriter
+}
+
+func NewHealthPresenter(w http.ResponseWriter) health.OutputHealthBoundary {
+	return &HealthPresenter{w: w}
+}
+
+// ReadinessResponse answers 503 with the report while the instance is not ready, probes only look at the status
+func (hp *HealthPresenter) ReadinessResponse(response domain.ReadinessResponse, err error) {
+	common.HandleEnvelopeError(err, hp.w)
+	if !response.Ready {
+		common.WriteEnvelope(hp.w, http.StatusServiceUnavailable, "Schema drift detected", response, nil)
+		return
+	}
+	common.WriteEnvelope(hp.w, http.StatusOK, "Ready", response, nil)
+}
+
+// ServiceStatusResponse answers 200 whatever the state, the status page reads the state from the body
+func (hp *HealthPresenter) ServiceStatusResponse(response domain.ServiceStatusResponse, err error) {
+	common.HandleEnvelopeError(err, hp.w)
+	hp.w.Header().Set("Cache-Control", "no-store")
+	common.WriteEnvelope(hp.w, http.StatusOK, "Get service status successfully", response, nil)
+}
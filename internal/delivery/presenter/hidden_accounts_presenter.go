@@ -0,0 +1,21 @@
+package presenters
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/hidden_accounts"
+	"godating-dealls/internal/domain"
+	"net/http"
+)
+
+type HiddenAccountPresenter struct {
+	w http.ResponseWriter
+}
+
+func NewHiddenAccountPresenter(w http.ResponseWriter) hidden_accounts.OutputHiddenAccountBoundary {
+	return &HiddenAccountPresenter{w: w}
+}
+
+func (hp *HiddenAccountPresenter) HiddenAccountsResponse(response domain.HiddenAccountsResponse, err error) {
+	common.HandleEnvelopeError(err, hp.w)
+	common.WriteEnvelope(hp.w, http.StatusOK, "Get hidden accounts successfully", response, nil)
+}
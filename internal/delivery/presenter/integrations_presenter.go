@@ -0,0 +1,37 @@
+package presenters
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/integrations"
+	"godating-dealls/internal/domain"
+	"net/http"
+)
+
+type IntegrationPresenter struct {
+	w http.ResponseWriter
+}
+
+func NewIntegrationPresenter(w http.ResponseWriter) integrations.OutputIntegrationBoundary {
+	return &IntegrationPresenter{w: w}
+}
+
+func (i IntegrationPresenter) ConnectIntegrationResponse(response domain.ConnectIntegrationResponse, err error) {
+	common.HandleInternalServerError(err, i.w)
+	common.WriteJSONResponse(i.w, http.StatusOK, "Connect integration successfully", response, 1)
+}
+
+func (i IntegrationPresenter) ImportedContentResponse(response []domain.ImportedContentResponse, err error) {
+	common.HandleInternalServerError(err, i.w)
+	if response == nil {
+		common.WriteJSONResponse(i.w, http.StatusOK, "Get imported content successfully", domain.UserViewNilResponse{
+			Message: "Imported content not found",
+		}, int64(len(response)))
+	} else {
+		common.WriteJSONResponse(i.w, http.StatusOK, "Get imported content successfully", response, int64(len(response)))
+	}
+}
+
+func (i IntegrationPresenter) DisconnectIntegrationResponse(response domain.DisconnectIntegrationResponse, err error) {
+	common.HandleInternalServerError(err, i.w)
+	common.WriteJSONResponse(i.w, http.StatusOK, "Disconnect integration successfully", response, 1)
+}
@@ -0,0 +1,21 @@
+package presenters
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/likes"
+	"godating-dealls/internal/domain"
+	"net/http"
+)
+
+type LikesPresenter struct {
+	w http.ResponseWriter
+}
+
+func NewLikesPresenter(w http.ResponseWriter) likes.OutputLikesBoundary {
+	return &LikesPresenter{w: w}
+}
+
+func (lp *LikesPresenter) LikesReceivedResponse(response domain.LikesReceivedResponse, err error) {
+	common.HandleEnvelopeError(err, lp.w)
+	common.WriteEnvelope(lp.w, http.StatusOK, "Get likes received successfully", response, &common.Pagination{Page: response.Page, Size: response.Size, Total: response.Total})
+}
@@ -0,0 +1,21 @@
+package presenters
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/match_features"
+	"godating-dealls/internal/domain"
+	"net/http"
+)
+
+type MatchFeaturePresenter struct {
+	w http.ResponseWriter
+}
+
+func NewMatchFeaturePresenter(w http.ResponseWriter) match_features.OutputMatchFeatureBoundary {
+	return &MatchFeaturePresenter{w: w}
+}
+
+func (mp *MatchFeaturePresenter) MatchScoresResponse(response domain.MatchScoresResponse, err error) {
+	common.HandleEnvelopeError(err, mp.w)
+	common.WriteEnvelope(mp.w, http.StatusOK, "Saved match scores successfully", response, nil)
+}
@@ -0,0 +1,21 @@
+package presenters
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/matches"
+	"godating-dealls/internal/domain"
+	"net/http"
+)
+
+type MatchPresenter struct {
+	w http.ResponseWriter
+}
+
+func NewMatchPresenter(w http.ResponseWriter) matches.OutputMatchBoundary {
+	return &MatchPresenter{w: w}
+}
+
+func (mp *MatchPresenter) MatchesResponse(response []domain.MatchResponse, err error) {
+	common.HandleEnvelopeError(err, mp.w)
+	common.WriteEnvelope(mp.w, http.StatusOK, "Get matches successfully", response, &common.Pagination{Page: 1, Size: len(response), Total: int64(len(response))})
+}
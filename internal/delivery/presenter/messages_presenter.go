@@ -0,0 +1,82 @@
+package presenters
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/messages"
+	"godating-dealls/internal/domain"
+	"golang.org/x/net/websocket"
+	"log"
+	"net/http"
+)
+
+type MessagePresenter struct {
+	w http.ResponseWriter
+}
+
+func NewMessagePresenter(w http.ResponseWriter) messages.OutputMessageBoundary {
+	return &MessagePresenter{w: w}
+}
+
+func (mp *MessagePresenter) MessageResponse(response domain.ChatMessageResponse, err error) {
+	common.HandleEnvelopeError(err, mp.w)
+	common.WriteEnvelope(mp.w, http.StatusCreated, "Sent message successfully", response, nil)
+}
+
+func (mp *MessagePresenter) MessagesResponse(response []domain.ChatMessageResponse, err error) {
+	common.HandleEnvelopeError(err, mp.w)
+	common.WriteEnvelope(mp.w, http.StatusOK, "Get messages successfully", response, &common.Pagination{Page: 1, Size: len(response), Total: int64(len(response))})
+}
+
+// LiveMessageResponse is not used over plain http, live messages go to the websocket
+func (mp *MessagePresenter) LiveMessageResponse(domain.ChatMessageResponse) {}
+
+func (mp *MessagePresenter) ParticipantsResponse(response domain.ChatParticipantsResponse, err error) {
+	common.HandleEnvelopeError(err, mp.w)
+	common.WriteEnvelope(mp.w, http.StatusOK, "Get chat participants successfully", response, nil)
+}
+
+func (mp *MessagePresenter) ReplicatedMessageResponse(response domain.ReplicatedMessageResponse, err error) {
+	common.HandleEnvelopeError(err, mp.w)
+	common.WriteEnvelope(mp.w, http.StatusOK, "Delivered replicated message successfully", response, nil)
+}
+
+// ChatSocketEvent is a frame pushed to the app, type is message for a live message, sent to acknowledge
+// a message the app sent on this connection, or error
+type ChatSocketEvent struct {
+	Type    string                      `json:"type"`
+	Message *domain.ChatMessageResponse `json:"message,omitempty"`
+	Error   *common.EnvelopeError       `json:"error,omitempty"`
+}
+
+type ChatSocketPresenter struct {
+	conn *websocket.Conn
+}
+
+func NewChatSocketPresenter(conn *websocket.Conn) *ChatSocketPresenter {
+	return &ChatSocketPresenter{conn: conn}
+}
+
+func (cp *ChatSocketPresenter) MessageResponse(response domain.ChatMessageResponse, err error) {
+	cp.send(ChatSocketEvent{Type: "sent", Message: &response})
+}
+
+func (cp *ChatSocketPresenter) MessagesResponse([]domain.ChatMessageResponse, error) {}
+
+func (cp *ChatSocketPresenter) LiveMessageResponse(response domain.ChatMessageResponse) {
+	cp.send(ChatSocketEvent{Type: "message", Message: &response})
+}
+
+func (cp *ChatSocketPresenter) ParticipantsResponse(domain.ChatParticipantsResponse, error) {}
+
+func (cp *ChatSocketPresenter) ReplicatedMessageResponse(domain.ReplicatedMessageResponse, error) {}
+
+func (cp *ChatSocketPresenter) ErrorResponse(code string, message string) {
+	cp.send(ChatSocketEvent{Type: "error", Error: &common.EnvelopeError{Code: code, Message: message}})
+}
+
+// send is safe from several goroutines, the websocket serializes frame writes
+func (cp *ChatSocketPresenter) send(event ChatSocketEvent) {
+	if err := websocket.JSON.Send(cp.conn, event); err != nil {
+		log.Println("chat socket write failed:", err)
+	}
+}
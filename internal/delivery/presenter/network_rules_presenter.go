@@ -0,0 +1,31 @@
+package presenters
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/network_rules"
+	"godating-dealls/internal/domain"
+	"net/http"
+)
+
+type NetworkRulePresenter struct {
+	w http.ResponseWriter
+}
+
+func NewNetworkRulePresenter(w http.ResponseWriter) network_rules.OutputNetworkRuleBoundary {
+	return &NetworkRulePresenter{w: w}
+}
+
+func (np *NetworkRulePresenter) NetworkRulesResponse(response []domain.NetworkRuleResponse, err error) {
+	common.HandleEnvelopeError(err, np.w)
+	common.WriteEnvelope(np.w, http.StatusOK, "Get network rules successfully", response, nil)
+}
+
+func (np *NetworkRulePresenter) NetworkRuleResponse(response domain.NetworkRuleResponse, err error) {
+	common.HandleEnvelopeError(err, np.w)
+	common.WriteEnvelope(np.w, http.StatusCreated, "Saved network rule successfully", response, nil)
+}
+
+func (np *NetworkRulePresenter) DeleteNetworkRuleResponse(response domain.DeleteNetworkRuleResponse, err error) {
+	common.HandleEnvelopeError(err, np.w)
+	common.WriteEnvelope(np.w, http.StatusOK, "Removed network rule successfully", response, nil)
+}
@@ -0,0 +1,37 @@
+package presenters
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/notes"
+	"godating-dealls/internal/domain"
+	"net/http"
+)
+
+type NotePresenter struct {
+	w http.ResponseWriter
+}
+
+func NewNotePresenter(w http.ResponseWriter) notes.OutputNoteBoundary {
+	return &NotePresenter{w: w}
+}
+
+func (n NotePresenter) NoteResponse(response domain.NoteResponse, err error) {
+	common.HandleInternalServerError(err, n.w)
+	common.WriteJSONResponse(n.w, http.StatusOK, "Save note successfully", response, 1)
+}
+
+func (n NotePresenter) NotesResponse(response []domain.NoteResponse, err error) {
+	common.HandleInternalServerError(err, n.w)
+	if response == nil {
+		common.WriteJSONResponse(n.w, http.StatusOK, "Get notes successfully", domain.UserViewNilResponse{
+			Message: "Notes not found",
+		}, int64(len(response)))
+	} else {
+		common.WriteJSONResponse(n.w, http.StatusOK, "Get notes successfully", response, int64(len(response)))
+	}
+}
+
+func (n NotePresenter) DeleteNoteResponse(response domain.DeleteNoteResponse, err error) {
+	common.HandleInternalServerError(err, n.w)
+	common.WriteJSONResponse(n.w, http.StatusOK, "Delete note successfully", response, 1)
+}
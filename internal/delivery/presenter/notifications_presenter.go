@@ -0,0 +1,31 @@
+package presenters
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/notifications"
+	"godating-dealls/internal/domain"
+	"net/http"
+)
+
+type NotificationPresenter struct {
+	w http.ResponseWriter
+}
+
+func NewNotificationPresenter(w http.ResponseWriter) notifications.OutputNotificationBoundary {
+	return &NotificationPresenter{w: w}
+}
+
+func (np *NotificationPresenter) DeviceResponse(response domain.DeviceResponse, err error) {
+	common.HandleEnvelopeError(err, np.w)
+	common.WriteEnvelope(np.w, http.StatusCreated, "Device registered successfully", response, nil)
+}
+
+func (np *NotificationPresenter) NotificationsResponse(response domain.NotificationsResponse, err error) {
+	common.HandleEnvelopeError(err, np.w)
+	common.WriteEnvelope(np.w, http.StatusOK, "Get notifications successfully", response, &common.Pagination{Page: response.Page, Size: response.Size, Total: response.Total})
+}
+
+func (np *NotificationPresenter) NotificationsReadResponse(response domain.NotificationsReadResponse, err error) {
+	common.HandleEnvelopeError(err, np.w)
+	common.WriteEnvelope(np.w, http.StatusOK, "Notifications marked read successfully", response, nil)
+}
@@ -30,3 +30,13 @@ func (p PackagePresenter) PurchasePackageResponse(response domain.PurchasePackag
 	common.HandleInternalServerError(err, p.w)
 	common.WriteJSONResponse(p.w, http.StatusOK, "Purchase packages successfully", response, 1)
 }
+
+func (p PackagePresenter) PremiumPurchaseResponse(response domain.PremiumStatusResponse, err error) {
+	common.HandleEnvelopeError(err, p.w)
+	common.WriteEnvelope(p.w, http.StatusCreated, "Purchased premium successfully", response, nil)
+}
+
+func (p PackagePresenter) PremiumStatusResponse(response domain.PremiumStatusResponse, err error) {
+	common.HandleEnvelopeError(err, p.w)
+	common.WriteEnvelope(p.w, http.StatusOK, "Get premium status successfully", response, nil)
+}
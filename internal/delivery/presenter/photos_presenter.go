@@ -0,0 +1,46 @@
+package presenters
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/photos"
+	"godating-dealls/internal/domain"
+	"net/http"
+)
+
+type PhotoPresenter struct {
+	w http.ResponseWriter
+}
+
+func NewPhotoPresenter(w http.ResponseWriter) photos.OutputPhotoBoundary {
+	return &PhotoPresenter{w: w}
+}
+
+func (pp *PhotoPresenter) PhotoResponse(response domain.PhotoResponse, err error) {
+	common.HandleEnvelopeError(err, pp.w)
+	common.WriteEnvelope(pp.w, http.StatusCreated, "Uploaded photo successfully", response, nil)
+}
+
+func (pp *PhotoPresenter) PhotosResponse(response []domain.PhotoResponse, err error) {
+	common.HandleEnvelopeError(err, pp.w)
+	common.WriteEnvelope(pp.w, http.StatusOK, "Get photos successfully", response, nil)
+}
+
+func (pp *PhotoPresenter) DeletePhotoResponse(response []domain.PhotoResponse, err error) {
+	common.HandleEnvelopeError(err, pp.w)
+	common.WriteEnvelope(pp.w, http.StatusOK, "Deleted photo successfully", response, nil)
+}
+
+func (pp *PhotoPresenter) TrashedPhotosResponse(response []domain.TrashedPhotoResponse, err error) {
+	common.HandleEnvelopeError(err, pp.w)
+	common.WriteEnvelope(pp.w, http.StatusOK, "Get deleted photos successfully", response, nil)
+}
+
+func (pp *PhotoPresenter) RestorePhotoResponse(response []domain.PhotoResponse, err error) {
+	common.HandleEnvelopeError(err, pp.w)
+	common.WriteEnvelope(pp.w, http.StatusOK, "Restored photo successfully", response, nil)
+}
+
+func (pp *PhotoPresenter) SmartPhotosResponse(response domain.SmartPhotosResponse, err error) {
+	common.HandleEnvelopeError(err, pp.w)
+	common.WriteEnvelope(pp.w, http.StatusOK, "Get smart photos successfully", response, nil)
+}
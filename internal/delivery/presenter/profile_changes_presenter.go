@@ -0,0 +1,31 @@
+package presenters
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/profile_changes"
+	"godating-dealls/internal/domain"
+	"net/http"
+)
+
+type ProfileChangePresenter struct {
+	w http.ResponseWriter
+}
+
+func NewProfileChangePresenter(w http.ResponseWriter) profile_changes.OutputProfileChangeBoundary {
+	return &ProfileChangePresenter{w: w}
+}
+
+func (pp *ProfileChangePresenter) ProfileChangeResponse(response domain.ProfileChangeResponse, err error) {
+	common.HandleEnvelopeError(err, pp.w)
+	common.WriteEnvelope(pp.w, http.StatusCreated, "Submitted profile change request successfully", response, nil)
+}
+
+func (pp *ProfileChangePresenter) ProfileChangesResponse(response []domain.ProfileChangeResponse, err error) {
+	common.HandleEnvelopeError(err, pp.w)
+	common.WriteEnvelope(pp.w, http.StatusOK, "Get profile change requests successfully", response, nil)
+}
+
+func (pp *ProfileChangePresenter) ReviewProfileChangeResponse(response domain.ProfileChangeResponse, err error) {
+	common.HandleEnvelopeError(err, pp.w)
+	common.WriteEnvelope(pp.w, http.StatusOK, "Reviewed profile change request successfully", response, nil)
+}
@@ -0,0 +1,21 @@
+package presenters
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/profile_strength"
+	"godating-dealls/internal/domain"
+	"net/http"
+)
+
+type ProfileStrengthPresenter struct {
+	w http.ResponseWriter
+}
+
+func NewProfileStrengthPresenter(w http.ResponseWriter) profile_strength.OutputProfileStrengthBoundary {
+	return &ProfileStrengthPresenter{w: w}
+}
+
+func (p ProfileStrengthPresenter) ProfileStrengthResponse(response domain.ProfileStrengthResponse, err error) {
+	common.HandleInternalServerError(err, p.w)
+	common.WriteJSONResponse(p.w, http.StatusOK, "Get profile strength successfully", response, int64(len(response.Suggestions)))
+}
@@ -0,0 +1,41 @@
+package presenters
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/recovery"
+	"godating-dealls/internal/domain"
+	"net/http"
+)
+
+type RecoveryPresenter struct {
+	w http.ResponseWriter
+}
+
+func NewRecoveryPresenter(w http.ResponseWriter) recovery.OutputRecoveryBoundary {
+	return &RecoveryPresenter{w: w}
+}
+
+func (rp *RecoveryPresenter) RecoverySettingsResponse(response domain.RecoverySettingsResponse, err error) {
+	common.HandleEnvelopeError(err, rp.w)
+	common.WriteEnvelope(rp.w, http.StatusOK, "Get recovery settings successfully", response, nil)
+}
+
+func (rp *RecoveryPresenter) StartRecoveryResponse(response domain.StartRecoveryResponse, err error) {
+	common.HandleEnvelopeError(err, rp.w)
+	common.WriteEnvelope(rp.w, http.StatusAccepted, "Recovery requested", response, nil)
+}
+
+func (rp *RecoveryPresenter) RecoveryApprovalsResponse(response []domain.RecoveryRequestResponse, err error) {
+	common.HandleEnvelopeError(err, rp.w)
+	common.WriteEnvelope(rp.w, http.StatusOK, "Get recovery approvals successfully", response, nil)
+}
+
+func (rp *RecoveryPresenter) RecoveryApprovalResponse(response domain.RecoveryApprovalResponse, err error) {
+	common.HandleEnvelopeError(err, rp.w)
+	common.WriteEnvelope(rp.w, http.StatusOK, "Approved recovery successfully", response, nil)
+}
+
+func (rp *RecoveryPresenter) RecoveryMessageResponse(response domain.RecoveryMessageResponse, err error) {
+	common.HandleEnvelopeError(err, rp.w)
+	common.WriteEnvelope(rp.w, http.StatusOK, response.Message, response, nil)
+}
@@ -0,0 +1,26 @@
+package presenters
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/rewards"
+	"godating-dealls/internal/domain"
+	"net/http"
+)
+
+type RewardPresenter struct {
+	w http.ResponseWriter
+}
+
+func NewRewardPresenter(w http.ResponseWriter) rewards.OutputRewardBoundary {
+	return &RewardPresenter{w: w}
+}
+
+func (r RewardPresenter) LoginStreakResponse(response domain.LoginStreakResponse, err error) {
+	common.HandleInternalServerError(err, r.w)
+	common.WriteJSONResponse(r.w, http.StatusOK, "Get login streak successfully", response, 1)
+}
+
+func (r RewardPresenter) ClaimRewardResponse(response domain.ClaimRewardResponse, err error) {
+	common.HandleInternalServerError(err, r.w)
+	common.WriteJSONResponse(r.w, http.StatusOK, "Claim reward successfully", response, 1)
+}
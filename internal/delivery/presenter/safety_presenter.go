@@ -0,0 +1,21 @@
+package presenters
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/safety"
+	"godating-dealls/internal/domain"
+	"net/http"
+)
+
+type SafetyPresenter struct {
+	w http.ResponseWriter
+}
+
+func NewSafetyPresenter(w http.ResponseWriter) safety.OutputSafetyBoundary {
+	return &SafetyPresenter{w: w}
+}
+
+func (sp *SafetyPresenter) SafetySettingsResponse(response domain.SafetySettingsResponse, err error) {
+	common.HandleEnvelopeError(err, sp.w)
+	common.WriteEnvelope(sp.w, http.StatusOK, "Get safety settings successfully", response, nil)
+}
@@ -0,0 +1,42 @@
+package presenters
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/share_links"
+	"godating-dealls/internal/domain"
+	"net/http"
+)
+
+type ShareLinkPresenter struct {
+	w http.ResponseWriter
+}
+
+func NewShareLinkPresenter(w http.ResponseWriter) share_links.OutputShareLinkBoundary {
+	return &ShareLinkPresenter{w: w}
+}
+
+func (s ShareLinkPresenter) ShareLinkResponse(response domain.ShareLinkResponse, err error) {
+	common.HandleInternalServerError(err, s.w)
+	common.WriteJSONResponse(s.w, http.StatusOK, "Create share link successfully", response, 1)
+}
+
+func (s ShareLinkPresenter) ShareLinksResponse(response []domain.ShareLinkResponse, err error) {
+	common.HandleInternalServerError(err, s.w)
+	if response == nil {
+		common.WriteJSONResponse(s.w, http.StatusOK, "Get share links successfully", domain.UserViewNilResponse{
+			Message: "Share links not found",
+		}, int64(len(response)))
+	} else {
+		common.WriteJSONResponse(s.w, http.StatusOK, "Get share links successfully", response, int64(len(response)))
+	}
+}
+
+func (s ShareLinkPresenter) RevokeShareLinkResponse(response domain.RevokeShareLinkResponse, err error) {
+	common.HandleInternalServerError(err, s.w)
+	common.WriteJSONResponse(s.w, http.StatusOK, "Revoke share link successfully", response, 1)
+}
+
+func (s ShareLinkPresenter) PublicProfileResponse(response domain.PublicProfileResponse, err error) {
+	common.HandleInternalServerError(err, s.w)
+	common.WriteJSONResponse(s.w, http.StatusOK, "Get public profile successfully", response, 1)
+}
@@ -0,0 +1,40 @@
+package presenters
+
+import (
+	"godating-dealls/internal/common"
+	"godating-dealls/internal/core/usecase/status_messages"
+	"godating-dealls/internal/domain"
+	"net/http"
+)
+
+// statusMaxAge matches the server side cache, the status is the same for everyone so shared caches may keep it
+const statusMaxAge = "public, max-age=30"
+
+type StatusMessagePresenter struct {
+	w           http.ResponseWriter
+	ifNoneMatch string
+}
+
+func NewStatusMessagePresenter(w http.ResponseWriter, ifNoneMatch string) status_messages.OutputStatusMessageBoundary {
+	return &StatusMessagePresenter{w: w, ifNoneMatch: ifNoneMatch}
+}
+
+func (sp *StatusMessagePresenter) StatusResponse(response domain.StatusResponse, err error) {
+	common.HandleEnvelopeError(err, sp.w)
+	writeEnvelopeWithETag(sp.w, sp.ifNoneMatch, statusMaxAge, "Get status successfully", response)
+}
+
+func (sp *StatusMessagePresenter) StatusMessagesResponse(response []domain.StatusMessageResponse, err error) {
+	common.HandleEnvelopeError(err, sp.w)
+	common.WriteEnvelope(sp.w, http.StatusOK, "Get status messages successfully", response, nil)
+}
+
+func (sp *StatusMessagePresenter) StatusMessageResponse(response domain.StatusMessageResponse, err error) {
+	common.HandleEnvelopeError(err, sp.w)
+	common.WriteEnvelope(sp.w, http.StatusOK, "Saved status message successfully", response, nil)
+}
+
+func (sp *StatusMessagePresenter) DeleteStatusMessageResponse(response domain.DeleteStatusMessageResponse, err error) {
+	common.HandleEnvelopeError(err, sp.w)
+	common.WriteEnvelope(sp.w, http.StatusOK, "Removed status message successfully", response, nil)
+}
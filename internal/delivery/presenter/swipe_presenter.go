@@ -19,3 +19,13 @@ func (u SwipePresenter) SwipeResponse(response domain.SwipeResponse, err error)
 	common.HandleInternalServerError(err, u.w)
 	common.WriteJSONResponse(u.w, http.StatusOK, "Get users view successfully", response, 1)
 }
+
+func (u SwipePresenter) SwipeUndoResponse(response domain.SwipeUndoResponse, err error) {
+	common.HandleEnvelopeError(err, u.w)
+	common.WriteEnvelope(u.w, http.StatusOK, "Undo swipe successfully", response, nil)
+}
+
+func (u SwipePresenter) SuperLikeResponse(response domain.SwipeResponse, err error) {
+	common.HandleEnvelopeError(err, u.w)
+	common.WriteEnvelope(u.w, http.StatusOK, "Super like successfully", response, nil)
+}
@@ -16,17 +16,25 @@ func NewUserPresenter(w http.ResponseWriter) users.OutputUserBoundary {
 }
 
 func (u UserPresenter) UserViewsResponse(response []domain.UserViewsResponse, err error) {
-	common.HandleInternalServerError(err, u.w)
+	common.HandleEnvelopeError(err, u.w)
 	if response == nil {
-		common.WriteJSONResponse(u.w, http.StatusOK, "Get users view successfully", domain.UserViewNilResponse{
-			Message: "Your is swipe in maximum 10",
-		}, int64(len(response)))
+		common.WriteEnvelope(u.w, http.StatusOK, "Your is swipe in maximum 10", []domain.UserViewsResponse{}, &common.Pagination{Page: 1, Size: 0, Total: 0})
 	} else {
-		common.WriteJSONResponse(u.w, http.StatusOK, "Get users view successfully", response, int64(len(response)))
+		common.WriteEnvelope(u.w, http.StatusOK, "Get users view successfully", response, &common.Pagination{Page: 1, Size: len(response), Total: int64(len(response))})
 	}
 }
 
 func (u UserPresenter) PatchUserResponse(response domain.PatchUserResponse, err error) {
-	common.HandleInternalServerError(err, u.w)
-	common.WriteJSONResponse(u.w, http.StatusCreated, "Patch user successfully", response, int64(1))
+	common.HandleEnvelopeError(err, u.w)
+	common.WriteEnvelope(u.w, http.StatusCreated, "Patch user successfully", response, nil)
+}
+
+func (u UserPresenter) UserLocationResponse(response domain.UserLocationResponse, err error) {
+	common.HandleEnvelopeError(err, u.w)
+	common.WriteEnvelope(u.w, http.StatusOK, "Updated location successfully", response, nil)
+}
+
+func (u UserPresenter) ClearUserLocationResponse(err error) {
+	common.HandleEnvelopeError(err, u.w)
+	common.WriteEnvelope(u.w, http.StatusOK, "Cleared location successfully", nil, nil)
 }
@@ -0,0 +1,45 @@
+package domain
+
+// AccountImportRow is one account of a dump from another platform. PasswordHash is a bcrypt hash, an account without
+// one, or with ResetPassword, gets no usable password and sets one with forgot password before its first login
+type AccountImportRow struct {
+	Username      string `json:"username" validate:"required,safe-username"`
+	Email         string `json:"email" validate:"required,email,max=255"`
+	PasswordHash  string `json:"password_hash"`
+	ResetPassword bool   `json:"reset_password"`
+	Verified      bool   `json:"verified"`
+	FullName      string `json:"full_name" validate:"max=255"`
+	DateOfBirth   string `json:"date_of_birth" validate:"omitempty,datetime=2006-01-02,adult-age"`
+	Gender        string `json:"gender" validate:"max=5"`
+	Address       string `json:"address" validate:"max=255"`
+	Bio           string `json:"bio" validate:"max=5000"`
+}
+
+// AccountImportRequest is a parsed dump, DryRun validates and checks the rows against the database without creating any
+type AccountImportRequest struct {
+	Rows   []AccountImportRow
+	DryRun bool
+}
+
+// AccountImportRowResult tells why a row was not imported, Row counts the rows of the dump from 1
+type AccountImportRowResult struct {
+	Row      int    `json:"row"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Status   string `json:"status"`
+	Reason   string `json:"reason"`
+}
+
+// AccountImportResponse counts the rows by outcome, Created is what would be created on a dry run
+type AccountImportResponse struct {
+	Total         int                      `json:"total"`
+	Processed     int                      `json:"processed"`
+	Created       int                      `json:"created"`
+	ResetRequired int                      `json:"reset_required"`
+	Existing      int                      `json:"existing"`
+	Duplicates    int                      `json:"duplicates"`
+	Invalid       int                      `json:"invalid"`
+	Failed        int                      `json:"failed"`
+	DryRun        bool                     `json:"dry_run"`
+	Rejected      []AccountImportRowResult `json:"rejected"`
+}
@@ -14,6 +14,7 @@ type AccountDataResponse struct {
 	Bio         string     `json:"bio"`
 	Verified    bool       `json:"verified"`
 	DateOfBirth *time.Time `json:"date_of_birth"`
+	Photos      []string   `json:"photos"`
 }
 
 type AccountViewResponse struct {
@@ -28,11 +29,12 @@ type AccountResponse struct {
 }
 
 type ViewedAccountResponse struct {
-	AccountID int64   `json:"account_id"`
-	UserName  string  `json:"user_name"`
-	Email     string  `json:"email"`
-	Verified  bool    `json:"verified"`
-	FullName  *string `json:"full_name"`
+	AccountID int64    `json:"account_id"`
+	UserName  string   `json:"user_name"`
+	Email     string   `json:"email"`
+	Verified  bool     `json:"verified"`
+	FullName  *string  `json:"full_name"`
+	Photos    []string `json:"photos"`
 }
 
 type ViewedAccountRequest struct {
@@ -44,3 +46,21 @@ type ViewedAccount struct {
 	AccountIDView int64
 	UserIDView    int64
 }
+
+// AccountExportSectionDto is one table of the personal data export, the rows by column name
+type AccountExportSectionDto struct {
+	Name string
+	Rows []map[string]interface{}
+}
+
+type AccountExportResponse struct {
+	AccountID  int64                               `json:"account_id"`
+	ExportedAt string                              `json:"exported_at"`
+	Sections   map[string][]map[string]interface{} `json:"sections"`
+}
+
+type DeleteAccountResponse struct {
+	AccountID  int64  `json:"account_id"`
+	DeletedAt  string `json:"deleted_at"`
+	PurgeAfter string `json:"purge_after"`
+}
@@ -0,0 +1,108 @@
+package domain
+
+import "time"
+
+type TimelineEventDto struct {
+	EventType  string
+	OccurredAt time.Time
+	Detail     string
+}
+
+type TimelineEventResponse struct {
+	EventType  string `json:"event_type"`
+	OccurredAt string `json:"occurred_at"`
+	Detail     string `json:"detail"`
+}
+
+type AccountTimelineResponse struct {
+	AccountID int64                   `json:"account_id"`
+	Page      int                     `json:"page"`
+	Size      int                     `json:"size"`
+	Total     int64                   `json:"total"`
+	Events    []TimelineEventResponse `json:"events"`
+}
+
+// RectificationRequest fields left out are kept as they are
+type RectificationRequest struct {
+	Actor         string  `json:"actor"`
+	Justification string  `json:"justification"`
+	FullName      *string `json:"full_name"`
+	DateOfBirth   *string `json:"date_of_birth"`
+	Gender        *string `json:"gender"`
+	Address       *string `json:"address"`
+	Bio           *string `json:"bio"`
+}
+
+type FieldChange struct {
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+type AdminAuditLogDto struct {
+	AuditID         int64
+	Actor           string `validate:"required,max=255"`
+	Action          string `validate:"required"`
+	TargetAccountID int64  `validate:"required"`
+	Justification   string `validate:"required,min=10,max=1000"`
+	Changes         map[string]FieldChange
+}
+
+type RectificationResponse struct {
+	AccountID     int64                  `json:"account_id"`
+	AuditID       int64                  `json:"audit_id"`
+	ChangedFields map[string]FieldChange `json:"changed_fields"`
+	Message       string                 `json:"message"`
+}
+
+type JobStatusResponse struct {
+	Name           string `json:"name"`
+	Schedule       string `json:"schedule"`
+	Paused         bool   `json:"paused"`
+	Running        bool   `json:"running"`
+	LastStatus     string `json:"last_status"`
+	LastRunAt      string `json:"last_run_at,omitempty"`
+	LastDurationMs int64  `json:"last_duration_ms"`
+	LastError      string `json:"last_error,omitempty"`
+	NextRunAt      string `json:"next_run_at,omitempty"`
+}
+
+// GeoRestrictionMetricsResponse reasons look like country:KP or asn:AS16509, routes are the override prefixes
+// and * for the routes without one
+type GeoRestrictionMetricsResponse struct {
+	Enabled         bool             `json:"enabled"`
+	Blocked         int64            `json:"blocked"`
+	Flagged         int64            `json:"flagged"`
+	LookupFailures  int64            `json:"lookup_failures"`
+	BlockedByReason map[string]int64 `json:"blocked_by_reason"`
+	BlockedByRoute  map[string]int64 `json:"blocked_by_route"`
+}
+
+// RuntimeMetricsResponse is a fresh sample of this instance, warnings and dumps count the exceeded thresholds since
+// it started
+type RuntimeMetricsResponse struct {
+	SampledAt             string `json:"sampled_at"`
+	Goroutines            int    `json:"goroutines"`
+	HeapAllocBytes        uint64 `json:"heap_alloc_bytes"`
+	HeapObjects           uint64 `json:"heap_objects"`
+	NumGC                 uint32 `json:"num_gc"`
+	DBOpenConnections     int    `json:"db_open_connections"`
+	DBInUse               int    `json:"db_in_use"`
+	DBIdle                int    `json:"db_idle"`
+	DBWaitCount           int64  `json:"db_wait_count"`
+	RedisTotalConnections uint32 `json:"redis_total_connections"`
+	RedisIdleConnections  uint32 `json:"redis_idle_connections"`
+	RedisTimeouts         uint32 `json:"redis_timeouts"`
+	Warnings              int64  `json:"warnings"`
+	Dumps                 int64  `json:"dumps"`
+	LastWarnAt            string `json:"last_warn_at,omitempty"`
+	LastDump              string `json:"last_dump,omitempty"`
+}
+
+type LogLevelRequest struct {
+	Level string `json:"level"`
+}
+
+type LogLevelResponse struct {
+	Module string `json:"module"`
+	Level  string `json:"level"`
+}
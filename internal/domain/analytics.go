@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+type QuotaUsageDto struct {
+	Date              time.Time
+	Tier              string
+	Accounts          int64
+	ActiveAccounts    int64
+	TotalSwipes       int64
+	TotalLikes        int64
+	ExhaustedAccounts int64
+}
+
+type QuotaUsageResponse struct {
+	Date               string  `json:"date"`
+	Tier               string  `json:"tier"`
+	Accounts           int64   `json:"accounts"`
+	ActiveAccounts     int64   `json:"active_accounts"`
+	AvgSwipesPerDay    float64 `json:"avg_swipes_per_day"`
+	AvgLikesPerDay     float64 `json:"avg_likes_per_day"`
+	ExhaustedAccounts  int64   `json:"exhausted_accounts"`
+	ExhaustionRatePerc float64 `json:"exhaustion_rate_percent"`
+}
@@ -6,18 +6,90 @@ type LoginRequest struct {
 	Username string `json:"username"`
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	Timezone string `json:"timezone"`
+	// ClientAddress is filled by the handler for login throttling, never read from the body
+	ClientAddress string `json:"-"`
 }
 
 type LoginResponse struct {
-	Username    string `json:"username"`
-	Email       string `json:"email"`
-	AccessToken string `json:"access_token"`
+	Username     string `json:"username"`
+	Email        string `json:"email"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	// NewAccount is set when a social login created the account, the app asks for the rest of the profile then
+	NewAccount bool `json:"new_account,omitempty"`
+	// Region is the home region of the account, the app connects to its base url. Unset in a single region
+	Region *RegionResponse `json:"region,omitempty"`
+}
+
+// RegionResponse is a deployment of the service, the api paths go after the base url
+type RegionResponse struct {
+	Name    string `json:"name"`
+	BaseURL string `json:"base_url"`
+}
+
+// OAuthLoginRequest carries the ID token the app got from the sign in SDK of Google or Facebook
+type OAuthLoginRequest struct {
+	IDToken  string `json:"id_token"`
+	Nonce    string `json:"nonce"`
+	Timezone string `json:"timezone"`
+}
+
+// RefreshTokenRequest exchanges a refresh token for a new access token, the refresh token is used once
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type RefreshTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// ScopedTokenRequest mints an access token limited to the scopes, e.g. ["chat:read"] for a websocket only client.
+// ExpiresInMinutes defaults to 60 and is at most 1440
+type ScopedTokenRequest struct {
+	Scopes           []string `json:"scopes"`
+	ExpiresInMinutes int      `json:"expires_in_minutes"`
+}
+
+type ScopedTokenResponse struct {
+	AccessToken string   `json:"access_token"`
+	Scopes      []string `json:"scopes"`
+	ExpiresAt   string   `json:"expires_at"`
+}
+
+// IntrospectionResponse follows RFC 7662, an inactive token only has active false so it reveals nothing else
+type IntrospectionResponse struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	Sub       string `json:"sub,omitempty"`
+	Exp       int64  `json:"exp,omitempty"`
+	Iat       int64  `json:"iat,omitempty"`
+	AccountID int64  `json:"account_id,omitempty"`
+	UserID    int64  `json:"user_id,omitempty"`
+	Email     string `json:"email,omitempty"`
+	SessionID string `json:"sid,omitempty"`
 }
 
 type LogoutResponse struct {
 	Message string `json:"message"`
 }
 
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+// ResetPasswordRequest sets a new password with the token sent by forgot password, the token is used once
+type ResetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+type PasswordResetResponse struct {
+	Message string `json:"message"`
+}
+
 type RegisterRequest struct {
 	Email    string `json:"email"`
 	Username string `json:"username"`
@@ -38,18 +110,38 @@ type AccountDto struct {
 	Password string
 }
 
+// SignUpAccountDto is a new account. Only new accounts are held to the username rules, existing usernames can
+// still log in with an AccountDto
+type SignUpAccountDto struct {
+	Username string `validate:"required,safe-username"`
+	Email    string `validate:"required,email,max=255"`
+	Password string
+}
+
+// Accounts and AccountDetail are mapped from record.AccountRecord, the map tag names the record field
 type Accounts struct {
-	AccountId int64
+	AccountId int64 `map:"AccountID"`
 	Email     string
 	Username  string
-	Password  string
-	CreateAt  time.Time
-	UpdateAt  time.Time
+	Password  string    `map:"PasswordHash"`
+	CreateAt  time.Time `map:"CreatedAt"`
+	UpdateAt  time.Time `map:"UpdatedAt"`
 }
 
 type AccountDetail struct {
-	AccountId int64
+	AccountId int64 `map:"AccountID"`
 	Email     string
 	Username  string
 	Verified  bool
 }
+
+// LoginThrottleMetrics counts throttling decisions since the process started
+type LoginThrottleMetrics struct {
+	FailuresRecorded    int64 `json:"failures_recorded"`
+	BlockedByCredential int64 `json:"blocked_by_credential"`
+	BlockedByAddress    int64 `json:"blocked_by_address"`
+	CredentialLimit     int64 `json:"credential_limit"`
+	AddressLimit        int64 `json:"address_limit"`
+	WindowSeconds       int64 `json:"window_seconds"`
+	ThrottleUnavailable int64 `json:"throttle_unavailable"`
+}
@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+type ReportAccountRequest struct {
+	Reason  string `json:"reason"`
+	Details string `json:"details"`
+}
+
+type BlockedAccountDto struct {
+	AccountID int64
+	Username  string
+	BlockedAt time.Time
+}
+
+type ReportDto struct {
+	ReportID          int64
+	ReporterAccountID int64
+	ReportedAccountID int64
+	Reason            string
+	Details           string
+	CreatedAt         time.Time
+}
+
+type BlockedAccountResponse struct {
+	AccountID int64  `json:"account_id"`
+	Username  string `json:"username"`
+	BlockedAt string `json:"blocked_at"`
+}
+
+type BlockedAccountsResponse struct {
+	Accounts     []BlockedAccountResponse `json:"accounts"`
+	TotalBlocked int64                    `json:"total_blocked"`
+}
+
+type ReportResponse struct {
+	ReportID          int64  `json:"report_id"`
+	ReporterAccountID int64  `json:"reporter_account_id"`
+	ReportedAccountID int64  `json:"reported_account_id"`
+	Reason            string `json:"reason"`
+	Details           string `json:"details"`
+	ReportedAt        string `json:"reported_at"`
+}
+
+type ReportsResponse struct {
+	Page    int              `json:"page"`
+	Size    int              `json:"size"`
+	Total   int64            `json:"total"`
+	Reports []ReportResponse `json:"reports"`
+}
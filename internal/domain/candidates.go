@@ -0,0 +1,52 @@
+package domain
+
+// CandidateFilterDto narrows the candidates, an empty Genders takes every gender and a zero MaxDistanceKm the
+// discovery radius
+type CandidateFilterDto struct {
+	MinAge        int      `validate:"min=18,max=100"`
+	MaxAge        int      `validate:"min=18,max=100,gtefield=MinAge"`
+	Genders       []string `validate:"dive,required,max=5"`
+	MaxDistanceKm int      `validate:"min=0,max=500"`
+}
+
+type CandidateDto struct {
+	AccountID         int64
+	UserID            int64
+	Verified          bool
+	Username          string
+	FullName          *string
+	Gender            string
+	Bio               string
+	Age               int
+	Address           string
+	DistanceKm        *float64
+	ActivityScore     float64
+	CompletenessScore float64
+	SuperLiked        bool
+	Score             float64
+}
+
+type CandidateResponse struct {
+	UserID    int64    `json:"user_id"`
+	AccountID int64    `json:"account_id"`
+	FullName  *string  `json:"full_name"`
+	Username  string   `json:"username"`
+	Photos    []string `json:"photos"`
+	Age       int      `json:"age"`
+	Gender    string   `json:"gender"`
+	Address   string   `json:"address"`
+	Bio       string   `json:"bio"`
+	Verified  bool     `json:"verified"`
+	// DistanceKm is rounded up to whole km so the exact location of the candidate is not revealed
+	DistanceKm *int `json:"distance_km,omitempty"`
+	// SuperLiked is true when the candidate super liked the viewer, these candidates come first
+	SuperLiked bool    `json:"super_liked"`
+	Score      float64 `json:"score"`
+}
+
+type CandidatesResponse struct {
+	Page       int                 `json:"page"`
+	Size       int                 `json:"size"`
+	Total      int64               `json:"total"`
+	Candidates []CandidateResponse `json:"candidates"`
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ClientConfigBundleRequest publishes a new version of a bundle, payload is any JSON object the app understands,
+// e.g. ranking weights or smart-reply templates
+type ClientConfigBundleRequest struct {
+	Name           string          `json:"name"`
+	Payload        json.RawMessage `json:"payload"`
+	MinAppVersion  string          `json:"min_app_version"`
+	MaxAppVersion  *string         `json:"max_app_version"`
+	RolloutPercent int             `json:"rollout_percent"`
+}
+
+// ClientConfigRolloutRequest changes who receives an existing version, omitted fields keep their value
+type ClientConfigRolloutRequest struct {
+	MinAppVersion  *string `json:"min_app_version"`
+	MaxAppVersion  *string `json:"max_app_version"`
+	RolloutPercent *int    `json:"rollout_percent"`
+}
+
+type ClientConfigBundleDto struct {
+	BundleID       int64
+	Name           string `validate:"required,max=64"`
+	Version        int64
+	MinAppVersion  string
+	MaxAppVersion  *string
+	RolloutPercent int             `validate:"gte=0,lte=100"`
+	Payload        json.RawMessage `validate:"required"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type ClientConfigBundleResponse struct {
+	BundleID       int64           `json:"bundle_id"`
+	Name           string          `json:"name"`
+	Version        int64           `json:"version"`
+	MinAppVersion  string          `json:"min_app_version"`
+	MaxAppVersion  *string         `json:"max_app_version,omitempty"`
+	RolloutPercent int             `json:"rollout_percent"`
+	Payload        json.RawMessage `json:"payload"`
+	CreatedAt      string          `json:"created_at"`
+	UpdatedAt      string          `json:"updated_at"`
+}
+
+// ClientConfigResponse is what one app install receives, at most one version per bundle name
+type ClientConfigResponse struct {
+	Bundles []ClientConfigItemResponse `json:"bundles"`
+}
+
+type ClientConfigItemResponse struct {
+	Name    string          `json:"name"`
+	Version int64           `json:"version"`
+	Payload json.RawMessage `json:"payload"`
+}
@@ -0,0 +1,21 @@
+package domain
+
+type ContactHashesRequest struct {
+	Hashes []string `json:"hashes"`
+}
+
+// ContactHashesDto hashes are the hex SHA-256 of the normalized phone number, see common.PhoneNumberEncoder
+type ContactHashesDto struct {
+	AccountID int64    `validate:"required"`
+	Hashes    []string `validate:"required,max=5000,dive,len=64,hexadecimal"`
+}
+
+type ContactExclusionsResponse struct {
+	Hashes        []string `json:"hashes"`
+	TotalExcluded int64    `json:"total_excluded"`
+}
+
+type ContactExclusionsUpdateResponse struct {
+	TotalChanged int64  `json:"total_changed"`
+	Message      string `json:"message"`
+}
@@ -11,7 +11,10 @@ type DailyQuotasDto struct {
 	SwipeCount     int
 }
 
+// DailyQuotaResponse total_quotas is the swipes left today, or Unlimited while a subscription includes unlimited swipes
 type DailyQuotaResponse struct {
-	TotalQuotas string `json:"total_quotas"`
-	SwipeCount  int    `json:"swipe_count"`
+	TotalQuotas    string  `json:"total_quotas"`
+	SwipeCount     int     `json:"swipe_count"`
+	Unlimited      bool    `json:"unlimited"`
+	UnlimitedUntil *string `json:"unlimited_until,omitempty"`
 }
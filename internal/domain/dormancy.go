@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// Dormancy states in the order an inactive account moves through them, an active account has no state
+const (
+	DormancyStateActive = ""
+	DormancyStateWarned = "warned"
+	DormancyStateHidden = "hidden"
+	DormancyStatePurged = "purged"
+	// DormancyStateDeleted the owner deleted the account, it is hidden until the deletion grace passed and then purged
+	DormancyStateDeleted = "deleted"
+)
+
+type AccountDormancyDto struct {
+	AccountID    int64
+	State        string
+	LastActiveAt time.Time
+	UpdatedAt    time.Time
+}
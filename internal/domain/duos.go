@@ -0,0 +1,69 @@
+package domain
+
+import "time"
+
+type DuoInviteRequest struct {
+	PartnerAccountID int64 `json:"partner_account_id"`
+}
+
+type DuoSwipeRequest struct {
+	DuoID      int64  `json:"duo_id"`
+	ActionType string `json:"action_type"`
+}
+
+type DuoDto struct {
+	DuoID              int64
+	AccountIDLow       int64
+	AccountIDHigh      int64
+	InvitedByAccountID int64
+	State              string
+	CreatedAt          time.Time
+	LinkedAt           *time.Time
+}
+
+type DuoMemberDto struct {
+	AccountID int64
+	FullName  string
+	Age       int
+	Gender    string
+	Bio       string
+}
+
+type DuoCandidateDto struct {
+	DuoID   int64
+	Members []DuoMemberDto
+}
+
+type DuoMatchDto struct {
+	MatchID   int64
+	Members   []DuoMemberDto
+	CreatedAt time.Time
+}
+
+type DuoResponse struct {
+	DuoID              int64  `json:"duo_id"`
+	PartnerAccountID   int64  `json:"partner_account_id"`
+	InvitedByAccountID int64  `json:"invited_by_account_id"`
+	State              string `json:"state"`
+	CreatedAt          string `json:"created_at"`
+	LinkedAt           string `json:"linked_at,omitempty"`
+}
+
+type DuoMemberResponse struct {
+	AccountID int64  `json:"account_id"`
+	FullName  string `json:"full_name"`
+	Age       int    `json:"age"`
+	Gender    string `json:"gender"`
+	Bio       string `json:"bio"`
+}
+
+type DuoCandidateResponse struct {
+	DuoID   int64               `json:"duo_id"`
+	Members []DuoMemberResponse `json:"members"`
+}
+
+type DuoMatchResponse struct {
+	MatchID   int64               `json:"match_id"`
+	Members   []DuoMemberResponse `json:"members"`
+	MatchedAt string              `json:"matched_at"`
+}
@@ -0,0 +1,51 @@
+package domain
+
+import "time"
+
+// Event states, a room can only be joined and used while the event is live
+const (
+	EventStateUpcoming = "upcoming"
+	EventStateLive     = "live"
+	EventStateEnded    = "ended"
+)
+
+// EventRequest schedules a virtual event, times are RFC 3339
+type EventRequest struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	StartsAt    time.Time `json:"starts_at"`
+	EndsAt      time.Time `json:"ends_at"`
+}
+
+type EventConnectRequest struct {
+	AccountID int64 `json:"account_id"`
+}
+
+type EventDto struct {
+	EventID     int64
+	Title       string `validate:"required,max=100"`
+	Description string `validate:"max=1000"`
+	StartsAt    time.Time
+	EndsAt      time.Time
+	RoomMatchID int64
+	CreatedAt   time.Time
+}
+
+type EventResponse struct {
+	EventID     int64  `json:"event_id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	State       string `json:"state"`
+	StartsAt    string `json:"starts_at"`
+	EndsAt      string `json:"ends_at"`
+	RoomMatchID int64  `json:"room_match_id,omitempty"`
+}
+
+// EventConnectionResponse has the match once both guests chose each other, the match outlives the event
+type EventConnectionResponse struct {
+	EventID   int64  `json:"event_id"`
+	AccountID int64  `json:"account_id"`
+	Matched   bool   `json:"matched"`
+	MatchID   int64  `json:"match_id,omitempty"`
+	Message   string `json:"message"`
+}
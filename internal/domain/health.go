@@ -0,0 +1,44 @@
+package domain
+
+// ReadinessResponse tells a load balancer or an orchestrator whether this instance can take traffic
+type ReadinessResponse struct {
+	Ready  bool                `json:"ready"`
+	Schema SchemaDriftResponse `json:"schema"`
+}
+
+// SchemaDriftResponse compares the database with the migrations and critical columns this build expects
+type SchemaDriftResponse struct {
+	Drifted           bool     `json:"drifted"`
+	ExpectedVersion   int      `json:"expected_version"`
+	AppliedVersion    int      `json:"applied_version"`
+	PendingMigrations []string `json:"pending_migrations,omitempty"`
+	EditedMigrations  []string `json:"edited_migrations,omitempty"`
+	UnknownMigrations []int    `json:"unknown_migrations,omitempty"`
+	MissingTables     []string `json:"missing_tables,omitempty"`
+	MissingColumns    []string `json:"missing_columns,omitempty"`
+	MismatchedColumns []string `json:"mismatched_columns,omitempty"`
+	CheckedAt         string   `json:"checked_at"`
+}
+
+// ServiceStatusResponse is the state of the service and of each dependency for the status page, Status is down when
+// the database or redis is down and degraded when any component is
+type ServiceStatusResponse struct {
+	Status        string                    `json:"status"`
+	StartedAt     string                    `json:"started_at"`
+	UptimeSeconds int64                     `json:"uptime_seconds"`
+	WindowMinutes int                       `json:"window_minutes"`
+	Components    []ComponentStatusResponse `json:"components"`
+}
+
+// ComponentStatusResponse counts the checks and failures of one dependency over the window, LatencyMs is the time of
+// the last probe and is 0 for a component that is not probed
+type ComponentStatusResponse struct {
+	Name          string  `json:"name"`
+	State         string  `json:"state"`
+	Checks        int     `json:"checks"`
+	Failures      int     `json:"failures"`
+	ErrorRate     float64 `json:"error_rate"`
+	LatencyMs     int64   `json:"latency_ms"`
+	LastCheckedAt string  `json:"last_checked_at,omitempty"`
+	LastFailureAt string  `json:"last_failure_at,omitempty"`
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+type HideAccountRequest struct {
+	AccountID int64 `json:"account_id"`
+}
+
+type HiddenAccountDto struct {
+	AccountID int64
+	Username  string
+	HiddenAt  time.Time
+}
+
+type HiddenAccountResponse struct {
+	AccountID int64  `json:"account_id"`
+	Username  string `json:"username"`
+	HiddenAt  string `json:"hidden_at"`
+}
+
+type HiddenAccountsResponse struct {
+	Accounts    []HiddenAccountResponse `json:"accounts"`
+	TotalHidden int64                   `json:"total_hidden"`
+}
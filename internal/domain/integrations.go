@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+type IntegrationDto struct {
+	AccountID    int64
+	Provider     string
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
+	LastSyncedAt *time.Time
+}
+
+type ImportedContentDto struct {
+	Provider    string
+	ContentType string
+	ExternalID  string
+	Value       string
+	URL         string
+	ImportedAt  time.Time
+}
+
+type ConnectIntegrationResponse struct {
+	Provider     string `json:"provider"`
+	AuthorizeURL string `json:"authorize_url"`
+}
+
+type ImportedContentResponse struct {
+	Source      string `json:"source"`
+	ContentType string `json:"content_type"`
+	Value       string `json:"value"`
+	URL         string `json:"url"`
+	ImportedAt  string `json:"imported_at"`
+}
+
+type DisconnectIntegrationResponse struct {
+	Provider string `json:"provider"`
+	Message  string `json:"message"`
+}
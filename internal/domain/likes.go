@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// LikeReceivedDto is an account that liked the viewer, LikedOn is the day of its latest like
+type LikeReceivedDto struct {
+	AccountID  int64
+	LikedOn    time.Time
+	SuperLiked bool
+}
+
+type LikeReceivedResponse struct {
+	UserID    int64    `json:"user_id"`
+	AccountID int64    `json:"account_id"`
+	FullName  *string  `json:"full_name"`
+	Photos    []string `json:"photos"`
+	Age       int      `json:"age"`
+	Gender    string   `json:"gender"`
+	Address   string   `json:"address"`
+	Bio       string   `json:"bio"`
+	// SuperLiked is true when the like was a super like, these come first
+	SuperLiked bool   `json:"super_liked"`
+	LikedOn    string `json:"liked_on"`
+}
+
+type LikesReceivedResponse struct {
+	Page  int                    `json:"page"`
+	Size  int                    `json:"size"`
+	Total int64                  `json:"total"`
+	Likes []LikeReceivedResponse `json:"likes"`
+}
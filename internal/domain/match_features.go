@@ -0,0 +1,33 @@
+package domain
+
+// MatchScoresRequest is a batch of predictions from the external match-prediction model, scores are 0 to 1
+type MatchScoresRequest struct {
+	ModelVersion string           `json:"model_version"`
+	Scores       []MatchScoreItem `json:"scores"`
+}
+
+type MatchScoreItem struct {
+	AccountID          int64   `json:"account_id"`
+	CandidateAccountID int64   `json:"candidate_account_id"`
+	Score              float64 `json:"score"`
+}
+
+type MatchScoreDto struct {
+	AccountID          int64   `validate:"required"`
+	CandidateAccountID int64   `validate:"required,nefield=AccountID"`
+	Score              float64 `validate:"gte=0,lte=1"`
+	ModelVersion       string  `validate:"required,max=64"`
+}
+
+type MatchScoresResponse struct {
+	ModelVersion string `json:"model_version"`
+	Accepted     int    `json:"accepted"`
+}
+
+// MatchFeatureRunDto counts the rows one feature extraction run wrote and removed
+type MatchFeatureRunDto struct {
+	AccountFeatures int64
+	PairFeatures    int64
+	StaleFeatures   int64
+	ExpiredScores   int64
+}
@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// MatchDto is a match seen from AccountID, Kind is pair, duo or group and MatchedAccountID is 0 unless it is a pair.
+// ParticipantAccountIDs are every account currently in the conversation, AccountID included
+type MatchDto struct {
+	MatchID               int64
+	Kind                  string
+	Title                 string
+	ExpiresAt             *time.Time
+	AccountID             int64
+	MatchedAccountID      int64
+	ParticipantAccountIDs []int64
+	CreatedAt             time.Time
+}
+
+type MatchViewDto struct {
+	MatchID          int64
+	MatchedAccountID int64
+	FullName         string
+	Age              int
+	Gender           string
+	Bio              string
+	CreatedAt        time.Time
+}
+
+type MatchResponse struct {
+	MatchID          int64  `json:"match_id"`
+	MatchedAccountID int64  `json:"matched_account_id"`
+	FullName         string `json:"full_name"`
+	Age              int    `json:"age"`
+	Gender           string `json:"gender"`
+	Bio              string `json:"bio"`
+	MatchedAt        string `json:"matched_at"`
+}
@@ -0,0 +1,73 @@
+package domain
+
+import "time"
+
+type ChatMessageRequest struct {
+	Body string `json:"body"`
+}
+
+// ChatSocketRequest is a frame sent by the app over the chat websocket
+type ChatSocketRequest struct {
+	MatchID int64  `json:"match_id"`
+	Body    string `json:"body"`
+}
+
+// ChatReadRequest marks the conversation read up to the message, a message id 0 marks every message read
+type ChatReadRequest struct {
+	MessageID int64 `json:"message_id"`
+}
+
+type ChatMessageDto struct {
+	MessageID       int64
+	MatchID         int64 `validate:"required"`
+	SenderAccountID int64 `validate:"required"`
+	SenderUsername  string
+	SenderFullName  string
+	Body            string `validate:"required,max=2000"`
+	CreatedAt       time.Time
+}
+
+type ChatParticipantDto struct {
+	AccountID         int64
+	Username          string
+	FullName          string
+	LastReadMessageID int64
+	JoinedAt          time.Time
+}
+
+type ChatMessageResponse struct {
+	MessageID       int64  `json:"message_id"`
+	MatchID         int64  `json:"match_id"`
+	SenderAccountID int64  `json:"sender_account_id"`
+	SenderUsername  string `json:"sender_username"`
+	SenderFullName  string `json:"sender_full_name"`
+	Body            string `json:"body"`
+	SentAt          string `json:"sent_at"`
+}
+
+// ReplicatedMessageRequest is a message another region stored, for the accounts connected to this one
+type ReplicatedMessageRequest struct {
+	AccountIDs []int64             `json:"account_ids"`
+	Message    ChatMessageResponse `json:"message"`
+}
+
+type ReplicatedMessageResponse struct {
+	Accounts int `json:"accounts"`
+}
+
+type ChatParticipantResponse struct {
+	AccountID         int64  `json:"account_id"`
+	Username          string `json:"username"`
+	FullName          string `json:"full_name"`
+	LastReadMessageID int64  `json:"last_read_message_id"`
+	JoinedAt          string `json:"joined_at"`
+}
+
+// ChatParticipantsResponse is the conversation seen from the account, UnreadCount is its own unread messages
+type ChatParticipantsResponse struct {
+	MatchID      int64                     `json:"match_id"`
+	Kind         string                    `json:"kind"`
+	Title        string                    `json:"title"`
+	UnreadCount  int64                     `json:"unread_count"`
+	Participants []ChatParticipantResponse `json:"participants"`
+}
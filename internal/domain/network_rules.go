@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// NetworkRuleRequest adds a CIDR range to the deny or allow list of a route group, a single address is a /32 or /128 range
+type NetworkRuleRequest struct {
+	List       string `json:"list"`
+	RouteGroup string `json:"route_group"`
+	CIDR       string `json:"cidr"`
+	Note       string `json:"note"`
+}
+
+type NetworkRuleDto struct {
+	RuleID     int64
+	List       string `validate:"required,oneof=deny allow"`
+	RouteGroup string `validate:"required,oneof=global admin metrics internal"`
+	CIDR       string `validate:"required,max=64"`
+	Note       string `validate:"max=255"`
+	CreatedAt  time.Time
+}
+
+type NetworkRuleResponse struct {
+	RuleID     int64  `json:"rule_id"`
+	List       string `json:"list"`
+	RouteGroup string `json:"route_group"`
+	CIDR       string `json:"cidr"`
+	Note       string `json:"note"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type DeleteNetworkRuleResponse struct {
+	RuleID  int64  `json:"rule_id"`
+	Message string `json:"message"`
+}
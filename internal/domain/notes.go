@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+type NoteRequest struct {
+	TargetAccountID int64  `json:"target_account_id"`
+	Content         string `json:"content"`
+}
+
+type NoteDto struct {
+	NoteID          int64
+	OwnerAccountID  int64  `validate:"required"`
+	TargetAccountID int64  `validate:"required"`
+	Content         string `validate:"required,max=1000"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+type NoteResponse struct {
+	NoteID          int64  `json:"note_id"`
+	TargetAccountID int64  `json:"target_account_id"`
+	Content         string `json:"content"`
+	CreatedAt       string `json:"created_at"`
+	UpdatedAt       string `json:"updated_at"`
+}
+
+type DeleteNoteResponse struct {
+	NoteID  int64  `json:"note_id"`
+	Message string `json:"message"`
+}
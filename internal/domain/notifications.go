@@ -0,0 +1,76 @@
+package domain
+
+import "time"
+
+type RegisterDeviceRequest struct {
+	Provider string `json:"provider"`
+	Token    string `json:"token"`
+}
+
+type DeviceDto struct {
+	DeviceID  int64
+	AccountID int64
+	Provider  string
+	Token     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NotificationDto is what an account is told about, Kind lets the app open the right screen with the ids in Data
+type NotificationDto struct {
+	Kind  string
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+type PushDto struct {
+	PushID       int64
+	AccountID    int64
+	Notification NotificationDto
+	Attempts     int
+}
+
+type DeviceResponse struct {
+	DeviceID     int64  `json:"device_id"`
+	Provider     string `json:"provider"`
+	RegisteredAt string `json:"registered_at"`
+}
+
+// FeedNotificationDto is an entry of the in-app feed, ReadAt is nil while it is unread
+type FeedNotificationDto struct {
+	NotificationID int64
+	AccountID      int64
+	Notification   NotificationDto
+	ReadAt         *time.Time
+	CreatedAt      time.Time
+}
+
+// MarkNotificationsReadRequest no id marks every notification of the account read
+type MarkNotificationsReadRequest struct {
+	NotificationIDs []int64 `json:"notification_ids"`
+}
+
+type NotificationResponse struct {
+	NotificationID int64             `json:"notification_id"`
+	Kind           string            `json:"kind"`
+	Title          string            `json:"title"`
+	Body           string            `json:"body"`
+	Data           map[string]string `json:"data"`
+	Read           bool              `json:"read"`
+	ReadAt         string            `json:"read_at,omitempty"`
+	CreatedAt      string            `json:"created_at"`
+}
+
+type NotificationsResponse struct {
+	Page          int                    `json:"page"`
+	Size          int                    `json:"size"`
+	Total         int64                  `json:"total"`
+	UnreadCount   int64                  `json:"unread_count"`
+	Notifications []NotificationResponse `json:"notifications"`
+}
+
+type NotificationsReadResponse struct {
+	Marked      int64 `json:"marked"`
+	UnreadCount int64 `json:"unread_count"`
+}
@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// OutboxEventDto is a domain event as the relayer publishes it, Payload is the JSON of one of the events below
+type OutboxEventDto struct {
+	EventID     int64
+	Kind        string
+	AggregateID int64
+	Payload     string
+	Attempts    int
+	CreatedAt   time.Time
+}
+
+// AccountRegisteredEvent is written when an account is created, by sign up, a social login or an import
+type AccountRegisteredEvent struct {
+	AccountID int64  `json:"account_id"`
+	Username  string `json:"username"`
+	Provider  string `json:"provider"`
+}
+
+// SwipeRecordedEvent is written for every stored swipe and super like, Action is left, right or super_like
+type SwipeRecordedEvent struct {
+	SwipeID         int64  `json:"swipe_id"`
+	AccountID       int64  `json:"account_id"`
+	TargetAccountID int64  `json:"target_account_id"`
+	Action          string `json:"action"`
+}
+
+// MatchCreatedEvent is written when a like back completes a match
+type MatchCreatedEvent struct {
+	MatchID    int64   `json:"match_id"`
+	AccountIDs []int64 `json:"account_ids"`
+}
@@ -37,7 +37,27 @@ type PurchasePackageResponse struct {
 	Message   string  `json:"message"`
 }
 
-type AccountPurchasePackage struct {
-	AccountID int64
-	ExpiresIn time.Time
+// PremiumPurchaseRequest buys a package at its current price, a price sent along is checked so a client
+// showing an outdated price is not charged a different amount
+type PremiumPurchaseRequest struct {
+	PackageID int64    `json:"package_id"`
+	Price     *float64 `json:"price"`
+}
+
+// EntitlementsDto is what the subscriptions running for an account grant
+type EntitlementsDto struct {
+	Premium         bool
+	UnlimitedSwipes bool
+	VerifiedBadge   bool
+	SwipeUndo       bool
+	LikesReceived   bool
+	PackageID       int64
+	ExpiresAt       *time.Time
+}
+
+type PremiumStatusResponse struct {
+	Premium      bool     `json:"premium"`
+	Entitlements []string `json:"entitlements"`
+	PackageID    *int64   `json:"package_id,omitempty"`
+	ExpiresAt    *string  `json:"expires_at,omitempty"`
 }
@@ -0,0 +1,99 @@
+package domain
+
+import "time"
+
+// PasskeyCredentialJSON is a PublicKeyCredential as serialized by its toJSON(), binary fields are base64url
+type PasskeyCredentialJSON struct {
+	ID       string                   `json:"id"`
+	RawID    string                   `json:"rawId"`
+	Type     string                   `json:"type"`
+	Response PasskeyAuthenticatorJSON `json:"response"`
+}
+
+// PasskeyAuthenticatorJSON holds the attestation fields after create() or the assertion fields after get()
+type PasskeyAuthenticatorJSON struct {
+	ClientDataJSON    string `json:"clientDataJSON"`
+	AttestationObject string `json:"attestationObject,omitempty"`
+	AuthenticatorData string `json:"authenticatorData,omitempty"`
+	Signature         string `json:"signature,omitempty"`
+	UserHandle        string `json:"userHandle,omitempty"`
+}
+
+type PasskeyRegisterRequest struct {
+	Name       string                `json:"name"`
+	Credential PasskeyCredentialJSON `json:"credential"`
+}
+
+type PasskeyLoginRequest struct {
+	Timezone   string                `json:"timezone"`
+	Credential PasskeyCredentialJSON `json:"credential"`
+}
+
+type PasskeyDto struct {
+	CredentialID []byte
+	AccountID    int64  `validate:"required"`
+	Name         string `validate:"required,max=100"`
+	PublicKey    []byte `validate:"required"`
+	SignCount    uint32
+	CreatedAt    time.Time
+	LastUsedAt   *time.Time
+}
+
+// PasskeyCreationOptions is the PublicKeyCredentialCreationOptionsJSON given to navigator.credentials.create()
+type PasskeyCreationOptions struct {
+	Challenge              string                        `json:"challenge"`
+	RP                     PasskeyRelyingParty           `json:"rp"`
+	User                   PasskeyUser                   `json:"user"`
+	PubKeyCredParams       []PasskeyCredentialParam      `json:"pubKeyCredParams"`
+	Timeout                int64                         `json:"timeout"`
+	ExcludeCredentials     []PasskeyCredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection PasskeyAuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                        `json:"attestation"`
+}
+
+// PasskeyRequestOptions is the PublicKeyCredentialRequestOptionsJSON given to navigator.credentials.get(),
+// no credentials are listed so the authenticator offers its discoverable passkeys for the relying party
+type PasskeyRequestOptions struct {
+	Challenge        string `json:"challenge"`
+	RPID             string `json:"rpId"`
+	Timeout          int64  `json:"timeout"`
+	UserVerification string `json:"userVerification"`
+}
+
+type PasskeyRelyingParty struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type PasskeyUser struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+type PasskeyCredentialParam struct {
+	Type string `json:"type"`
+	Alg  int64  `json:"alg"`
+}
+
+type PasskeyCredentialDescriptor struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+type PasskeyAuthenticatorSelection struct {
+	ResidentKey      string `json:"residentKey"`
+	UserVerification string `json:"userVerification"`
+}
+
+type PasskeyResponse struct {
+	CredentialID string `json:"credential_id"`
+	Name         string `json:"name"`
+	CreatedAt    string `json:"created_at"`
+	LastUsedAt   string `json:"last_used_at,omitempty"`
+}
+
+type DeletePasskeyResponse struct {
+	CredentialID string `json:"credential_id"`
+	Message      string `json:"message"`
+}
@@ -0,0 +1,76 @@
+package domain
+
+import "time"
+
+// PhotoUpload is the file of an upload, ContentType is sniffed from the bytes and not taken from the client
+type PhotoUpload struct {
+	ContentType string
+	Data        []byte
+}
+
+type PhotoDto struct {
+	PhotoID     int64
+	AccountID   int64
+	StorageKey  string
+	ContentType string
+	SizeBytes   int64
+	Position    int
+	Primary     bool
+	CreatedAt   time.Time
+	DeletedAt   *time.Time
+}
+
+// ReorderPhotosRequest lists every photo of the account in the new order
+type ReorderPhotosRequest struct {
+	PhotoIDs []int64 `json:"photo_ids"`
+}
+
+type PhotoResponse struct {
+	PhotoID   int64  `json:"photo_id"`
+	URL       string `json:"url"`
+	Position  int    `json:"position"`
+	Primary   bool   `json:"primary"`
+	CreatedAt string `json:"created_at"`
+}
+
+// TrashedPhotoResponse is a deleted photo that can still be restored until PurgeAt
+type TrashedPhotoResponse struct {
+	PhotoID   int64  `json:"photo_id"`
+	URL       string `json:"url"`
+	CreatedAt string `json:"created_at"`
+	DeletedAt string `json:"deleted_at"`
+	PurgeAt   string `json:"purge_at"`
+}
+
+// SmartPhotosRequest turns the rotation of the lead photo on or off
+type SmartPhotosRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+type PhotoPerformanceDto struct {
+	PhotoID     int64
+	Impressions int64
+	Likes       int64
+}
+
+// SmartPhotosDto BestPhotoID is only set once enough viewers saw each of the compared photos first
+type SmartPhotosDto struct {
+	Enabled     bool
+	Photos      []PhotoPerformanceDto
+	BestPhotoID *int64
+}
+
+type SmartPhotosResponse struct {
+	Enabled     bool                       `json:"enabled"`
+	Photos      []PhotoPerformanceResponse `json:"photos"`
+	BestPhotoID *int64                     `json:"best_photo_id"`
+	Message     string                     `json:"message"`
+}
+
+type PhotoPerformanceResponse struct {
+	PhotoID     int64   `json:"photo_id"`
+	URL         string  `json:"url"`
+	Impressions int64   `json:"impressions"`
+	Likes       int64   `json:"likes"`
+	LikeRate    float64 `json:"like_rate"`
+}
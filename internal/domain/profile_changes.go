@@ -0,0 +1,57 @@
+package domain
+
+import "time"
+
+// Profile change request statuses, a request is decided once
+const (
+	ProfileChangePending  = "pending"
+	ProfileChangeApproved = "approved"
+	ProfileChangeRejected = "rejected"
+)
+
+// ProfileChangeRequest asks for one locked field, evidence references the re-verification document
+// (e.g. a support ticket or a document id from the verification provider) and never contains the document itself
+type ProfileChangeRequest struct {
+	Field          string `json:"field"`
+	RequestedValue string `json:"requested_value"`
+	Evidence       string `json:"evidence"`
+	Reason         string `json:"reason"`
+}
+
+// ProfileChangeReviewRequest decides a request, decision is approve or reject
+type ProfileChangeReviewRequest struct {
+	Actor         string `json:"actor"`
+	Decision      string `json:"decision"`
+	Justification string `json:"justification"`
+}
+
+type ProfileChangeDto struct {
+	RequestID      int64
+	AccountID      int64  `validate:"required"`
+	Field          string `validate:"required,oneof=full_name date_of_birth gender"`
+	CurrentValue   string `validate:"max=255"`
+	RequestedValue string `validate:"required,max=255"`
+	Evidence       string `validate:"required,max=500"`
+	Reason         string `validate:"required,min=10,max=500"`
+	Status         string
+	Reviewer       *string
+	ReviewNote     *string
+	AuditID        *int64
+	CreatedAt      time.Time
+	ReviewedAt     *time.Time
+}
+
+type ProfileChangeResponse struct {
+	RequestID      int64  `json:"request_id"`
+	AccountID      int64  `json:"account_id"`
+	Field          string `json:"field"`
+	CurrentValue   string `json:"current_value"`
+	RequestedValue string `json:"requested_value"`
+	Evidence       string `json:"evidence,omitempty"`
+	Reason         string `json:"reason"`
+	Status         string `json:"status"`
+	ReviewNote     string `json:"review_note,omitempty"`
+	AuditID        int64  `json:"audit_id,omitempty"`
+	CreatedAt      string `json:"created_at"`
+	ReviewedAt     string `json:"reviewed_at,omitempty"`
+}
@@ -0,0 +1,24 @@
+package domain
+
+type ProfileSuggestionDto struct {
+	Key     string
+	Message string
+	Impact  int
+}
+
+type ProfileStrengthDto struct {
+	Score       int
+	Suggestions []ProfileSuggestionDto
+}
+
+type ProfileSuggestionResponse struct {
+	Key      string `json:"key"`
+	Message  string `json:"message"`
+	Priority int    `json:"priority"`
+	Impact   int    `json:"impact"`
+}
+
+type ProfileStrengthResponse struct {
+	Score       int                         `json:"score"`
+	Suggestions []ProfileSuggestionResponse `json:"suggestions"`
+}
@@ -0,0 +1,83 @@
+package domain
+
+import "time"
+
+// Recovery request states, a request is closed once it leaves pending
+const (
+	RecoveryStatusPending   = "pending"
+	RecoveryStatusCompleted = "completed"
+	RecoveryStatusCancelled = "cancelled"
+)
+
+type RecoveryContactsRequest struct {
+	Usernames []string `json:"usernames"`
+	Threshold int      `json:"threshold"`
+}
+
+type StartRecoveryRequest struct {
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
+
+type CompleteRecoveryRequest struct {
+	RecoveryToken string   `json:"recovery_token"`
+	Codes         []string `json:"codes"`
+	NewPassword   string   `json:"new_password"`
+}
+
+type RecoveryContactsDto struct {
+	AccountID         int64
+	ContactAccountIDs []int64
+	Threshold         int
+}
+
+type RecoveryRequestDto struct {
+	RequestID          int64
+	AccountID          int64
+	Status             string
+	ApprovalsRequired  int
+	ApproverAccountIDs []int64
+	CreatedAt          time.Time
+	AvailableAt        time.Time
+	ExpiresAt          time.Time
+}
+
+type RecoveryContactResponse struct {
+	AccountID int64  `json:"account_id"`
+	Username  string `json:"username"`
+}
+
+type RecoveryRequestResponse struct {
+	RequestID         int64  `json:"request_id"`
+	Username          string `json:"username,omitempty"`
+	ApprovalsRequired int    `json:"approvals_required"`
+	Approvals         int    `json:"approvals"`
+	Approved          bool   `json:"approved,omitempty"`
+	CreatedAt         string `json:"created_at"`
+	AvailableAt       string `json:"available_at"`
+	ExpiresAt         string `json:"expires_at"`
+}
+
+// RecoverySettingsResponse also lists the open requests so the owner notices a recovery they did not start
+type RecoverySettingsResponse struct {
+	Contacts        []RecoveryContactResponse `json:"contacts"`
+	Threshold       int                       `json:"threshold"`
+	PendingRequests []RecoveryRequestResponse `json:"pending_requests"`
+}
+
+type StartRecoveryResponse struct {
+	RecoveryToken string `json:"recovery_token"`
+	AvailableAt   string `json:"available_at"`
+	ExpiresAt     string `json:"expires_at"`
+	Message       string `json:"message"`
+}
+
+type RecoveryApprovalResponse struct {
+	RequestID int64  `json:"request_id"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+}
+
+type RecoveryMessageResponse struct {
+	Message string `json:"message"`
+}
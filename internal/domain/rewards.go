@@ -0,0 +1,32 @@
+package domain
+
+type LoginStreakDto struct {
+	AccountID     int64
+	Timezone      string
+	CurrentStreak int
+	LongestStreak int
+	Today         string
+	LastLoginDay  string
+	ClaimedToday  bool
+}
+
+type LoginRewardDto struct {
+	AccountID     int64
+	CurrentStreak int
+	ExtraLikes    int64
+}
+
+type LoginStreakResponse struct {
+	CurrentStreak  int    `json:"current_streak"`
+	LongestStreak  int    `json:"longest_streak"`
+	Timezone       string `json:"timezone"`
+	Today          string `json:"today"`
+	ClaimedToday   bool   `json:"claimed_today"`
+	NextExtraLikes int64  `json:"next_extra_likes"`
+}
+
+type ClaimRewardResponse struct {
+	CurrentStreak int    `json:"current_streak"`
+	ExtraLikes    int64  `json:"extra_likes"`
+	Message       string `json:"message"`
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// SafetySettingsRequest replaces the safety settings of the account, a missing setting is rejected
+type SafetySettingsRequest struct {
+	LateNightMode *bool `json:"late_night_mode"`
+}
+
+type SafetySettingsDto struct {
+	AccountID     int64
+	LateNightMode bool
+	UpdatedAt     time.Time
+}
+
+// SafetySettingsResponse tells how long the conversation of a new match stays restricted while LateNightMode is on
+type SafetySettingsResponse struct {
+	LateNightMode          bool   `json:"late_night_mode"`
+	FirstConversationHours int    `json:"first_conversation_hours"`
+	UpdatedAt              string `json:"updated_at,omitempty"`
+}
@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+type ShareLinkRequest struct {
+	ExpiresInHours int `json:"expires_in_hours"`
+}
+
+type ShareLinkDto struct {
+	LinkID         int64
+	AccountID      int64 `validate:"required"`
+	ExpiresInHours int   `validate:"min=1,max=720"`
+	ExpiresAt      time.Time
+	RevokedAt      *time.Time
+	ViewCount      int64
+	CreatedAt      time.Time
+}
+
+type ShareLinkResponse struct {
+	LinkID    int64  `json:"link_id"`
+	URL       string `json:"url"`
+	ExpiresAt string `json:"expires_at"`
+	ViewCount int64  `json:"view_count"`
+	Active    bool   `json:"active"`
+	CreatedAt string `json:"created_at"`
+}
+
+type RevokeShareLinkResponse struct {
+	LinkID  int64  `json:"link_id"`
+	Message string `json:"message"`
+}
+
+// PublicProfileResponse is the limited profile visible to anyone holding a share link
+type PublicProfileResponse struct {
+	FullName string   `json:"full_name"`
+	Age      int      `json:"age"`
+	Photos   []string `json:"photos"`
+}
@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+// Status message severities, the apps show critical messages first
+const (
+	StatusSeverityInfo     = "info"
+	StatusSeverityWarning  = "warning"
+	StatusSeverityCritical = "critical"
+)
+
+// StatusMessageRequest schedules a message, times are RFC 3339, a missing starts_at means now and a missing ends_at keeps it until removed
+type StatusMessageRequest struct {
+	Severity string     `json:"severity"`
+	Title    string     `json:"title"`
+	Message  string     `json:"message"`
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+}
+
+type StatusMessageDto struct {
+	MessageID int64
+	Severity  string `validate:"required,oneof=info warning critical"`
+	Title     string `validate:"required,max=120"`
+	Message   string `validate:"required,max=500"`
+	StartsAt  time.Time
+	EndsAt    *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type StatusMessageResponse struct {
+	MessageID int64  `json:"message_id"`
+	Severity  string `json:"severity"`
+	Title     string `json:"title"`
+	Message   string `json:"message"`
+	StartsAt  string `json:"starts_at"`
+	EndsAt    string `json:"ends_at,omitempty"`
+}
+
+// StatusResponse lists the messages live right now, empty when everything works
+type StatusResponse struct {
+	Messages []StatusMessageResponse `json:"messages"`
+}
+
+type DeleteStatusMessageResponse struct {
+	MessageID int64  `json:"message_id"`
+	Message   string `json:"message"`
+}
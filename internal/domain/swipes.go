@@ -1,12 +1,39 @@
 package domain
 
+import "time"
+
 type SwipeRequest struct {
 	ActionType     string `json:"action_type"`
 	AccountIdSwipe int64  `json:"account_id_swipe"`
 }
 
+// SuperLikeRequest is a like sent with the super like quota, the target is told about it
+type SuperLikeRequest struct {
+	AccountIdSwipe int64 `json:"account_id_swipe"`
+}
+
+// SwipeResponse tells the app right away when a like completed a match
 type SwipeResponse struct {
 	Message string `json:"message"`
+	Matched bool   `json:"matched"`
+	MatchID int64  `json:"match_id,omitempty"`
+}
+
+// LastSwipeDto is the swipe an account can undo, QuotaUsed is true when it took a unit of the daily quota
+type LastSwipeDto struct {
+	AccountID      int64
+	SwipeID        int64
+	AccountIDSwipe int64
+	Action         string
+	QuotaUsed      bool
+	MatchID        *int64
+	SwipeDate      time.Time
+}
+
+type SwipeUndoResponse struct {
+	Message        string `json:"message"`
+	AccountIdSwipe int64  `json:"account_id_swipe"`
+	ActionType     string `json:"action_type"`
 }
 
 type TotalSwipeAction struct {
@@ -35,15 +35,16 @@ type AllUsers struct {
 }
 
 type AllUserViews struct {
-	UserID    int64
-	AccountID int64
-	FullName  *string
-	Username  string
-	Age       int
-	Gender    string
-	Address   string
-	Bio       string
-	Verified  bool
+	UserID     int64
+	AccountID  int64
+	FullName   *string
+	Username   string
+	Age        int
+	Gender     string
+	Address    string
+	Bio        string
+	Verified   bool
+	DistanceKm *float64
 }
 
 type UserViewsResponse struct {
@@ -51,13 +52,15 @@ type UserViewsResponse struct {
 	AccountID int64    `json:"account_id"`
 	FullName  *string  `json:"full_name"`
 	Username  string   `json:"username"`
-	Photos    []string `json:"photos"`
-	Videos    []string `json:"videos"`
+	Photos    []string `json:"photos" map:"-"`
+	Videos    []string `json:"videos" map:"-"`
 	Age       int      `json:"age"`
 	Gender    string   `json:"gender"`
 	Address   string   `json:"address"`
 	Bio       string   `json:"bio"`
 	Verified  bool     `json:"verified"`
+	// DistanceKm is rounded up to whole km so the exact location of the candidate is not revealed
+	DistanceKm *int `json:"distance_km,omitempty" map:"-"`
 }
 
 type UserViewNilResponse struct {
@@ -70,6 +73,7 @@ type PatchUserRequest struct {
 	Address     *string `json:"address"`
 	Bio         *string `json:"bio"`
 	DateOfBirth *string `json:"date_of_birth"`
+	PhoneNumber *string `json:"phone_number"`
 }
 
 type PatchUser struct {
@@ -78,7 +82,8 @@ type PatchUser struct {
 	Gender      *string
 	Address     *string
 	Bio         *string
-	DateOfBirth *string
+	DateOfBirth *string `validate:"omitempty,adult-age"`
+	PhoneNumber *string
 }
 
 type PatchUserResponse struct {
@@ -104,3 +109,20 @@ type PatchUserDto struct {
 	DateOfBirth *time.Time
 	UpdatedAt   *time.Time
 }
+
+// UserLocationRequest sets the location discovery searches around
+type UserLocationRequest struct {
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+}
+
+type UserLocationDto struct {
+	Latitude  *float64 `validate:"required,gte=-90,lte=90"`
+	Longitude *float64 `validate:"required,gte=-180,lte=180"`
+}
+
+type UserLocationResponse struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	RadiusKm  int     `json:"radius_km"`
+}
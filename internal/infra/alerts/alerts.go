@@ -0,0 +1,76 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"godating-dealls/internal/common"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Alert is an operational problem that someone has to look at
+type Alert struct {
+	Source   string            `json:"source"`
+	Severity string            `json:"severity"`
+	Message  string            `json:"message"`
+	Details  map[string]string `json:"details,omitempty"`
+	RaisedAt time.Time         `json:"raised_at"`
+}
+
+type Alerter interface {
+	Alert(ctx context.Context, alert Alert) error
+}
+
+// NewAlerter posts alerts to webhookURL, ALERT_WEBHOOK_URL, without it alerts only go to the log
+func NewAlerter(webhookURL string) Alerter {
+	if webhookURL == "" {
+		return LogAlerter{}
+	}
+	return &WebhookAlerter{URL: webhookURL, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+type LogAlerter struct{}
+
+func (l LogAlerter) Alert(ctx context.Context, alert Alert) error {
+	log.Printf("ALERT [%s] %s: %s %v", alert.Severity, alert.Source, alert.Message, alert.Details)
+	return nil
+}
+
+type WebhookAlerter struct {
+	URL    string
+	Client *http.Client
+}
+
+func (w *WebhookAlerter) Alert(ctx context.Context, alert Alert) error {
+	// The alert is logged as well so it is never lost when the webhook is down
+	_ = LogAlerter{}.Alert(ctx, alert)
+
+	var payload interface{} = alert
+	if common.PIIScrubbingEnabled() {
+		payload = common.ScrubPII(alert)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("could not encode alert: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("could not create alert request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not send alert: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("alert webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
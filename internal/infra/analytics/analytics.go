@@ -0,0 +1,67 @@
+package analytics
+
+import (
+	"context"
+	"github.com/redis/go-redis/v9"
+	"log"
+	"time"
+)
+
+// Events of the funnels, in the order of a user journey
+const (
+	EventSignedUp      = "signed_up"
+	EventLoggedIn      = "logged_in"
+	EventSelectionSeen = "selection_seen"
+	EventQuotaChecked  = "quota_checked"
+	EventSwiped        = "swiped"
+	EventQuotaExceeded = "quota_exceeded"
+	EventMatched       = "matched"
+)
+
+// Event is a step of a user journey for the analytics consumers. Unlike the domain events of the outbox it is not
+// part of a transaction and can be lost, it is published once the change was committed and counted, not replayed
+type Event struct {
+	Name       string                 `json:"name"`
+	AccountID  int64                  `json:"account_id"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+	OccurredAt time.Time              `json:"occurred_at"`
+}
+
+// NewEvent is an event of the account that occurred now
+func NewEvent(name string, accountId int64, properties map[string]interface{}) Event {
+	return Event{Name: name, AccountID: accountId, Properties: properties, OccurredAt: time.Now().UTC()}
+}
+
+// Publisher never blocks the request nor fails it, a publisher that cannot keep up drops events and logs it
+type Publisher interface {
+	Publish(ctx context.Context, events ...Event)
+}
+
+// NopPublisher drops the events, analytics is off
+type NopPublisher struct{}
+
+func (NopPublisher) Publish(ctx context.Context, events ...Event) {}
+
+const (
+	DefaultStream       = "events"
+	DefaultStreamMaxLen = 1000000
+)
+
+// Config is read by config.Load from ANALYTICS_PUBLISHER, ANALYTICS_REDIS_STREAM and ANALYTICS_REDIS_STREAM_MAXLEN
+type Config struct {
+	Publisher    string `validate:"omitempty,oneof=redis"`
+	Stream       string `validate:"required"`
+	StreamMaxLen int64  `validate:"gt=0"`
+}
+
+// NewPublisher picks the configured publisher, empty turns analytics off and redis adds the events to a stream on the
+// redis of the service
+func NewPublisher(client *redis.Client, config Config) Publisher {
+	if config.Publisher == "redis" {
+		if client != nil {
+			return NewRedisStreamPublisher(client, config.Stream, config.StreamMaxLen)
+		}
+		log.Println("ANALYTICS_PUBLISHER redis needs a redis client, analytics is off")
+	}
+	return NopPublisher{}
+}
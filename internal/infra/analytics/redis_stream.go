@@ -0,0 +1,102 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/redis/go-redis/v9"
+	"godating-dealls/internal/infra/redisclient"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// publishBuffer events wait for the writer, more are dropped
+	publishBuffer = 10000
+	// publishBatch events at most are written in one round trip
+	publishBatch = 500
+)
+
+// RedisStreamPublisher appends the events to a redis stream from a goroutine of its own, so a request only hands the
+// event over. The consumers read the stream with a consumer group, it is trimmed to about MaxLen entries
+type RedisStreamPublisher struct {
+	Client  *redis.Client
+	Stream  string
+	MaxLen  int64
+	queue   chan Event
+	dropped atomic.Int64
+}
+
+// NewRedisStreamPublisher starts the writer, the stream is kept at about maxLen entries
+func NewRedisStreamPublisher(client *redis.Client, stream string, maxLen int64) *RedisStreamPublisher {
+	publisher := &RedisStreamPublisher{
+		Client: client,
+		Stream: redisclient.AnalyticsEventStreamKey.Key(stream),
+		MaxLen: maxLen,
+		queue:  make(chan Event, publishBuffer),
+	}
+	go publisher.run()
+	log.Printf("Analytics events are published to the redis stream %s", publisher.Stream)
+	return publisher
+}
+
+func (p *RedisStreamPublisher) Publish(ctx context.Context, events ...Event) {
+	for _, event := range events {
+		select {
+		case p.queue <- event:
+		default:
+			// Logged on the first drop and then every thousand, a slow redis must not flood the log either
+			if dropped := p.dropped.Add(1); dropped%1000 == 1 {
+				log.Printf("Analytics publisher is behind, %d events dropped", dropped)
+			}
+		}
+	}
+}
+
+// run writes the events waiting in the queue together, a failed write loses them
+func (p *RedisStreamPublisher) run() {
+	for event := range p.queue {
+		batch := []Event{event}
+	collect:
+		for len(batch) < publishBatch {
+			select {
+			case next := <-p.queue:
+				batch = append(batch, next)
+			default:
+				break collect
+			}
+		}
+		if err := p.write(batch); err != nil {
+			log.Printf("Failed to publish %d analytics events: %v", len(batch), err)
+		}
+	}
+}
+
+func (p *RedisStreamPublisher) write(batch []Event) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pipe := p.Client.Pipeline()
+	for _, event := range batch {
+		properties := []byte("{}")
+		if len(event.Properties) > 0 {
+			if encoded, err := json.Marshal(event.Properties); err == nil {
+				properties = encoded
+			}
+		}
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: p.Stream,
+			MaxLen: p.MaxLen,
+			Approx: true,
+			Values: map[string]interface{}{
+				"name":        event.Name,
+				"account_id":  event.AccountID,
+				"properties":  string(properties),
+				"occurred_at": event.OccurredAt.Format(time.RFC3339Nano),
+			},
+		})
+	}
+	pipe.Expire(ctx, p.Stream, redisclient.AnalyticsEventStreamKey.TTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
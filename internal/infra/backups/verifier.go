@@ -0,0 +1,187 @@
+package backups
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	checksumSuffix = ".sha256"
+	restoreTimeout = 30 * time.Minute
+
+	DefaultMaxAgeHours = 26
+)
+
+var schemaNamePattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// Config describes where backups are and how to restore one, the restore command gets {file} and {schema} substituted.
+// It is read by config.Load from BACKUP_DIR, BACKUP_MAX_AGE_HOURS, BACKUP_RESTORE_COMMAND and BACKUP_SCRATCH_SCHEMA
+type Config struct {
+	Dir            string
+	MaxAge         time.Duration `validate:"gt=0"`
+	RestoreCommand string
+	ScratchSchema  string
+}
+
+type Report struct {
+	File         string
+	CreatedAt    time.Time
+	Checksum     string
+	RestoreCheck string
+}
+
+type Verifier struct {
+	Config Config
+	DB     *sql.DB
+}
+
+func NewVerifier(config Config, db *sql.DB) *Verifier {
+	return &Verifier{Config: config, DB: db}
+}
+
+// Verify checks the newest backup is recent, matches its checksum file and, when a restore command is set, restores into the scratch schema
+func (v *Verifier) Verify(ctx context.Context) (Report, error) {
+	if v.Config.Dir == "" {
+		return Report{}, errors.New("backup dir is not configured")
+	}
+
+	file, createdAt, err := latestBackup(v.Config.Dir)
+	if err != nil {
+		return Report{}, err
+	}
+	report := Report{File: file, CreatedAt: createdAt}
+
+	if age := time.Since(createdAt); age > v.Config.MaxAge {
+		return report, fmt.Errorf("latest backup is %s old, expected under %s", age.Round(time.Minute), v.Config.MaxAge)
+	}
+
+	report.Checksum, err = verifyChecksum(file)
+	if err != nil {
+		return report, err
+	}
+
+	if v.Config.RestoreCommand == "" {
+		report.RestoreCheck = "skipped, no restore command configured"
+		return report, nil
+	}
+
+	tables, err := v.testRestore(ctx, file)
+	if err != nil {
+		return report, err
+	}
+	report.RestoreCheck = fmt.Sprintf("restored %d tables", tables)
+	return report, nil
+}
+
+func (v *Verifier) testRestore(ctx context.Context, file string) (int, error) {
+	schema := v.Config.ScratchSchema
+	if !schemaNamePattern.MatchString(schema) {
+		return 0, errors.New("scratch schema name is missing or invalid")
+	}
+
+	// The scratch schema is always recreated so a previous failed run can't make this one pass
+	if _, err := v.DB.ExecContext(ctx, "DROP DATABASE IF EXISTS "+schema); err != nil {
+		return 0, fmt.Errorf("could not drop scratch schema: %v", err)
+	}
+	if _, err := v.DB.ExecContext(ctx, "CREATE DATABASE "+schema); err != nil {
+		return 0, fmt.Errorf("could not create scratch schema: %v", err)
+	}
+	defer func() {
+		_, _ = v.DB.ExecContext(context.Background(), "DROP DATABASE IF EXISTS "+schema)
+	}()
+
+	restoreCtx, cancel := context.WithTimeout(ctx, restoreTimeout)
+	defer cancel()
+
+	command := strings.NewReplacer("{file}", file, "{schema}", schema).Replace(v.Config.RestoreCommand)
+	output, err := exec.CommandContext(restoreCtx, "sh", "-c", command).CombinedOutput()
+	if err != nil {
+		return 0, fmt.Errorf("restore command failed: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	var tables int
+	err = v.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = ?", schema).Scan(&tables)
+	if err != nil {
+		return 0, fmt.Errorf("could not inspect scratch schema: %v", err)
+	}
+	if tables == 0 {
+		return 0, errors.New("restore finished but the scratch schema has no tables")
+	}
+	return tables, nil
+}
+
+func latestBackup(dir string) (string, time.Time, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("could not read backup dir: %v", err)
+	}
+
+	var latest string
+	var latestAt time.Time
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasSuffix(entry.Name(), checksumSuffix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if info.ModTime().After(latestAt) {
+			latest = filepath.Join(dir, entry.Name())
+			latestAt = info.ModTime()
+		}
+	}
+
+	if latest == "" {
+		return "", time.Time{}, errors.New("no backup found")
+	}
+	return latest, latestAt, nil
+}
+
+// verifyChecksum compares the file with its sha256sum style sidecar file
+func verifyChecksum(file string) (string, error) {
+	sidecar, err := os.Open(file + checksumSuffix)
+	if err != nil {
+		return "", fmt.Errorf("checksum file missing for %s", filepath.Base(file))
+	}
+	defer sidecar.Close()
+
+	scanner := bufio.NewScanner(sidecar)
+	if !scanner.Scan() {
+		return "", errors.New("checksum file is empty")
+	}
+	fields := strings.Fields(scanner.Text())
+	if len(fields) == 0 {
+		return "", errors.New("checksum file is empty")
+	}
+	expected := strings.ToLower(fields[0])
+
+	backup, err := os.Open(file)
+	if err != nil {
+		return "", fmt.Errorf("could not open backup: %v", err)
+	}
+	defer backup.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, backup); err != nil {
+		return "", fmt.Errorf("could not read backup: %v", err)
+	}
+	actual := hex.EncodeToString(hash.Sum(nil))
+
+	if actual != expected {
+		return actual, fmt.Errorf("checksum mismatch for %s", filepath.Base(file))
+	}
+	return actual, nil
+}
@@ -0,0 +1,63 @@
+package emaildomains
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxListBytes bounds the download, the public disposable domain lists are well under 2 MB
+const maxListBytes = 8 << 20
+
+// Source supplies the remote list of email domains
+type Source interface {
+	Fetch(ctx context.Context) ([]string, error)
+}
+
+// NewSource downloads listURL, EMAIL_DOMAIN_LIST_URL, without it only the configured domains apply
+func NewSource(listURL string) Source {
+	if listURL == "" {
+		return nil
+	}
+	return &HTTPSource{URL: listURL, Client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// HTTPSource reads a plain text list with one domain per line, blank lines and lines starting with # are skipped
+type HTTPSource struct {
+	URL    string
+	Client *http.Client
+}
+
+func (h *HTTPSource) Fetch(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not build email domain list request: %v", err)
+	}
+	resp, err := h.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not download email domain list: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("email domain list returned status %d", resp.StatusCode)
+	}
+
+	var domains []string
+	scanner := bufio.NewScanner(io.LimitReader(resp.Body, maxListBytes))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		domains = append(domains, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read email domain list: %v", err)
+	}
+	return domains, nil
+}
@@ -0,0 +1,60 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/redis/go-redis/v9"
+	"log"
+	"time"
+)
+
+// Event is a domain event on the bus. ID is the id of the outbox row, a consumer that sees it twice skips it, the
+// relayer publishes at least once
+type Event struct {
+	ID          int64           `json:"id"`
+	Kind        string          `json:"kind"`
+	AggregateID int64           `json:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+}
+
+// Bus publishes the events of the outbox, Publish returns once the bus took the event
+type Bus interface {
+	Publish(ctx context.Context, event Event) error
+	Name() string
+}
+
+const (
+	DefaultBus          = "memory"
+	DefaultStream       = "events"
+	DefaultStreamMaxLen = 100000
+	DefaultKafkaTopic   = "godating.events"
+)
+
+// Config is read by config.Load from OUTBOX_BUS, OUTBOX_REDIS_STREAM, OUTBOX_REDIS_STREAM_MAXLEN,
+// OUTBOX_KAFKA_REST_URL, OUTBOX_KAFKA_TOPIC and the optional basic auth of the proxy in OUTBOX_KAFKA_REST_USERNAME and
+// OUTBOX_KAFKA_REST_PASSWORD
+type Config struct {
+	Bus               string `validate:"oneof=memory redis kafka"`
+	Stream            string `validate:"required"`
+	StreamMaxLen      int64  `validate:"gt=0"`
+	KafkaRESTURL      string `validate:"required_if=Bus kafka,omitempty,url"`
+	KafkaTopic        string `validate:"required"`
+	KafkaRESTUsername string
+	KafkaRESTPassword string
+}
+
+// NewBus picks the configured bus, memory (in the process), redis (a stream on the redis of the service) or kafka
+// (through a Kafka REST proxy)
+func NewBus(client *redis.Client, config Config) Bus {
+	switch config.Bus {
+	case "redis":
+		if client != nil {
+			return NewRedisStreamBus(client, config.Stream, config.StreamMaxLen)
+		}
+		log.Println("OUTBOX_BUS redis needs a redis client, using the in-process bus")
+	case "kafka":
+		return NewKafkaRESTBus(config)
+	}
+	return NewInProcessBus()
+}
@@ -0,0 +1,95 @@
+package eventbus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// KafkaRESTBus produces the events to a Kafka topic through a REST proxy (the v2 API of the Confluent REST Proxy),
+// so the service needs no Kafka client. The aggregate id is the key of the record, the events of one account or
+// match land on the same partition in order
+type KafkaRESTBus struct {
+	BaseURL  string
+	Topic    string
+	Username string
+	Password string
+	Client   *http.Client
+}
+
+func NewKafkaRESTBus(config Config) *KafkaRESTBus {
+	return &KafkaRESTBus{
+		BaseURL:  config.KafkaRESTURL,
+		Topic:    config.KafkaTopic,
+		Username: config.KafkaRESTUsername,
+		Password: config.KafkaRESTPassword,
+		Client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type kafkaProduceRequest struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+type kafkaRecord struct {
+	Key   string `json:"key"`
+	Value Event  `json:"value"`
+}
+
+// kafkaProduceResponse has an offset per record, a record the proxy could not produce has an error code
+type kafkaProduceResponse struct {
+	Offsets []struct {
+		ErrorCode *int   `json:"error_code"`
+		Error     string `json:"error"`
+	} `json:"offsets"`
+}
+
+func (b *KafkaRESTBus) Publish(ctx context.Context, event Event) error {
+	if b.BaseURL == "" {
+		return fmt.Errorf("OUTBOX_KAFKA_REST_URL is not set")
+	}
+	body, err := json.Marshal(kafkaProduceRequest{Records: []kafkaRecord{{Key: strconv.FormatInt(event.AggregateID, 10), Value: event}}})
+	if err != nil {
+		return fmt.Errorf("could not encode event: %v", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.BaseURL+"/topics/"+url.PathEscape(b.Topic), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("could not create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+	if b.Username != "" {
+		req.SetBasicAuth(b.Username, b.Password)
+	}
+
+	resp, err := b.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not produce event: %v", err)
+	}
+	defer resp.Body.Close()
+	content, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("kafka rest proxy answered %d: %s", resp.StatusCode, bytes.TrimSpace(content))
+	}
+
+	var produced kafkaProduceResponse
+	if err := json.Unmarshal(content, &produced); err != nil {
+		return fmt.Errorf("could not decode kafka rest proxy response: %v", err)
+	}
+	for _, offset := range produced.Offsets {
+		if offset.ErrorCode != nil {
+			return fmt.Errorf("kafka rest proxy could not produce the event: %d %s", *offset.ErrorCode, offset.Error)
+		}
+	}
+	return nil
+}
+
+func (b *KafkaRESTBus) Name() string {
+	return "kafka"
+}
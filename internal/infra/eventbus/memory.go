@@ -0,0 +1,45 @@
+package eventbus
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// Handler consumes an event, an error is logged and does not stop the other handlers
+type Handler func(ctx context.Context, event Event) error
+
+// InProcessBus hands the events to the handlers subscribed in this process, in the relayer goroutine. An event
+// nobody subscribed to is published all the same
+type InProcessBus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func NewInProcessBus() *InProcessBus {
+	return &InProcessBus{handlers: map[string][]Handler{}}
+}
+
+// Subscribe calls the handler for every event of the kind, "*" for every event
+func (b *InProcessBus) Subscribe(kind string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[kind] = append(b.handlers[kind], handler)
+}
+
+func (b *InProcessBus) Publish(ctx context.Context, event Event) error {
+	b.mu.RLock()
+	handlers := append(append([]Handler(nil), b.handlers[event.Kind]...), b.handlers["*"]...)
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			log.Printf("event %d (%s) handler failed: %v", event.ID, event.Kind, err)
+		}
+	}
+	return nil
+}
+
+func (b *InProcessBus) Name() string {
+	return "memory"
+}
@@ -0,0 +1,46 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"godating-dealls/internal/infra/redisclient"
+	"time"
+)
+
+// RedisStreamBus appends the events to a redis stream, the consumers read it with XREAD or a consumer group. The
+// stream is trimmed to about MaxLen entries and expires when nothing was published for the ttl of its key policy
+type RedisStreamBus struct {
+	Client *redis.Client
+	Stream string
+	MaxLen int64
+}
+
+func NewRedisStreamBus(client *redis.Client, stream string, maxLen int64) *RedisStreamBus {
+	return &RedisStreamBus{Client: client, Stream: redisclient.DomainEventStreamKey.Key(stream), MaxLen: maxLen}
+}
+
+func (b *RedisStreamBus) Publish(ctx context.Context, event Event) error {
+	pipe := b.Client.TxPipeline()
+	pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: b.Stream,
+		MaxLen: b.MaxLen,
+		Approx: true,
+		Values: map[string]interface{}{
+			"id":           event.ID,
+			"kind":         event.Kind,
+			"aggregate_id": event.AggregateID,
+			"payload":      string(event.Payload),
+			"occurred_at":  event.OccurredAt.UTC().Format(time.RFC3339),
+		},
+	})
+	pipe.Expire(ctx, b.Stream, redisclient.DomainEventStreamKey.TTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("could not add event to stream: %v", err)
+	}
+	return nil
+}
+
+func (b *RedisStreamBus) Name() string {
+	return "redis"
+}
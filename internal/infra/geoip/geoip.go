@@ -0,0 +1,99 @@
+package geoip
+
+import (
+	"context"
+	"net/http"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+const (
+	cacheTTL     = time.Hour
+	cacheEntries = 10000
+)
+
+// Location is where an address is registered, ASN is 0 when the provider does not know the network
+type Location struct {
+	Country      string
+	ASN          uint32
+	Organization string
+}
+
+type Provider interface {
+	Lookup(ctx context.Context, address netip.Addr) (Location, error)
+}
+
+// Config is read by config.Load. GEOIP_PROVIDER and IPINFO_TOKEN pick the provider, GEO_BLOCK_COUNTRIES,
+// GEO_FLAG_COUNTRIES, GEO_BLOCK_ASNS and GEO_FLAG_ASNS are comma separated and GEO_ROUTE_OVERRIDES is
+// "path prefix=mode,..." where the longest matching prefix applies
+type Config struct {
+	Provider       string   `validate:"omitempty,oneof=ipinfo"`
+	IPInfoToken    string   `validate:"required_if=Provider ipinfo"`
+	BlockCountries []string `validate:"dive,iso3166_1_alpha2"`
+	FlagCountries  []string `validate:"dive,iso3166_1_alpha2"`
+	BlockASNs      []uint32
+	FlagASNs       []uint32
+	RouteOverrides map[string]string `validate:"dive,oneof=off flag block"`
+}
+
+// NewProvider returns the configured provider, without one no address is looked up and country restrictions are off
+func NewProvider(config Config) Provider {
+	switch config.Provider {
+	case "ipinfo":
+		return newCachingProvider(&IPInfoProvider{
+			Token:  config.IPInfoToken,
+			Client: &http.Client{Timeout: 2 * time.Second},
+		})
+	default:
+		return nil
+	}
+}
+
+type cacheEntry struct {
+	location  Location
+	expiresAt time.Time
+}
+
+// cachingProvider keeps lookups for an hour, the same clients come back many times a minute and
+// the providers bill per lookup. Failed lookups are not kept
+type cachingProvider struct {
+	provider Provider
+	mu       sync.Mutex
+	entries  map[netip.Addr]cacheEntry
+}
+
+func newCachingProvider(provider Provider) *cachingProvider {
+	return &cachingProvider{provider: provider, entries: map[netip.Addr]cacheEntry{}}
+}
+
+func (c *cachingProvider) Lookup(ctx context.Context, address netip.Addr) (Location, error) {
+	now := time.Now()
+	c.mu.Lock()
+	entry, ok := c.entries[address]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.location, nil
+	}
+
+	location, err := c.provider.Lookup(ctx, address)
+	if err != nil {
+		return Location{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= cacheEntries {
+		for cached, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, cached)
+			}
+		}
+		// Still full of live entries, start over rather than track which one is the oldest
+		if len(c.entries) >= cacheEntries {
+			c.entries = map[netip.Addr]cacheEntry{}
+		}
+	}
+	c.entries[address] = cacheEntry{location: location, expiresAt: now.Add(cacheTTL)}
+	return location, nil
+}
@@ -0,0 +1,221 @@
+package geoip
+
+import (
+	"context"
+	"fmt"
+	"godating-dealls/internal/common"
+	"log"
+	"net/http"
+	"net/netip"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Route modes, a route override turns the restrictions off, only flags what would be blocked or blocks what would be flagged
+const (
+	ModeOff   = "off"
+	ModeFlag  = "flag"
+	ModeBlock = "block"
+)
+
+const lookupTimeout = 2 * time.Second
+
+// defaultRoute names the routes without an override in the metrics
+const defaultRoute = "*"
+
+type RouteOverride struct {
+	Prefix string
+	Mode   string
+}
+
+// Policy lists the countries (ISO 3166 alpha-2) and networks (ASN) to block or flag
+type Policy struct {
+	BlockCountries map[string]bool
+	FlagCountries  map[string]bool
+	BlockASNs      map[uint32]bool
+	FlagASNs       map[uint32]bool
+	Routes         []RouteOverride
+}
+
+// NewPolicy keeps the route overrides longest prefix first
+func NewPolicy(config Config) Policy {
+	policy := Policy{
+		BlockCountries: countrySet(config.BlockCountries),
+		FlagCountries:  countrySet(config.FlagCountries),
+		BlockASNs:      asnSet(config.BlockASNs),
+		FlagASNs:       asnSet(config.FlagASNs),
+	}
+	for prefix, mode := range config.RouteOverrides {
+		policy.Routes = append(policy.Routes, RouteOverride{Prefix: prefix, Mode: mode})
+	}
+	sort.SliceStable(policy.Routes, func(i, j int) bool {
+		if len(policy.Routes[i].Prefix) != len(policy.Routes[j].Prefix) {
+			return len(policy.Routes[i].Prefix) > len(policy.Routes[j].Prefix)
+		}
+		return policy.Routes[i].Prefix < policy.Routes[j].Prefix
+	})
+	return policy
+}
+
+func countrySet(countries []string) map[string]bool {
+	set := map[string]bool{}
+	for _, country := range countries {
+		set[country] = true
+	}
+	return set
+}
+
+func asnSet(asns []uint32) map[uint32]bool {
+	set := map[uint32]bool{}
+	for _, asn := range asns {
+		set[asn] = true
+	}
+	return set
+}
+
+func (p Policy) empty() bool {
+	return len(p.BlockCountries) == 0 && len(p.FlagCountries) == 0 && len(p.BlockASNs) == 0 && len(p.FlagASNs) == 0
+}
+
+// route returns the override of the path, the default is to apply the lists as they are
+func (p Policy) route(path string) RouteOverride {
+	for _, route := range p.Routes {
+		if strings.HasPrefix(path, route.Prefix) {
+			return route
+		}
+	}
+	return RouteOverride{Prefix: defaultRoute}
+}
+
+// match returns what the lists say about the location and why, an empty mode when it is not listed
+func (p Policy) match(location Location) (string, string) {
+	country := "country:" + location.Country
+	asn := fmt.Sprintf("asn:AS%d", location.ASN)
+	switch {
+	case location.Country != "" && p.BlockCountries[location.Country]:
+		return ModeBlock, country
+	case location.ASN != 0 && p.BlockASNs[location.ASN]:
+		return ModeBlock, asn
+	case location.Country != "" && p.FlagCountries[location.Country]:
+		return ModeFlag, country
+	case location.ASN != 0 && p.FlagASNs[location.ASN]:
+		return ModeFlag, asn
+	}
+	return "", ""
+}
+
+// Metrics counts the decisions since the process started
+type Metrics struct {
+	Enabled         bool
+	Blocked         int64
+	Flagged         int64
+	LookupFailures  int64
+	BlockedByReason map[string]int64
+	BlockedByRoute  map[string]int64
+}
+
+// Guard blocks or flags requests by the country and network of the client address
+type Guard struct {
+	provider Provider
+	policy   Policy
+
+	blocked        atomic.Int64
+	flagged        atomic.Int64
+	lookupFailures atomic.Int64
+
+	mu              sync.Mutex
+	blockedByReason map[string]int64
+	blockedByRoute  map[string]int64
+}
+
+// NewGuard without a provider or without any listed country or network lets every request through
+func NewGuard(provider Provider, policy Policy) *Guard {
+	return &Guard{
+		provider:        provider,
+		policy:          policy,
+		blockedByReason: map[string]int64{},
+		blockedByRoute:  map[string]int64{},
+	}
+}
+
+func (g *Guard) enabled() bool {
+	return g.provider != nil && !g.policy.empty()
+}
+
+// Middleware answers 403 geo_denied for a blocked location. A flagged request goes through with the reason
+// in the context under "geo_flag" for the handlers that want to be stricter, e.g. on sign up.
+// A failed lookup lets the request through, the provider being down must not take the service down with it
+func (g *Guard) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !g.enabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		route := g.policy.route(r.URL.Path)
+		if route.Mode == ModeOff {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		address, err := netip.ParseAddr(common.TrustedClientAddress(r))
+		if err != nil || !address.Unmap().IsGlobalUnicast() || address.Unmap().IsPrivate() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), lookupTimeout)
+		location, err := g.provider.Lookup(ctx, address.Unmap())
+		cancel()
+		if err != nil {
+			g.lookupFailures.Add(1)
+			log.Println("geoip lookup failed:", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		mode, reason := g.policy.match(location)
+		switch {
+		case mode == "":
+		case mode == ModeBlock && route.Mode != ModeFlag, mode == ModeFlag && route.Mode == ModeBlock:
+			g.recordBlocked(reason, route.Prefix)
+			common.WriteEnvelopeError(w, http.StatusForbidden, "geo_denied", "Requests from this region are not allowed")
+			return
+		default:
+			g.flagged.Add(1)
+			r = r.WithContext(context.WithValue(r.Context(), "geo_flag", reason))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (g *Guard) recordBlocked(reason string, route string) {
+	g.blocked.Add(1)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.blockedByReason[reason]++
+	g.blockedByRoute[route]++
+}
+
+func (g *Guard) Metrics() Metrics {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	metrics := Metrics{
+		Enabled:         g.enabled(),
+		Blocked:         g.blocked.Load(),
+		Flagged:         g.flagged.Load(),
+		LookupFailures:  g.lookupFailures.Load(),
+		BlockedByReason: make(map[string]int64, len(g.blockedByReason)),
+		BlockedByRoute:  make(map[string]int64, len(g.blockedByRoute)),
+	}
+	for reason, count := range g.blockedByReason {
+		metrics.BlockedByReason[reason] = count
+	}
+	for route, count := range g.blockedByRoute {
+		metrics.BlockedByRoute[route] = count
+	}
+	return metrics
+}
@@ -0,0 +1,71 @@
+package geoip
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const ipInfoURL = "https://ipinfo.io/"
+
+// IPInfoProvider looks addresses up on ipinfo.io, the org field carries the ASN as in "AS16509 Amazon.com, Inc."
+type IPInfoProvider struct {
+	Token  string
+	Client *http.Client
+}
+
+type ipInfoResponse struct {
+	Country string `json:"country"`
+	Org     string `json:"org"`
+}
+
+func (p *IPInfoProvider) Lookup(ctx context.Context, address netip.Addr) (Location, error) {
+	endpoint := ipInfoURL + url.PathEscape(address.String()) + "/json"
+	if p.Token != "" {
+		endpoint += "?token=" + url.QueryEscape(p.Token)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return Location{}, fmt.Errorf("could not build ipinfo request: %v", err)
+	}
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return Location{}, fmt.Errorf("could not reach ipinfo: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Location{}, fmt.Errorf("ipinfo returned status %d", resp.StatusCode)
+	}
+
+	var body ipInfoResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return Location{}, fmt.Errorf("could not decode ipinfo response: %v", err)
+	}
+
+	location := Location{Country: strings.ToUpper(body.Country)}
+	number, organization, _ := strings.Cut(body.Org, " ")
+	if asn, ok := ParseASN(number); ok {
+		location.ASN = asn
+		location.Organization = organization
+	} else {
+		location.Organization = body.Org
+	}
+	return location, nil
+}
+
+// ParseASN reads an autonomous system number written as AS16509 or 16509
+func ParseASN(value string) (uint32, bool) {
+	value = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(value)), "AS")
+	asn, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || asn == 0 {
+		return 0, false
+	}
+	return uint32(asn), true
+}
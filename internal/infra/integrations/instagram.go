@@ -0,0 +1,120 @@
+package integrations
+
+import (
+	"context"
+	"net/url"
+)
+
+const (
+	instagramAuthorizeURL = "https://api.instagram.com/oauth/authorize"
+	instagramTokenURL     = "https://api.instagram.com/oauth/access_token"
+	instagramLongLived    = "https://graph.instagram.com/access_token"
+	instagramRefreshURL   = "https://graph.instagram.com/refresh_access_token"
+	instagramMediaURL     = "https://graph.instagram.com/me/media"
+)
+
+type InstagramProvider struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+}
+
+// NewInstagramProvider returns nil without a client id so the provider is not registered
+func NewInstagramProvider(credentials Credentials) Provider {
+	if credentials.ClientID == "" {
+		return nil
+	}
+	return &InstagramProvider{
+		ClientID:     credentials.ClientID,
+		ClientSecret: credentials.ClientSecret,
+		RedirectURL:  credentials.RedirectURL,
+	}
+}
+
+func (i InstagramProvider) Name() string {
+	return "instagram"
+}
+
+func (i InstagramProvider) AuthorizeURL(state string) string {
+	query := url.Values{
+		"client_id":     {i.ClientID},
+		"redirect_uri":  {i.RedirectURL},
+		"scope":         {"user_profile,user_media"},
+		"response_type": {"code"},
+		"state":         {state},
+	}
+	return instagramAuthorizeURL + "?" + query.Encode()
+}
+
+// ExchangeCode swaps the code for a short-lived token and upgrades it to a long-lived one (60 days)
+func (i InstagramProvider) ExchangeCode(ctx context.Context, code string) (Token, error) {
+	form := url.Values{
+		"client_id":     {i.ClientID},
+		"client_secret": {i.ClientSecret},
+		"grant_type":    {"authorization_code"},
+		"redirect_uri":  {i.RedirectURL},
+		"code":          {code},
+	}
+	var shortLived tokenResponse
+	if err := postForm(ctx, instagramTokenURL, form, nil, &shortLived); err != nil {
+		return Token{}, err
+	}
+
+	query := url.Values{
+		"grant_type":    {"ig_exchange_token"},
+		"client_secret": {i.ClientSecret},
+		"access_token":  {shortLived.AccessToken},
+	}
+	var longLived tokenResponse
+	if err := getJSON(ctx, instagramLongLived+"?"+query.Encode(), nil, &longLived); err != nil {
+		return Token{}, err
+	}
+	return longLived.toToken(Token{}), nil
+}
+
+// RefreshToken long-lived Instagram tokens are refreshed with themselves, there is no separate refresh token
+func (i InstagramProvider) RefreshToken(ctx context.Context, token Token) (Token, error) {
+	query := url.Values{
+		"grant_type":   {"ig_refresh_token"},
+		"access_token": {token.AccessToken},
+	}
+	var res tokenResponse
+	if err := getJSON(ctx, instagramRefreshURL+"?"+query.Encode(), nil, &res); err != nil {
+		return Token{}, err
+	}
+	return res.toToken(token), nil
+}
+
+func (i InstagramProvider) FetchContent(ctx context.Context, accessToken string) ([]ImportedItem, error) {
+	query := url.Values{
+		"fields":       {"id,media_type,media_url,permalink"},
+		"limit":        {"12"},
+		"access_token": {accessToken},
+	}
+	var res struct {
+		Data []struct {
+			ID        string `json:"id"`
+			MediaType string `json:"media_type"`
+			MediaURL  string `json:"media_url"`
+			Permalink string `json:"permalink"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, instagramMediaURL+"?"+query.Encode(), nil, &res); err != nil {
+		return nil, err
+	}
+
+	var items []ImportedItem
+	for _, media := range res.Data {
+		// Only still images are imported as profile photos
+		if media.MediaType != "IMAGE" {
+			continue
+		}
+		items = append(items, ImportedItem{
+			ExternalID:  media.ID,
+			ContentType: "photo",
+			Value:       media.MediaURL,
+			URL:         media.Permalink,
+		})
+	}
+	return items, nil
+}
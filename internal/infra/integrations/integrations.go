@@ -0,0 +1,134 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Token is the credential pair returned by a provider after the OAuth exchange
+type Token struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
+}
+
+// ImportedItem is one piece of profile content pulled from a provider
+type ImportedItem struct {
+	ExternalID  string
+	ContentType string
+	Value       string
+	URL         string
+}
+
+// Provider is implemented by every external profile source (Spotify, Instagram, ...)
+type Provider interface {
+	Name() string
+	AuthorizeURL(state string) string
+	ExchangeCode(ctx context.Context, code string) (Token, error)
+	RefreshToken(ctx context.Context, token Token) (Token, error)
+	FetchContent(ctx context.Context, accessToken string) ([]ImportedItem, error)
+}
+
+// Credentials of the app registered at a provider, read by config.Load from <PROVIDER>_CLIENT_ID,
+// <PROVIDER>_CLIENT_SECRET and <PROVIDER>_REDIRECT_URL. Without a client id the provider is left out
+type Credentials struct {
+	ClientID     string
+	ClientSecret string `validate:"required_with=ClientID"`
+	RedirectURL  string `validate:"required_with=ClientID,omitempty,url"`
+}
+
+// Config has the credentials of every provider
+type Config struct {
+	Spotify   Credentials
+	Instagram Credentials
+}
+
+// Registry keeps the configured providers by name
+type Registry map[string]Provider
+
+// NewRegistry registers only the providers that have a client id configured
+func NewRegistry(providers ...Provider) Registry {
+	registry := make(Registry)
+	for _, provider := range providers {
+		if provider != nil {
+			registry[provider.Name()] = provider
+		}
+	}
+	return registry
+}
+
+func (r Registry) Find(name string) (Provider, error) {
+	provider, ok := r[name]
+	if !ok {
+		return nil, fmt.Errorf("integration provider %s is not configured", name)
+	}
+	return provider, nil
+}
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+func (t tokenResponse) toToken(previous Token) Token {
+	token := Token{
+		AccessToken:  t.AccessToken,
+		RefreshToken: t.RefreshToken,
+		ExpiresAt:    time.Now().Add(time.Duration(t.ExpiresIn) * time.Second),
+	}
+	// Some providers only return a new refresh token when it rotates
+	if token.RefreshToken == "" {
+		token.RefreshToken = previous.RefreshToken
+	}
+	return token
+}
+
+func postForm(ctx context.Context, endpoint string, form url.Values, header http.Header, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	for key, values := range header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	return do(req, out)
+}
+
+func getJSON(ctx context.Context, endpoint string, header http.Header, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	for key, values := range header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	return do(req, out)
+}
+
+func do(req *http.Request, out interface{}) error {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s %s returned %d: %s", req.Method, req.URL.Host, resp.StatusCode, string(body))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,106 @@
+package integrations
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	spotifyAuthorizeURL = "https://accounts.spotify.com/authorize"
+	spotifyTokenURL     = "https://accounts.spotify.com/api/token"
+	spotifyTopArtists   = "https://api.spotify.com/v1/me/top/artists?limit=10&time_range=medium_term"
+)
+
+type SpotifyProvider struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+}
+
+// NewSpotifyProvider returns nil without a client id so the provider is not registered
+func NewSpotifyProvider(credentials Credentials) Provider {
+	if credentials.ClientID == "" {
+		return nil
+	}
+	return &SpotifyProvider{
+		ClientID:     credentials.ClientID,
+		ClientSecret: credentials.ClientSecret,
+		RedirectURL:  credentials.RedirectURL,
+	}
+}
+
+func (s SpotifyProvider) Name() string {
+	return "spotify"
+}
+
+func (s SpotifyProvider) AuthorizeURL(state string) string {
+	query := url.Values{
+		"client_id":     {s.ClientID},
+		"response_type": {"code"},
+		"redirect_uri":  {s.RedirectURL},
+		"scope":         {"user-top-read"},
+		"state":         {state},
+	}
+	return spotifyAuthorizeURL + "?" + query.Encode()
+}
+
+func (s SpotifyProvider) ExchangeCode(ctx context.Context, code string) (Token, error) {
+	form := url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {s.RedirectURL},
+	}
+	var res tokenResponse
+	if err := postForm(ctx, spotifyTokenURL, form, s.basicAuth(), &res); err != nil {
+		return Token{}, err
+	}
+	return res.toToken(Token{}), nil
+}
+
+func (s SpotifyProvider) RefreshToken(ctx context.Context, token Token) (Token, error) {
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {token.RefreshToken},
+	}
+	var res tokenResponse
+	if err := postForm(ctx, spotifyTokenURL, form, s.basicAuth(), &res); err != nil {
+		return Token{}, err
+	}
+	return res.toToken(token), nil
+}
+
+func (s SpotifyProvider) FetchContent(ctx context.Context, accessToken string) ([]ImportedItem, error) {
+	var res struct {
+		Items []struct {
+			ID     string   `json:"id"`
+			Name   string   `json:"name"`
+			Genres []string `json:"genres"`
+			URLs   struct {
+				Spotify string `json:"spotify"`
+			} `json:"external_urls"`
+		} `json:"items"`
+	}
+	header := http.Header{"Authorization": {"Bearer " + accessToken}}
+	if err := getJSON(ctx, spotifyTopArtists, header, &res); err != nil {
+		return nil, err
+	}
+
+	items := make([]ImportedItem, 0, len(res.Items))
+	for _, artist := range res.Items {
+		items = append(items, ImportedItem{
+			ExternalID:  artist.ID,
+			ContentType: "top_artist",
+			Value:       strings.TrimSpace(artist.Name),
+			URL:         artist.URLs.Spotify,
+		})
+	}
+	return items, nil
+}
+
+func (s SpotifyProvider) basicAuth() http.Header {
+	req := http.Request{Header: http.Header{}}
+	req.SetBasicAuth(s.ClientID, s.ClientSecret)
+	return req.Header
+}
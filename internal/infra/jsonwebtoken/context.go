@@ -0,0 +1,26 @@
+package jsonwebtoken
+
+import "context"
+
+// Context keys set by the auth middleware once it verified the bearer token of the request
+const (
+	claimsContextKey    = "claims"
+	accountIdContextKey = "account_id"
+)
+
+// WithClaims puts the claims of the verified token and its account id in the context
+func WithClaims(ctx context.Context, claims *JWTTokenClaims) context.Context {
+	ctx = context.WithValue(ctx, claimsContextKey, claims)
+	return context.WithValue(ctx, accountIdContextKey, claims.AccountId)
+}
+
+// ClaimsFromContext returns the claims the auth middleware verified, false outside an authenticated request
+func ClaimsFromContext(ctx context.Context) (*JWTTokenClaims, bool) {
+	claims, ok := ctx.Value(claimsContextKey).(*JWTTokenClaims)
+	return claims, ok && claims != nil
+}
+
+func AccountIdFromContext(ctx context.Context) (int64, bool) {
+	accountId, ok := ctx.Value(accountIdContextKey).(int64)
+	return accountId, ok
+}
@@ -3,28 +3,41 @@ package jsonwebtoken
 import (
 	"fmt"
 	"github.com/golang-jwt/jwt/v5"
-	"godating-dealls/internal/common"
 	"time"
 )
 
-var jwtSecret = []byte(common.StringEncoder("key-app-godating-dealls"))
+var jwtSecret []byte
+
+// SetSecret sets the key signing and verifying every token, it is called once at startup before serving
+func SetSecret(secret string) {
+	jwtSecret = []byte(secret)
+}
 
 type JWTTokenClaims struct {
 	UserId    int64  `json:"user_id"`
 	AccountId int64  `json:"account_id"`
 	Email     string `json:"email"`
 	Username  string `json:"username"`
+	// SessionId is the login session, it stays the same when the token is renewed with a refresh token
+	SessionId string `json:"sid,omitempty"`
+	// Scope is space separated as in OAuth, empty for a full session token
+	Scope string `json:"scope,omitempty"`
+	// Region is the home region of the account, empty when the service runs in a single region
+	Region string `json:"region,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func GenerateJWTToken(userId int64, accountId int64, email string) (string, error) {
+func GenerateJWTToken(userId int64, accountId int64, email string, sessionId string, region string) (string, error) {
 	expireAt := time.Now().Add(24 * time.Hour)
 	claims := JWTTokenClaims{
 		UserId:    userId,
 		AccountId: accountId,
 		Email:     email,
+		SessionId: sessionId,
+		Region:    region,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expireAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -40,7 +53,7 @@ func VerifyJWTToken(accessToken string) (*JWTTokenClaims, error) {
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*JWTTokenClaims); ok && token.Valid {
+	if claims, ok := token.Claims.(*JWTTokenClaims); ok && token.Valid && claims.Subject != "share_link" {
 		// Check if the token is expired
 		if claims.ExpiresAt.Time.Before(time.Now()) {
 			return nil, fmt.Errorf("token has expired")
@@ -0,0 +1,42 @@
+package jsonwebtoken
+
+import (
+	"context"
+	"testing"
+)
+
+func benchmarkToken(b *testing.B) string {
+	SetSecret("benchmark-only-secret-0123456789abcdef")
+	token, err := GenerateJWTToken(1, 1, "bench@godating.local", "bench", "")
+	if err != nil {
+		b.Fatal(err)
+	}
+	return token
+}
+
+func BenchmarkVerifyJWTToken(b *testing.B) {
+	token := benchmarkToken(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := VerifyJWTToken(token); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkClaimsFromContext is what a usecase pays for the claims once the auth middleware verified the token
+func BenchmarkClaimsFromContext(b *testing.B) {
+	claims, err := VerifyJWTToken(benchmarkToken(b))
+	if err != nil {
+		b.Fatal(err)
+	}
+	ctx := WithClaims(context.Background(), claims)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, ok := ClaimsFromContext(ctx); !ok {
+			b.Fatal("no claims in the context")
+		}
+	}
+}
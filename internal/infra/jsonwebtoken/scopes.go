@@ -0,0 +1,76 @@
+package jsonwebtoken
+
+import (
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Scopes guard the route groups, a token from login or refresh carries no scope claim and may use every route
+const (
+	// ScopeAccount covers security sensitive routes (logout, passkeys, recovery, purchases, minting tokens), it is never minted
+	ScopeAccount       = "account"
+	ScopeProfileRead   = "profile:read"
+	ScopeProfileWrite  = "profile:write"
+	ScopeDiscoverRead  = "discover:read"
+	ScopeDiscoverWrite = "discover:write"
+	ScopeChatRead      = "chat:read"
+	ScopeChatWrite     = "chat:write"
+)
+
+// MintableScopes can be put in a limited token, e.g. chat:read alone for a client that only holds the chat websocket
+var MintableScopes = []string{
+	ScopeProfileRead,
+	ScopeProfileWrite,
+	ScopeDiscoverRead,
+	ScopeDiscoverWrite,
+	ScopeChatRead,
+	ScopeChatWrite,
+}
+
+// HasScope reports whether the token may use routes of the scope
+func (c *JWTTokenClaims) HasScope(scope string) bool {
+	if c.Scope == "" {
+		return true
+	}
+	for _, granted := range strings.Fields(c.Scope) {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// GrantedScopes lists the scopes of the token, a full session token has all of them
+func (c *JWTTokenClaims) GrantedScopes() []string {
+	if c.Scope == "" {
+		return append([]string{ScopeAccount}, MintableScopes...)
+	}
+	return strings.Fields(c.Scope)
+}
+
+// GenerateScopedJWTToken mints a token limited to the scopes, in the same session as the token it is minted from
+func GenerateScopedJWTToken(claims *JWTTokenClaims, scopes []string, expireAt time.Time) (string, error) {
+	if len(scopes) == 0 {
+		return "", fmt.Errorf("scoped token needs at least one scope")
+	}
+	sorted := append([]string(nil), scopes...)
+	sort.Strings(sorted)
+
+	scoped := JWTTokenClaims{
+		UserId:    claims.UserId,
+		AccountId: claims.AccountId,
+		Email:     claims.Email,
+		SessionId: claims.SessionId,
+		Scope:     strings.Join(sorted, " "),
+		Region:    claims.Region,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expireAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, scoped)
+	return token.SignedString(jwtSecret)
+}
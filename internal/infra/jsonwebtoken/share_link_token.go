@@ -0,0 +1,41 @@
+package jsonwebtoken
+
+import (
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"time"
+)
+
+// ShareLinkClaims identifies a public profile share link, the account itself is never put in the token
+type ShareLinkClaims struct {
+	LinkId int64 `json:"link_id"`
+	jwt.RegisteredClaims
+}
+
+func GenerateShareLinkToken(linkId int64, expireAt time.Time) (string, error) {
+	claims := ShareLinkClaims{
+		LinkId: linkId,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "share_link",
+			ExpiresAt: jwt.NewNumericDate(expireAt),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(jwtSecret)
+}
+
+func VerifyShareLinkToken(shareToken string) (*ShareLinkClaims, error) {
+	token, err := jwt.ParseWithClaims(shareToken, &ShareLinkClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return jwtSecret, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Subject keeps an access token from being accepted as a share link and the other way around
+	if claims, ok := token.Claims.(*ShareLinkClaims); ok && token.Valid && claims.Subject == "share_link" {
+		return claims, nil
+	}
+	return nil, fmt.Errorf("invalid share link")
+}
@@ -0,0 +1,232 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// driftRecheckInterval a drifted schema is looked at again at most this often, so the instance becomes ready once
+// cmd/migrate ran without restarting it
+const driftRecheckInterval = 30 * time.Second
+
+const (
+	columnInteger = "integer"
+	columnText    = "text"
+	columnTime    = "time"
+)
+
+type expectedColumn struct {
+	Name string
+	Kind string
+}
+
+// criticalColumns are the columns the queries rely on, mostly the ones later migrations added. A database that lacks
+// them, or has them with another type, answers those queries with an error that says nothing about the schema.
+// A migration adding a column the queries rely on adds it here too
+var criticalColumns = []struct {
+	Table   string
+	Columns []expectedColumn
+}{
+	{"accounts", []expectedColumn{{"account_id", columnInteger}, {"username", columnText}, {"password_hash", columnText}, {"email", columnText}, {"verified", columnInteger}, {"provider", columnText}, {"provider_id", columnText}, {"home_region", columnText}}},
+	{"users", []expectedColumn{{"user_id", columnInteger}, {"account_id", columnInteger}, {"full_name", columnText}, {"date_of_birth", columnTime}}},
+	{"swipes", []expectedColumn{{"swipe_id", columnInteger}, {"account_id", columnInteger}, {"account_id_swipe", columnInteger}, {"swipe_date", columnTime}}},
+	{"daily_quotas", []expectedColumn{{"account_id", columnInteger}, {"date", columnTime}, {"total_quota", columnInteger}, {"swipe_count", columnInteger}, {"super_like_count", columnInteger}}},
+	{"matches", []expectedColumn{{"match_id", columnInteger}, {"account_id_low", columnInteger}, {"account_id_high", columnInteger}}},
+	{"messages", []expectedColumn{{"message_id", columnInteger}, {"match_id", columnInteger}, {"sender_account_id", columnInteger}, {"body", columnText}}},
+	{"profile_photos", []expectedColumn{{"deleted_at", columnTime}}},
+	{"super_likes", []expectedColumn{{"account_id", columnInteger}, {"target_account_id", columnInteger}, {"swipe_id", columnInteger}}},
+	{"device_tokens", []expectedColumn{{"account_id", columnInteger}, {"provider", columnText}, {"token", columnText}}},
+	{"push_notifications", []expectedColumn{{"account_id", columnInteger}, {"status", columnText}, {"next_attempt_at", columnTime}}},
+	{"notifications", []expectedColumn{{"account_id", columnInteger}, {"kind", columnText}, {"read_at", columnTime}}},
+	{"outbox_events", []expectedColumn{{"event_id", columnInteger}, {"kind", columnText}, {"payload", columnText}, {"next_attempt_at", columnTime}, {"published_at", columnTime}}},
+	{"safety_settings", []expectedColumn{{"account_id", columnInteger}, {"late_night_mode", columnInteger}}},
+}
+
+// DriftReport compares the database with this build, the schema drifted when a migration is pending or was edited
+// after it was applied, or a critical column is missing or has another type. Migrations newer than the build are
+// reported but are no drift, a rolled back deploy runs on the newer schema
+type DriftReport struct {
+	ExpectedVersion   int
+	AppliedVersion    int
+	PendingMigrations []string
+	EditedMigrations  []string
+	UnknownMigrations []int
+	MissingTables     []string
+	MissingColumns    []string
+	MismatchedColumns []string
+	CheckedAt         time.Time
+}
+
+func (r DriftReport) Drifted() bool {
+	return len(r.PendingMigrations) > 0 || len(r.EditedMigrations) > 0 || len(r.MissingTables) > 0 ||
+		len(r.MissingColumns) > 0 || len(r.MismatchedColumns) > 0
+}
+
+// Problems lists the drift one line each, for the log
+func (r DriftReport) Problems() []string {
+	var problems []string
+	for _, migration := range r.PendingMigrations {
+		problems = append(problems, "migration "+migration+" is not applied")
+	}
+	for _, migration := range r.EditedMigrations {
+		problems = append(problems, "migration "+migration+" was edited after it was applied")
+	}
+	for _, table := range r.MissingTables {
+		problems = append(problems, "table "+table+" is missing")
+	}
+	for _, column := range r.MissingColumns {
+		problems = append(problems, "column "+column+" is missing")
+	}
+	problems = append(problems, r.MismatchedColumns...)
+	return problems
+}
+
+// CheckDrift reads the applied migrations without the migration lock, a migration running elsewhere shows up as
+// pending until it is recorded
+func (m *Migrator) CheckDrift(ctx context.Context) (DriftReport, error) {
+	report := DriftReport{CheckedAt: time.Now()}
+	if err := m.DB.PingContext(ctx); err != nil {
+		return report, fmt.Errorf("could not reach database: %v", err)
+	}
+
+	migrations, err := Load()
+	if err != nil {
+		return report, err
+	}
+	// A database nobody migrated has no schema_migrations, every migration is pending then
+	applied := map[int]appliedMigration{}
+	if tableExists(ctx, m.DB, "schema_migrations") {
+		conn, err := m.DB.Conn(ctx)
+		if err != nil {
+			return report, fmt.Errorf("could not get connection: %v", err)
+		}
+		applied, err = appliedMigrations(ctx, conn)
+		conn.Close()
+		if err != nil {
+			return report, err
+		}
+	}
+
+	known := map[int]bool{}
+	for _, migration := range migrations {
+		known[migration.Version] = true
+		report.ExpectedVersion = migration.Version
+		record, ok := applied[migration.Version]
+		switch {
+		case !ok:
+			report.PendingMigrations = append(report.PendingMigrations, fmt.Sprintf("%d_%s", migration.Version, migration.Name))
+		case record.checksum != migration.Checksum:
+			report.EditedMigrations = append(report.EditedMigrations, fmt.Sprintf("%d_%s", migration.Version, migration.Name))
+		}
+	}
+	for version := range applied {
+		if version > report.AppliedVersion {
+			report.AppliedVersion = version
+		}
+		if !known[version] {
+			report.UnknownMigrations = append(report.UnknownMigrations, version)
+		}
+	}
+
+	for _, table := range criticalColumns {
+		if !tableExists(ctx, m.DB, table.Table) {
+			report.MissingTables = append(report.MissingTables, table.Table)
+			continue
+		}
+		for _, column := range table.Columns {
+			kind, ok := columnKind(ctx, m.DB, table.Table, column.Name)
+			switch {
+			case !ok:
+				report.MissingColumns = append(report.MissingColumns, table.Table+"."+column.Name)
+			case kind != "" && kind != column.Kind:
+				report.MismatchedColumns = append(report.MismatchedColumns,
+					fmt.Sprintf("column %s.%s is %s, expected %s", table.Table, column.Name, kind, column.Kind))
+			}
+		}
+	}
+	return report, nil
+}
+
+// tableExists and columnKind select nothing from the table, it works the same on MySQL and on the sqlite of the
+// development mode. A failing query means the table or the column does not exist, the database answered the ping
+func tableExists(ctx context.Context, db *sql.DB, table string) bool {
+	rows, err := db.QueryContext(ctx, "SELECT 1 FROM "+table+" LIMIT 0")
+	if err != nil {
+		return false
+	}
+	rows.Close()
+	return true
+}
+
+// columnKind returns the kind of the declared type, empty for a type it does not know
+func columnKind(ctx context.Context, db *sql.DB, table string, column string) (string, bool) {
+	rows, err := db.QueryContext(ctx, "SELECT "+column+" FROM "+table+" LIMIT 0")
+	if err != nil {
+		return "", false
+	}
+	defer rows.Close()
+	types, err := rows.ColumnTypes()
+	if err != nil || len(types) == 0 {
+		return "", true
+	}
+
+	typeName := strings.ToUpper(types[0].DatabaseTypeName())
+	switch {
+	case strings.Contains(typeName, "INT"), strings.HasPrefix(typeName, "BOOL"):
+		return columnInteger, true
+	case strings.Contains(typeName, "CHAR"), strings.Contains(typeName, "TEXT"), strings.HasPrefix(typeName, "ENUM"):
+		return columnText, true
+	case strings.Contains(typeName, "DATE"), strings.Contains(typeName, "TIME"):
+		return columnTime, true
+	}
+	return "", true
+}
+
+// SchemaGuard keeps the drift report of the instance for the readiness probe. A clean schema is not checked again,
+// the migrations of a later deploy come with a new instance
+type SchemaGuard struct {
+	migrator *Migrator
+	mu       sync.Mutex
+	report   DriftReport
+	err      error
+}
+
+func NewSchemaGuard(db *sql.DB) *SchemaGuard {
+	return &SchemaGuard{migrator: NewMigrator(db)}
+}
+
+// Check compares the schema now and logs the drift it found
+func (g *SchemaGuard) Check(ctx context.Context) (DriftReport, error) {
+	report, err := g.migrator.CheckDrift(ctx)
+	g.mu.Lock()
+	g.report, g.err = report, err
+	g.mu.Unlock()
+
+	switch {
+	case err != nil:
+		log.Printf("Schema check failed: %v", err)
+	case report.Drifted():
+		log.Printf("Schema drift detected, the database is at migration %d and this build expects %d:", report.AppliedVersion, report.ExpectedVersion)
+		for _, problem := range report.Problems() {
+			log.Printf("  %s", problem)
+		}
+	}
+	return report, err
+}
+
+// Report returns the last report, checking again when it drifted or failed at least driftRecheckInterval ago
+func (g *SchemaGuard) Report(ctx context.Context) (DriftReport, error) {
+	g.mu.Lock()
+	report, err := g.report, g.err
+	g.mu.Unlock()
+
+	if (err != nil || report.Drifted()) && time.Since(report.CheckedAt) >= driftRecheckInterval {
+		return g.Check(ctx)
+	}
+	return report, err
+}
@@ -0,0 +1,289 @@
+package migrations
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The schema is versioned as sql/<version>_<name>.sql, a change to the schema is a new file with the next version.
+// A file already applied somewhere must not be edited, its checksum is compared on every run
+//
+//go:embed sql/*.sql
+var files embed.FS
+
+// lockName serializes the instances migrating the same database, e.g. several replicas starting at once
+const lockName = "godating_schema_migrations"
+
+const lockTimeoutSeconds = 60
+
+type Migration struct {
+	Version  int
+	Name     string
+	SQL      string
+	Checksum string
+}
+
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Load returns the embedded migrations in version order
+func Load() ([]Migration, error) {
+	entries, err := fs.ReadDir(files, "sql")
+	if err != nil {
+		return nil, fmt.Errorf("could not read migrations: %v", err)
+	}
+
+	var migrations []Migration
+	seen := map[int]string{}
+	for _, entry := range entries {
+		versionPart, name, ok := strings.Cut(strings.TrimSuffix(entry.Name(), ".sql"), "_")
+		version, err := strconv.Atoi(versionPart)
+		if !ok || err != nil || version <= 0 || name == "" {
+			return nil, fmt.Errorf("migration %s is not named <version>_<name>.sql", entry.Name())
+		}
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("migrations %s and %s have the same version", other, entry.Name())
+		}
+		seen[version] = entry.Name()
+
+		content, err := files.ReadFile(path.Join("sql", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("could not read migration %s: %v", entry.Name(), err)
+		}
+		sum := sha256.Sum256(content)
+		migrations = append(migrations, Migration{
+			Version:  version,
+			Name:     name,
+			SQL:      string(content),
+			Checksum: hex.EncodeToString(sum[:]),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+type Migrator struct {
+	DB *sql.DB
+}
+
+func NewMigrator(db *sql.DB) *Migrator {
+	return &Migrator{DB: db}
+}
+
+// Up applies the pending migrations in order and returns them. MySQL commits every DDL statement on its own,
+// so a migration failing halfway is not recorded and has to be finished by hand before running again
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		pending, err := m.pending(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range pending {
+			for i, statement := range splitStatements(migration.SQL) {
+				if _, err := conn.ExecContext(ctx, statement); err != nil {
+					return fmt.Errorf("migration %d_%s failed at statement %d: %v", migration.Version, migration.Name, i+1, err)
+				}
+			}
+			if err := recordMigration(ctx, conn, migration); err != nil {
+				return err
+			}
+			log.Printf("Applied migration %d_%s", migration.Version, migration.Name)
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Baseline records the migrations up to version as applied without running them,
+// for a database created before the migrations existed
+func (m *Migrator) Baseline(ctx context.Context, version int) ([]Migration, error) {
+	var baselined []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		pending, err := m.pending(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range pending {
+			if migration.Version > version {
+				break
+			}
+			if err := recordMigration(ctx, conn, migration); err != nil {
+				return err
+			}
+			baselined = append(baselined, migration)
+		}
+		return nil
+	})
+	return baselined, err
+}
+
+// Status returns every migration with the time it was applied, nil while it is pending
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		migrations, err := Load()
+		if err != nil {
+			return err
+		}
+		applied, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range migrations {
+			status := Status{Migration: migration}
+			if record, ok := applied[migration.Version]; ok {
+				appliedAt := record.appliedAt
+				status.AppliedAt = &appliedAt
+			}
+			statuses = append(statuses, status)
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// pending refuses to go on when an applied migration was edited or is no longer embedded
+func (m *Migrator) pending(ctx context.Context, conn *sql.Conn) ([]Migration, error) {
+	migrations, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	known := map[int]bool{}
+	var pending []Migration
+	for _, migration := range migrations {
+		known[migration.Version] = true
+		record, ok := applied[migration.Version]
+		if !ok {
+			pending = append(pending, migration)
+			continue
+		}
+		if record.checksum != migration.Checksum {
+			return nil, fmt.Errorf("migration %d_%s was edited after it was applied", migration.Version, migration.Name)
+		}
+	}
+	for version := range applied {
+		if !known[version] {
+			return nil, fmt.Errorf("migration %d is applied but not known to this build", version)
+		}
+	}
+	return pending, nil
+}
+
+// withLock runs fn on one connection holding a named lock, MySQL releases it with the connection
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.DB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("could not get connection: %v", err)
+	}
+	defer conn.Close()
+
+	var locked sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", lockName, lockTimeoutSeconds).Scan(&locked); err != nil {
+		return fmt.Errorf("could not take migration lock: %v", err)
+	}
+	if locked.Int64 != 1 {
+		return fmt.Errorf("could not take migration lock within %d seconds", lockTimeoutSeconds)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), "SELECT RELEASE_LOCK(?)", lockName); err != nil {
+			log.Printf("Failed to release migration lock: %v", err)
+		}
+	}()
+
+	query := `CREATE TABLE IF NOT EXISTS schema_migrations
+(
+    version    INTEGER PRIMARY KEY,
+    name       VARCHAR(255) NOT NULL,
+    checksum   CHAR(64)     NOT NULL,
+    applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+)`
+	if _, err := conn.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("could not create schema_migrations: %v", err)
+	}
+	return fn(conn)
+}
+
+type appliedMigration struct {
+	checksum  string
+	appliedAt time.Time
+}
+
+func appliedMigrations(ctx context.Context, conn *sql.Conn) (map[int]appliedMigration, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, checksum, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("could not query schema_migrations: %v", err)
+	}
+	defer rows.Close()
+
+	applied := map[int]appliedMigration{}
+	for rows.Next() {
+		var version int
+		var record appliedMigration
+		if err := rows.Scan(&version, &record.checksum, &record.appliedAt); err != nil {
+			return nil, fmt.Errorf("could not scan schema_migrations: %v", err)
+		}
+		applied[version] = record
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+	return applied, nil
+}
+
+func recordMigration(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	_, err := conn.ExecContext(ctx, "INSERT INTO schema_migrations (version, name, checksum) VALUES (?, ?, ?)",
+		migration.Version, migration.Name, migration.Checksum)
+	if err != nil {
+		return fmt.Errorf("could not record migration %d_%s: %v", migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+// splitStatements splits on a semicolon ending a line, the driver runs one statement per call.
+// Lines starting with -- are comments
+func splitStatements(content string) []string {
+	var statements []string
+	var current strings.Builder
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "--") || (trimmed == "" && current.Len() == 0) {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
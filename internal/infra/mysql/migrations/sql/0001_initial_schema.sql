@@ -0,0 +1,634 @@
+CREATE TABLE accounts
+(
+    account_id    INTEGER AUTO_INCREMENT PRIMARY KEY,
+    username      VARCHAR(255) UNIQUE NOT NULL,
+    password_hash VARCHAR(255)        NOT NULL,
+    email         VARCHAR(255) UNIQUE NOT NULL,
+    verified      BOOLEAN   DEFAULT FALSE,
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE users
+(
+    user_id       INTEGER AUTO_INCREMENT PRIMARY KEY,
+    account_id    INTEGER NOT NULL,
+    full_name     VARCHAR(255),
+    date_of_birth DATE,
+    age           INTEGER,
+    gender        VARCHAR(5),
+    address       VARCHAR(255),
+    bio           TEXT,
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE storages
+(
+    storage_id  INTEGER AUTO_INCREMENT PRIMARY KEY,
+    account_id  INTEGER NOT NULL,
+    user_id     INTEGER NOT NULL,
+    videos_path VARCHAR(255),
+    photos_path VARCHAR(255),
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users (user_id),
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE swipes
+(
+    swipe_id   INTEGER AUTO_INCREMENT PRIMARY KEY,
+    account_id INTEGER NOT NULL,
+    user_id    INTEGER NOT NULL,
+    action     VARCHAR(10) CHECK (action IN ('PASSED', 'LIKED')
+) ,
+    account_id_swipe INTEGER NOT NULL,
+    swipe_date DATE DEFAULT (CURRENT_DATE),
+    FOREIGN KEY (user_id) REFERENCES users (user_id),
+    FOREIGN KEY (account_id) REFERENCES accounts(account_id),
+        FOREIGN KEY (account_id_swipe) REFERENCES accounts(account_id)
+);
+
+CREATE TABLE daily_quotas
+(
+    quota_id    INTEGER AUTO_INCREMENT PRIMARY KEY,
+    account_id  INTEGER NOT NULL,
+    date        DATE    DEFAULT (CURRENT_DATE),
+    total_quota INTEGER DEFAULT 0,
+    swipe_count INTEGER DEFAULT 0,
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE packages
+(
+    package_id                  INTEGER AUTO_INCREMENT PRIMARY KEY,
+    package_name                VARCHAR(255),
+    description                 TEXT,
+    package_duration_in_monthly INTEGER,
+    price                       NUMERIC,
+    unlimited_swipes            BOOLEAN   DEFAULT FALSE,
+    status                      BOOLEAN   DEFAULT FALSE,
+    created_at                  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at                  TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE account_premiums
+(
+    purchase_id             INTEGER AUTO_INCREMENT PRIMARY KEY,
+    account_id              INTEGER NOT NULL,
+    package_id              INTEGER NOT NULL,
+    purchase_date           TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    expiry_date             TIMESTAMP,
+    unlimited_swipes_active BOOLEAN   DEFAULT FALSE,
+    status                  BOOLEAN   DEFAULT FALSE,
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id),
+    FOREIGN KEY (package_id) REFERENCES packages (package_id)
+);
+
+CREATE TABLE view_accounts
+(
+    view_id    INTEGER AUTO_INCREMENT PRIMARY KEY,
+    account_id INTEGER NOT NULL,
+    user_id    INTEGER NOT NULL,
+    date       DATE DEFAULT (CURRENT_DATE),
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id),
+    FOREIGN KEY (user_id) REFERENCES users (user_id)
+);
+
+CREATE TABLE login_histories
+(
+    login_histories_id INTEGER AUTO_INCREMENT PRIMARY KEY,
+    user_id            INTEGER NOT NULL,
+    account_id         INTEGER NOT NULL,
+    login_at           TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    logout_at          TIMESTAMP DEFAULT NULL,
+    duration_in_seconds DOUBLE DEFAULT NULL,
+    FOREIGN KEY (user_id) REFERENCES users (user_id),
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE selection_histories
+(
+    selection_history_id  INTEGER AUTO_INCREMENT PRIMARY KEY,
+    account_id_identifier INTEGER,
+    account_id            INTEGER,
+    selection_date        DATE DEFAULT (CURRENT_DATE),
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id),
+    FOREIGN KEY (account_id_identifier) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE task_histories
+(
+    task_id               INTEGER AUTO_INCREMENT PRIMARY KEY,
+    account_id_identifier INTEGER,
+    task_name             VARCHAR(255) NOT NULL,
+    last_run_timestamp    BIGINT       NOT NULL,
+    FOREIGN KEY (account_id_identifier) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE notes
+(
+    note_id           INTEGER AUTO_INCREMENT PRIMARY KEY,
+    owner_account_id  INTEGER NOT NULL,
+    target_account_id INTEGER NOT NULL,
+    content           TEXT    NOT NULL,
+    created_at        TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at        TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_notes_owner_target (owner_account_id, target_account_id),
+    FOREIGN KEY (owner_account_id) REFERENCES accounts (account_id),
+    FOREIGN KEY (target_account_id) REFERENCES accounts (account_id)
+);
+
+ALTER TABLE users
+    ADD COLUMN phone_hash CHAR(64) DEFAULT NULL,
+    ADD INDEX idx_users_phone_hash (phone_hash);
+
+CREATE TABLE contact_exclusions
+(
+    exclusion_id INTEGER AUTO_INCREMENT PRIMARY KEY,
+    account_id   INTEGER  NOT NULL,
+    contact_hash CHAR(64) NOT NULL,
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_contact_exclusions_account_hash (account_id, contact_hash),
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE profile_integrations
+(
+    integration_id INTEGER AUTO_INCREMENT PRIMARY KEY,
+    account_id     INTEGER      NOT NULL,
+    provider       VARCHAR(32)  NOT NULL,
+    access_token   TEXT         NOT NULL,
+    refresh_token  TEXT,
+    expires_at     TIMESTAMP    NULL,
+    last_synced_at TIMESTAMP    NULL,
+    created_at     TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_profile_integrations_account_provider (account_id, provider),
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE profile_imports
+(
+    import_id     INTEGER AUTO_INCREMENT PRIMARY KEY,
+    account_id    INTEGER      NOT NULL,
+    provider      VARCHAR(32)  NOT NULL,
+    content_type  VARCHAR(32)  NOT NULL,
+    external_id   VARCHAR(255) NOT NULL,
+    content_value VARCHAR(255) NOT NULL,
+    content_url   TEXT,
+    imported_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_profile_imports_account_provider (account_id, provider),
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE profile_share_links
+(
+    link_id    INTEGER AUTO_INCREMENT PRIMARY KEY,
+    account_id INTEGER   NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NULL,
+    view_count INTEGER   NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_profile_share_links_account (account_id),
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE login_streaks
+(
+    account_id       INTEGER PRIMARY KEY,
+    timezone         VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    current_streak   INTEGER     NOT NULL DEFAULT 0,
+    longest_streak   INTEGER     NOT NULL DEFAULT 0,
+    last_login_day   DATE        NOT NULL,
+    last_claimed_day DATE        NULL,
+    updated_at       TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE admin_audit_logs
+(
+    audit_id          INTEGER AUTO_INCREMENT PRIMARY KEY,
+    actor             VARCHAR(255) NOT NULL,
+    action            VARCHAR(64)  NOT NULL,
+    target_account_id INTEGER      NOT NULL,
+    justification     TEXT         NOT NULL,
+    changes           JSON,
+    created_at        TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_admin_audit_logs_target (target_account_id),
+    FOREIGN KEY (target_account_id) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE quota_usage_rollups
+(
+    rollup_date        DATE        NOT NULL,
+    tier               VARCHAR(16) NOT NULL,
+    accounts           INTEGER     NOT NULL DEFAULT 0,
+    active_accounts    INTEGER     NOT NULL DEFAULT 0,
+    total_swipes       INTEGER     NOT NULL DEFAULT 0,
+    total_likes        INTEGER     NOT NULL DEFAULT 0,
+    exhausted_accounts INTEGER     NOT NULL DEFAULT 0,
+    created_at         TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (rollup_date, tier)
+);
+
+CREATE TABLE account_dormancy
+(
+    account_id     INTEGER PRIMARY KEY,
+    state          VARCHAR(16) NOT NULL,
+    last_active_at TIMESTAMP   NOT NULL,
+    updated_at     TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_account_dormancy_state (state),
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE account_dormancy_events
+(
+    event_id       INTEGER AUTO_INCREMENT PRIMARY KEY,
+    account_id     INTEGER     NOT NULL,
+    from_state     VARCHAR(16) NOT NULL,
+    to_state       VARCHAR(16) NOT NULL,
+    last_active_at TIMESTAMP   NOT NULL,
+    occurred_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_account_dormancy_events_account (account_id),
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE login_history_summaries
+(
+    account_id             INTEGER   NOT NULL,
+    summary_month          DATE      NOT NULL,
+    login_count            INTEGER   NOT NULL DEFAULT 0,
+    closed_sessions        INTEGER   NOT NULL DEFAULT 0,
+    total_duration_seconds DOUBLE    NOT NULL DEFAULT 0,
+    max_duration_seconds   DOUBLE    NOT NULL DEFAULT 0,
+    first_login_at         TIMESTAMP NOT NULL,
+    last_login_at          TIMESTAMP NOT NULL,
+    PRIMARY KEY (account_id, summary_month),
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id)
+);
+
+CREATE INDEX idx_login_histories_login_at ON login_histories (login_at);
+
+CREATE TABLE passkey_credentials
+(
+    credential_id VARBINARY(1023) PRIMARY KEY,
+    account_id    INTEGER      NOT NULL,
+    name          VARCHAR(100) NOT NULL,
+    public_key    BLOB         NOT NULL,
+    sign_count    INTEGER UNSIGNED NOT NULL DEFAULT 0,
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_used_at  TIMESTAMP NULL DEFAULT NULL,
+    INDEX idx_passkey_credentials_account (account_id),
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE recovery_contacts
+(
+    account_id         INTEGER NOT NULL,
+    contact_account_id INTEGER NOT NULL,
+    created_at         TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (account_id, contact_account_id),
+    INDEX idx_recovery_contacts_contact (contact_account_id),
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id),
+    FOREIGN KEY (contact_account_id) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE recovery_settings
+(
+    account_id INTEGER PRIMARY KEY,
+    threshold  INTEGER NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE recovery_requests
+(
+    request_id         INTEGER AUTO_INCREMENT PRIMARY KEY,
+    account_id         INTEGER     NOT NULL,
+    token_hash         CHAR(64)    NOT NULL UNIQUE,
+    status             VARCHAR(16) NOT NULL DEFAULT 'pending',
+    approvals_required INTEGER     NOT NULL,
+    failed_attempts    INTEGER     NOT NULL DEFAULT 0,
+    created_at         TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    available_at       TIMESTAMP   NOT NULL,
+    expires_at         TIMESTAMP   NOT NULL,
+    closed_at          TIMESTAMP   NULL DEFAULT NULL,
+    INDEX idx_recovery_requests_account_status (account_id, status),
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE recovery_approvals
+(
+    request_id         INTEGER  NOT NULL,
+    contact_account_id INTEGER  NOT NULL,
+    code_hash          CHAR(64) NOT NULL,
+    approved_at        TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (request_id, contact_account_id),
+    FOREIGN KEY (request_id) REFERENCES recovery_requests (request_id),
+    FOREIGN KEY (contact_account_id) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE account_features
+(
+    account_id         INTEGER PRIMARY KEY,
+    logins_7d          INTEGER   NOT NULL DEFAULT 0,
+    logins_30d         INTEGER   NOT NULL DEFAULT 0,
+    active_days_30d    INTEGER   NOT NULL DEFAULT 0,
+    likes_sent_30d     INTEGER   NOT NULL DEFAULT 0,
+    passes_sent_30d    INTEGER   NOT NULL DEFAULT 0,
+    likes_received_30d INTEGER   NOT NULL DEFAULT 0,
+    response_rate      DOUBLE    NULL DEFAULT NULL,
+    interests          INTEGER   NOT NULL DEFAULT 0,
+    computed_at        TIMESTAMP NOT NULL,
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE pair_features
+(
+    account_id           INTEGER   NOT NULL,
+    candidate_account_id INTEGER   NOT NULL,
+    shared_interests     INTEGER   NOT NULL,
+    computed_at          TIMESTAMP NOT NULL,
+    PRIMARY KEY (account_id, candidate_account_id),
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id),
+    FOREIGN KEY (candidate_account_id) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE match_scores
+(
+    account_id           INTEGER     NOT NULL,
+    candidate_account_id INTEGER     NOT NULL,
+    score                DOUBLE      NOT NULL,
+    model_version        VARCHAR(64) NOT NULL,
+    scored_at            TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (account_id, candidate_account_id),
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id),
+    FOREIGN KEY (candidate_account_id) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE client_config_bundles
+(
+    bundle_id       INTEGER AUTO_INCREMENT PRIMARY KEY,
+    name            VARCHAR(64) NOT NULL,
+    version         INTEGER     NOT NULL,
+    min_app_version VARCHAR(32) NOT NULL DEFAULT '0',
+    max_app_version VARCHAR(32) NULL DEFAULT NULL,
+    rollout_percent INTEGER     NOT NULL DEFAULT 0,
+    payload         JSON        NOT NULL,
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_client_config_bundles_name_version (name, version)
+);
+
+CREATE TABLE status_messages
+(
+    message_id INTEGER AUTO_INCREMENT PRIMARY KEY,
+    severity   VARCHAR(16)  NOT NULL,
+    title      VARCHAR(120) NOT NULL,
+    message    VARCHAR(500) NOT NULL,
+    starts_at  TIMESTAMP    NOT NULL,
+    ends_at    TIMESTAMP    NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_status_messages_ends_at (ends_at)
+);
+
+CREATE TABLE profile_change_requests
+(
+    request_id      INTEGER AUTO_INCREMENT PRIMARY KEY,
+    account_id      INTEGER      NOT NULL,
+    field           VARCHAR(32)  NOT NULL,
+    current_value   VARCHAR(255) NOT NULL,
+    requested_value VARCHAR(255) NOT NULL,
+    evidence        VARCHAR(500) NOT NULL,
+    reason          VARCHAR(500) NOT NULL,
+    status          VARCHAR(16)  NOT NULL DEFAULT 'pending',
+    reviewer        VARCHAR(255) NULL DEFAULT NULL,
+    review_note     VARCHAR(1000) NULL DEFAULT NULL,
+    audit_id        INTEGER      NULL DEFAULT NULL,
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    reviewed_at     TIMESTAMP    NULL DEFAULT NULL,
+    pending_field   VARCHAR(32) AS (IF(status = 'pending', field, NULL)) STORED,
+    UNIQUE KEY uq_profile_change_requests_pending (account_id, pending_field),
+    INDEX idx_profile_change_requests_status (status, created_at),
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE matches
+(
+    match_id        INTEGER AUTO_INCREMENT PRIMARY KEY,
+    account_id_low  INTEGER NOT NULL,
+    account_id_high INTEGER NOT NULL,
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_matches_pair (account_id_low, account_id_high),
+    INDEX idx_matches_account_id_high (account_id_high),
+    FOREIGN KEY (account_id_low) REFERENCES accounts (account_id),
+    FOREIGN KEY (account_id_high) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE messages
+(
+    message_id        INTEGER AUTO_INCREMENT PRIMARY KEY,
+    match_id          INTEGER       NOT NULL,
+    sender_account_id INTEGER       NOT NULL,
+    body              VARCHAR(2000) NOT NULL,
+    created_at        TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_messages_match_id (match_id, message_id),
+    FOREIGN KEY (match_id) REFERENCES matches (match_id),
+    FOREIGN KEY (sender_account_id) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE profile_photos
+(
+    photo_id     INTEGER AUTO_INCREMENT PRIMARY KEY,
+    account_id   INTEGER      NOT NULL,
+    storage_key  VARCHAR(255) NOT NULL,
+    content_type VARCHAR(32)  NOT NULL,
+    size_bytes   INTEGER      NOT NULL,
+    position     TINYINT      NOT NULL,
+    is_primary   BOOLEAN      NOT NULL DEFAULT FALSE,
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_profile_photos_storage_key (storage_key),
+    INDEX idx_profile_photos_account_id (account_id, position),
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id)
+);
+
+ALTER TABLE users
+    ADD COLUMN latitude            DECIMAL(9, 6) DEFAULT NULL,
+    ADD COLUMN longitude           DECIMAL(9, 6) DEFAULT NULL,
+    ADD COLUMN location_updated_at TIMESTAMP     NULL DEFAULT NULL;
+
+CREATE TABLE network_rules
+(
+    rule_id     INTEGER AUTO_INCREMENT PRIMARY KEY,
+    list        ENUM ('deny', 'allow') NOT NULL,
+    route_group VARCHAR(16)            NOT NULL,
+    cidr        VARCHAR(64)            NOT NULL,
+    note        VARCHAR(255)           NOT NULL DEFAULT '',
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_network_rules (list, route_group, cidr)
+);
+
+UPDATE account_premiums SET status = TRUE WHERE status = FALSE;
+
+CREATE INDEX idx_account_premiums_account_id ON account_premiums (account_id, status, expiry_date);
+
+CREATE TABLE smart_photo_settings
+(
+    account_id INTEGER PRIMARY KEY,
+    enabled    BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE photo_impressions
+(
+    owner_account_id  INTEGER NOT NULL,
+    viewer_account_id INTEGER NOT NULL,
+    photo_id          INTEGER NOT NULL,
+    liked             BOOLEAN NOT NULL DEFAULT FALSE,
+    shown_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (owner_account_id, viewer_account_id),
+    INDEX idx_photo_impressions_photo_id (photo_id),
+    FOREIGN KEY (photo_id) REFERENCES profile_photos (photo_id) ON DELETE CASCADE
+);
+
+CREATE TABLE hidden_accounts
+(
+    account_id        INTEGER NOT NULL,
+    hidden_account_id INTEGER NOT NULL,
+    created_at        TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (account_id, hidden_account_id),
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id),
+    FOREIGN KEY (hidden_account_id) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE duos
+(
+    duo_id                INTEGER AUTO_INCREMENT PRIMARY KEY,
+    account_id_low        INTEGER                                 NOT NULL,
+    account_id_high       INTEGER                                 NOT NULL,
+    invited_by_account_id INTEGER                                 NOT NULL,
+    state                 ENUM ('pending', 'active', 'unlinked') NOT NULL DEFAULT 'pending',
+    created_at            TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    linked_at             TIMESTAMP                               NULL,
+    unlinked_at           TIMESTAMP                               NULL,
+    INDEX idx_duos_account_id_low (account_id_low, state),
+    INDEX idx_duos_account_id_high (account_id_high, state),
+    FOREIGN KEY (account_id_low) REFERENCES accounts (account_id),
+    FOREIGN KEY (account_id_high) REFERENCES accounts (account_id),
+    FOREIGN KEY (invited_by_account_id) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE duo_swipes
+(
+    duo_id               INTEGER                  NOT NULL,
+    target_duo_id        INTEGER                  NOT NULL,
+    swiped_by_account_id INTEGER                  NOT NULL,
+    action               ENUM ('LIKED', 'PASSED') NOT NULL,
+    created_at           TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (duo_id, target_duo_id),
+    FOREIGN KEY (duo_id) REFERENCES duos (duo_id),
+    FOREIGN KEY (target_duo_id) REFERENCES duos (duo_id),
+    FOREIGN KEY (swiped_by_account_id) REFERENCES accounts (account_id)
+);
+
+ALTER TABLE matches
+    MODIFY account_id_low INTEGER NULL,
+    MODIFY account_id_high INTEGER NULL,
+    ADD COLUMN duo_id_low  INTEGER NULL,
+    ADD COLUMN duo_id_high INTEGER NULL,
+    ADD UNIQUE KEY uq_matches_duo_pair (duo_id_low, duo_id_high),
+    ADD FOREIGN KEY (duo_id_low) REFERENCES duos (duo_id),
+    ADD FOREIGN KEY (duo_id_high) REFERENCES duos (duo_id);
+
+CREATE TABLE match_participants
+(
+    match_id   INTEGER NOT NULL,
+    account_id INTEGER NOT NULL,
+    PRIMARY KEY (match_id, account_id),
+    INDEX idx_match_participants_account_id (account_id),
+    FOREIGN KEY (match_id) REFERENCES matches (match_id),
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE last_swipes
+(
+    account_id       INTEGER PRIMARY KEY,
+    swipe_id         INTEGER     NOT NULL,
+    account_id_swipe INTEGER     NOT NULL,
+    action           VARCHAR(10) NOT NULL,
+    quota_used       BOOLEAN     NOT NULL DEFAULT FALSE,
+    match_id         INTEGER     NULL,
+    swipe_date       DATE        NOT NULL,
+    swiped_at        TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE blocks
+(
+    account_id         INTEGER NOT NULL,
+    blocked_account_id INTEGER NOT NULL,
+    created_at         TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (account_id, blocked_account_id),
+    INDEX idx_blocks_blocked_account_id (blocked_account_id),
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id),
+    FOREIGN KEY (blocked_account_id) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE reports
+(
+    report_id           INTEGER AUTO_INCREMENT PRIMARY KEY,
+    reporter_account_id INTEGER     NOT NULL,
+    reported_account_id INTEGER     NOT NULL,
+    reason              VARCHAR(32) NOT NULL,
+    details             TEXT        NOT NULL,
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_reports_reported_account_id (reported_account_id),
+    FOREIGN KEY (reporter_account_id) REFERENCES accounts (account_id),
+    FOREIGN KEY (reported_account_id) REFERENCES accounts (account_id)
+);
+
+ALTER TABLE matches
+    ADD COLUMN title VARCHAR(100) NULL;
+
+ALTER TABLE match_participants
+    ADD COLUMN last_read_message_id INTEGER   NOT NULL DEFAULT 0,
+    ADD COLUMN joined_at            TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN left_at              TIMESTAMP NULL;
+
+INSERT IGNORE INTO match_participants (match_id, account_id, joined_at)
+SELECT match_id, account_id_low, created_at FROM matches WHERE account_id_low IS NOT NULL
+UNION ALL
+SELECT match_id, account_id_high, created_at FROM matches WHERE account_id_high IS NOT NULL;
+
+ALTER TABLE matches
+    ADD COLUMN expires_at TIMESTAMP NULL;
+
+CREATE TABLE events
+(
+    event_id      INTEGER AUTO_INCREMENT PRIMARY KEY,
+    title         VARCHAR(100) NOT NULL,
+    description   TEXT         NOT NULL,
+    starts_at     TIMESTAMP    NOT NULL,
+    ends_at       TIMESTAMP    NOT NULL,
+    room_match_id INTEGER      NULL,
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_events_ends_at (ends_at),
+    FOREIGN KEY (room_match_id) REFERENCES matches (match_id)
+);
+
+CREATE TABLE event_connections
+(
+    event_id          INTEGER NOT NULL,
+    account_id        INTEGER NOT NULL,
+    target_account_id INTEGER NOT NULL,
+    created_at        TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (event_id, account_id, target_account_id),
+    INDEX idx_event_connections_target_account_id (target_account_id),
+    FOREIGN KEY (event_id) REFERENCES events (event_id),
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id),
+    FOREIGN KEY (target_account_id) REFERENCES accounts (account_id)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE profile_photos
+    ADD COLUMN deleted_at TIMESTAMP NULL,
+    ADD INDEX idx_profile_photos_deleted_at (deleted_at);
@@ -0,0 +1 @@
+CREATE INDEX idx_login_histories_account_login_at ON login_histories (account_id, login_at);
@@ -0,0 +1,17 @@
+-- Super likes have their own daily quota next to the swipe quota
+ALTER TABLE daily_quotas
+    ADD COLUMN super_like_count INTEGER DEFAULT 0;
+
+CREATE TABLE super_likes
+(
+    super_like_id     INTEGER AUTO_INCREMENT PRIMARY KEY,
+    account_id        INTEGER NOT NULL,
+    target_account_id INTEGER NOT NULL,
+    swipe_id          INTEGER NOT NULL,
+    created_at        TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (account_id, target_account_id),
+    INDEX idx_super_likes_target (target_account_id, account_id),
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id),
+    FOREIGN KEY (target_account_id) REFERENCES accounts (account_id),
+    FOREIGN KEY (swipe_id) REFERENCES swipes (swipe_id) ON DELETE CASCADE
+);
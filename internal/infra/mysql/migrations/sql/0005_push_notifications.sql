@@ -0,0 +1,31 @@
+-- Device tokens of the mobile apps and the queue of the pushes sent to them
+CREATE TABLE device_tokens
+(
+    device_id  INTEGER AUTO_INCREMENT PRIMARY KEY,
+    account_id INTEGER      NOT NULL,
+    provider   ENUM ('fcm', 'apns') NOT NULL,
+    token      VARCHAR(512) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE (token),
+    INDEX idx_device_tokens_account (account_id),
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id)
+);
+
+CREATE TABLE push_notifications
+(
+    push_id         INTEGER AUTO_INCREMENT PRIMARY KEY,
+    account_id      INTEGER      NOT NULL,
+    kind            VARCHAR(32)  NOT NULL,
+    title           VARCHAR(255) NOT NULL,
+    body            VARCHAR(512) NOT NULL,
+    data            TEXT,
+    status          ENUM ('pending', 'sent', 'skipped', 'failed') NOT NULL DEFAULT 'pending',
+    attempts        INTEGER      NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_error      VARCHAR(512),
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    sent_at         TIMESTAMP NULL,
+    INDEX idx_push_notifications_due (status, next_attempt_at),
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id)
+);
@@ -0,0 +1,16 @@
+-- In-app notifications with their read state, written next to the pushes of the same events
+CREATE TABLE notifications
+(
+    notification_id INTEGER AUTO_INCREMENT PRIMARY KEY,
+    account_id      INTEGER      NOT NULL,
+    kind            VARCHAR(32)  NOT NULL,
+    title           VARCHAR(255) NOT NULL,
+    body            VARCHAR(512) NOT NULL,
+    data            TEXT,
+    read_at         TIMESTAMP NULL,
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_notifications_feed (account_id, notification_id),
+    INDEX idx_notifications_unread (account_id, read_at),
+    INDEX idx_notifications_created (created_at),
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id)
+);
@@ -0,0 +1,5 @@
+-- Accounts created or linked by a social login keep the provider and the subject of the identity there
+ALTER TABLE accounts
+    ADD COLUMN provider VARCHAR(16) NULL,
+    ADD COLUMN provider_id VARCHAR(255) NULL,
+    ADD UNIQUE KEY uq_accounts_provider (provider, provider_id);
@@ -0,0 +1,3 @@
+-- The region whose deployment serves the account, NULL until its first login in a multi-region setup
+ALTER TABLE accounts
+    ADD COLUMN home_region VARCHAR(32) NULL;
@@ -0,0 +1,14 @@
+-- Domain events written in the transaction of the change they tell about, the relayer publishes them to the bus
+CREATE TABLE outbox_events
+(
+    event_id        BIGINT AUTO_INCREMENT PRIMARY KEY,
+    kind            VARCHAR(64) NOT NULL,
+    aggregate_id    BIGINT      NOT NULL,
+    payload         TEXT        NOT NULL,
+    attempts        INTEGER     NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_error      VARCHAR(512),
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    published_at    TIMESTAMP NULL,
+    INDEX idx_outbox_events_due (published_at, next_attempt_at)
+);
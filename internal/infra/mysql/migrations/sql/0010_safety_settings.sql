@@ -0,0 +1,8 @@
+-- Safety settings of the account, late_night_mode restricts the first day of the conversations of its new matches
+CREATE TABLE safety_settings
+(
+    account_id      INTEGER PRIMARY KEY,
+    late_night_mode BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id)
+);
@@ -6,25 +6,98 @@ import (
 	"log"
 )
 
+// accountColumns are the columns of record.AccountRecord, the provider columns of a social login are read on their own
+const accountColumns = `account_id, username, password_hash, email, verified, created_at, updated_at`
+
 const (
 	SaveToAccountsRecord                             = `INSERT INTO accounts (username, password_hash, email, verified) VALUES(?, ?, ?, ?);`
-	FindByEmailAccountRecord                         = `SELECT EXISTS(SELECT 1 FROM accounts WHERE email = ?);`
-	FindByUsernameAccountRecord                      = `SELECT EXISTS(SELECT 1 FROM accounts WHERE username = ?)`
 	SaveToUserRecord                                 = `INSERT INTO users (account_id, date_of_birth, full_name, age, gender, address, bio) VALUES(?, ?, ?, ?, ?, ?, ?);`
 	FindByAccountIdUserRecord                        = `SELECT EXISTS(SELECT 1 FROM users WHERE account_id = ?);`
-	GetByUsernameAccountRecord                       = `SELECT * FROM accounts WHERE username = ?;`
-	GetByEmailAccountRecord                          = `SELECT * FROM accounts WHERE email = ?;`
-	GetByUsernameAndEmailAccountRecord               = `SELECT * FROM accounts WHERE username = ? AND email = ?;`
-	GetUserByAccountIdUserRecord                     = `SELECT * FROM users WHERE account_id = ?`
+	GetByUsernameAccountRecord                       = `SELECT ` + accountColumns + ` FROM accounts WHERE username = ?;`
+	GetByEmailAccountRecord                          = `SELECT ` + accountColumns + ` FROM accounts WHERE email = ?;`
+	GetByUsernameAndEmailAccountRecord               = `SELECT ` + accountColumns + ` FROM accounts WHERE username = ? AND email = ?;`
+	GetByProviderAccountRecord                       = `SELECT ` + accountColumns + ` FROM accounts WHERE provider = ? AND provider_id = ?;`
+	FindAccountConflictsRecord                       = `SELECT EXISTS(SELECT 1 FROM accounts WHERE email = ?), EXISTS(SELECT 1 FROM accounts WHERE username = ?);`
+	GetUserByAccountIdUserRecord                     = `SELECT user_id, account_id, full_name, date_of_birth, age, gender, address, bio, created_at, updated_at FROM users WHERE account_id = ?`
+	GetUsersByAccountIdsUserRecord                   = `SELECT user_id, account_id, full_name, date_of_birth, COALESCE(age, 0), COALESCE(gender, ''), COALESCE(address, ''), COALESCE(bio, ''), created_at, updated_at FROM users WHERE account_id IN `
 	SaveLoginHistoryRecord                           = `INSERT INTO login_histories (user_id, account_id) VALUES(?, ?);`
 	FindByUserIdAndAccountIdLoginHistoryRecord       = `SELECT * FROM login_histories WHERE user_id = ? AND account_id = ? AND logout_at IS NULL`
 	UpdateLoginHistoryRecord                         = `UPDATE login_histories SET logout_at = ?, duration_in_seconds = ? WHERE login_histories_id = ?`
+	FindOldestLoginHistoryRecord                     = `SELECT MIN(login_at) FROM login_histories WHERE login_at < ?`
+	SummarizeLoginHistoryRecord                      = `INSERT INTO login_history_summaries (account_id, summary_month, login_count, closed_sessions, total_duration_seconds, max_duration_seconds, first_login_at, last_login_at) SELECT account_id, DATE_FORMAT(MIN(login_at), '%Y-%m-01'), COUNT(*), COUNT(logout_at), COALESCE(SUM(duration_in_seconds), 0), COALESCE(MAX(duration_in_seconds), 0), MIN(login_at), MAX(login_at) FROM login_histories WHERE login_at >= ? AND login_at < ? GROUP BY account_id ON DUPLICATE KEY UPDATE login_count = login_count + VALUES(login_count), closed_sessions = closed_sessions + VALUES(closed_sessions), total_duration_seconds = total_duration_seconds + VALUES(total_duration_seconds), max_duration_seconds = GREATEST(max_duration_seconds, VALUES(max_duration_seconds)), first_login_at = LEAST(first_login_at, VALUES(first_login_at)), last_login_at = GREATEST(last_login_at, VALUES(last_login_at))`
+	DeleteLoginHistoryRecord                         = `DELETE FROM login_histories WHERE login_at >= ? AND login_at < ?`
 	InsertIntoDailyQuotaRecord                       = `INSERT INTO daily_quotas (account_id, swipe_count, total_quota) VALUES (?, ?, ?)`
 	FindAllUserAccountsListRecord                    = `SELECT a.account_id, u.user_id, a.verified FROM users u INNER JOIN accounts a ON u.account_id = a.account_id`
-	FindAllUserAccountsViewInPremiumFirstListRecord  = `SELECT a.account_id, u.user_id, a.verified, a.username, u.full_name, u.gender, u.bio, u.age, u.address FROM users u INNER JOIN accounts a ON u.account_id = a.account_id WHERE a.account_id != ?`
-	FindAllUserAccountsViewInPremiumSecondListRecord = `SELECT a.account_id, u.user_id, a.verified, a.username, u.full_name, u.gender, u.bio, u.age, u.address FROM users u INNER JOIN accounts a ON u.account_id = a.account_id WHERE a.account_id != ? AND a.account_id NOT IN ( SELECT s.account_id_swipe from swipes s WHERE s.account_id = ? ) ORDER BY RAND();`
-	FindAllUserAccountsView10InFirstHitListRecord    = `SELECT a.account_id, u.user_id, a.verified, a.username, u.full_name, u.gender, u.bio, u.age, u.address FROM users u INNER JOIN accounts a ON u.account_id = a.account_id WHERE a.verified = FALSE AND a.account_id != ? AND a.account_id NOT IN (SELECT DISTINCT sh2.account_id_identifier FROM selection_histories sh2 WHERE sh2.selection_date = CURDATE()) ORDER BY RAND() LIMIT 10;`
-	FindAllUserAccountsView10InSecondHitListRecord   = `SELECT a.account_id, u.user_id, a.verified, a.username, u.full_name, u.gender, u.bio, u.age, u.address FROM users u INNER JOIN accounts a ON u.account_id = a.account_id INNER JOIN selection_histories sh ON a.account_id = sh.account_id AND u.account_id = sh.account_id AND sh.selection_date = CURDATE() WHERE a.verified = FALSE AND sh.account_id_identifier = ? AND a.account_id != ? AND a.account_id NOT IN (SELECT s.account_id_swipe from swipes s WHERE s.account_id = ?) ORDER BY RAND() LIMIT 10;`
+	FindAllUserAccountsViewInPremiumFirstListRecord  = `SELECT a.account_id, u.user_id, a.verified, a.username, u.full_name, u.gender, u.bio, u.age, u.address, ` + candidateDistance + ` FROM users u INNER JOIN accounts a ON u.account_id = a.account_id` + matchScoreJoin + viewerLocationJoin + ` WHERE a.account_id != ? AND (u.phone_hash IS NULL OR u.phone_hash NOT IN (SELECT ce.contact_hash FROM contact_exclusions ce WHERE ce.account_id = ?)) AND a.account_id NOT IN (SELECT ad.account_id FROM account_dormancy ad WHERE ad.state IN ('hidden', 'deleted', 'purged'))` + hiddenAccountsFilter + blockedAccountsFilter + locationRadiusFilter + ` ORDER BY ` + superLikeOrder + `, ` + matchScoreOrder
+	FindAllUserAccountsViewInPremiumSecondListRecord = `SELECT a.account_id, u.user_id, a.verified, a.username, u.full_name, u.gender, u.bio, u.age, u.address, ` + candidateDistance + ` FROM users u INNER JOIN accounts a ON u.account_id = a.account_id` + matchScoreJoin + viewerLocationJoin + ` WHERE a.account_id != ? AND a.account_id NOT IN ( SELECT s.account_id_swipe from swipes s WHERE s.account_id = ? ) AND (u.phone_hash IS NULL OR u.phone_hash NOT IN (SELECT ce.contact_hash FROM contact_exclusions ce WHERE ce.account_id = ?)) AND a.account_id NOT IN (SELECT ad.account_id FROM account_dormancy ad WHERE ad.state IN ('hidden', 'deleted', 'purged'))` + hiddenAccountsFilter + blockedAccountsFilter + locationRadiusFilter + ` ORDER BY ` + superLikeOrder + `, ` + matchScoreOrder + `, RAND();`
+	FindAllUserAccountsView10InFirstHitListRecord    = `SELECT a.account_id, u.user_id, a.verified, a.username, u.full_name, u.gender, u.bio, u.age, u.address, ` + candidateDistance + ` FROM users u INNER JOIN accounts a ON u.account_id = a.account_id` + matchScoreJoin + viewerLocationJoin + ` WHERE a.verified = FALSE AND a.account_id != ? AND a.account_id NOT IN (SELECT DISTINCT sh2.account_id_identifier FROM selection_histories sh2 WHERE sh2.selection_date = CURDATE()) AND (u.phone_hash IS NULL OR u.phone_hash NOT IN (SELECT ce.contact_hash FROM contact_exclusions ce WHERE ce.account_id = ?)) AND a.account_id NOT IN (SELECT ad.account_id FROM account_dormancy ad WHERE ad.state IN ('hidden', 'deleted', 'purged'))` + hiddenAccountsFilter + blockedAccountsFilter + locationRadiusFilter + ` ORDER BY ` + superLikeOrder + `, ` + matchScoreOrder + `, RAND() LIMIT 10;`
+	FindAllUserAccountsView10InSecondHitListRecord   = `SELECT a.account_id, u.user_id, a.verified, a.username, u.full_name, u.gender, u.bio, u.age, u.address, ` + candidateDistance + ` FROM users u INNER JOIN accounts a ON u.account_id = a.account_id INNER JOIN selection_histories sh ON a.account_id = sh.account_id AND u.account_id = sh.account_id AND sh.selection_date = CURDATE()` + matchScoreJoin + viewerLocationJoin + ` WHERE a.verified = FALSE AND sh.account_id_identifier = ? AND a.account_id != ? AND a.account_id NOT IN (SELECT s.account_id_swipe from swipes s WHERE s.account_id = ?) AND (u.phone_hash IS NULL OR u.phone_hash NOT IN (SELECT ce.contact_hash FROM contact_exclusions ce WHERE ce.account_id = ?)) AND a.account_id NOT IN (SELECT ad.account_id FROM account_dormancy ad WHERE ad.state IN ('hidden', 'deleted', 'purged'))` + hiddenAccountsFilter + blockedAccountsFilter + locationRadiusFilter + ` ORDER BY ` + superLikeOrder + `, ` + matchScoreOrder + `, RAND() LIMIT 10;`
+)
+
+// Discovery shows candidates with a fresh model score first, best score first, the others keep their random order.
+// Scores older than 7 days are ignored and later deleted by the match feature job.
+const (
+	matchScoreJoin  = ` LEFT JOIN match_scores ms ON ms.account_id = ? AND ms.candidate_account_id = a.account_id AND ms.scored_at >= NOW() - INTERVAL 7 DAY`
+	matchScoreOrder = `ms.score IS NULL, ms.score DESC`
+)
+
+// Discovery shows the accounts that super liked the viewer before any other, the viewer comes from the viewer location join
+const (
+	candidateSuperLiked = `EXISTS(SELECT 1 FROM super_likes sl WHERE sl.account_id = a.account_id AND sl.target_account_id = viewer.account_id)`
+	superLikeOrder      = candidateSuperLiked + ` DESC`
+)
+
+// Discovery leaves out the accounts the viewer hid, the placeholder is the viewer account
+const hiddenAccountsFilter = ` AND a.account_id NOT IN (SELECT ha.hidden_account_id FROM hidden_accounts ha WHERE ha.account_id = ?)`
+
+// Discovery leaves out the accounts the viewer blocked and the accounts that blocked the viewer, both placeholders are the viewer account
+const blockedAccountsFilter = ` AND a.account_id NOT IN (SELECT b.blocked_account_id FROM blocks b WHERE b.account_id = ?) AND a.account_id NOT IN (SELECT b.account_id FROM blocks b WHERE b.blocked_account_id = ?)`
+
+// Discovery only shows candidates within the radius around the viewer location, the first placeholder is the viewer account
+// and the second the radius in km. A viewer without a location is not filtered, a candidate without one is left out
+const (
+	viewerLocationJoin   = ` LEFT JOIN users viewer ON viewer.account_id = ?`
+	candidateDistance    = `ST_Distance_Sphere(POINT(u.longitude, u.latitude), POINT(viewer.longitude, viewer.latitude)) / 1000 AS distance_km`
+	locationRadiusFilter = ` AND (viewer.latitude IS NULL OR ST_Distance_Sphere(POINT(u.longitude, u.latitude), POINT(viewer.longitude, viewer.latitude)) <= ? * 1000)`
+)
+
+// Candidates of the recommendation engine, the same exclusions as discovery plus the swiped accounts. The viewer placeholders
+// come first as in discovery, then the radius and the age range. The score is computed around it by the repository
+const (
+	candidateAge = `COALESCE(TIMESTAMPDIFF(YEAR, u.date_of_birth, CURDATE()), u.age, 0)`
+	// candidateActivity decays with the hours since the last login, 1 right after it and 0.5 a week later
+	candidateActivity = `COALESCE(1 / (1 + TIMESTAMPDIFF(HOUR, (SELECT MAX(lh.login_at) FROM login_histories lh WHERE lh.account_id = a.account_id), NOW()) / 168), 0)`
+	// candidateCompleteness weighs the profile like the profile strength does, 1 for a complete profile
+	candidateCompleteness = `(30 * (EXISTS(SELECT 1 FROM profile_photos pp WHERE pp.account_id = a.account_id AND pp.deleted_at IS NULL) OR EXISTS(SELECT 1 FROM profile_imports pi WHERE pi.account_id = a.account_id AND pi.content_type = 'photo'))
+		+ 20 * (TRIM(COALESCE(u.bio, '')) != '') + 10 * (CHAR_LENGTH(TRIM(COALESCE(u.bio, ''))) >= 50) + 15 * (u.date_of_birth IS NOT NULL)
+		+ 10 * EXISTS(SELECT 1 FROM profile_imports pi WHERE pi.account_id = a.account_id AND pi.content_type = 'top_artist')
+		+ 10 * (TRIM(COALESCE(u.full_name, '')) != '') + 10 * (TRIM(COALESCE(u.address, '')) != '') + 5 * (TRIM(COALESCE(u.gender, '')) != '')) / 100`
+	candidatesFrom        = ` FROM users u INNER JOIN accounts a ON u.account_id = a.account_id` + viewerLocationJoin + ` WHERE a.account_id != ? AND a.account_id NOT IN (SELECT s.account_id_swipe FROM swipes s WHERE s.account_id = ?) AND (u.phone_hash IS NULL OR u.phone_hash NOT IN (SELECT ce.contact_hash FROM contact_exclusions ce WHERE ce.account_id = ?)) AND a.account_id NOT IN (SELECT ad.account_id FROM account_dormancy ad WHERE ad.state IN ('hidden', 'deleted', 'purged'))` + hiddenAccountsFilter + blockedAccountsFilter + locationRadiusFilter + ` AND ` + candidateAge + ` BETWEEN ? AND ?`
+	FindCandidatesRecord  = `SELECT a.account_id, u.user_id, a.verified, a.username, u.full_name, COALESCE(u.gender, '') AS gender, COALESCE(u.bio, '') AS bio, ` + candidateAge + ` AS age, COALESCE(u.address, '') AS address, ` + candidateDistance + `, ` + candidateActivity + ` AS activity_score, ` + candidateCompleteness + ` AS completeness_score, ` + candidateSuperLiked + ` AS super_liked` + candidatesFrom
+	CountCandidatesRecord = `SELECT COUNT(*)` + candidatesFrom
+)
+
+// Likes received are the accounts that liked the viewer and the viewer did not swipe yet, with the same exclusions as
+// discovery. Every placeholder is the viewer account, an account liking again on another day counts once
+const (
+	likesReceivedFrom        = ` FROM swipes s INNER JOIN accounts a ON s.account_id = a.account_id WHERE s.account_id_swipe = ? AND s.action = 'LIKED' AND a.account_id NOT IN (SELECT sw.account_id_swipe FROM swipes sw WHERE sw.account_id = ?) AND a.account_id NOT IN (SELECT ad.account_id FROM account_dormancy ad WHERE ad.state IN ('hidden', 'deleted', 'purged'))` + hiddenAccountsFilter + blockedAccountsFilter
+	FindLikesReceivedRecord  = `SELECT a.account_id, TIMESTAMP(MAX(s.swipe_date)) AS liked_on, EXISTS(SELECT 1 FROM super_likes sl WHERE sl.account_id = a.account_id AND sl.target_account_id = s.account_id_swipe) AS super_liked` + likesReceivedFrom + ` GROUP BY a.account_id, s.account_id_swipe ORDER BY super_liked DESC, MAX(s.swipe_id) DESC LIMIT ? OFFSET ?`
+	CountLikesReceivedRecord = `SELECT COUNT(DISTINCT a.account_id)` + likesReceivedFrom
+)
+
+// Account timeline for admin investigations, every source is normalised to (event_type, occurred_at, detail)
+const (
+	AccountTimelineEventsRecord = `SELECT 'login' AS event_type, lh.login_at AS occurred_at, '' AS detail FROM login_histories lh WHERE lh.account_id = ?
+		UNION ALL SELECT 'logins_monthly', TIMESTAMP(ls.summary_month), CONCAT(ls.login_count, ' logins, ', ROUND(ls.total_duration_seconds), ' seconds active, last at ', ls.last_login_at) FROM login_history_summaries ls WHERE ls.account_id = ?
+		UNION ALL SELECT 'logout', lh.logout_at, CONCAT(COALESCE(ROUND(lh.duration_in_seconds), 0), ' seconds active') FROM login_histories lh WHERE lh.account_id = ? AND lh.logout_at IS NOT NULL
+		UNION ALL SELECT 'swipes_sent', TIMESTAMP(s.swipe_date), CONCAT(SUM(s.action = 'LIKED'), ' liked, ', SUM(s.action = 'PASSED'), ' passed') FROM swipes s WHERE s.account_id = ? GROUP BY s.swipe_date
+		UNION ALL SELECT 'swipes_received', TIMESTAMP(s.swipe_date), CONCAT(SUM(s.action = 'LIKED'), ' liked, ', SUM(s.action = 'PASSED'), ' passed') FROM swipes s WHERE s.account_id_swipe = ? GROUP BY s.swipe_date
+		UNION ALL SELECT 'purchase', ap.purchase_date, CONCAT(COALESCE(p.package_name, ''), ' until ', COALESCE(ap.expiry_date, '-')) FROM account_premiums ap LEFT JOIN packages p ON ap.package_id = p.package_id WHERE ap.account_id = ?
+		UNION ALL SELECT CONCAT('admin_', al.action), al.created_at, CONCAT(al.actor, ': ', al.justification) FROM admin_audit_logs al WHERE al.target_account_id = ?
+		UNION ALL SELECT CONCAT('dormancy_', de.to_state), de.occurred_at, CONCAT('from ', IF(de.from_state = '', 'active', de.from_state), ', last active ', de.last_active_at) FROM account_dormancy_events de WHERE de.account_id = ?`
+	FindAccountTimelineRecord   = `SELECT event_type, occurred_at, detail FROM (` + AccountTimelineEventsRecord + `) timeline ORDER BY occurred_at DESC LIMIT ? OFFSET ?`
+	CountAccountTimelineRecord  = `SELECT COUNT(*) FROM (` + AccountTimelineEventsRecord + `) timeline`
+	StreamAccountTimelineRecord = `SELECT event_type, occurred_at, detail FROM (` + AccountTimelineEventsRecord + `) timeline ORDER BY occurred_at DESC`
 )
 
 func ExecuteQuery(ctx context.Context, db *sql.DB, query string, args ...interface{}) (sql.Result, error) {
@@ -0,0 +1,29 @@
+package record
+
+import "time"
+
+// AccountDormancyRecord is the current dormancy state of an inactive account, active accounts have no row
+type AccountDormancyRecord struct {
+	AccountID    int64      `db:"account_id"`
+	State        string     `db:"state"`
+	LastActiveAt time.Time  `db:"last_active_at"`
+	UpdatedAt    *time.Time `db:"updated_at"`
+}
+
+func (AccountDormancyRecord) TableName() string {
+	return "account_dormancy"
+}
+
+// AccountDormancyEventRecord is one state transition, kept after purge as the retention evidence
+type AccountDormancyEventRecord struct {
+	EventID      int64     `db:"event_id"`
+	AccountID    int64     `db:"account_id"`
+	FromState    string    `db:"from_state"`
+	ToState      string    `db:"to_state"`
+	LastActiveAt time.Time `db:"last_active_at"`
+	OccurredAt   time.Time `db:"occurred_at"`
+}
+
+func (AccountDormancyEventRecord) TableName() string {
+	return "account_dormancy_events"
+}
@@ -0,0 +1,7 @@
+package record
+
+// AccountExportSectionRecord is one table of the personal data export, the rows by column name
+type AccountExportSectionRecord struct {
+	Name string
+	Rows []map[string]interface{}
+}
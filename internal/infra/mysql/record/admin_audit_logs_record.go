@@ -0,0 +1,18 @@
+package record
+
+import "time"
+
+// AdminAuditLogRecord represents an action taken by an admin on an account, changes are kept as JSON
+type AdminAuditLogRecord struct {
+	AuditID         int64     `db:"audit_id"`
+	Actor           string    `db:"actor"`
+	Action          string    `db:"action"`
+	TargetAccountID int64     `db:"target_account_id"`
+	Justification   string    `db:"justification"`
+	Changes         string    `db:"changes"`
+	CreatedAt       time.Time `db:"created_at"`
+}
+
+func (AdminAuditLogRecord) TableName() string {
+	return "admin_audit_logs"
+}
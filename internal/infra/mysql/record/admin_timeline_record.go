@@ -0,0 +1,10 @@
+package record
+
+import "time"
+
+// TimelineEventRecord represents one entry of an account timeline assembled from several tables
+type TimelineEventRecord struct {
+	EventType  string    `db:"event_type"`
+	OccurredAt time.Time `db:"occurred_at"`
+	Detail     string    `db:"detail"`
+}
@@ -0,0 +1,29 @@
+package record
+
+import "time"
+
+// BlockRecord hides both accounts from each other in discovery, matches and chat, unlike a hidden account
+type BlockRecord struct {
+	AccountID        int64     `db:"account_id"`
+	BlockedAccountID int64     `db:"blocked_account_id"`
+	BlockedUsername  string    `db:"username"`
+	CreatedAt        time.Time `db:"created_at"`
+}
+
+func (BlockRecord) TableName() string {
+	return "blocks"
+}
+
+// ReportRecord is kept for trust and safety even after either account is purged
+type ReportRecord struct {
+	ReportID          int64     `db:"report_id"`
+	ReporterAccountID int64     `db:"reporter_account_id"`
+	ReportedAccountID int64     `db:"reported_account_id"`
+	Reason            string    `db:"reason"`
+	Details           string    `db:"details"`
+	CreatedAt         time.Time `db:"created_at"`
+}
+
+func (ReportRecord) TableName() string {
+	return "reports"
+}
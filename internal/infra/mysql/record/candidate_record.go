@@ -0,0 +1,30 @@
+package record
+
+// CandidateRecord is a discovery candidate with the parts of its recommendation score, it is not a table
+type CandidateRecord struct {
+	AccountID         int64    `db:"account_id"`
+	UserID            int64    `db:"user_id"`
+	Verified          bool     `db:"verified"`
+	Username          string   `db:"username"`
+	FullName          *string  `db:"full_name"`
+	Gender            string   `db:"gender"`
+	Bio               string   `db:"bio"`
+	Age               int      `db:"age"`
+	Address           string   `db:"address"`
+	DistanceKm        *float64 `db:"distance_km"`
+	ActivityScore     float64  `db:"activity_score"`
+	CompletenessScore float64  `db:"completeness_score"`
+	SuperLiked        bool     `db:"super_liked"`
+	Score             float64  `db:"score"`
+}
+
+// CandidateQuery filters and weighs the candidates of AccountID, an empty Genders takes every gender
+type CandidateQuery struct {
+	AccountID          int64
+	MinAge             int
+	MaxAge             int
+	Genders            []string
+	MaxDistanceKm      int
+	ActivityWeight     float64
+	CompletenessWeight float64
+}
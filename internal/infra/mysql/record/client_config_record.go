@@ -0,0 +1,20 @@
+package record
+
+import "time"
+
+// ClientConfigBundleRecord is one immutable version of a client-side configuration bundle, only its rollout can change
+type ClientConfigBundleRecord struct {
+	BundleID       int64     `db:"bundle_id"`
+	Name           string    `db:"name"`
+	Version        int64     `db:"version"`
+	MinAppVersion  string    `db:"min_app_version"`
+	MaxAppVersion  *string   `db:"max_app_version"`
+	RolloutPercent int       `db:"rollout_percent"`
+	Payload        []byte    `db:"payload"`
+	CreatedAt      time.Time `db:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
+
+func (ClientConfigBundleRecord) TableName() string {
+	return "client_config_bundles"
+}
@@ -0,0 +1,15 @@
+package record
+
+import "time"
+
+// ContactExclusionRecord represents a hashed phone contact an account doesn't want to see in discovery
+type ContactExclusionRecord struct {
+	ExclusionID int64     `db:"exclusion_id"`
+	AccountID   int64     `db:"account_id"`
+	ContactHash string    `db:"contact_hash"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+func (ContactExclusionRecord) TableName() string {
+	return "contact_exclusions"
+}
@@ -4,11 +4,12 @@ import "time"
 
 // DailyQuotaRecord represents the daily swipe quota for a user
 type DailyQuotaRecord struct {
-	QuotaID    int64     `db:"quota_id"`
-	AccountID  int64     `db:"account_id"`
-	Date       time.Time `db:"date"`
-	TotalQuota int64     `db:"total_quota"`
-	SwipeCount int       `db:"swipe_count"`
+	QuotaID        int64     `db:"quota_id"`
+	AccountID      int64     `db:"account_id"`
+	Date           time.Time `db:"date"`
+	TotalQuota     int64     `db:"total_quota"`
+	SwipeCount     int       `db:"swipe_count"`
+	SuperLikeCount int       `db:"super_like_count"`
 }
 
 func (DailyQuotaRecord) TableName() string {
@@ -0,0 +1,54 @@
+package record
+
+import "time"
+
+// DuoRecord links two friends who appear together in duo discovery, the pair is stored with the lower account id first.
+// An account is in at most one pending or active duo, an unlinked duo is kept for the matches it made
+type DuoRecord struct {
+	DuoID              int64      `db:"duo_id"`
+	AccountIDLow       int64      `db:"account_id_low"`
+	AccountIDHigh      int64      `db:"account_id_high"`
+	InvitedByAccountID int64      `db:"invited_by_account_id"`
+	State              string     `db:"state"`
+	CreatedAt          time.Time  `db:"created_at"`
+	LinkedAt           *time.Time `db:"linked_at"`
+	UnlinkedAt         *time.Time `db:"unlinked_at"`
+}
+
+func (DuoRecord) TableName() string {
+	return "duos"
+}
+
+// DuoSwipeRecord is the latest swipe of a duo on another duo, made by either member
+type DuoSwipeRecord struct {
+	DuoID             int64     `db:"duo_id"`
+	TargetDuoID       int64     `db:"target_duo_id"`
+	SwipedByAccountID int64     `db:"swiped_by_account_id"`
+	Action            string    `db:"action"`
+	CreatedAt         time.Time `db:"created_at"`
+}
+
+func (DuoSwipeRecord) TableName() string {
+	return "duo_swipes"
+}
+
+// DuoMemberRecord is the profile of one member of a duo candidate
+type DuoMemberRecord struct {
+	DuoID     int64   `db:"duo_id"`
+	AccountID int64   `db:"account_id"`
+	FullName  *string `db:"full_name"`
+	Age       *int    `db:"age"`
+	Gender    *string `db:"gender"`
+	Bio       *string `db:"bio"`
+}
+
+// DuoMatchMemberRecord is the profile of one of the other participants of a duo match
+type DuoMatchMemberRecord struct {
+	MatchID   int64     `db:"match_id"`
+	AccountID int64     `db:"account_id"`
+	FullName  *string   `db:"full_name"`
+	Age       *int      `db:"age"`
+	Gender    *string   `db:"gender"`
+	Bio       *string   `db:"bio"`
+	CreatedAt time.Time `db:"created_at"`
+}
@@ -0,0 +1,30 @@
+package record
+
+import "time"
+
+// EventRecord is a virtual event, its room is an ephemeral group match that is purged once the event ended
+type EventRecord struct {
+	EventID     int64     `db:"event_id"`
+	Title       string    `db:"title"`
+	Description string    `db:"description"`
+	StartsAt    time.Time `db:"starts_at"`
+	EndsAt      time.Time `db:"ends_at"`
+	RoomMatchID *int64    `db:"room_match_id"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+func (EventRecord) TableName() string {
+	return "events"
+}
+
+// EventConnectionRecord is one guest choosing another during the event, two that chose each other are matched
+type EventConnectionRecord struct {
+	EventID         int64     `db:"event_id"`
+	AccountID       int64     `db:"account_id"`
+	TargetAccountID int64     `db:"target_account_id"`
+	CreatedAt       time.Time `db:"created_at"`
+}
+
+func (EventConnectionRecord) TableName() string {
+	return "event_connections"
+}
@@ -0,0 +1,16 @@
+package record
+
+import "time"
+
+// HiddenAccountRecord is an account the owner never wants recommended in discovery, without blocking it.
+// HiddenUsername is joined from accounts when listing
+type HiddenAccountRecord struct {
+	AccountID       int64     `db:"account_id"`
+	HiddenAccountID int64     `db:"hidden_account_id"`
+	HiddenUsername  string    `db:"username"`
+	CreatedAt       time.Time `db:"created_at"`
+}
+
+func (HiddenAccountRecord) TableName() string {
+	return "hidden_accounts"
+}
@@ -0,0 +1,19 @@
+package record
+
+import "time"
+
+// LoginHistorySummaryRecord aggregates one month of login_histories rows for an account once they pass retention
+type LoginHistorySummaryRecord struct {
+	AccountID            int64     `db:"account_id"`
+	SummaryMonth         time.Time `db:"summary_month"`
+	LoginCount           int64     `db:"login_count"`
+	ClosedSessions       int64     `db:"closed_sessions"`
+	TotalDurationSeconds float64   `db:"total_duration_seconds"`
+	MaxDurationSeconds   float64   `db:"max_duration_seconds"`
+	FirstLoginAt         time.Time `db:"first_login_at"`
+	LastLoginAt          time.Time `db:"last_login_at"`
+}
+
+func (LoginHistorySummaryRecord) TableName() string {
+	return "login_history_summaries"
+}
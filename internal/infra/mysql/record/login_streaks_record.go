@@ -0,0 +1,18 @@
+package record
+
+import "time"
+
+// LoginStreakRecord represents the consecutive daily login streak of an account, days are in the account timezone
+type LoginStreakRecord struct {
+	AccountID      int64      `db:"account_id"`
+	Timezone       string     `db:"timezone"`
+	CurrentStreak  int        `db:"current_streak"`
+	LongestStreak  int        `db:"longest_streak"`
+	LastLoginDay   time.Time  `db:"last_login_day"`
+	LastClaimedDay *time.Time `db:"last_claimed_day"`
+	UpdatedAt      time.Time  `db:"updated_at"`
+}
+
+func (LoginStreakRecord) TableName() string {
+	return "login_streaks"
+}
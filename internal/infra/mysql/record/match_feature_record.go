@@ -0,0 +1,46 @@
+package record
+
+import "time"
+
+// AccountFeatureRecord is the latest activity snapshot of one account, read by the external match-prediction model
+type AccountFeatureRecord struct {
+	AccountID        int64     `db:"account_id"`
+	Logins7d         int64     `db:"logins_7d"`
+	Logins30d        int64     `db:"logins_30d"`
+	ActiveDays30d    int64     `db:"active_days_30d"`
+	LikesSent30d     int64     `db:"likes_sent_30d"`
+	PassesSent30d    int64     `db:"passes_sent_30d"`
+	LikesReceived30d int64     `db:"likes_received_30d"`
+	ResponseRate     *float64  `db:"response_rate"`
+	Interests        int64     `db:"interests"`
+	ComputedAt       time.Time `db:"computed_at"`
+}
+
+func (AccountFeatureRecord) TableName() string {
+	return "account_features"
+}
+
+// PairFeatureRecord is kept only for pairs that share at least one interest
+type PairFeatureRecord struct {
+	AccountID          int64     `db:"account_id"`
+	CandidateAccountID int64     `db:"candidate_account_id"`
+	SharedInterests    int64     `db:"shared_interests"`
+	ComputedAt         time.Time `db:"computed_at"`
+}
+
+func (PairFeatureRecord) TableName() string {
+	return "pair_features"
+}
+
+// MatchScoreRecord is the predicted chance that the account matches the candidate, written back by the model
+type MatchScoreRecord struct {
+	AccountID          int64     `db:"account_id"`
+	CandidateAccountID int64     `db:"candidate_account_id"`
+	Score              float64   `db:"score"`
+	ModelVersion       string    `db:"model_version"`
+	ScoredAt           time.Time `db:"scored_at"`
+}
+
+func (MatchScoreRecord) TableName() string {
+	return "match_scores"
+}
@@ -0,0 +1,48 @@
+package record
+
+import "time"
+
+// MatchRecord is created once two accounts liked each other, the pair is stored with the lower account id first.
+// A duo match stores the two duos instead and a group chat only has a title, for both the account ids are 0.
+// The accounts of every match conversation are in match_participants, an ephemeral group ends at ExpiresAt
+type MatchRecord struct {
+	MatchID       int64      `db:"match_id"`
+	AccountIDLow  int64      `db:"account_id_low"`
+	AccountIDHigh int64      `db:"account_id_high"`
+	DuoIDLow      *int64     `db:"duo_id_low"`
+	DuoIDHigh     *int64     `db:"duo_id_high"`
+	Title         *string    `db:"title"`
+	ExpiresAt     *time.Time `db:"expires_at"`
+	CreatedAt     time.Time  `db:"created_at"`
+}
+
+func (MatchRecord) TableName() string {
+	return "matches"
+}
+
+// MatchViewRecord is a match seen from one account, with the profile of the other account
+type MatchViewRecord struct {
+	MatchID          int64     `db:"match_id"`
+	MatchedAccountID int64     `db:"matched_account_id"`
+	FullName         *string   `db:"full_name"`
+	Age              *int      `db:"age"`
+	Gender           *string   `db:"gender"`
+	Bio              *string   `db:"bio"`
+	CreatedAt        time.Time `db:"created_at"`
+}
+
+// MatchParticipantRecord is one account of a match conversation, a participant that left keeps its row with LeftAt set.
+// LastReadMessageID is the newest message the participant has read
+type MatchParticipantRecord struct {
+	MatchID           int64      `db:"match_id"`
+	AccountID         int64      `db:"account_id"`
+	Username          string     `db:"username"`
+	FullName          *string    `db:"full_name"`
+	LastReadMessageID int64      `db:"last_read_message_id"`
+	JoinedAt          time.Time  `db:"joined_at"`
+	LeftAt            *time.Time `db:"left_at"`
+}
+
+func (MatchParticipantRecord) TableName() string {
+	return "match_participants"
+}
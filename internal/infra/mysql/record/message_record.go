@@ -0,0 +1,18 @@
+package record
+
+import "time"
+
+// MessageRecord is one chat message inside a match, the match is the conversation
+type MessageRecord struct {
+	MessageID       int64     `db:"message_id"`
+	MatchID         int64     `db:"match_id"`
+	SenderAccountID int64     `db:"sender_account_id"`
+	SenderUsername  string    `db:"sender_username"`
+	SenderFullName  *string   `db:"sender_full_name"`
+	Body            string    `db:"body"`
+	CreatedAt       time.Time `db:"created_at"`
+}
+
+func (MessageRecord) TableName() string {
+	return "messages"
+}
@@ -0,0 +1,17 @@
+package record
+
+import "time"
+
+// NetworkRuleRecord denies or allows a CIDR range for a route group, cidr is stored in its canonical form
+type NetworkRuleRecord struct {
+	RuleID     int64     `db:"rule_id"`
+	List       string    `db:"list"`
+	RouteGroup string    `db:"route_group"`
+	CIDR       string    `db:"cidr"`
+	Note       string    `db:"note"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+func (NetworkRuleRecord) TableName() string {
+	return "network_rules"
+}
@@ -0,0 +1,17 @@
+package record
+
+import "time"
+
+// NoteRecord represents a private note written by an account about another profile
+type NoteRecord struct {
+	NoteID          int64     `db:"note_id"`
+	OwnerAccountID  int64     `db:"owner_account_id"`
+	TargetAccountID int64     `db:"target_account_id"`
+	Content         string    `db:"content"`
+	CreatedAt       time.Time `db:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at"`
+}
+
+func (NoteRecord) TableName() string {
+	return "notes"
+}
@@ -0,0 +1,22 @@
+package record
+
+import (
+	"database/sql"
+	"time"
+)
+
+// NotificationRecord is an entry of the in-app feed of an account, Data is a JSON object like the one of its push
+type NotificationRecord struct {
+	NotificationID int64          `db:"notification_id"`
+	AccountID      int64          `db:"account_id"`
+	Kind           string         `db:"kind"`
+	Title          string         `db:"title"`
+	Body           string         `db:"body"`
+	Data           sql.NullString `db:"data"`
+	ReadAt         sql.NullTime   `db:"read_at"`
+	CreatedAt      time.Time      `db:"created_at"`
+}
+
+func (NotificationRecord) TableName() string {
+	return "notifications"
+}
@@ -0,0 +1,24 @@
+package record
+
+import (
+	"database/sql"
+	"time"
+)
+
+// OutboxEventRecord is a domain event waiting for the relayer, Payload is a JSON object. PublishedAt is set once the
+// bus took it
+type OutboxEventRecord struct {
+	EventID       int64          `db:"event_id"`
+	Kind          string         `db:"kind"`
+	AggregateID   int64          `db:"aggregate_id"`
+	Payload       string         `db:"payload"`
+	Attempts      int            `db:"attempts"`
+	NextAttemptAt time.Time      `db:"next_attempt_at"`
+	LastError     sql.NullString `db:"last_error"`
+	CreatedAt     time.Time      `db:"created_at"`
+	PublishedAt   sql.NullTime   `db:"published_at"`
+}
+
+func (OutboxEventRecord) TableName() string {
+	return "outbox_events"
+}
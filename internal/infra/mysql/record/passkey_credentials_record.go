@@ -0,0 +1,18 @@
+package record
+
+import "time"
+
+// PasskeyCredentialRecord is a WebAuthn credential registered by an account for passwordless login
+type PasskeyCredentialRecord struct {
+	CredentialID []byte     `db:"credential_id"`
+	AccountID    int64      `db:"account_id"`
+	Name         string     `db:"name"`
+	PublicKey    []byte     `db:"public_key"`
+	SignCount    uint32     `db:"sign_count"`
+	CreatedAt    time.Time  `db:"created_at"`
+	LastUsedAt   *time.Time `db:"last_used_at"`
+}
+
+func (PasskeyCredentialRecord) TableName() string {
+	return "passkey_credentials"
+}
@@ -0,0 +1,25 @@
+package record
+
+import "time"
+
+// ProfileChangeRequestRecord asks a moderator to change a field that is locked once the account is verified,
+// an account holds at most one pending request per field
+type ProfileChangeRequestRecord struct {
+	RequestID      int64      `db:"request_id"`
+	AccountID      int64      `db:"account_id"`
+	Field          string     `db:"field"`
+	CurrentValue   string     `db:"current_value"`
+	RequestedValue string     `db:"requested_value"`
+	Evidence       string     `db:"evidence"`
+	Reason         string     `db:"reason"`
+	Status         string     `db:"status"`
+	Reviewer       *string    `db:"reviewer"`
+	ReviewNote     *string    `db:"review_note"`
+	AuditID        *int64     `db:"audit_id"`
+	CreatedAt      time.Time  `db:"created_at"`
+	ReviewedAt     *time.Time `db:"reviewed_at"`
+}
+
+func (ProfileChangeRequestRecord) TableName() string {
+	return "profile_change_requests"
+}
@@ -0,0 +1,35 @@
+package record
+
+import "time"
+
+// ProfileIntegrationRecord represents an OAuth connection between an account and an external provider
+type ProfileIntegrationRecord struct {
+	IntegrationID int64      `db:"integration_id"`
+	AccountID     int64      `db:"account_id"`
+	Provider      string     `db:"provider"`
+	AccessToken   string     `db:"access_token"`
+	RefreshToken  string     `db:"refresh_token"`
+	ExpiresAt     time.Time  `db:"expires_at"`
+	LastSyncedAt  *time.Time `db:"last_synced_at"`
+	CreatedAt     time.Time  `db:"created_at"`
+}
+
+func (ProfileIntegrationRecord) TableName() string {
+	return "profile_integrations"
+}
+
+// ProfileImportRecord represents profile content imported from a provider, kept with its source
+type ProfileImportRecord struct {
+	ImportID     int64     `db:"import_id"`
+	AccountID    int64     `db:"account_id"`
+	Provider     string    `db:"provider"`
+	ContentType  string    `db:"content_type"`
+	ExternalID   string    `db:"external_id"`
+	ContentValue string    `db:"content_value"`
+	ContentURL   string    `db:"content_url"`
+	ImportedAt   time.Time `db:"imported_at"`
+}
+
+func (ProfileImportRecord) TableName() string {
+	return "profile_imports"
+}
@@ -0,0 +1,21 @@
+package record
+
+import "time"
+
+// ProfilePhotoRecord is an uploaded photo, the file itself lives in the media storage under StorageKey.
+// A deleted photo stays in the trash with DeletedAt set until it is purged
+type ProfilePhotoRecord struct {
+	PhotoID     int64      `db:"photo_id"`
+	AccountID   int64      `db:"account_id"`
+	StorageKey  string     `db:"storage_key"`
+	ContentType string     `db:"content_type"`
+	SizeBytes   int64      `db:"size_bytes"`
+	Position    int        `db:"position"`
+	IsPrimary   bool       `db:"is_primary"`
+	CreatedAt   time.Time  `db:"created_at"`
+	DeletedAt   *time.Time `db:"deleted_at"`
+}
+
+func (ProfilePhotoRecord) TableName() string {
+	return "profile_photos"
+}
@@ -0,0 +1,17 @@
+package record
+
+import "time"
+
+// ProfileShareLinkRecord represents a public link to a limited version of a profile
+type ProfileShareLinkRecord struct {
+	LinkID    int64      `db:"link_id"`
+	AccountID int64      `db:"account_id"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	RevokedAt *time.Time `db:"revoked_at"`
+	ViewCount int64      `db:"view_count"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+func (ProfileShareLinkRecord) TableName() string {
+	return "profile_share_links"
+}
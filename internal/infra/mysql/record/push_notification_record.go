@@ -0,0 +1,40 @@
+package record
+
+import (
+	"database/sql"
+	"time"
+)
+
+// DeviceTokenRecord a token belongs to the account last signed in on the device
+type DeviceTokenRecord struct {
+	DeviceID  int64     `db:"device_id"`
+	AccountID int64     `db:"account_id"`
+	Provider  string    `db:"provider"`
+	Token     string    `db:"token"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (DeviceTokenRecord) TableName() string {
+	return "device_tokens"
+}
+
+// PushNotificationRecord is queued with the change it tells about and sent by the push delivery job, Data is a JSON object
+type PushNotificationRecord struct {
+	PushID        int64          `db:"push_id"`
+	AccountID     int64          `db:"account_id"`
+	Kind          string         `db:"kind"`
+	Title         string         `db:"title"`
+	Body          string         `db:"body"`
+	Data          sql.NullString `db:"data"`
+	Status        string         `db:"status"`
+	Attempts      int            `db:"attempts"`
+	NextAttemptAt time.Time      `db:"next_attempt_at"`
+	LastError     sql.NullString `db:"last_error"`
+	CreatedAt     time.Time      `db:"created_at"`
+	SentAt        sql.NullTime   `db:"sent_at"`
+}
+
+func (PushNotificationRecord) TableName() string {
+	return "push_notifications"
+}
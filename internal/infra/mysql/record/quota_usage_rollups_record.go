@@ -0,0 +1,19 @@
+package record
+
+import "time"
+
+// QuotaUsageRollupRecord represents the quota consumption of one tier on one day
+type QuotaUsageRollupRecord struct {
+	RollupDate        time.Time `db:"rollup_date"`
+	Tier              string    `db:"tier"`
+	Accounts          int64     `db:"accounts"`
+	ActiveAccounts    int64     `db:"active_accounts"`
+	TotalSwipes       int64     `db:"total_swipes"`
+	TotalLikes        int64     `db:"total_likes"`
+	ExhaustedAccounts int64     `db:"exhausted_accounts"`
+	CreatedAt         time.Time `db:"created_at"`
+}
+
+func (QuotaUsageRollupRecord) TableName() string {
+	return "quota_usage_rollups"
+}
@@ -0,0 +1,55 @@
+package record
+
+import "time"
+
+// RecoveryContactRecord is an account nominated by the owner to vouch for a recovery
+type RecoveryContactRecord struct {
+	AccountID        int64     `db:"account_id"`
+	ContactAccountID int64     `db:"contact_account_id"`
+	CreatedAt        time.Time `db:"created_at"`
+}
+
+func (RecoveryContactRecord) TableName() string {
+	return "recovery_contacts"
+}
+
+// RecoverySettingRecord is how many of the nominated contacts must approve a recovery
+type RecoverySettingRecord struct {
+	AccountID int64     `db:"account_id"`
+	Threshold int       `db:"threshold"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (RecoverySettingRecord) TableName() string {
+	return "recovery_settings"
+}
+
+// RecoveryRequestRecord is one attempt to regain an account, only the hash of the requester's token is kept
+type RecoveryRequestRecord struct {
+	RequestID         int64      `db:"request_id"`
+	AccountID         int64      `db:"account_id"`
+	TokenHash         string     `db:"token_hash"`
+	Status            string     `db:"status"`
+	ApprovalsRequired int        `db:"approvals_required"`
+	FailedAttempts    int        `db:"failed_attempts"`
+	CreatedAt         time.Time  `db:"created_at"`
+	AvailableAt       time.Time  `db:"available_at"`
+	ExpiresAt         time.Time  `db:"expires_at"`
+	ClosedAt          *time.Time `db:"closed_at"`
+}
+
+func (RecoveryRequestRecord) TableName() string {
+	return "recovery_requests"
+}
+
+// RecoveryApprovalRecord is a contact vouching for a request, the code handed to the requester is kept hashed
+type RecoveryApprovalRecord struct {
+	RequestID        int64     `db:"request_id"`
+	ContactAccountID int64     `db:"contact_account_id"`
+	CodeHash         string    `db:"code_hash"`
+	ApprovedAt       time.Time `db:"approved_at"`
+}
+
+func (RecoveryApprovalRecord) TableName() string {
+	return "recovery_approvals"
+}
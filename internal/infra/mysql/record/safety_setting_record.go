@@ -0,0 +1,14 @@
+package record
+
+import "time"
+
+// SafetySettingRecord holds the safety settings of an account, an account without a row has every setting off
+type SafetySettingRecord struct {
+	AccountID     int64     `db:"account_id"`
+	LateNightMode bool      `db:"late_night_mode"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+func (SafetySettingRecord) TableName() string {
+	return "safety_settings"
+}
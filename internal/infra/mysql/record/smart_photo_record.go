@@ -0,0 +1,34 @@
+package record
+
+import "time"
+
+// SmartPhotoSettingRecord turns the rotation of the lead photo on for an account
+type SmartPhotoSettingRecord struct {
+	AccountID int64     `db:"account_id"`
+	Enabled   bool      `db:"enabled"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (SmartPhotoSettingRecord) TableName() string {
+	return "smart_photo_settings"
+}
+
+// PhotoImpressionRecord is the photo a viewer was shown first, one row per owner and viewer
+type PhotoImpressionRecord struct {
+	OwnerAccountID  int64     `db:"owner_account_id"`
+	ViewerAccountID int64     `db:"viewer_account_id"`
+	PhotoID         int64     `db:"photo_id"`
+	Liked           bool      `db:"liked"`
+	ShownAt         time.Time `db:"shown_at"`
+}
+
+func (PhotoImpressionRecord) TableName() string {
+	return "photo_impressions"
+}
+
+// PhotoPerformanceRecord counts the viewers shown a photo first and how many of them liked the profile
+type PhotoPerformanceRecord struct {
+	PhotoID     int64 `db:"photo_id"`
+	Impressions int64 `db:"impressions"`
+	Likes       int64 `db:"likes"`
+}
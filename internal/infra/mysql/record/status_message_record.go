@@ -0,0 +1,19 @@
+package record
+
+import "time"
+
+// StatusMessageRecord is an operational notice shown in the apps between starts_at and ends_at, open ended without ends_at
+type StatusMessageRecord struct {
+	MessageID int64      `db:"message_id"`
+	Severity  string     `db:"severity"`
+	Title     string     `db:"title"`
+	Message   string     `db:"message"`
+	StartsAt  time.Time  `db:"starts_at"`
+	EndsAt    *time.Time `db:"ends_at"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt time.Time  `db:"updated_at"`
+}
+
+func (StatusMessageRecord) TableName() string {
+	return "status_messages"
+}
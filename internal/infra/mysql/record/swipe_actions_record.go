@@ -16,7 +16,43 @@ func (SwipeRecord) TableName() string {
 	return "swipes"
 }
 
+// LastSwipeRecord is the latest swipe of an account while it can still be undone, MatchID is set when the like made a match
+type LastSwipeRecord struct {
+	AccountID      int64     `db:"account_id"`
+	SwipeID        int64     `db:"swipe_id"`
+	AccountIDSwipe int64     `db:"account_id_swipe"`
+	Action         string    `db:"action"`
+	QuotaUsed      bool      `db:"quota_used"`
+	MatchID        *int64    `db:"match_id"`
+	SwipeDate      time.Time `db:"swipe_date"`
+	SwipedAt       time.Time `db:"swiped_at"`
+}
+
+func (LastSwipeRecord) TableName() string {
+	return "last_swipes"
+}
+
 type SwipeActionsRecord struct {
 	TotalSwipeLike *int64 `db:"total_swipe_like"`
 	TotalSwipePass *int64 `db:"total_swipe_pass"`
 }
+
+// SuperLikeRecord is a like sent with the super like quota, SwipeID is the LIKED swipe stored with it
+type SuperLikeRecord struct {
+	SuperLikeID     int64     `db:"super_like_id"`
+	AccountID       int64     `db:"account_id"`
+	TargetAccountID int64     `db:"target_account_id"`
+	SwipeID         int64     `db:"swipe_id"`
+	CreatedAt       time.Time `db:"created_at"`
+}
+
+func (SuperLikeRecord) TableName() string {
+	return "super_likes"
+}
+
+// LikeReceivedRecord is an account that liked the viewer, LikedOn is the day of its latest like
+type LikeReceivedRecord struct {
+	AccountID  int64     `db:"account_id"`
+	LikedOn    time.Time `db:"liked_on"`
+	SuperLiked bool      `db:"super_liked"`
+}
@@ -12,6 +12,9 @@ type UserRecord struct {
 	Gender      string     `db:"gender"`
 	Address     string     `db:"address"`
 	Bio         string     `db:"bio"`
+	PhoneHash   *string    `db:"phone_hash"`
+	Latitude    *float64   `db:"latitude"`
+	Longitude   *float64   `db:"longitude"`
 	CreatedAt   time.Time  `db:"created_at"`
 	UpdatedAt   time.Time  `db:"updated_at"`
 }
@@ -23,6 +26,7 @@ func (UserRecord) TableName() string {
 // UserAccountRecord represents a user profile with additional verified field
 type UserAccountRecord struct {
 	UserRecord
-	Verified bool
-	Username string
+	Verified   bool
+	Username   string
+	DistanceKm *float64
 }
@@ -0,0 +1,18 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+	"time"
+)
+
+type AccountDormancyRepository interface {
+	FindInactiveAccountsFromDB(ctx context.Context, tx *sql.Tx, state string, inactiveBefore time.Time, stateBefore time.Time, limit int) ([]record.AccountDormancyRecord, error)
+	FindDormancyStateFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (record.AccountDormancyRecord, error)
+	UpsertDormancyStateToDB(ctx context.Context, tx *sql.Tx, dormancy record.AccountDormancyRecord) error
+	DeleteDormancyStateFromDB(ctx context.Context, tx *sql.Tx, accountId int64) error
+	InsertDormancyEventToDB(ctx context.Context, tx *sql.Tx, event record.AccountDormancyEventRecord) error
+	PurgeAccountDataFromDB(ctx context.Context, tx *sql.Tx, accountId int64) error
+	EraseAccountDataFromDB(ctx context.Context, tx *sql.Tx, accountId int64) error
+}
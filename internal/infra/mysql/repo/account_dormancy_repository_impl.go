@@ -0,0 +1,196 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/record"
+	"strings"
+	"time"
+)
+
+type AccountDormancyRepositoryImpl struct {
+	AccountDormancyRepository AccountDormancyRepository
+}
+
+func NewAccountDormancyRepositoryImpl() AccountDormancyRepository {
+	return &AccountDormancyRepositoryImpl{}
+}
+
+// FindInactiveAccountsFromDB finds accounts in the given state ("" is active) whose last login, raw or summarised, or signup when never logged in,
+// is before inactiveBefore and whose state has not changed since stateBefore
+func (a AccountDormancyRepositoryImpl) FindInactiveAccountsFromDB(ctx context.Context, tx *sql.Tx, state string, inactiveBefore time.Time, stateBefore time.Time, limit int) ([]record.AccountDormancyRecord, error) {
+	query := `SELECT a.account_id, COALESCE(d.state, ''),
+			GREATEST(COALESCE(MAX(lh.login_at), a.created_at), COALESCE(MAX(ls.last_login_at), a.created_at)) AS last_active_at
+		FROM accounts a
+		LEFT JOIN login_histories lh ON lh.account_id = a.account_id
+		LEFT JOIN (SELECT account_id, MAX(last_login_at) AS last_login_at FROM login_history_summaries GROUP BY account_id) ls ON ls.account_id = a.account_id
+		LEFT JOIN account_dormancy d ON d.account_id = a.account_id
+		WHERE COALESCE(d.state, '') = ? AND (d.updated_at IS NULL OR d.updated_at < ?)
+		GROUP BY a.account_id, a.created_at, d.state
+		HAVING last_active_at < ?
+		ORDER BY last_active_at
+		LIMIT ?`
+
+	rows, err := tx.QueryContext(ctx, query, state, stateBefore, inactiveBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var accounts []record.AccountDormancyRecord
+	for rows.Next() {
+		var dormancy record.AccountDormancyRecord
+		if err := rows.Scan(&dormancy.AccountID, &dormancy.State, &dormancy.LastActiveAt); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		accounts = append(accounts, dormancy)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return accounts, nil
+}
+
+func (a AccountDormancyRepositoryImpl) FindDormancyStateFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (record.AccountDormancyRecord, error) {
+	query := "SELECT account_id, state, last_active_at, updated_at FROM account_dormancy WHERE account_id = ?"
+
+	var dormancy record.AccountDormancyRecord
+	err := tx.QueryRowContext(ctx, query, accountId).Scan(
+		&dormancy.AccountID,
+		&dormancy.State,
+		&dormancy.LastActiveAt,
+		&dormancy.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dormancy, errors.New("dormancy state not found")
+		}
+		return dormancy, fmt.Errorf("could not find dormancy state: %v", err)
+	}
+	return dormancy, nil
+}
+
+func (a AccountDormancyRepositoryImpl) UpsertDormancyStateToDB(ctx context.Context, tx *sql.Tx, dormancy record.AccountDormancyRecord) error {
+	query := `INSERT INTO account_dormancy (account_id, state, last_active_at) VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE state = VALUES(state), last_active_at = VALUES(last_active_at), updated_at = CURRENT_TIMESTAMP`
+
+	_, err := tx.ExecContext(ctx, query, dormancy.AccountID, dormancy.State, dormancy.LastActiveAt)
+	if err != nil {
+		return fmt.Errorf("could not save dormancy state: %v", err)
+	}
+	return nil
+}
+
+func (a AccountDormancyRepositoryImpl) DeleteDormancyStateFromDB(ctx context.Context, tx *sql.Tx, accountId int64) error {
+	query := "DELETE FROM account_dormancy WHERE account_id = ?"
+
+	_, err := tx.ExecContext(ctx, query, accountId)
+	if err != nil {
+		return fmt.Errorf("could not delete dormancy state: %v", err)
+	}
+	return nil
+}
+
+func (a AccountDormancyRepositoryImpl) InsertDormancyEventToDB(ctx context.Context, tx *sql.Tx, event record.AccountDormancyEventRecord) error {
+	query := "INSERT INTO account_dormancy_events (account_id, from_state, to_state, last_active_at) VALUES (?, ?, ?, ?)"
+
+	_, err := tx.ExecContext(ctx, query, event.AccountID, event.FromState, event.ToState, event.LastActiveAt)
+	if err != nil {
+		return fmt.Errorf("could not insert dormancy event: %v", err)
+	}
+	return nil
+}
+
+// PurgeAccountDataFromDB removes personal data and anonymises the account in place,
+// the account id stays so swipes, quotas and purchases remain valid for aggregate reporting
+func (a AccountDormancyRepositoryImpl) PurgeAccountDataFromDB(ctx context.Context, tx *sql.Tx, accountId int64) error {
+	statements := []string{
+		"DELETE FROM notes WHERE owner_account_id = ? OR target_account_id = ?",
+		"DELETE FROM contact_exclusions WHERE account_id = ?",
+		"DELETE FROM hidden_accounts WHERE account_id = ? OR hidden_account_id = ?",
+		"DELETE FROM blocks WHERE account_id = ? OR blocked_account_id = ?",
+		"DELETE FROM event_connections WHERE account_id = ? OR target_account_id = ?",
+		"DELETE FROM last_swipes WHERE account_id = ? OR account_id_swipe = ?",
+		"DELETE FROM profile_imports WHERE account_id = ?",
+		"DELETE FROM profile_integrations WHERE account_id = ?",
+		"DELETE FROM profile_share_links WHERE account_id = ?",
+		"DELETE FROM login_streaks WHERE account_id = ?",
+		"DELETE FROM login_histories WHERE account_id = ?",
+		"DELETE FROM login_history_summaries WHERE account_id = ?",
+		"DELETE FROM passkey_credentials WHERE account_id = ?",
+		"DELETE FROM device_tokens WHERE account_id = ?",
+		"DELETE FROM push_notifications WHERE account_id = ?",
+		"DELETE FROM notifications WHERE account_id = ?",
+		"DELETE FROM recovery_approvals WHERE contact_account_id = ? OR request_id IN (SELECT request_id FROM recovery_requests WHERE account_id = ?)",
+		"DELETE FROM recovery_requests WHERE account_id = ?",
+		"DELETE FROM recovery_contacts WHERE account_id = ? OR contact_account_id = ?",
+		"DELETE FROM recovery_settings WHERE account_id = ?",
+		"DELETE FROM safety_settings WHERE account_id = ?",
+		"DELETE FROM account_features WHERE account_id = ?",
+		"DELETE FROM pair_features WHERE account_id = ? OR candidate_account_id = ?",
+		"DELETE FROM match_scores WHERE account_id = ? OR candidate_account_id = ?",
+		"DELETE FROM profile_change_requests WHERE account_id = ?",
+		"DELETE FROM messages WHERE match_id IN (SELECT match_id FROM matches WHERE account_id_low = ? OR account_id_high = ?)",
+		"DELETE FROM match_participants WHERE match_id IN (SELECT match_id FROM matches WHERE account_id_low = ? OR account_id_high = ?)",
+		"DELETE FROM matches WHERE account_id_low = ? OR account_id_high = ?",
+		"DELETE FROM messages WHERE sender_account_id = ?",
+		"DELETE FROM match_participants WHERE account_id = ?",
+		"UPDATE duos SET state = 'unlinked', unlinked_at = CURRENT_TIMESTAMP WHERE (account_id_low = ? OR account_id_high = ?) AND state != 'unlinked'",
+		"DELETE FROM profile_photos WHERE account_id = ?",
+		"DELETE FROM storages WHERE account_id = ?",
+		"UPDATE users SET full_name = NULL, date_of_birth = NULL, age = NULL, gender = NULL, address = NULL, bio = NULL, phone_hash = NULL, updated_at = CURRENT_TIMESTAMP WHERE account_id = ?",
+		"UPDATE accounts SET username = CONCAT('purged_', account_id), email = CONCAT('purged_', account_id, '@invalid'), password_hash = '', verified = FALSE, provider = NULL, provider_id = NULL, updated_at = CURRENT_TIMESTAMP WHERE account_id = ?",
+	}
+
+	if err := execForAccount(ctx, tx, statements, accountId); err != nil {
+		return fmt.Errorf("could not purge account data: %v", err)
+	}
+	return nil
+}
+
+// EraseAccountDataFromDB purges the account the owner deleted and also removes what the dormancy purge keeps for
+// reporting, the swipes, selections, views and quotas and the user row. The anonymised account row stays for the
+// purchases and the reports about the account
+func (a AccountDormancyRepositoryImpl) EraseAccountDataFromDB(ctx context.Context, tx *sql.Tx, accountId int64) error {
+	statements := []string{
+		"DELETE FROM selection_histories WHERE account_id = ? OR account_id_identifier = ?",
+		"DELETE FROM view_accounts WHERE account_id = ? OR user_id IN (SELECT user_id FROM users WHERE account_id = ?)",
+		"DELETE FROM photo_impressions WHERE owner_account_id = ? OR viewer_account_id = ?",
+		"DELETE FROM super_likes WHERE account_id = ? OR target_account_id = ?",
+		"DELETE FROM swipes WHERE account_id = ? OR account_id_swipe = ?",
+		"DELETE FROM daily_quotas WHERE account_id = ?",
+		"DELETE FROM task_histories WHERE account_id_identifier = ?",
+		"DELETE FROM smart_photo_settings WHERE account_id = ?",
+	}
+	if err := execForAccount(ctx, tx, statements, accountId); err != nil {
+		return fmt.Errorf("could not erase account data: %v", err)
+	}
+
+	if err := a.PurgeAccountDataFromDB(ctx, tx, accountId); err != nil {
+		return err
+	}
+
+	// The storages and login histories referencing the user row went with the purge
+	if _, err := tx.ExecContext(ctx, "DELETE FROM users WHERE account_id = ?", accountId); err != nil {
+		return fmt.Errorf("could not erase account data: %v", err)
+	}
+	return nil
+}
+
+// execForAccount runs the statements with the account id for each placeholder
+func execForAccount(ctx context.Context, tx *sql.Tx, statements []string, accountId int64) error {
+	for _, statement := range statements {
+		args := make([]interface{}, strings.Count(statement, "?"))
+		for i := range args {
+			args[i] = accountId
+		}
+		if _, err := tx.ExecContext(ctx, statement, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
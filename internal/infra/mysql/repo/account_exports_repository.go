@@ -0,0 +1,11 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+type AccountExportsRepository interface {
+	FindAccountExportFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.AccountExportSectionRecord, error)
+}
@@ -0,0 +1,109 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/record"
+	"strings"
+)
+
+// accountExportSections is what the export has of each table, every placeholder is the account id. Passwords,
+// provider and push tokens, passkey keys and the phone hash are not personal data the owner can use and are left out.
+// A table with personal data gets a section here and a statement in the purge
+var accountExportSections = []struct {
+	Name  string
+	Query string
+}{
+	{"account", "SELECT account_id, username, email, verified, provider, home_region, created_at, updated_at FROM accounts WHERE account_id = ?"},
+	{"profile", "SELECT user_id, full_name, date_of_birth, age, gender, address, bio, latitude, longitude, location_updated_at, created_at, updated_at FROM users WHERE account_id = ?"},
+	{"photos", "SELECT photo_id, storage_key, content_type, size_bytes, position, is_primary, created_at, deleted_at FROM profile_photos WHERE account_id = ? ORDER BY position"},
+	{"smart_photos", "SELECT enabled, updated_at FROM smart_photo_settings WHERE account_id = ?"},
+	{"swipes", "SELECT swipe_id, account_id_swipe, action, swipe_date FROM swipes WHERE account_id = ? ORDER BY swipe_id"},
+	{"super_likes", "SELECT target_account_id, created_at FROM super_likes WHERE account_id = ? ORDER BY created_at"},
+	{"matches", "SELECT match_id, title, account_id_low, account_id_high, created_at FROM matches WHERE account_id_low = ? OR account_id_high = ? OR match_id IN (SELECT match_id FROM match_participants WHERE account_id = ?) ORDER BY match_id"},
+	{"messages", "SELECT message_id, match_id, body, created_at FROM messages WHERE sender_account_id = ? ORDER BY message_id"},
+	{"duos", "SELECT duo_id, account_id_low, account_id_high, invited_by_account_id, state, created_at, linked_at, unlinked_at FROM duos WHERE account_id_low = ? OR account_id_high = ? ORDER BY duo_id"},
+	{"event_connections", "SELECT event_id, target_account_id, created_at FROM event_connections WHERE account_id = ? ORDER BY created_at"},
+	{"notes", "SELECT note_id, target_account_id, content, created_at, updated_at FROM notes WHERE owner_account_id = ? ORDER BY note_id"},
+	{"blocks", "SELECT blocked_account_id, created_at FROM blocks WHERE account_id = ? ORDER BY created_at"},
+	{"hidden_accounts", "SELECT hidden_account_id, created_at FROM hidden_accounts WHERE account_id = ? ORDER BY created_at"},
+	{"reports", "SELECT report_id, reported_account_id, reason, details, created_at FROM reports WHERE reporter_account_id = ? ORDER BY report_id"},
+	{"contact_exclusions", "SELECT contact_hash, created_at FROM contact_exclusions WHERE account_id = ? ORDER BY created_at"},
+	{"recovery_contacts", "SELECT contact_account_id, created_at FROM recovery_contacts WHERE account_id = ? ORDER BY created_at"},
+	{"recovery_settings", "SELECT threshold, updated_at FROM recovery_settings WHERE account_id = ?"},
+	{"safety_settings", "SELECT late_night_mode, updated_at FROM safety_settings WHERE account_id = ?"},
+	{"profile_integrations", "SELECT provider, expires_at, last_synced_at, created_at FROM profile_integrations WHERE account_id = ?"},
+	{"profile_imports", "SELECT provider, content_type, content_value, content_url, imported_at FROM profile_imports WHERE account_id = ? ORDER BY import_id"},
+	{"profile_share_links", "SELECT link_id, expires_at, revoked_at, view_count, created_at FROM profile_share_links WHERE account_id = ? ORDER BY link_id"},
+	{"profile_change_requests", "SELECT request_id, field, current_value, requested_value, evidence, reason, status, review_note, created_at, reviewed_at FROM profile_change_requests WHERE account_id = ? ORDER BY request_id"},
+	{"premium_purchases", "SELECT purchase_id, package_id, purchase_date, expiry_date, unlimited_swipes_active, status FROM account_premiums WHERE account_id = ? ORDER BY purchase_id"},
+	{"daily_quotas", "SELECT date, total_quota, swipe_count, super_like_count FROM daily_quotas WHERE account_id = ? ORDER BY date"},
+	{"login_histories", "SELECT login_at, logout_at, duration_in_seconds FROM login_histories WHERE account_id = ? ORDER BY login_at"},
+	{"login_history_summaries", "SELECT summary_month, login_count, closed_sessions, total_duration_seconds, first_login_at, last_login_at FROM login_history_summaries WHERE account_id = ? ORDER BY summary_month"},
+	{"login_streaks", "SELECT timezone, current_streak, longest_streak, last_login_day, last_claimed_day FROM login_streaks WHERE account_id = ?"},
+	{"passkeys", "SELECT name, created_at, last_used_at FROM passkey_credentials WHERE account_id = ? ORDER BY created_at"},
+	{"devices", "SELECT device_id, provider, created_at, updated_at FROM device_tokens WHERE account_id = ? ORDER BY device_id"},
+	{"notifications", "SELECT notification_id, kind, title, body, read_at, created_at FROM notifications WHERE account_id = ? ORDER BY notification_id"},
+	{"dormancy", "SELECT state, last_active_at, updated_at FROM account_dormancy WHERE account_id = ?"},
+}
+
+type AccountExportsRepositoryImpl struct{}
+
+func NewAccountExportsRepositoryImpl() AccountExportsRepository {
+	return &AccountExportsRepositoryImpl{}
+}
+
+// FindAccountExportFromDB reads every section in the order of accountExportSections, a section without rows is empty
+func (a AccountExportsRepositoryImpl) FindAccountExportFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.AccountExportSectionRecord, error) {
+	sections := make([]record.AccountExportSectionRecord, 0, len(accountExportSections))
+	for _, section := range accountExportSections {
+		args := make([]interface{}, strings.Count(section.Query, "?"))
+		for i := range args {
+			args[i] = accountId
+		}
+		rows, err := findExportRows(ctx, tx, section.Query, args)
+		if err != nil {
+			return nil, fmt.Errorf("could not export %s: %v", section.Name, err)
+		}
+		sections = append(sections, record.AccountExportSectionRecord{Name: section.Name, Rows: rows})
+	}
+	return sections, nil
+}
+
+func findExportRows(ctx context.Context, tx *sql.Tx, query string, args []interface{}) ([]map[string]interface{}, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	found := []map[string]interface{}{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		targets := make([]interface{}, len(columns))
+		for i := range values {
+			targets[i] = &values[i]
+		}
+		if err := rows.Scan(targets...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			// Text columns are scanned as bytes by the driver
+			if value, ok := values[i].([]byte); ok {
+				row[column] = string(value)
+				continue
+			}
+			row[column] = values[i]
+		}
+		found = append(found, row)
+	}
+	return found, rows.Err()
+}
@@ -11,9 +11,16 @@ type AccountRepository interface {
 	FindAccountByUsernameFromDB(ctx context.Context, tx *sql.Tx, username string) (record.AccountRecord, error)
 	FindAccountByEmailFromDB(ctx context.Context, tx *sql.Tx, email string) (record.AccountRecord, error)
 	FindAccountByUsernameAndEmailFromDB(ctx context.Context, tx *sql.Tx, email string, username string) (record.AccountRecord, error)
-	IsExistAccountByEmailFromDB(ctx context.Context, tx *sql.Tx, email string) bool
-	IsExistAccountByUsernameFromDB(ctx context.Context, tx *sql.Tx, username string) bool
 	FindAccountVerifiedByAccountIdFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (bool, error)
 	UpdateAccountVerifiedByAccountIdFromDB(ctx context.Context, tx *sql.Tx, accountId int64) error
+	UpdateAccountUnverifiedByAccountIdFromDB(ctx context.Context, tx *sql.Tx, accountId int64) error
+	UpdateAccountPasswordByAccountIdFromDB(ctx context.Context, tx *sql.Tx, accountId int64, passwordHash string) error
 	FindAccountByIdFromDB(ctx context.Context, tx *sql.Tx, id int64) (record.AccountRecord, error)
+	FindAccountConflictsFromDB(ctx context.Context, tx *sql.Tx, email string, username string) (bool, bool, error)
+	FindAccountByProviderFromDB(ctx context.Context, tx *sql.Tx, provider string, providerId string) (record.AccountRecord, error)
+	FindAccountProviderFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (sql.NullString, error)
+	UpdateAccountProviderToDB(ctx context.Context, tx *sql.Tx, accountId int64, provider string, providerId string) error
+	FindAccountHomeRegionFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (sql.NullString, error)
+	FindAccountHomeRegionByLoginFromDB(ctx context.Context, tx *sql.Tx, login string) (sql.NullString, error)
+	UpdateAccountHomeRegionToDB(ctx context.Context, tx *sql.Tx, accountId int64, region string) error
 }
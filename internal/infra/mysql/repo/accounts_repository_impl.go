@@ -27,6 +27,11 @@ func (a AccountRepositoryImpl) CreateAccountToDB(ctx context.Context, tx *sql.Tx
 		accountRecord.Verified,
 	)
 	if err != nil {
+		// The unique constraints on username and email are the only existence check, so concurrent signups cannot both pass
+		var duplicate *DuplicateKeyError
+		if errors.As(asDuplicateKeyError(err), &duplicate) {
+			return record.AccountRecord{}, duplicate
+		}
 		return record.AccountRecord{}, fmt.Errorf("could not insert account: %v", err)
 	}
 
@@ -42,32 +47,6 @@ func (a AccountRepositoryImpl) CreateAccountToDB(ctx context.Context, tx *sql.Tx
 	return accountRecord, nil
 }
 
-func (a AccountRepositoryImpl) IsExistAccountByEmailFromDB(ctx context.Context, tx *sql.Tx, email string) bool {
-	result, err := tx.ExecContext(ctx, queries.FindByEmailAccountRecord, email)
-	if err != nil {
-		return false
-	}
-
-	rowCount, err := result.RowsAffected()
-	if err != nil {
-		return false
-	}
-	return rowCount > 0
-}
-
-func (a AccountRepositoryImpl) IsExistAccountByUsernameFromDB(ctx context.Context, tx *sql.Tx, username string) bool {
-	result, err := tx.ExecContext(ctx, queries.FindByUsernameAccountRecord, username)
-	if err != nil {
-		return false
-	}
-
-	rowCount, err := result.RowsAffected()
-	if err != nil {
-		return false
-	}
-	return rowCount > 0
-}
-
 func (a AccountRepositoryImpl) FindAccountByUsernameFromDB(ctx context.Context, tx *sql.Tx, username string) (record.AccountRecord, error) {
 	// Query the database to find the account record by username
 	row := tx.QueryRowContext(ctx, queries.GetByUsernameAccountRecord, username)
@@ -180,6 +159,24 @@ func (a AccountRepositoryImpl) UpdateAccountVerifiedByAccountIdFromDB(ctx contex
 	return err
 }
 
+func (a AccountRepositoryImpl) UpdateAccountUnverifiedByAccountIdFromDB(ctx context.Context, tx *sql.Tx, accountId int64) error {
+	query := "UPDATE accounts SET verified = FALSE, updated_at = CURRENT_TIMESTAMP WHERE account_id = ? AND verified = TRUE"
+	_, err := tx.ExecContext(ctx, query, accountId)
+	if err != nil {
+		return fmt.Errorf("could not update account unverified: %v", err)
+	}
+	return nil
+}
+
+func (a AccountRepositoryImpl) UpdateAccountPasswordByAccountIdFromDB(ctx context.Context, tx *sql.Tx, accountId int64, passwordHash string) error {
+	query := "UPDATE accounts SET password_hash = ?, updated_at = CURRENT_TIMESTAMP WHERE account_id = ?"
+	_, err := tx.ExecContext(ctx, query, passwordHash, accountId)
+	if err != nil {
+		return fmt.Errorf("could not update account password: %v", err)
+	}
+	return nil
+}
+
 func (a AccountRepositoryImpl) FindAccountByIdFromDB(ctx context.Context, tx *sql.Tx, id int64) (record.AccountRecord, error) {
 	row := tx.QueryRowContext(ctx, "SELECT a.account_id, a.username, a.email, a.verified FROM accounts a WHERE account_id = ?", id)
 	// Initialize a new AccountRecord to store the result
@@ -196,3 +193,87 @@ func (a AccountRepositoryImpl) FindAccountByIdFromDB(ctx context.Context, tx *sq
 	// Return the retrieved account record
 	return accountRecord, nil
 }
+
+// FindAccountConflictsFromDB reports whether the email and the username are registered, both in one round trip
+func (a AccountRepositoryImpl) FindAccountConflictsFromDB(ctx context.Context, tx *sql.Tx, email string, username string) (bool, bool, error) {
+	var emailTaken, usernameTaken bool
+	err := tx.QueryRowContext(ctx, queries.FindAccountConflictsRecord, email, username).Scan(&emailTaken, &usernameTaken)
+	if err != nil {
+		return false, false, fmt.Errorf("could not check account conflicts: %v", err)
+	}
+	return emailTaken, usernameTaken, nil
+}
+
+// FindAccountByProviderFromDB returns sql.ErrNoRows when no account is linked to the identity
+func (a AccountRepositoryImpl) FindAccountByProviderFromDB(ctx context.Context, tx *sql.Tx, provider string, providerId string) (record.AccountRecord, error) {
+	var accountRecord record.AccountRecord
+	err := tx.QueryRowContext(ctx, queries.GetByProviderAccountRecord, provider, providerId).Scan(
+		&accountRecord.AccountID,
+		&accountRecord.Username,
+		&accountRecord.PasswordHash,
+		&accountRecord.Email,
+		&accountRecord.Verified,
+		&accountRecord.CreatedAt,
+		&accountRecord.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return record.AccountRecord{}, err
+		}
+		return record.AccountRecord{}, fmt.Errorf("error scanning account record: %v", err)
+	}
+	return accountRecord, nil
+}
+
+// FindAccountProviderFromDB returns the provider the account is linked to, NULL for an account without social login
+func (a AccountRepositoryImpl) FindAccountProviderFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (sql.NullString, error) {
+	var provider sql.NullString
+	err := tx.QueryRowContext(ctx, "SELECT provider FROM accounts WHERE account_id = ?", accountId).Scan(&provider)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("could not find account provider: %v", err)
+	}
+	return provider, nil
+}
+
+func (a AccountRepositoryImpl) UpdateAccountProviderToDB(ctx context.Context, tx *sql.Tx, accountId int64, provider string, providerId string) error {
+	query := "UPDATE accounts SET provider = ?, provider_id = ?, updated_at = CURRENT_TIMESTAMP WHERE account_id = ?"
+	if _, err := tx.ExecContext(ctx, query, provider, providerId, accountId); err != nil {
+		var duplicate *DuplicateKeyError
+		if errors.As(asDuplicateKeyError(err), &duplicate) {
+			return duplicate
+		}
+		return fmt.Errorf("could not update account provider: %v", err)
+	}
+	return nil
+}
+
+func (a AccountRepositoryImpl) FindAccountHomeRegionFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (sql.NullString, error) {
+	var region sql.NullString
+	err := tx.QueryRowContext(ctx, "SELECT home_region FROM accounts WHERE account_id = ?", accountId).Scan(&region)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("could not find account home region: %v", err)
+	}
+	return region, nil
+}
+
+// FindAccountHomeRegionByLoginFromDB finds the home region by the username or the email, it returns sql.ErrNoRows
+// when no account has either
+func (a AccountRepositoryImpl) FindAccountHomeRegionByLoginFromDB(ctx context.Context, tx *sql.Tx, login string) (sql.NullString, error) {
+	var region sql.NullString
+	err := tx.QueryRowContext(ctx, "SELECT home_region FROM accounts WHERE username = ? OR email = ? LIMIT 1", login, login).Scan(&region)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return sql.NullString{}, err
+		}
+		return sql.NullString{}, fmt.Errorf("could not find account home region: %v", err)
+	}
+	return region, nil
+}
+
+func (a AccountRepositoryImpl) UpdateAccountHomeRegionToDB(ctx context.Context, tx *sql.Tx, accountId int64, region string) error {
+	query := "UPDATE accounts SET home_region = ?, updated_at = CURRENT_TIMESTAMP WHERE account_id = ?"
+	if _, err := tx.ExecContext(ctx, query, region, accountId); err != nil {
+		return fmt.Errorf("could not update account home region: %v", err)
+	}
+	return nil
+}
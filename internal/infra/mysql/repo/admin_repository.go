@@ -0,0 +1,14 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+type AdminRepository interface {
+	FindAccountTimelineFromDB(ctx context.Context, tx *sql.Tx, accountId int64, limit int, offset int) ([]record.TimelineEventRecord, error)
+	CountAccountTimelineFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (int64, error)
+	StreamAccountTimelineFromDB(ctx context.Context, tx *sql.Tx, accountId int64, fn func(record.TimelineEventRecord) error) error
+	InsertAuditLogToDB(ctx context.Context, tx *sql.Tx, record record.AdminAuditLogRecord) (record.AdminAuditLogRecord, error)
+}
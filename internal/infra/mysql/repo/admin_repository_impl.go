@@ -0,0 +1,104 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/queries"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+// timelineSources is how many times the account id appears in the timeline query
+const timelineSources = 8
+
+type AdminRepositoryImpl struct {
+	AdminRepository AdminRepository
+}
+
+func NewAdminRepositoryImpl() AdminRepository {
+	return &AdminRepositoryImpl{}
+}
+
+func (a AdminRepositoryImpl) FindAccountTimelineFromDB(ctx context.Context, tx *sql.Tx, accountId int64, limit int, offset int) ([]record.TimelineEventRecord, error) {
+	args := timelineArgs(accountId)
+	args = append(args, limit, offset)
+
+	rows, err := tx.QueryContext(ctx, queries.FindAccountTimelineRecord, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var events []record.TimelineEventRecord
+	for rows.Next() {
+		var event record.TimelineEventRecord
+		if err := rows.Scan(&event.EventType, &event.OccurredAt, &event.Detail); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return events, nil
+}
+
+func (a AdminRepositoryImpl) CountAccountTimelineFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (int64, error) {
+	var total int64
+	err := tx.QueryRowContext(ctx, queries.CountAccountTimelineRecord, timelineArgs(accountId)...).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("could not count timeline: %v", err)
+	}
+	return total, nil
+}
+
+// StreamAccountTimelineFromDB hands the whole timeline to fn one row at a time, newest first.
+// An error of fn stops the query and is returned as it is
+func (a AdminRepositoryImpl) StreamAccountTimelineFromDB(ctx context.Context, tx *sql.Tx, accountId int64, fn func(record.TimelineEventRecord) error) error {
+	rows, err := tx.QueryContext(ctx, queries.StreamAccountTimelineRecord, timelineArgs(accountId)...)
+	if err != nil {
+		return fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event record.TimelineEventRecord
+		if err := rows.Scan(&event.EventType, &event.OccurredAt, &event.Detail); err != nil {
+			return fmt.Errorf("could not scan row: %v", err)
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("row iteration error: %v", err)
+	}
+	return nil
+}
+
+func (a AdminRepositoryImpl) InsertAuditLogToDB(ctx context.Context, tx *sql.Tx, record record.AdminAuditLogRecord) (record.AdminAuditLogRecord, error) {
+	query := "INSERT INTO admin_audit_logs (actor, action, target_account_id, justification, changes) VALUES (?, ?, ?, ?, ?)"
+	result, err := tx.ExecContext(ctx, query, record.Actor, record.Action, record.TargetAccountID, record.Justification, record.Changes)
+	if err != nil {
+		return record, fmt.Errorf("could not insert audit log: %v", err)
+	}
+
+	auditId, err := result.LastInsertId()
+	if err != nil {
+		return record, fmt.Errorf("could not retrieve last insert id: %v", err)
+	}
+
+	record.AuditID = auditId
+	return record, nil
+}
+
+func timelineArgs(accountId int64) []interface{} {
+	args := make([]interface{}, 0, timelineSources+2)
+	for i := 0; i < timelineSources; i++ {
+		args = append(args, accountId)
+	}
+	return args
+}
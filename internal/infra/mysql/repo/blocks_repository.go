@@ -0,0 +1,18 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+type BlocksRepository interface {
+	InsertBlockToDB(ctx context.Context, tx *sql.Tx, accountId int64, blockedAccountId int64) error
+	DeleteBlockFromDB(ctx context.Context, tx *sql.Tx, accountId int64, blockedAccountId int64) (int64, error)
+	FindBlocksFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.BlockRecord, error)
+	FindBlockedEitherWayFromDB(ctx context.Context, tx *sql.Tx, accountId int64, otherAccountIds []int64) (bool, error)
+	InsertReportToDB(ctx context.Context, tx *sql.Tx, report record.ReportRecord) (record.ReportRecord, error)
+	FindReportsFromDB(ctx context.Context, tx *sql.Tx, reportedAccountId int64, limit int, offset int) ([]record.ReportRecord, error)
+	CountReportsFromDB(ctx context.Context, tx *sql.Tx, reportedAccountId int64) (int64, error)
+	StreamReportsFromDB(ctx context.Context, tx *sql.Tx, reportedAccountId int64, fn func(record.ReportRecord) error) error
+}
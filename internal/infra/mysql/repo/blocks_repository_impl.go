@@ -0,0 +1,178 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/record"
+	"strings"
+)
+
+const reportColumns = "report_id, reporter_account_id, reported_account_id, reason, details, created_at"
+
+type BlocksRepositoryImpl struct {
+	BlocksRepository BlocksRepository
+}
+
+func NewBlocksRepositoryImpl() BlocksRepository {
+	return &BlocksRepositoryImpl{}
+}
+
+// InsertBlockToDB blocking an account twice keeps the first time it was blocked
+func (b BlocksRepositoryImpl) InsertBlockToDB(ctx context.Context, tx *sql.Tx, accountId int64, blockedAccountId int64) error {
+	_, err := tx.ExecContext(ctx, "INSERT IGNORE INTO blocks (account_id, blocked_account_id) VALUES (?, ?)", accountId, blockedAccountId)
+	if err != nil {
+		return fmt.Errorf("could not insert block: %v", err)
+	}
+	return nil
+}
+
+func (b BlocksRepositoryImpl) DeleteBlockFromDB(ctx context.Context, tx *sql.Tx, accountId int64, blockedAccountId int64) (int64, error) {
+	result, err := tx.ExecContext(ctx, "DELETE FROM blocks WHERE account_id = ? AND blocked_account_id = ?", accountId, blockedAccountId)
+	if err != nil {
+		return 0, fmt.Errorf("could not delete block: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not retrieve rows affected: %v", err)
+	}
+	return rowsAffected, nil
+}
+
+// FindBlocksFromDB returns the most recently blocked first
+func (b BlocksRepositoryImpl) FindBlocksFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.BlockRecord, error) {
+	query := `SELECT b.account_id, b.blocked_account_id, a.username, b.created_at
+		FROM blocks b INNER JOIN accounts a ON a.account_id = b.blocked_account_id
+		WHERE b.account_id = ? ORDER BY b.created_at DESC, b.blocked_account_id`
+
+	rows, err := tx.QueryContext(ctx, query, accountId)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var blocks []record.BlockRecord
+	for rows.Next() {
+		var block record.BlockRecord
+		if err := rows.Scan(&block.AccountID, &block.BlockedAccountID, &block.BlockedUsername, &block.CreatedAt); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		blocks = append(blocks, block)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return blocks, nil
+}
+
+// FindBlockedEitherWayFromDB tells whether the account blocked any of the other accounts or was blocked by one of them
+func (b BlocksRepositoryImpl) FindBlockedEitherWayFromDB(ctx context.Context, tx *sql.Tx, accountId int64, otherAccountIds []int64) (bool, error) {
+	if len(otherAccountIds) == 0 {
+		return false, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(otherAccountIds)), ", ")
+	query := "SELECT COUNT(*) FROM blocks WHERE (account_id = ? AND blocked_account_id IN (" + placeholders + ")) OR (blocked_account_id = ? AND account_id IN (" + placeholders + "))"
+
+	args := []interface{}{accountId}
+	for _, other := range otherAccountIds {
+		args = append(args, other)
+	}
+	args = append(args, accountId)
+	for _, other := range otherAccountIds {
+		args = append(args, other)
+	}
+
+	var blocks int64
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&blocks); err != nil {
+		return false, fmt.Errorf("could not scan row: %v", err)
+	}
+	return blocks > 0, nil
+}
+
+func (b BlocksRepositoryImpl) InsertReportToDB(ctx context.Context, tx *sql.Tx, report record.ReportRecord) (record.ReportRecord, error) {
+	result, err := tx.ExecContext(ctx, "INSERT INTO reports (reporter_account_id, reported_account_id, reason, details) VALUES (?, ?, ?, ?)",
+		report.ReporterAccountID, report.ReportedAccountID, report.Reason, report.Details)
+	if err != nil {
+		return record.ReportRecord{}, fmt.Errorf("could not insert report: %v", err)
+	}
+
+	reportId, err := result.LastInsertId()
+	if err != nil {
+		return record.ReportRecord{}, fmt.Errorf("could not retrieve last insert id: %v", err)
+	}
+
+	var res record.ReportRecord
+	err = tx.QueryRowContext(ctx, "SELECT "+reportColumns+" FROM reports WHERE report_id = ?", reportId).Scan(
+		&res.ReportID, &res.ReporterAccountID, &res.ReportedAccountID, &res.Reason, &res.Details, &res.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return record.ReportRecord{}, err
+		}
+		return record.ReportRecord{}, fmt.Errorf("could not scan row: %v", err)
+	}
+	return res, nil
+}
+
+// FindReportsFromDB pages through the reports newest first, reportedAccountId 0 returns the reports of every account
+func (b BlocksRepositoryImpl) FindReportsFromDB(ctx context.Context, tx *sql.Tx, reportedAccountId int64, limit int, offset int) ([]record.ReportRecord, error) {
+	query := "SELECT " + reportColumns + " FROM reports WHERE (? = 0 OR reported_account_id = ?) ORDER BY report_id DESC LIMIT ? OFFSET ?"
+	rows, err := tx.QueryContext(ctx, query, reportedAccountId, reportedAccountId, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var reports []record.ReportRecord
+	for rows.Next() {
+		var report record.ReportRecord
+		if err := rows.Scan(&report.ReportID, &report.ReporterAccountID, &report.ReportedAccountID, &report.Reason, &report.Details, &report.CreatedAt); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		reports = append(reports, report)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return reports, nil
+}
+
+// StreamReportsFromDB hands the reports to fn one row at a time as the driver reads them, newest first, so an export
+// never holds the whole result. An error of fn stops the query and is returned as it is
+func (b BlocksRepositoryImpl) StreamReportsFromDB(ctx context.Context, tx *sql.Tx, reportedAccountId int64, fn func(record.ReportRecord) error) error {
+	query := "SELECT " + reportColumns + " FROM reports WHERE (? = 0 OR reported_account_id = ?) ORDER BY report_id DESC"
+	rows, err := tx.QueryContext(ctx, query, reportedAccountId, reportedAccountId)
+	if err != nil {
+		return fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var report record.ReportRecord
+		if err := rows.Scan(&report.ReportID, &report.ReporterAccountID, &report.ReportedAccountID, &report.Reason, &report.Details, &report.CreatedAt); err != nil {
+			return fmt.Errorf("could not scan row: %v", err)
+		}
+		if err := fn(report); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("row iteration error: %v", err)
+	}
+	return nil
+}
+
+func (b BlocksRepositoryImpl) CountReportsFromDB(ctx context.Context, tx *sql.Tx, reportedAccountId int64) (int64, error) {
+	var total int64
+	err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM reports WHERE (? = 0 OR reported_account_id = ?)", reportedAccountId, reportedAccountId).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("could not count reports: %v", err)
+	}
+	return total, nil
+}
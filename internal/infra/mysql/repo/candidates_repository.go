@@ -0,0 +1,12 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+type CandidatesRepository interface {
+	FindCandidatesFromDB(ctx context.Context, tx *sql.Tx, query record.CandidateQuery, limit int, offset int) ([]record.CandidateRecord, error)
+	CountCandidatesFromDB(ctx context.Context, tx *sql.Tx, query record.CandidateQuery) (int64, error)
+}
@@ -0,0 +1,94 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/queries"
+	"godating-dealls/internal/infra/mysql/record"
+	"strings"
+)
+
+type CandidatesRepositoryImpl struct {
+	CandidatesRepository CandidatesRepository
+}
+
+func NewCandidatesRepositoryImpl() CandidatesRepository {
+	return &CandidatesRepositoryImpl{}
+}
+
+// FindCandidatesFromDB pages through the candidates that super liked the account first, then best score first.
+// The account id breaks ties so pages do not overlap
+func (c CandidatesRepositoryImpl) FindCandidatesFromDB(ctx context.Context, tx *sql.Tx, query record.CandidateQuery, limit int, offset int) ([]record.CandidateRecord, error) {
+	filter, filterArgs := candidateFilter(query)
+	statement := "SELECT c.*, ? * c.activity_score + ? * c.completeness_score AS score FROM (" + queries.FindCandidatesRecord + filter +
+		") c ORDER BY c.super_liked DESC, score DESC, c.account_id DESC LIMIT ? OFFSET ?"
+
+	args := append([]interface{}{query.ActivityWeight, query.CompletenessWeight}, filterArgs...)
+	args = append(args, limit, offset)
+	rows, err := tx.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var candidates []record.CandidateRecord
+	for rows.Next() {
+		var candidate record.CandidateRecord
+		if err := rows.Scan(
+			&candidate.AccountID,
+			&candidate.UserID,
+			&candidate.Verified,
+			&candidate.Username,
+			&candidate.FullName,
+			&candidate.Gender,
+			&candidate.Bio,
+			&candidate.Age,
+			&candidate.Address,
+			&candidate.DistanceKm,
+			&candidate.ActivityScore,
+			&candidate.CompletenessScore,
+			&candidate.SuperLiked,
+			&candidate.Score,
+		); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return candidates, nil
+}
+
+func (c CandidatesRepositoryImpl) CountCandidatesFromDB(ctx context.Context, tx *sql.Tx, query record.CandidateQuery) (int64, error) {
+	filter, args := candidateFilter(query)
+
+	var total int64
+	err := tx.QueryRowContext(ctx, queries.CountCandidatesRecord+filter, args...).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("could not count candidates: %v", err)
+	}
+	return total, nil
+}
+
+// candidateFilter returns the gender filter and the arguments of the candidates query in placeholder order
+func candidateFilter(query record.CandidateQuery) (string, []interface{}) {
+	args := []interface{}{
+		// viewer location join, the viewer itself, swiped, contacts, hidden and blocked either way
+		query.AccountID, query.AccountID, query.AccountID, query.AccountID, query.AccountID, query.AccountID, query.AccountID,
+		query.MaxDistanceKm, query.MinAge, query.MaxAge,
+	}
+	if len(query.Genders) == 0 {
+		return "", args
+	}
+
+	placeholders := make([]string, 0, len(query.Genders))
+	for _, gender := range query.Genders {
+		placeholders = append(placeholders, "?")
+		args = append(args, gender)
+	}
+	return " AND UPPER(u.gender) IN (" + strings.Join(placeholders, ", ") + ")", args
+}
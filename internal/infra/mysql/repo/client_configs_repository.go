@@ -0,0 +1,15 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+type ClientConfigsRepository interface {
+	InsertClientConfigBundleToDB(ctx context.Context, tx *sql.Tx, bundle record.ClientConfigBundleRecord) (record.ClientConfigBundleRecord, error)
+	FindClientConfigBundlesFromDB(ctx context.Context, tx *sql.Tx) ([]record.ClientConfigBundleRecord, error)
+	FindRolledOutClientConfigBundlesFromDB(ctx context.Context, tx *sql.Tx) ([]record.ClientConfigBundleRecord, error)
+	FindClientConfigBundleByIdFromDB(ctx context.Context, tx *sql.Tx, bundleId int64) (record.ClientConfigBundleRecord, error)
+	UpdateClientConfigRolloutToDB(ctx context.Context, tx *sql.Tx, bundle record.ClientConfigBundleRecord) error
+}
@@ -0,0 +1,121 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+const clientConfigBundleColumns = "bundle_id, name, version, min_app_version, max_app_version, rollout_percent, payload, created_at, updated_at"
+
+type ClientConfigsRepositoryImpl struct {
+	ClientConfigsRepository ClientConfigsRepository
+}
+
+func NewClientConfigsRepositoryImpl() ClientConfigsRepository {
+	return &ClientConfigsRepositoryImpl{}
+}
+
+// InsertClientConfigBundleToDB stores the bundle as the next version of its name, two admins publishing
+// the same name at once collide on the unique (name, version) key
+func (c ClientConfigsRepositoryImpl) InsertClientConfigBundleToDB(ctx context.Context, tx *sql.Tx, bundle record.ClientConfigBundleRecord) (record.ClientConfigBundleRecord, error) {
+	query := `INSERT INTO client_config_bundles (name, version, min_app_version, max_app_version, rollout_percent, payload)
+		SELECT ?, COALESCE(MAX(version), 0) + 1, ?, ?, ?, ? FROM client_config_bundles WHERE name = ?`
+
+	result, err := tx.ExecContext(ctx, query,
+		bundle.Name,
+		bundle.MinAppVersion,
+		bundle.MaxAppVersion,
+		bundle.RolloutPercent,
+		bundle.Payload,
+		bundle.Name,
+	)
+	if err != nil {
+		var duplicate *DuplicateKeyError
+		if errors.As(asDuplicateKeyError(err), &duplicate) {
+			return record.ClientConfigBundleRecord{}, duplicate
+		}
+		return record.ClientConfigBundleRecord{}, fmt.Errorf("could not insert client config bundle: %v", err)
+	}
+
+	bundleId, err := result.LastInsertId()
+	if err != nil {
+		return record.ClientConfigBundleRecord{}, fmt.Errorf("could not retrieve last insert id: %v", err)
+	}
+	return c.FindClientConfigBundleByIdFromDB(ctx, tx, bundleId)
+}
+
+func (c ClientConfigsRepositoryImpl) FindClientConfigBundlesFromDB(ctx context.Context, tx *sql.Tx) ([]record.ClientConfigBundleRecord, error) {
+	query := "SELECT " + clientConfigBundleColumns + " FROM client_config_bundles ORDER BY name, version DESC"
+	return queryClientConfigBundles(ctx, tx, query)
+}
+
+// FindRolledOutClientConfigBundlesFromDB leaves out versions with a zero rollout, newest version first per name
+func (c ClientConfigsRepositoryImpl) FindRolledOutClientConfigBundlesFromDB(ctx context.Context, tx *sql.Tx) ([]record.ClientConfigBundleRecord, error) {
+	query := "SELECT " + clientConfigBundleColumns + " FROM client_config_bundles WHERE rollout_percent > 0 ORDER BY name, version DESC"
+	return queryClientConfigBundles(ctx, tx, query)
+}
+
+func (c ClientConfigsRepositoryImpl) FindClientConfigBundleByIdFromDB(ctx context.Context, tx *sql.Tx, bundleId int64) (record.ClientConfigBundleRecord, error) {
+	query := "SELECT " + clientConfigBundleColumns + " FROM client_config_bundles WHERE bundle_id = ?"
+	bundle, err := scanClientConfigBundle(tx.QueryRowContext(ctx, query, bundleId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return record.ClientConfigBundleRecord{}, err
+		}
+		return record.ClientConfigBundleRecord{}, fmt.Errorf("could not scan row: %v", err)
+	}
+	return bundle, nil
+}
+
+func (c ClientConfigsRepositoryImpl) UpdateClientConfigRolloutToDB(ctx context.Context, tx *sql.Tx, bundle record.ClientConfigBundleRecord) error {
+	query := `UPDATE client_config_bundles SET min_app_version = ?, max_app_version = ?, rollout_percent = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE bundle_id = ?`
+
+	_, err := tx.ExecContext(ctx, query, bundle.MinAppVersion, bundle.MaxAppVersion, bundle.RolloutPercent, bundle.BundleID)
+	if err != nil {
+		return fmt.Errorf("could not update client config rollout: %v", err)
+	}
+	return nil
+}
+
+func queryClientConfigBundles(ctx context.Context, tx *sql.Tx, query string) ([]record.ClientConfigBundleRecord, error) {
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var bundles []record.ClientConfigBundleRecord
+	for rows.Next() {
+		bundle, err := scanClientConfigBundle(rows)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		bundles = append(bundles, bundle)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return bundles, nil
+}
+
+func scanClientConfigBundle(row interface{ Scan(dest ...any) error }) (record.ClientConfigBundleRecord, error) {
+	var bundle record.ClientConfigBundleRecord
+	err := row.Scan(
+		&bundle.BundleID,
+		&bundle.Name,
+		&bundle.Version,
+		&bundle.MinAppVersion,
+		&bundle.MaxAppVersion,
+		&bundle.RolloutPercent,
+		&bundle.Payload,
+		&bundle.CreatedAt,
+		&bundle.UpdatedAt,
+	)
+	return bundle, err
+}
@@ -0,0 +1,14 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+type ContactExclusionsRepository interface {
+	InsertContactHashesToDB(ctx context.Context, tx *sql.Tx, accountId int64, hashes []string) (int64, error)
+	FindContactHashesByAccountIdFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.ContactExclusionRecord, error)
+	DeleteContactHashFromDB(ctx context.Context, tx *sql.Tx, accountId int64, hash string) (int64, error)
+	DeleteAllContactHashesFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (int64, error)
+}
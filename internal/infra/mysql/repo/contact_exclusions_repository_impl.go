@@ -0,0 +1,87 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/record"
+	"strings"
+)
+
+type ContactExclusionsRepositoryImpl struct {
+	ContactExclusionsRepository ContactExclusionsRepository
+}
+
+func NewContactExclusionsRepositoryImpl() ContactExclusionsRepository {
+	return &ContactExclusionsRepositoryImpl{}
+}
+
+// InsertContactHashesToDB inserts all hashes in one statement, hashes already stored for the account are ignored
+func (c ContactExclusionsRepositoryImpl) InsertContactHashesToDB(ctx context.Context, tx *sql.Tx, accountId int64, hashes []string) (int64, error) {
+	if len(hashes) == 0 {
+		return 0, nil
+	}
+
+	placeholders := make([]string, 0, len(hashes))
+	args := make([]interface{}, 0, len(hashes)*2)
+	for _, hash := range hashes {
+		placeholders = append(placeholders, "(?, ?)")
+		args = append(args, accountId, hash)
+	}
+
+	query := "INSERT IGNORE INTO contact_exclusions (account_id, contact_hash) VALUES " + strings.Join(placeholders, ", ")
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("could not insert contact hashes: %v", err)
+	}
+
+	return result.RowsAffected()
+}
+
+func (c ContactExclusionsRepositoryImpl) FindContactHashesByAccountIdFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.ContactExclusionRecord, error) {
+	query := "SELECT exclusion_id, account_id, contact_hash, created_at FROM contact_exclusions WHERE account_id = ? ORDER BY exclusion_id"
+	rows, err := tx.QueryContext(ctx, query, accountId)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var exclusions []record.ContactExclusionRecord
+	for rows.Next() {
+		var exclusion record.ContactExclusionRecord
+		if err := rows.Scan(
+			&exclusion.ExclusionID,
+			&exclusion.AccountID,
+			&exclusion.ContactHash,
+			&exclusion.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		exclusions = append(exclusions, exclusion)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return exclusions, nil
+}
+
+func (c ContactExclusionsRepositoryImpl) DeleteContactHashFromDB(ctx context.Context, tx *sql.Tx, accountId int64, hash string) (int64, error) {
+	query := "DELETE FROM contact_exclusions WHERE account_id = ? AND contact_hash = ?"
+	result, err := tx.ExecContext(ctx, query, accountId, hash)
+	if err != nil {
+		return 0, errors.New("error while executing delete contact_exclusions")
+	}
+	return result.RowsAffected()
+}
+
+func (c ContactExclusionsRepositoryImpl) DeleteAllContactHashesFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (int64, error) {
+	query := "DELETE FROM contact_exclusions WHERE account_id = ?"
+	result, err := tx.ExecContext(ctx, query, accountId)
+	if err != nil {
+		return 0, errors.New("error while executing delete contact_exclusions")
+	}
+	return result.RowsAffected()
+}
@@ -12,5 +12,9 @@ type DailyQuotasRepository interface {
 	UpdateIncreaseSwipeCount(ctx context.Context, tx *sql.Tx, dailyQuota record.DailyQuotaRecord) error
 	UpdateDecreaseTotalCount(ctx context.Context, tx *sql.Tx, dailyQuota record.DailyQuotaRecord) error
 	UpdateTotalQuotaInPremiumAccount(ctx context.Context, tx *sql.Tx, dailyQuota record.DailyQuotaRecord) error
+	UpdateTotalQuotaOnPremiumLapse(ctx context.Context, tx *sql.Tx, dailyQuota record.DailyQuotaRecord) error
 	FindTotalQuotaByAccountId(ctx context.Context, tx *sql.Tx, accountId int64) (record.DailyQuotaRecord, error)
+	UpdateIncreaseTotalQuota(ctx context.Context, tx *sql.Tx, dailyQuota record.DailyQuotaRecord) error
+	UpdateRestoreSwipeQuota(ctx context.Context, tx *sql.Tx, dailyQuota record.DailyQuotaRecord, quotaUsed bool) error
+	UpdateIncreaseSuperLikeCount(ctx context.Context, tx *sql.Tx, dailyQuota record.DailyQuotaRecord, limit int) (bool, error)
 }
@@ -4,10 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"godating-dealls/internal/common"
 	"godating-dealls/internal/infra/mysql/queries"
 	"godating-dealls/internal/infra/mysql/record"
-	"log"
 )
 
 type DailyQuotasRepositoryImpl struct {
@@ -20,13 +20,13 @@ func NewDailyQuotasRepositoryImpl() DailyQuotasRepository {
 
 func (d DailyQuotasRepositoryImpl) UpdateOrInsertDailyQuota(ctx context.Context, tx *sql.Tx, dailyQuota record.DailyQuotaRecord) error {
 	query := queries.InsertIntoDailyQuotaRecord
-	log.Printf("query: %s", query)
+	common.RepoLog.Debugf("query: %s", query)
 	_, err := tx.ExecContext(ctx, query, dailyQuota.AccountID, dailyQuota.SwipeCount, dailyQuota.TotalQuota)
 	return err
 }
 
 func (d DailyQuotasRepositoryImpl) FindDailyQuotasByUserId(ctx context.Context, tx *sql.Tx, accountId int64) (record.DailyQuotaRecord, error) {
-	query := "SELECT d.quota_id, d.account_id, d.swipe_count, d.total_quota, d.date FROM daily_quotas d INNER JOIN accounts a ON d.account_id = a.account_id WHERE d.account_id = ? AND a.verified = FALSE"
+	query := "SELECT d.quota_id, d.account_id, d.swipe_count, d.total_quota, d.date FROM daily_quotas d INNER JOIN accounts a ON d.account_id = a.account_id WHERE d.account_id = ? AND d.date = CURDATE()"
 	row := tx.QueryRowContext(ctx, query, accountId)
 
 	var records record.DailyQuotaRecord
@@ -55,7 +55,8 @@ func (d DailyQuotasRepositoryImpl) UpdateIncreaseSwipeCount(ctx context.Context,
 }
 
 func (d DailyQuotasRepositoryImpl) UpdateDecreaseTotalCount(ctx context.Context, tx *sql.Tx, dailyQuota record.DailyQuotaRecord) error {
-	query := "UPDATE daily_quotas SET total_quota  = total_quota  - 1 WHERE account_id = ? AND date = CURDATE()"
+	// An unlimited quota is -1 and never counts down
+	query := "UPDATE daily_quotas SET total_quota  = total_quota  - 1 WHERE account_id = ? AND date = CURDATE() AND total_quota > 0"
 	common.PrintJSON("printed query", query)
 	_, err := tx.ExecContext(ctx, query, dailyQuota.AccountID)
 	return err
@@ -68,8 +69,18 @@ func (d DailyQuotasRepositoryImpl) UpdateTotalQuotaInPremiumAccount(ctx context.
 	return err
 }
 
+// UpdateTotalQuotaOnPremiumLapse gives an unlimited quota of today back its limit, less the swipes already made
+func (d DailyQuotasRepositoryImpl) UpdateTotalQuotaOnPremiumLapse(ctx context.Context, tx *sql.Tx, dailyQuota record.DailyQuotaRecord) error {
+	query := "UPDATE daily_quotas SET total_quota = GREATEST(? - swipe_count, 0) WHERE account_id = ? AND date = CURDATE() AND total_quota = -1"
+	_, err := tx.ExecContext(ctx, query, dailyQuota.TotalQuota, dailyQuota.AccountID)
+	if err != nil {
+		return fmt.Errorf("could not update total quota on premium lapse: %v", err)
+	}
+	return nil
+}
+
 func (d DailyQuotasRepositoryImpl) FindTotalQuotaByAccountId(ctx context.Context, tx *sql.Tx, accountId int64) (record.DailyQuotaRecord, error) {
-	query := "SELECT d.quota_id, d.account_id, d.swipe_count, d.total_quota, d.date FROM daily_quotas d INNER JOIN accounts a ON d.account_id = a.account_id WHERE d.account_id = ?"
+	query := "SELECT d.quota_id, d.account_id, d.swipe_count, d.total_quota, d.date FROM daily_quotas d INNER JOIN accounts a ON d.account_id = a.account_id WHERE d.account_id = ? AND d.date = CURDATE()"
 	row := tx.QueryRowContext(ctx, query, accountId)
 
 	var records record.DailyQuotaRecord
@@ -89,3 +100,45 @@ func (d DailyQuotasRepositoryImpl) FindTotalQuotaByAccountId(ctx context.Context
 	}
 	return records, nil
 }
+
+// UpdateIncreaseTotalQuota adds to today's quota, unlimited quota (-1) is left untouched
+func (d DailyQuotasRepositoryImpl) UpdateIncreaseTotalQuota(ctx context.Context, tx *sql.Tx, dailyQuota record.DailyQuotaRecord) error {
+	query := "UPDATE daily_quotas SET total_quota = total_quota + ? WHERE account_id = ? AND date = CURDATE() AND total_quota >= 0"
+	result, err := tx.ExecContext(ctx, query, dailyQuota.TotalQuota, dailyQuota.AccountID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("daily quota not found")
+	}
+	return nil
+}
+
+// UpdateRestoreSwipeQuota takes back a swipe on the quota of its day, the quota unit is only given back when the swipe
+// used one and the quota is not unlimited (-1)
+func (d DailyQuotasRepositoryImpl) UpdateRestoreSwipeQuota(ctx context.Context, tx *sql.Tx, dailyQuota record.DailyQuotaRecord, quotaUsed bool) error {
+	query := "UPDATE daily_quotas SET swipe_count = GREATEST(swipe_count - 1, 0), total_quota = IF(? AND total_quota >= 0, total_quota + 1, total_quota) WHERE account_id = ? AND date = ?"
+	_, err := tx.ExecContext(ctx, query, quotaUsed, dailyQuota.AccountID, dailyQuota.Date.Format("2006-01-02"))
+	return err
+}
+
+// UpdateIncreaseSuperLikeCount takes a super like from today's quota, false means the limit is reached or the
+// account has no quota today. The check and the increase are one statement so two super likes cannot both pass
+func (d DailyQuotasRepositoryImpl) UpdateIncreaseSuperLikeCount(ctx context.Context, tx *sql.Tx, dailyQuota record.DailyQuotaRecord, limit int) (bool, error) {
+	query := "UPDATE daily_quotas SET super_like_count = super_like_count + 1 WHERE account_id = ? AND date = CURDATE() AND super_like_count < ?"
+	result, err := tx.ExecContext(ctx, query, dailyQuota.AccountID, limit)
+	if err != nil {
+		return false, fmt.Errorf("could not increase super like count: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("could not read rows affected: %v", err)
+	}
+	return rowsAffected > 0, nil
+}
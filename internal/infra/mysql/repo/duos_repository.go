@@ -0,0 +1,20 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+type DuosRepository interface {
+	LockDuoAccountsFromDB(ctx context.Context, tx *sql.Tx, accountId int64, partnerAccountId int64) error
+	InsertDuoToDB(ctx context.Context, tx *sql.Tx, duo record.DuoRecord) (record.DuoRecord, error)
+	FindDuoByIdFromDB(ctx context.Context, tx *sql.Tx, duoId int64) (record.DuoRecord, error)
+	FindCurrentDuoFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (record.DuoRecord, error)
+	UpdateDuoStateToDB(ctx context.Context, tx *sql.Tx, duoId int64, state string) error
+	LockDuoPairFromDB(ctx context.Context, tx *sql.Tx, duoId int64, otherDuoId int64) error
+	UpsertDuoSwipeToDB(ctx context.Context, tx *sql.Tx, swipe record.DuoSwipeRecord) error
+	FindDuoLikedFromDB(ctx context.Context, tx *sql.Tx, duoId int64, likedDuoId int64) (bool, error)
+	FindDuoCandidatesFromDB(ctx context.Context, tx *sql.Tx, duo record.DuoRecord, limit int) ([]record.DuoMemberRecord, error)
+	FindDuoMatchesFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.DuoMatchMemberRecord, error)
+}
@@ -0,0 +1,179 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+const duoColumns = "duo_id, account_id_low, account_id_high, invited_by_account_id, state, created_at, linked_at, unlinked_at"
+
+type DuosRepositoryImpl struct {
+	DuosRepository DuosRepository
+}
+
+func NewDuosRepositoryImpl() DuosRepository {
+	return &DuosRepositoryImpl{}
+}
+
+// LockDuoAccountsFromDB locks both account rows in id order, so two invites or accepts involving the same
+// account are serialized and an account never ends up in two duos
+func (d DuosRepositoryImpl) LockDuoAccountsFromDB(ctx context.Context, tx *sql.Tx, accountId int64, partnerAccountId int64) error {
+	rows, err := tx.QueryContext(ctx, "SELECT account_id FROM accounts WHERE account_id IN (?, ?) ORDER BY account_id FOR UPDATE", accountId, partnerAccountId)
+	if err != nil {
+		return fmt.Errorf("could not lock duo accounts: %v", err)
+	}
+	return rows.Close()
+}
+
+func (d DuosRepositoryImpl) InsertDuoToDB(ctx context.Context, tx *sql.Tx, duo record.DuoRecord) (record.DuoRecord, error) {
+	query := "INSERT INTO duos (account_id_low, account_id_high, invited_by_account_id, state) VALUES (?, ?, ?, ?)"
+	result, err := tx.ExecContext(ctx, query, duo.AccountIDLow, duo.AccountIDHigh, duo.InvitedByAccountID, duo.State)
+	if err != nil {
+		return record.DuoRecord{}, fmt.Errorf("could not insert duo: %v", err)
+	}
+
+	duoId, err := result.LastInsertId()
+	if err != nil {
+		return record.DuoRecord{}, fmt.Errorf("could not retrieve last insert id: %v", err)
+	}
+	return d.FindDuoByIdFromDB(ctx, tx, duoId)
+}
+
+func (d DuosRepositoryImpl) FindDuoByIdFromDB(ctx context.Context, tx *sql.Tx, duoId int64) (record.DuoRecord, error) {
+	query := "SELECT " + duoColumns + " FROM duos WHERE duo_id = ?"
+	return scanDuo(tx.QueryRowContext(ctx, query, duoId))
+}
+
+// FindCurrentDuoFromDB returns the pending or active duo of the account
+func (d DuosRepositoryImpl) FindCurrentDuoFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (record.DuoRecord, error) {
+	query := "SELECT " + duoColumns + " FROM duos WHERE (account_id_low = ? OR account_id_high = ?) AND state IN ('pending', 'active') ORDER BY duo_id DESC LIMIT 1"
+	return scanDuo(tx.QueryRowContext(ctx, query, accountId, accountId))
+}
+
+func scanDuo(row *sql.Row) (record.DuoRecord, error) {
+	var duo record.DuoRecord
+	err := row.Scan(&duo.DuoID, &duo.AccountIDLow, &duo.AccountIDHigh, &duo.InvitedByAccountID, &duo.State, &duo.CreatedAt, &duo.LinkedAt, &duo.UnlinkedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return record.DuoRecord{}, err
+		}
+		return record.DuoRecord{}, fmt.Errorf("could not scan row: %v", err)
+	}
+	return duo, nil
+}
+
+// UpdateDuoStateToDB stamps linked_at when the duo becomes active and unlinked_at when it is unlinked
+func (d DuosRepositoryImpl) UpdateDuoStateToDB(ctx context.Context, tx *sql.Tx, duoId int64, state string) error {
+	query := `UPDATE duos SET state = ?,
+		linked_at = IF(? = 'active', CURRENT_TIMESTAMP, linked_at),
+		unlinked_at = IF(? = 'unlinked', CURRENT_TIMESTAMP, unlinked_at)
+		WHERE duo_id = ?`
+	_, err := tx.ExecContext(ctx, query, state, state, state, duoId)
+	if err != nil {
+		return fmt.Errorf("could not update duo state: %v", err)
+	}
+	return nil
+}
+
+// LockDuoPairFromDB locks both duo rows in id order, the duo counterpart of LockMatchPairFromDB
+func (d DuosRepositoryImpl) LockDuoPairFromDB(ctx context.Context, tx *sql.Tx, duoId int64, otherDuoId int64) error {
+	rows, err := tx.QueryContext(ctx, "SELECT duo_id FROM duos WHERE duo_id IN (?, ?) ORDER BY duo_id FOR UPDATE", duoId, otherDuoId)
+	if err != nil {
+		return fmt.Errorf("could not lock duo pair: %v", err)
+	}
+	return rows.Close()
+}
+
+// UpsertDuoSwipeToDB keeps one swipe per pair of duos, the latest swipe of either member wins
+func (d DuosRepositoryImpl) UpsertDuoSwipeToDB(ctx context.Context, tx *sql.Tx, swipe record.DuoSwipeRecord) error {
+	query := `INSERT INTO duo_swipes (duo_id, target_duo_id, swiped_by_account_id, action) VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE swiped_by_account_id = VALUES(swiped_by_account_id), action = VALUES(action), created_at = CURRENT_TIMESTAMP`
+	_, err := tx.ExecContext(ctx, query, swipe.DuoID, swipe.TargetDuoID, swipe.SwipedByAccountID, swipe.Action)
+	if err != nil {
+		return fmt.Errorf("could not upsert duo swipe: %v", err)
+	}
+	return nil
+}
+
+// FindDuoLikedFromDB is a locking read so it sees a like committed after this transaction started
+func (d DuosRepositoryImpl) FindDuoLikedFromDB(ctx context.Context, tx *sql.Tx, duoId int64, likedDuoId int64) (bool, error) {
+	query := "SELECT COUNT(*) FROM duo_swipes WHERE duo_id = ? AND target_duo_id = ? AND action = 'LIKED' FOR SHARE"
+	var likes int64
+	if err := tx.QueryRowContext(ctx, query, duoId, likedDuoId).Scan(&likes); err != nil {
+		return false, fmt.Errorf("could not scan row: %v", err)
+	}
+	return likes > 0, nil
+}
+
+// FindDuoCandidatesFromDB picks random active duos the duo did not swipe yet, one row per member.
+// Duos with a hidden or purged member, or with a member hidden by or blocked either way with an account of the duo, are left out
+func (d DuosRepositoryImpl) FindDuoCandidatesFromDB(ctx context.Context, tx *sql.Tx, duo record.DuoRecord, limit int) ([]record.DuoMemberRecord, error) {
+	query := `SELECT c.duo_id, u.account_id, u.full_name, u.age, u.gender, u.bio
+		FROM (SELECT cd.duo_id, cd.account_id_low, cd.account_id_high FROM duos cd
+			WHERE cd.state = 'active' AND cd.duo_id != ?
+				AND cd.duo_id NOT IN (SELECT ds.target_duo_id FROM duo_swipes ds WHERE ds.duo_id = ?)
+				AND NOT EXISTS (SELECT 1 FROM account_dormancy ad WHERE ad.account_id IN (cd.account_id_low, cd.account_id_high) AND ad.state IN ('hidden', 'deleted', 'purged'))
+				AND NOT EXISTS (SELECT 1 FROM hidden_accounts ha WHERE ha.account_id IN (?, ?) AND ha.hidden_account_id IN (cd.account_id_low, cd.account_id_high))
+				AND NOT EXISTS (SELECT 1 FROM blocks b WHERE (b.account_id IN (?, ?) AND b.blocked_account_id IN (cd.account_id_low, cd.account_id_high))
+					OR (b.blocked_account_id IN (?, ?) AND b.account_id IN (cd.account_id_low, cd.account_id_high)))
+			ORDER BY RAND() LIMIT ?) c
+		INNER JOIN users u ON u.account_id IN (c.account_id_low, c.account_id_high)
+		ORDER BY c.duo_id, u.account_id`
+
+	rows, err := tx.QueryContext(ctx, query, duo.DuoID, duo.DuoID, duo.AccountIDLow, duo.AccountIDHigh,
+		duo.AccountIDLow, duo.AccountIDHigh, duo.AccountIDLow, duo.AccountIDHigh, limit)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var members []record.DuoMemberRecord
+	for rows.Next() {
+		var member record.DuoMemberRecord
+		if err := rows.Scan(&member.DuoID, &member.AccountID, &member.FullName, &member.Age, &member.Gender, &member.Bio); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		members = append(members, member)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return members, nil
+}
+
+// FindDuoMatchesFromDB lists the duo matches of the account newest first, one row per other participant
+func (d DuosRepositoryImpl) FindDuoMatchesFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.DuoMatchMemberRecord, error) {
+	query := `SELECT m.match_id, u.account_id, u.full_name, u.age, u.gender, u.bio, m.created_at
+		FROM match_participants self
+		INNER JOIN matches m ON m.match_id = self.match_id
+		INNER JOIN match_participants mp ON mp.match_id = m.match_id AND mp.account_id != self.account_id
+		INNER JOIN users u ON u.account_id = mp.account_id
+		WHERE self.account_id = ?
+		ORDER BY m.created_at DESC, m.match_id DESC, u.account_id`
+
+	rows, err := tx.QueryContext(ctx, query, accountId)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var members []record.DuoMatchMemberRecord
+	for rows.Next() {
+		var member record.DuoMatchMemberRecord
+		if err := rows.Scan(&member.MatchID, &member.AccountID, &member.FullName, &member.Age, &member.Gender, &member.Bio, &member.CreatedAt); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		members = append(members, member)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return members, nil
+}
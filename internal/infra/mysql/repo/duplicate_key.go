@@ -0,0 +1,36 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-sql-driver/mysql"
+	"regexp"
+)
+
+// mysqlDuplicateEntry is the server error number for a unique constraint violation
+const mysqlDuplicateEntry = 1062
+
+var duplicateKeyPattern = regexp.MustCompile(`for key '([^']+)'`)
+
+// DuplicateKeyError reports the unique key an insert or update collided with, e.g. "accounts.email"
+type DuplicateKeyError struct {
+	Key string
+}
+
+func (e *DuplicateKeyError) Error() string {
+	return fmt.Sprintf("duplicate entry for key %s", e.Key)
+}
+
+// asDuplicateKeyError turns a unique constraint violation into a DuplicateKeyError, other errors are returned as is
+func asDuplicateKeyError(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) || mysqlErr.Number != mysqlDuplicateEntry {
+		return err
+	}
+
+	key := ""
+	if match := duplicateKeyPattern.FindStringSubmatch(mysqlErr.Message); match != nil {
+		key = match[1]
+	}
+	return &DuplicateKeyError{Key: key}
+}
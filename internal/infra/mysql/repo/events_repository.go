@@ -0,0 +1,20 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+	"time"
+)
+
+type EventsRepository interface {
+	InsertEventToDB(ctx context.Context, tx *sql.Tx, event record.EventRecord) (record.EventRecord, error)
+	UpdateEventRoomToDB(ctx context.Context, tx *sql.Tx, eventId int64, roomMatchId *int64) error
+	FindEventByIdFromDB(ctx context.Context, tx *sql.Tx, eventId int64) (record.EventRecord, error)
+	LockEventFromDB(ctx context.Context, tx *sql.Tx, eventId int64) error
+	FindEventsFromDB(ctx context.Context, tx *sql.Tx, endsAfter time.Time) ([]record.EventRecord, error)
+	FindEndedEventRoomsFromDB(ctx context.Context, tx *sql.Tx, endedBefore time.Time, limit int) ([]record.EventRecord, error)
+	InsertEventConnectionToDB(ctx context.Context, tx *sql.Tx, connection record.EventConnectionRecord) error
+	FindEventConnectionFromDB(ctx context.Context, tx *sql.Tx, eventId int64, accountId int64, targetAccountId int64) (bool, error)
+	DeleteEventConnectionsFromDB(ctx context.Context, tx *sql.Tx, eventId int64) error
+}
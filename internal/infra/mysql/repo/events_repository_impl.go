@@ -0,0 +1,125 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/record"
+	"time"
+)
+
+const eventColumns = "event_id, title, description, starts_at, ends_at, room_match_id, created_at"
+
+type EventsRepositoryImpl struct {
+	EventsRepository EventsRepository
+}
+
+func NewEventsRepositoryImpl() EventsRepository {
+	return &EventsRepositoryImpl{}
+}
+
+func (e EventsRepositoryImpl) InsertEventToDB(ctx context.Context, tx *sql.Tx, event record.EventRecord) (record.EventRecord, error) {
+	result, err := tx.ExecContext(ctx, "INSERT INTO events (title, description, starts_at, ends_at) VALUES (?, ?, ?, ?)",
+		event.Title, event.Description, event.StartsAt, event.EndsAt)
+	if err != nil {
+		return record.EventRecord{}, fmt.Errorf("could not insert event: %v", err)
+	}
+
+	eventId, err := result.LastInsertId()
+	if err != nil {
+		return record.EventRecord{}, fmt.Errorf("could not retrieve last insert id: %v", err)
+	}
+	return e.FindEventByIdFromDB(ctx, tx, eventId)
+}
+
+// UpdateEventRoomToDB links the room of the event, nil once the room was purged
+func (e EventsRepositoryImpl) UpdateEventRoomToDB(ctx context.Context, tx *sql.Tx, eventId int64, roomMatchId *int64) error {
+	_, err := tx.ExecContext(ctx, "UPDATE events SET room_match_id = ? WHERE event_id = ?", roomMatchId, eventId)
+	if err != nil {
+		return fmt.Errorf("could not update event room: %v", err)
+	}
+	return nil
+}
+
+func (e EventsRepositoryImpl) FindEventByIdFromDB(ctx context.Context, tx *sql.Tx, eventId int64) (record.EventRecord, error) {
+	var event record.EventRecord
+	err := tx.QueryRowContext(ctx, "SELECT "+eventColumns+" FROM events WHERE event_id = ?", eventId).Scan(
+		&event.EventID, &event.Title, &event.Description, &event.StartsAt, &event.EndsAt, &event.RoomMatchID, &event.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return record.EventRecord{}, err
+		}
+		return record.EventRecord{}, fmt.Errorf("could not scan row: %v", err)
+	}
+	return event, nil
+}
+
+// LockEventFromDB keeps concurrent joins of the same event in order, so the room cannot take more guests than it holds
+func (e EventsRepositoryImpl) LockEventFromDB(ctx context.Context, tx *sql.Tx, eventId int64) error {
+	rows, err := tx.QueryContext(ctx, "SELECT event_id FROM events WHERE event_id = ? FOR UPDATE", eventId)
+	if err != nil {
+		return fmt.Errorf("could not lock event: %v", err)
+	}
+	return rows.Close()
+}
+
+// FindEventsFromDB lists the events that did not end yet, the next to start first
+func (e EventsRepositoryImpl) FindEventsFromDB(ctx context.Context, tx *sql.Tx, endsAfter time.Time) ([]record.EventRecord, error) {
+	return e.findEvents(ctx, tx, "SELECT "+eventColumns+" FROM events WHERE ends_at > ? ORDER BY starts_at, event_id", endsAfter)
+}
+
+// FindEndedEventRoomsFromDB returns the events whose room is still there although the event ended
+func (e EventsRepositoryImpl) FindEndedEventRoomsFromDB(ctx context.Context, tx *sql.Tx, endedBefore time.Time, limit int) ([]record.EventRecord, error) {
+	return e.findEvents(ctx, tx, "SELECT "+eventColumns+" FROM events WHERE room_match_id IS NOT NULL AND ends_at <= ? ORDER BY ends_at, event_id LIMIT ?", endedBefore, limit)
+}
+
+func (e EventsRepositoryImpl) findEvents(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]record.EventRecord, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var events []record.EventRecord
+	for rows.Next() {
+		var event record.EventRecord
+		if err := rows.Scan(&event.EventID, &event.Title, &event.Description, &event.StartsAt, &event.EndsAt, &event.RoomMatchID, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return events, nil
+}
+
+// InsertEventConnectionToDB choosing the same guest twice keeps the first time
+func (e EventsRepositoryImpl) InsertEventConnectionToDB(ctx context.Context, tx *sql.Tx, connection record.EventConnectionRecord) error {
+	_, err := tx.ExecContext(ctx, "INSERT IGNORE INTO event_connections (event_id, account_id, target_account_id) VALUES (?, ?, ?)",
+		connection.EventID, connection.AccountID, connection.TargetAccountID)
+	if err != nil {
+		return fmt.Errorf("could not insert event connection: %v", err)
+	}
+	return nil
+}
+
+// FindEventConnectionFromDB is a locking read so it sees a connection committed after this transaction started
+func (e EventsRepositoryImpl) FindEventConnectionFromDB(ctx context.Context, tx *sql.Tx, eventId int64, accountId int64, targetAccountId int64) (bool, error) {
+	query := "SELECT COUNT(*) FROM event_connections WHERE event_id = ? AND account_id = ? AND target_account_id = ? FOR SHARE"
+	var connections int64
+	if err := tx.QueryRowContext(ctx, query, eventId, accountId, targetAccountId).Scan(&connections); err != nil {
+		return false, fmt.Errorf("could not scan row: %v", err)
+	}
+	return connections > 0, nil
+}
+
+func (e EventsRepositoryImpl) DeleteEventConnectionsFromDB(ctx context.Context, tx *sql.Tx, eventId int64) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM event_connections WHERE event_id = ?", eventId); err != nil {
+		return fmt.Errorf("could not delete event connections: %v", err)
+	}
+	return nil
+}
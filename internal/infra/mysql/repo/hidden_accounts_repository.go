@@ -0,0 +1,14 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+type HiddenAccountsRepository interface {
+	InsertHiddenAccountToDB(ctx context.Context, tx *sql.Tx, accountId int64, hiddenAccountId int64) error
+	CountHiddenAccountsFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (int64, error)
+	FindHiddenAccountsFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.HiddenAccountRecord, error)
+	DeleteHiddenAccountFromDB(ctx context.Context, tx *sql.Tx, accountId int64, hiddenAccountId int64) (int64, error)
+}
@@ -0,0 +1,76 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+type HiddenAccountsRepositoryImpl struct {
+	HiddenAccountsRepository HiddenAccountsRepository
+}
+
+func NewHiddenAccountsRepositoryImpl() HiddenAccountsRepository {
+	return &HiddenAccountsRepositoryImpl{}
+}
+
+// InsertHiddenAccountToDB hiding an account twice keeps the first time it was hidden
+func (h HiddenAccountsRepositoryImpl) InsertHiddenAccountToDB(ctx context.Context, tx *sql.Tx, accountId int64, hiddenAccountId int64) error {
+	query := "INSERT IGNORE INTO hidden_accounts (account_id, hidden_account_id) VALUES (?, ?)"
+	_, err := tx.ExecContext(ctx, query, accountId, hiddenAccountId)
+	if err != nil {
+		return fmt.Errorf("could not insert hidden account: %v", err)
+	}
+	return nil
+}
+
+func (h HiddenAccountsRepositoryImpl) CountHiddenAccountsFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (int64, error) {
+	var total int64
+	err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM hidden_accounts WHERE account_id = ?", accountId).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("could not count hidden accounts: %v", err)
+	}
+	return total, nil
+}
+
+// FindHiddenAccountsFromDB returns the most recently hidden first
+func (h HiddenAccountsRepositoryImpl) FindHiddenAccountsFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.HiddenAccountRecord, error) {
+	query := `SELECT ha.account_id, ha.hidden_account_id, a.username, ha.created_at
+		FROM hidden_accounts ha INNER JOIN accounts a ON a.account_id = ha.hidden_account_id
+		WHERE ha.account_id = ? ORDER BY ha.created_at DESC, ha.hidden_account_id`
+
+	rows, err := tx.QueryContext(ctx, query, accountId)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var hidden []record.HiddenAccountRecord
+	for rows.Next() {
+		var rec record.HiddenAccountRecord
+		if err := rows.Scan(&rec.AccountID, &rec.HiddenAccountID, &rec.HiddenUsername, &rec.CreatedAt); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		hidden = append(hidden, rec)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return hidden, nil
+}
+
+func (h HiddenAccountsRepositoryImpl) DeleteHiddenAccountFromDB(ctx context.Context, tx *sql.Tx, accountId int64, hiddenAccountId int64) (int64, error) {
+	result, err := tx.ExecContext(ctx, "DELETE FROM hidden_accounts WHERE account_id = ? AND hidden_account_id = ?", accountId, hiddenAccountId)
+	if err != nil {
+		return 0, fmt.Errorf("could not delete hidden account: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not retrieve rows affected: %v", err)
+	}
+	return rowsAffected, nil
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"godating-dealls/internal/infra/mysql/record"
+	"time"
 )
 
 type LoginHistoriesRepository interface {
 	CreateLoginHistoryDB(ctx context.Context, tx *sql.Tx, record record.LoginHistoriesRecord) (record.LoginHistoriesRecord, error)
 	UpdateLoginHistoryDB(ctx context.Context, tx *sql.Tx, record record.LoginHistoriesRecord) (record.LoginHistoriesRecord, error)
+	FindOldestLoginHistoryDB(ctx context.Context, tx *sql.Tx, before time.Time) (*time.Time, error)
+	SummarizeLoginHistoryDB(ctx context.Context, tx *sql.Tx, from time.Time, to time.Time) (int64, error)
+	DeleteLoginHistoryDB(ctx context.Context, tx *sql.Tx, from time.Time, to time.Time) (int64, error)
 }
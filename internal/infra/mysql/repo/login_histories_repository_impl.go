@@ -75,3 +75,43 @@ func (l LoginRepositoryImpl) UpdateLoginHistoryDB(ctx context.Context, tx *sql.T
 	existingRecord.DurationInSeconds = loginRecord.DurationInSeconds
 	return existingRecord, nil
 }
+
+// FindOldestLoginHistoryDB returns nil when no row is older than before
+func (l LoginRepositoryImpl) FindOldestLoginHistoryDB(ctx context.Context, tx *sql.Tx, before time.Time) (*time.Time, error) {
+	var oldest sql.NullTime
+	err := tx.QueryRowContext(ctx, queries.FindOldestLoginHistoryRecord, before).Scan(&oldest)
+	if err != nil {
+		return nil, fmt.Errorf("could not find oldest login history: %v", err)
+	}
+	if !oldest.Valid {
+		return nil, nil
+	}
+	return &oldest.Time, nil
+}
+
+// SummarizeLoginHistoryDB adds the rows logged in within [from, to) to the monthly summaries, the window must not cross a month
+func (l LoginRepositoryImpl) SummarizeLoginHistoryDB(ctx context.Context, tx *sql.Tx, from time.Time, to time.Time) (int64, error) {
+	result, err := tx.ExecContext(ctx, queries.SummarizeLoginHistoryRecord, from, to)
+	if err != nil {
+		return 0, fmt.Errorf("could not summarize login history: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not retrieve rows affected: %v", err)
+	}
+	return rowsAffected, nil
+}
+
+func (l LoginRepositoryImpl) DeleteLoginHistoryDB(ctx context.Context, tx *sql.Tx, from time.Time, to time.Time) (int64, error) {
+	result, err := tx.ExecContext(ctx, queries.DeleteLoginHistoryRecord, from, to)
+	if err != nil {
+		return 0, fmt.Errorf("could not delete login history: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not retrieve rows affected: %v", err)
+	}
+	return rowsAffected, nil
+}
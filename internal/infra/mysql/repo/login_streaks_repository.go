@@ -0,0 +1,14 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+	"time"
+)
+
+type LoginStreaksRepository interface {
+	FindLoginStreakFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (record.LoginStreakRecord, error)
+	UpsertLoginStreakToDB(ctx context.Context, tx *sql.Tx, record record.LoginStreakRecord) error
+	UpdateClaimedDayToDB(ctx context.Context, tx *sql.Tx, accountId int64, claimedDay time.Time) error
+}
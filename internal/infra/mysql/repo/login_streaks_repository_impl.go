@@ -0,0 +1,67 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/record"
+	"time"
+)
+
+type LoginStreaksRepositoryImpl struct {
+	LoginStreaksRepository LoginStreaksRepository
+}
+
+func NewLoginStreaksRepositoryImpl() LoginStreaksRepository {
+	return &LoginStreaksRepositoryImpl{}
+}
+
+func (l LoginStreaksRepositoryImpl) FindLoginStreakFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (record.LoginStreakRecord, error) {
+	query := "SELECT account_id, timezone, current_streak, longest_streak, last_login_day, last_claimed_day, updated_at FROM login_streaks WHERE account_id = ?"
+
+	var streak record.LoginStreakRecord
+	err := tx.QueryRowContext(ctx, query, accountId).Scan(
+		&streak.AccountID,
+		&streak.Timezone,
+		&streak.CurrentStreak,
+		&streak.LongestStreak,
+		&streak.LastLoginDay,
+		&streak.LastClaimedDay,
+		&streak.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return streak, errors.New("login streak not found")
+		}
+		return streak, fmt.Errorf("could not query login streak: %v", err)
+	}
+	return streak, nil
+}
+
+func (l LoginStreaksRepositoryImpl) UpsertLoginStreakToDB(ctx context.Context, tx *sql.Tx, record record.LoginStreakRecord) error {
+	query := `INSERT INTO login_streaks (account_id, timezone, current_streak, longest_streak, last_login_day)
+		VALUES (?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE timezone = VALUES(timezone), current_streak = VALUES(current_streak),
+			longest_streak = VALUES(longest_streak), last_login_day = VALUES(last_login_day), updated_at = CURRENT_TIMESTAMP`
+	_, err := tx.ExecContext(ctx, query,
+		record.AccountID,
+		record.Timezone,
+		record.CurrentStreak,
+		record.LongestStreak,
+		record.LastLoginDay.Format("2006-01-02"),
+	)
+	if err != nil {
+		return fmt.Errorf("could not save login streak: %v", err)
+	}
+	return nil
+}
+
+func (l LoginStreaksRepositoryImpl) UpdateClaimedDayToDB(ctx context.Context, tx *sql.Tx, accountId int64, claimedDay time.Time) error {
+	query := "UPDATE login_streaks SET last_claimed_day = ?, updated_at = CURRENT_TIMESTAMP WHERE account_id = ?"
+	_, err := tx.ExecContext(ctx, query, claimedDay.Format("2006-01-02"), accountId)
+	if err != nil {
+		return fmt.Errorf("could not update claimed day: %v", err)
+	}
+	return nil
+}
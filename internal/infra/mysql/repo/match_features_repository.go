@@ -0,0 +1,16 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+	"time"
+)
+
+type MatchFeaturesRepository interface {
+	ComputeAccountFeaturesToDB(ctx context.Context, tx *sql.Tx, computedAt time.Time) (int64, error)
+	ComputePairFeaturesToDB(ctx context.Context, tx *sql.Tx, computedAt time.Time) (int64, error)
+	DeleteStaleFeaturesToDB(ctx context.Context, tx *sql.Tx, computedAt time.Time) (int64, error)
+	UpsertMatchScoresToDB(ctx context.Context, tx *sql.Tx, scores []record.MatchScoreRecord) (int64, error)
+	DeleteExpiredMatchScoresToDB(ctx context.Context, tx *sql.Tx, before time.Time) (int64, error)
+}
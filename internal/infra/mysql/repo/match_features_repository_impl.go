@@ -0,0 +1,146 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/record"
+	"strings"
+	"time"
+)
+
+type MatchFeaturesRepositoryImpl struct {
+	MatchFeaturesRepository MatchFeaturesRepository
+}
+
+func NewMatchFeaturesRepositoryImpl() MatchFeaturesRepository {
+	return &MatchFeaturesRepositoryImpl{}
+}
+
+// ComputeAccountFeaturesToDB snapshots every visible account, response rate is the share of likes received
+// that the account answered with a swipe of its own and stays NULL without likes received
+func (m MatchFeaturesRepositoryImpl) ComputeAccountFeaturesToDB(ctx context.Context, tx *sql.Tx, computedAt time.Time) (int64, error) {
+	query := `INSERT INTO account_features (account_id, logins_7d, logins_30d, active_days_30d, likes_sent_30d, passes_sent_30d, likes_received_30d, response_rate, interests, computed_at)
+		SELECT a.account_id,
+			COALESCE(lh.logins_7d, 0),
+			COALESCE(lh.logins_30d, 0),
+			COALESCE(lh.active_days, 0),
+			COALESCE(ss.likes, 0),
+			COALESCE(ss.passes, 0),
+			COALESCE(lr.received, 0),
+			lr.answered / lr.received,
+			COALESCE(pi.interests, 0),
+			?
+		FROM accounts a
+		LEFT JOIN (SELECT account_id, SUM(login_at >= ?) AS logins_7d, COUNT(*) AS logins_30d, COUNT(DISTINCT DATE(login_at)) AS active_days
+			FROM login_histories WHERE login_at >= ? GROUP BY account_id) lh ON lh.account_id = a.account_id
+		LEFT JOIN (SELECT account_id, SUM(action = 'LIKED') AS likes, SUM(action = 'PASSED') AS passes
+			FROM swipes WHERE swipe_date >= ? GROUP BY account_id) ss ON ss.account_id = a.account_id
+		LEFT JOIN (SELECT r.account_id_swipe AS account_id, COUNT(*) AS received,
+				SUM(EXISTS (SELECT 1 FROM swipes b WHERE b.account_id = r.account_id_swipe AND b.account_id_swipe = r.account_id)) AS answered
+			FROM swipes r WHERE r.action = 'LIKED' AND r.swipe_date >= ? GROUP BY r.account_id_swipe) lr ON lr.account_id = a.account_id
+		LEFT JOIN (SELECT account_id, COUNT(DISTINCT content_type, content_value) AS interests
+			FROM profile_imports GROUP BY account_id) pi ON pi.account_id = a.account_id
+		WHERE a.account_id NOT IN (SELECT ad.account_id FROM account_dormancy ad WHERE ad.state IN ('hidden', 'deleted', 'purged'))
+		ON DUPLICATE KEY UPDATE logins_7d = VALUES(logins_7d), logins_30d = VALUES(logins_30d), active_days_30d = VALUES(active_days_30d),
+			likes_sent_30d = VALUES(likes_sent_30d), passes_sent_30d = VALUES(passes_sent_30d), likes_received_30d = VALUES(likes_received_30d),
+			response_rate = VALUES(response_rate), interests = VALUES(interests), computed_at = VALUES(computed_at)`
+
+	weekAgo := computedAt.AddDate(0, 0, -7)
+	monthAgo := computedAt.AddDate(0, 0, -30)
+	result, err := tx.ExecContext(ctx, query, computedAt, weekAgo, monthAgo, monthAgo.Format("2006-01-02"), monthAgo.Format("2006-01-02"))
+	if err != nil {
+		return 0, fmt.Errorf("could not compute account features: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not retrieve rows affected: %v", err)
+	}
+	return rowsAffected, nil
+}
+
+// ComputePairFeaturesToDB counts the imported interests (artists, tracks) two visible accounts have in common
+func (m MatchFeaturesRepositoryImpl) ComputePairFeaturesToDB(ctx context.Context, tx *sql.Tx, computedAt time.Time) (int64, error) {
+	query := `INSERT INTO pair_features (account_id, candidate_account_id, shared_interests, computed_at)
+		SELECT p1.account_id, p2.account_id, COUNT(DISTINCT p1.content_type, p1.content_value), ?
+		FROM profile_imports p1
+		INNER JOIN profile_imports p2 ON p2.content_type = p1.content_type AND p2.content_value = p1.content_value AND p2.account_id != p1.account_id
+		WHERE p1.account_id NOT IN (SELECT ad.account_id FROM account_dormancy ad WHERE ad.state IN ('hidden', 'deleted', 'purged'))
+			AND p2.account_id NOT IN (SELECT ad.account_id FROM account_dormancy ad WHERE ad.state IN ('hidden', 'deleted', 'purged'))
+		GROUP BY p1.account_id, p2.account_id
+		ON DUPLICATE KEY UPDATE shared_interests = VALUES(shared_interests), computed_at = VALUES(computed_at)`
+
+	result, err := tx.ExecContext(ctx, query, computedAt)
+	if err != nil {
+		return 0, fmt.Errorf("could not compute pair features: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not retrieve rows affected: %v", err)
+	}
+	return rowsAffected, nil
+}
+
+// DeleteStaleFeaturesToDB removes rows the last run did not write, accounts that went hidden or pairs that no longer share anything
+func (m MatchFeaturesRepositoryImpl) DeleteStaleFeaturesToDB(ctx context.Context, tx *sql.Tx, computedAt time.Time) (int64, error) {
+	var deleted int64
+	for _, query := range []string{
+		"DELETE FROM account_features WHERE computed_at < ?",
+		"DELETE FROM pair_features WHERE computed_at < ?",
+	} {
+		result, err := tx.ExecContext(ctx, query, computedAt)
+		if err != nil {
+			return 0, fmt.Errorf("could not delete stale features: %v", err)
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("could not retrieve rows affected: %v", err)
+		}
+		deleted += rowsAffected
+	}
+	return deleted, nil
+}
+
+func (m MatchFeaturesRepositoryImpl) UpsertMatchScoresToDB(ctx context.Context, tx *sql.Tx, scores []record.MatchScoreRecord) (int64, error) {
+	if len(scores) == 0 {
+		return 0, nil
+	}
+
+	placeholders := make([]string, 0, len(scores))
+	args := make([]interface{}, 0, len(scores)*4)
+	for _, score := range scores {
+		placeholders = append(placeholders, "(?, ?, ?, ?, CURRENT_TIMESTAMP)")
+		args = append(args, score.AccountID, score.CandidateAccountID, score.Score, score.ModelVersion)
+	}
+
+	query := `INSERT INTO match_scores (account_id, candidate_account_id, score, model_version, scored_at) VALUES ` +
+		strings.Join(placeholders, ", ") +
+		` ON DUPLICATE KEY UPDATE score = VALUES(score), model_version = VALUES(model_version), scored_at = VALUES(scored_at)`
+
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("could not upsert match scores: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not retrieve rows affected: %v", err)
+	}
+	return rowsAffected, nil
+}
+
+// DeleteExpiredMatchScoresToDB drops scores the model did not refresh, ranking ignores them already
+func (m MatchFeaturesRepositoryImpl) DeleteExpiredMatchScoresToDB(ctx context.Context, tx *sql.Tx, before time.Time) (int64, error) {
+	result, err := tx.ExecContext(ctx, "DELETE FROM match_scores WHERE scored_at < ?", before)
+	if err != nil {
+		return 0, fmt.Errorf("could not delete expired match scores: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not retrieve rows affected: %v", err)
+	}
+	return rowsAffected, nil
+}
@@ -0,0 +1,24 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+	"time"
+)
+
+type MatchesRepository interface {
+	LockMatchPairFromDB(ctx context.Context, tx *sql.Tx, accountId int64, otherAccountId int64) error
+	FindLikedFromDB(ctx context.Context, tx *sql.Tx, accountId int64, likedAccountId int64) (bool, error)
+	FindMatchByIdFromDB(ctx context.Context, tx *sql.Tx, matchId int64) (record.MatchRecord, error)
+	InsertMatchToDB(ctx context.Context, tx *sql.Tx, match record.MatchRecord) (record.MatchRecord, bool, error)
+	FindMatchesByAccountFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.MatchViewRecord, error)
+	InsertDuoMatchToDB(ctx context.Context, tx *sql.Tx, match record.MatchRecord, participants []int64) (record.MatchRecord, bool, error)
+	FindMatchParticipantsFromDB(ctx context.Context, tx *sql.Tx, matchId int64) ([]int64, error)
+	InsertGroupMatchToDB(ctx context.Context, tx *sql.Tx, title string, participants []int64, expiresAt *time.Time) (record.MatchRecord, error)
+	InsertMatchParticipantToDB(ctx context.Context, tx *sql.Tx, matchId int64, accountId int64) error
+	UpdateMatchParticipantLeftToDB(ctx context.Context, tx *sql.Tx, matchId int64, accountId int64) (int64, error)
+	UpdateMatchParticipantReadToDB(ctx context.Context, tx *sql.Tx, matchId int64, accountId int64, messageId int64) error
+	FindMatchParticipantDetailsFromDB(ctx context.Context, tx *sql.Tx, matchId int64) ([]record.MatchParticipantRecord, error)
+	DeleteMatchFromDB(ctx context.Context, tx *sql.Tx, matchId int64) error
+}
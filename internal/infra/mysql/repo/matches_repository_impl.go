@@ -0,0 +1,298 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/record"
+	"time"
+)
+
+type MatchesRepositoryImpl struct {
+	MatchesRepository MatchesRepository
+}
+
+func NewMatchesRepositoryImpl() MatchesRepository {
+	return &MatchesRepositoryImpl{}
+}
+
+// LockMatchPairFromDB locks both account rows in id order, so two accounts liking each other at the same time
+// are serialized and the second like sees the first one
+func (m MatchesRepositoryImpl) LockMatchPairFromDB(ctx context.Context, tx *sql.Tx, accountId int64, otherAccountId int64) error {
+	rows, err := tx.QueryContext(ctx, "SELECT account_id FROM accounts WHERE account_id IN (?, ?) ORDER BY account_id FOR UPDATE", accountId, otherAccountId)
+	if err != nil {
+		return fmt.Errorf("could not lock match pair: %v", err)
+	}
+	return rows.Close()
+}
+
+// FindLikedFromDB is a locking read so it sees a like committed after this transaction started.
+// A like between accounts that blocked one another does not count, so they cannot match
+func (m MatchesRepositoryImpl) FindLikedFromDB(ctx context.Context, tx *sql.Tx, accountId int64, likedAccountId int64) (bool, error) {
+	query := `SELECT COUNT(*) FROM swipes s WHERE s.account_id = ? AND s.account_id_swipe = ? AND s.action = 'LIKED'
+		AND NOT EXISTS (SELECT 1 FROM blocks b WHERE (b.account_id = s.account_id AND b.blocked_account_id = s.account_id_swipe)
+			OR (b.account_id = s.account_id_swipe AND b.blocked_account_id = s.account_id)) FOR SHARE`
+	var likes int64
+	if err := tx.QueryRowContext(ctx, query, accountId, likedAccountId).Scan(&likes); err != nil {
+		return false, fmt.Errorf("could not scan row: %v", err)
+	}
+	return likes > 0, nil
+}
+
+// InsertMatchToDB keeps one match per pair and adds both accounts to its conversation,
+// created is false when the pair was already matched
+func (m MatchesRepositoryImpl) InsertMatchToDB(ctx context.Context, tx *sql.Tx, match record.MatchRecord) (record.MatchRecord, bool, error) {
+	query := `INSERT INTO matches (account_id_low, account_id_high) VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE match_id = LAST_INSERT_ID(match_id)`
+
+	result, err := tx.ExecContext(ctx, query, match.AccountIDLow, match.AccountIDHigh)
+	if err != nil {
+		return record.MatchRecord{}, false, fmt.Errorf("could not insert match: %v", err)
+	}
+
+	matchId, err := result.LastInsertId()
+	if err != nil {
+		return record.MatchRecord{}, false, fmt.Errorf("could not retrieve last insert id: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return record.MatchRecord{}, false, fmt.Errorf("could not retrieve rows affected: %v", err)
+	}
+
+	if rowsAffected == 1 {
+		if err := insertMatchParticipants(ctx, tx, matchId, []int64{match.AccountIDLow, match.AccountIDHigh}); err != nil {
+			return record.MatchRecord{}, false, err
+		}
+	}
+
+	res, err := m.FindMatchByIdFromDB(ctx, tx, matchId)
+	if err != nil {
+		return record.MatchRecord{}, false, err
+	}
+	return res, rowsAffected == 1, nil
+}
+
+// InsertDuoMatchToDB keeps one match per pair of duos and adds the accounts of both duos to its conversation,
+// created is false when the duos were already matched
+func (m MatchesRepositoryImpl) InsertDuoMatchToDB(ctx context.Context, tx *sql.Tx, match record.MatchRecord, participants []int64) (record.MatchRecord, bool, error) {
+	query := `INSERT INTO matches (duo_id_low, duo_id_high) VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE match_id = LAST_INSERT_ID(match_id)`
+
+	result, err := tx.ExecContext(ctx, query, match.DuoIDLow, match.DuoIDHigh)
+	if err != nil {
+		return record.MatchRecord{}, false, fmt.Errorf("could not insert duo match: %v", err)
+	}
+
+	matchId, err := result.LastInsertId()
+	if err != nil {
+		return record.MatchRecord{}, false, fmt.Errorf("could not retrieve last insert id: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return record.MatchRecord{}, false, fmt.Errorf("could not retrieve rows affected: %v", err)
+	}
+
+	if rowsAffected == 1 {
+		if err := insertMatchParticipants(ctx, tx, matchId, participants); err != nil {
+			return record.MatchRecord{}, false, err
+		}
+	}
+
+	res, err := m.FindMatchByIdFromDB(ctx, tx, matchId)
+	if err != nil {
+		return record.MatchRecord{}, false, err
+	}
+	return res, rowsAffected == 1, nil
+}
+
+// InsertGroupMatchToDB creates a group conversation that is not tied to a pair or to duos, expiresAt is nil for a lasting one
+func (m MatchesRepositoryImpl) InsertGroupMatchToDB(ctx context.Context, tx *sql.Tx, title string, participants []int64, expiresAt *time.Time) (record.MatchRecord, error) {
+	result, err := tx.ExecContext(ctx, "INSERT INTO matches (title, expires_at) VALUES (?, ?)", title, expiresAt)
+	if err != nil {
+		return record.MatchRecord{}, fmt.Errorf("could not insert group match: %v", err)
+	}
+
+	matchId, err := result.LastInsertId()
+	if err != nil {
+		return record.MatchRecord{}, fmt.Errorf("could not retrieve last insert id: %v", err)
+	}
+
+	if err := insertMatchParticipants(ctx, tx, matchId, participants); err != nil {
+		return record.MatchRecord{}, err
+	}
+	return m.FindMatchByIdFromDB(ctx, tx, matchId)
+}
+
+// InsertMatchParticipantToDB adds the account to the conversation, an account that left joins again from now
+func (m MatchesRepositoryImpl) InsertMatchParticipantToDB(ctx context.Context, tx *sql.Tx, matchId int64, accountId int64) error {
+	query := `INSERT INTO match_participants (match_id, account_id) VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE joined_at = IF(left_at IS NULL, joined_at, CURRENT_TIMESTAMP), left_at = NULL`
+
+	if _, err := tx.ExecContext(ctx, query, matchId, accountId); err != nil {
+		return fmt.Errorf("could not insert match participant: %v", err)
+	}
+	return nil
+}
+
+// UpdateMatchParticipantLeftToDB returns 0 when the account was not a participant anymore
+func (m MatchesRepositoryImpl) UpdateMatchParticipantLeftToDB(ctx context.Context, tx *sql.Tx, matchId int64, accountId int64) (int64, error) {
+	result, err := tx.ExecContext(ctx, "UPDATE match_participants SET left_at = CURRENT_TIMESTAMP WHERE match_id = ? AND account_id = ? AND left_at IS NULL", matchId, accountId)
+	if err != nil {
+		return 0, fmt.Errorf("could not update match participant: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not retrieve rows affected: %v", err)
+	}
+	return rowsAffected, nil
+}
+
+// UpdateMatchParticipantReadToDB moves the read state up to the message, or to the latest message for messageId 0.
+// It never moves back and only counts messages of this conversation
+func (m MatchesRepositoryImpl) UpdateMatchParticipantReadToDB(ctx context.Context, tx *sql.Tx, matchId int64, accountId int64, messageId int64) error {
+	query := `UPDATE match_participants SET last_read_message_id = GREATEST(last_read_message_id,
+			(SELECT COALESCE(MAX(ms.message_id), 0) FROM messages ms WHERE ms.match_id = ? AND (? = 0 OR ms.message_id <= ?)))
+		WHERE match_id = ? AND account_id = ? AND left_at IS NULL`
+
+	if _, err := tx.ExecContext(ctx, query, matchId, messageId, messageId, matchId, accountId); err != nil {
+		return fmt.Errorf("could not update read state: %v", err)
+	}
+	return nil
+}
+
+// FindMatchParticipantDetailsFromDB lists the current participants with their read state, in the order they joined
+func (m MatchesRepositoryImpl) FindMatchParticipantDetailsFromDB(ctx context.Context, tx *sql.Tx, matchId int64) ([]record.MatchParticipantRecord, error) {
+	query := `SELECT mp.match_id, mp.account_id, a.username, u.full_name, mp.last_read_message_id, mp.joined_at, mp.left_at
+		FROM match_participants mp
+		INNER JOIN accounts a ON a.account_id = mp.account_id
+		LEFT JOIN users u ON u.account_id = mp.account_id
+		WHERE mp.match_id = ? AND mp.left_at IS NULL
+		ORDER BY mp.joined_at, mp.account_id`
+
+	rows, err := tx.QueryContext(ctx, query, matchId)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var participants []record.MatchParticipantRecord
+	for rows.Next() {
+		var participant record.MatchParticipantRecord
+		if err := rows.Scan(&participant.MatchID, &participant.AccountID, &participant.Username, &participant.FullName,
+			&participant.LastReadMessageID, &participant.JoinedAt, &participant.LeftAt); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		participants = append(participants, participant)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return participants, nil
+}
+
+// FindMatchParticipantsFromDB returns the accounts currently in the conversation, not the ones that left
+func (m MatchesRepositoryImpl) FindMatchParticipantsFromDB(ctx context.Context, tx *sql.Tx, matchId int64) ([]int64, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT account_id FROM match_participants WHERE match_id = ? AND left_at IS NULL ORDER BY account_id", matchId)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var participants []int64
+	for rows.Next() {
+		var accountId int64
+		if err := rows.Scan(&accountId); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		participants = append(participants, accountId)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return participants, nil
+}
+
+func (m MatchesRepositoryImpl) FindMatchByIdFromDB(ctx context.Context, tx *sql.Tx, matchId int64) (record.MatchRecord, error) {
+	query := "SELECT match_id, COALESCE(account_id_low, 0), COALESCE(account_id_high, 0), duo_id_low, duo_id_high, title, expires_at, created_at FROM matches WHERE match_id = ?"
+	var match record.MatchRecord
+	err := tx.QueryRowContext(ctx, query, matchId).Scan(&match.MatchID, &match.AccountIDLow, &match.AccountIDHigh, &match.DuoIDLow, &match.DuoIDHigh, &match.Title, &match.ExpiresAt, &match.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return record.MatchRecord{}, err
+		}
+		return record.MatchRecord{}, fmt.Errorf("could not scan row: %v", err)
+	}
+	return match, nil
+}
+
+// FindMatchesByAccountFromDB lists the matches of the account newest first, hidden and purged accounts and
+// accounts blocked either way are left out
+func (m MatchesRepositoryImpl) FindMatchesByAccountFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.MatchViewRecord, error) {
+	query := `SELECT m.match_id, u.account_id, u.full_name, u.age, u.gender, u.bio, m.created_at
+		FROM matches m
+		INNER JOIN users u ON u.account_id = IF(m.account_id_low = ?, m.account_id_high, m.account_id_low)
+		WHERE (m.account_id_low = ? OR m.account_id_high = ?)
+			AND u.account_id NOT IN (SELECT ad.account_id FROM account_dormancy ad WHERE ad.state IN ('hidden', 'deleted', 'purged'))
+			AND NOT EXISTS (SELECT 1 FROM blocks b WHERE (b.account_id = ? AND b.blocked_account_id = u.account_id)
+				OR (b.account_id = u.account_id AND b.blocked_account_id = ?))
+		ORDER BY m.created_at DESC, m.match_id DESC`
+
+	rows, err := tx.QueryContext(ctx, query, accountId, accountId, accountId, accountId, accountId)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var matches []record.MatchViewRecord
+	for rows.Next() {
+		var match record.MatchViewRecord
+		if err := rows.Scan(
+			&match.MatchID,
+			&match.MatchedAccountID,
+			&match.FullName,
+			&match.Age,
+			&match.Gender,
+			&match.Bio,
+			&match.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		matches = append(matches, match)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return matches, nil
+}
+
+// DeleteMatchFromDB removes the conversation with its messages and participants
+func (m MatchesRepositoryImpl) DeleteMatchFromDB(ctx context.Context, tx *sql.Tx, matchId int64) error {
+	statements := []string{
+		"DELETE FROM messages WHERE match_id = ?",
+		"DELETE FROM match_participants WHERE match_id = ?",
+		"DELETE FROM matches WHERE match_id = ?",
+	}
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement, matchId); err != nil {
+			return fmt.Errorf("could not delete match: %v", err)
+		}
+	}
+	return nil
+}
+
+func insertMatchParticipants(ctx context.Context, tx *sql.Tx, matchId int64, participants []int64) error {
+	for _, accountId := range participants {
+		_, err := tx.ExecContext(ctx, "INSERT IGNORE INTO match_participants (match_id, account_id) VALUES (?, ?)", matchId, accountId)
+		if err != nil {
+			return fmt.Errorf("could not insert match participant: %v", err)
+		}
+	}
+	return nil
+}
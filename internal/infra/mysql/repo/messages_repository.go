@@ -0,0 +1,13 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+type MessagesRepository interface {
+	InsertMessageToDB(ctx context.Context, tx *sql.Tx, message record.MessageRecord) (record.MessageRecord, error)
+	FindMessagesByMatchFromDB(ctx context.Context, tx *sql.Tx, matchId int64, beforeId int64, limit int) ([]record.MessageRecord, error)
+	CountUnreadMessagesFromDB(ctx context.Context, tx *sql.Tx, matchId int64, accountId int64) (int64, error)
+}
@@ -0,0 +1,86 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+// Messages are read with the username and name of the sender, a group conversation needs them to tell senders apart
+const (
+	messageColumns = "m.message_id, m.match_id, m.sender_account_id, a.username, u.full_name, m.body, m.created_at"
+	messageFrom    = " FROM messages m INNER JOIN accounts a ON a.account_id = m.sender_account_id LEFT JOIN users u ON u.account_id = m.sender_account_id"
+)
+
+type MessagesRepositoryImpl struct {
+	MessagesRepository MessagesRepository
+}
+
+func NewMessagesRepositoryImpl() MessagesRepository {
+	return &MessagesRepositoryImpl{}
+}
+
+func (m MessagesRepositoryImpl) InsertMessageToDB(ctx context.Context, tx *sql.Tx, message record.MessageRecord) (record.MessageRecord, error) {
+	result, err := tx.ExecContext(ctx, "INSERT INTO messages (match_id, sender_account_id, body) VALUES (?, ?, ?)",
+		message.MatchID, message.SenderAccountID, message.Body)
+	if err != nil {
+		return record.MessageRecord{}, fmt.Errorf("could not insert message: %v", err)
+	}
+
+	messageId, err := result.LastInsertId()
+	if err != nil {
+		return record.MessageRecord{}, fmt.Errorf("could not retrieve last insert id: %v", err)
+	}
+
+	query := "SELECT " + messageColumns + messageFrom + " WHERE m.message_id = ?"
+	var res record.MessageRecord
+	err = tx.QueryRowContext(ctx, query, messageId).Scan(&res.MessageID, &res.MatchID, &res.SenderAccountID, &res.SenderUsername, &res.SenderFullName, &res.Body, &res.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return record.MessageRecord{}, err
+		}
+		return record.MessageRecord{}, fmt.Errorf("could not scan row: %v", err)
+	}
+	return res, nil
+}
+
+// FindMessagesByMatchFromDB pages backwards through a conversation, newest message first,
+// beforeId 0 starts from the latest message
+func (m MessagesRepositoryImpl) FindMessagesByMatchFromDB(ctx context.Context, tx *sql.Tx, matchId int64, beforeId int64, limit int) ([]record.MessageRecord, error) {
+	query := "SELECT " + messageColumns + messageFrom + " WHERE m.match_id = ? AND (? = 0 OR m.message_id < ?) ORDER BY m.message_id DESC LIMIT ?"
+	rows, err := tx.QueryContext(ctx, query, matchId, beforeId, beforeId, limit)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var messages []record.MessageRecord
+	for rows.Next() {
+		var message record.MessageRecord
+		if err := rows.Scan(&message.MessageID, &message.MatchID, &message.SenderAccountID, &message.SenderUsername, &message.SenderFullName, &message.Body, &message.CreatedAt); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		messages = append(messages, message)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return messages, nil
+}
+
+// CountUnreadMessagesFromDB counts the messages of others after the last one the participant read
+func (m MessagesRepositoryImpl) CountUnreadMessagesFromDB(ctx context.Context, tx *sql.Tx, matchId int64, accountId int64) (int64, error) {
+	query := `SELECT COUNT(*) FROM messages m
+		INNER JOIN match_participants mp ON mp.match_id = m.match_id AND mp.account_id = ?
+		WHERE m.match_id = ? AND m.sender_account_id != ? AND m.message_id > mp.last_read_message_id`
+
+	var unread int64
+	if err := tx.QueryRowContext(ctx, query, accountId, matchId, accountId).Scan(&unread); err != nil {
+		return 0, fmt.Errorf("could not count unread messages: %v", err)
+	}
+	return unread, nil
+}
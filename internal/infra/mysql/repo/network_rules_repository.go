@@ -0,0 +1,14 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+type NetworkRulesRepository interface {
+	InsertNetworkRuleToDB(ctx context.Context, tx *sql.Tx, rule record.NetworkRuleRecord) (record.NetworkRuleRecord, error)
+	DeleteNetworkRuleToDB(ctx context.Context, tx *sql.Tx, ruleId int64) (bool, error)
+	FindNetworkRuleByIdFromDB(ctx context.Context, tx *sql.Tx, ruleId int64) (record.NetworkRuleRecord, error)
+	FindNetworkRulesFromDB(ctx context.Context, tx *sql.Tx) ([]record.NetworkRuleRecord, error)
+}
@@ -0,0 +1,99 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+const networkRuleColumns = "rule_id, list, route_group, cidr, note, created_at"
+
+type NetworkRulesRepositoryImpl struct {
+	NetworkRulesRepository NetworkRulesRepository
+}
+
+func NewNetworkRulesRepositoryImpl() NetworkRulesRepository {
+	return &NetworkRulesRepositoryImpl{}
+}
+
+func (n NetworkRulesRepositoryImpl) InsertNetworkRuleToDB(ctx context.Context, tx *sql.Tx, rule record.NetworkRuleRecord) (record.NetworkRuleRecord, error) {
+	query := "INSERT INTO network_rules (list, route_group, cidr, note) VALUES (?, ?, ?, ?)"
+	result, err := tx.ExecContext(ctx, query, rule.List, rule.RouteGroup, rule.CIDR, rule.Note)
+	if err != nil {
+		var duplicate *DuplicateKeyError
+		if errors.As(asDuplicateKeyError(err), &duplicate) {
+			return record.NetworkRuleRecord{}, duplicate
+		}
+		return record.NetworkRuleRecord{}, fmt.Errorf("could not insert network rule: %v", err)
+	}
+
+	ruleId, err := result.LastInsertId()
+	if err != nil {
+		return record.NetworkRuleRecord{}, fmt.Errorf("could not retrieve last insert id: %v", err)
+	}
+	return n.FindNetworkRuleByIdFromDB(ctx, tx, ruleId)
+}
+
+func (n NetworkRulesRepositoryImpl) DeleteNetworkRuleToDB(ctx context.Context, tx *sql.Tx, ruleId int64) (bool, error) {
+	result, err := tx.ExecContext(ctx, "DELETE FROM network_rules WHERE rule_id = ?", ruleId)
+	if err != nil {
+		return false, fmt.Errorf("could not delete network rule: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("could not retrieve rows affected: %v", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+func (n NetworkRulesRepositoryImpl) FindNetworkRuleByIdFromDB(ctx context.Context, tx *sql.Tx, ruleId int64) (record.NetworkRuleRecord, error) {
+	query := "SELECT " + networkRuleColumns + " FROM network_rules WHERE rule_id = ?"
+	rule, err := scanNetworkRule(tx.QueryRowContext(ctx, query, ruleId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return record.NetworkRuleRecord{}, err
+		}
+		return record.NetworkRuleRecord{}, fmt.Errorf("could not scan row: %v", err)
+	}
+	return rule, nil
+}
+
+func (n NetworkRulesRepositoryImpl) FindNetworkRulesFromDB(ctx context.Context, tx *sql.Tx) ([]record.NetworkRuleRecord, error) {
+	query := "SELECT " + networkRuleColumns + " FROM network_rules ORDER BY route_group, list, rule_id"
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var rules []record.NetworkRuleRecord
+	for rows.Next() {
+		rule, err := scanNetworkRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return rules, nil
+}
+
+func scanNetworkRule(row interface{ Scan(dest ...any) error }) (record.NetworkRuleRecord, error) {
+	var rule record.NetworkRuleRecord
+	err := row.Scan(
+		&rule.RuleID,
+		&rule.List,
+		&rule.RouteGroup,
+		&rule.CIDR,
+		&rule.Note,
+		&rule.CreatedAt,
+	)
+	return rule, err
+}
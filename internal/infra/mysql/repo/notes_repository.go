@@ -0,0 +1,15 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+type NotesRepository interface {
+	InsertNoteToDB(ctx context.Context, tx *sql.Tx, record record.NoteRecord) (record.NoteRecord, error)
+	FindNotesByOwnerFromDB(ctx context.Context, tx *sql.Tx, ownerAccountId int64, targetAccountId int64) ([]record.NoteRecord, error)
+	UpdateNoteToDB(ctx context.Context, tx *sql.Tx, record record.NoteRecord) error
+	FindNoteByIdFromDB(ctx context.Context, tx *sql.Tx, ownerAccountId int64, noteId int64) (record.NoteRecord, error)
+	DeleteNoteFromDB(ctx context.Context, tx *sql.Tx, ownerAccountId int64, noteId int64) error
+}
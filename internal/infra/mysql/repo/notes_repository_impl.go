@@ -0,0 +1,117 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+type NotesRepositoryImpl struct {
+	NotesRepository NotesRepository
+}
+
+func NewNotesRepositoryImpl() NotesRepository {
+	return &NotesRepositoryImpl{}
+}
+
+func (n NotesRepositoryImpl) InsertNoteToDB(ctx context.Context, tx *sql.Tx, record record.NoteRecord) (record.NoteRecord, error) {
+	query := "INSERT INTO notes (owner_account_id, target_account_id, content) VALUES (?, ?, ?)"
+	result, err := tx.ExecContext(ctx, query, record.OwnerAccountID, record.TargetAccountID, record.Content)
+	if err != nil {
+		return record, fmt.Errorf("could not insert note: %v", err)
+	}
+
+	noteId, err := result.LastInsertId()
+	if err != nil {
+		return record, fmt.Errorf("could not retrieve last insert id: %v", err)
+	}
+
+	record.NoteID = noteId
+	return record, nil
+}
+
+// FindNotesByOwnerFromDB returns the notes of the owner, optionally narrowed to one target profile when targetAccountId is set
+func (n NotesRepositoryImpl) FindNotesByOwnerFromDB(ctx context.Context, tx *sql.Tx, ownerAccountId int64, targetAccountId int64) ([]record.NoteRecord, error) {
+	query := "SELECT note_id, owner_account_id, target_account_id, content, created_at, updated_at FROM notes WHERE owner_account_id = ?"
+	args := []interface{}{ownerAccountId}
+	if targetAccountId != 0 {
+		query += " AND target_account_id = ?"
+		args = append(args, targetAccountId)
+	}
+	query += " ORDER BY updated_at DESC"
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var notes []record.NoteRecord
+	for rows.Next() {
+		var note record.NoteRecord
+		if err := rows.Scan(
+			&note.NoteID,
+			&note.OwnerAccountID,
+			&note.TargetAccountID,
+			&note.Content,
+			&note.CreatedAt,
+			&note.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		notes = append(notes, note)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return notes, nil
+}
+
+func (n NotesRepositoryImpl) UpdateNoteToDB(ctx context.Context, tx *sql.Tx, record record.NoteRecord) error {
+	query := "UPDATE notes SET content = ?, updated_at = CURRENT_TIMESTAMP WHERE note_id = ? AND owner_account_id = ?"
+	result, err := tx.ExecContext(ctx, query, record.Content, record.NoteID, record.OwnerAccountID)
+	if err != nil {
+		return errors.New("error while executing update notes")
+	}
+
+	rowCount, err := result.RowsAffected()
+	if err != nil || rowCount == 0 {
+		return errors.New("note not found")
+	}
+	return nil
+}
+
+func (n NotesRepositoryImpl) FindNoteByIdFromDB(ctx context.Context, tx *sql.Tx, ownerAccountId int64, noteId int64) (record.NoteRecord, error) {
+	query := "SELECT note_id, owner_account_id, target_account_id, content, created_at, updated_at FROM notes WHERE note_id = ? AND owner_account_id = ?"
+	var note record.NoteRecord
+	err := tx.QueryRowContext(ctx, query, noteId, ownerAccountId).Scan(
+		&note.NoteID,
+		&note.OwnerAccountID,
+		&note.TargetAccountID,
+		&note.Content,
+		&note.CreatedAt,
+		&note.UpdatedAt,
+	)
+	if err != nil {
+		return record.NoteRecord{}, fmt.Errorf("could not scan row: %v", err)
+	}
+	return note, nil
+}
+
+func (n NotesRepositoryImpl) DeleteNoteFromDB(ctx context.Context, tx *sql.Tx, ownerAccountId int64, noteId int64) error {
+	query := "DELETE FROM notes WHERE note_id = ? AND owner_account_id = ?"
+	result, err := tx.ExecContext(ctx, query, noteId, ownerAccountId)
+	if err != nil {
+		return errors.New("error while executing delete notes")
+	}
+
+	rowCount, err := result.RowsAffected()
+	if err != nil || rowCount == 0 {
+		return errors.New("note not found")
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+	"time"
+)
+
+type NotificationsRepository interface {
+	InsertNotificationsToDB(ctx context.Context, tx *sql.Tx, notifications []record.NotificationRecord) error
+	FindNotificationsByAccountIdFromDB(ctx context.Context, tx *sql.Tx, accountId int64, unreadOnly bool, limit int, offset int) ([]record.NotificationRecord, error)
+	CountNotificationsByAccountIdFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (total int64, unread int64, err error)
+	MarkNotificationsReadToDB(ctx context.Context, tx *sql.Tx, accountId int64, notificationIds []int64, readAt time.Time) (int64, error)
+	DeleteNotificationsBeforeFromDB(ctx context.Context, tx *sql.Tx, before time.Time) (int64, error)
+}
@@ -0,0 +1,114 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/record"
+	"strings"
+	"time"
+)
+
+const notificationColumns = "notification_id, account_id, kind, title, body, data, read_at, created_at"
+
+type NotificationsRepositoryImpl struct {
+	NotificationsRepository NotificationsRepository
+}
+
+func NewNotificationsRepositoryImpl() NotificationsRepository {
+	return &NotificationsRepositoryImpl{}
+}
+
+func (n NotificationsRepositoryImpl) InsertNotificationsToDB(ctx context.Context, tx *sql.Tx, notifications []record.NotificationRecord) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("(?, ?, ?, ?, ?), ", len(notifications)), ", ")
+	query := "INSERT INTO notifications (account_id, kind, title, body, data) VALUES " + placeholders
+	args := make([]interface{}, 0, len(notifications)*5)
+	for _, notification := range notifications {
+		args = append(args, notification.AccountID, notification.Kind, notification.Title, notification.Body, notification.Data)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("could not insert notifications: %v", err)
+	}
+	return nil
+}
+
+// FindNotificationsByAccountIdFromDB pages through the feed of the account, newest first
+func (n NotificationsRepositoryImpl) FindNotificationsByAccountIdFromDB(ctx context.Context, tx *sql.Tx, accountId int64, unreadOnly bool, limit int, offset int) ([]record.NotificationRecord, error) {
+	query := "SELECT " + notificationColumns + " FROM notifications WHERE account_id = ?"
+	if unreadOnly {
+		query += " AND read_at IS NULL"
+	}
+	query += " ORDER BY notification_id DESC LIMIT ? OFFSET ?"
+
+	rows, err := tx.QueryContext(ctx, query, accountId, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var notifications []record.NotificationRecord
+	for rows.Next() {
+		var notification record.NotificationRecord
+		if err := rows.Scan(&notification.NotificationID, &notification.AccountID, &notification.Kind, &notification.Title,
+			&notification.Body, &notification.Data, &notification.ReadAt, &notification.CreatedAt); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		notifications = append(notifications, notification)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return notifications, nil
+}
+
+func (n NotificationsRepositoryImpl) CountNotificationsByAccountIdFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (int64, int64, error) {
+	query := "SELECT COUNT(*), COALESCE(SUM(CASE WHEN read_at IS NULL THEN 1 ELSE 0 END), 0) FROM notifications WHERE account_id = ?"
+	var total, unread int64
+	if err := tx.QueryRowContext(ctx, query, accountId).Scan(&total, &unread); err != nil {
+		return 0, 0, fmt.Errorf("could not count notifications: %v", err)
+	}
+	return total, unread, nil
+}
+
+// MarkNotificationsReadToDB marks the unread notifications of the account read, every one of them when no id is given.
+// Ids of other accounts are left alone
+func (n NotificationsRepositoryImpl) MarkNotificationsReadToDB(ctx context.Context, tx *sql.Tx, accountId int64, notificationIds []int64, readAt time.Time) (int64, error) {
+	query := "UPDATE notifications SET read_at = ? WHERE account_id = ? AND read_at IS NULL"
+	args := []interface{}{readAt, accountId}
+	if len(notificationIds) > 0 {
+		query += " AND notification_id IN (" + strings.TrimSuffix(strings.Repeat("?, ", len(notificationIds)), ", ") + ")"
+		for _, notificationId := range notificationIds {
+			args = append(args, notificationId)
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("could not mark notifications read: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not retrieve rows affected: %v", err)
+	}
+	return rowsAffected, nil
+}
+
+// DeleteNotificationsBeforeFromDB removes the notifications created before the time, read or not
+func (n NotificationsRepositoryImpl) DeleteNotificationsBeforeFromDB(ctx context.Context, tx *sql.Tx, before time.Time) (int64, error) {
+	result, err := tx.ExecContext(ctx, "DELETE FROM notifications WHERE created_at < ?", before)
+	if err != nil {
+		return 0, fmt.Errorf("could not delete notifications: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not retrieve rows affected: %v", err)
+	}
+	return rowsAffected, nil
+}
@@ -0,0 +1,17 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+	"time"
+)
+
+type OutboxEventsRepository interface {
+	InsertOutboxEventToDB(ctx context.Context, tx *sql.Tx, event record.OutboxEventRecord) error
+	FindDueOutboxEventsFromDB(ctx context.Context, tx *sql.Tx, now time.Time, limit int) ([]record.OutboxEventRecord, error)
+	LeaseOutboxEventsToDB(ctx context.Context, tx *sql.Tx, eventIds []int64, until time.Time) error
+	MarkOutboxEventPublishedToDB(ctx context.Context, tx *sql.Tx, eventId int64) error
+	RetryOutboxEventToDB(ctx context.Context, tx *sql.Tx, eventId int64, nextAttemptAt time.Time, lastError string) error
+	DeletePublishedOutboxEventsBeforeFromDB(ctx context.Context, tx *sql.Tx, before time.Time) (int64, error)
+}
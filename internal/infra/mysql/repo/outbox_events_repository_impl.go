@@ -0,0 +1,103 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/record"
+	"strings"
+	"time"
+)
+
+const outboxEventColumns = "event_id, kind, aggregate_id, payload, attempts, next_attempt_at, last_error, created_at, published_at"
+
+type OutboxEventsRepositoryImpl struct {
+	OutboxEventsRepository OutboxEventsRepository
+}
+
+func NewOutboxEventsRepositoryImpl() OutboxEventsRepository {
+	return &OutboxEventsRepositoryImpl{}
+}
+
+func (o OutboxEventsRepositoryImpl) InsertOutboxEventToDB(ctx context.Context, tx *sql.Tx, event record.OutboxEventRecord) error {
+	query := "INSERT INTO outbox_events (kind, aggregate_id, payload) VALUES (?, ?, ?)"
+	if _, err := tx.ExecContext(ctx, query, event.Kind, event.AggregateID, event.Payload); err != nil {
+		return fmt.Errorf("could not insert outbox event: %v", err)
+	}
+	return nil
+}
+
+// FindDueOutboxEventsFromDB locks the unpublished events due by now, oldest first, until they are leased
+func (o OutboxEventsRepositoryImpl) FindDueOutboxEventsFromDB(ctx context.Context, tx *sql.Tx, now time.Time, limit int) ([]record.OutboxEventRecord, error) {
+	query := "SELECT " + outboxEventColumns + " FROM outbox_events WHERE published_at IS NULL AND next_attempt_at <= ? ORDER BY event_id LIMIT ? FOR UPDATE"
+	rows, err := tx.QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var events []record.OutboxEventRecord
+	for rows.Next() {
+		var event record.OutboxEventRecord
+		if err := rows.Scan(&event.EventID, &event.Kind, &event.AggregateID, &event.Payload, &event.Attempts,
+			&event.NextAttemptAt, &event.LastError, &event.CreatedAt, &event.PublishedAt); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return events, nil
+}
+
+// LeaseOutboxEventsToDB counts an attempt and keeps the events from the other relayers until the lease ends, a
+// relayer stopping mid publish leaves them to be published again then
+func (o OutboxEventsRepositoryImpl) LeaseOutboxEventsToDB(ctx context.Context, tx *sql.Tx, eventIds []int64, until time.Time) error {
+	if len(eventIds) == 0 {
+		return nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(eventIds)), ", ")
+	args := []interface{}{until}
+	for _, eventId := range eventIds {
+		args = append(args, eventId)
+	}
+
+	query := "UPDATE outbox_events SET attempts = attempts + 1, next_attempt_at = ? WHERE event_id IN (" + placeholders + ")"
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("could not lease outbox events: %v", err)
+	}
+	return nil
+}
+
+func (o OutboxEventsRepositoryImpl) MarkOutboxEventPublishedToDB(ctx context.Context, tx *sql.Tx, eventId int64) error {
+	query := "UPDATE outbox_events SET published_at = CURRENT_TIMESTAMP, last_error = NULL WHERE event_id = ?"
+	if _, err := tx.ExecContext(ctx, query, eventId); err != nil {
+		return fmt.Errorf("could not update outbox event: %v", err)
+	}
+	return nil
+}
+
+func (o OutboxEventsRepositoryImpl) RetryOutboxEventToDB(ctx context.Context, tx *sql.Tx, eventId int64, nextAttemptAt time.Time, lastError string) error {
+	query := "UPDATE outbox_events SET next_attempt_at = ?, last_error = ? WHERE event_id = ?"
+	if _, err := tx.ExecContext(ctx, query, nextAttemptAt, lastError, eventId); err != nil {
+		return fmt.Errorf("could not update outbox event: %v", err)
+	}
+	return nil
+}
+
+// DeletePublishedOutboxEventsBeforeFromDB removes the events published before the time, unpublished ones are kept
+func (o OutboxEventsRepositoryImpl) DeletePublishedOutboxEventsBeforeFromDB(ctx context.Context, tx *sql.Tx, before time.Time) (int64, error) {
+	result, err := tx.ExecContext(ctx, "DELETE FROM outbox_events WHERE published_at IS NOT NULL AND published_at < ?", before)
+	if err != nil {
+		return 0, fmt.Errorf("could not delete outbox events: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not retrieve rows affected: %v", err)
+	}
+	return rowsAffected, nil
+}
@@ -8,4 +8,5 @@ import (
 
 type PackagesRepository interface {
 	GetAllPackages(ctx context.Context, tx *sql.Tx) ([]record.PremiumPackageRecord, error)
+	FindPackageByIdFromDB(ctx context.Context, tx *sql.Tx, packageId int64) (record.PremiumPackageRecord, error)
 }
@@ -3,6 +3,8 @@ package repo
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"godating-dealls/internal/infra/mysql/record"
 )
 
@@ -49,3 +51,26 @@ func (p PackagesRepositoryImpl) GetAllPackages(ctx context.Context, tx *sql.Tx)
 
 	return packages, nil
 }
+
+func (p PackagesRepositoryImpl) FindPackageByIdFromDB(ctx context.Context, tx *sql.Tx, packageId int64) (record.PremiumPackageRecord, error) {
+	query := "SELECT package_id, package_name, description, package_duration_in_monthly, price, unlimited_swipes, status, created_at, updated_at FROM packages WHERE package_id = ?"
+	var pkg record.PremiumPackageRecord
+	err := tx.QueryRowContext(ctx, query, packageId).Scan(
+		&pkg.PackageID,
+		&pkg.PackageName,
+		&pkg.Description,
+		&pkg.PackageDurationInMonthly,
+		&pkg.Price,
+		&pkg.UnlimitedSwipes,
+		&pkg.Status,
+		&pkg.CreatedAt,
+		&pkg.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return record.PremiumPackageRecord{}, err
+		}
+		return record.PremiumPackageRecord{}, fmt.Errorf("could not scan row: %v", err)
+	}
+	return pkg, nil
+}
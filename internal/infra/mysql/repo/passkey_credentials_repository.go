@@ -0,0 +1,15 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+type PasskeyCredentialsRepository interface {
+	InsertPasskeyToDB(ctx context.Context, tx *sql.Tx, record record.PasskeyCredentialRecord) error
+	FindPasskeyByCredentialIdFromDB(ctx context.Context, tx *sql.Tx, credentialId []byte) (record.PasskeyCredentialRecord, error)
+	FindPasskeysByAccountIdFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.PasskeyCredentialRecord, error)
+	UpdatePasskeySignCountToDB(ctx context.Context, tx *sql.Tx, credentialId []byte, signCount uint32) error
+	DeletePasskeyFromDB(ctx context.Context, tx *sql.Tx, accountId int64, credentialId []byte) error
+}
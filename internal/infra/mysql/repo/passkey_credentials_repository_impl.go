@@ -0,0 +1,107 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+type PasskeyCredentialsRepositoryImpl struct {
+	PasskeyCredentialsRepository PasskeyCredentialsRepository
+}
+
+func NewPasskeyCredentialsRepositoryImpl() PasskeyCredentialsRepository {
+	return &PasskeyCredentialsRepositoryImpl{}
+}
+
+// InsertPasskeyToDB returns a DuplicateKeyError when the credential id is already registered
+func (p PasskeyCredentialsRepositoryImpl) InsertPasskeyToDB(ctx context.Context, tx *sql.Tx, record record.PasskeyCredentialRecord) error {
+	query := "INSERT INTO passkey_credentials (credential_id, account_id, name, public_key, sign_count) VALUES (?, ?, ?, ?, ?)"
+	_, err := tx.ExecContext(ctx, query, record.CredentialID, record.AccountID, record.Name, record.PublicKey, record.SignCount)
+	if err != nil {
+		var duplicate *DuplicateKeyError
+		if errors.As(asDuplicateKeyError(err), &duplicate) {
+			return duplicate
+		}
+		return fmt.Errorf("could not insert passkey: %v", err)
+	}
+	return nil
+}
+
+func (p PasskeyCredentialsRepositoryImpl) FindPasskeyByCredentialIdFromDB(ctx context.Context, tx *sql.Tx, credentialId []byte) (record.PasskeyCredentialRecord, error) {
+	query := "SELECT credential_id, account_id, name, public_key, sign_count, created_at, last_used_at FROM passkey_credentials WHERE credential_id = ?"
+	var passkey record.PasskeyCredentialRecord
+	err := tx.QueryRowContext(ctx, query, credentialId).Scan(
+		&passkey.CredentialID,
+		&passkey.AccountID,
+		&passkey.Name,
+		&passkey.PublicKey,
+		&passkey.SignCount,
+		&passkey.CreatedAt,
+		&passkey.LastUsedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return record.PasskeyCredentialRecord{}, errors.New("passkey not found")
+		}
+		return record.PasskeyCredentialRecord{}, fmt.Errorf("could not scan row: %v", err)
+	}
+	return passkey, nil
+}
+
+func (p PasskeyCredentialsRepositoryImpl) FindPasskeysByAccountIdFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.PasskeyCredentialRecord, error) {
+	query := "SELECT credential_id, account_id, name, public_key, sign_count, created_at, last_used_at FROM passkey_credentials WHERE account_id = ? ORDER BY created_at"
+	rows, err := tx.QueryContext(ctx, query, accountId)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var passkeys []record.PasskeyCredentialRecord
+	for rows.Next() {
+		var passkey record.PasskeyCredentialRecord
+		if err := rows.Scan(
+			&passkey.CredentialID,
+			&passkey.AccountID,
+			&passkey.Name,
+			&passkey.PublicKey,
+			&passkey.SignCount,
+			&passkey.CreatedAt,
+			&passkey.LastUsedAt,
+		); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		passkeys = append(passkeys, passkey)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return passkeys, nil
+}
+
+func (p PasskeyCredentialsRepositoryImpl) UpdatePasskeySignCountToDB(ctx context.Context, tx *sql.Tx, credentialId []byte, signCount uint32) error {
+	query := "UPDATE passkey_credentials SET sign_count = ?, last_used_at = CURRENT_TIMESTAMP WHERE credential_id = ?"
+	_, err := tx.ExecContext(ctx, query, signCount, credentialId)
+	if err != nil {
+		return fmt.Errorf("could not update passkey: %v", err)
+	}
+	return nil
+}
+
+func (p PasskeyCredentialsRepositoryImpl) DeletePasskeyFromDB(ctx context.Context, tx *sql.Tx, accountId int64, credentialId []byte) error {
+	query := "DELETE FROM passkey_credentials WHERE credential_id = ? AND account_id = ?"
+	result, err := tx.ExecContext(ctx, query, credentialId, accountId)
+	if err != nil {
+		return errors.New("error while executing delete passkey")
+	}
+
+	rowCount, err := result.RowsAffected()
+	if err != nil || rowCount == 0 {
+		return errors.New("passkey not found")
+	}
+	return nil
+}
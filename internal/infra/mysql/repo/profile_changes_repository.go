@@ -0,0 +1,16 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+type ProfileChangesRepository interface {
+	InsertProfileChangeRequestToDB(ctx context.Context, tx *sql.Tx, request record.ProfileChangeRequestRecord) (record.ProfileChangeRequestRecord, error)
+	FindProfileChangeRequestByIdFromDB(ctx context.Context, tx *sql.Tx, requestId int64) (record.ProfileChangeRequestRecord, error)
+	FindProfileChangeRequestForUpdateFromDB(ctx context.Context, tx *sql.Tx, requestId int64) (record.ProfileChangeRequestRecord, error)
+	FindProfileChangeRequestsByAccountFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.ProfileChangeRequestRecord, error)
+	FindPendingProfileChangeRequestsFromDB(ctx context.Context, tx *sql.Tx) ([]record.ProfileChangeRequestRecord, error)
+	UpdateProfileChangeReviewToDB(ctx context.Context, tx *sql.Tx, request record.ProfileChangeRequestRecord) error
+}
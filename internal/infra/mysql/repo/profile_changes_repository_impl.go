@@ -0,0 +1,134 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+const profileChangeRequestColumns = "request_id, account_id, field, current_value, requested_value, evidence, reason, status, reviewer, review_note, audit_id, created_at, reviewed_at"
+
+type ProfileChangesRepositoryImpl struct {
+	ProfileChangesRepository ProfileChangesRepository
+}
+
+func NewProfileChangesRepositoryImpl() ProfileChangesRepository {
+	return &ProfileChangesRepositoryImpl{}
+}
+
+// InsertProfileChangeRequestToDB stores a pending request, a second pending request for the same field collides on the unique key
+func (p ProfileChangesRepositoryImpl) InsertProfileChangeRequestToDB(ctx context.Context, tx *sql.Tx, request record.ProfileChangeRequestRecord) (record.ProfileChangeRequestRecord, error) {
+	query := `INSERT INTO profile_change_requests (account_id, field, current_value, requested_value, evidence, reason, status)
+		VALUES (?, ?, ?, ?, ?, ?, 'pending')`
+
+	result, err := tx.ExecContext(ctx, query,
+		request.AccountID,
+		request.Field,
+		request.CurrentValue,
+		request.RequestedValue,
+		request.Evidence,
+		request.Reason,
+	)
+	if err != nil {
+		var duplicate *DuplicateKeyError
+		if errors.As(asDuplicateKeyError(err), &duplicate) {
+			return record.ProfileChangeRequestRecord{}, duplicate
+		}
+		return record.ProfileChangeRequestRecord{}, fmt.Errorf("could not insert profile change request: %v", err)
+	}
+
+	requestId, err := result.LastInsertId()
+	if err != nil {
+		return record.ProfileChangeRequestRecord{}, fmt.Errorf("could not retrieve last insert id: %v", err)
+	}
+	return p.FindProfileChangeRequestByIdFromDB(ctx, tx, requestId)
+}
+
+func (p ProfileChangesRepositoryImpl) FindProfileChangeRequestByIdFromDB(ctx context.Context, tx *sql.Tx, requestId int64) (record.ProfileChangeRequestRecord, error) {
+	query := "SELECT " + profileChangeRequestColumns + " FROM profile_change_requests WHERE request_id = ?"
+	return findProfileChangeRequest(ctx, tx, query, requestId)
+}
+
+// FindProfileChangeRequestForUpdateFromDB locks the request so two moderators cannot decide it at the same time
+func (p ProfileChangesRepositoryImpl) FindProfileChangeRequestForUpdateFromDB(ctx context.Context, tx *sql.Tx, requestId int64) (record.ProfileChangeRequestRecord, error) {
+	query := "SELECT " + profileChangeRequestColumns + " FROM profile_change_requests WHERE request_id = ? FOR UPDATE"
+	return findProfileChangeRequest(ctx, tx, query, requestId)
+}
+
+func (p ProfileChangesRepositoryImpl) FindProfileChangeRequestsByAccountFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.ProfileChangeRequestRecord, error) {
+	query := "SELECT " + profileChangeRequestColumns + " FROM profile_change_requests WHERE account_id = ? ORDER BY created_at DESC, request_id DESC"
+	return queryProfileChangeRequests(ctx, tx, query, accountId)
+}
+
+// FindPendingProfileChangeRequestsFromDB is the moderation queue, oldest request first
+func (p ProfileChangesRepositoryImpl) FindPendingProfileChangeRequestsFromDB(ctx context.Context, tx *sql.Tx) ([]record.ProfileChangeRequestRecord, error) {
+	query := "SELECT " + profileChangeRequestColumns + " FROM profile_change_requests WHERE status = 'pending' ORDER BY created_at, request_id"
+	return queryProfileChangeRequests(ctx, tx, query)
+}
+
+func (p ProfileChangesRepositoryImpl) UpdateProfileChangeReviewToDB(ctx context.Context, tx *sql.Tx, request record.ProfileChangeRequestRecord) error {
+	query := `UPDATE profile_change_requests SET status = ?, reviewer = ?, review_note = ?, audit_id = ?, reviewed_at = CURRENT_TIMESTAMP
+		WHERE request_id = ?`
+
+	_, err := tx.ExecContext(ctx, query, request.Status, request.Reviewer, request.ReviewNote, request.AuditID, request.RequestID)
+	if err != nil {
+		return fmt.Errorf("could not update profile change review: %v", err)
+	}
+	return nil
+}
+
+func findProfileChangeRequest(ctx context.Context, tx *sql.Tx, query string, requestId int64) (record.ProfileChangeRequestRecord, error) {
+	request, err := scanProfileChangeRequest(tx.QueryRowContext(ctx, query, requestId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return record.ProfileChangeRequestRecord{}, err
+		}
+		return record.ProfileChangeRequestRecord{}, fmt.Errorf("could not scan row: %v", err)
+	}
+	return request, nil
+}
+
+func queryProfileChangeRequests(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]record.ProfileChangeRequestRecord, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var requests []record.ProfileChangeRequestRecord
+	for rows.Next() {
+		request, err := scanProfileChangeRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		requests = append(requests, request)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return requests, nil
+}
+
+func scanProfileChangeRequest(row interface{ Scan(dest ...any) error }) (record.ProfileChangeRequestRecord, error) {
+	var request record.ProfileChangeRequestRecord
+	err := row.Scan(
+		&request.RequestID,
+		&request.AccountID,
+		&request.Field,
+		&request.CurrentValue,
+		&request.RequestedValue,
+		&request.Evidence,
+		&request.Reason,
+		&request.Status,
+		&request.Reviewer,
+		&request.ReviewNote,
+		&request.AuditID,
+		&request.CreatedAt,
+		&request.ReviewedAt,
+	)
+	return request, err
+}
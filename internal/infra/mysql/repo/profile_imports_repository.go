@@ -0,0 +1,13 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+type ProfileImportsRepository interface {
+	InsertImportsToDB(ctx context.Context, tx *sql.Tx, records []record.ProfileImportRecord) error
+	FindImportsByAccountIdFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.ProfileImportRecord, error)
+	DeleteImportsFromDB(ctx context.Context, tx *sql.Tx, accountId int64, provider string) error
+}
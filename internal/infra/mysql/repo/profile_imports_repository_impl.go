@@ -0,0 +1,79 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/record"
+	"strings"
+)
+
+type ProfileImportsRepositoryImpl struct {
+	ProfileImportsRepository ProfileImportsRepository
+}
+
+func NewProfileImportsRepositoryImpl() ProfileImportsRepository {
+	return &ProfileImportsRepositoryImpl{}
+}
+
+func (p ProfileImportsRepositoryImpl) InsertImportsToDB(ctx context.Context, tx *sql.Tx, records []record.ProfileImportRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, 0, len(records))
+	args := make([]interface{}, 0, len(records)*6)
+	for _, rec := range records {
+		placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?)")
+		args = append(args, rec.AccountID, rec.Provider, rec.ContentType, rec.ExternalID, rec.ContentValue, rec.ContentURL)
+	}
+
+	query := "INSERT INTO profile_imports (account_id, provider, content_type, external_id, content_value, content_url) VALUES " + strings.Join(placeholders, ", ")
+	_, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("could not insert profile imports: %v", err)
+	}
+	return nil
+}
+
+func (p ProfileImportsRepositoryImpl) FindImportsByAccountIdFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.ProfileImportRecord, error) {
+	query := "SELECT import_id, account_id, provider, content_type, external_id, content_value, content_url, imported_at FROM profile_imports WHERE account_id = ? ORDER BY provider, import_id"
+	rows, err := tx.QueryContext(ctx, query, accountId)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var imports []record.ProfileImportRecord
+	for rows.Next() {
+		var rec record.ProfileImportRecord
+		if err := rows.Scan(
+			&rec.ImportID,
+			&rec.AccountID,
+			&rec.Provider,
+			&rec.ContentType,
+			&rec.ExternalID,
+			&rec.ContentValue,
+			&rec.ContentURL,
+			&rec.ImportedAt,
+		); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		imports = append(imports, rec)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+	return imports, nil
+}
+
+func (p ProfileImportsRepositoryImpl) DeleteImportsFromDB(ctx context.Context, tx *sql.Tx, accountId int64, provider string) error {
+	query := "DELETE FROM profile_imports WHERE account_id = ? AND provider = ?"
+	_, err := tx.ExecContext(ctx, query, accountId, provider)
+	if err != nil {
+		return errors.New("error while executing delete profile_imports")
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+type ProfileIntegrationsRepository interface {
+	UpsertIntegrationToDB(ctx context.Context, tx *sql.Tx, record record.ProfileIntegrationRecord) error
+	FindIntegrationsByAccountIdFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.ProfileIntegrationRecord, error)
+	FindAllIntegrationsFromDB(ctx context.Context, tx *sql.Tx) ([]record.ProfileIntegrationRecord, error)
+	DeleteIntegrationFromDB(ctx context.Context, tx *sql.Tx, accountId int64, provider string) error
+}
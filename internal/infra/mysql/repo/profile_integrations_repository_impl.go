@@ -0,0 +1,97 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+type ProfileIntegrationsRepositoryImpl struct {
+	ProfileIntegrationsRepository ProfileIntegrationsRepository
+}
+
+func NewProfileIntegrationsRepositoryImpl() ProfileIntegrationsRepository {
+	return &ProfileIntegrationsRepositoryImpl{}
+}
+
+func (p ProfileIntegrationsRepositoryImpl) UpsertIntegrationToDB(ctx context.Context, tx *sql.Tx, record record.ProfileIntegrationRecord) error {
+	query := `
+		INSERT INTO profile_integrations (account_id, provider, access_token, refresh_token, expires_at, last_synced_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE access_token = VALUES(access_token), refresh_token = VALUES(refresh_token),
+			expires_at = VALUES(expires_at), last_synced_at = VALUES(last_synced_at)
+	`
+	_, err := tx.ExecContext(ctx, query,
+		record.AccountID,
+		record.Provider,
+		record.AccessToken,
+		record.RefreshToken,
+		record.ExpiresAt,
+		record.LastSyncedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("could not upsert profile integration: %v", err)
+	}
+	return nil
+}
+
+func (p ProfileIntegrationsRepositoryImpl) FindIntegrationsByAccountIdFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.ProfileIntegrationRecord, error) {
+	query := "SELECT integration_id, account_id, provider, access_token, refresh_token, expires_at, last_synced_at, created_at FROM profile_integrations WHERE account_id = ?"
+	rows, err := tx.QueryContext(ctx, query, accountId)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+	return scanProfileIntegrations(rows)
+}
+
+func (p ProfileIntegrationsRepositoryImpl) FindAllIntegrationsFromDB(ctx context.Context, tx *sql.Tx) ([]record.ProfileIntegrationRecord, error) {
+	query := "SELECT integration_id, account_id, provider, access_token, refresh_token, expires_at, last_synced_at, created_at FROM profile_integrations"
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+	return scanProfileIntegrations(rows)
+}
+
+func (p ProfileIntegrationsRepositoryImpl) DeleteIntegrationFromDB(ctx context.Context, tx *sql.Tx, accountId int64, provider string) error {
+	query := "DELETE FROM profile_integrations WHERE account_id = ? AND provider = ?"
+	result, err := tx.ExecContext(ctx, query, accountId, provider)
+	if err != nil {
+		return errors.New("error while executing delete profile_integrations")
+	}
+
+	rowCount, err := result.RowsAffected()
+	if err != nil || rowCount == 0 {
+		return errors.New("integration not found")
+	}
+	return nil
+}
+
+func scanProfileIntegrations(rows *sql.Rows) ([]record.ProfileIntegrationRecord, error) {
+	var integrations []record.ProfileIntegrationRecord
+	for rows.Next() {
+		var integration record.ProfileIntegrationRecord
+		if err := rows.Scan(
+			&integration.IntegrationID,
+			&integration.AccountID,
+			&integration.Provider,
+			&integration.AccessToken,
+			&integration.RefreshToken,
+			&integration.ExpiresAt,
+			&integration.LastSyncedAt,
+			&integration.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		integrations = append(integrations, integration)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+	return integrations, nil
+}
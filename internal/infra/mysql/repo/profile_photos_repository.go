@@ -0,0 +1,22 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+	"time"
+)
+
+type ProfilePhotosRepository interface {
+	LockPhotoOwnerFromDB(ctx context.Context, tx *sql.Tx, accountId int64) error
+	InsertProfilePhotoToDB(ctx context.Context, tx *sql.Tx, photo record.ProfilePhotoRecord) (record.ProfilePhotoRecord, error)
+	FindProfilePhotosFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.ProfilePhotoRecord, error)
+	FindAllProfilePhotosFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.ProfilePhotoRecord, error)
+	FindProfilePhotosByAccountsFromDB(ctx context.Context, tx *sql.Tx, accountIds []int64) ([]record.ProfilePhotoRecord, error)
+	UpdateProfilePhotoOrderToDB(ctx context.Context, tx *sql.Tx, photoId int64, position int, isPrimary bool) error
+	TrashProfilePhotoToDB(ctx context.Context, tx *sql.Tx, photoId int64) error
+	FindTrashedProfilePhotosFromDB(ctx context.Context, tx *sql.Tx, accountId int64, trashedAfter time.Time) ([]record.ProfilePhotoRecord, error)
+	RestoreProfilePhotoToDB(ctx context.Context, tx *sql.Tx, photoId int64, position int, isPrimary bool) error
+	FindExpiredTrashedPhotosFromDB(ctx context.Context, tx *sql.Tx, trashedBefore time.Time, limit int) ([]record.ProfilePhotoRecord, error)
+	DeleteProfilePhotoToDB(ctx context.Context, tx *sql.Tx, photoId int64) error
+}
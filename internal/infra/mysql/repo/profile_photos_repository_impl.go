@@ -0,0 +1,167 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/record"
+	"strings"
+	"time"
+)
+
+const profilePhotoColumns = "photo_id, account_id, storage_key, content_type, size_bytes, position, is_primary, created_at, deleted_at"
+
+type ProfilePhotosRepositoryImpl struct {
+	ProfilePhotosRepository ProfilePhotosRepository
+}
+
+func NewProfilePhotosRepositoryImpl() ProfilePhotosRepository {
+	return &ProfilePhotosRepositoryImpl{}
+}
+
+// LockPhotoOwnerFromDB locks the account row, so two uploads at once cannot both pass the photo limit
+func (p ProfilePhotosRepositoryImpl) LockPhotoOwnerFromDB(ctx context.Context, tx *sql.Tx, accountId int64) error {
+	var lockedId int64
+	err := tx.QueryRowContext(ctx, "SELECT account_id FROM accounts WHERE account_id = ? FOR UPDATE", accountId).Scan(&lockedId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		return fmt.Errorf("could not lock account: %v", err)
+	}
+	return nil
+}
+
+func (p ProfilePhotosRepositoryImpl) InsertProfilePhotoToDB(ctx context.Context, tx *sql.Tx, photo record.ProfilePhotoRecord) (record.ProfilePhotoRecord, error) {
+	query := "INSERT INTO profile_photos (account_id, storage_key, content_type, size_bytes, position, is_primary) VALUES (?, ?, ?, ?, ?, ?)"
+	result, err := tx.ExecContext(ctx, query, photo.AccountID, photo.StorageKey, photo.ContentType, photo.SizeBytes, photo.Position, photo.IsPrimary)
+	if err != nil {
+		return record.ProfilePhotoRecord{}, fmt.Errorf("could not insert profile photo: %v", err)
+	}
+
+	photoId, err := result.LastInsertId()
+	if err != nil {
+		return record.ProfilePhotoRecord{}, fmt.Errorf("could not retrieve last insert id: %v", err)
+	}
+
+	query = "SELECT " + profilePhotoColumns + " FROM profile_photos WHERE photo_id = ?"
+	inserted, err := scanProfilePhoto(tx.QueryRowContext(ctx, query, photoId))
+	if err != nil {
+		return record.ProfilePhotoRecord{}, fmt.Errorf("could not scan row: %v", err)
+	}
+	return inserted, nil
+}
+
+// FindProfilePhotosFromDB returns the primary photo first, then the others by position, trashed photos left out
+func (p ProfilePhotosRepositoryImpl) FindProfilePhotosFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.ProfilePhotoRecord, error) {
+	query := "SELECT " + profilePhotoColumns + " FROM profile_photos WHERE account_id = ? AND deleted_at IS NULL ORDER BY is_primary DESC, position"
+	return queryProfilePhotos(ctx, tx, query, accountId)
+}
+
+// FindAllProfilePhotosFromDB returns the photos of the account, trashed photos included
+func (p ProfilePhotosRepositoryImpl) FindAllProfilePhotosFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.ProfilePhotoRecord, error) {
+	query := "SELECT " + profilePhotoColumns + " FROM profile_photos WHERE account_id = ? ORDER BY photo_id"
+	return queryProfilePhotos(ctx, tx, query, accountId)
+}
+
+func (p ProfilePhotosRepositoryImpl) FindProfilePhotosByAccountsFromDB(ctx context.Context, tx *sql.Tx, accountIds []int64) ([]record.ProfilePhotoRecord, error) {
+	if len(accountIds) == 0 {
+		return nil, nil
+	}
+
+	placeholders := make([]string, 0, len(accountIds))
+	args := make([]interface{}, 0, len(accountIds))
+	for _, accountId := range accountIds {
+		placeholders = append(placeholders, "?")
+		args = append(args, accountId)
+	}
+
+	query := "SELECT " + profilePhotoColumns + " FROM profile_photos WHERE account_id IN (" + strings.Join(placeholders, ", ") +
+		") AND deleted_at IS NULL ORDER BY account_id, is_primary DESC, position"
+	return queryProfilePhotos(ctx, tx, query, args...)
+}
+
+func (p ProfilePhotosRepositoryImpl) UpdateProfilePhotoOrderToDB(ctx context.Context, tx *sql.Tx, photoId int64, position int, isPrimary bool) error {
+	_, err := tx.ExecContext(ctx, "UPDATE profile_photos SET position = ?, is_primary = ? WHERE photo_id = ?", position, isPrimary, photoId)
+	if err != nil {
+		return fmt.Errorf("could not update profile photo: %v", err)
+	}
+	return nil
+}
+
+// TrashProfilePhotoToDB keeps the row and the file, the photo only leaves the profile
+func (p ProfilePhotosRepositoryImpl) TrashProfilePhotoToDB(ctx context.Context, tx *sql.Tx, photoId int64) error {
+	_, err := tx.ExecContext(ctx, "UPDATE profile_photos SET deleted_at = NOW(), is_primary = FALSE WHERE photo_id = ? AND deleted_at IS NULL", photoId)
+	if err != nil {
+		return fmt.Errorf("could not trash profile photo: %v", err)
+	}
+	return nil
+}
+
+// FindTrashedProfilePhotosFromDB returns the photos trashed after trashedAfter, the latest first
+func (p ProfilePhotosRepositoryImpl) FindTrashedProfilePhotosFromDB(ctx context.Context, tx *sql.Tx, accountId int64, trashedAfter time.Time) ([]record.ProfilePhotoRecord, error) {
+	query := "SELECT " + profilePhotoColumns + " FROM profile_photos WHERE account_id = ? AND deleted_at > ? ORDER BY deleted_at DESC, photo_id DESC"
+	return queryProfilePhotos(ctx, tx, query, accountId, trashedAfter)
+}
+
+func (p ProfilePhotosRepositoryImpl) RestoreProfilePhotoToDB(ctx context.Context, tx *sql.Tx, photoId int64, position int, isPrimary bool) error {
+	query := "UPDATE profile_photos SET deleted_at = NULL, position = ?, is_primary = ? WHERE photo_id = ? AND deleted_at IS NOT NULL"
+	if _, err := tx.ExecContext(ctx, query, position, isPrimary, photoId); err != nil {
+		return fmt.Errorf("could not restore profile photo: %v", err)
+	}
+	return nil
+}
+
+// FindExpiredTrashedPhotosFromDB returns the photos trashed before trashedBefore of every account, the oldest first
+func (p ProfilePhotosRepositoryImpl) FindExpiredTrashedPhotosFromDB(ctx context.Context, tx *sql.Tx, trashedBefore time.Time, limit int) ([]record.ProfilePhotoRecord, error) {
+	query := "SELECT " + profilePhotoColumns + " FROM profile_photos WHERE deleted_at IS NOT NULL AND deleted_at <= ? ORDER BY deleted_at, photo_id LIMIT ?"
+	return queryProfilePhotos(ctx, tx, query, trashedBefore, limit)
+}
+
+func (p ProfilePhotosRepositoryImpl) DeleteProfilePhotoToDB(ctx context.Context, tx *sql.Tx, photoId int64) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM profile_photos WHERE photo_id = ?", photoId)
+	if err != nil {
+		return fmt.Errorf("could not delete profile photo: %v", err)
+	}
+	return nil
+}
+
+func queryProfilePhotos(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]record.ProfilePhotoRecord, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var photos []record.ProfilePhotoRecord
+	for rows.Next() {
+		photo, err := scanProfilePhoto(rows)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		photos = append(photos, photo)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return photos, nil
+}
+
+func scanProfilePhoto(row interface{ Scan(dest ...any) error }) (record.ProfilePhotoRecord, error) {
+	var photo record.ProfilePhotoRecord
+	err := row.Scan(
+		&photo.PhotoID,
+		&photo.AccountID,
+		&photo.StorageKey,
+		&photo.ContentType,
+		&photo.SizeBytes,
+		&photo.Position,
+		&photo.IsPrimary,
+		&photo.CreatedAt,
+		&photo.DeletedAt,
+	)
+	return photo, err
+}
@@ -0,0 +1,15 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+type ProfileShareLinksRepository interface {
+	InsertShareLinkToDB(ctx context.Context, tx *sql.Tx, record record.ProfileShareLinkRecord) (record.ProfileShareLinkRecord, error)
+	FindShareLinkByIdFromDB(ctx context.Context, tx *sql.Tx, linkId int64) (record.ProfileShareLinkRecord, error)
+	FindShareLinksByAccountIdFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.ProfileShareLinkRecord, error)
+	RevokeShareLinkToDB(ctx context.Context, tx *sql.Tx, accountId int64, linkId int64) error
+	IncrementShareLinkViewToDB(ctx context.Context, tx *sql.Tx, linkId int64) error
+}
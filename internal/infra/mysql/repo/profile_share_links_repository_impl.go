@@ -0,0 +1,112 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+type ProfileShareLinksRepositoryImpl struct {
+	ProfileShareLinksRepository ProfileShareLinksRepository
+}
+
+func NewProfileShareLinksRepositoryImpl() ProfileShareLinksRepository {
+	return &ProfileShareLinksRepositoryImpl{}
+}
+
+func (p ProfileShareLinksRepositoryImpl) InsertShareLinkToDB(ctx context.Context, tx *sql.Tx, record record.ProfileShareLinkRecord) (record.ProfileShareLinkRecord, error) {
+	query := "INSERT INTO profile_share_links (account_id, expires_at) VALUES (?, ?)"
+	result, err := tx.ExecContext(ctx, query, record.AccountID, record.ExpiresAt)
+	if err != nil {
+		return record, fmt.Errorf("could not insert share link: %v", err)
+	}
+
+	linkId, err := result.LastInsertId()
+	if err != nil {
+		return record, fmt.Errorf("could not retrieve last insert id: %v", err)
+	}
+
+	record.LinkID = linkId
+	return record, nil
+}
+
+func (p ProfileShareLinksRepositoryImpl) FindShareLinkByIdFromDB(ctx context.Context, tx *sql.Tx, linkId int64) (record.ProfileShareLinkRecord, error) {
+	query := "SELECT link_id, account_id, expires_at, revoked_at, view_count, created_at FROM profile_share_links WHERE link_id = ?"
+
+	var link record.ProfileShareLinkRecord
+	err := tx.QueryRowContext(ctx, query, linkId).Scan(
+		&link.LinkID,
+		&link.AccountID,
+		&link.ExpiresAt,
+		&link.RevokedAt,
+		&link.ViewCount,
+		&link.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return link, errors.New("share link not found")
+		}
+		return link, fmt.Errorf("could not query share link: %v", err)
+	}
+	return link, nil
+}
+
+func (p ProfileShareLinksRepositoryImpl) FindShareLinksByAccountIdFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.ProfileShareLinkRecord, error) {
+	query := "SELECT link_id, account_id, expires_at, revoked_at, view_count, created_at FROM profile_share_links WHERE account_id = ? ORDER BY created_at DESC"
+
+	rows, err := tx.QueryContext(ctx, query, accountId)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var links []record.ProfileShareLinkRecord
+	for rows.Next() {
+		var link record.ProfileShareLinkRecord
+		if err := rows.Scan(
+			&link.LinkID,
+			&link.AccountID,
+			&link.ExpiresAt,
+			&link.RevokedAt,
+			&link.ViewCount,
+			&link.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		links = append(links, link)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return links, nil
+}
+
+func (p ProfileShareLinksRepositoryImpl) RevokeShareLinkToDB(ctx context.Context, tx *sql.Tx, accountId int64, linkId int64) error {
+	query := "UPDATE profile_share_links SET revoked_at = CURRENT_TIMESTAMP WHERE link_id = ? AND account_id = ? AND revoked_at IS NULL"
+	result, err := tx.ExecContext(ctx, query, linkId, accountId)
+	if err != nil {
+		return errors.New("error while executing revoke share link")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.New("error while checking rows affected")
+	}
+	if rowsAffected == 0 {
+		return errors.New("share link not found")
+	}
+	return nil
+}
+
+func (p ProfileShareLinksRepositoryImpl) IncrementShareLinkViewToDB(ctx context.Context, tx *sql.Tx, linkId int64) error {
+	query := "UPDATE profile_share_links SET view_count = view_count + 1 WHERE link_id = ?"
+	_, err := tx.ExecContext(ctx, query, linkId)
+	if err != nil {
+		return fmt.Errorf("could not increment share link view: %v", err)
+	}
+	return nil
+}
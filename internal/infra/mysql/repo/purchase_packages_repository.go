@@ -4,9 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"godating-dealls/internal/infra/mysql/record"
+	"time"
 )
 
 type PurchasePackagesRepository interface {
 	PurchasePackagesByAccount(ctx context.Context, tx *sql.Tx, record record.AccountPremiumRecord) error
 	FindAccountPremiumByAccountId(ctx context.Context, tx *sql.Tx, accountId int64) (record.AccountPremiumRecord, error)
+	FindActiveAccountPremiumsFromDB(ctx context.Context, tx *sql.Tx, accountId int64, now time.Time) ([]record.AccountPremiumRecord, error)
+	FindLapsedPremiumAccountIdsFromDB(ctx context.Context, tx *sql.Tx, now time.Time) ([]int64, error)
 }
@@ -4,7 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"godating-dealls/internal/infra/mysql/record"
+	"time"
 )
 
 type PurchasePackagesRepositoryImpl struct {
@@ -62,3 +64,65 @@ func (p PurchasePackagesRepositoryImpl) FindAccountPremiumByAccountId(ctx contex
 
 	return record.AccountPremiumRecord{}, errors.New("no account premium found")
 }
+
+// FindActiveAccountPremiumsFromDB returns the subscriptions running at now, the one lasting longest first
+func (p PurchasePackagesRepositoryImpl) FindActiveAccountPremiumsFromDB(ctx context.Context, tx *sql.Tx, accountId int64, now time.Time) ([]record.AccountPremiumRecord, error) {
+	query := `SELECT purchase_id, account_id, package_id, purchase_date, expiry_date, unlimited_swipes_active, status FROM account_premiums
+		WHERE account_id = ? AND status = TRUE AND expiry_date > ? ORDER BY expiry_date DESC`
+
+	rows, err := tx.QueryContext(ctx, query, accountId, now)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var premiums []record.AccountPremiumRecord
+	for rows.Next() {
+		var premium record.AccountPremiumRecord
+		if err := rows.Scan(
+			&premium.PurchaseID,
+			&premium.AccountID,
+			&premium.PackageID,
+			&premium.PurchaseDate,
+			&premium.ExpiryDate,
+			&premium.UnlimitedSwipesActive,
+			&premium.Status,
+		); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		premiums = append(premiums, premium)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return premiums, nil
+}
+
+// FindLapsedPremiumAccountIdsFromDB returns the accounts still flagged verified without a subscription running at now
+func (p PurchasePackagesRepositoryImpl) FindLapsedPremiumAccountIdsFromDB(ctx context.Context, tx *sql.Tx, now time.Time) ([]int64, error) {
+	query := `SELECT a.account_id FROM accounts a WHERE a.verified = TRUE AND NOT EXISTS (
+		SELECT 1 FROM account_premiums ap WHERE ap.account_id = a.account_id AND ap.status = TRUE AND ap.expiry_date > ?)`
+
+	rows, err := tx.QueryContext(ctx, query, now)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var accountIds []int64
+	for rows.Next() {
+		var accountId int64
+		if err := rows.Scan(&accountId); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		accountIds = append(accountIds, accountId)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return accountIds, nil
+}
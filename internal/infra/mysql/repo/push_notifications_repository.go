@@ -0,0 +1,20 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+	"time"
+)
+
+type PushNotificationsRepository interface {
+	UpsertDeviceTokenToDB(ctx context.Context, tx *sql.Tx, device record.DeviceTokenRecord) (record.DeviceTokenRecord, error)
+	FindDeviceTokensByAccountIdsFromDB(ctx context.Context, tx *sql.Tx, accountIds []int64) ([]record.DeviceTokenRecord, error)
+	DeleteDeviceTokensFromDB(ctx context.Context, tx *sql.Tx, tokens []string) (int64, error)
+	InsertPushNotificationsToDB(ctx context.Context, tx *sql.Tx, pushes []record.PushNotificationRecord) error
+	FindDuePushNotificationsFromDB(ctx context.Context, tx *sql.Tx, now time.Time, limit int) ([]record.PushNotificationRecord, error)
+	LeasePushNotificationsToDB(ctx context.Context, tx *sql.Tx, pushIds []int64, until time.Time) error
+	UpdatePushNotificationStatusToDB(ctx context.Context, tx *sql.Tx, pushId int64, status string, lastError string) error
+	RetryPushNotificationToDB(ctx context.Context, tx *sql.Tx, pushId int64, nextAttemptAt time.Time, lastError string) error
+	DeletePushNotificationsBeforeFromDB(ctx context.Context, tx *sql.Tx, before time.Time) (int64, error)
+}
@@ -0,0 +1,195 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/record"
+	"strings"
+	"time"
+)
+
+const (
+	deviceTokenColumns      = "device_id, account_id, provider, token, created_at, updated_at"
+	pushNotificationColumns = "push_id, account_id, kind, title, body, data, status, attempts, next_attempt_at, last_error, created_at, sent_at"
+)
+
+type PushNotificationsRepositoryImpl struct {
+	PushNotificationsRepository PushNotificationsRepository
+}
+
+func NewPushNotificationsRepositoryImpl() PushNotificationsRepository {
+	return &PushNotificationsRepositoryImpl{}
+}
+
+// UpsertDeviceTokenToDB a token registered again moves to the account registering it, the device changed hands
+func (p PushNotificationsRepositoryImpl) UpsertDeviceTokenToDB(ctx context.Context, tx *sql.Tx, device record.DeviceTokenRecord) (record.DeviceTokenRecord, error) {
+	query := `INSERT INTO device_tokens (account_id, provider, token) VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE account_id = VALUES(account_id), provider = VALUES(provider), updated_at = CURRENT_TIMESTAMP`
+	if _, err := tx.ExecContext(ctx, query, device.AccountID, device.Provider, device.Token); err != nil {
+		return record.DeviceTokenRecord{}, fmt.Errorf("could not upsert device token: %v", err)
+	}
+
+	var res record.DeviceTokenRecord
+	err := tx.QueryRowContext(ctx, "SELECT "+deviceTokenColumns+" FROM device_tokens WHERE token = ?", device.Token).Scan(
+		&res.DeviceID, &res.AccountID, &res.Provider, &res.Token, &res.CreatedAt, &res.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return record.DeviceTokenRecord{}, err
+		}
+		return record.DeviceTokenRecord{}, fmt.Errorf("could not scan row: %v", err)
+	}
+	return res, nil
+}
+
+func (p PushNotificationsRepositoryImpl) FindDeviceTokensByAccountIdsFromDB(ctx context.Context, tx *sql.Tx, accountIds []int64) ([]record.DeviceTokenRecord, error) {
+	if len(accountIds) == 0 {
+		return nil, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(accountIds)), ", ")
+	query := "SELECT " + deviceTokenColumns + " FROM device_tokens WHERE account_id IN (" + placeholders + ") ORDER BY device_id"
+	args := make([]interface{}, 0, len(accountIds))
+	for _, accountId := range accountIds {
+		args = append(args, accountId)
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var devices []record.DeviceTokenRecord
+	for rows.Next() {
+		var device record.DeviceTokenRecord
+		if err := rows.Scan(&device.DeviceID, &device.AccountID, &device.Provider, &device.Token, &device.CreatedAt, &device.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		devices = append(devices, device)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return devices, nil
+}
+
+func (p PushNotificationsRepositoryImpl) DeleteDeviceTokensFromDB(ctx context.Context, tx *sql.Tx, tokens []string) (int64, error) {
+	if len(tokens) == 0 {
+		return 0, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(tokens)), ", ")
+	args := make([]interface{}, 0, len(tokens))
+	for _, token := range tokens {
+		args = append(args, token)
+	}
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM device_tokens WHERE token IN ("+placeholders+")", args...)
+	if err != nil {
+		return 0, fmt.Errorf("could not delete device tokens: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not retrieve rows affected: %v", err)
+	}
+	return rowsAffected, nil
+}
+
+func (p PushNotificationsRepositoryImpl) InsertPushNotificationsToDB(ctx context.Context, tx *sql.Tx, pushes []record.PushNotificationRecord) error {
+	if len(pushes) == 0 {
+		return nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("(?, ?, ?, ?, ?, ?), ", len(pushes)), ", ")
+	query := "INSERT INTO push_notifications (account_id, kind, title, body, data, next_attempt_at) VALUES " + placeholders
+	args := make([]interface{}, 0, len(pushes)*6)
+	for _, push := range pushes {
+		args = append(args, push.AccountID, push.Kind, push.Title, push.Body, push.Data, push.NextAttemptAt)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("could not insert push notifications: %v", err)
+	}
+	return nil
+}
+
+// FindDuePushNotificationsFromDB locks the pending pushes due by now, oldest first, until they are leased
+func (p PushNotificationsRepositoryImpl) FindDuePushNotificationsFromDB(ctx context.Context, tx *sql.Tx, now time.Time, limit int) ([]record.PushNotificationRecord, error) {
+	query := "SELECT " + pushNotificationColumns + " FROM push_notifications WHERE status = 'pending' AND next_attempt_at <= ? ORDER BY push_id LIMIT ? FOR UPDATE"
+	rows, err := tx.QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var pushes []record.PushNotificationRecord
+	for rows.Next() {
+		var push record.PushNotificationRecord
+		if err := rows.Scan(&push.PushID, &push.AccountID, &push.Kind, &push.Title, &push.Body, &push.Data, &push.Status,
+			&push.Attempts, &push.NextAttemptAt, &push.LastError, &push.CreatedAt, &push.SentAt); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		pushes = append(pushes, push)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return pushes, nil
+}
+
+// LeasePushNotificationsToDB counts an attempt and keeps the pushes from the other workers until the lease ends,
+// a worker stopping mid delivery leaves them to be picked up again then
+func (p PushNotificationsRepositoryImpl) LeasePushNotificationsToDB(ctx context.Context, tx *sql.Tx, pushIds []int64, until time.Time) error {
+	if len(pushIds) == 0 {
+		return nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(pushIds)), ", ")
+	args := []interface{}{until}
+	for _, pushId := range pushIds {
+		args = append(args, pushId)
+	}
+
+	query := "UPDATE push_notifications SET attempts = attempts + 1, next_attempt_at = ? WHERE push_id IN (" + placeholders + ")"
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("could not lease push notifications: %v", err)
+	}
+	return nil
+}
+
+// UpdatePushNotificationStatusToDB ends the delivery of a push, sent_at is set when it was sent
+func (p PushNotificationsRepositoryImpl) UpdatePushNotificationStatusToDB(ctx context.Context, tx *sql.Tx, pushId int64, status string, lastError string) error {
+	query := `UPDATE push_notifications SET status = ?, last_error = NULLIF(?, ''),
+		sent_at = IF(? = 'sent', CURRENT_TIMESTAMP, NULL) WHERE push_id = ?`
+	if _, err := tx.ExecContext(ctx, query, status, lastError, status, pushId); err != nil {
+		return fmt.Errorf("could not update push notification: %v", err)
+	}
+	return nil
+}
+
+func (p PushNotificationsRepositoryImpl) RetryPushNotificationToDB(ctx context.Context, tx *sql.Tx, pushId int64, nextAttemptAt time.Time, lastError string) error {
+	query := "UPDATE push_notifications SET next_attempt_at = ?, last_error = ? WHERE push_id = ?"
+	if _, err := tx.ExecContext(ctx, query, nextAttemptAt, lastError, pushId); err != nil {
+		return fmt.Errorf("could not update push notification: %v", err)
+	}
+	return nil
+}
+
+// DeletePushNotificationsBeforeFromDB removes the delivered, skipped and failed pushes created before the time
+func (p PushNotificationsRepositoryImpl) DeletePushNotificationsBeforeFromDB(ctx context.Context, tx *sql.Tx, before time.Time) (int64, error) {
+	result, err := tx.ExecContext(ctx, "DELETE FROM push_notifications WHERE status != 'pending' AND created_at < ?", before)
+	if err != nil {
+		return 0, fmt.Errorf("could not delete push notifications: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not retrieve rows affected: %v", err)
+	}
+	return rowsAffected, nil
+}
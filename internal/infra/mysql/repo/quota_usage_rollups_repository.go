@@ -0,0 +1,13 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+	"time"
+)
+
+type QuotaUsageRollupsRepository interface {
+	RollupQuotaUsageToDB(ctx context.Context, tx *sql.Tx, day time.Time) (int64, error)
+	FindQuotaUsageRollupsFromDB(ctx context.Context, tx *sql.Tx, from time.Time, to time.Time) ([]record.QuotaUsageRollupRecord, error)
+}
@@ -0,0 +1,83 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/record"
+	"time"
+)
+
+type QuotaUsageRollupsRepositoryImpl struct {
+	QuotaUsageRollupsRepository QuotaUsageRollupsRepository
+}
+
+func NewQuotaUsageRollupsRepositoryImpl() QuotaUsageRollupsRepository {
+	return &QuotaUsageRollupsRepositoryImpl{}
+}
+
+// RollupQuotaUsageToDB summarises one day of daily_quotas per tier, an unlimited quota (-1) marks the premium tier that day
+func (q QuotaUsageRollupsRepositoryImpl) RollupQuotaUsageToDB(ctx context.Context, tx *sql.Tx, day time.Time) (int64, error) {
+	query := `INSERT INTO quota_usage_rollups (rollup_date, tier, accounts, active_accounts, total_swipes, total_likes, exhausted_accounts)
+		SELECT dq.date,
+			CASE WHEN dq.total_quota < 0 THEN 'premium' ELSE 'free' END AS tier,
+			COUNT(*),
+			SUM(dq.swipe_count > 0),
+			SUM(dq.swipe_count),
+			COALESCE(SUM(l.likes), 0),
+			SUM(dq.total_quota = 0)
+		FROM daily_quotas dq
+		LEFT JOIN (SELECT account_id, COUNT(*) AS likes FROM swipes WHERE swipe_date = ? AND action = 'LIKED' GROUP BY account_id) l
+			ON l.account_id = dq.account_id
+		WHERE dq.date = ?
+		GROUP BY dq.date, tier
+		ON DUPLICATE KEY UPDATE accounts = VALUES(accounts), active_accounts = VALUES(active_accounts),
+			total_swipes = VALUES(total_swipes), total_likes = VALUES(total_likes), exhausted_accounts = VALUES(exhausted_accounts)`
+
+	date := day.Format("2006-01-02")
+	result, err := tx.ExecContext(ctx, query, date, date)
+	if err != nil {
+		return 0, fmt.Errorf("could not rollup quota usage: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not retrieve rows affected: %v", err)
+	}
+	return rowsAffected, nil
+}
+
+func (q QuotaUsageRollupsRepositoryImpl) FindQuotaUsageRollupsFromDB(ctx context.Context, tx *sql.Tx, from time.Time, to time.Time) ([]record.QuotaUsageRollupRecord, error) {
+	query := `SELECT rollup_date, tier, accounts, active_accounts, total_swipes, total_likes, exhausted_accounts, created_at
+		FROM quota_usage_rollups WHERE rollup_date BETWEEN ? AND ? ORDER BY rollup_date DESC, tier`
+
+	rows, err := tx.QueryContext(ctx, query, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var rollups []record.QuotaUsageRollupRecord
+	for rows.Next() {
+		var rollup record.QuotaUsageRollupRecord
+		if err := rows.Scan(
+			&rollup.RollupDate,
+			&rollup.Tier,
+			&rollup.Accounts,
+			&rollup.ActiveAccounts,
+			&rollup.TotalSwipes,
+			&rollup.TotalLikes,
+			&rollup.ExhaustedAccounts,
+			&rollup.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		rollups = append(rollups, rollup)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return rollups, nil
+}
@@ -0,0 +1,23 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+type RecoveryRepository interface {
+	ReplaceRecoveryContactsToDB(ctx context.Context, tx *sql.Tx, accountId int64, contactAccountIds []int64, threshold int) error
+	FindRecoveryContactsFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.RecoveryContactRecord, error)
+	FindRecoverySettingFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (record.RecoverySettingRecord, error)
+	InsertRecoveryRequestToDB(ctx context.Context, tx *sql.Tx, record record.RecoveryRequestRecord) (record.RecoveryRequestRecord, error)
+	FindRecoveryRequestByTokenHashFromDB(ctx context.Context, tx *sql.Tx, tokenHash string) (record.RecoveryRequestRecord, error)
+	FindRecoveryRequestByIdFromDB(ctx context.Context, tx *sql.Tx, requestId int64) (record.RecoveryRequestRecord, error)
+	FindPendingRecoveryRequestsFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.RecoveryRequestRecord, error)
+	FindPendingRecoveryRequestsForContactFromDB(ctx context.Context, tx *sql.Tx, contactAccountId int64) ([]record.RecoveryRequestRecord, error)
+	IncrementRecoveryFailedAttemptsToDB(ctx context.Context, tx *sql.Tx, requestId int64) (int, error)
+	CloseRecoveryRequestToDB(ctx context.Context, tx *sql.Tx, requestId int64, status string) error
+	ClosePendingRecoveryRequestsToDB(ctx context.Context, tx *sql.Tx, accountId int64, status string) error
+	InsertRecoveryApprovalToDB(ctx context.Context, tx *sql.Tx, record record.RecoveryApprovalRecord) error
+	FindRecoveryApprovalsFromDB(ctx context.Context, tx *sql.Tx, requestId int64) ([]record.RecoveryApprovalRecord, error)
+}
@@ -0,0 +1,259 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+const recoveryRequestColumns = "request_id, account_id, token_hash, status, approvals_required, failed_attempts, created_at, available_at, expires_at, closed_at"
+
+type RecoveryRepositoryImpl struct {
+	RecoveryRepository RecoveryRepository
+}
+
+func NewRecoveryRepositoryImpl() RecoveryRepository {
+	return &RecoveryRepositoryImpl{}
+}
+
+// ReplaceRecoveryContactsToDB swaps the whole contact list and threshold of the account
+func (r RecoveryRepositoryImpl) ReplaceRecoveryContactsToDB(ctx context.Context, tx *sql.Tx, accountId int64, contactAccountIds []int64, threshold int) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM recovery_contacts WHERE account_id = ?", accountId)
+	if err != nil {
+		return fmt.Errorf("could not delete recovery contacts: %v", err)
+	}
+
+	for _, contactAccountId := range contactAccountIds {
+		_, err = tx.ExecContext(ctx, "INSERT INTO recovery_contacts (account_id, contact_account_id) VALUES (?, ?)", accountId, contactAccountId)
+		if err != nil {
+			return fmt.Errorf("could not insert recovery contact: %v", err)
+		}
+	}
+
+	query := "INSERT INTO recovery_settings (account_id, threshold) VALUES (?, ?) ON DUPLICATE KEY UPDATE threshold = VALUES(threshold), updated_at = CURRENT_TIMESTAMP"
+	_, err = tx.ExecContext(ctx, query, accountId, threshold)
+	if err != nil {
+		return fmt.Errorf("could not save recovery settings: %v", err)
+	}
+	return nil
+}
+
+func (r RecoveryRepositoryImpl) FindRecoveryContactsFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.RecoveryContactRecord, error) {
+	query := "SELECT account_id, contact_account_id, created_at FROM recovery_contacts WHERE account_id = ? ORDER BY created_at"
+	rows, err := tx.QueryContext(ctx, query, accountId)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var contacts []record.RecoveryContactRecord
+	for rows.Next() {
+		var contact record.RecoveryContactRecord
+		if err := rows.Scan(&contact.AccountID, &contact.ContactAccountID, &contact.CreatedAt); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		contacts = append(contacts, contact)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return contacts, nil
+}
+
+// FindRecoverySettingFromDB returns a zero threshold when the account never nominated contacts
+func (r RecoveryRepositoryImpl) FindRecoverySettingFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (record.RecoverySettingRecord, error) {
+	query := "SELECT account_id, threshold, updated_at FROM recovery_settings WHERE account_id = ?"
+	var setting record.RecoverySettingRecord
+	err := tx.QueryRowContext(ctx, query, accountId).Scan(&setting.AccountID, &setting.Threshold, &setting.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return record.RecoverySettingRecord{AccountID: accountId}, nil
+		}
+		return record.RecoverySettingRecord{}, fmt.Errorf("could not scan row: %v", err)
+	}
+	return setting, nil
+}
+
+func (r RecoveryRepositoryImpl) InsertRecoveryRequestToDB(ctx context.Context, tx *sql.Tx, record record.RecoveryRequestRecord) (record.RecoveryRequestRecord, error) {
+	query := "INSERT INTO recovery_requests (account_id, token_hash, status, approvals_required, created_at, available_at, expires_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	result, err := tx.ExecContext(ctx, query,
+		record.AccountID,
+		record.TokenHash,
+		record.Status,
+		record.ApprovalsRequired,
+		record.CreatedAt,
+		record.AvailableAt,
+		record.ExpiresAt,
+	)
+	if err != nil {
+		return record, fmt.Errorf("could not insert recovery request: %v", err)
+	}
+
+	requestId, err := result.LastInsertId()
+	if err != nil {
+		return record, fmt.Errorf("could not retrieve last insert id: %v", err)
+	}
+
+	record.RequestID = requestId
+	return record, nil
+}
+
+func (r RecoveryRepositoryImpl) FindRecoveryRequestByTokenHashFromDB(ctx context.Context, tx *sql.Tx, tokenHash string) (record.RecoveryRequestRecord, error) {
+	query := "SELECT " + recoveryRequestColumns + " FROM recovery_requests WHERE token_hash = ? FOR UPDATE"
+	return scanRecoveryRequest(tx.QueryRowContext(ctx, query, tokenHash))
+}
+
+func (r RecoveryRepositoryImpl) FindRecoveryRequestByIdFromDB(ctx context.Context, tx *sql.Tx, requestId int64) (record.RecoveryRequestRecord, error) {
+	query := "SELECT " + recoveryRequestColumns + " FROM recovery_requests WHERE request_id = ?"
+	return scanRecoveryRequest(tx.QueryRowContext(ctx, query, requestId))
+}
+
+func (r RecoveryRepositoryImpl) FindPendingRecoveryRequestsFromDB(ctx context.Context, tx *sql.Tx, accountId int64) ([]record.RecoveryRequestRecord, error) {
+	query := "SELECT " + recoveryRequestColumns + " FROM recovery_requests WHERE account_id = ? AND status = 'pending' AND expires_at > NOW() ORDER BY created_at"
+	return queryRecoveryRequests(ctx, tx, query, accountId)
+}
+
+// FindPendingRecoveryRequestsForContactFromDB returns the open requests of every account that nominated the contact
+func (r RecoveryRepositoryImpl) FindPendingRecoveryRequestsForContactFromDB(ctx context.Context, tx *sql.Tx, contactAccountId int64) ([]record.RecoveryRequestRecord, error) {
+	query := "SELECT rr.request_id, rr.account_id, rr.token_hash, rr.status, rr.approvals_required, rr.failed_attempts, rr.created_at, rr.available_at, rr.expires_at, rr.closed_at " +
+		"FROM recovery_requests rr INNER JOIN recovery_contacts rc ON rc.account_id = rr.account_id " +
+		"WHERE rc.contact_account_id = ? AND rr.status = 'pending' AND rr.expires_at > NOW() ORDER BY rr.created_at"
+	return queryRecoveryRequests(ctx, tx, query, contactAccountId)
+}
+
+// IncrementRecoveryFailedAttemptsToDB counts a wrong completion and returns the new count
+func (r RecoveryRepositoryImpl) IncrementRecoveryFailedAttemptsToDB(ctx context.Context, tx *sql.Tx, requestId int64) (int, error) {
+	_, err := tx.ExecContext(ctx, "UPDATE recovery_requests SET failed_attempts = failed_attempts + 1 WHERE request_id = ?", requestId)
+	if err != nil {
+		return 0, fmt.Errorf("could not update recovery request: %v", err)
+	}
+
+	var attempts int
+	err = tx.QueryRowContext(ctx, "SELECT failed_attempts FROM recovery_requests WHERE request_id = ?", requestId).Scan(&attempts)
+	if err != nil {
+		return 0, fmt.Errorf("could not scan row: %v", err)
+	}
+	return attempts, nil
+}
+
+func (r RecoveryRepositoryImpl) CloseRecoveryRequestToDB(ctx context.Context, tx *sql.Tx, requestId int64, status string) error {
+	query := "UPDATE recovery_requests SET status = ?, closed_at = CURRENT_TIMESTAMP WHERE request_id = ? AND status = 'pending'"
+	result, err := tx.ExecContext(ctx, query, status, requestId)
+	if err != nil {
+		return fmt.Errorf("could not close recovery request: %v", err)
+	}
+
+	rowCount, err := result.RowsAffected()
+	if err != nil || rowCount == 0 {
+		return errors.New("recovery request is not pending")
+	}
+	return nil
+}
+
+func (r RecoveryRepositoryImpl) ClosePendingRecoveryRequestsToDB(ctx context.Context, tx *sql.Tx, accountId int64, status string) error {
+	query := "UPDATE recovery_requests SET status = ?, closed_at = CURRENT_TIMESTAMP WHERE account_id = ? AND status = 'pending'"
+	_, err := tx.ExecContext(ctx, query, status, accountId)
+	if err != nil {
+		return fmt.Errorf("could not close recovery requests: %v", err)
+	}
+	return nil
+}
+
+// InsertRecoveryApprovalToDB returns a DuplicateKeyError when the contact already approved the request
+func (r RecoveryRepositoryImpl) InsertRecoveryApprovalToDB(ctx context.Context, tx *sql.Tx, record record.RecoveryApprovalRecord) error {
+	query := "INSERT INTO recovery_approvals (request_id, contact_account_id, code_hash) VALUES (?, ?, ?)"
+	_, err := tx.ExecContext(ctx, query, record.RequestID, record.ContactAccountID, record.CodeHash)
+	if err != nil {
+		var duplicate *DuplicateKeyError
+		if errors.As(asDuplicateKeyError(err), &duplicate) {
+			return duplicate
+		}
+		return fmt.Errorf("could not insert recovery approval: %v", err)
+	}
+	return nil
+}
+
+func (r RecoveryRepositoryImpl) FindRecoveryApprovalsFromDB(ctx context.Context, tx *sql.Tx, requestId int64) ([]record.RecoveryApprovalRecord, error) {
+	query := "SELECT request_id, contact_account_id, code_hash, approved_at FROM recovery_approvals WHERE request_id = ?"
+	rows, err := tx.QueryContext(ctx, query, requestId)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var approvals []record.RecoveryApprovalRecord
+	for rows.Next() {
+		var approval record.RecoveryApprovalRecord
+		if err := rows.Scan(&approval.RequestID, &approval.ContactAccountID, &approval.CodeHash, &approval.ApprovedAt); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		approvals = append(approvals, approval)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return approvals, nil
+}
+
+func scanRecoveryRequest(row *sql.Row) (record.RecoveryRequestRecord, error) {
+	var request record.RecoveryRequestRecord
+	err := row.Scan(
+		&request.RequestID,
+		&request.AccountID,
+		&request.TokenHash,
+		&request.Status,
+		&request.ApprovalsRequired,
+		&request.FailedAttempts,
+		&request.CreatedAt,
+		&request.AvailableAt,
+		&request.ExpiresAt,
+		&request.ClosedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return record.RecoveryRequestRecord{}, errors.New("recovery request not found")
+		}
+		return record.RecoveryRequestRecord{}, fmt.Errorf("could not scan row: %v", err)
+	}
+	return request, nil
+}
+
+func queryRecoveryRequests(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]record.RecoveryRequestRecord, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var requests []record.RecoveryRequestRecord
+	for rows.Next() {
+		var request record.RecoveryRequestRecord
+		if err := rows.Scan(
+			&request.RequestID,
+			&request.AccountID,
+			&request.TokenHash,
+			&request.Status,
+			&request.ApprovalsRequired,
+			&request.FailedAttempts,
+			&request.CreatedAt,
+			&request.AvailableAt,
+			&request.ExpiresAt,
+			&request.ClosedAt,
+		); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		requests = append(requests, request)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return requests, nil
+}
@@ -0,0 +1,13 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+type SafetySettingsRepository interface {
+	UpsertSafetySettingToDB(ctx context.Context, tx *sql.Tx, record record.SafetySettingRecord) error
+	FindSafetySettingFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (record.SafetySettingRecord, error)
+	CountLateNightModeAccountsFromDB(ctx context.Context, tx *sql.Tx, accountIds []int64) (int, error)
+}
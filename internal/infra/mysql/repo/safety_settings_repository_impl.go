@@ -0,0 +1,62 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/record"
+	"strings"
+)
+
+type SafetySettingsRepositoryImpl struct {
+	SafetySettingsRepository SafetySettingsRepository
+}
+
+func NewSafetySettingsRepositoryImpl() SafetySettingsRepository {
+	return &SafetySettingsRepositoryImpl{}
+}
+
+func (s SafetySettingsRepositoryImpl) UpsertSafetySettingToDB(ctx context.Context, tx *sql.Tx, record record.SafetySettingRecord) error {
+	query := "INSERT INTO safety_settings (account_id, late_night_mode) VALUES (?, ?) ON DUPLICATE KEY UPDATE late_night_mode = VALUES(late_night_mode), updated_at = CURRENT_TIMESTAMP"
+	_, err := tx.ExecContext(ctx, query, record.AccountID, record.LateNightMode)
+	if err != nil {
+		return fmt.Errorf("could not save safety settings: %v", err)
+	}
+	return nil
+}
+
+// FindSafetySettingFromDB returns every setting off when the account never saved its safety settings
+func (s SafetySettingsRepositoryImpl) FindSafetySettingFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (record.SafetySettingRecord, error) {
+	query := "SELECT account_id, late_night_mode, updated_at FROM safety_settings WHERE account_id = ?"
+	var setting record.SafetySettingRecord
+	err := tx.QueryRowContext(ctx, query, accountId).Scan(&setting.AccountID, &setting.LateNightMode, &setting.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return record.SafetySettingRecord{AccountID: accountId}, nil
+		}
+		return record.SafetySettingRecord{}, fmt.Errorf("could not scan row: %v", err)
+	}
+	return setting, nil
+}
+
+// CountLateNightModeAccountsFromDB counts the accounts of the list that turned the late night mode on
+func (s SafetySettingsRepositoryImpl) CountLateNightModeAccountsFromDB(ctx context.Context, tx *sql.Tx, accountIds []int64) (int, error) {
+	if len(accountIds) == 0 {
+		return 0, nil
+	}
+
+	placeholders := make([]string, 0, len(accountIds))
+	args := make([]interface{}, 0, len(accountIds))
+	for _, accountId := range accountIds {
+		placeholders = append(placeholders, "?")
+		args = append(args, accountId)
+	}
+
+	query := "SELECT COUNT(*) FROM safety_settings WHERE late_night_mode = TRUE AND account_id IN (" + strings.Join(placeholders, ", ") + ")"
+	var count int
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("could not count late night mode accounts: %v", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,16 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+)
+
+type SmartPhotosRepository interface {
+	UpsertSmartPhotoSettingToDB(ctx context.Context, tx *sql.Tx, accountId int64, enabled bool) error
+	FindSmartPhotoSettingFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (record.SmartPhotoSettingRecord, error)
+	FindSmartPhotoAccountsFromDB(ctx context.Context, tx *sql.Tx, accountIds []int64) ([]int64, error)
+	UpsertPhotoImpressionToDB(ctx context.Context, tx *sql.Tx, impression record.PhotoImpressionRecord) error
+	UpdatePhotoImpressionLikedToDB(ctx context.Context, tx *sql.Tx, ownerAccountId int64, viewerAccountId int64, liked bool) error
+	FindPhotoPerformanceFromDB(ctx context.Context, tx *sql.Tx, ownerAccountId int64) ([]record.PhotoPerformanceRecord, error)
+}
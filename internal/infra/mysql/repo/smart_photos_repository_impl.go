@@ -0,0 +1,132 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/record"
+	"strings"
+)
+
+type SmartPhotosRepositoryImpl struct {
+	SmartPhotosRepository SmartPhotosRepository
+}
+
+func NewSmartPhotosRepositoryImpl() SmartPhotosRepository {
+	return &SmartPhotosRepositoryImpl{}
+}
+
+func (s SmartPhotosRepositoryImpl) UpsertSmartPhotoSettingToDB(ctx context.Context, tx *sql.Tx, accountId int64, enabled bool) error {
+	query := `INSERT INTO smart_photo_settings (account_id, enabled) VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE enabled = VALUES(enabled), updated_at = CURRENT_TIMESTAMP`
+
+	_, err := tx.ExecContext(ctx, query, accountId, enabled)
+	if err != nil {
+		return fmt.Errorf("could not save smart photo setting: %v", err)
+	}
+	return nil
+}
+
+func (s SmartPhotosRepositoryImpl) FindSmartPhotoSettingFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (record.SmartPhotoSettingRecord, error) {
+	query := "SELECT account_id, enabled, updated_at FROM smart_photo_settings WHERE account_id = ?"
+	var setting record.SmartPhotoSettingRecord
+	err := tx.QueryRowContext(ctx, query, accountId).Scan(&setting.AccountID, &setting.Enabled, &setting.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return record.SmartPhotoSettingRecord{}, err
+		}
+		return record.SmartPhotoSettingRecord{}, fmt.Errorf("could not scan row: %v", err)
+	}
+	return setting, nil
+}
+
+// FindSmartPhotoAccountsFromDB returns the accounts among accountIds that turned smart photos on
+func (s SmartPhotosRepositoryImpl) FindSmartPhotoAccountsFromDB(ctx context.Context, tx *sql.Tx, accountIds []int64) ([]int64, error) {
+	if len(accountIds) == 0 {
+		return nil, nil
+	}
+
+	placeholders := make([]string, 0, len(accountIds))
+	args := make([]interface{}, 0, len(accountIds))
+	for _, accountId := range accountIds {
+		placeholders = append(placeholders, "?")
+		args = append(args, accountId)
+	}
+
+	query := "SELECT account_id FROM smart_photo_settings WHERE enabled = TRUE AND account_id IN (" + strings.Join(placeholders, ", ") + ")"
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var res []int64
+	for rows.Next() {
+		var accountId int64
+		if err := rows.Scan(&accountId); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		res = append(res, accountId)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return res, nil
+}
+
+// UpsertPhotoImpressionToDB keeps one impression per viewer, a like only counts for the photo it was given to
+// so it is reset when the viewer is shown another photo (MySQL assigns left to right, liked reads the old photo_id)
+func (s SmartPhotosRepositoryImpl) UpsertPhotoImpressionToDB(ctx context.Context, tx *sql.Tx, impression record.PhotoImpressionRecord) error {
+	query := `INSERT INTO photo_impressions (owner_account_id, viewer_account_id, photo_id) VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE liked = IF(photo_id = VALUES(photo_id), liked, FALSE), photo_id = VALUES(photo_id), shown_at = CURRENT_TIMESTAMP`
+
+	_, err := tx.ExecContext(ctx, query, impression.OwnerAccountID, impression.ViewerAccountID, impression.PhotoID)
+	if err != nil {
+		return fmt.Errorf("could not save photo impression: %v", err)
+	}
+	return nil
+}
+
+func (s SmartPhotosRepositoryImpl) UpdatePhotoImpressionLikedToDB(ctx context.Context, tx *sql.Tx, ownerAccountId int64, viewerAccountId int64, liked bool) error {
+	query := "UPDATE photo_impressions SET liked = ? WHERE owner_account_id = ? AND viewer_account_id = ?"
+	_, err := tx.ExecContext(ctx, query, liked, ownerAccountId, viewerAccountId)
+	if err != nil {
+		return fmt.Errorf("could not update photo impression: %v", err)
+	}
+	return nil
+}
+
+// FindPhotoPerformanceFromDB counts per current photo of the owner, a photo never shown first has 0 impressions.
+// Trashed photos are left out, their counts come back with them when restored
+func (s SmartPhotosRepositoryImpl) FindPhotoPerformanceFromDB(ctx context.Context, tx *sql.Tx, ownerAccountId int64) ([]record.PhotoPerformanceRecord, error) {
+	query := `SELECT p.photo_id, COUNT(i.viewer_account_id), COALESCE(SUM(i.liked), 0)
+		FROM profile_photos p
+		LEFT JOIN photo_impressions i ON i.photo_id = p.photo_id
+		WHERE p.account_id = ? AND p.deleted_at IS NULL
+		GROUP BY p.photo_id, p.position
+		ORDER BY p.position`
+
+	rows, err := tx.QueryContext(ctx, query, ownerAccountId)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var res []record.PhotoPerformanceRecord
+	for rows.Next() {
+		var performance record.PhotoPerformanceRecord
+		if err := rows.Scan(&performance.PhotoID, &performance.Impressions, &performance.Likes); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		res = append(res, performance)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,17 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"godating-dealls/internal/infra/mysql/record"
+	"time"
+)
+
+type StatusMessagesRepository interface {
+	InsertStatusMessageToDB(ctx context.Context, tx *sql.Tx, message record.StatusMessageRecord) (record.StatusMessageRecord, error)
+	UpdateStatusMessageToDB(ctx context.Context, tx *sql.Tx, message record.StatusMessageRecord) error
+	DeleteStatusMessageToDB(ctx context.Context, tx *sql.Tx, messageId int64) (bool, error)
+	FindStatusMessageByIdFromDB(ctx context.Context, tx *sql.Tx, messageId int64) (record.StatusMessageRecord, error)
+	FindStatusMessagesFromDB(ctx context.Context, tx *sql.Tx) ([]record.StatusMessageRecord, error)
+	FindUnendedStatusMessagesFromDB(ctx context.Context, tx *sql.Tx, now time.Time) ([]record.StatusMessageRecord, error)
+}
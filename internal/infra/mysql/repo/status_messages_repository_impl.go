@@ -0,0 +1,123 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/record"
+	"time"
+)
+
+const statusMessageColumns = "message_id, severity, title, message, starts_at, ends_at, created_at, updated_at"
+
+type StatusMessagesRepositoryImpl struct {
+	StatusMessagesRepository StatusMessagesRepository
+}
+
+func NewStatusMessagesRepositoryImpl() StatusMessagesRepository {
+	return &StatusMessagesRepositoryImpl{}
+}
+
+func (s StatusMessagesRepositoryImpl) InsertStatusMessageToDB(ctx context.Context, tx *sql.Tx, message record.StatusMessageRecord) (record.StatusMessageRecord, error) {
+	query := "INSERT INTO status_messages (severity, title, message, starts_at, ends_at) VALUES (?, ?, ?, ?, ?)"
+	result, err := tx.ExecContext(ctx, query, message.Severity, message.Title, message.Message, message.StartsAt, message.EndsAt)
+	if err != nil {
+		return record.StatusMessageRecord{}, fmt.Errorf("could not insert status message: %v", err)
+	}
+
+	messageId, err := result.LastInsertId()
+	if err != nil {
+		return record.StatusMessageRecord{}, fmt.Errorf("could not retrieve last insert id: %v", err)
+	}
+	return s.FindStatusMessageByIdFromDB(ctx, tx, messageId)
+}
+
+func (s StatusMessagesRepositoryImpl) UpdateStatusMessageToDB(ctx context.Context, tx *sql.Tx, message record.StatusMessageRecord) error {
+	query := `UPDATE status_messages SET severity = ?, title = ?, message = ?, starts_at = ?, ends_at = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE message_id = ?`
+
+	_, err := tx.ExecContext(ctx, query, message.Severity, message.Title, message.Message, message.StartsAt, message.EndsAt, message.MessageID)
+	if err != nil {
+		return fmt.Errorf("could not update status message: %v", err)
+	}
+	return nil
+}
+
+func (s StatusMessagesRepositoryImpl) DeleteStatusMessageToDB(ctx context.Context, tx *sql.Tx, messageId int64) (bool, error) {
+	result, err := tx.ExecContext(ctx, "DELETE FROM status_messages WHERE message_id = ?", messageId)
+	if err != nil {
+		return false, fmt.Errorf("could not delete status message: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("could not retrieve rows affected: %v", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+func (s StatusMessagesRepositoryImpl) FindStatusMessageByIdFromDB(ctx context.Context, tx *sql.Tx, messageId int64) (record.StatusMessageRecord, error) {
+	query := "SELECT " + statusMessageColumns + " FROM status_messages WHERE message_id = ?"
+	var message record.StatusMessageRecord
+	err := tx.QueryRowContext(ctx, query, messageId).Scan(
+		&message.MessageID,
+		&message.Severity,
+		&message.Title,
+		&message.Message,
+		&message.StartsAt,
+		&message.EndsAt,
+		&message.CreatedAt,
+		&message.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return record.StatusMessageRecord{}, err
+		}
+		return record.StatusMessageRecord{}, fmt.Errorf("could not scan row: %v", err)
+	}
+	return message, nil
+}
+
+func (s StatusMessagesRepositoryImpl) FindStatusMessagesFromDB(ctx context.Context, tx *sql.Tx) ([]record.StatusMessageRecord, error) {
+	query := "SELECT " + statusMessageColumns + " FROM status_messages ORDER BY starts_at DESC"
+	return queryStatusMessages(ctx, tx, query)
+}
+
+// FindUnendedStatusMessagesFromDB returns the live and the scheduled messages, those whose end is not yet reached
+func (s StatusMessagesRepositoryImpl) FindUnendedStatusMessagesFromDB(ctx context.Context, tx *sql.Tx, now time.Time) ([]record.StatusMessageRecord, error) {
+	query := "SELECT " + statusMessageColumns + " FROM status_messages WHERE ends_at IS NULL OR ends_at > ? ORDER BY starts_at"
+	return queryStatusMessages(ctx, tx, query, now)
+}
+
+func queryStatusMessages(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]record.StatusMessageRecord, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var messages []record.StatusMessageRecord
+	for rows.Next() {
+		var message record.StatusMessageRecord
+		if err := rows.Scan(
+			&message.MessageID,
+			&message.Severity,
+			&message.Title,
+			&message.Message,
+			&message.StartsAt,
+			&message.EndsAt,
+			&message.CreatedAt,
+			&message.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		messages = append(messages, message)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return messages, nil
+}
@@ -7,6 +7,13 @@ import (
 )
 
 type SwipesRepository interface {
-	InsertSwipesToDB(ctx context.Context, tx *sql.Tx, record record.SwipeRecord) error
+	InsertSwipesToDB(ctx context.Context, tx *sql.Tx, record record.SwipeRecord) (int64, error)
+	UpsertLastSwipeToDB(ctx context.Context, tx *sql.Tx, lastSwipe record.LastSwipeRecord) error
+	FindLastSwipeFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (record.LastSwipeRecord, error)
+	DeleteSwipeFromDB(ctx context.Context, tx *sql.Tx, accountId int64, swipeId int64) error
+	DeleteLastSwipeFromDB(ctx context.Context, tx *sql.Tx, accountId int64) error
 	FindTotalSwipes(ctx context.Context, tx *sql.Tx, accountIdSwipe int64) (record.SwipeActionsRecord, error)
+	InsertSuperLikeToDB(ctx context.Context, tx *sql.Tx, superLike record.SuperLikeRecord) error
+	FindLikesReceivedFromDB(ctx context.Context, tx *sql.Tx, accountId int64, limit int, offset int) ([]record.LikeReceivedRecord, error)
+	CountLikesReceivedFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (int64, error)
 }
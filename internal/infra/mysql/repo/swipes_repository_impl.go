@@ -4,7 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"godating-dealls/internal/common"
+	"godating-dealls/internal/infra/mysql/queries"
 	"godating-dealls/internal/infra/mysql/record"
 )
 
@@ -18,13 +20,70 @@ func NewSwipesRepositoryImpl() SwipesRepository {
 	return &SwipesRepositoryImpl{}
 }
 
-func (s SwipesRepositoryImpl) InsertSwipesToDB(ctx context.Context, tx *sql.Tx, record record.SwipeRecord) error {
+func (s SwipesRepositoryImpl) InsertSwipesToDB(ctx context.Context, tx *sql.Tx, record record.SwipeRecord) (int64, error) {
 	query := `
 		INSERT INTO swipes (account_id, user_id, action, account_id_swipe) 
 		VALUES (?, ?, ?, ?)
 	`
-	_, err := tx.ExecContext(ctx, query, record.AccountID, record.UserID, record.Action, record.AccountIDSwipe)
-	return err
+	result, err := tx.ExecContext(ctx, query, record.AccountID, record.UserID, record.Action, record.AccountIDSwipe)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// UpsertLastSwipeToDB keeps only the latest swipe per account, the swipe date is the one of the swipe row
+func (s SwipesRepositoryImpl) UpsertLastSwipeToDB(ctx context.Context, tx *sql.Tx, lastSwipe record.LastSwipeRecord) error {
+	query := `INSERT INTO last_swipes (account_id, swipe_id, account_id_swipe, action, quota_used, match_id, swipe_date)
+		SELECT ?, ?, ?, ?, ?, ?, sw.swipe_date FROM swipes sw WHERE sw.swipe_id = ?
+		ON DUPLICATE KEY UPDATE swipe_id = VALUES(swipe_id), account_id_swipe = VALUES(account_id_swipe), action = VALUES(action),
+			quota_used = VALUES(quota_used), match_id = VALUES(match_id), swipe_date = VALUES(swipe_date), swiped_at = CURRENT_TIMESTAMP`
+	_, err := tx.ExecContext(ctx, query, lastSwipe.AccountID, lastSwipe.SwipeID, lastSwipe.AccountIDSwipe, lastSwipe.Action,
+		lastSwipe.QuotaUsed, lastSwipe.MatchID, lastSwipe.SwipeID)
+	if err != nil {
+		return fmt.Errorf("could not upsert last swipe: %v", err)
+	}
+	return nil
+}
+
+// FindLastSwipeFromDB locks the row, so two undo requests cannot both undo the same swipe
+func (s SwipesRepositoryImpl) FindLastSwipeFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (record.LastSwipeRecord, error) {
+	query := `SELECT account_id, swipe_id, account_id_swipe, action, quota_used, match_id, swipe_date, swiped_at
+		FROM last_swipes WHERE account_id = ? FOR UPDATE`
+	var lastSwipe record.LastSwipeRecord
+	err := tx.QueryRowContext(ctx, query, accountId).Scan(
+		&lastSwipe.AccountID,
+		&lastSwipe.SwipeID,
+		&lastSwipe.AccountIDSwipe,
+		&lastSwipe.Action,
+		&lastSwipe.QuotaUsed,
+		&lastSwipe.MatchID,
+		&lastSwipe.SwipeDate,
+		&lastSwipe.SwipedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return record.LastSwipeRecord{}, err
+		}
+		return record.LastSwipeRecord{}, fmt.Errorf("could not scan row: %v", err)
+	}
+	return lastSwipe, nil
+}
+
+func (s SwipesRepositoryImpl) DeleteSwipeFromDB(ctx context.Context, tx *sql.Tx, accountId int64, swipeId int64) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM swipes WHERE swipe_id = ? AND account_id = ?", swipeId, accountId)
+	if err != nil {
+		return fmt.Errorf("could not delete swipe: %v", err)
+	}
+	return nil
+}
+
+func (s SwipesRepositoryImpl) DeleteLastSwipeFromDB(ctx context.Context, tx *sql.Tx, accountId int64) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM last_swipes WHERE account_id = ?", accountId)
+	if err != nil {
+		return fmt.Errorf("could not delete last swipe: %v", err)
+	}
+	return nil
 }
 
 func (s SwipesRepositoryImpl) FindTotalSwipes(ctx context.Context, tx *sql.Tx, accountIdSwipe int64) (record.SwipeActionsRecord, error) {
@@ -51,3 +110,50 @@ func (s SwipesRepositoryImpl) FindTotalSwipes(ctx context.Context, tx *sql.Tx, a
 
 	return swipeActions, nil
 }
+
+// InsertSuperLikeToDB returns a DuplicateKeyError when the account already super liked the target
+func (s SwipesRepositoryImpl) InsertSuperLikeToDB(ctx context.Context, tx *sql.Tx, superLike record.SuperLikeRecord) error {
+	query := "INSERT INTO super_likes (account_id, target_account_id, swipe_id) VALUES (?, ?, ?)"
+	_, err := tx.ExecContext(ctx, query, superLike.AccountID, superLike.TargetAccountID, superLike.SwipeID)
+	if err != nil {
+		var duplicate *DuplicateKeyError
+		if errors.As(asDuplicateKeyError(err), &duplicate) {
+			return duplicate
+		}
+		return fmt.Errorf("could not insert super like: %v", err)
+	}
+	return nil
+}
+
+// FindLikesReceivedFromDB pages through the accounts that liked the account, super likes first then the latest like
+func (s SwipesRepositoryImpl) FindLikesReceivedFromDB(ctx context.Context, tx *sql.Tx, accountId int64, limit int, offset int) ([]record.LikeReceivedRecord, error) {
+	rows, err := tx.QueryContext(ctx, queries.FindLikesReceivedRecord, accountId, accountId, accountId, accountId, accountId, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var likes []record.LikeReceivedRecord
+	for rows.Next() {
+		var like record.LikeReceivedRecord
+		if err := rows.Scan(&like.AccountID, &like.LikedOn, &like.SuperLiked); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		likes = append(likes, like)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return likes, nil
+}
+
+func (s SwipesRepositoryImpl) CountLikesReceivedFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (int64, error) {
+	var total int64
+	err := tx.QueryRowContext(ctx, queries.CountLikesReceivedRecord, accountId, accountId, accountId, accountId, accountId).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("could not count likes received: %v", err)
+	}
+	return total, nil
+}
@@ -10,8 +10,10 @@ type UserRepository interface {
 	CreateUserToDB(ctx context.Context, tx *sql.Tx, userRecord record.UserRecord) (record.UserRecord, error)
 	FindUserByUserIDFromDB(ctx context.Context, tx *sql.Tx, id int64) bool
 	GetUserByAccountIdFromDB(ctx context.Context, tx *sql.Tx, accountId int64) (record.UserRecord, error)
+	GetUsersByAccountIdsFromDB(ctx context.Context, tx *sql.Tx, accountIds []int64) ([]record.UserRecord, error)
 	GetAllUsersFromDB(ctx context.Context, tx *sql.Tx) ([]record.UserAccountRecord, error)
-	GetAllUsersViewsFromDB(ctx context.Context, verifiedUser bool, accountIdIdentifier int64, tx *sql.Tx) ([]record.UserAccountRecord, error)
-	GetAllUsersNextViewsFromDB(ctx context.Context, verifiedUser bool, accountId int64, tx *sql.Tx) ([]record.UserAccountRecord, error)
+	GetAllUsersViewsFromDB(ctx context.Context, verifiedUser bool, accountIdIdentifier int64, radiusKm int, tx *sql.Tx) ([]record.UserAccountRecord, error)
+	GetAllUsersNextViewsFromDB(ctx context.Context, verifiedUser bool, accountId int64, radiusKm int, tx *sql.Tx) ([]record.UserAccountRecord, error)
 	UpdateUserToDB(ctx context.Context, tx *sql.Tx, userRecord record.UserRecord) (record.UserRecord, error)
+	UpdateUserLocationToDB(ctx context.Context, tx *sql.Tx, accountId int64, latitude *float64, longitude *float64) error
 }
@@ -8,6 +8,7 @@ import (
 	"godating-dealls/internal/common"
 	"godating-dealls/internal/infra/mysql/queries"
 	"godating-dealls/internal/infra/mysql/record"
+	"strings"
 )
 
 type UserRepositoryImpl struct {
@@ -92,6 +93,52 @@ func (u UserRepositoryImpl) GetUserByAccountIdFromDB(ctx context.Context, tx *sq
 	return userRecord, nil
 }
 
+// GetUsersByAccountIdsFromDB returns the users of the accounts in one query, the accounts without a user are left out
+func (u UserRepositoryImpl) GetUsersByAccountIdsFromDB(ctx context.Context, tx *sql.Tx, accountIds []int64) ([]record.UserRecord, error) {
+	if len(accountIds) == 0 {
+		return nil, nil
+	}
+
+	placeholders := make([]string, 0, len(accountIds))
+	args := make([]interface{}, 0, len(accountIds))
+	for _, accountId := range accountIds {
+		placeholders = append(placeholders, "?")
+		args = append(args, accountId)
+	}
+
+	rows, err := tx.QueryContext(ctx, queries.GetUsersByAccountIdsUserRecord+"("+strings.Join(placeholders, ", ")+")", args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var users []record.UserRecord
+	for rows.Next() {
+		var userRecord record.UserRecord
+		if err := rows.Scan(
+			&userRecord.UserID,
+			&userRecord.AccountID,
+			&userRecord.FullName,
+			&userRecord.DateOfBirth,
+			&userRecord.Age,
+			&userRecord.Gender,
+			&userRecord.Address,
+			&userRecord.Bio,
+			&userRecord.CreatedAt,
+			&userRecord.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("could not scan row: %v", err)
+		}
+		users = append(users, userRecord)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %v", err)
+	}
+
+	return users, nil
+}
+
 func (u UserRepositoryImpl) GetAllUsersFromDB(ctx context.Context, tx *sql.Tx) ([]record.UserAccountRecord, error) {
 	rows, err := tx.QueryContext(ctx, queries.FindAllUserAccountsListRecord)
 	if err != nil {
@@ -119,7 +166,7 @@ func (u UserRepositoryImpl) GetAllUsersFromDB(ctx context.Context, tx *sql.Tx) (
 	return users, nil
 }
 
-func (u UserRepositoryImpl) GetAllUsersViewsFromDB(ctx context.Context, verifiedUser bool, accountIdIdentifier int64, tx *sql.Tx) ([]record.UserAccountRecord, error) {
+func (u UserRepositoryImpl) GetAllUsersViewsFromDB(ctx context.Context, verifiedUser bool, accountIdIdentifier int64, radiusKm int, tx *sql.Tx) ([]record.UserAccountRecord, error) {
 	var query string
 	if verifiedUser {
 		query = queries.FindAllUserAccountsViewInPremiumFirstListRecord
@@ -128,7 +175,8 @@ func (u UserRepositoryImpl) GetAllUsersViewsFromDB(ctx context.Context, verified
 	}
 	common.PrintJSON("printed query for daily views", query)
 
-	rows, err := tx.QueryContext(ctx, query, accountIdIdentifier)
+	// The first identifiers are the match score and viewer location joins, the others filter the viewer, its contacts, hidden and blocked accounts out
+	rows, err := tx.QueryContext(ctx, query, accountIdIdentifier, accountIdIdentifier, accountIdIdentifier, accountIdIdentifier, accountIdIdentifier, accountIdIdentifier, accountIdIdentifier, radiusKm)
 	if err != nil {
 		return nil, fmt.Errorf("could not execute query: %v", err)
 	}
@@ -147,6 +195,7 @@ func (u UserRepositoryImpl) GetAllUsersViewsFromDB(ctx context.Context, verified
 			&user.Bio,
 			&user.Age,
 			&user.Address,
+			&user.DistanceKm,
 		); err != nil {
 			return nil, fmt.Errorf("could not scan row: %v", err)
 		}
@@ -160,32 +209,32 @@ func (u UserRepositoryImpl) GetAllUsersViewsFromDB(ctx context.Context, verified
 	return users, nil
 }
 
-func fetchSecondAllUsersViewInPremiumUser(ctx context.Context, tx *sql.Tx, identifier int64) (*sql.Rows, error) {
+func fetchSecondAllUsersViewInPremiumUser(ctx context.Context, tx *sql.Tx, identifier int64, radiusKm int) (*sql.Rows, error) {
 	query := queries.FindAllUserAccountsViewInPremiumSecondListRecord
-	rows, err := tx.QueryContext(ctx, query, identifier, identifier)
+	rows, err := tx.QueryContext(ctx, query, identifier, identifier, identifier, identifier, identifier, identifier, identifier, identifier, radiusKm)
 	if err != nil {
 		return nil, fmt.Errorf("could not execute query: %v", err)
 	}
 	return rows, nil
 }
 
-func fetchSecondAllUsersViewInRegularUser(ctx context.Context, tx *sql.Tx, identifier int64) (*sql.Rows, error) {
+func fetchSecondAllUsersViewInRegularUser(ctx context.Context, tx *sql.Tx, identifier int64, radiusKm int) (*sql.Rows, error) {
 	query := queries.FindAllUserAccountsView10InSecondHitListRecord
-	rows, err := tx.QueryContext(ctx, query, identifier, identifier, identifier)
+	rows, err := tx.QueryContext(ctx, query, identifier, identifier, identifier, identifier, identifier, identifier, identifier, identifier, identifier, radiusKm)
 	if err != nil {
 		return nil, fmt.Errorf("could not execute query: %v", err)
 	}
 	return rows, nil
 }
 
-func (u UserRepositoryImpl) GetAllUsersNextViewsFromDB(ctx context.Context, verifiedUser bool, accountIdIdentifier int64, tx *sql.Tx) ([]record.UserAccountRecord, error) {
+func (u UserRepositoryImpl) GetAllUsersNextViewsFromDB(ctx context.Context, verifiedUser bool, accountIdIdentifier int64, radiusKm int, tx *sql.Tx) ([]record.UserAccountRecord, error) {
 	var rows *sql.Rows
 	var err error
 
 	if verifiedUser {
-		rows, err = fetchSecondAllUsersViewInPremiumUser(ctx, tx, accountIdIdentifier)
+		rows, err = fetchSecondAllUsersViewInPremiumUser(ctx, tx, accountIdIdentifier, radiusKm)
 	} else {
-		rows, err = fetchSecondAllUsersViewInRegularUser(ctx, tx, accountIdIdentifier)
+		rows, err = fetchSecondAllUsersViewInRegularUser(ctx, tx, accountIdIdentifier, radiusKm)
 	}
 
 	if err != nil {
@@ -206,6 +255,7 @@ func (u UserRepositoryImpl) GetAllUsersNextViewsFromDB(ctx context.Context, veri
 			&user.Bio,
 			&user.Age,
 			&user.Address,
+			&user.DistanceKm,
 		); err != nil {
 			return nil, fmt.Errorf("could not scan row: %v", err)
 		}
@@ -222,7 +272,7 @@ func (u UserRepositoryImpl) GetAllUsersNextViewsFromDB(ctx context.Context, veri
 func (u UserRepositoryImpl) UpdateUserToDB(ctx context.Context, tx *sql.Tx, userRecord record.UserRecord) (record.UserRecord, error) {
 	query := `
 		UPDATE users
-		SET full_name = ?, date_of_birth = ?, age = ?, gender = ?, address = ?, bio = ?, updated_at = CURRENT_TIMESTAMP
+		SET full_name = ?, date_of_birth = ?, age = ?, gender = ?, address = ?, bio = ?, phone_hash = COALESCE(?, phone_hash), updated_at = CURRENT_TIMESTAMP
 		WHERE user_id = ?;
 	`
 
@@ -233,6 +283,7 @@ func (u UserRepositoryImpl) UpdateUserToDB(ctx context.Context, tx *sql.Tx, user
 		userRecord.Gender,
 		userRecord.Address,
 		userRecord.Bio,
+		userRecord.PhoneHash,
 		userRecord.UserID,
 	)
 
@@ -249,6 +300,18 @@ func (u UserRepositoryImpl) UpdateUserToDB(ctx context.Context, tx *sql.Tx, user
 	return updatedUserRecord, nil
 }
 
+// UpdateUserLocationToDB sets or, with nil coordinates, clears the location used for discovery
+func (u UserRepositoryImpl) UpdateUserLocationToDB(ctx context.Context, tx *sql.Tx, accountId int64, latitude *float64, longitude *float64) error {
+	query := `UPDATE users SET latitude = ?, longitude = ?, location_updated_at = IF(? IS NULL, NULL, CURRENT_TIMESTAMP), updated_at = CURRENT_TIMESTAMP
+		WHERE account_id = ?`
+
+	_, err := tx.ExecContext(ctx, query, latitude, longitude, latitude, accountId)
+	if err != nil {
+		return fmt.Errorf("could not update user location: %v", err)
+	}
+	return nil
+}
+
 func (u UserRepositoryImpl) findUserByID(ctx context.Context, tx *sql.Tx, userID int64) (record.UserRecord, error) {
 	query := `
 		SELECT user_id, account_id, full_name, date_of_birth, age, gender, address, bio, created_at, updated_at
@@ -0,0 +1,126 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Message is addressed to one account, To is the email address of the account
+type Message struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, message Message) error
+}
+
+const DefaultSMTPPort = 587
+
+// Config is read by config.Load from SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD, SMTP_FROM and
+// NOTIFIER_WEBHOOK_URL
+type Config struct {
+	SMTPHost     string
+	SMTPPort     int `validate:"min=1,max=65535"`
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string `validate:"required_with=SMTPHost,omitempty,email"`
+	WebhookURL   string `validate:"omitempty,url"`
+}
+
+// NewNotifier sends mail through the SMTP host when it is set, otherwise posts to the webhook. Without either the
+// messages only go to the log, which is meant for local development
+func NewNotifier(config Config) Notifier {
+	if config.SMTPHost != "" {
+		return &SMTPNotifier{
+			Address:  net.JoinHostPort(config.SMTPHost, strconv.Itoa(config.SMTPPort)),
+			Host:     config.SMTPHost,
+			Username: config.SMTPUsername,
+			Password: config.SMTPPassword,
+			From:     config.SMTPFrom,
+		}
+	}
+	if config.WebhookURL != "" {
+		return &WebhookNotifier{URL: config.WebhookURL, Client: &http.Client{Timeout: 10 * time.Second}}
+	}
+	return LogNotifier{}
+}
+
+// LogNotifier writes the whole message, one time tokens included, so it must not be used in production
+type LogNotifier struct{}
+
+func (l LogNotifier) Notify(ctx context.Context, message Message) error {
+	log.Printf("NOTIFY %s: %s\n%s", message.To, message.Subject, message.Body)
+	return nil
+}
+
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+func (w *WebhookNotifier) Notify(ctx context.Context, message Message) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("could not encode notification: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("could not create notification request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not send notification: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notification webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SMTPNotifier sends a plain text mail, it authenticates only when a username is configured
+type SMTPNotifier struct {
+	Address  string
+	Host     string
+	Username string
+	Password string
+	From     string
+}
+
+func (s *SMTPNotifier) Notify(ctx context.Context, message Message) error {
+	// A header must not continue on another line, or the recipient could add headers of its own
+	if strings.ContainsAny(message.To+message.Subject, "\r\n") {
+		return fmt.Errorf("notification header contains a line break")
+	}
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+
+	mail := "From: " + s.From + "\r\n" +
+		"To: " + message.To + "\r\n" +
+		"Subject: " + message.Subject + "\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" + message.Body + "\r\n"
+
+	if err := smtp.SendMail(s.Address, auth, s.From, []string{message.To}, []byte(mail)); err != nil {
+		return fmt.Errorf("could not send mail: %v", err)
+	}
+	return nil
+}
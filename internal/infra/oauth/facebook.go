@@ -0,0 +1,43 @@
+package oauth
+
+import (
+	"context"
+)
+
+// Limited Login of the Facebook SDK returns an OpenID Connect ID token, the classic access token is not accepted
+const facebookKeysURL = "https://limited.facebook.com/.well-known/oauth/openid/jwks/"
+
+type FacebookProvider struct {
+	verifier *idTokenVerifier
+}
+
+// NewFacebookProvider returns nil without app ids so the provider is not registered
+func NewFacebookProvider(appIds []string) Provider {
+	if len(appIds) == 0 {
+		return nil
+	}
+	return &FacebookProvider{verifier: &idTokenVerifier{
+		issuers:   []string{"https://www.facebook.com", "https://limited.facebook.com"},
+		audiences: appIds,
+		keysURL:   facebookKeysURL,
+	}}
+}
+
+func (f *FacebookProvider) Name() string {
+	return "facebook"
+}
+
+// VerifyIDToken Facebook has no email_verified claim, it only shares an email the user confirmed with it
+func (f *FacebookProvider) VerifyIDToken(ctx context.Context, idToken string, nonce string) (Identity, error) {
+	claims, err := f.verifier.verify(ctx, idToken, nonce)
+	if err != nil {
+		return Identity{}, err
+	}
+	return Identity{
+		Provider:      f.Name(),
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.Email != "",
+		Name:          claims.Name,
+	}, nil
+}
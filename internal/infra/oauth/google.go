@@ -0,0 +1,41 @@
+package oauth
+
+import (
+	"context"
+)
+
+const googleKeysURL = "https://www.googleapis.com/oauth2/v3/certs"
+
+type GoogleProvider struct {
+	verifier *idTokenVerifier
+}
+
+// NewGoogleProvider returns nil without client ids so the provider is not registered
+func NewGoogleProvider(clientIds []string) Provider {
+	if len(clientIds) == 0 {
+		return nil
+	}
+	return &GoogleProvider{verifier: &idTokenVerifier{
+		issuers:   []string{"accounts.google.com", "https://accounts.google.com"},
+		audiences: clientIds,
+		keysURL:   googleKeysURL,
+	}}
+}
+
+func (g *GoogleProvider) Name() string {
+	return "google"
+}
+
+func (g *GoogleProvider) VerifyIDToken(ctx context.Context, idToken string, nonce string) (Identity, error) {
+	claims, err := g.verifier.verify(ctx, idToken, nonce)
+	if err != nil {
+		return Identity{}, err
+	}
+	return Identity{
+		Provider:      g.Name(),
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.emailVerified(),
+		Name:          claims.Name,
+	}, nil
+}
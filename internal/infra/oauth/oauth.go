@@ -0,0 +1,63 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrInvalidIDToken is returned for a token that is malformed, expired, signed by another key or issued to another app
+var ErrInvalidIDToken = errors.New("invalid id token")
+
+// Identity is the account at the provider the ID token was issued for, Subject never changes for the account
+type Identity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// Provider verifies the ID token the app got from the sign in SDK of the provider
+type Provider interface {
+	Name() string
+	VerifyIDToken(ctx context.Context, idToken string, nonce string) (Identity, error)
+}
+
+// Registry keeps the configured providers by name
+type Registry map[string]Provider
+
+// NewRegistry registers only the providers that have a client id configured
+func NewRegistry(providers ...Provider) Registry {
+	registry := make(Registry)
+	for _, provider := range providers {
+		if provider != nil {
+			registry[provider.Name()] = provider
+		}
+	}
+	return registry
+}
+
+// Config has the client ids of the apps of each provider (web, Android, iOS), a token of any of them is accepted.
+// It is read by config.Load from GOOGLE_CLIENT_IDS and FACEBOOK_APP_IDS
+type Config struct {
+	GoogleClientIds []string
+	FacebookAppIds  []string
+}
+
+// NewRegistryFromConfig registers Google and Facebook when they have client ids
+func NewRegistryFromConfig(config Config) Registry {
+	return NewRegistry(NewGoogleProvider(config.GoogleClientIds), NewFacebookProvider(config.FacebookAppIds))
+}
+
+func (r Registry) Find(name string) (Provider, error) {
+	provider, ok := r[name]
+	if !ok {
+		return nil, fmt.Errorf("oauth provider %s is not configured", name)
+	}
+	return provider, nil
+}
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
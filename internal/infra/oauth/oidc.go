@@ -0,0 +1,182 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultKeysTTL is used when the key endpoint sends no max-age, keys rotate in days
+	defaultKeysTTL = time.Hour
+	// keysRefetchInterval an unknown key id fetches the keys again at most this often, a forged kid cannot make
+	// every request call the provider
+	keysRefetchInterval = time.Minute
+)
+
+// idTokenClaims are the OpenID Connect claims both providers put in their ID tokens
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Email         string      `json:"email"`
+	EmailVerified interface{} `json:"email_verified"`
+	Name          string      `json:"name"`
+	Nonce         string      `json:"nonce"`
+}
+
+// emailVerified the claim is a boolean, some tokens carry it as the string "true"
+func (c idTokenClaims) emailVerified() bool {
+	switch v := c.EmailVerified.(type) {
+	case bool:
+		return v
+	case string:
+		verified, _ := strconv.ParseBool(v)
+		return verified
+	}
+	return false
+}
+
+// idTokenVerifier checks an RS256 ID token against the published keys of the provider, the issuer and the client ids
+type idTokenVerifier struct {
+	issuers   []string
+	audiences []string
+	keysURL   string
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	expiresAt time.Time
+	fetchedAt time.Time
+}
+
+func (v *idTokenVerifier) verify(ctx context.Context, idToken string, nonce string) (idTokenClaims, error) {
+	var claims idTokenClaims
+	_, err := jwt.ParseWithClaims(idToken, &claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return v.key(ctx, kid)
+	}, jwt.WithValidMethods([]string{"RS256"}), jwt.WithExpirationRequired(), jwt.WithIssuedAt(), jwt.WithLeeway(time.Minute))
+	if err != nil {
+		return idTokenClaims{}, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	if !contains(v.issuers, claims.Issuer) {
+		return idTokenClaims{}, fmt.Errorf("%w: issued by %s", ErrInvalidIDToken, claims.Issuer)
+	}
+	audienceKnown := false
+	for _, audience := range claims.Audience {
+		audienceKnown = audienceKnown || contains(v.audiences, audience)
+	}
+	if !audienceKnown {
+		return idTokenClaims{}, fmt.Errorf("%w: issued to another app", ErrInvalidIDToken)
+	}
+	if claims.Subject == "" {
+		return idTokenClaims{}, fmt.Errorf("%w: no subject", ErrInvalidIDToken)
+	}
+	// The nonce ties the token to the sign in the app started, a token without the expected nonce was taken elsewhere
+	if nonce != "" && claims.Nonce != nonce {
+		return idTokenClaims{}, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+	return claims, nil
+}
+
+// key returns the public key of the kid, the keys are fetched again when they expired or the kid is new
+func (v *idTokenVerifier) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := time.Now()
+	key, ok := v.keys[kid]
+	if ok && now.Before(v.expiresAt) {
+		return key, nil
+	}
+	if !ok && now.Sub(v.fetchedAt) < keysRefetchInterval && now.Before(v.expiresAt) {
+		return nil, errors.New("unknown signing key")
+	}
+
+	keys, ttl, err := fetchKeys(ctx, v.keysURL)
+	if err != nil {
+		return nil, err
+	}
+	v.keys, v.fetchedAt, v.expiresAt = keys, now, now.Add(ttl)
+	if key, ok = keys[kid]; !ok {
+		return nil, errors.New("unknown signing key")
+	}
+	return key, nil
+}
+
+// jsonWebKeys is the JWKS document of a provider, only the RSA signing keys are used
+type jsonWebKeys struct {
+	Keys []struct {
+		Kid string `json:"kid"`
+		Kty string `json:"kty"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+func fetchKeys(ctx context.Context, keysURL string) (map[string]*rsa.PublicKey, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, keysURL, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not fetch signing keys: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, 0, fmt.Errorf("GET %s returned %d: %s", req.URL.Host, resp.StatusCode, string(body))
+	}
+
+	var document jsonWebKeys
+	if err := json.NewDecoder(resp.Body).Decode(&document); err != nil {
+		return nil, 0, fmt.Errorf("could not decode signing keys: %v", err)
+	}
+	keys := make(map[string]*rsa.PublicKey, len(document.Keys))
+	for _, jwk := range document.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(jwk.N)
+		e, errE := base64.RawURLEncoding.DecodeString(jwk.E)
+		if errN != nil || errE != nil || len(e) > 4 {
+			continue
+		}
+		keys[jwk.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	return keys, maxAge(resp.Header.Get("Cache-Control")), nil
+}
+
+// maxAge reads the max-age of a Cache-Control header, the providers publish how long their keys may be cached
+func maxAge(cacheControl string) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		value, found := strings.CutPrefix(strings.TrimSpace(directive), "max-age=")
+		if !found {
+			continue
+		}
+		if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return defaultKeysTTL
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	apnsProductionURL = "https://api.push.apple.com/3/device/"
+	apnsSandboxURL    = "https://api.sandbox.push.apple.com/3/device/"
+)
+
+// APNsProvider sends to Apple devices with a token based provider connection, the .p8 key in KeyFile signs the
+// provider token. Go's client speaks HTTP/2 to the api as it requires
+type APNsProvider struct {
+	KeyFile  string
+	KeyID    string
+	TeamID   string
+	BundleID string
+	Sandbox  bool
+	Client   *http.Client
+
+	mu            sync.Mutex
+	providerToken string
+	issuedAt      time.Time
+}
+
+func NewAPNsProvider(config Config) Provider {
+	return &APNsProvider{
+		KeyFile:  config.APNsKeyFile,
+		KeyID:    config.APNsKeyID,
+		TeamID:   config.APNsTeamID,
+		BundleID: config.APNsBundleID,
+		Sandbox:  config.APNsSandbox,
+		Client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (a *APNsProvider) Send(ctx context.Context, token string, message Message) error {
+	payload := map[string]interface{}{
+		"aps": map[string]interface{}{
+			"alert": map[string]string{"title": message.Title, "body": message.Body},
+			"sound": "default",
+		},
+	}
+	for key, value := range message.Data {
+		payload[key] = value
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("could not encode push: %v", err)
+	}
+
+	providerToken, err := a.token()
+	if err != nil {
+		return err
+	}
+	target := apnsProductionURL
+	if a.Sandbox {
+		target = apnsSandboxURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target+token, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("could not create push request: %v", err)
+	}
+	req.Header.Set("Authorization", "bearer "+providerToken)
+	req.Header.Set("apns-topic", a.BundleID)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not send push: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var failure struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&failure)
+	if resp.StatusCode == http.StatusGone || failure.Reason == "BadDeviceToken" || failure.Reason == "DeviceTokenNotForTopic" {
+		return ErrInvalidToken
+	}
+	return fmt.Errorf("apns responded with status %d %s", resp.StatusCode, failure.Reason)
+}
+
+// token is signed again after 50 minutes, apple refuses a provider token older than an hour and one renewed
+// more often than every 20 minutes
+func (a *APNsProvider) token() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.providerToken != "" && time.Since(a.issuedAt) < 50*time.Minute {
+		return a.providerToken, nil
+	}
+
+	content, err := os.ReadFile(a.KeyFile)
+	if err != nil {
+		return "", fmt.Errorf("could not read APNs key: %v", err)
+	}
+	privateKey, err := jwt.ParseECPrivateKeyFromPEM(content)
+	if err != nil {
+		return "", fmt.Errorf("could not parse APNs key: %v", err)
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{"iss": a.TeamID, "iat": now.Unix()})
+	token.Header["kid"] = strings.TrimSpace(a.KeyID)
+	signed, err := token.SignedString(privateKey)
+	if err != nil {
+		return "", fmt.Errorf("could not sign APNs provider token: %v", err)
+	}
+	a.providerToken = signed
+	a.issuedAt = now
+	return a.providerToken, nil
+}
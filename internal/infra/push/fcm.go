@@ -0,0 +1,181 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	fcmAPIURL      = "https://fcm.googleapis.com/v1/projects/"
+	fcmScope       = "https://www.googleapis.com/auth/firebase.messaging"
+	fcmMetadataURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+)
+
+// FCMProvider sends through the Firebase Cloud Messaging HTTP v1 api. It authenticates with the service account key
+// in CredentialsFile, or with the metadata server of the instance when there is none
+type FCMProvider struct {
+	ProjectID       string
+	CredentialsFile string
+	Client          *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+func NewFCMProvider(config Config) Provider {
+	return &FCMProvider{
+		ProjectID:       config.FCMProjectID,
+		CredentialsFile: config.FCMCredentialsFile,
+		Client:          &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (f *FCMProvider) Send(ctx context.Context, token string, message Message) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"message": map[string]interface{}{
+			"token":        token,
+			"notification": map[string]string{"title": message.Title, "body": message.Body},
+			"data":         message.Data,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("could not encode push: %v", err)
+	}
+
+	accessToken, err := f.token(ctx)
+	if err != nil {
+		return err
+	}
+	target := fcmAPIURL + url.PathEscape(f.ProjectID) + "/messages:send"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("could not create push request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not send push: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var failure struct {
+		Error struct {
+			Status  string `json:"status"`
+			Details []struct {
+				ErrorCode string `json:"errorCode"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&failure)
+	for _, detail := range failure.Error.Details {
+		if detail.ErrorCode == "UNREGISTERED" {
+			return ErrInvalidToken
+		}
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrInvalidToken
+	}
+	return fmt.Errorf("fcm responded with status %d %s", resp.StatusCode, failure.Error.Status)
+}
+
+// token is cached until a minute before it expires
+func (f *FCMProvider) token(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.accessToken != "" && time.Now().Before(f.expiresAt.Add(-time.Minute)) {
+		return f.accessToken, nil
+	}
+
+	var req *http.Request
+	var err error
+	if f.CredentialsFile != "" {
+		req, err = f.serviceAccountTokenRequest(ctx)
+	} else {
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, fcmMetadataURL, nil)
+		if err == nil {
+			req.Header.Set("Metadata-Flavor", "Google")
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("could not get access token: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("access token responded with status %d", resp.StatusCode)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil || token.AccessToken == "" {
+		return "", errors.New("could not read access token")
+	}
+	f.accessToken = token.AccessToken
+	f.expiresAt = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	return f.accessToken, nil
+}
+
+// serviceAccountTokenRequest exchanges an assertion signed with the service account key, RFC 7523
+func (f *FCMProvider) serviceAccountTokenRequest(ctx context.Context) (*http.Request, error) {
+	content, err := os.ReadFile(f.CredentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read FCM credentials: %v", err)
+	}
+	var credentials struct {
+		ClientEmail string `json:"client_email"`
+		PrivateKey  string `json:"private_key"`
+		TokenURI    string `json:"token_uri"`
+	}
+	if err := json.Unmarshal(content, &credentials); err != nil {
+		return nil, fmt.Errorf("could not parse FCM credentials: %v", err)
+	}
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(credentials.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse FCM private key: %v", err)
+	}
+	if credentials.TokenURI == "" {
+		credentials.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+
+	now := time.Now()
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   credentials.ClientEmail,
+		"scope": fcmScope,
+		"aud":   credentials.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not sign FCM assertion: %v", err)
+	}
+
+	form := url.Values{"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"}, "assertion": {assertion}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, credentials.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req, nil
+}
@@ -0,0 +1,69 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"log"
+)
+
+// Providers a device token can be registered with
+const (
+	ProviderFCM  = "fcm"
+	ProviderAPNs = "apns"
+)
+
+// ErrInvalidToken is returned when the provider no longer knows the device token, the app was removed or the
+// token rotated, so it must not be used again
+var ErrInvalidToken = errors.New("device token is no longer valid")
+
+// Message is shown as a notification on the device, Data is handed to the app with it
+type Message struct {
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+type Provider interface {
+	Send(ctx context.Context, token string, message Message) error
+}
+
+// Config is read by config.Load from FCM_PROJECT_ID and FCM_CREDENTIALS_FILE, and from APNS_KEY_FILE, APNS_KEY_ID,
+// APNS_TEAM_ID and APNS_BUNDLE_ID. APNS_SANDBOX=true sends to the development environment of the app
+type Config struct {
+	FCMProjectID       string
+	FCMCredentialsFile string `validate:"required_with=FCMProjectID"`
+	APNsKeyFile        string
+	APNsKeyID          string `validate:"required_with=APNsKeyFile"`
+	APNsTeamID         string `validate:"required_with=APNsKeyFile"`
+	APNsBundleID       string `validate:"required_with=APNsKeyFile"`
+	APNsSandbox        bool
+}
+
+// NewProviders returns a provider for FCM and one for APNs. A provider without its settings writes the pushes to the
+// log instead, which is meant for local development
+func NewProviders(config Config) map[string]Provider {
+	providers := map[string]Provider{
+		ProviderFCM:  LogProvider{Name: ProviderFCM},
+		ProviderAPNs: LogProvider{Name: ProviderAPNs},
+	}
+	if config.FCMProjectID != "" {
+		providers[ProviderFCM] = NewFCMProvider(config)
+	}
+	if config.APNsKeyFile != "" {
+		providers[ProviderAPNs] = NewAPNsProvider(config)
+	}
+	return providers
+}
+
+// LogProvider only logs the push, the token is shortened so the log cannot be used to push to the device
+type LogProvider struct {
+	Name string
+}
+
+func (l LogProvider) Send(ctx context.Context, token string, message Message) error {
+	if len(token) > 8 {
+		token = token[:8] + "..."
+	}
+	log.Printf("PUSH %s %s: %s - %s %v", l.Name, token, message.Title, message.Body, message.Data)
+	return nil
+}
@@ -0,0 +1,60 @@
+package realtime
+
+import (
+	"godating-dealls/internal/common"
+	"sync"
+)
+
+// subscriberBuffer is how many events a slow connection may lag behind before events to it are dropped,
+// clients fetch the history again after reconnecting so nothing is lost for good
+const subscriberBuffer = 32
+
+// Hub fans events out to the live connections of an account. It is in memory, so an event only reaches
+// connections held by the same instance, running several instances needs a shared broker in front of it
+type Hub struct {
+	mu          sync.RWMutex
+	subscribers map[int64]map[chan interface{}]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{subscribers: map[int64]map[chan interface{}]struct{}{}}
+}
+
+// Subscribe registers a connection of the account, the returned func must be called when the connection ends
+func (h *Hub) Subscribe(accountId int64) (<-chan interface{}, func()) {
+	events := make(chan interface{}, subscriberBuffer)
+
+	h.mu.Lock()
+	if h.subscribers[accountId] == nil {
+		h.subscribers[accountId] = map[chan interface{}]struct{}{}
+	}
+	h.subscribers[accountId][events] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subscribers[accountId], events)
+			if len(h.subscribers[accountId]) == 0 {
+				delete(h.subscribers, accountId)
+			}
+			h.mu.Unlock()
+			close(events)
+		})
+	}
+	return events, unsubscribe
+}
+
+// Publish never blocks, a connection whose buffer is full misses the event
+func (h *Hub) Publish(accountId int64, event interface{}) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for events := range h.subscribers[accountId] {
+		select {
+		case events <- event:
+		default:
+			common.MessagingLog.Warnf("realtime: dropped event for account %d, subscriber is too slow", accountId)
+		}
+	}
+}
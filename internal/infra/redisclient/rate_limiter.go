@@ -0,0 +1,93 @@
+package redisclient
+
+import (
+	"context"
+	"errors"
+	"godating-dealls/internal/common"
+	"log"
+	"strconv"
+	"time"
+)
+
+// SlidingWindowLimiter counts requests in redis so every instance shares the limit. The last window is estimated
+// from the current and the previous fixed window, the previous one weighted by how much of it still overlaps
+type SlidingWindowLimiter struct {
+	rds      RedisInterface
+	name     string
+	limit    int64
+	window   time.Duration
+	fallback common.Limiter
+}
+
+// NewSlidingWindowLimiter keeps the window within half the ttl of RateLimitKey, so the previous window is still there.
+// The in memory limiter with the same limit takes over while redis is unavailable
+func NewSlidingWindowLimiter(rds RedisInterface, name string, limit int, window time.Duration) *SlidingWindowLimiter {
+	window = min(window, RateLimitKey.TTL/2)
+	return &SlidingWindowLimiter{
+		rds:      rds,
+		name:     name,
+		limit:    int64(limit),
+		window:   window,
+		fallback: common.NewRateLimiter(limit, window),
+	}
+}
+
+// Allow checks before counting, so refused requests do not push the retry further away.
+// Two requests at the same moment may both pass the last slot, the limit is a rate and not an exact budget
+func (s *SlidingWindowLimiter) Allow(ctx context.Context, key string) (bool, time.Duration) {
+	now := time.Now()
+	index := now.UnixNano() / int64(s.window)
+	elapsed := time.Duration(now.UnixNano() % int64(s.window))
+	currentKey := RateLimitKey.Key(s.name, key, strconv.FormatInt(index, 10))
+
+	previous, err := s.count(ctx, RateLimitKey.Key(s.name, key, strconv.FormatInt(index-1, 10)))
+	if err != nil {
+		return s.fallback.Allow(ctx, key)
+	}
+	current, err := s.count(ctx, currentKey)
+	if err != nil {
+		return s.fallback.Allow(ctx, key)
+	}
+
+	overlap := 1 - float64(elapsed)/float64(s.window)
+	if float64(previous)*overlap+float64(current)+1 > float64(s.limit) {
+		return false, s.retryAfter(previous, current, elapsed)
+	}
+
+	if _, err := s.rds.IncrementInRedis(ctx, currentKey); err != nil {
+		log.Println("failed to count request for rate limit:", err)
+		return s.fallback.Allow(ctx, key)
+	}
+	return true, 0
+}
+
+// retryAfter is when the estimate leaves room for one request, when nothing else is counted meanwhile
+func (s *SlidingWindowLimiter) retryAfter(previous int64, current int64, elapsed time.Duration) time.Duration {
+	window := float64(s.window)
+	room := float64(s.limit - 1)
+	var wait float64
+	if float64(current) > room {
+		// The current window alone is full, wait for it to become the previous one and fade enough
+		wait = window - float64(elapsed) + window*(1-room/float64(current))
+	} else {
+		wait = window*(1-(room-float64(current))/float64(previous)) - float64(elapsed)
+	}
+	return max(time.Duration(wait), time.Second)
+}
+
+// count reads a counter, a missing key is zero
+func (s *SlidingWindowLimiter) count(ctx context.Context, key string) (int64, error) {
+	value, err := s.rds.LoadFromRedis(ctx, key)
+	if err != nil {
+		if errors.Is(err, ErrKeyNotFound) {
+			return 0, nil
+		}
+		log.Println("failed to load rate limit count:", err)
+		return 0, err
+	}
+	count, ok := value.(float64)
+	if !ok {
+		return 0, nil
+	}
+	return int64(count), nil
+}
@@ -6,4 +6,9 @@ type RedisInterface interface {
 	StoreToRedis(ctx context.Context, key string, data interface{}) error
 	LoadFromRedis(ctx context.Context, key string) (interface{}, error)
 	ClearFromRedis(ctx context.Context, key string) error
+	IncrementInRedis(ctx context.Context, key string) (int64, error)
+	TakeFromRedis(ctx context.Context, key string) (interface{}, error)
+	AddToSetInRedis(ctx context.Context, key string, members ...string) error
+	SetMembersFromRedis(ctx context.Context, key string) ([]string, error)
+	ScanKeysFromRedis(ctx context.Context, policy KeyPolicy) ([]string, error)
 }
@@ -0,0 +1,74 @@
+package redisclient
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+const auditScanCount = 500
+
+type KeyViolation struct {
+	Key    string
+	Reason string
+	TTL    time.Duration
+}
+
+type AuditReport struct {
+	Scanned    int
+	ByPrefix   map[string]int
+	Violations []KeyViolation
+}
+
+// AuditKeys scans the whole keyspace and reports keys outside the registry, without a ttl or with a ttl above their policy.
+// SCAN is incremental so it is safe to run against a live instance
+func AuditKeys(ctx context.Context, client *redis.Client) (AuditReport, error) {
+	report := AuditReport{ByPrefix: map[string]int{}}
+
+	var cursor uint64
+	for {
+		keys, next, err := client.Scan(ctx, cursor, "*", auditScanCount).Result()
+		if err != nil {
+			return report, fmt.Errorf("could not scan keys: %v", err)
+		}
+
+		pipe := client.Pipeline()
+		ttls := make([]*redis.DurationCmd, len(keys))
+		for i, key := range keys {
+			ttls[i] = pipe.TTL(ctx, key)
+		}
+		if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+			return report, fmt.Errorf("could not read key ttl: %v", err)
+		}
+
+		for i, key := range keys {
+			report.Scanned++
+			ttl := ttls[i].Val()
+			// A key that expired between SCAN and TTL is gone, nothing to report
+			if ttl == -2 {
+				continue
+			}
+
+			policy, ok := FindKeyPolicy(key)
+			if !ok {
+				report.ByPrefix["(unregistered)"]++
+				report.Violations = append(report.Violations, KeyViolation{Key: key, Reason: "unregistered namespace", TTL: ttl})
+				continue
+			}
+			report.ByPrefix[policy.Prefix]++
+
+			switch {
+			case ttl == -1:
+				report.Violations = append(report.Violations, KeyViolation{Key: key, Reason: "no ttl", TTL: ttl})
+			case ttl > policy.TTL:
+				report.Violations = append(report.Violations, KeyViolation{Key: key, Reason: fmt.Sprintf("ttl above policy %s", policy.TTL), TTL: ttl})
+			}
+		}
+
+		cursor = next
+		if cursor == 0 {
+			return report, nil
+		}
+	}
+}
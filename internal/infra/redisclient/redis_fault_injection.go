@@ -0,0 +1,74 @@
+package redisclient
+
+import (
+	"context"
+	"godating-dealls/internal/common"
+)
+
+// FaultInjectingRedis wraps a redis client with the configured fault injection, used only for resilience testing
+type FaultInjectingRedis struct {
+	Redis RedisInterface
+}
+
+func NewFaultInjectingRedis(redis RedisInterface) RedisInterface {
+	if !common.LoadFaultInjectionConfig().Enabled {
+		return redis
+	}
+	return &FaultInjectingRedis{Redis: redis}
+}
+
+func (f FaultInjectingRedis) StoreToRedis(ctx context.Context, key string, data interface{}) error {
+	if err := common.InjectFault(ctx, common.FaultTargetRedis); err != nil {
+		return err
+	}
+	return f.Redis.StoreToRedis(ctx, key, data)
+}
+
+func (f FaultInjectingRedis) LoadFromRedis(ctx context.Context, key string) (interface{}, error) {
+	if err := common.InjectFault(ctx, common.FaultTargetRedis); err != nil {
+		return nil, err
+	}
+	return f.Redis.LoadFromRedis(ctx, key)
+}
+
+func (f FaultInjectingRedis) ClearFromRedis(ctx context.Context, key string) error {
+	if err := common.InjectFault(ctx, common.FaultTargetRedis); err != nil {
+		return err
+	}
+	return f.Redis.ClearFromRedis(ctx, key)
+}
+
+func (f FaultInjectingRedis) IncrementInRedis(ctx context.Context, key string) (int64, error) {
+	if err := common.InjectFault(ctx, common.FaultTargetRedis); err != nil {
+		return 0, err
+	}
+	return f.Redis.IncrementInRedis(ctx, key)
+}
+
+func (f FaultInjectingRedis) TakeFromRedis(ctx context.Context, key string) (interface{}, error) {
+	if err := common.InjectFault(ctx, common.FaultTargetRedis); err != nil {
+		return nil, err
+	}
+	return f.Redis.TakeFromRedis(ctx, key)
+}
+
+func (f FaultInjectingRedis) AddToSetInRedis(ctx context.Context, key string, members ...string) error {
+	if err := common.InjectFault(ctx, common.FaultTargetRedis); err != nil {
+		return err
+	}
+	return f.Redis.AddToSetInRedis(ctx, key, members...)
+}
+
+func (f FaultInjectingRedis) SetMembersFromRedis(ctx context.Context, key string) ([]string, error) {
+	if err := common.InjectFault(ctx, common.FaultTargetRedis); err != nil {
+		return nil, err
+	}
+	return f.Redis.SetMembersFromRedis(ctx, key)
+}
+
+func (f FaultInjectingRedis) ScanKeysFromRedis(ctx context.Context, policy KeyPolicy) ([]string, error) {
+	if err := common.InjectFault(ctx, common.FaultTargetRedis); err != nil {
+		return nil, err
+	}
+	return f.Redis.ScanKeysFromRedis(ctx, policy)
+}
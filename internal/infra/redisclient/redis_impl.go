@@ -4,10 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/redis/go-redis/v9"
-	"time"
 )
 
+// ErrKeyNotFound is returned by LoadFromRedis for a missing or expired key
+var ErrKeyNotFound = errors.New("key does not exist")
+
 type RdsImpl struct {
 	Redis  RedisInterface
 	Client *redis.Client
@@ -19,13 +22,19 @@ func NewRedisService(Client *redis.Client) RedisInterface {
 	}
 }
 
+// StoreToRedis expires the key with the ttl of its registered namespace, an unregistered key is refused
 func (r RdsImpl) StoreToRedis(ctx context.Context, key string, data interface{}) error {
+	policy, ok := FindKeyPolicy(key)
+	if !ok {
+		return fmt.Errorf("redis key %q is not in a registered namespace", key)
+	}
+
 	serializedData, err := json.Marshal(data)
 	if err != nil {
 		return err
 	}
 
-	err = r.Client.Set(ctx, key, serializedData, 24*time.Hour).Err()
+	err = r.Client.Set(ctx, key, serializedData, policy.TTL).Err()
 	if err != nil {
 		return err
 	}
@@ -37,7 +46,7 @@ func (r RdsImpl) LoadFromRedis(ctx context.Context, key string) (interface{}, er
 	data, err := r.Client.Get(ctx, key).Result()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
-			return nil, errors.New("key does not exist")
+			return nil, ErrKeyNotFound
 		}
 		return nil, err
 	}
@@ -59,3 +68,82 @@ func (r RdsImpl) ClearFromRedis(ctx context.Context, key string) error {
 
 	return nil
 }
+
+// IncrementInRedis counts in a window that starts with the first increment and lasts the ttl of the namespace
+func (r RdsImpl) IncrementInRedis(ctx context.Context, key string) (int64, error) {
+	policy, ok := FindKeyPolicy(key)
+	if !ok {
+		return 0, fmt.Errorf("redis key %q is not in a registered namespace", key)
+	}
+
+	count, err := r.Client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if count == 1 {
+		if err := r.Client.Expire(ctx, key, policy.TTL).Err(); err != nil {
+			return 0, err
+		}
+	}
+
+	return count, nil
+}
+
+// TakeFromRedis loads and deletes the key in one step, so a value meant for a single use is only handed out once
+func (r RdsImpl) TakeFromRedis(ctx context.Context, key string) (interface{}, error) {
+	data, err := r.Client.GetDel(ctx, key).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrKeyNotFound
+		}
+		return nil, err
+	}
+
+	var result interface{}
+	err = json.Unmarshal([]byte(data), &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// AddToSetInRedis adds members to the set, the set expires with the ttl of its namespace counted from the last add
+func (r RdsImpl) AddToSetInRedis(ctx context.Context, key string, members ...string) error {
+	policy, ok := FindKeyPolicy(key)
+	if !ok {
+		return fmt.Errorf("redis key %q is not in a registered namespace", key)
+	}
+	if len(members) == 0 {
+		return nil
+	}
+
+	values := make([]interface{}, 0, len(members))
+	for _, member := range members {
+		values = append(values, member)
+	}
+	_, err := r.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SAdd(ctx, key, values...)
+		pipe.Expire(ctx, key, policy.TTL)
+		return nil
+	})
+	return err
+}
+
+// SetMembersFromRedis returns no members for a missing or expired set
+func (r RdsImpl) SetMembersFromRedis(ctx context.Context, key string) ([]string, error) {
+	return r.Client.SMembers(ctx, key).Result()
+}
+
+// ScanKeysFromRedis lists the keys of the namespace with SCAN, so a large namespace does not block redis like KEYS would
+func (r RdsImpl) ScanKeysFromRedis(ctx context.Context, policy KeyPolicy) ([]string, error) {
+	var keys []string
+	iter := r.Client.Scan(ctx, 0, policy.Prefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
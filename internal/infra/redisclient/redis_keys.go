@@ -0,0 +1,130 @@
+package redisclient
+
+import (
+	"strings"
+	"time"
+)
+
+// KeyPolicy is one registered key namespace, every key written to redis must start with a registered prefix
+type KeyPolicy struct {
+	Prefix      string
+	TTL         time.Duration
+	Description string
+}
+
+var (
+	AccessTokenKey = KeyPolicy{
+		Prefix:      "access_token:",
+		TTL:         24 * time.Hour,
+		Description: "issued access token per account, cleared on logout",
+	}
+	IntegrationStateKey = KeyPolicy{
+		Prefix:      "integration_state:",
+		TTL:         15 * time.Minute,
+		Description: "oauth state between integration connect and callback",
+	}
+	AccountAbsentKey = KeyPolicy{
+		Prefix:      "account_absent:",
+		TTL:         30 * time.Second,
+		Description: "email or username recently found unregistered, lets repeated signup checks skip the database",
+	}
+	LoginFailureCredentialKey = KeyPolicy{
+		Prefix:      "login_failure_credential:",
+		TTL:         15 * time.Minute,
+		Description: "failed logins per credential and source address, the window starts at the first failure",
+	}
+	LoginFailureAddressKey = KeyPolicy{
+		Prefix:      "login_failure_address:",
+		TTL:         15 * time.Minute,
+		Description: "failed logins per source address across every credential",
+	}
+	PasskeyChallengeKey = KeyPolicy{
+		Prefix:      "passkey_challenge:",
+		TTL:         5 * time.Minute,
+		Description: "pending webauthn ceremony by challenge, taken once when the ceremony finishes",
+	}
+	RefreshTokenKey = KeyPolicy{
+		Prefix:      "refresh_token:",
+		TTL:         30 * 24 * time.Hour,
+		Description: "unused refresh token by its hash, taken once when it is exchanged",
+	}
+	RefreshTokenUsedKey = KeyPolicy{
+		Prefix:      "refresh_token_used:",
+		TTL:         30 * 24 * time.Hour,
+		Description: "exchanged refresh token hash to its session, presenting it again revokes the session",
+	}
+	RefreshSessionKey = KeyPolicy{
+		Prefix:      "refresh_session:",
+		TTL:         30 * 24 * time.Hour,
+		Description: "login session to the hash of its current refresh token, cleared on logout",
+	}
+	RefreshRevokedKey = KeyPolicy{
+		Prefix:      "refresh_revoked:",
+		TTL:         30 * 24 * time.Hour,
+		Description: "per account time before which every session is revoked, set on account recovery",
+	}
+	PasswordResetKey = KeyPolicy{
+		Prefix:      "password_reset:",
+		TTL:         30 * time.Minute,
+		Description: "account of an unused password reset token by its hash, taken once when the password is reset",
+	}
+	RateLimitKey = KeyPolicy{
+		Prefix:      "rate_limit:",
+		TTL:         10 * time.Minute,
+		Description: "requests per limiter, client and fixed window, the current and the previous window estimate a sliding one",
+	}
+	PasswordResetAccountKey = KeyPolicy{
+		Prefix:      "password_reset_account:",
+		TTL:         30 * time.Minute,
+		Description: "hash of the latest password reset token per account, older tokens of the account stop working",
+	}
+	SeenTodayKey = KeyPolicy{
+		Prefix:      "seen_today:",
+		TTL:         48 * time.Hour,
+		Description: "set of accounts shown in the daily list per viewer and UTC date, past dates are cleared nightly",
+	}
+	DomainEventStreamKey = KeyPolicy{
+		Prefix:      "domain_events:",
+		TTL:         7 * 24 * time.Hour,
+		Description: "stream of the domain events relayed from the outbox, trimmed by length and expiring a week after the last event",
+	}
+	AnalyticsEventStreamKey = KeyPolicy{
+		Prefix:      "analytics_events:",
+		TTL:         7 * 24 * time.Hour,
+		Description: "stream of the analytics events for the funnels, trimmed by length and expiring a week after the last event",
+	}
+)
+
+// KeyRegistry lists every namespace the service writes, the audit command reports keys outside it
+var KeyRegistry = []KeyPolicy{
+	AccessTokenKey,
+	IntegrationStateKey,
+	AccountAbsentKey,
+	LoginFailureCredentialKey,
+	LoginFailureAddressKey,
+	PasskeyChallengeKey,
+	RefreshTokenKey,
+	RefreshTokenUsedKey,
+	RefreshSessionKey,
+	RefreshRevokedKey,
+	PasswordResetKey,
+	PasswordResetAccountKey,
+	RateLimitKey,
+	SeenTodayKey,
+	DomainEventStreamKey,
+	AnalyticsEventStreamKey,
+}
+
+// Key builds a key in the namespace, parts are joined with ":"
+func (k KeyPolicy) Key(parts ...string) string {
+	return k.Prefix + strings.Join(parts, ":")
+}
+
+func FindKeyPolicy(key string) (KeyPolicy, bool) {
+	for _, policy := range KeyRegistry {
+		if strings.HasPrefix(key, policy.Prefix) {
+			return policy, true
+		}
+	}
+	return KeyPolicy{}, false
+}
@@ -0,0 +1,153 @@
+package regions
+
+import (
+	"context"
+	"godating-dealls/internal/infra/geoip"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"log"
+	"net/http"
+	"net/netip"
+	"sort"
+	"strings"
+	"time"
+)
+
+// lookupTimeout the nearest region falls back to this one when the geoip provider is slower
+const lookupTimeout = 2 * time.Second
+
+const (
+	// HomeRegionHeader and HomeRegionURLHeader are set on the responses to a token of an account homed in another
+	// region, the app connects there from then on
+	HomeRegionHeader    = "X-Home-Region"
+	HomeRegionURLHeader = "X-Home-Region-URL"
+)
+
+// Region is one deployment of the service, the app prefixes the api paths with BaseURL
+type Region struct {
+	Name    string
+	BaseURL string
+}
+
+// Registry knows the deployments by region. The database is shared, a region has its own instances, Redis and chat
+// connections. Without REGION the service runs in one region and every region feature is off
+type Registry struct {
+	Current   string
+	regions   map[string]Region
+	countries map[string]string
+	geo       geoip.Provider
+}
+
+// Config is read by config.Load. REGION is the region of this deployment, REGIONS "name=base url,..." has every
+// deployment including this one and REGION_COUNTRIES "country=name,..." the nearest region of a client.
+// REGION_REPLICATION_CLIENT is the name:secret the peers list in their SERVICE_CLIENTS
+type Config struct {
+	Current           string
+	Regions           map[string]string `validate:"dive,url"`
+	Countries         map[string]string `validate:"dive,keys,iso3166_1_alpha2,endkeys,required"`
+	ReplicationName   string            `validate:"required_with=ReplicationSecret"`
+	ReplicationSecret string            `validate:"required_with=ReplicationName"`
+}
+
+// NewRegistry knows the configured regions. The country of a client is looked up with the geoip provider, without
+// one the nearest region is this one
+func NewRegistry(config Config, geo geoip.Provider) *Registry {
+	registry := &Registry{
+		Current:   config.Current,
+		regions:   map[string]Region{},
+		countries: map[string]string{},
+		geo:       geo,
+	}
+	for name, baseURL := range config.Regions {
+		registry.regions[name] = Region{Name: name, BaseURL: strings.TrimRight(baseURL, "/")}
+	}
+	for country, name := range config.Countries {
+		if _, known := registry.regions[name]; !known {
+			log.Printf("Ignoring region country %s, region %s is not in REGIONS", country, name)
+			continue
+		}
+		registry.countries[country] = name
+	}
+
+	if registry.Current != "" {
+		if _, ok := registry.regions[registry.Current]; !ok {
+			log.Printf("Region %s is not in REGIONS, clients are not sent to it", registry.Current)
+		}
+	}
+	return registry
+}
+
+func (r *Registry) Enabled() bool {
+	return r != nil && r.Current != ""
+}
+
+func (r *Registry) Find(name string) (Region, bool) {
+	region, ok := r.regions[name]
+	return region, ok
+}
+
+// Peers are the other regions, sorted by name
+func (r *Registry) Peers() []Region {
+	var peers []Region
+	for name, region := range r.regions {
+		if name != r.Current {
+			peers = append(peers, region)
+		}
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i].Name < peers[j].Name })
+	return peers
+}
+
+// Nearest is the region of the country of the client address, this region when the country has no region, the
+// address is private or the lookup failed
+func (r *Registry) Nearest(ctx context.Context, address string) Region {
+	current := r.current()
+	if r.geo == nil {
+		return current
+	}
+	addr, err := netip.ParseAddr(address)
+	if err != nil || !addr.Unmap().IsGlobalUnicast() || addr.Unmap().IsPrivate() {
+		return current
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, lookupTimeout)
+	location, err := r.geo.Lookup(ctx, addr.Unmap())
+	cancel()
+	if err != nil {
+		log.Println("geoip lookup failed:", err)
+		return current
+	}
+	if region, ok := r.regions[r.countries[strings.ToUpper(location.Country)]]; ok {
+		return region
+	}
+	return current
+}
+
+// Home is the region of an account, an account without one is served by this region
+func (r *Registry) Home(name string) Region {
+	if region, ok := r.regions[name]; ok {
+		return region
+	}
+	return r.current()
+}
+
+func (r *Registry) current() Region {
+	if region, ok := r.regions[r.Current]; ok {
+		return region
+	}
+	return Region{Name: r.Current}
+}
+
+// HintMiddleware runs after the auth middleware and tells the app of an account homed in another region where to
+// connect, the request is still served here since the database is shared
+func (r *Registry) HintMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		claims, ok := jsonwebtoken.ClaimsFromContext(req.Context())
+		if ok && r.Enabled() && claims.Region != "" && claims.Region != r.Current {
+			if home, ok := r.regions[claims.Region]; ok {
+				w.Header().Set(HomeRegionHeader, home.Name)
+				w.Header().Set(HomeRegionURLHeader, home.BaseURL)
+			}
+		}
+		next.ServeHTTP(w, req)
+	})
+}
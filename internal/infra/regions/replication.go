@@ -0,0 +1,83 @@
+package regions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+)
+
+// replicationTimeout a peer slower than this misses the event, the message is in the shared database and the app
+// loads it with the history
+const replicationTimeout = 5 * time.Second
+
+// ReplicatedMessagesPath is the internal route of a region receiving the chat events of its peers
+const ReplicatedMessagesPath = "/godating-dealls/api/v1/internal/regions/messages"
+
+// Replicator sends the chat events of this region to its peers. The chat connections of an account are held by the
+// region it connects to, the other side of a match may be connected elsewhere
+type Replicator struct {
+	registry *Registry
+	client   *http.Client
+	name     string
+	secret   string
+}
+
+// NewReplicator authenticates to the peers with the replication client of the config. Without it, or with a single
+// region, nothing is replicated
+func NewReplicator(config Config, registry *Registry) *Replicator {
+	replicator := &Replicator{registry: registry, client: &http.Client{Timeout: replicationTimeout}}
+	if config.ReplicationName != "" {
+		replicator.name, replicator.secret = config.ReplicationName, config.ReplicationSecret
+	} else if registry.Enabled() && len(registry.Peers()) > 0 {
+		log.Println("REGION_REPLICATION_CLIENT is not set, chat events are not replicated to the other regions")
+	}
+	return replicator
+}
+
+func (r *Replicator) enabled() bool {
+	return r != nil && r.registry.Enabled() && r.name != ""
+}
+
+// Replicate posts the event to every peer in the background, a peer that fails is logged and not retried
+func (r *Replicator) Replicate(event interface{}) {
+	if !r.enabled() {
+		return
+	}
+	peers := r.registry.Peers()
+	if len(peers) == 0 {
+		return
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Println("could not encode replicated event:", err)
+		return
+	}
+	for _, peer := range peers {
+		go r.send(peer, body)
+	}
+}
+
+func (r *Replicator) send(peer Region, body []byte) {
+	ctx, cancel := context.WithTimeout(context.Background(), replicationTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, peer.BaseURL+ReplicatedMessagesPath, bytes.NewReader(body))
+	if err != nil {
+		log.Printf("could not replicate to region %s: %v", peer.Name, err)
+		return
+	}
+	req.SetBasicAuth(r.name, r.secret)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		log.Printf("could not replicate to region %s: %v", peer.Name, err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("region %s answered %d to a replicated event", peer.Name, resp.StatusCode)
+	}
+}
@@ -0,0 +1,242 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"github.com/robfig/cron/v3"
+	"log"
+	"sync"
+	"time"
+)
+
+var (
+	ErrJobNotFound       = errors.New("job not found")
+	ErrJobAlreadyRunning = errors.New("job is already running")
+	ErrSchedulerStopped  = errors.New("scheduler is stopped")
+)
+
+// JobStatus is a snapshot of a registered job for the admin API
+type JobStatus struct {
+	Name         string
+	Schedule     string
+	Paused       bool
+	Running      bool
+	LastRunAt    *time.Time
+	LastDuration time.Duration
+	LastStatus   string
+	LastError    string
+	NextRunAt    *time.Time
+}
+
+type job struct {
+	name     string
+	spec     string
+	entryID  cron.EntryID
+	fn       func(ctx context.Context) error
+	paused   bool
+	running  bool
+	hasRun   bool
+	lastRun  time.Time
+	duration time.Duration
+	lastErr  error
+}
+
+// Scheduler runs every background job on one cron and keeps the outcome of the last run of each
+type Scheduler struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	cron    *cron.Cron
+	mu      sync.Mutex
+	jobs    map[string]*job
+	order   []string
+	stopped bool
+	// triggered counts the runs started by Trigger, the cron only waits for its own runs
+	triggered sync.WaitGroup
+}
+
+func New(ctx context.Context) *Scheduler {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Scheduler{ctx: ctx, cancel: cancel, cron: cron.New(), jobs: map[string]*job{}}
+}
+
+// Register adds a job, an empty spec keeps it paused so it can still be triggered by hand
+func (s *Scheduler) Register(name string, spec string, fn func(ctx context.Context) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j := &job{name: name, spec: spec, fn: fn, paused: spec == ""}
+	if spec != "" {
+		entryID, err := s.cron.AddFunc(spec, func() { s.run(name, false) })
+		if err != nil {
+			log.Printf("Error adding cron job %s: %v", name, err)
+			j.paused = true
+		}
+		j.entryID = entryID
+	} else {
+		log.Printf("Cron job %s has no schedule, it only runs when triggered", name)
+	}
+
+	s.jobs[name] = j
+	s.order = append(s.order, name)
+}
+
+func (s *Scheduler) Start() {
+	s.cron.Start()
+	log.Println("Cron scheduler started")
+}
+
+// Stop ends the scheduling and waits for the running jobs to finish. When ctx is done first
+// the jobs are cancelled through their context, so they do not hold connections that are about to close
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
+
+	cronDone := s.cron.Stop()
+	done := make(chan struct{})
+	go func() {
+		<-cronDone.Done()
+		s.triggered.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.cancel()
+		log.Println("Cron scheduler stopped")
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) List() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]JobStatus, 0, len(s.order))
+	for _, name := range s.order {
+		statuses = append(statuses, s.status(s.jobs[name]))
+	}
+	return statuses
+}
+
+func (s *Scheduler) Find(name string) (JobStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[name]
+	if !ok {
+		return JobStatus{}, ErrJobNotFound
+	}
+	return s.status(j), nil
+}
+
+// Trigger runs the job now in the background, paused jobs can be triggered too
+func (s *Scheduler) Trigger(name string) error {
+	s.mu.Lock()
+	j, ok := s.jobs[name]
+	if !ok {
+		s.mu.Unlock()
+		return ErrJobNotFound
+	}
+	if j.running {
+		s.mu.Unlock()
+		return ErrJobAlreadyRunning
+	}
+	if s.stopped {
+		s.mu.Unlock()
+		return ErrSchedulerStopped
+	}
+	s.triggered.Add(1)
+	s.mu.Unlock()
+
+	go func() {
+		defer s.triggered.Done()
+		s.run(name, true)
+	}()
+	return nil
+}
+
+func (s *Scheduler) Pause(name string) error {
+	return s.setPaused(name, true)
+}
+
+func (s *Scheduler) Resume(name string) error {
+	s.mu.Lock()
+	j, ok := s.jobs[name]
+	s.mu.Unlock()
+	if ok && j.spec == "" {
+		return errors.New("job has no schedule to resume")
+	}
+	return s.setPaused(name, false)
+}
+
+func (s *Scheduler) setPaused(name string, paused bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[name]
+	if !ok {
+		return ErrJobNotFound
+	}
+	j.paused = paused
+	return nil
+}
+
+func (s *Scheduler) run(name string, manual bool) {
+	s.mu.Lock()
+	j := s.jobs[name]
+	// Scheduled runs respect pause, and a job never overlaps with itself
+	if (j.paused && !manual) || j.running {
+		s.mu.Unlock()
+		return
+	}
+	j.running = true
+	s.mu.Unlock()
+
+	log.Printf("Executing job %s", name)
+	start := time.Now()
+	err := j.fn(s.ctx)
+	duration := time.Since(start)
+	if err != nil {
+		log.Printf("Error executing job %s: %v", name, err)
+	} else {
+		log.Printf("Successfully executed job %s in %s", name, duration)
+	}
+
+	s.mu.Lock()
+	j.running = false
+	j.hasRun = true
+	j.lastRun = start
+	j.duration = duration
+	j.lastErr = err
+	s.mu.Unlock()
+}
+
+func (s *Scheduler) status(j *job) JobStatus {
+	status := JobStatus{
+		Name:       j.name,
+		Schedule:   j.spec,
+		Paused:     j.paused,
+		Running:    j.running,
+		LastStatus: "never_run",
+	}
+	if j.hasRun {
+		lastRun := j.lastRun
+		status.LastRunAt = &lastRun
+		status.LastDuration = j.duration
+		status.LastStatus = "success"
+		if j.lastErr != nil {
+			status.LastStatus = "failed"
+			status.LastError = j.lastErr.Error()
+		}
+	}
+	if j.spec != "" && !j.paused {
+		if next := s.cron.Entry(j.entryID).Next; !next.IsZero() {
+			status.NextRunAt = &next
+		}
+	}
+	return status
+}
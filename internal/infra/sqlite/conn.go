@@ -0,0 +1,281 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"time"
+)
+
+// shimDriver wraps the sqlite driver so every statement goes through the dialect shims first
+type shimDriver struct {
+	driver driver.Driver
+}
+
+func (d *shimDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &shimConn{conn: conn}, nil
+}
+
+type shimConn struct {
+	conn driver.Conn
+}
+
+func (c *shimConn) Prepare(query string) (driver.Stmt, error) {
+	stmt, err := c.conn.Prepare(Rewrite(query))
+	if err != nil {
+		return nil, err
+	}
+	return &shimStmt{stmt: stmt}, nil
+}
+
+func (c *shimConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	preparer, ok := c.conn.(driver.ConnPrepareContext)
+	if !ok {
+		return c.Prepare(query)
+	}
+	stmt, err := preparer.PrepareContext(ctx, Rewrite(query))
+	if err != nil {
+		return nil, err
+	}
+	return &shimStmt{stmt: stmt}, nil
+}
+
+func (c *shimConn) Close() error {
+	return c.conn.Close()
+}
+
+func (c *shimConn) Begin() (driver.Tx, error) {
+	return c.conn.Begin()
+}
+
+func (c *shimConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Begin()
+}
+
+func (c *shimConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// ExecContext also runs the schema changes of the migrations, sqlite has no inline indexes and
+// changes a table by rebuilding it
+func (c *shimConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	switch {
+	case createTable.MatchString(query):
+		var result driver.Result = driver.RowsAffected(0)
+		for _, statement := range translateCreateTable(query) {
+			var err error
+			if result, err = execer.ExecContext(ctx, statement, nil); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	case alterTable.MatchString(query):
+		return rebuildTable(ctx, c.conn, query)
+	}
+
+	if loc := lastInsertIdUpsert.FindStringSubmatchIndex(query); loc != nil {
+		return c.upsertReturningId(ctx, query[:loc[0]], query[loc[2]:loc[3]], bindArgs(args))
+	}
+	result, err := execer.ExecContext(ctx, Rewrite(query), bindArgs(args))
+	if err != nil {
+		return nil, err
+	}
+	// sqlite reports the changes of the last write for a SELECT, MySQL none
+	if selectStatement.MatchString(query) {
+		return driver.RowsAffected(0), nil
+	}
+	return result, nil
+}
+
+func (c *shimConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	rows, err := queryer.QueryContext(ctx, Rewrite(query), bindArgs(args))
+	if err != nil {
+		return nil, err
+	}
+	return &shimRows{rows: rows}, nil
+}
+
+// upsertReturningId answers "ON DUPLICATE KEY UPDATE id = LAST_INSERT_ID(id)" like MySQL: one row affected and
+// the new id on insert, none affected and the id of the existing row otherwise
+func (c *shimConn) upsertReturningId(ctx context.Context, insert string, column string, args []driver.NamedValue) (driver.Result, error) {
+	execer, okExec := c.conn.(driver.ExecerContext)
+	queryer, okQuery := c.conn.(driver.QueryerContext)
+	if !okExec || !okQuery {
+		return nil, driver.ErrSkip
+	}
+
+	result, err := execer.ExecContext(ctx, Rewrite(insert)+" ON CONFLICT DO NOTHING", args)
+	if err != nil {
+		return nil, err
+	}
+	if inserted, err := result.RowsAffected(); err != nil || inserted > 0 {
+		return result, err
+	}
+
+	query := fmt.Sprintf("%s ON CONFLICT DO UPDATE SET %s = %s RETURNING %s", Rewrite(insert), column, column, column)
+	rows, err := queryer.QueryContext(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make([]driver.Value, 1)
+	if err := rows.Next(values); err != nil {
+		return nil, fmt.Errorf("could not read the id of the existing row: %v", err)
+	}
+	id, ok := values[0].(int64)
+	if !ok {
+		return nil, fmt.Errorf("the id of the existing row is %T, not an integer", values[0])
+	}
+	return existingRowResult(id), nil
+}
+
+type existingRowResult int64
+
+func (r existingRowResult) LastInsertId() (int64, error) {
+	return int64(r), nil
+}
+
+func (r existingRowResult) RowsAffected() (int64, error) {
+	return 0, nil
+}
+
+type shimStmt struct {
+	stmt driver.Stmt
+}
+
+func (s *shimStmt) Close() error {
+	return s.stmt.Close()
+}
+
+func (s *shimStmt) NumInput() int {
+	return s.stmt.NumInput()
+}
+
+func (s *shimStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.stmt.Exec(bindValues(args))
+}
+
+func (s *shimStmt) Query(args []driver.Value) (driver.Rows, error) {
+	rows, err := s.stmt.Query(bindValues(args))
+	if err != nil {
+		return nil, err
+	}
+	return &shimRows{rows: rows}, nil
+}
+
+func (s *shimStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := s.stmt.(driver.StmtExecContext)
+	if !ok {
+		return s.Exec(namedValues(args))
+	}
+	return execer.ExecContext(ctx, bindArgs(args))
+}
+
+func (s *shimStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := s.stmt.(driver.StmtQueryContext)
+	if !ok {
+		return s.Query(namedValues(args))
+	}
+	rows, err := queryer.QueryContext(ctx, bindArgs(args))
+	if err != nil {
+		return nil, err
+	}
+	return &shimRows{rows: rows}, nil
+}
+
+// shimRows hands out the times computed by the queries as time.Time, the driver only converts
+// the columns declared as a date or a timestamp
+type shimRows struct {
+	rows driver.Rows
+}
+
+func (r *shimRows) Columns() []string {
+	return r.rows.Columns()
+}
+
+func (r *shimRows) Close() error {
+	return r.rows.Close()
+}
+
+// ColumnTypeDatabaseTypeName passes the declared types through, the schema drift check compares them
+func (r *shimRows) ColumnTypeDatabaseTypeName(index int) string {
+	if typed, ok := r.rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return typed.ColumnTypeDatabaseTypeName(index)
+	}
+	return ""
+}
+
+func (r *shimRows) Next(dest []driver.Value) error {
+	if err := r.rows.Next(dest); err != nil {
+		return err
+	}
+	for i, value := range dest {
+		text, ok := value.(string)
+		if !ok || len(text) < len(timestampLayout) {
+			continue
+		}
+		if t, err := time.ParseInLocation("2006-01-02 15:04:05.999999999", text, time.UTC); err == nil {
+			dest[i] = t
+		}
+	}
+	return nil
+}
+
+func bindArgs(args []driver.NamedValue) []driver.NamedValue {
+	bound := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		bound[i] = arg
+		bound[i].Value = bindValue(arg.Value)
+	}
+	return bound
+}
+
+func bindValues(args []driver.Value) []driver.Value {
+	bound := make([]driver.Value, len(args))
+	for i, arg := range args {
+		bound[i] = bindValue(arg)
+	}
+	return bound
+}
+
+func namedValues(args []driver.NamedValue) []driver.Value {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	return values
+}
+
+// bindValue stores a time as UTC text like CURRENT_TIMESTAMP does, so the two compare as text.
+// A time at midnight is a date, the columns it is compared with are DATE
+func bindValue(value driver.Value) driver.Value {
+	t, ok := value.(time.Time)
+	if !ok {
+		return value
+	}
+	t = t.UTC()
+	if t.Equal(t.Truncate(24 * time.Hour)) {
+		return t.Format(dateLayout)
+	}
+	return t.Format("2006-01-02 15:04:05.999999")
+}
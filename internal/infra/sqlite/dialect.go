@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	selectStatement   = regexp.MustCompile(`(?i)^\s*SELECT\b`)
+	insertIgnore      = regexp.MustCompile(`(?i)\bINSERT\s+IGNORE\b`)
+	lockingRead       = regexp.MustCompile(`(?i)\s+FOR\s+(UPDATE|SHARE)\b`)
+	ifCall            = regexp.MustCompile(`(?i)\bIF\s*\(`)
+	greatestCall      = regexp.MustCompile(`(?i)\bGREATEST\s*\(`)
+	leastCall         = regexp.MustCompile(`(?i)\bLEAST\s*\(`)
+	charLengthCall    = regexp.MustCompile(`(?i)\bCHAR_LENGTH\s*\(`)
+	timestampDiffUnit = regexp.MustCompile(`(?i)\bTIMESTAMPDIFF\s*\(\s*(\w+)\s*,`)
+	countDistinctPair = regexp.MustCompile(`(?i)\bCOUNT\s*\(\s*DISTINCT\s+([\w.]+)\s*,\s*([\w.]+)\s*\)`)
+	intervalOperation = regexp.MustCompile(`(?i)(NOW\(\)|CURDATE\(\)|CURRENT_TIMESTAMP|CURRENT_DATE|\?|[\w.]+)\s*([-+])\s*INTERVAL\s+(\d+|\?)\s+(\w+)`)
+	onDuplicateKey    = regexp.MustCompile(`(?i)\bON\s+DUPLICATE\s+KEY\s+UPDATE\b`)
+	insertedValue     = regexp.MustCompile(`(?i)\bVALUES\s*\(\s*(\w+)\s*\)`)
+	// lastInsertIdUpsert is the upsert returning the id of the existing row, the driver runs it in two steps
+	lastInsertIdUpsert = regexp.MustCompile(`(?i)\bON\s+DUPLICATE\s+KEY\s+UPDATE\s+(\w+)\s*=\s*LAST_INSERT_ID\(\s*\w+\s*\)\s*$`)
+)
+
+// Rewrite translates the MySQL syntax the repositories use and sqlite does not parse. Functions sqlite is missing
+// are registered on every connection instead, see registerFunctions
+func Rewrite(query string) string {
+	var tail string
+	if loc := onDuplicateKey.FindStringIndex(query); loc != nil {
+		tail = " ON CONFLICT DO UPDATE SET" + insertedValue.ReplaceAllString(query[loc[1]:], "excluded.$1")
+		query = query[:loc[0]]
+	}
+
+	query = insertIgnore.ReplaceAllString(query, "INSERT OR IGNORE")
+	query = lockingRead.ReplaceAllString(query, "")
+	query += tail
+
+	query = ifCall.ReplaceAllString(query, "IIF(")
+	query = greatestCall.ReplaceAllString(query, "MAX(")
+	query = leastCall.ReplaceAllString(query, "MIN(")
+	query = charLengthCall.ReplaceAllString(query, "LENGTH(")
+	query = timestampDiffUnit.ReplaceAllString(query, "TIMESTAMPDIFF('$1',")
+	query = countDistinctPair.ReplaceAllString(query, "COUNT(DISTINCT $1 || char(0) || $2)")
+	return intervalOperation.ReplaceAllStringFunc(query, func(operation string) string {
+		parts := intervalOperation.FindStringSubmatch(operation)
+		amount := parts[3]
+		if parts[2] == "-" {
+			amount = "-" + amount
+		}
+		return "DATE_ADD(" + parts[1] + ", " + amount + ", '" + strings.ToUpper(parts[4]) + "')"
+	})
+}
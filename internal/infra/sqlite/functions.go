@@ -0,0 +1,241 @@
+package sqlite
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+const (
+	dateLayout      = "2006-01-02"
+	timestampLayout = "2006-01-02 15:04:05"
+	// earthRadiusMeters is the radius ST_Distance_Sphere of MySQL uses by default
+	earthRadiusMeters = 6370986
+)
+
+// timeLayouts are the layouts the driver and sqlite write times in, the ones with a zone first
+var timeLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	timestampLayout,
+	"2006-01-02 15:04",
+	dateLayout,
+}
+
+// dateFormatVerbs are the DATE_FORMAT specifiers the queries use
+var dateFormatVerbs = strings.NewReplacer("%Y", "2006", "%m", "01", "%d", "02", "%H", "15", "%i", "04", "%s", "05")
+
+// registerFunctions adds the MySQL functions the queries call and sqlite does not have. Times are UTC text like
+// CURRENT_TIMESTAMP of sqlite, so they compare with the stored ones as text
+func registerFunctions(conn *sqlite3.SQLiteConn) error {
+	functions := []struct {
+		name string
+		impl interface{}
+		pure bool
+	}{
+		{"CURDATE", func() string { return time.Now().UTC().Format(dateLayout) }, false},
+		{"NOW", func() string { return time.Now().UTC().Format(timestampLayout) }, false},
+		{"RAND", rand.Float64, false},
+		{"CONCAT", concat, true},
+		{"TIMESTAMP", timestamp, true},
+		{"DATE_FORMAT", dateFormat, true},
+		{"DATE_ADD", dateAdd, true},
+		{"TIMESTAMPDIFF", timestampDiff, true},
+		{"POINT", point, true},
+		{"ST_Distance_Sphere", distanceSphere, true},
+		// Only the upsert of matches calls it, the driver answers those itself
+		{"LAST_INSERT_ID", func(value interface{}) interface{} { return value }, true},
+		// One process owns the database file, the named locks of the migrations always succeed
+		{"GET_LOCK", func(name string, timeout int64) int64 { return 1 }, false},
+		{"RELEASE_LOCK", func(name string) int64 { return 1 }, false},
+	}
+	for _, function := range functions {
+		if err := conn.RegisterFunc(function.name, function.impl, function.pure); err != nil {
+			return fmt.Errorf("could not register function %s: %v", function.name, err)
+		}
+	}
+	return nil
+}
+
+// concat is NULL when any value is NULL, like in MySQL
+func concat(values ...interface{}) interface{} {
+	var b strings.Builder
+	for _, value := range values {
+		if value == nil {
+			return nil
+		}
+		b.WriteString(toText(value))
+	}
+	return b.String()
+}
+
+func timestamp(value interface{}) interface{} {
+	t, ok := toTime(value)
+	if !ok {
+		return nil
+	}
+	return t.Format(timestampLayout)
+}
+
+func dateFormat(value interface{}, format string) interface{} {
+	t, ok := toTime(value)
+	if !ok {
+		return nil
+	}
+	return t.Format(dateFormatVerbs.Replace(format))
+}
+
+// dateAdd is what the dialect rewrites "x + INTERVAL n UNIT" to, a date stays a date when whole days are added
+func dateAdd(value interface{}, amount int64, unit string) interface{} {
+	t, ok := toTime(value)
+	if !ok {
+		return nil
+	}
+
+	n := int(amount)
+	switch strings.ToUpper(unit) {
+	case "SECOND":
+		t = t.Add(time.Duration(n) * time.Second)
+	case "MINUTE":
+		t = t.Add(time.Duration(n) * time.Minute)
+	case "HOUR":
+		t = t.Add(time.Duration(n) * time.Hour)
+	case "DAY":
+		t = t.AddDate(0, 0, n)
+	case "WEEK":
+		t = t.AddDate(0, 0, 7*n)
+	case "MONTH":
+		t = t.AddDate(0, n, 0)
+	case "YEAR":
+		t = t.AddDate(n, 0, 0)
+	default:
+		return nil
+	}
+
+	if text := toText(value); len(text) == len(dateLayout) {
+		return t.Format(dateLayout)
+	}
+	return t.Format(timestampLayout)
+}
+
+// timestampDiff counts whole units from start to end, a month or a year is only counted once it is complete
+func timestampDiff(unit string, start interface{}, end interface{}) interface{} {
+	from, ok := toTime(start)
+	if !ok {
+		return nil
+	}
+	to, ok := toTime(end)
+	if !ok {
+		return nil
+	}
+
+	switch strings.ToUpper(unit) {
+	case "SECOND":
+		return int64(to.Sub(from) / time.Second)
+	case "MINUTE":
+		return int64(to.Sub(from) / time.Minute)
+	case "HOUR":
+		return int64(to.Sub(from) / time.Hour)
+	case "DAY":
+		return int64(to.Sub(from) / (24 * time.Hour))
+	case "WEEK":
+		return int64(to.Sub(from) / (7 * 24 * time.Hour))
+	case "MONTH":
+		return monthsBetween(from, to)
+	case "YEAR":
+		return monthsBetween(from, to) / 12
+	}
+	return nil
+}
+
+func monthsBetween(from time.Time, to time.Time) int64 {
+	if to.Before(from) {
+		return -monthsBetween(to, from)
+	}
+	months := (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+	if from.AddDate(0, months, 0).After(to) {
+		months--
+	}
+	return int64(months)
+}
+
+// point keeps the coordinates as text, only ST_Distance_Sphere reads it
+func point(longitude interface{}, latitude interface{}) interface{} {
+	x, okX := toFloat(longitude)
+	y, okY := toFloat(latitude)
+	if !okX || !okY {
+		return nil
+	}
+	return strconv.FormatFloat(x, 'f', -1, 64) + " " + strconv.FormatFloat(y, 'f', -1, 64)
+}
+
+// distanceSphere is the haversine distance in meters between two points of longitude and latitude
+func distanceSphere(from interface{}, to interface{}) interface{} {
+	var lon1, lat1, lon2, lat2 float64
+	if from == nil || to == nil {
+		return nil
+	}
+	if _, err := fmt.Sscan(toText(from), &lon1, &lat1); err != nil {
+		return nil
+	}
+	if _, err := fmt.Sscan(toText(to), &lon2, &lat2); err != nil {
+		return nil
+	}
+
+	radians := math.Pi / 180
+	dLat := (lat2 - lat1) * radians
+	dLon := (lon2 - lon1) * radians
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1*radians)*math.Cos(lat2*radians)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(a))
+}
+
+func toText(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string, []byte:
+		f, err := strconv.ParseFloat(toText(v), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// toTime reads the text times sqlite and the driver store, in UTC unless the text has a zone
+func toTime(value interface{}) (time.Time, bool) {
+	if value == nil {
+		return time.Time{}, false
+	}
+	text := strings.TrimSpace(toText(value))
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, text, time.UTC); err == nil {
+			return t.UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
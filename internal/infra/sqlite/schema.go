@@ -0,0 +1,212 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var (
+	createTable     = regexp.MustCompile(`(?is)^\s*CREATE\s+TABLE\s+(IF\s+NOT\s+EXISTS\s+)?["\x60]?(\w+)["\x60]?\s*\((.*)\)[^)]*$`)
+	alterTable      = regexp.MustCompile(`(?is)^\s*ALTER\s+TABLE\s+["\x60]?(\w+)["\x60]?\s+(.*)$`)
+	autoIncrementPK = regexp.MustCompile(`(?i)\b(INTEGER|INT|BIGINT)(\s+UNSIGNED)?\s+AUTO_INCREMENT\s+PRIMARY\s+KEY\b`)
+	unsignedType    = regexp.MustCompile(`(?i)\s+UNSIGNED\b`)
+	enumType        = regexp.MustCompile(`(?i)\bENUM\s*\([^)]*\)`)
+	jsonType        = regexp.MustCompile(`(?i)\bJSON\b`)
+	onUpdate        = regexp.MustCompile(`(?i)\s+ON\s+UPDATE\s+CURRENT_TIMESTAMP\b`)
+	generatedColumn = regexp.MustCompile(`(?i)\bAS\s*\(`)
+	inlineIndex     = regexp.MustCompile(`(?is)^(INDEX|KEY)\s+(\w+)\s*(\(.*\))$`)
+	inlineUnique    = regexp.MustCompile(`(?is)^UNIQUE\s+(KEY|INDEX)\s+\w+\s*(\(.*\))$`)
+	addIndex        = regexp.MustCompile(`(?is)^ADD\s+(INDEX|KEY)\s+(\w+)\s*(\(.*\))$`)
+	addUnique       = regexp.MustCompile(`(?is)^ADD\s+(CONSTRAINT\s+\w+\s+)?UNIQUE\s+((KEY|INDEX)\s+)?(\w+\s*)?(\(.*\))$`)
+	addForeignKey   = regexp.MustCompile(`(?is)^ADD\s+(CONSTRAINT\s+\w+\s+)?(FOREIGN\s+KEY\s+.*)$`)
+	addColumn       = regexp.MustCompile(`(?is)^ADD\s+(COLUMN\s+)?(\w+)\s+(.*)$`)
+	modifyColumn    = regexp.MustCompile(`(?is)^MODIFY\s+(COLUMN\s+)?(\w+)\s+(.*)$`)
+)
+
+// tableConstraints are the first words of the items of a table that are not columns
+var tableConstraints = map[string]bool{"PRIMARY": true, "UNIQUE": true, "FOREIGN": true, "CHECK": true, "CONSTRAINT": true}
+
+// translateCreateTable returns the sqlite statements for a MySQL CREATE TABLE, the inline indexes become
+// CREATE INDEX statements since sqlite only has them on their own
+func translateCreateTable(query string) []string {
+	match := createTable.FindStringSubmatch(query)
+	table := match[2]
+
+	var items, indexes []string
+	for _, item := range splitItems(match[3]) {
+		if index := inlineIndex.FindStringSubmatch(item); index != nil {
+			indexes = append(indexes, fmt.Sprintf("CREATE INDEX %s ON %s %s", index[2], table, index[3]))
+			continue
+		}
+		if unique := inlineUnique.FindStringSubmatch(item); unique != nil {
+			items = append(items, "UNIQUE "+unique[2])
+			continue
+		}
+		items = append(items, translateColumn(item))
+	}
+
+	statements := []string{fmt.Sprintf("CREATE TABLE %s%s\n(\n    %s\n)", match[1], table, strings.Join(items, ",\n    "))}
+	return append(statements, indexes...)
+}
+
+func translateColumn(definition string) string {
+	definition = autoIncrementPK.ReplaceAllString(definition, "INTEGER PRIMARY KEY AUTOINCREMENT")
+	definition = unsignedType.ReplaceAllString(definition, "")
+	definition = enumType.ReplaceAllString(definition, "TEXT")
+	definition = jsonType.ReplaceAllString(definition, "TEXT")
+	definition = ifCall.ReplaceAllString(definition, "IIF(")
+	return onUpdate.ReplaceAllString(definition, "")
+}
+
+// rebuildTable runs a MySQL ALTER TABLE the way sqlite changes a table: a copy with the new definition replaces it
+// and its indexes are created again. The foreign keys are off meanwhile so the tables referencing it keep their rows
+func rebuildTable(ctx context.Context, conn driver.Conn, query string) (driver.Result, error) {
+	execer, okExec := conn.(driver.ExecerContext)
+	queryer, okQuery := conn.(driver.QueryerContext)
+	if !okExec || !okQuery {
+		return nil, driver.ErrSkip
+	}
+
+	match := alterTable.FindStringSubmatch(query)
+	table := match[1]
+	rows, err := querySchema(ctx, queryer, "SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?", table)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("no such table: %s", table)
+	}
+	indexes, err := querySchema(ctx, queryer, "SELECT sql FROM sqlite_master WHERE type = 'index' AND tbl_name = ? AND sql IS NOT NULL", table)
+	if err != nil {
+		return nil, err
+	}
+
+	var columns, constraints, names, copied []string
+	for _, item := range splitItems(createTable.FindStringSubmatch(rows[0])[3]) {
+		word := strings.Fields(item)[0]
+		if tableConstraints[strings.ToUpper(word)] {
+			constraints = append(constraints, item)
+			continue
+		}
+		name := strings.Trim(word, "`\"[]")
+		columns = append(columns, item)
+		names = append(names, name)
+		// A generated column is computed again by the copy
+		if !generatedColumn.MatchString(item) {
+			copied = append(copied, name)
+		}
+	}
+
+	for _, clause := range splitItems(match[2]) {
+		switch {
+		case addIndex.MatchString(clause):
+			index := addIndex.FindStringSubmatch(clause)
+			indexes = append(indexes, fmt.Sprintf("CREATE INDEX %s ON %s %s", index[2], table, index[3]))
+		case addUnique.MatchString(clause):
+			constraints = append(constraints, "UNIQUE "+addUnique.FindStringSubmatch(clause)[5])
+		case addForeignKey.MatchString(clause):
+			constraints = append(constraints, addForeignKey.FindStringSubmatch(clause)[2])
+		case addColumn.MatchString(clause):
+			column := addColumn.FindStringSubmatch(clause)
+			columns = append(columns, column[2]+" "+translateColumn(column[3]))
+		case modifyColumn.MatchString(clause):
+			column := modifyColumn.FindStringSubmatch(clause)
+			found := false
+			for i, name := range names {
+				if strings.EqualFold(name, column[2]) {
+					columns[i] = column[2] + " " + translateColumn(column[3])
+					found = true
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("no such column: %s.%s", table, column[2])
+			}
+		default:
+			return nil, fmt.Errorf("ALTER TABLE clause not supported by the sqlite shim: %s", clause)
+		}
+	}
+
+	rebuilt := table + "__rebuild"
+	copiedColumns := strings.Join(copied, ", ")
+	statements := []string{
+		fmt.Sprintf("CREATE TABLE %s\n(\n    %s\n)", rebuilt, strings.Join(append(columns, constraints...), ",\n    ")),
+		fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s", rebuilt, copiedColumns, copiedColumns, table),
+		"DROP TABLE " + table,
+		fmt.Sprintf("ALTER TABLE %s RENAME TO %s", rebuilt, table),
+	}
+	statements = append(statements, indexes...)
+
+	if _, err := execer.ExecContext(ctx, "PRAGMA foreign_keys = OFF", nil); err != nil {
+		return nil, err
+	}
+	defer execer.ExecContext(context.WithoutCancel(ctx), "PRAGMA foreign_keys = ON", nil)
+
+	// A savepoint works inside and outside of a transaction, a failing step leaves the table as it was
+	if _, err := execer.ExecContext(ctx, "SAVEPOINT rebuild_table", nil); err != nil {
+		return nil, err
+	}
+	for _, statement := range statements {
+		if _, err := execer.ExecContext(ctx, statement, nil); err != nil {
+			_, _ = execer.ExecContext(context.WithoutCancel(ctx), "ROLLBACK TO rebuild_table", nil)
+			_, _ = execer.ExecContext(context.WithoutCancel(ctx), "RELEASE rebuild_table", nil)
+			return nil, fmt.Errorf("could not rebuild table %s: %v", table, err)
+		}
+	}
+	if _, err := execer.ExecContext(ctx, "RELEASE rebuild_table", nil); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func querySchema(ctx context.Context, queryer driver.QueryerContext, query string, table string) ([]string, error) {
+	rows, err := queryer.QueryContext(ctx, query, []driver.NamedValue{{Ordinal: 1, Value: table}})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var statements []string
+	values := make([]driver.Value, 1)
+	for {
+		if err := rows.Next(values); err != nil {
+			if errors.Is(err, io.EOF) {
+				return statements, nil
+			}
+			return nil, err
+		}
+		statements = append(statements, toText(values[0]))
+	}
+}
+
+// splitItems splits on the commas outside of parentheses and quotes
+func splitItems(list string) []string {
+	var items []string
+	var quote rune
+	depth, start := 0, 0
+	for i, r := range list {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			items = append(items, strings.TrimSpace(list[start:i]))
+			start = i + 1
+		}
+	}
+	if rest := strings.TrimSpace(list[start:]); rest != "" {
+		items = append(items, rest)
+	}
+	return items
+}
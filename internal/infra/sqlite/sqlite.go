@@ -0,0 +1,36 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// DriverName is the sqlite driver with the MySQL dialect shims, the repositories keep their MySQL queries.
+// It is meant for local development only, see Rewrite for what is translated
+const DriverName = "sqlite3_mysql"
+
+func init() {
+	sql.Register(DriverName, &shimDriver{driver: &sqlite3.SQLiteDriver{ConnectHook: registerFunctions}})
+}
+
+// Open opens the database file at path, creating it when missing. Transactions take the write lock when they begin
+// so two of them never deadlock upgrading a read lock, the others wait for it up to the busy timeout
+func Open(ctx context.Context, path string) (*sql.DB, error) {
+	dsn := fmt.Sprintf("file:%s?_foreign_keys=on&_busy_timeout=10000&_journal_mode=WAL&_txlock=immediate", path)
+	db, err := sql.Open(DriverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("could not open sqlite database: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("could not open sqlite database %s: %v", path, err)
+	}
+	return db, nil
+}
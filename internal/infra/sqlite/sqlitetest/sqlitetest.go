@@ -0,0 +1,87 @@
+// Package sqlitetest opens throwaway sqlite databases with the whole schema, for the tests and benchmarks that run
+// the usecases against a real database
+package sqlitetest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"godating-dealls/internal/infra/mysql/migrations"
+	"godating-dealls/internal/infra/sqlite"
+	"io"
+	"log"
+	"path/filepath"
+	"testing"
+)
+
+// Open creates a migrated database in a temporary directory, it is closed and removed when tb ends. The log is
+// silenced until then, the lines of the migrations and the usecases would break up the benchmark results
+func Open(tb testing.TB) *sql.DB {
+	tb.Helper()
+	output := log.Writer()
+	log.SetOutput(io.Discard)
+	tb.Cleanup(func() { log.SetOutput(output) })
+	ctx := context.Background()
+	db, err := sqlite.Open(ctx, filepath.Join(tb.TempDir(), "godating.db"))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { _ = db.Close() })
+
+	if _, err := migrations.NewMigrator(db).Up(ctx); err != nil {
+		tb.Fatalf("could not migrate sqlite database: %v", err)
+	}
+	return db
+}
+
+// Account is a seeded account and its user
+type Account struct {
+	AccountId int64
+	UserId    int64
+	Email     string
+}
+
+// CreateAccounts seeds count unverified accounts, each with a user in Jakarta and dailyQuota swipes left for today
+func CreateAccounts(tb testing.TB, db *sql.DB, count int, dailyQuota int) []Account {
+	tb.Helper()
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var existing int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM accounts`).Scan(&existing); err != nil {
+		tb.Fatal(err)
+	}
+
+	created := make([]Account, 0, count)
+	for i := existing; i < existing+count; i++ {
+		account := Account{Email: fmt.Sprintf("seed%d@godating.local", i)}
+		result, err := tx.ExecContext(ctx, `INSERT INTO accounts (username, password_hash, email) VALUES (?, ?, ?)`,
+			fmt.Sprintf("seed%d", i), "not-a-hash", account.Email)
+		if err != nil {
+			tb.Fatalf("could not seed account: %v", err)
+		}
+		account.AccountId, _ = result.LastInsertId()
+
+		result, err = tx.ExecContext(ctx, `INSERT INTO users (account_id, full_name, date_of_birth, age, gender, address, bio, latitude, longitude) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			account.AccountId, fmt.Sprintf("Seed %d", i), "1995-02-14", 29, []string{"L", "P"}[i%2], "Jakarta", "Seeded", -6.2, 106.8)
+		if err != nil {
+			tb.Fatalf("could not seed user: %v", err)
+		}
+		account.UserId, _ = result.LastInsertId()
+
+		if _, err := tx.ExecContext(ctx, `INSERT INTO daily_quotas (account_id, swipe_count, total_quota) VALUES (?, ?, ?)`,
+			account.AccountId, 0, dailyQuota); err != nil {
+			tb.Fatalf("could not seed daily quota: %v", err)
+		}
+		created = append(created, account)
+	}
+
+	if err := tx.Commit(); err != nil {
+		tb.Fatal(err)
+	}
+	return created
+}
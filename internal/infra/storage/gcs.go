@@ -0,0 +1,250 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	gcsAPIURL      = "https://storage.googleapis.com"
+	gcsScope       = "https://www.googleapis.com/auth/devstorage.full_control"
+	gcsMetadataURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+)
+
+// GCSStorage stores objects in a Google Cloud Storage bucket through the JSON API. It authenticates with the service
+// account key in CredentialsFile, or with the metadata server of the instance when there is none
+type GCSStorage struct {
+	Bucket          string
+	CredentialsFile string
+	PublicURL       string
+	Client          *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// GCSConfig PublicURL is the base of the links (e.g. a CDN), the bucket itself by default
+type GCSConfig struct {
+	Bucket          string
+	CredentialsFile string
+	PublicURL       string `validate:"omitempty,url"`
+}
+
+func NewGCSStorage(config GCSConfig) Storage {
+	g := &GCSStorage{
+		Bucket:          config.Bucket,
+		CredentialsFile: config.CredentialsFile,
+		PublicURL:       config.PublicURL,
+		Client:          &http.Client{Timeout: 30 * time.Second},
+	}
+	if g.PublicURL == "" {
+		g.PublicURL = gcsAPIURL + "/" + g.Bucket
+	}
+	return g
+}
+
+func (g *GCSStorage) Put(ctx context.Context, key string, contentType string, data []byte) error {
+	query := url.Values{"uploadType": {"media"}, "name": {key}}
+	resp, err := g.do(ctx, http.MethodPost, gcsAPIURL+"/upload/storage/v1/b/"+url.PathEscape(g.Bucket)+"/o?"+query.Encode(), contentType, data)
+	if err != nil {
+		return fmt.Errorf("could not upload object: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("upload responded with status %d: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+func (g *GCSStorage) Delete(ctx context.Context, key string) error {
+	resp, err := g.do(ctx, http.MethodDelete, g.bucketURL()+"/o/"+url.PathEscape(key), "", nil)
+	if err != nil {
+		return fmt.Errorf("could not delete object: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrObjectNotFound
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("delete responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (g *GCSStorage) URL(key string) string {
+	return joinURL(g.PublicURL, escapeKey(key))
+}
+
+// Check asks for the bucket name, a missing bucket or rejected credentials fail it
+func (g *GCSStorage) Check(ctx context.Context) error {
+	if g.Bucket == "" {
+		return errors.New("GCS_BUCKET is not set")
+	}
+	resp, err := g.do(ctx, http.MethodGet, g.bucketURL()+"?fields=name", "", nil)
+	if err != nil {
+		return fmt.Errorf("could not reach bucket: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("bucket responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SetLifecycle replaces every lifecycle rule of the bucket, no rules removes them
+func (g *GCSStorage) SetLifecycle(ctx context.Context, rules []LifecycleRule) error {
+	type condition struct {
+		Age           int      `json:"age"`
+		MatchesPrefix []string `json:"matchesPrefix,omitempty"`
+	}
+	type rule struct {
+		Action    map[string]string `json:"action"`
+		Condition condition         `json:"condition"`
+	}
+	lifecycleRules := make([]rule, 0, len(rules))
+	for _, r := range rules {
+		c := condition{Age: r.ExpireAfterDays}
+		if r.Prefix != "" {
+			c.MatchesPrefix = []string{r.Prefix}
+		}
+		lifecycleRules = append(lifecycleRules, rule{Action: map[string]string{"type": "Delete"}, Condition: c})
+	}
+	body, err := json.Marshal(map[string]interface{}{"lifecycle": map[string]interface{}{"rule": lifecycleRules}})
+	if err != nil {
+		return fmt.Errorf("could not encode lifecycle rules: %v", err)
+	}
+
+	resp, err := g.do(ctx, http.MethodPatch, g.bucketURL()+"?fields=lifecycle", "application/json", body)
+	if err != nil {
+		return fmt.Errorf("could not set lifecycle rules: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("lifecycle responded with status %d: %s", resp.StatusCode, respBody)
+	}
+	return nil
+}
+
+func (g *GCSStorage) bucketURL() string {
+	return gcsAPIURL + "/storage/v1/b/" + url.PathEscape(g.Bucket)
+}
+
+func (g *GCSStorage) do(ctx context.Context, method string, target string, contentType string, data []byte) (*http.Response, error) {
+	token, err := g.token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return g.Client.Do(req)
+}
+
+// token is cached until a minute before it expires
+func (g *GCSStorage) token(ctx context.Context) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.accessToken != "" && time.Now().Before(g.expiresAt.Add(-time.Minute)) {
+		return g.accessToken, nil
+	}
+
+	var req *http.Request
+	var err error
+	if g.CredentialsFile != "" {
+		req, err = g.serviceAccountTokenRequest(ctx)
+	} else {
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, gcsMetadataURL, nil)
+		if err == nil {
+			req.Header.Set("Metadata-Flavor", "Google")
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := g.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("could not get access token: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("access token responded with status %d", resp.StatusCode)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil || token.AccessToken == "" {
+		return "", errors.New("could not read access token")
+	}
+	g.accessToken = token.AccessToken
+	g.expiresAt = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	return g.accessToken, nil
+}
+
+// serviceAccountTokenRequest exchanges an assertion signed with the service account key, RFC 7523
+func (g *GCSStorage) serviceAccountTokenRequest(ctx context.Context) (*http.Request, error) {
+	content, err := os.ReadFile(g.CredentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read GCS credentials: %v", err)
+	}
+	var credentials struct {
+		ClientEmail string `json:"client_email"`
+		PrivateKey  string `json:"private_key"`
+		TokenURI    string `json:"token_uri"`
+	}
+	if err := json.Unmarshal(content, &credentials); err != nil {
+		return nil, fmt.Errorf("could not parse GCS credentials: %v", err)
+	}
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(credentials.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse GCS private key: %v", err)
+	}
+	if credentials.TokenURI == "" {
+		credentials.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+
+	now := time.Now()
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   credentials.ClientEmail,
+		"scope": gcsScope,
+		"aud":   credentials.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not sign GCS assertion: %v", err)
+	}
+
+	form := url.Values{"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"}, "assertion": {assertion}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, credentials.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req, nil
+}
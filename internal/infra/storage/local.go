@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// LocalMediaPath is where the API serves the files of a local storage
+const LocalMediaPath = "/godating-dealls/media/"
+
+// LocalStorage writes to a directory on the server disk, for development and single instance deployments
+type LocalStorage struct {
+	Dir       string
+	PublicURL string
+}
+
+// LocalConfig the public URL is by default the media path of this API on localhost
+type LocalConfig struct {
+	Dir       string `validate:"required"`
+	PublicURL string `validate:"url"`
+}
+
+func NewLocalStorage(config LocalConfig) Storage {
+	return &LocalStorage{Dir: config.Dir, PublicURL: config.PublicURL}
+}
+
+func (l *LocalStorage) Put(ctx context.Context, key string, contentType string, data []byte) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("could not create storage directory: %v", err)
+	}
+
+	// Written under a temporary name first so a half written file is never served
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("could not write object: %v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("could not write object: %v", err)
+	}
+	return nil
+}
+
+func (l *LocalStorage) Delete(ctx context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ErrObjectNotFound
+		}
+		return fmt.Errorf("could not delete object: %v", err)
+	}
+	return nil
+}
+
+// Check writes and removes a probe file, the directory must exist or be creatable and be writable
+func (l *LocalStorage) Check(ctx context.Context) error {
+	if err := os.MkdirAll(l.Dir, 0o755); err != nil {
+		return fmt.Errorf("could not create storage directory: %v", err)
+	}
+	probe, err := os.CreateTemp(l.Dir, ".check-*")
+	if err != nil {
+		return fmt.Errorf("storage directory is not writable: %v", err)
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
+
+func (l *LocalStorage) URL(key string) string {
+	return joinURL(l.PublicURL, key)
+}
+
+// path keeps the key inside the storage directory
+func (l *LocalStorage) path(key string) (string, error) {
+	if !filepath.IsLocal(key) {
+		return "", fmt.Errorf("invalid object key %q", key)
+	}
+	return filepath.Join(l.Dir, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,235 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// S3Storage stores objects in an S3 compatible bucket, requests are signed with AWS signature version 4
+type S3Storage struct {
+	Bucket          string
+	Region          string
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+	PublicURL       string
+	Client          *http.Client
+}
+
+// S3Config is a bucket of S3 or of an S3 compatible server. Endpoint switches to path style requests for MinIO and
+// the like, PublicURL is the base of the links (e.g. a CDN), the bucket itself by default
+type S3Config struct {
+	Bucket          string
+	Region          string `validate:"required"`
+	Endpoint        string `validate:"omitempty,url"`
+	AccessKeyID     string
+	SecretAccessKey string
+	PublicURL       string `validate:"omitempty,url"`
+}
+
+func NewS3Storage(config S3Config) Storage {
+	s := &S3Storage{
+		Bucket:          config.Bucket,
+		Region:          config.Region,
+		Endpoint:        strings.TrimRight(config.Endpoint, "/"),
+		AccessKeyID:     config.AccessKeyID,
+		SecretAccessKey: config.SecretAccessKey,
+		PublicURL:       config.PublicURL,
+		Client:          &http.Client{Timeout: 30 * time.Second},
+	}
+	if s.PublicURL == "" {
+		s.PublicURL = s.objectBaseURL()
+	}
+	return s
+}
+
+func (s *S3Storage) Put(ctx context.Context, key string, contentType string, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key), bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("could not create upload request: %v", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	s.sign(req, data, time.Now())
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not upload object: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("upload responded with status %d: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key), nil)
+	if err != nil {
+		return fmt.Errorf("could not create delete request: %v", err)
+	}
+	s.sign(req, nil, time.Now())
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not delete object: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrObjectNotFound
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("delete responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Check asks for the bucket, a missing bucket or rejected credentials fail it
+func (s *S3Storage) Check(ctx context.Context) error {
+	if s.Bucket == "" {
+		return errors.New("S3_BUCKET is not set")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, s.objectBaseURL()+"/", nil)
+	if err != nil {
+		return fmt.Errorf("could not create bucket request: %v", err)
+	}
+	s.sign(req, nil, time.Now())
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not reach bucket: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("bucket responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SetLifecycle replaces every lifecycle rule of the bucket, incomplete multipart uploads are aborted after a day
+func (s *S3Storage) SetLifecycle(ctx context.Context, rules []LifecycleRule) error {
+	var body bytes.Buffer
+	body.WriteString(`<LifecycleConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">`)
+	for i, rule := range rules {
+		var prefix bytes.Buffer
+		if err := xml.EscapeText(&prefix, []byte(rule.Prefix)); err != nil {
+			return fmt.Errorf("could not encode lifecycle rule: %v", err)
+		}
+		fmt.Fprintf(&body, `<Rule><ID>godating-%d</ID><Filter><Prefix>%s</Prefix></Filter><Status>Enabled</Status><Expiration><Days>%d</Days></Expiration><AbortIncompleteMultipartUpload><DaysAfterInitiation>1</DaysAfterInitiation></AbortIncompleteMultipartUpload></Rule>`,
+			i+1, prefix.String(), rule.ExpireAfterDays)
+	}
+	body.WriteString(`</LifecycleConfiguration>`)
+	data := body.Bytes()
+
+	method := http.MethodPut
+	if len(rules) == 0 {
+		method, data = http.MethodDelete, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.objectBaseURL()+"/?lifecycle=", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("could not create lifecycle request: %v", err)
+	}
+	if data != nil {
+		sum := md5.Sum(data)
+		req.Header.Set("Content-MD5", base64.StdEncoding.EncodeToString(sum[:]))
+		req.Header.Set("Content-Type", "application/xml")
+	}
+	s.sign(req, data, time.Now())
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not set lifecycle rules: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("lifecycle responded with status %d: %s", resp.StatusCode, respBody)
+	}
+	return nil
+}
+
+func (s *S3Storage) URL(key string) string {
+	return joinURL(s.PublicURL, escapeKey(key))
+}
+
+func (s *S3Storage) objectBaseURL() string {
+	if s.Endpoint != "" {
+		return s.Endpoint + "/" + s.Bucket
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com", s.Bucket, s.Region)
+}
+
+func (s *S3Storage) objectURL(key string) string {
+	return s.objectBaseURL() + "/" + escapeKey(key)
+}
+
+// sign adds the signature version 4 headers, the payload hash covers the body so it cannot be swapped in transit
+func (s *S3Storage) sign(req *http.Request, payload []byte, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	day := amzDate[:8]
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + s.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretAccessKey), day)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrObjectNotFound        = errors.New("object not found")
+	ErrLifecycleNotSupported = errors.New("lifecycle rules are not supported by this storage driver")
+	ErrInvalidLifecycleRules = errors.New("invalid lifecycle rules, use prefix=days pairs")
+)
+
+// Storage keeps uploaded media, the database only stores the key and URL turns it into a link for clients.
+// Check reports whether the backend can be reached with the configured credentials
+type Storage interface {
+	Put(ctx context.Context, key string, contentType string, data []byte) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+	Check(ctx context.Context) error
+}
+
+// LifecycleRule expires the objects under Prefix ExpireAfterDays after they were written
+type LifecycleRule struct {
+	Prefix          string
+	ExpireAfterDays int
+}
+
+// LifecycleConfigurer is a Storage whose bucket can expire objects on its own
+type LifecycleConfigurer interface {
+	SetLifecycle(ctx context.Context, rules []LifecycleRule) error
+}
+
+const (
+	DefaultDriver         = "local"
+	DefaultLocalDir       = "uploads"
+	DefaultLocalPublicURL = "http://localhost:8000" + LocalMediaPath
+	DefaultRegion         = "us-east-1"
+	DefaultMinIOEndpoint  = "http://localhost:9000"
+)
+
+// Config is read by config.Load. STORAGE_DRIVER picks the backend, the local one reads STORAGE_LOCAL_DIR and
+// STORAGE_PUBLIC_URL and the buckets the S3_*, MINIO_* and GCS_* settings. LifecycleRules are the
+// STORAGE_LIFECYCLE_RULES the storage command applies
+type Config struct {
+	Driver         string `validate:"oneof=local s3 minio gcs"`
+	Local          LocalConfig
+	S3             S3Config
+	MinIO          S3Config
+	GCS            GCSConfig
+	LifecycleRules []LifecycleRule
+}
+
+// NewStorage returns the configured backend, local, s3, minio or gcs
+func NewStorage(config Config) Storage {
+	switch config.Driver {
+	case "s3":
+		return NewS3Storage(config.S3)
+	case "minio":
+		return NewS3Storage(config.MinIO)
+	case "gcs":
+		return NewGCSStorage(config.GCS)
+	default:
+		return NewLocalStorage(config.Local)
+	}
+}
+
+// SetLifecycle replaces the lifecycle rules of the bucket behind storage, the local driver has no bucket
+func SetLifecycle(ctx context.Context, storage Storage, rules []LifecycleRule) error {
+	configurer, ok := storage.(LifecycleConfigurer)
+	if !ok {
+		return ErrLifecycleNotSupported
+	}
+	return configurer.SetLifecycle(ctx, rules)
+}
+
+// ParseLifecycleRules reads "prefix=days,..." like STORAGE_LIFECYCLE_RULES, e.g. "trash/=30,exports/=7"
+func ParseLifecycleRules(value string) ([]LifecycleRule, error) {
+	var rules []LifecycleRule
+	for _, entry := range strings.Split(value, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		prefix, days, ok := strings.Cut(entry, "=")
+		expireAfterDays, err := strconv.Atoi(strings.TrimSpace(days))
+		if !ok || err != nil || expireAfterDays <= 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidLifecycleRules, entry)
+		}
+		rules = append(rules, LifecycleRule{Prefix: strings.TrimSpace(prefix), ExpireAfterDays: expireAfterDays})
+	}
+	return rules, nil
+}
+
+// MediaHandler serves the files of a local storage, the other backends serve their own URLs and it returns nil for them
+func MediaHandler(storage Storage) http.Handler {
+	local, ok := storage.(*LocalStorage)
+	if !ok {
+		return nil
+	}
+	return http.StripPrefix(LocalMediaPath, http.FileServer(http.Dir(local.Dir)))
+}
+
+func joinURL(base string, key string) string {
+	return strings.TrimRight(base, "/") + "/" + key
+}
@@ -0,0 +1,57 @@
+package uptime
+
+import (
+	"context"
+	"errors"
+	"godating-dealls/internal/infra/notifier"
+	"godating-dealls/internal/infra/push"
+)
+
+// TrackNotifier adds the mail component and counts every mail the notifier sends. The log notifier is not
+// configured, its messages never leave the instance
+func (m *Monitor) TrackNotifier(n notifier.Notifier) notifier.Notifier {
+	_, logOnly := n.(notifier.LogNotifier)
+	m.AddPassive(ComponentMail, !logOnly)
+	return trackedNotifier{notifier: n, monitor: m}
+}
+
+type trackedNotifier struct {
+	notifier notifier.Notifier
+	monitor  *Monitor
+}
+
+func (t trackedNotifier) Notify(ctx context.Context, message notifier.Message) error {
+	err := t.notifier.Notify(ctx, message)
+	t.monitor.Record(ComponentMail, err)
+	return err
+}
+
+// TrackPushProviders adds the push component and counts every push of the providers, it is configured once one of
+// them is more than a log provider. A token the provider no longer knows is an answer of the provider, not a failure
+func (m *Monitor) TrackPushProviders(providers map[string]push.Provider) map[string]push.Provider {
+	configured := false
+	tracked := make(map[string]push.Provider, len(providers))
+	for name, provider := range providers {
+		if _, logOnly := provider.(push.LogProvider); !logOnly {
+			configured = true
+		}
+		tracked[name] = trackedProvider{provider: provider, monitor: m}
+	}
+	m.AddPassive(ComponentPush, configured)
+	return tracked
+}
+
+type trackedProvider struct {
+	provider push.Provider
+	monitor  *Monitor
+}
+
+func (t trackedProvider) Send(ctx context.Context, token string, message push.Message) error {
+	err := t.provider.Send(ctx, token, message)
+	if errors.Is(err, push.ErrInvalidToken) {
+		t.monitor.Record(ComponentPush, nil)
+	} else {
+		t.monitor.Record(ComponentPush, err)
+	}
+	return err
+}
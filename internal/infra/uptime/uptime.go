@@ -0,0 +1,261 @@
+package uptime
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Components shown on the status page, in this order when they are added in it
+const (
+	ComponentDatabase = "database"
+	ComponentRedis    = "redis"
+	ComponentStorage  = "storage"
+	ComponentMail     = "mail"
+	ComponentPush     = "push"
+)
+
+// States of a component and of the whole service
+const (
+	StateOperational   = "operational"
+	StateDegraded      = "degraded"
+	StateDown          = "down"
+	StateNotConfigured = "not_configured"
+)
+
+const (
+	DefaultIntervalSeconds   = 30
+	DefaultWindowMinutes     = 60
+	DefaultDegradedErrorRate = 0.05
+	probeTimeout             = 5 * time.Second
+	// A component without a probe is down once half of its calls in the window failed
+	downErrorRate = 0.5
+)
+
+// Config holds how often the probes run, and the window the error rate is counted over. A component is degraded
+// once its error rate in the window reaches DegradedErrorRate. It is read by config.Load from
+// STATUS_CHECK_INTERVAL_SECONDS, STATUS_WINDOW_MINUTES and STATUS_DEGRADED_ERROR_RATE, an interval of 0 turns the
+// probes off
+type Config struct {
+	Interval          time.Duration `validate:"min=0"`
+	Window            time.Duration `validate:"min=1m"`
+	DegradedErrorRate float64       `validate:"gt=0,lte=1"`
+}
+
+// Probe reports whether a dependency can be reached, it is called with a timeout
+type Probe func(ctx context.Context) error
+
+// Status of one component. Checks and Failures are counted over the window, from the probes and from the calls
+// recorded by the wrapped clients. The error itself is only logged, the status page is not meant to show it
+type Status struct {
+	Name          string
+	State         string
+	Checks        int
+	Failures      int
+	ErrorRate     float64
+	Latency       time.Duration
+	LastCheckedAt time.Time
+	LastFailureAt time.Time
+}
+
+// Snapshot is the status of every component at one moment
+type Snapshot struct {
+	State      string
+	StartedAt  time.Time
+	Window     time.Duration
+	Components []Status
+}
+
+// bucket counts the calls of one minute, the window keeps at most one bucket per minute
+type bucket struct {
+	minute   int64
+	checks   int
+	failures int
+}
+
+type component struct {
+	name          string
+	configured    bool
+	critical      bool
+	probe         Probe
+	probeFailed   bool
+	latency       time.Duration
+	lastCheckedAt time.Time
+	lastFailureAt time.Time
+	buckets       []bucket
+}
+
+// Monitor keeps the recent outcomes of the dependencies of the service in memory, each instance reports its own
+type Monitor struct {
+	config     Config
+	startedAt  time.Time
+	mu         sync.Mutex
+	components []*component
+}
+
+func New(config Config) *Monitor {
+	return &Monitor{config: config, startedAt: time.Now()}
+}
+
+// AddProbe adds a component that is probed on the interval. A critical component that is down takes the whole
+// service down, the requests cannot be served without it
+func (m *Monitor) AddProbe(name string, critical bool, probe Probe) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.components = append(m.components, &component{name: name, configured: true, critical: critical, probe: probe})
+}
+
+// AddPassive adds a component that is only known by the calls recorded for it, a component that is not configured
+// only writes to the log and is reported as such
+func (m *Monitor) AddPassive(name string, configured bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.components = append(m.components, &component{name: name, configured: configured})
+}
+
+// Record counts the outcome of one call to the component, a nil err is a success
+func (m *Monitor) Record(name string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if c := m.find(name); c != nil {
+		m.record(c, time.Now(), err)
+	}
+}
+
+// Run probes right away and then on the interval until ctx is done
+func (m *Monitor) Run(ctx context.Context) {
+	if m.config.Interval <= 0 {
+		return
+	}
+	m.probeAll(ctx)
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.probeAll(ctx)
+		}
+	}
+}
+
+// probeAll probes one component after the other, the probes are few and each has its own timeout
+func (m *Monitor) probeAll(ctx context.Context) {
+	m.mu.Lock()
+	components := append([]*component(nil), m.components...)
+	m.mu.Unlock()
+
+	for _, c := range components {
+		if c.probe == nil {
+			continue
+		}
+		probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+		started := time.Now()
+		err := c.probe(probeCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Printf("Status probe of %s failed: %v", c.name, err)
+		}
+
+		m.mu.Lock()
+		c.probeFailed = err != nil
+		c.latency = time.Since(started)
+		m.record(c, started, err)
+		m.mu.Unlock()
+	}
+}
+
+// Snapshot returns the state of every component, in the order they were added
+func (m *Monitor) Snapshot() Snapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	snapshot := Snapshot{State: StateOperational, StartedAt: m.startedAt, Window: m.config.Window}
+	for _, c := range m.components {
+		m.prune(c, now)
+		status := Status{
+			Name:          c.name,
+			Latency:       c.latency,
+			LastCheckedAt: c.lastCheckedAt,
+			LastFailureAt: c.lastFailureAt,
+		}
+		for _, b := range c.buckets {
+			status.Checks += b.checks
+			status.Failures += b.failures
+		}
+		if status.Checks > 0 {
+			status.ErrorRate = float64(status.Failures) / float64(status.Checks)
+		}
+		status.State = m.state(c, status)
+		snapshot.Components = append(snapshot.Components, status)
+
+		switch {
+		case status.State == StateDown && c.critical:
+			snapshot.State = StateDown
+		case status.State == StateDown || status.State == StateDegraded:
+			if snapshot.State == StateOperational {
+				snapshot.State = StateDegraded
+			}
+		}
+	}
+	return snapshot
+}
+
+// state of a probed component follows its last probe, one without a probe is judged by the error rate only. Either
+// is degraded while the error rate of the window is at or above the configured rate
+func (m *Monitor) state(c *component, status Status) string {
+	switch {
+	case !c.configured:
+		return StateNotConfigured
+	case c.probe != nil && c.probeFailed:
+		return StateDown
+	case c.probe == nil && status.Failures > 0 && status.ErrorRate >= downErrorRate:
+		return StateDown
+	case status.Failures > 0 && status.ErrorRate >= m.config.DegradedErrorRate:
+		return StateDegraded
+	default:
+		return StateOperational
+	}
+}
+
+func (m *Monitor) find(name string) *component {
+	for _, c := range m.components {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func (m *Monitor) record(c *component, at time.Time, err error) {
+	c.lastCheckedAt = at
+	minute := at.Unix() / 60
+	if len(c.buckets) == 0 || c.buckets[len(c.buckets)-1].minute != minute {
+		c.buckets = append(c.buckets, bucket{minute: minute})
+	}
+	last := &c.buckets[len(c.buckets)-1]
+	last.checks++
+	if err != nil {
+		last.failures++
+		c.lastFailureAt = at
+	}
+	m.prune(c, at)
+}
+
+// prune drops the minutes that left the window
+func (m *Monitor) prune(c *component, now time.Time) {
+	oldest := now.Add(-m.config.Window).Unix() / 60
+	drop := 0
+	for drop < len(c.buckets) && c.buckets[drop].minute <= oldest {
+		drop++
+	}
+	if drop > 0 {
+		c.buckets = append(c.buckets[:0], c.buckets[drop:]...)
+	}
+}
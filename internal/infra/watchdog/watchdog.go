@@ -0,0 +1,225 @@
+package watchdog
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"godating-dealls/internal/common"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultIntervalSeconds     = 30
+	DefaultMaxGoroutines       = 10000
+	DefaultMaxHeapMB           = 1024
+	DefaultMaxDBConnections    = 100
+	DefaultMaxRedisConnections = 100
+	DefaultGrowthSamples       = 20
+	DefaultDumpCooldownMinutes = 15
+)
+
+// Config holds the thresholds, a zero threshold is not checked. GrowthSamples warns about goroutines that grew on
+// that many samples in a row, a leak grows steadily long before it reaches MaxGoroutines. It is read by config.Load
+// from the WATCHDOG_* settings, an interval of 0 turns the watchdog off
+type Config struct {
+	Interval            time.Duration `validate:"min=0"`
+	MaxGoroutines       int           `validate:"min=0"`
+	MaxHeapBytes        uint64
+	MaxDBConnections    int `validate:"min=0"`
+	MaxRedisConnections int `validate:"min=0"`
+	GrowthSamples       int `validate:"min=0"`
+	PprofDir            string
+	DumpCooldown        time.Duration `validate:"min=0"`
+}
+
+type Sample struct {
+	SampledAt             time.Time
+	Goroutines            int
+	HeapAllocBytes        uint64
+	HeapObjects           uint64
+	NumGC                 uint32
+	DBOpenConnections     int
+	DBInUse               int
+	DBIdle                int
+	DBWaitCount           int64
+	RedisTotalConnections uint32
+	RedisIdleConnections  uint32
+	RedisTimeouts         uint32
+}
+
+type Metrics struct {
+	Sample
+	Warnings   int64
+	Dumps      int64
+	LastWarnAt *time.Time
+	LastDump   string
+}
+
+// Watchdog samples the runtime and the connection pools of this instance and warns when a threshold is exceeded
+type Watchdog struct {
+	config Config
+	db     *sql.DB
+	redis  *redis.Client
+
+	mu         sync.Mutex
+	last       Sample
+	growth     int
+	warnings   int64
+	dumps      int64
+	lastWarnAt *time.Time
+	lastDump   string
+	lastDumpAt time.Time
+}
+
+// New db and redis may be nil, their pools are then left out
+func New(config Config, db *sql.DB, redis *redis.Client) *Watchdog {
+	return &Watchdog{config: config, db: db, redis: redis}
+}
+
+// Run samples on the interval until ctx is done, an interval of 0 turns the watchdog off
+func (w *Watchdog) Run(ctx context.Context) {
+	if w.config.Interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.check(w.sample())
+		}
+	}
+}
+
+// Metrics takes a fresh sample, the counters are since the instance started
+func (w *Watchdog) Metrics() Metrics {
+	sample := w.sample()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return Metrics{
+		Sample:     sample,
+		Warnings:   w.warnings,
+		Dumps:      w.dumps,
+		LastWarnAt: w.lastWarnAt,
+		LastDump:   w.lastDump,
+	}
+}
+
+func (w *Watchdog) sample() Sample {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	sample := Sample{
+		SampledAt:      time.Now(),
+		Goroutines:     runtime.NumGoroutine(),
+		HeapAllocBytes: mem.HeapAlloc,
+		HeapObjects:    mem.HeapObjects,
+		NumGC:          mem.NumGC,
+	}
+	if w.db != nil {
+		stats := w.db.Stats()
+		sample.DBOpenConnections = stats.OpenConnections
+		sample.DBInUse = stats.InUse
+		sample.DBIdle = stats.Idle
+		sample.DBWaitCount = stats.WaitCount
+	}
+	if w.redis != nil {
+		stats := w.redis.PoolStats()
+		sample.RedisTotalConnections = stats.TotalConns
+		sample.RedisIdleConnections = stats.IdleConns
+		sample.RedisTimeouts = stats.Timeouts
+	}
+	return sample
+}
+
+func (w *Watchdog) check(sample Sample) {
+	w.mu.Lock()
+	if w.last.SampledAt.IsZero() || sample.Goroutines <= w.last.Goroutines {
+		w.growth = 0
+	} else {
+		w.growth++
+	}
+	growth := w.growth
+	w.last = sample
+	w.mu.Unlock()
+
+	var exceeded []string
+	if w.config.MaxGoroutines > 0 && sample.Goroutines > w.config.MaxGoroutines {
+		exceeded = append(exceeded, fmt.Sprintf("%d goroutines, threshold %d", sample.Goroutines, w.config.MaxGoroutines))
+	}
+	if w.config.GrowthSamples > 0 && growth >= w.config.GrowthSamples {
+		exceeded = append(exceeded, fmt.Sprintf("goroutines grew on %d samples in a row to %d", growth, sample.Goroutines))
+	}
+	if w.config.MaxHeapBytes > 0 && sample.HeapAllocBytes > w.config.MaxHeapBytes {
+		exceeded = append(exceeded, fmt.Sprintf("%d MB heap, threshold %d MB", sample.HeapAllocBytes>>20, w.config.MaxHeapBytes>>20))
+	}
+	if w.config.MaxDBConnections > 0 && sample.DBOpenConnections > w.config.MaxDBConnections {
+		exceeded = append(exceeded, fmt.Sprintf("%d open db connections (%d in use), threshold %d", sample.DBOpenConnections, sample.DBInUse, w.config.MaxDBConnections))
+	}
+	if w.config.MaxRedisConnections > 0 && int(sample.RedisTotalConnections) > w.config.MaxRedisConnections {
+		exceeded = append(exceeded, fmt.Sprintf("%d redis connections, threshold %d", sample.RedisTotalConnections, w.config.MaxRedisConnections))
+	}
+	if len(exceeded) == 0 {
+		return
+	}
+
+	w.mu.Lock()
+	w.warnings++
+	warnedAt := sample.SampledAt
+	w.lastWarnAt = &warnedAt
+	w.mu.Unlock()
+
+	for _, reason := range exceeded {
+		common.WatchdogLog.Warnf("Threshold exceeded: %s", reason)
+	}
+	w.dump(sample.SampledAt)
+}
+
+// dump writes the goroutine and heap profiles to PprofDir, at most once per DumpCooldown so a lasting breach does
+// not fill the disk
+func (w *Watchdog) dump(at time.Time) {
+	if w.config.PprofDir == "" {
+		return
+	}
+	w.mu.Lock()
+	if !w.lastDumpAt.IsZero() && at.Sub(w.lastDumpAt) < w.config.DumpCooldown {
+		w.mu.Unlock()
+		return
+	}
+	w.lastDumpAt = at
+	w.mu.Unlock()
+
+	if err := os.MkdirAll(w.config.PprofDir, 0o750); err != nil {
+		common.WatchdogLog.Errorf("Failed to create pprof dir: %v", err)
+		return
+	}
+	prefix := filepath.Join(w.config.PprofDir, at.UTC().Format("20060102T150405Z"))
+	for _, profile := range []string{"goroutine", "heap"} {
+		if err := writeProfile(profile, prefix+"-"+profile+".pprof"); err != nil {
+			common.WatchdogLog.Errorf("Failed to dump %s profile: %v", profile, err)
+			return
+		}
+	}
+	common.WatchdogLog.Warnf("Dumped goroutine and heap profiles to %s-*.pprof", prefix)
+
+	w.mu.Lock()
+	w.dumps++
+	w.lastDump = prefix
+	w.mu.Unlock()
+}
+
+func writeProfile(name string, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return pprof.Lookup(name).WriteTo(file, 0)
+}
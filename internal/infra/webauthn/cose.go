@@ -0,0 +1,113 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+// COSE identifiers from RFC 9053 for the algorithms requested in the creation options
+const (
+	coseKeyTypeEC2 = 2
+	coseKeyTypeRSA = 3
+	coseAlgES256   = -7
+	coseAlgRS256   = -257
+	coseCurveP256  = 1
+)
+
+// SupportedAlgorithms lists the COSE algorithms offered to authenticators, in order of preference
+var SupportedAlgorithms = []int64{coseAlgES256, coseAlgRS256}
+
+// verifySignature checks the signature over data with a COSE encoded public key
+func verifySignature(coseKey []byte, data []byte, signature []byte) error {
+	decoded, _, err := decodeCBOR(coseKey)
+	if err != nil {
+		return err
+	}
+	key, ok := decoded.(map[interface{}]interface{})
+	if !ok {
+		return errors.New("public key is not a cose key")
+	}
+
+	digest := sha256.Sum256(data)
+	kty, _ := key[int64(1)].(int64)
+	alg, _ := key[int64(3)].(int64)
+	switch {
+	case kty == coseKeyTypeEC2 && alg == coseAlgES256:
+		publicKey, err := ecdsaPublicKey(key)
+		if err != nil {
+			return err
+		}
+		if !ecdsa.VerifyASN1(publicKey, digest[:], signature) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	case kty == coseKeyTypeRSA && alg == coseAlgRS256:
+		publicKey, err := rsaPublicKey(key)
+		if err != nil {
+			return err
+		}
+		if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signature); err != nil {
+			return errors.New("invalid signature")
+		}
+		return nil
+	}
+	return errors.New("unsupported public key algorithm")
+}
+
+// checkPublicKey makes sure a key presented at registration can be used for later assertions
+func checkPublicKey(coseKey []byte) error {
+	decoded, _, err := decodeCBOR(coseKey)
+	if err != nil {
+		return err
+	}
+	key, ok := decoded.(map[interface{}]interface{})
+	if !ok {
+		return errors.New("public key is not a cose key")
+	}
+
+	kty, _ := key[int64(1)].(int64)
+	alg, _ := key[int64(3)].(int64)
+	switch {
+	case kty == coseKeyTypeEC2 && alg == coseAlgES256:
+		_, err = ecdsaPublicKey(key)
+	case kty == coseKeyTypeRSA && alg == coseAlgRS256:
+		_, err = rsaPublicKey(key)
+	default:
+		err = errors.New("unsupported public key algorithm")
+	}
+	return err
+}
+
+func ecdsaPublicKey(key map[interface{}]interface{}) (*ecdsa.PublicKey, error) {
+	curve, _ := key[int64(-1)].(int64)
+	x, _ := key[int64(-2)].([]byte)
+	y, _ := key[int64(-3)].([]byte)
+	if curve != coseCurveP256 || len(x) != 32 || len(y) != 32 {
+		return nil, errors.New("invalid p-256 public key")
+	}
+
+	publicKey := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+	if !publicKey.Curve.IsOnCurve(publicKey.X, publicKey.Y) {
+		return nil, errors.New("invalid p-256 public key")
+	}
+	return publicKey, nil
+}
+
+func rsaPublicKey(key map[interface{}]interface{}) (*rsa.PublicKey, error) {
+	n, _ := key[int64(-1)].([]byte)
+	e, _ := key[int64(-2)].([]byte)
+	if len(n) < 256 || len(e) == 0 || len(e) > 4 {
+		return nil, errors.New("invalid rsa public key")
+	}
+
+	exponent := 0
+	for _, b := range e {
+		exponent = exponent<<8 | int(b)
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: exponent}, nil
+}
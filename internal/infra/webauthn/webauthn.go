@@ -0,0 +1,200 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// Authenticator data flags, https://www.w3.org/TR/webauthn-2/#sctn-authenticator-data
+const (
+	flagUserPresent      = 0x01
+	flagUserVerified     = 0x04
+	flagAttestedCredData = 0x40
+)
+
+const (
+	challengeSize      = 32
+	maxCredentialIDLen = 1023
+)
+
+// Config is the relying party the ceremonies are bound to. Only attestation "none" is requested and
+// attestation statements are not verified, the service does not restrict which authenticators can be used.
+// It is read by config.Load from WEBAUTHN_RP_ID, WEBAUTHN_RP_NAME and the comma separated WEBAUTHN_ORIGINS
+type Config struct {
+	RPID    string   `validate:"required"`
+	RPName  string   `validate:"required"`
+	Origins []string `validate:"min=1,dive,url"`
+}
+
+const (
+	DefaultRPID   = "localhost"
+	DefaultRPName = "GoDating"
+	DefaultOrigin = "http://localhost:8000"
+)
+
+// Credential is what is kept per passkey: the raw credential id, the COSE public key and the last signature counter
+type Credential struct {
+	ID        []byte
+	PublicKey []byte
+	SignCount uint32
+}
+
+// ClientData is the part of clientDataJSON the ceremonies check
+type ClientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+// NewChallenge returns a random challenge, it must be used for a single ceremony
+func NewChallenge() ([]byte, error) {
+	challenge := make([]byte, challengeSize)
+	if _, err := rand.Read(challenge); err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+// Encode is the unpadded base64url used for binary values in the WebAuthn JSON forms
+func Encode(value []byte) string {
+	return base64.RawURLEncoding.EncodeToString(value)
+}
+
+// Decode accepts base64url with or without padding
+func Decode(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+}
+
+// ParseClientData decodes clientDataJSON so the caller can find the ceremony by its challenge before verifying
+func ParseClientData(clientDataJSON []byte) (ClientData, error) {
+	var clientData ClientData
+	if err := json.Unmarshal(clientDataJSON, &clientData); err != nil {
+		return ClientData{}, errors.New("invalid client data")
+	}
+	return clientData, nil
+}
+
+// VerifyRegistration checks a navigator.credentials.create() response against the issued challenge
+// and returns the new credential to store
+func (c Config) VerifyRegistration(challenge []byte, clientDataJSON []byte, attestationObject []byte) (Credential, error) {
+	if err := c.verifyClientData(clientDataJSON, "webauthn.create", challenge); err != nil {
+		return Credential{}, err
+	}
+
+	decoded, _, err := decodeCBOR(attestationObject)
+	if err != nil {
+		return Credential{}, errors.New("invalid attestation object")
+	}
+	attestation, ok := decoded.(map[interface{}]interface{})
+	if !ok {
+		return Credential{}, errors.New("invalid attestation object")
+	}
+	authData, ok := attestation["authData"].([]byte)
+	if !ok {
+		return Credential{}, errors.New("attestation object without authenticator data")
+	}
+
+	signCount, flags, err := c.verifyAuthenticatorData(authData)
+	if err != nil {
+		return Credential{}, err
+	}
+	if flags&flagAttestedCredData == 0 || len(authData) < 37+16+2 {
+		return Credential{}, errors.New("authenticator data without credential")
+	}
+
+	// aaguid (16) then the credential id length (2), the id and the COSE key
+	attested := authData[37+16:]
+	idLength := int(binary.BigEndian.Uint16(attested))
+	attested = attested[2:]
+	if idLength == 0 || idLength > maxCredentialIDLen || len(attested) < idLength {
+		return Credential{}, errors.New("invalid credential id")
+	}
+	credentialID := append([]byte(nil), attested[:idLength]...)
+
+	_, rest, err := decodeCBOR(attested[idLength:])
+	if err != nil {
+		return Credential{}, errors.New("invalid credential public key")
+	}
+	publicKey := append([]byte(nil), attested[idLength:len(attested)-len(rest)]...)
+	if err := checkPublicKey(publicKey); err != nil {
+		return Credential{}, err
+	}
+
+	return Credential{ID: credentialID, PublicKey: publicKey, SignCount: signCount}, nil
+}
+
+// VerifyAssertion checks a navigator.credentials.get() response signed by the stored credential
+// and returns the new signature counter to store
+func (c Config) VerifyAssertion(challenge []byte, credential Credential, clientDataJSON []byte, authenticatorData []byte, signature []byte) (uint32, error) {
+	if err := c.verifyClientData(clientDataJSON, "webauthn.get", challenge); err != nil {
+		return 0, err
+	}
+
+	signCount, _, err := c.verifyAuthenticatorData(authenticatorData)
+	if err != nil {
+		return 0, err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := append(append([]byte(nil), authenticatorData...), clientDataHash[:]...)
+	if err := verifySignature(credential.PublicKey, signed, signature); err != nil {
+		return 0, err
+	}
+
+	// Authenticators that count must move forward, a counter that does not is a sign of a cloned key
+	if (signCount != 0 || credential.SignCount != 0) && signCount <= credential.SignCount {
+		return 0, errors.New("signature counter did not increase")
+	}
+	return signCount, nil
+}
+
+func (c Config) verifyClientData(clientDataJSON []byte, ceremony string, challenge []byte) error {
+	clientData, err := ParseClientData(clientDataJSON)
+	if err != nil {
+		return err
+	}
+	if clientData.Type != ceremony {
+		return errors.New("unexpected ceremony type")
+	}
+
+	received, err := Decode(clientData.Challenge)
+	if err != nil || subtle.ConstantTimeCompare(received, challenge) != 1 {
+		return errors.New("challenge mismatch")
+	}
+
+	for _, origin := range c.Origins {
+		if clientData.Origin == origin {
+			return nil
+		}
+	}
+	return errors.New("origin not allowed")
+}
+
+// verifyAuthenticatorData checks the relying party and that the user was present and verified,
+// a passkey replaces the password so user verification is required
+func (c Config) verifyAuthenticatorData(authData []byte) (uint32, byte, error) {
+	if len(authData) < 37 {
+		return 0, 0, errors.New("authenticator data too short")
+	}
+
+	rpIDHash := sha256.Sum256([]byte(c.RPID))
+	if !bytes.Equal(authData[:32], rpIDHash[:]) {
+		return 0, 0, errors.New("relying party mismatch")
+	}
+
+	flags := authData[32]
+	if flags&flagUserPresent == 0 {
+		return 0, 0, errors.New("user not present")
+	}
+	if flags&flagUserVerified == 0 {
+		return 0, 0, errors.New("user not verified")
+	}
+	return binary.BigEndian.Uint32(authData[33:37]), flags, nil
+}
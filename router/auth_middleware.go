@@ -0,0 +1,32 @@
+package router
+
+import (
+	md "godating-dealls/internal/common"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"net/http"
+	"strings"
+)
+
+// AuthMiddleware verifies the bearer token once per request and puts its claims and account id in the context, the
+// middlewares and handlers after it read the claims from there
+func AuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader := r.Header.Get("Authorization")
+		if authHeader == "" {
+			md.WriteEnvelopeError(w, http.StatusUnauthorized, "missing_token", "Missing Authorization header")
+			return
+		}
+		token, found := strings.CutPrefix(authHeader, "Bearer ")
+		if !found || token == "" {
+			md.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid Authorization header format")
+			return
+		}
+
+		claims, err := jsonwebtoken.VerifyJWTToken(token)
+		if err != nil {
+			md.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(jsonwebtoken.WithClaims(r.Context(), claims)))
+	})
+}
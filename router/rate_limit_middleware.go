@@ -0,0 +1,23 @@
+package router
+
+import (
+	md "godating-dealls/internal/common"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"net/http"
+	"strconv"
+)
+
+// AccountRateLimitMiddleware runs after AuthMiddleware and limits per account, so changing address does not reset the limit
+func AccountRateLimitMiddleware(rl md.Limiter, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accountId, ok := jsonwebtoken.AccountIdFromContext(r.Context())
+		if !ok {
+			md.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+			return
+		}
+		if !md.AllowRequest(w, r, rl, strconv.FormatInt(accountId, 10)) {
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -3,33 +3,250 @@ package router
 import (
 	md "godating-dealls/internal/common"
 	"godating-dealls/internal/delivery/handler"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"godating-dealls/internal/infra/redisclient"
+	"godating-dealls/internal/infra/storage"
 	"net/http"
+	"net/http/pprof"
+	"strings"
+	"time"
 )
 
+// Requests per minute allowed by the auth and swipe limiters unless configured otherwise
+const (
+	DefaultAuthRateLimitPerMinute  = 10
+	DefaultSwipeRateLimitPerMinute = 60
+)
+
+// Config has the router settings that are not a handler
+type Config struct {
+	// PprofEnabled serves the profiles, a profile shows memory contents and a cpu profile slows the instance
+	PprofEnabled            bool
+	AuthRateLimitPerMinute  int `validate:"gt=0"`
+	SwipeRateLimitPerMinute int `validate:"gt=0"`
+}
+
 func InitializeRouter(
 	authHandler *handler.AuthHandler,
 	userHandler *handler.UsersHandler,
+	candidateHandler *handler.CandidateHandler,
 	swipeHandler *handler.SwipeHandler,
 	packageHandler *handler.PackageHandler,
 	quotaHandler *handler.QuotaHandler,
-	accountHandler *handler.AccountHandler) *http.ServeMux {
+	accountHandler *handler.AccountHandler,
+	noteHandler *handler.NoteHandler,
+	contactHandler *handler.ContactHandler,
+	hiddenAccountHandler *handler.HiddenAccountHandler,
+	duoHandler *handler.DuoHandler,
+	blockHandler *handler.BlockHandler,
+	eventHandler *handler.EventHandler,
+	integrationHandler *handler.IntegrationHandler,
+	shareLinkHandler *handler.ShareLinkHandler,
+	rewardHandler *handler.RewardHandler,
+	profileStrengthHandler *handler.ProfileStrengthHandler,
+	adminHandler *handler.AdminHandler,
+	recoveryHandler *handler.RecoveryHandler,
+	matchFeatureHandler *handler.MatchFeatureHandler,
+	clientConfigHandler *handler.ClientConfigHandler,
+	statusMessageHandler *handler.StatusMessageHandler,
+	profileChangeHandler *handler.ProfileChangeHandler,
+	matchHandler *handler.MatchHandler,
+	messageHandler *handler.MessageHandler,
+	photoHandler *handler.PhotoHandler,
+	networkRuleHandler *handler.NetworkRuleHandler,
+	likesHandler *handler.LikesHandler,
+	notificationHandler *handler.NotificationHandler,
+	healthHandler *handler.HealthHandler,
+	accountImportHandler *handler.AccountImportHandler,
+	safetyHandler *handler.SafetyHandler,
+	rds redisclient.RedisInterface,
+	mediaHandler http.Handler,
+	regionHint func(http.Handler) http.Handler,
+	config Config) *http.ServeMux {
 
 	r := http.NewServeMux()
 
+	// The token is verified once by AuthMiddleware, the region hint and the scope check read its claims
+	authenticated := func(next http.Handler) http.Handler {
+		return AuthMiddleware(regionHint(next))
+	}
+	scoped := func(scope string, handlerFunc http.HandlerFunc) http.Handler {
+		return authenticated(ScopeMiddleware(scope, handlerFunc))
+	}
+
+	// Anonymous public profile access is limited per client address
+	publicProfileLimiter := md.NewRateLimiter(30, time.Minute)
+
+	// Register and login reveal whether an account exists, so they are limited per client address too.
+	// The counts live in redis so the limit holds across instances
+	authLimiter := redisclient.NewSlidingWindowLimiter(rds, "auth", config.AuthRateLimitPerMinute, time.Minute)
+
+	// Swipes are limited per account on top of the daily quota, against scripted swiping
+	swipeLimiter := redisclient.NewSlidingWindowLimiter(rds, "swipe", config.SwipeRateLimitPerMinute, time.Minute)
+
 	// Without middleware
-	r.HandleFunc("POST /godating-dealls/api/authenticate/register", authHandler.RegisterUserHandler)
-	r.HandleFunc("POST /godating-dealls/api/authenticate/login", authHandler.LoginUserHandler)
-
-	// Using middleware authenticate
-	r.Handle("POST /godating-dealls/api/authenticate/logout", md.AuthMiddleware(http.HandlerFunc(authHandler.LogoutUserHandler)))
-	r.Handle("POST /godating-dealls/api/daily-accounts", md.AuthMiddleware(http.HandlerFunc(userHandler.UserViewsHandler)))
-	r.Handle("PATCH /godating-dealls/api/users", md.AuthMiddleware(http.HandlerFunc(userHandler.UpdateUserHandler))) // New
-	r.Handle("POST /godating-dealls/api/swipes", md.AuthMiddleware(http.HandlerFunc(swipeHandler.SwipeHandler)))
-	r.Handle("GET /godating-dealls/api/quota", md.AuthMiddleware(http.HandlerFunc(quotaHandler.CheckQuotaAccountHandler)))
-	r.Handle("POST /godating-dealls/api/purchase-package", md.AuthMiddleware(http.HandlerFunc(packageHandler.PurchasePackages)))
-	r.Handle("GET /godating-dealls/api/packages", md.AuthMiddleware(http.HandlerFunc(packageHandler.GetPackageHandler)))
-	r.Handle("GET /godating-dealls/api/account-details", md.AuthMiddleware(http.HandlerFunc(accountHandler.FetchAccountDetailsHandler)))
-	r.Handle("POST /godating-dealls/api/account-view", md.AuthMiddleware(http.HandlerFunc(accountHandler.AccountViewHandler)))
+	handleWithLegacyPath(r, "POST /godating-dealls/api/v1/authenticate/register", md.RateLimitMiddleware(authLimiter, http.HandlerFunc(authHandler.RegisterUserHandler)))
+	handleWithLegacyPath(r, "POST /godating-dealls/api/v1/authenticate/login", md.RateLimitMiddleware(authLimiter, http.HandlerFunc(authHandler.LoginUserHandler)))
+	r.Handle("POST /godating-dealls/api/v1/authenticate/refresh", md.RateLimitMiddleware(authLimiter, http.HandlerFunc(authHandler.RefreshTokenHandler)))
+	r.Handle("POST /godating-dealls/api/v1/authenticate/forgot-password", md.RateLimitMiddleware(authLimiter, http.HandlerFunc(authHandler.ForgotPasswordHandler)))
+	r.Handle("POST /godating-dealls/api/v1/authenticate/reset-password", md.RateLimitMiddleware(authLimiter, http.HandlerFunc(authHandler.ResetPasswordHandler)))
+	r.Handle("POST /godating-dealls/api/v1/authenticate/passkeys/login/options", md.RateLimitMiddleware(authLimiter, http.HandlerFunc(authHandler.PasskeyLoginOptionsHandler)))
+	r.Handle("POST /godating-dealls/api/v1/authenticate/passkeys/login", md.RateLimitMiddleware(authLimiter, http.HandlerFunc(authHandler.PasskeyLoginHandler)))
+	r.Handle("POST /godating-dealls/api/v1/authenticate/oauth/{provider}", md.RateLimitMiddleware(authLimiter, http.HandlerFunc(authHandler.OAuthLoginHandler)))
+	r.Handle("GET /godating-dealls/api/v1/regions/lookup", md.RateLimitMiddleware(authLimiter, http.HandlerFunc(authHandler.RegionLookupHandler)))
+	r.Handle("POST /godating-dealls/api/v1/recovery/requests", md.RateLimitMiddleware(authLimiter, http.HandlerFunc(recoveryHandler.StartRecoveryHandler)))
+	r.Handle("POST /godating-dealls/api/v1/recovery/requests/complete", md.RateLimitMiddleware(authLimiter, http.HandlerFunc(recoveryHandler.CompleteRecoveryHandler)))
+	r.HandleFunc("GET /godating-dealls/api/v1/integrations/{provider}/callback", integrationHandler.IntegrationCallbackHandler)
+	r.HandleFunc("GET /godating-dealls/api/v1/status", statusMessageHandler.StatusHandler)
+	r.HandleFunc("GET /godating-dealls/api/v1/ready", healthHandler.ReadinessHandler)
+	r.HandleFunc("GET /godating-dealls/api/v1/status/components", healthHandler.ServiceStatusHandler)
+	if mediaHandler != nil {
+		// Files of the local media storage, an S3 storage serves its own links
+		r.Handle("GET "+storage.LocalMediaPath, mediaHandler)
+	}
+	r.Handle("GET /godating-dealls/api/v1/public/profiles/{share_token}", md.RateLimitMiddleware(publicProfileLimiter, http.HandlerFunc(shareLinkHandler.PublicProfileHandler)))
+
+	// Using middleware authenticate, each route belongs to a scope group so limited tokens only reach their group
+	handleWithLegacyPath(r, "POST /godating-dealls/api/v1/authenticate/logout", scoped(jsonwebtoken.ScopeAccount, authHandler.LogoutUserHandler))
+	r.Handle("POST /godating-dealls/api/v1/authenticate/tokens", scoped(jsonwebtoken.ScopeAccount, authHandler.IssueScopedTokenHandler))
+	r.Handle("POST /godating-dealls/api/v1/authenticate/passkeys/register/options", scoped(jsonwebtoken.ScopeAccount, authHandler.PasskeyRegisterOptionsHandler))
+	r.Handle("POST /godating-dealls/api/v1/authenticate/passkeys/register", scoped(jsonwebtoken.ScopeAccount, authHandler.PasskeyRegisterHandler))
+	r.Handle("GET /godating-dealls/api/v1/authenticate/passkeys", scoped(jsonwebtoken.ScopeAccount, authHandler.FetchPasskeysHandler))
+	r.Handle("DELETE /godating-dealls/api/v1/authenticate/passkeys/{credential_id}", scoped(jsonwebtoken.ScopeAccount, authHandler.DeletePasskeyHandler))
+	r.Handle("POST /godating-dealls/api/v1/devices", scoped(jsonwebtoken.ScopeAccount, notificationHandler.RegisterDeviceHandler))
+	handleWithLegacyPath(r, "POST /godating-dealls/api/v1/daily-accounts", scoped(jsonwebtoken.ScopeDiscoverRead, userHandler.UserViewsHandler))
+	r.Handle("GET /godating-dealls/api/v1/candidates", scoped(jsonwebtoken.ScopeDiscoverRead, candidateHandler.FetchCandidatesHandler))
+	r.Handle("GET /godating-dealls/api/v1/likes/received", scoped(jsonwebtoken.ScopeDiscoverRead, likesHandler.FetchLikesReceivedHandler))
+	r.Handle("GET /godating-dealls/api/v1/notifications", scoped(jsonwebtoken.ScopeDiscoverRead, notificationHandler.FetchNotificationsHandler))
+	r.Handle("PUT /godating-dealls/api/v1/notifications/read", scoped(jsonwebtoken.ScopeDiscoverWrite, notificationHandler.MarkNotificationsReadHandler))
+	handleWithLegacyPath(r, "PATCH /godating-dealls/api/v1/users", scoped(jsonwebtoken.ScopeProfileWrite, userHandler.UpdateUserHandler)) // New
+	r.Handle("PUT /godating-dealls/api/v1/users/location", scoped(jsonwebtoken.ScopeProfileWrite, userHandler.UpdateLocationHandler))
+	r.Handle("DELETE /godating-dealls/api/v1/users/location", scoped(jsonwebtoken.ScopeProfileWrite, userHandler.ClearLocationHandler))
+	r.Handle("GET /godating-dealls/api/v1/users/safety-settings", scoped(jsonwebtoken.ScopeProfileRead, safetyHandler.FetchSafetySettingsHandler))
+	r.Handle("PUT /godating-dealls/api/v1/users/safety-settings", scoped(jsonwebtoken.ScopeProfileWrite, safetyHandler.UpdateSafetySettingsHandler))
+	handleWithLegacyPath(r, "POST /godating-dealls/api/v1/swipes", authenticated(ScopeMiddleware(jsonwebtoken.ScopeDiscoverWrite, AccountRateLimitMiddleware(swipeLimiter, http.HandlerFunc(swipeHandler.SwipeHandler)))))
+	r.Handle("GET /godating-dealls/api/v1/matches", scoped(jsonwebtoken.ScopeChatRead, matchHandler.FetchMatchesHandler))
+	r.Handle("DELETE /godating-dealls/api/v1/matches/{match_id}", scoped(jsonwebtoken.ScopeChatWrite, matchHandler.UnmatchHandler))
+	r.Handle("GET /godating-dealls/api/v1/matches/{match_id}/messages", scoped(jsonwebtoken.ScopeChatRead, messageHandler.FetchMessagesHandler))
+	r.Handle("POST /godating-dealls/api/v1/matches/{match_id}/messages", scoped(jsonwebtoken.ScopeChatWrite, messageHandler.SendMessageHandler))
+	r.Handle("GET /godating-dealls/api/v1/matches/{match_id}/participants", scoped(jsonwebtoken.ScopeChatRead, messageHandler.FetchParticipantsHandler))
+	r.Handle("PUT /godating-dealls/api/v1/matches/{match_id}/read", scoped(jsonwebtoken.ScopeChatWrite, messageHandler.MarkReadHandler))
+	r.Handle("DELETE /godating-dealls/api/v1/matches/{match_id}/participants/me", scoped(jsonwebtoken.ScopeChatWrite, messageHandler.LeaveChatHandler))
+	r.Handle("GET /godating-dealls/api/v1/chat/ws", scoped(jsonwebtoken.ScopeChatRead, messageHandler.ChatSocketHandler))
+	r.Handle("POST /godating-dealls/api/v1/selections/undo", scoped(jsonwebtoken.ScopeDiscoverWrite, swipeHandler.UndoSwipeHandler))
+	r.Handle("POST /godating-dealls/api/v1/selections/superlike", scoped(jsonwebtoken.ScopeDiscoverWrite, swipeHandler.SuperLikeHandler))
+	r.Handle("POST /godating-dealls/api/v1/duos", scoped(jsonwebtoken.ScopeProfileWrite, duoHandler.InviteDuoHandler))
+	r.Handle("GET /godating-dealls/api/v1/duos", scoped(jsonwebtoken.ScopeProfileRead, duoHandler.FetchDuoHandler))
+	r.Handle("DELETE /godating-dealls/api/v1/duos", scoped(jsonwebtoken.ScopeProfileWrite, duoHandler.UnlinkDuoHandler))
+	r.Handle("POST /godating-dealls/api/v1/duos/{duo_id}/accept", scoped(jsonwebtoken.ScopeProfileWrite, duoHandler.AcceptDuoHandler))
+	r.Handle("GET /godating-dealls/api/v1/duos/discover", scoped(jsonwebtoken.ScopeDiscoverRead, duoHandler.FetchDuoCandidatesHandler))
+	r.Handle("POST /godating-dealls/api/v1/duos/swipes", scoped(jsonwebtoken.ScopeDiscoverWrite, duoHandler.SwipeDuoHandler))
+	r.Handle("GET /godating-dealls/api/v1/duos/matches", scoped(jsonwebtoken.ScopeChatRead, duoHandler.FetchDuoMatchesHandler))
+	handleWithLegacyPath(r, "GET /godating-dealls/api/v1/quota", scoped(jsonwebtoken.ScopeDiscoverRead, quotaHandler.CheckQuotaAccountHandler))
+	handleWithLegacyPath(r, "POST /godating-dealls/api/v1/purchase-package", scoped(jsonwebtoken.ScopeAccount, packageHandler.PurchasePackages))
+	handleWithLegacyPath(r, "GET /godating-dealls/api/v1/packages", scoped(jsonwebtoken.ScopeProfileRead, packageHandler.GetPackageHandler))
+	r.Handle("POST /godating-dealls/api/v1/premium/purchase", scoped(jsonwebtoken.ScopeAccount, packageHandler.PurchasePremiumHandler))
+	r.Handle("GET /godating-dealls/api/v1/premium/status", scoped(jsonwebtoken.ScopeProfileRead, packageHandler.PremiumStatusHandler))
+	handleWithLegacyPath(r, "GET /godating-dealls/api/v1/account-details", scoped(jsonwebtoken.ScopeProfileRead, accountHandler.FetchAccountDetailsHandler))
+	r.Handle("DELETE /godating-dealls/api/v1/accounts/me", scoped(jsonwebtoken.ScopeAccount, accountHandler.DeleteAccountHandler))
+	r.Handle("GET /godating-dealls/api/v1/accounts/me/export", scoped(jsonwebtoken.ScopeAccount, accountHandler.ExportAccountDataHandler))
+	handleWithLegacyPath(r, "POST /godating-dealls/api/v1/account-view", scoped(jsonwebtoken.ScopeDiscoverRead, accountHandler.AccountViewHandler))
+	r.Handle("POST /godating-dealls/api/v1/notes", scoped(jsonwebtoken.ScopeProfileWrite, noteHandler.CreateNoteHandler))
+	r.Handle("GET /godating-dealls/api/v1/notes", scoped(jsonwebtoken.ScopeProfileRead, noteHandler.FetchNotesHandler))
+	r.Handle("PATCH /godating-dealls/api/v1/notes/{note_id}", scoped(jsonwebtoken.ScopeProfileWrite, noteHandler.UpdateNoteHandler))
+	r.Handle("DELETE /godating-dealls/api/v1/notes/{note_id}", scoped(jsonwebtoken.ScopeProfileWrite, noteHandler.DeleteNoteHandler))
+	r.Handle("POST /godating-dealls/api/v1/contacts", scoped(jsonwebtoken.ScopeProfileWrite, contactHandler.UploadContactsHandler))
+	r.Handle("GET /godating-dealls/api/v1/contacts", scoped(jsonwebtoken.ScopeProfileRead, contactHandler.FetchContactsHandler))
+	r.Handle("DELETE /godating-dealls/api/v1/contacts", scoped(jsonwebtoken.ScopeProfileWrite, contactHandler.RemoveContactsHandler))
+	r.Handle("DELETE /godating-dealls/api/v1/contacts/{hash}", scoped(jsonwebtoken.ScopeProfileWrite, contactHandler.RemoveContactsHandler))
+	r.Handle("POST /godating-dealls/api/v1/users/hidden", scoped(jsonwebtoken.ScopeDiscoverWrite, hiddenAccountHandler.HideAccountHandler))
+	r.Handle("GET /godating-dealls/api/v1/users/hidden", scoped(jsonwebtoken.ScopeDiscoverRead, hiddenAccountHandler.FetchHiddenAccountsHandler))
+	r.Handle("DELETE /godating-dealls/api/v1/users/hidden/{account_id}", scoped(jsonwebtoken.ScopeDiscoverWrite, hiddenAccountHandler.UnhideAccountHandler))
+	r.Handle("POST /godating-dealls/api/v1/users/{account_id}/block", scoped(jsonwebtoken.ScopeDiscoverWrite, blockHandler.BlockAccountHandler))
+	r.Handle("GET /godating-dealls/api/v1/users/blocks", scoped(jsonwebtoken.ScopeDiscoverRead, blockHandler.FetchBlockedAccountsHandler))
+	r.Handle("DELETE /godating-dealls/api/v1/users/blocks/{account_id}", scoped(jsonwebtoken.ScopeDiscoverWrite, blockHandler.UnblockAccountHandler))
+	r.Handle("POST /godating-dealls/api/v1/users/{account_id}/report", scoped(jsonwebtoken.ScopeDiscoverWrite, blockHandler.ReportAccountHandler))
+	r.Handle("GET /godating-dealls/api/v1/events", scoped(jsonwebtoken.ScopeDiscoverRead, eventHandler.FetchEventsHandler))
+	r.Handle("POST /godating-dealls/api/v1/events/{event_id}/join", scoped(jsonwebtoken.ScopeChatWrite, eventHandler.JoinEventHandler))
+	r.Handle("POST /godating-dealls/api/v1/events/{event_id}/connections", scoped(jsonwebtoken.ScopeDiscoverWrite, eventHandler.ConnectAtEventHandler))
+	r.Handle("POST /godating-dealls/api/v1/integrations/{provider}/connect", scoped(jsonwebtoken.ScopeProfileWrite, integrationHandler.ConnectIntegrationHandler))
+	r.Handle("GET /godating-dealls/api/v1/integrations/imports", scoped(jsonwebtoken.ScopeProfileRead, integrationHandler.FetchImportedContentHandler))
+	r.Handle("DELETE /godating-dealls/api/v1/integrations/{provider}", scoped(jsonwebtoken.ScopeProfileWrite, integrationHandler.DisconnectIntegrationHandler))
+	r.Handle("POST /godating-dealls/api/v1/share-links", scoped(jsonwebtoken.ScopeProfileWrite, shareLinkHandler.CreateShareLinkHandler))
+	r.Handle("GET /godating-dealls/api/v1/share-links", scoped(jsonwebtoken.ScopeProfileRead, shareLinkHandler.FetchShareLinksHandler))
+	r.Handle("DELETE /godating-dealls/api/v1/share-links/{link_id}", scoped(jsonwebtoken.ScopeProfileWrite, shareLinkHandler.RevokeShareLinkHandler))
+	r.Handle("GET /godating-dealls/api/v1/rewards", scoped(jsonwebtoken.ScopeProfileRead, rewardHandler.FetchLoginStreakHandler))
+	r.Handle("POST /godating-dealls/api/v1/rewards/claim", scoped(jsonwebtoken.ScopeProfileWrite, rewardHandler.ClaimRewardHandler))
+	r.Handle("GET /godating-dealls/api/v1/client-config", scoped(jsonwebtoken.ScopeProfileRead, clientConfigHandler.FetchClientConfigHandler))
+	r.Handle("GET /godating-dealls/api/v1/users/profile-strength", scoped(jsonwebtoken.ScopeProfileRead, profileStrengthHandler.ProfileStrengthHandler))
+	r.Handle("POST /godating-dealls/api/v1/users/change-requests", scoped(jsonwebtoken.ScopeProfileWrite, profileChangeHandler.SubmitProfileChangeHandler))
+	r.Handle("GET /godating-dealls/api/v1/users/change-requests", scoped(jsonwebtoken.ScopeProfileRead, profileChangeHandler.FetchProfileChangesHandler))
+	r.Handle("POST /godating-dealls/api/v1/users/photos", scoped(jsonwebtoken.ScopeProfileWrite, photoHandler.UploadPhotoHandler))
+	r.Handle("GET /godating-dealls/api/v1/users/photos", scoped(jsonwebtoken.ScopeProfileRead, photoHandler.FetchPhotosHandler))
+	r.Handle("PUT /godating-dealls/api/v1/users/photos/order", scoped(jsonwebtoken.ScopeProfileWrite, photoHandler.ReorderPhotosHandler))
+	r.Handle("POST /godating-dealls/api/v1/users/photos/{photo_id}/primary", scoped(jsonwebtoken.ScopeProfileWrite, photoHandler.SetPrimaryPhotoHandler))
+	r.Handle("DELETE /godating-dealls/api/v1/users/photos/{photo_id}", scoped(jsonwebtoken.ScopeProfileWrite, photoHandler.DeletePhotoHandler))
+	r.Handle("GET /godating-dealls/api/v1/users/photos/trash", scoped(jsonwebtoken.ScopeProfileRead, photoHandler.FetchTrashedPhotosHandler))
+	r.Handle("POST /godating-dealls/api/v1/users/photos/{photo_id}/restore", scoped(jsonwebtoken.ScopeProfileWrite, photoHandler.RestorePhotoHandler))
+	r.Handle("PUT /godating-dealls/api/v1/users/photos/smart", scoped(jsonwebtoken.ScopeProfileWrite, photoHandler.SetSmartPhotosHandler))
+	r.Handle("GET /godating-dealls/api/v1/users/photos/smart", scoped(jsonwebtoken.ScopeProfileRead, photoHandler.FetchSmartPhotosHandler))
+	r.Handle("PUT /godating-dealls/api/v1/recovery/contacts", scoped(jsonwebtoken.ScopeAccount, recoveryHandler.SaveRecoveryContactsHandler))
+	r.Handle("GET /godating-dealls/api/v1/recovery/contacts", scoped(jsonwebtoken.ScopeAccount, recoveryHandler.FetchRecoverySettingsHandler))
+	r.Handle("DELETE /godating-dealls/api/v1/recovery/requests", scoped(jsonwebtoken.ScopeAccount, recoveryHandler.CancelRecoveryHandler))
+	r.Handle("GET /godating-dealls/api/v1/recovery/approvals", scoped(jsonwebtoken.ScopeAccount, recoveryHandler.FetchRecoveryApprovalsHandler))
+	r.Handle("POST /godating-dealls/api/v1/recovery/approvals/{request_id}", scoped(jsonwebtoken.ScopeAccount, recoveryHandler.ApproveRecoveryHandler))
+
+	// Endpoints for sibling services, using middleware service client credentials
+	r.Handle("POST /godating-dealls/api/v1/oauth/introspect", md.ServiceClientMiddleware(http.HandlerFunc(authHandler.IntrospectTokenHandler)))
+	r.Handle("POST /godating-dealls/api/v1/internal/regions/messages", md.ServiceClientMiddleware(http.HandlerFunc(messageHandler.ReceiveReplicatedMessageHandler)))
+
+	// Admin endpoints for trust and safety, using middleware admin key
+	r.Handle("GET /godating-dealls/api/v1/admin/accounts/{account_id}/timeline", md.AdminMiddleware(http.HandlerFunc(adminHandler.AccountTimelineHandler)))
+	r.Handle("GET /godating-dealls/api/v1/admin/accounts/{account_id}/timeline/export", md.AdminMiddleware(http.HandlerFunc(adminHandler.ExportAccountTimelineHandler)))
+	r.Handle("GET /godating-dealls/api/v1/admin/reports", md.AdminMiddleware(http.HandlerFunc(blockHandler.FetchReportsHandler)))
+	r.Handle("GET /godating-dealls/api/v1/admin/reports/export", md.AdminMiddleware(http.HandlerFunc(blockHandler.ExportReportsHandler)))
+	r.Handle("POST /godating-dealls/api/v1/admin/events", md.AdminMiddleware(http.HandlerFunc(eventHandler.CreateEventHandler)))
+	r.Handle("PATCH /godating-dealls/api/v1/admin/accounts/{account_id}/rectification", md.AdminMiddleware(http.HandlerFunc(adminHandler.RectifyUserHandler)))
+	r.Handle("POST /godating-dealls/api/v1/admin/accounts/import", md.AdminMiddleware(http.HandlerFunc(accountImportHandler.ImportAccountsHandler)))
+	r.Handle("GET /godating-dealls/api/v1/admin/profile-change-requests", md.AdminMiddleware(http.HandlerFunc(profileChangeHandler.FetchPendingProfileChangesHandler)))
+	r.Handle("POST /godating-dealls/api/v1/admin/profile-change-requests/{request_id}/review", md.AdminMiddleware(http.HandlerFunc(profileChangeHandler.ReviewProfileChangeHandler)))
+	r.Handle("GET /godating-dealls/api/v1/admin/metrics/quota-usage", md.AdminMiddleware(http.HandlerFunc(adminHandler.QuotaUsageMetricsHandler)))
+	r.Handle("GET /godating-dealls/api/v1/admin/metrics/login-throttle", md.AdminMiddleware(http.HandlerFunc(authHandler.LoginThrottleMetricsHandler)))
+	r.Handle("GET /godating-dealls/api/v1/admin/metrics/geo-restrictions", md.AdminMiddleware(http.HandlerFunc(adminHandler.GeoRestrictionMetricsHandler)))
+	r.Handle("GET /godating-dealls/api/v1/admin/metrics/runtime", md.AdminMiddleware(http.HandlerFunc(adminHandler.RuntimeMetricsHandler)))
+	r.Handle("GET /godating-dealls/api/v1/admin/status-messages", md.AdminMiddleware(http.HandlerFunc(statusMessageHandler.FetchStatusMessagesHandler)))
+	r.Handle("POST /godating-dealls/api/v1/admin/status-messages", md.AdminMiddleware(http.HandlerFunc(statusMessageHandler.CreateStatusMessageHandler)))
+	r.Handle("PUT /godating-dealls/api/v1/admin/status-messages/{message_id}", md.AdminMiddleware(http.HandlerFunc(statusMessageHandler.UpdateStatusMessageHandler)))
+	r.Handle("DELETE /godating-dealls/api/v1/admin/status-messages/{message_id}", md.AdminMiddleware(http.HandlerFunc(statusMessageHandler.DeleteStatusMessageHandler)))
+	r.Handle("GET /godating-dealls/api/v1/admin/client-config", md.AdminMiddleware(http.HandlerFunc(clientConfigHandler.FetchClientConfigBundlesHandler)))
+	r.Handle("POST /godating-dealls/api/v1/admin/client-config", md.AdminMiddleware(http.HandlerFunc(clientConfigHandler.PublishClientConfigHandler)))
+	r.Handle("PATCH /godating-dealls/api/v1/admin/client-config/{bundle_id}", md.AdminMiddleware(http.HandlerFunc(clientConfigHandler.UpdateClientConfigRolloutHandler)))
+	r.Handle("PUT /godating-dealls/api/v1/admin/match-scores", md.AdminMiddleware(http.HandlerFunc(matchFeatureHandler.SaveMatchScoresHandler)))
+	r.Handle("GET /godating-dealls/api/v1/admin/jobs", md.AdminMiddleware(http.HandlerFunc(adminHandler.ListJobsHandler)))
+	r.Handle("POST /godating-dealls/api/v1/admin/jobs/{name}/trigger", md.AdminMiddleware(http.HandlerFunc(adminHandler.TriggerJobHandler)))
+	r.Handle("POST /godating-dealls/api/v1/admin/jobs/{name}/pause", md.AdminMiddleware(http.HandlerFunc(adminHandler.PauseJobHandler)))
+	r.Handle("POST /godating-dealls/api/v1/admin/jobs/{name}/resume", md.AdminMiddleware(http.HandlerFunc(adminHandler.ResumeJobHandler)))
+	r.Handle("GET /godating-dealls/api/v1/admin/log-levels", md.AdminMiddleware(http.HandlerFunc(adminHandler.FetchLogLevelsHandler)))
+	r.Handle("PUT /godating-dealls/api/v1/admin/log-levels/{module}", md.AdminMiddleware(http.HandlerFunc(adminHandler.SetLogLevelHandler)))
+	r.Handle("GET /godating-dealls/api/v1/admin/network-rules", md.AdminMiddleware(http.HandlerFunc(networkRuleHandler.FetchNetworkRulesHandler)))
+	r.Handle("POST /godating-dealls/api/v1/admin/network-rules", md.AdminMiddleware(http.HandlerFunc(networkRuleHandler.CreateNetworkRuleHandler)))
+	r.Handle("DELETE /godating-dealls/api/v1/admin/network-rules/{rule_id}", md.AdminMiddleware(http.HandlerFunc(networkRuleHandler.DeleteNetworkRuleHandler)))
+
+	// Profiling is off unless PPROF_ENABLED. pprof.Index serves the named profiles only under /debug/pprof/
+	if config.PprofEnabled {
+		r.Handle("GET /debug/pprof/", md.AdminMiddleware(http.HandlerFunc(pprof.Index)))
+		r.Handle("GET /debug/pprof/cmdline", md.AdminMiddleware(http.HandlerFunc(pprof.Cmdline)))
+		r.Handle("GET /debug/pprof/profile", md.AdminMiddleware(http.HandlerFunc(pprof.Profile)))
+		r.Handle("/debug/pprof/symbol", md.AdminMiddleware(http.HandlerFunc(pprof.Symbol)))
+		r.Handle("GET /debug/pprof/trace", md.AdminMiddleware(http.HandlerFunc(pprof.Trace)))
+	}
 
 	return r
 }
+
+// handleWithLegacyPath registers a route of the first release on its versioned path and on the unversioned path
+// the apps already in use call
+func handleWithLegacyPath(r *http.ServeMux, pattern string, handler http.Handler) {
+	r.Handle(pattern, handler)
+	r.Handle(strings.Replace(pattern, "/godating-dealls/api/v1/", "/godating-dealls/api/", 1), handler)
+}
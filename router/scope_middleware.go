@@ -0,0 +1,23 @@
+package router
+
+import (
+	md "godating-dealls/internal/common"
+	"godating-dealls/internal/infra/jsonwebtoken"
+	"net/http"
+)
+
+// ScopeMiddleware runs after AuthMiddleware and refuses a token minted without the scope of the route
+func ScopeMiddleware(scope string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := jsonwebtoken.ClaimsFromContext(r.Context())
+		if !ok {
+			md.WriteEnvelopeError(w, http.StatusUnauthorized, "invalid_token", "Invalid token")
+			return
+		}
+		if !claims.HasScope(scope) {
+			md.WriteEnvelopeError(w, http.StatusForbidden, "insufficient_scope", "Token is missing the "+scope+" scope")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}